@@ -0,0 +1,755 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: vaulthub/v1/key.proto
+
+package vaulthubv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// EncryptionKey 用户加密密钥信息（不含密钥材料）
+type EncryptionKey struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserUuid          string                 `protobuf:"bytes,2,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"`
+	KekAlgorithm      string                 `protobuf:"bytes,3,opt,name=kek_algorithm,json=kekAlgorithm,proto3" json:"kek_algorithm,omitempty"`
+	DekVersion        int32                  `protobuf:"varint,4,opt,name=dek_version,json=dekVersion,proto3" json:"dek_version,omitempty"`
+	DekAlgorithm      string                 `protobuf:"bytes,5,opt,name=dek_algorithm,json=dekAlgorithm,proto3" json:"dek_algorithm,omitempty"`
+	LastRotationAt    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_rotation_at,json=lastRotationAt,proto3" json:"last_rotation_at,omitempty"`
+	RotationStatus    string                 `protobuf:"bytes,7,opt,name=rotation_status,json=rotationStatus,proto3" json:"rotation_status,omitempty"`
+	RotationStartedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=rotation_started_at,json=rotationStartedAt,proto3" json:"rotation_started_at,omitempty"`
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt         *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *EncryptionKey) Reset() {
+	*x = EncryptionKey{}
+	mi := &file_vaulthub_v1_key_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EncryptionKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncryptionKey) ProtoMessage() {}
+
+func (x *EncryptionKey) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_key_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncryptionKey.ProtoReflect.Descriptor instead.
+func (*EncryptionKey) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_key_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EncryptionKey) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EncryptionKey) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *EncryptionKey) GetKekAlgorithm() string {
+	if x != nil {
+		return x.KekAlgorithm
+	}
+	return ""
+}
+
+func (x *EncryptionKey) GetDekVersion() int32 {
+	if x != nil {
+		return x.DekVersion
+	}
+	return 0
+}
+
+func (x *EncryptionKey) GetDekAlgorithm() string {
+	if x != nil {
+		return x.DekAlgorithm
+	}
+	return ""
+}
+
+func (x *EncryptionKey) GetLastRotationAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastRotationAt
+	}
+	return nil
+}
+
+func (x *EncryptionKey) GetRotationStatus() string {
+	if x != nil {
+		return x.RotationStatus
+	}
+	return ""
+}
+
+func (x *EncryptionKey) GetRotationStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RotationStartedAt
+	}
+	return nil
+}
+
+func (x *EncryptionKey) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *EncryptionKey) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type CreateKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SecurityPin   string                 `protobuf:"bytes,1,opt,name=security_pin,json=securityPin,proto3" json:"security_pin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateKeyRequest) Reset() {
+	*x = CreateKeyRequest{}
+	mi := &file_vaulthub_v1_key_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateKeyRequest) ProtoMessage() {}
+
+func (x *CreateKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_key_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateKeyRequest) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_key_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateKeyRequest) GetSecurityPin() string {
+	if x != nil {
+		return x.SecurityPin
+	}
+	return ""
+}
+
+type CreateKeyResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   *EncryptionKey         `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// 恢复助记词，只返回一次
+	RecoveryKey   string `protobuf:"bytes,2,opt,name=recovery_key,json=recoveryKey,proto3" json:"recovery_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateKeyResponse) Reset() {
+	*x = CreateKeyResponse{}
+	mi := &file_vaulthub_v1_key_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateKeyResponse) ProtoMessage() {}
+
+func (x *CreateKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_key_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateKeyResponse) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_key_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateKeyResponse) GetKey() *EncryptionKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *CreateKeyResponse) GetRecoveryKey() string {
+	if x != nil {
+		return x.RecoveryKey
+	}
+	return ""
+}
+
+type VerifyRecoveryKeyRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RecoveryMnemonic string                 `protobuf:"bytes,1,opt,name=recovery_mnemonic,json=recoveryMnemonic,proto3" json:"recovery_mnemonic,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *VerifyRecoveryKeyRequest) Reset() {
+	*x = VerifyRecoveryKeyRequest{}
+	mi := &file_vaulthub_v1_key_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyRecoveryKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyRecoveryKeyRequest) ProtoMessage() {}
+
+func (x *VerifyRecoveryKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_key_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyRecoveryKeyRequest.ProtoReflect.Descriptor instead.
+func (*VerifyRecoveryKeyRequest) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_key_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *VerifyRecoveryKeyRequest) GetRecoveryMnemonic() string {
+	if x != nil {
+		return x.RecoveryMnemonic
+	}
+	return ""
+}
+
+type VerifyRecoveryKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyRecoveryKeyResponse) Reset() {
+	*x = VerifyRecoveryKeyResponse{}
+	mi := &file_vaulthub_v1_key_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyRecoveryKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyRecoveryKeyResponse) ProtoMessage() {}
+
+func (x *VerifyRecoveryKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_key_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyRecoveryKeyResponse.ProtoReflect.Descriptor instead.
+func (*VerifyRecoveryKeyResponse) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_key_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *VerifyRecoveryKeyResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyRecoveryKeyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type RotateKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SecurityPin   string                 `protobuf:"bytes,1,opt,name=security_pin,json=securityPin,proto3" json:"security_pin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateKeyRequest) Reset() {
+	*x = RotateKeyRequest{}
+	mi := &file_vaulthub_v1_key_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateKeyRequest) ProtoMessage() {}
+
+func (x *RotateKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_key_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateKeyRequest) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_key_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RotateKeyRequest) GetSecurityPin() string {
+	if x != nil {
+		return x.SecurityPin
+	}
+	return ""
+}
+
+type RotateKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           *EncryptionKey         `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateKeyResponse) Reset() {
+	*x = RotateKeyResponse{}
+	mi := &file_vaulthub_v1_key_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateKeyResponse) ProtoMessage() {}
+
+func (x *RotateKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_key_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateKeyResponse.ProtoReflect.Descriptor instead.
+func (*RotateKeyResponse) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_key_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RotateKeyResponse) GetKey() *EncryptionKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *RotateKeyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type GetRotationStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRotationStatusRequest) Reset() {
+	*x = GetRotationStatusRequest{}
+	mi := &file_vaulthub_v1_key_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRotationStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRotationStatusRequest) ProtoMessage() {}
+
+func (x *GetRotationStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_key_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRotationStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetRotationStatusRequest) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_key_proto_rawDescGZIP(), []int{7}
+}
+
+type WatchRotationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRotationRequest) Reset() {
+	*x = WatchRotationRequest{}
+	mi := &file_vaulthub_v1_key_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRotationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRotationRequest) ProtoMessage() {}
+
+func (x *WatchRotationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_key_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRotationRequest.ProtoReflect.Descriptor instead.
+func (*WatchRotationRequest) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_key_proto_rawDescGZIP(), []int{8}
+}
+
+// RotationStatus 密钥轮换进度
+type RotationStatus struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserUuid        string                 `protobuf:"bytes,1,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"`
+	OldVersion      int32                  `protobuf:"varint,2,opt,name=old_version,json=oldVersion,proto3" json:"old_version,omitempty"`
+	NewVersion      int32                  `protobuf:"varint,3,opt,name=new_version,json=newVersion,proto3" json:"new_version,omitempty"`
+	TotalSecrets    int64                  `protobuf:"varint,4,opt,name=total_secrets,json=totalSecrets,proto3" json:"total_secrets,omitempty"`
+	MigratedSecrets int64                  `protobuf:"varint,5,opt,name=migrated_secrets,json=migratedSecrets,proto3" json:"migrated_secrets,omitempty"`
+	FailedSecrets   int64                  `protobuf:"varint,6,opt,name=failed_secrets,json=failedSecrets,proto3" json:"failed_secrets,omitempty"`
+	// running, completed, failed
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	Error         string                 `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotationStatus) Reset() {
+	*x = RotationStatus{}
+	mi := &file_vaulthub_v1_key_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotationStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotationStatus) ProtoMessage() {}
+
+func (x *RotationStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_key_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotationStatus.ProtoReflect.Descriptor instead.
+func (*RotationStatus) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_key_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RotationStatus) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *RotationStatus) GetOldVersion() int32 {
+	if x != nil {
+		return x.OldVersion
+	}
+	return 0
+}
+
+func (x *RotationStatus) GetNewVersion() int32 {
+	if x != nil {
+		return x.NewVersion
+	}
+	return 0
+}
+
+func (x *RotationStatus) GetTotalSecrets() int64 {
+	if x != nil {
+		return x.TotalSecrets
+	}
+	return 0
+}
+
+func (x *RotationStatus) GetMigratedSecrets() int64 {
+	if x != nil {
+		return x.MigratedSecrets
+	}
+	return 0
+}
+
+func (x *RotationStatus) GetFailedSecrets() int64 {
+	if x != nil {
+		return x.FailedSecrets
+	}
+	return 0
+}
+
+func (x *RotationStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RotationStatus) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *RotationStatus) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *RotationStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_vaulthub_v1_key_proto protoreflect.FileDescriptor
+
+const file_vaulthub_v1_key_proto_rawDesc = "" +
+	"\n" +
+	"\x15vaulthub/v1/key.proto\x12\vvaulthub.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd8\x03\n" +
+	"\rEncryptionKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1b\n" +
+	"\tuser_uuid\x18\x02 \x01(\tR\buserUuid\x12#\n" +
+	"\rkek_algorithm\x18\x03 \x01(\tR\fkekAlgorithm\x12\x1f\n" +
+	"\vdek_version\x18\x04 \x01(\x05R\n" +
+	"dekVersion\x12#\n" +
+	"\rdek_algorithm\x18\x05 \x01(\tR\fdekAlgorithm\x12D\n" +
+	"\x10last_rotation_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastRotationAt\x12'\n" +
+	"\x0frotation_status\x18\a \x01(\tR\x0erotationStatus\x12J\n" +
+	"\x13rotation_started_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x11rotationStartedAt\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"5\n" +
+	"\x10CreateKeyRequest\x12!\n" +
+	"\fsecurity_pin\x18\x01 \x01(\tR\vsecurityPin\"d\n" +
+	"\x11CreateKeyResponse\x12,\n" +
+	"\x03key\x18\x01 \x01(\v2\x1a.vaulthub.v1.EncryptionKeyR\x03key\x12!\n" +
+	"\frecovery_key\x18\x02 \x01(\tR\vrecoveryKey\"G\n" +
+	"\x18VerifyRecoveryKeyRequest\x12+\n" +
+	"\x11recovery_mnemonic\x18\x01 \x01(\tR\x10recoveryMnemonic\"K\n" +
+	"\x19VerifyRecoveryKeyResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"5\n" +
+	"\x10RotateKeyRequest\x12!\n" +
+	"\fsecurity_pin\x18\x01 \x01(\tR\vsecurityPin\"[\n" +
+	"\x11RotateKeyResponse\x12,\n" +
+	"\x03key\x18\x01 \x01(\v2\x1a.vaulthub.v1.EncryptionKeyR\x03key\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x1a\n" +
+	"\x18GetRotationStatusRequest\"\x16\n" +
+	"\x14WatchRotationRequest\"\x8e\x03\n" +
+	"\x0eRotationStatus\x12\x1b\n" +
+	"\tuser_uuid\x18\x01 \x01(\tR\buserUuid\x12\x1f\n" +
+	"\vold_version\x18\x02 \x01(\x05R\n" +
+	"oldVersion\x12\x1f\n" +
+	"\vnew_version\x18\x03 \x01(\x05R\n" +
+	"newVersion\x12#\n" +
+	"\rtotal_secrets\x18\x04 \x01(\x03R\ftotalSecrets\x12)\n" +
+	"\x10migrated_secrets\x18\x05 \x01(\x03R\x0fmigratedSecrets\x12%\n" +
+	"\x0efailed_secrets\x18\x06 \x01(\x03R\rfailedSecrets\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"started_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\fcompleted_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12\x14\n" +
+	"\x05error\x18\n" +
+	" \x01(\tR\x05error2\xb4\x03\n" +
+	"\n" +
+	"KeyService\x12J\n" +
+	"\tCreateKey\x12\x1d.vaulthub.v1.CreateKeyRequest\x1a\x1e.vaulthub.v1.CreateKeyResponse\x12b\n" +
+	"\x11VerifyRecoveryKey\x12%.vaulthub.v1.VerifyRecoveryKeyRequest\x1a&.vaulthub.v1.VerifyRecoveryKeyResponse\x12J\n" +
+	"\tRotateKey\x12\x1d.vaulthub.v1.RotateKeyRequest\x1a\x1e.vaulthub.v1.RotateKeyResponse\x12W\n" +
+	"\x11GetRotationStatus\x12%.vaulthub.v1.GetRotationStatusRequest\x1a\x1b.vaulthub.v1.RotationStatus\x12Q\n" +
+	"\rWatchRotation\x12!.vaulthub.v1.WatchRotationRequest\x1a\x1b.vaulthub.v1.RotationStatus0\x01B?Z=github.com/cuihe500/vaulthub/api/proto/vaulthub/v1;vaulthubv1b\x06proto3"
+
+var (
+	file_vaulthub_v1_key_proto_rawDescOnce sync.Once
+	file_vaulthub_v1_key_proto_rawDescData []byte
+)
+
+func file_vaulthub_v1_key_proto_rawDescGZIP() []byte {
+	file_vaulthub_v1_key_proto_rawDescOnce.Do(func() {
+		file_vaulthub_v1_key_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_vaulthub_v1_key_proto_rawDesc), len(file_vaulthub_v1_key_proto_rawDesc)))
+	})
+	return file_vaulthub_v1_key_proto_rawDescData
+}
+
+var file_vaulthub_v1_key_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_vaulthub_v1_key_proto_goTypes = []any{
+	(*EncryptionKey)(nil),             // 0: vaulthub.v1.EncryptionKey
+	(*CreateKeyRequest)(nil),          // 1: vaulthub.v1.CreateKeyRequest
+	(*CreateKeyResponse)(nil),         // 2: vaulthub.v1.CreateKeyResponse
+	(*VerifyRecoveryKeyRequest)(nil),  // 3: vaulthub.v1.VerifyRecoveryKeyRequest
+	(*VerifyRecoveryKeyResponse)(nil), // 4: vaulthub.v1.VerifyRecoveryKeyResponse
+	(*RotateKeyRequest)(nil),          // 5: vaulthub.v1.RotateKeyRequest
+	(*RotateKeyResponse)(nil),         // 6: vaulthub.v1.RotateKeyResponse
+	(*GetRotationStatusRequest)(nil),  // 7: vaulthub.v1.GetRotationStatusRequest
+	(*WatchRotationRequest)(nil),      // 8: vaulthub.v1.WatchRotationRequest
+	(*RotationStatus)(nil),            // 9: vaulthub.v1.RotationStatus
+	(*timestamppb.Timestamp)(nil),     // 10: google.protobuf.Timestamp
+}
+var file_vaulthub_v1_key_proto_depIdxs = []int32{
+	10, // 0: vaulthub.v1.EncryptionKey.last_rotation_at:type_name -> google.protobuf.Timestamp
+	10, // 1: vaulthub.v1.EncryptionKey.rotation_started_at:type_name -> google.protobuf.Timestamp
+	10, // 2: vaulthub.v1.EncryptionKey.created_at:type_name -> google.protobuf.Timestamp
+	10, // 3: vaulthub.v1.EncryptionKey.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 4: vaulthub.v1.CreateKeyResponse.key:type_name -> vaulthub.v1.EncryptionKey
+	0,  // 5: vaulthub.v1.RotateKeyResponse.key:type_name -> vaulthub.v1.EncryptionKey
+	10, // 6: vaulthub.v1.RotationStatus.started_at:type_name -> google.protobuf.Timestamp
+	10, // 7: vaulthub.v1.RotationStatus.completed_at:type_name -> google.protobuf.Timestamp
+	1,  // 8: vaulthub.v1.KeyService.CreateKey:input_type -> vaulthub.v1.CreateKeyRequest
+	3,  // 9: vaulthub.v1.KeyService.VerifyRecoveryKey:input_type -> vaulthub.v1.VerifyRecoveryKeyRequest
+	5,  // 10: vaulthub.v1.KeyService.RotateKey:input_type -> vaulthub.v1.RotateKeyRequest
+	7,  // 11: vaulthub.v1.KeyService.GetRotationStatus:input_type -> vaulthub.v1.GetRotationStatusRequest
+	8,  // 12: vaulthub.v1.KeyService.WatchRotation:input_type -> vaulthub.v1.WatchRotationRequest
+	2,  // 13: vaulthub.v1.KeyService.CreateKey:output_type -> vaulthub.v1.CreateKeyResponse
+	4,  // 14: vaulthub.v1.KeyService.VerifyRecoveryKey:output_type -> vaulthub.v1.VerifyRecoveryKeyResponse
+	6,  // 15: vaulthub.v1.KeyService.RotateKey:output_type -> vaulthub.v1.RotateKeyResponse
+	9,  // 16: vaulthub.v1.KeyService.GetRotationStatus:output_type -> vaulthub.v1.RotationStatus
+	9,  // 17: vaulthub.v1.KeyService.WatchRotation:output_type -> vaulthub.v1.RotationStatus
+	13, // [13:18] is the sub-list for method output_type
+	8,  // [8:13] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_vaulthub_v1_key_proto_init() }
+func file_vaulthub_v1_key_proto_init() {
+	if File_vaulthub_v1_key_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vaulthub_v1_key_proto_rawDesc), len(file_vaulthub_v1_key_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vaulthub_v1_key_proto_goTypes,
+		DependencyIndexes: file_vaulthub_v1_key_proto_depIdxs,
+		MessageInfos:      file_vaulthub_v1_key_proto_msgTypes,
+	}.Build()
+	File_vaulthub_v1_key_proto = out.File
+	file_vaulthub_v1_key_proto_goTypes = nil
+	file_vaulthub_v1_key_proto_depIdxs = nil
+}
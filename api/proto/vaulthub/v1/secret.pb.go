@@ -0,0 +1,827 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: vaulthub/v1/secret.proto
+
+package vaulthubv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SecretMetadata 秘密元数据
+type SecretMetadata struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Tags      []string               `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	// 所属文件夹（多级用"/"分隔）
+	Folder        string           `protobuf:"bytes,3,opt,name=folder,proto3" json:"folder,omitempty"`
+	Extra         *structpb.Struct `protobuf:"bytes,4,opt,name=extra,proto3" json:"extra,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SecretMetadata) Reset() {
+	*x = SecretMetadata{}
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SecretMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretMetadata) ProtoMessage() {}
+
+func (x *SecretMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretMetadata.ProtoReflect.Descriptor instead.
+func (*SecretMetadata) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_secret_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SecretMetadata) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *SecretMetadata) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *SecretMetadata) GetFolder() string {
+	if x != nil {
+		return x.Folder
+	}
+	return ""
+}
+
+func (x *SecretMetadata) GetExtra() *structpb.Struct {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+// Secret 秘密信息（不含明文）
+type Secret struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserUuid   string                 `protobuf:"bytes,2,opt,name=user_uuid,json=userUuid,proto3" json:"user_uuid,omitempty"`
+	SecretUuid string                 `protobuf:"bytes,3,opt,name=secret_uuid,json=secretUuid,proto3" json:"secret_uuid,omitempty"`
+	SecretName string                 `protobuf:"bytes,4,opt,name=secret_name,json=secretName,proto3" json:"secret_name,omitempty"`
+	// api_key, db_credential, certificate, ssh_key, token, password, other
+	SecretType       string                 `protobuf:"bytes,5,opt,name=secret_type,json=secretType,proto3" json:"secret_type,omitempty"`
+	Description      string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	DekVersion       int32                  `protobuf:"varint,7,opt,name=dek_version,json=dekVersion,proto3" json:"dek_version,omitempty"`
+	Metadata         *SecretMetadata        `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	CheckoutRequired bool                   `protobuf:"varint,9,opt,name=checkout_required,json=checkoutRequired,proto3" json:"checkout_required,omitempty"`
+	RotationRequired bool                   `protobuf:"varint,10,opt,name=rotation_required,json=rotationRequired,proto3" json:"rotation_required,omitempty"`
+	ApprovalRequired bool                   `protobuf:"varint,11,opt,name=approval_required,json=approvalRequired,proto3" json:"approval_required,omitempty"`
+	ValueChangedAt   *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=value_changed_at,json=valueChangedAt,proto3" json:"value_changed_at,omitempty"`
+	LastAccessedAt   *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=last_accessed_at,json=lastAccessedAt,proto3" json:"last_accessed_at,omitempty"`
+	AccessCount      int64                  `protobuf:"varint,14,opt,name=access_count,json=accessCount,proto3" json:"access_count,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Secret) Reset() {
+	*x = Secret{}
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Secret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Secret) ProtoMessage() {}
+
+func (x *Secret) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Secret.ProtoReflect.Descriptor instead.
+func (*Secret) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_secret_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Secret) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Secret) GetUserUuid() string {
+	if x != nil {
+		return x.UserUuid
+	}
+	return ""
+}
+
+func (x *Secret) GetSecretUuid() string {
+	if x != nil {
+		return x.SecretUuid
+	}
+	return ""
+}
+
+func (x *Secret) GetSecretName() string {
+	if x != nil {
+		return x.SecretName
+	}
+	return ""
+}
+
+func (x *Secret) GetSecretType() string {
+	if x != nil {
+		return x.SecretType
+	}
+	return ""
+}
+
+func (x *Secret) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Secret) GetDekVersion() int32 {
+	if x != nil {
+		return x.DekVersion
+	}
+	return 0
+}
+
+func (x *Secret) GetMetadata() *SecretMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Secret) GetCheckoutRequired() bool {
+	if x != nil {
+		return x.CheckoutRequired
+	}
+	return false
+}
+
+func (x *Secret) GetRotationRequired() bool {
+	if x != nil {
+		return x.RotationRequired
+	}
+	return false
+}
+
+func (x *Secret) GetApprovalRequired() bool {
+	if x != nil {
+		return x.ApprovalRequired
+	}
+	return false
+}
+
+func (x *Secret) GetValueChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValueChangedAt
+	}
+	return nil
+}
+
+func (x *Secret) GetLastAccessedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAccessedAt
+	}
+	return nil
+}
+
+func (x *Secret) GetAccessCount() int64 {
+	if x != nil {
+		return x.AccessCount
+	}
+	return 0
+}
+
+func (x *Secret) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Secret) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// DecryptedSecret 解密后的秘密
+type DecryptedSecret struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Secret    *Secret                `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	PlainData string                 `protobuf:"bytes,2,opt,name=plain_data,json=plainData,proto3" json:"plain_data,omitempty"`
+	// 高敏感秘密解密时依据的访问申请
+	AccessRequestUuid string `protobuf:"bytes,3,opt,name=access_request_uuid,json=accessRequestUuid,proto3" json:"access_request_uuid,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *DecryptedSecret) Reset() {
+	*x = DecryptedSecret{}
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecryptedSecret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecryptedSecret) ProtoMessage() {}
+
+func (x *DecryptedSecret) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecryptedSecret.ProtoReflect.Descriptor instead.
+func (*DecryptedSecret) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_secret_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DecryptedSecret) GetSecret() *Secret {
+	if x != nil {
+		return x.Secret
+	}
+	return nil
+}
+
+func (x *DecryptedSecret) GetPlainData() string {
+	if x != nil {
+		return x.PlainData
+	}
+	return ""
+}
+
+func (x *DecryptedSecret) GetAccessRequestUuid() string {
+	if x != nil {
+		return x.AccessRequestUuid
+	}
+	return ""
+}
+
+// ListSecretsRequest 不设置分页时返回全部（最多10000条）
+type ListSecretsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SecretType    string                 `protobuf:"bytes,1,opt,name=secret_type,json=secretType,proto3" json:"secret_type,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSecretsRequest) Reset() {
+	*x = ListSecretsRequest{}
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSecretsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSecretsRequest) ProtoMessage() {}
+
+func (x *ListSecretsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSecretsRequest.ProtoReflect.Descriptor instead.
+func (*ListSecretsRequest) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_secret_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListSecretsRequest) GetSecretType() string {
+	if x != nil {
+		return x.SecretType
+	}
+	return ""
+}
+
+func (x *ListSecretsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListSecretsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type ListSecretsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Secrets       []*Secret              `protobuf:"bytes,1,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	TotalPages    int32                  `protobuf:"varint,5,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSecretsResponse) Reset() {
+	*x = ListSecretsResponse{}
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSecretsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSecretsResponse) ProtoMessage() {}
+
+func (x *ListSecretsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSecretsResponse.ProtoReflect.Descriptor instead.
+func (*ListSecretsResponse) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_secret_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListSecretsResponse) GetSecrets() []*Secret {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+func (x *ListSecretsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListSecretsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListSecretsResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListSecretsResponse) GetTotalPages() int32 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+type CreateSecretRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SecurityPin   string                 `protobuf:"bytes,1,opt,name=security_pin,json=securityPin,proto3" json:"security_pin,omitempty"`
+	SecretName    string                 `protobuf:"bytes,2,opt,name=secret_name,json=secretName,proto3" json:"secret_name,omitempty"`
+	SecretType    string                 `protobuf:"bytes,3,opt,name=secret_type,json=secretType,proto3" json:"secret_type,omitempty"`
+	PlainData     string                 `protobuf:"bytes,4,opt,name=plain_data,json=plainData,proto3" json:"plain_data,omitempty"`
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	Metadata      *SecretMetadata        `protobuf:"bytes,6,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSecretRequest) Reset() {
+	*x = CreateSecretRequest{}
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSecretRequest) ProtoMessage() {}
+
+func (x *CreateSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSecretRequest.ProtoReflect.Descriptor instead.
+func (*CreateSecretRequest) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_secret_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateSecretRequest) GetSecurityPin() string {
+	if x != nil {
+		return x.SecurityPin
+	}
+	return ""
+}
+
+func (x *CreateSecretRequest) GetSecretName() string {
+	if x != nil {
+		return x.SecretName
+	}
+	return ""
+}
+
+func (x *CreateSecretRequest) GetSecretType() string {
+	if x != nil {
+		return x.SecretType
+	}
+	return ""
+}
+
+func (x *CreateSecretRequest) GetPlainData() string {
+	if x != nil {
+		return x.PlainData
+	}
+	return ""
+}
+
+func (x *CreateSecretRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateSecretRequest) GetMetadata() *SecretMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type DecryptSecretRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SecretUuid    string                 `protobuf:"bytes,1,opt,name=secret_uuid,json=secretUuid,proto3" json:"secret_uuid,omitempty"`
+	SecurityPin   string                 `protobuf:"bytes,2,opt,name=security_pin,json=securityPin,proto3" json:"security_pin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecryptSecretRequest) Reset() {
+	*x = DecryptSecretRequest{}
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecryptSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecryptSecretRequest) ProtoMessage() {}
+
+func (x *DecryptSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecryptSecretRequest.ProtoReflect.Descriptor instead.
+func (*DecryptSecretRequest) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_secret_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DecryptSecretRequest) GetSecretUuid() string {
+	if x != nil {
+		return x.SecretUuid
+	}
+	return ""
+}
+
+func (x *DecryptSecretRequest) GetSecurityPin() string {
+	if x != nil {
+		return x.SecurityPin
+	}
+	return ""
+}
+
+type DeleteSecretRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SecretUuid    string                 `protobuf:"bytes,1,opt,name=secret_uuid,json=secretUuid,proto3" json:"secret_uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSecretRequest) Reset() {
+	*x = DeleteSecretRequest{}
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSecretRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSecretRequest) ProtoMessage() {}
+
+func (x *DeleteSecretRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSecretRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSecretRequest) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_secret_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteSecretRequest) GetSecretUuid() string {
+	if x != nil {
+		return x.SecretUuid
+	}
+	return ""
+}
+
+type DeleteSecretResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSecretResponse) Reset() {
+	*x = DeleteSecretResponse{}
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSecretResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSecretResponse) ProtoMessage() {}
+
+func (x *DeleteSecretResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vaulthub_v1_secret_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSecretResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSecretResponse) Descriptor() ([]byte, []int) {
+	return file_vaulthub_v1_secret_proto_rawDescGZIP(), []int{8}
+}
+
+var File_vaulthub_v1_secret_proto protoreflect.FileDescriptor
+
+const file_vaulthub_v1_secret_proto_rawDesc = "" +
+	"\n" +
+	"\x18vaulthub/v1/secret.proto\x12\vvaulthub.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa6\x01\n" +
+	"\x0eSecretMetadata\x129\n" +
+	"\n" +
+	"expires_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\x12\x16\n" +
+	"\x06folder\x18\x03 \x01(\tR\x06folder\x12-\n" +
+	"\x05extra\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x05extra\"\xc0\x05\n" +
+	"\x06Secret\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1b\n" +
+	"\tuser_uuid\x18\x02 \x01(\tR\buserUuid\x12\x1f\n" +
+	"\vsecret_uuid\x18\x03 \x01(\tR\n" +
+	"secretUuid\x12\x1f\n" +
+	"\vsecret_name\x18\x04 \x01(\tR\n" +
+	"secretName\x12\x1f\n" +
+	"\vsecret_type\x18\x05 \x01(\tR\n" +
+	"secretType\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vdek_version\x18\a \x01(\x05R\n" +
+	"dekVersion\x127\n" +
+	"\bmetadata\x18\b \x01(\v2\x1b.vaulthub.v1.SecretMetadataR\bmetadata\x12+\n" +
+	"\x11checkout_required\x18\t \x01(\bR\x10checkoutRequired\x12+\n" +
+	"\x11rotation_required\x18\n" +
+	" \x01(\bR\x10rotationRequired\x12+\n" +
+	"\x11approval_required\x18\v \x01(\bR\x10approvalRequired\x12D\n" +
+	"\x10value_changed_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\x0evalueChangedAt\x12D\n" +
+	"\x10last_accessed_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\x0elastAccessedAt\x12!\n" +
+	"\faccess_count\x18\x0e \x01(\x03R\vaccessCount\x129\n" +
+	"\n" +
+	"created_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x8d\x01\n" +
+	"\x0fDecryptedSecret\x12+\n" +
+	"\x06secret\x18\x01 \x01(\v2\x13.vaulthub.v1.SecretR\x06secret\x12\x1d\n" +
+	"\n" +
+	"plain_data\x18\x02 \x01(\tR\tplainData\x12.\n" +
+	"\x13access_request_uuid\x18\x03 \x01(\tR\x11accessRequestUuid\"f\n" +
+	"\x12ListSecretsRequest\x12\x1f\n" +
+	"\vsecret_type\x18\x01 \x01(\tR\n" +
+	"secretType\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\"\xac\x01\n" +
+	"\x13ListSecretsResponse\x12-\n" +
+	"\asecrets\x18\x01 \x03(\v2\x13.vaulthub.v1.SecretR\asecrets\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1f\n" +
+	"\vtotal_pages\x18\x05 \x01(\x05R\n" +
+	"totalPages\"\xf4\x01\n" +
+	"\x13CreateSecretRequest\x12!\n" +
+	"\fsecurity_pin\x18\x01 \x01(\tR\vsecurityPin\x12\x1f\n" +
+	"\vsecret_name\x18\x02 \x01(\tR\n" +
+	"secretName\x12\x1f\n" +
+	"\vsecret_type\x18\x03 \x01(\tR\n" +
+	"secretType\x12\x1d\n" +
+	"\n" +
+	"plain_data\x18\x04 \x01(\tR\tplainData\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x127\n" +
+	"\bmetadata\x18\x06 \x01(\v2\x1b.vaulthub.v1.SecretMetadataR\bmetadata\"Z\n" +
+	"\x14DecryptSecretRequest\x12\x1f\n" +
+	"\vsecret_uuid\x18\x01 \x01(\tR\n" +
+	"secretUuid\x12!\n" +
+	"\fsecurity_pin\x18\x02 \x01(\tR\vsecurityPin\"6\n" +
+	"\x13DeleteSecretRequest\x12\x1f\n" +
+	"\vsecret_uuid\x18\x01 \x01(\tR\n" +
+	"secretUuid\"\x16\n" +
+	"\x14DeleteSecretResponse2\xcf\x02\n" +
+	"\rSecretService\x12P\n" +
+	"\vListSecrets\x12\x1f.vaulthub.v1.ListSecretsRequest\x1a .vaulthub.v1.ListSecretsResponse\x12E\n" +
+	"\fCreateSecret\x12 .vaulthub.v1.CreateSecretRequest\x1a\x13.vaulthub.v1.Secret\x12P\n" +
+	"\rDecryptSecret\x12!.vaulthub.v1.DecryptSecretRequest\x1a\x1c.vaulthub.v1.DecryptedSecret\x12S\n" +
+	"\fDeleteSecret\x12 .vaulthub.v1.DeleteSecretRequest\x1a!.vaulthub.v1.DeleteSecretResponseB?Z=github.com/cuihe500/vaulthub/api/proto/vaulthub/v1;vaulthubv1b\x06proto3"
+
+var (
+	file_vaulthub_v1_secret_proto_rawDescOnce sync.Once
+	file_vaulthub_v1_secret_proto_rawDescData []byte
+)
+
+func file_vaulthub_v1_secret_proto_rawDescGZIP() []byte {
+	file_vaulthub_v1_secret_proto_rawDescOnce.Do(func() {
+		file_vaulthub_v1_secret_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_vaulthub_v1_secret_proto_rawDesc), len(file_vaulthub_v1_secret_proto_rawDesc)))
+	})
+	return file_vaulthub_v1_secret_proto_rawDescData
+}
+
+var file_vaulthub_v1_secret_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_vaulthub_v1_secret_proto_goTypes = []any{
+	(*SecretMetadata)(nil),        // 0: vaulthub.v1.SecretMetadata
+	(*Secret)(nil),                // 1: vaulthub.v1.Secret
+	(*DecryptedSecret)(nil),       // 2: vaulthub.v1.DecryptedSecret
+	(*ListSecretsRequest)(nil),    // 3: vaulthub.v1.ListSecretsRequest
+	(*ListSecretsResponse)(nil),   // 4: vaulthub.v1.ListSecretsResponse
+	(*CreateSecretRequest)(nil),   // 5: vaulthub.v1.CreateSecretRequest
+	(*DecryptSecretRequest)(nil),  // 6: vaulthub.v1.DecryptSecretRequest
+	(*DeleteSecretRequest)(nil),   // 7: vaulthub.v1.DeleteSecretRequest
+	(*DeleteSecretResponse)(nil),  // 8: vaulthub.v1.DeleteSecretResponse
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),       // 10: google.protobuf.Struct
+}
+var file_vaulthub_v1_secret_proto_depIdxs = []int32{
+	9,  // 0: vaulthub.v1.SecretMetadata.expires_at:type_name -> google.protobuf.Timestamp
+	10, // 1: vaulthub.v1.SecretMetadata.extra:type_name -> google.protobuf.Struct
+	0,  // 2: vaulthub.v1.Secret.metadata:type_name -> vaulthub.v1.SecretMetadata
+	9,  // 3: vaulthub.v1.Secret.value_changed_at:type_name -> google.protobuf.Timestamp
+	9,  // 4: vaulthub.v1.Secret.last_accessed_at:type_name -> google.protobuf.Timestamp
+	9,  // 5: vaulthub.v1.Secret.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 6: vaulthub.v1.Secret.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 7: vaulthub.v1.DecryptedSecret.secret:type_name -> vaulthub.v1.Secret
+	1,  // 8: vaulthub.v1.ListSecretsResponse.secrets:type_name -> vaulthub.v1.Secret
+	0,  // 9: vaulthub.v1.CreateSecretRequest.metadata:type_name -> vaulthub.v1.SecretMetadata
+	3,  // 10: vaulthub.v1.SecretService.ListSecrets:input_type -> vaulthub.v1.ListSecretsRequest
+	5,  // 11: vaulthub.v1.SecretService.CreateSecret:input_type -> vaulthub.v1.CreateSecretRequest
+	6,  // 12: vaulthub.v1.SecretService.DecryptSecret:input_type -> vaulthub.v1.DecryptSecretRequest
+	7,  // 13: vaulthub.v1.SecretService.DeleteSecret:input_type -> vaulthub.v1.DeleteSecretRequest
+	4,  // 14: vaulthub.v1.SecretService.ListSecrets:output_type -> vaulthub.v1.ListSecretsResponse
+	1,  // 15: vaulthub.v1.SecretService.CreateSecret:output_type -> vaulthub.v1.Secret
+	2,  // 16: vaulthub.v1.SecretService.DecryptSecret:output_type -> vaulthub.v1.DecryptedSecret
+	8,  // 17: vaulthub.v1.SecretService.DeleteSecret:output_type -> vaulthub.v1.DeleteSecretResponse
+	14, // [14:18] is the sub-list for method output_type
+	10, // [10:14] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_vaulthub_v1_secret_proto_init() }
+func file_vaulthub_v1_secret_proto_init() {
+	if File_vaulthub_v1_secret_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vaulthub_v1_secret_proto_rawDesc), len(file_vaulthub_v1_secret_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vaulthub_v1_secret_proto_goTypes,
+		DependencyIndexes: file_vaulthub_v1_secret_proto_depIdxs,
+		MessageInfos:      file_vaulthub_v1_secret_proto_msgTypes,
+	}.Build()
+	File_vaulthub_v1_secret_proto = out.File
+	file_vaulthub_v1_secret_proto_goTypes = nil
+	file_vaulthub_v1_secret_proto_depIdxs = nil
+}
@@ -0,0 +1,445 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/client"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// 代理相关环境变量
+const (
+	envAgentPassword = "VAULTHUB_AGENT_PASSWORD"
+)
+
+// 代理默认参数
+const (
+	defaultAgentPollInterval  = 60 * time.Second
+	defaultAgentReloadTimeout = time.Minute
+	defaultAgentCacheDir      = "./.vaulthub-agent"
+	defaultAgentFileMode      = "0600"
+)
+
+var (
+	agentConfigPath string
+	agentOnce       bool
+)
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "运行本地代理，把秘密同步到文件并保持更新",
+	Long: `以机器账号登录 VaultHub，把配置的模板或单个秘密渲染到目标文件，
+并定期检查引用的秘密，变化时原子替换文件并执行重载命令。
+
+渲染结果以安全密码派生的密钥加密缓存在本地，服务端暂时不可达时用缓存写出目标文件，
+应用仍可正常启动。收到 SIGHUP 时立即重新渲染全部目标。
+
+配置文件格式见 configs/agent.toml.example。`,
+	Example: `  vaulthub agent -c /etc/vaulthub/agent.toml
+  vaulthub agent -c agent.toml --once`,
+	RunE: runAgent,
+}
+
+func init() {
+	agentCmd.Flags().StringVarP(&agentConfigPath, "config", "c", "", "代理配置文件路径")
+	agentCmd.Flags().BoolVar(&agentOnce, "once", false, "渲染一次后退出（适用于初始化容器）")
+	_ = agentCmd.MarkFlagRequired("config")
+
+	rootCmd.AddCommand(agentCmd)
+}
+
+// agentConfig 代理配置
+type agentConfig struct {
+	Server        string          `mapstructure:"server"`         // 服务地址
+	PollInterval  time.Duration   `mapstructure:"poll_interval"`  // 检查秘密变化的间隔
+	ReloadTimeout time.Duration   `mapstructure:"reload_timeout"` // 重载命令的超时时间
+	CacheDir      string          `mapstructure:"cache_dir"`      // 加密缓存目录
+	Auth          agentAuthConfig `mapstructure:"auth"`
+	Targets       []agentTarget   `mapstructure:"targets"`
+}
+
+// agentAuthConfig 代理认证配置
+// 配置username时用密码登录并在令牌失效后自动重新登录，否则使用固定的访问令牌
+type agentAuthConfig struct {
+	Username        string `mapstructure:"username"`
+	PasswordFile    string `mapstructure:"password_file"`     // 为空时读取 VAULTHUB_AGENT_PASSWORD
+	TokenFile       string `mapstructure:"token_file"`        // 为空时读取 VAULTHUB_TOKEN
+	SecurityPINFile string `mapstructure:"security_pin_file"` // 为空时读取 VAULTHUB_SECURITY_PIN
+}
+
+// agentTarget 同步目标
+// template和secret二选一：template为模板文件路径，secret为单个秘密的路径（可用field取JSON字段）
+type agentTarget struct {
+	Path          string   `mapstructure:"path"`
+	Template      string   `mapstructure:"template"`
+	Secret        string   `mapstructure:"secret"`
+	Field         string   `mapstructure:"field"`
+	Mode          string   `mapstructure:"mode"`           // 八进制文件权限，默认0600
+	ReloadCommand []string `mapstructure:"reload_command"` // 内容变化后执行的命令
+}
+
+// agentTargetState 目标文件的运行状态
+type agentTargetState struct {
+	target   agentTarget
+	mode     os.FileMode
+	refs     []string // 上次渲染引用的秘密路径
+	versions string   // 上次渲染时引用秘密的版本
+	stale    bool     // 上次渲染失败，正在使用缓存或旧文件
+}
+
+// secretAgent 本地同步代理
+type secretAgent struct {
+	cfg         *agentConfig
+	client      *apiClient
+	securityPIN string
+	cache       *agentCache
+	targets     []*agentTargetState
+}
+
+// runAgent 运行代理
+func runAgent(cmd *cobra.Command, args []string) error {
+	if err := logger.Init(logger.DefaultConfig()); err != nil {
+		return fmt.Errorf("初始化日志失败: %w", err)
+	}
+
+	cfg, err := loadAgentConfig(agentConfigPath)
+	if err != nil {
+		return err
+	}
+	agent, err := newSecretAgent(cfg)
+	if err != nil {
+		return err
+	}
+	defer agent.cache.close()
+
+	if err := agent.login(); err != nil && !isTransportError(err) {
+		return err
+	} else if err != nil {
+		logger.Warn("登录失败，使用本地缓存", logger.Err(err))
+	}
+
+	changed := agent.syncAll(true)
+	agent.reload(changed)
+	if agentOnce {
+		for _, t := range agent.targets {
+			if t.stale {
+				return fmt.Errorf("目标 %s 未能从服务端渲染", t.target.Path)
+			}
+		}
+		return nil
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+
+	logger.Info("代理已启动", logger.Int("targets", len(agent.targets)), logger.Duration("poll_interval", cfg.PollInterval))
+	for {
+		select {
+		case sig := <-signals:
+			if sig != syscall.SIGHUP {
+				logger.Info("代理退出", logger.String("signal", sig.String()))
+				return nil
+			}
+			logger.Info("收到SIGHUP，重新渲染全部目标")
+			agent.reload(agent.syncAll(true))
+		case <-ticker.C:
+			agent.reload(agent.syncAll(false))
+		}
+	}
+}
+
+// loadAgentConfig 读取并校验代理配置
+func loadAgentConfig(path string) (*agentConfig, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("读取代理配置失败: %w", err)
+	}
+
+	cfg := &agentConfig{}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("解析代理配置失败: %w", err)
+	}
+	cfg.Server = firstNonEmptyString(cfg.Server, os.Getenv(envServer), "http://localhost:8080")
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = defaultAgentPollInterval
+	}
+	if cfg.PollInterval < time.Second {
+		return nil, errors.New("poll_interval 不能小于1秒")
+	}
+	if cfg.ReloadTimeout == 0 {
+		cfg.ReloadTimeout = defaultAgentReloadTimeout
+	}
+	cfg.CacheDir = firstNonEmptyString(cfg.CacheDir, defaultAgentCacheDir)
+
+	if len(cfg.Targets) == 0 {
+		return nil, errors.New("至少需要配置一个同步目标 [[targets]]")
+	}
+	seen := make(map[string]bool, len(cfg.Targets))
+	for i, t := range cfg.Targets {
+		switch {
+		case t.Path == "":
+			return nil, fmt.Errorf("第%d个目标缺少 path", i+1)
+		case (t.Template == "") == (t.Secret == ""):
+			return nil, fmt.Errorf("目标 %s 必须且只能配置 template 或 secret 之一", t.Path)
+		case t.Field != "" && t.Secret == "":
+			return nil, fmt.Errorf("目标 %s 的 field 只能与 secret 一起使用", t.Path)
+		case seen[t.Path]:
+			return nil, fmt.Errorf("目标 %s 重复配置", t.Path)
+		}
+		seen[t.Path] = true
+	}
+	return cfg, nil
+}
+
+// newSecretAgent 读取凭据、打开缓存并初始化目标状态
+func newSecretAgent(cfg *agentConfig) (*secretAgent, error) {
+	securityPIN, err := readCredential(cfg.Auth.SecurityPINFile, envSecurityPIN)
+	if err != nil {
+		return nil, err
+	}
+	if securityPIN == "" {
+		return nil, fmt.Errorf("缺少安全密码，请配置 auth.security_pin_file 或 %s", envSecurityPIN)
+	}
+
+	agent := &secretAgent{cfg: cfg, securityPIN: securityPIN}
+
+	var auth client.Option
+	if cfg.Auth.Username != "" {
+		password, err := readCredential(cfg.Auth.PasswordFile, envAgentPassword)
+		if err != nil {
+			return nil, err
+		}
+		if password == "" {
+			return nil, fmt.Errorf("缺少登录密码，请配置 auth.password_file 或 %s", envAgentPassword)
+		}
+		// 令牌过期或失效时由客户端自动重新登录
+		auth = client.WithCredentials(cfg.Auth.Username, password)
+	} else {
+		token, err := readCredential(cfg.Auth.TokenFile, envToken)
+		if err != nil {
+			return nil, err
+		}
+		if token == "" {
+			return nil, fmt.Errorf("缺少认证信息，请配置 auth.username 或 auth.token_file（或 %s）", envToken)
+		}
+		auth = client.WithToken(token)
+	}
+	if agent.client, err = newCLIClient(cfg.Server, time.Minute, auth); err != nil {
+		return nil, err
+	}
+
+	for _, t := range cfg.Targets {
+		mode, err := strconv.ParseUint(firstNonEmptyString(t.Mode, defaultAgentFileMode), 8, 32)
+		if err != nil || mode > 0777 {
+			return nil, fmt.Errorf("目标 %s 的 mode 无效: %s", t.Path, t.Mode)
+		}
+		agent.targets = append(agent.targets, &agentTargetState{target: t, mode: os.FileMode(mode)})
+	}
+
+	if agent.cache, err = openAgentCache(cfg.CacheDir, securityPIN); err != nil {
+		return nil, err
+	}
+	for _, t := range agent.targets {
+		if entry := agent.cache.get(t.target.Path); entry != nil {
+			t.refs = entry.Refs
+		}
+	}
+	return agent, nil
+}
+
+// readCredential 从文件读取凭据，未配置文件时读取环境变量
+func readCredential(path, env string) (string, error) {
+	if path == "" {
+		return os.Getenv(env), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("读取凭据文件失败: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// login 使用机器账号登录，未配置用户名时直接使用访问令牌
+func (a *secretAgent) login() error {
+	if a.cfg.Auth.Username == "" {
+		return nil
+	}
+	// 只用于启动时尽早发现凭据错误，令牌失效后的重新登录由客户端处理
+	_, err := a.client.Auth.Me(context.Background())
+	return err
+}
+
+// syncAll 检查并渲染所有目标，force为true时不比较版本直接渲染
+// 返回内容发生变化的目标
+func (a *secretAgent) syncAll(force bool) []*agentTargetState {
+	versions, err := a.secretVersions()
+	if err != nil {
+		logger.Warn("查询秘密列表失败", logger.Err(err))
+		if !force {
+			return nil
+		}
+	}
+
+	var changed []*agentTargetState
+	for _, t := range a.targets {
+		if !force && !t.stale && versionsOf(t.refs, versions) == t.versions {
+			continue
+		}
+		updated, err := a.sync(t, versions)
+		if err != nil {
+			logger.Error("同步目标失败", logger.String("path", t.target.Path), logger.Err(err))
+			continue
+		}
+		if updated {
+			changed = append(changed, t)
+		}
+	}
+	return changed
+}
+
+// sync 渲染单个目标并写入文件，服务端不可达时使用缓存
+// 返回文件内容是否发生变化
+func (a *secretAgent) sync(t *agentTargetState, versions map[string]string) (bool, error) {
+	content, refs, err := a.render(t.target)
+	if err != nil {
+		t.stale = true
+		entry := a.cache.get(t.target.Path)
+		if !isTransportError(err) || entry == nil {
+			return false, err
+		}
+		logger.Warn("服务端不可达，使用本地缓存", logger.String("path", t.target.Path), logger.Err(err))
+		content, refs = entry.Content, entry.Refs
+	} else {
+		t.stale = false
+		t.versions = versionsOf(refs, versions)
+		if err := a.cache.put(t.target.Path, &agentCacheEntry{Content: content, Refs: refs}); err != nil {
+			logger.Warn("更新本地缓存失败", logger.Err(err))
+		}
+	}
+	t.refs = refs
+
+	if current, err := os.ReadFile(t.target.Path); err == nil && bytes.Equal(current, content) {
+		if info, err := os.Stat(t.target.Path); err == nil && info.Mode().Perm() == t.mode {
+			return false, nil
+		}
+	}
+	if err := writeFileAtomic(t.target.Path, content, t.mode); err != nil {
+		return false, err
+	}
+	logger.Info("已更新目标文件", logger.String("path", t.target.Path), logger.Bool("from_cache", t.stale))
+	return true, nil
+}
+
+// render 通过模板渲染接口渲染目标内容
+func (a *secretAgent) render(t agentTarget) ([]byte, []string, error) {
+	tmpl := ""
+	if t.Template != "" {
+		data, err := os.ReadFile(t.Template)
+		if err != nil {
+			return nil, nil, fmt.Errorf("读取模板失败: %w", err)
+		}
+		tmpl = string(data)
+	} else {
+		args := strconv.Quote(strings.Trim(t.Secret, "/"))
+		if t.Field != "" {
+			args += " " + strconv.Quote(t.Field)
+		}
+		tmpl = "{{ secret " + args + " }}"
+	}
+
+	var resp service.RenderTemplateResponse
+	req := &service.RenderTemplateRequest{SecurityPIN: a.securityPIN, Template: tmpl}
+	if err := a.client.do("POST", "/api/v1/secrets/render", req, &resp); err != nil {
+		return nil, nil, err
+	}
+	refs := make([]string, 0, len(resp.Secrets))
+	for _, s := range resp.Secrets {
+		refs = append(refs, s.Path)
+	}
+	return []byte(resp.Output), refs, nil
+}
+
+// secretVersions 查询秘密列表，返回路径到版本（UUID和值变更时间）的映射
+// 秘密列表不包含加密数据，轮询不会计入访问统计
+func (a *secretAgent) secretVersions() (map[string]string, error) {
+	var list service.ListUserSecretsResponse
+	if err := a.client.do("GET", "/api/v1/secrets", nil, &list); err != nil {
+		return nil, err
+	}
+	versions := make(map[string]string, len(list.Secrets))
+	for _, secret := range list.Secrets {
+		changedAt := secret.UpdatedAt
+		if secret.ValueChangedAt != nil {
+			changedAt = *secret.ValueChangedAt
+		}
+		versions[service.SecretRefPath(secret.Metadata, secret.SecretName)] = secret.SecretUUID + "@" + strconv.FormatInt(changedAt.UnixNano(), 10)
+	}
+	return versions, nil
+}
+
+// reload 执行内容变化的目标配置的重载命令，相同命令只执行一次
+func (a *secretAgent) reload(changed []*agentTargetState) {
+	done := make(map[string]bool)
+	for _, t := range changed {
+		command := t.target.ReloadCommand
+		if len(command) == 0 {
+			continue
+		}
+		key := strings.Join(command, "\x00")
+		if done[key] {
+			continue
+		}
+		done[key] = true
+
+		ctx, cancel := context.WithTimeout(context.Background(), a.cfg.ReloadTimeout)
+		cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			logger.Error("执行重载命令失败", logger.Strings("command", command), logger.Err(err))
+		} else {
+			logger.Info("已执行重载命令", logger.Strings("command", command))
+		}
+		cancel()
+	}
+}
+
+// versionsOf 按引用路径拼接版本串，用于判断引用的秘密是否变化
+func versionsOf(refs []string, versions map[string]string) string {
+	if versions == nil {
+		return ""
+	}
+	sorted := append([]string(nil), refs...)
+	sort.Strings(sorted)
+	var b strings.Builder
+	for _, ref := range sorted {
+		b.WriteString(ref)
+		b.WriteByte('=')
+		b.WriteString(versions[ref])
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// isTransportError 判断是否为网络层错误（服务端不可达），业务错误不使用缓存兜底
+func isTransportError(err error) bool {
+	return err != nil && client.Code(err) == 0
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+)
+
+// agentCacheVersion 缓存文件格式版本
+const agentCacheVersion = 1
+
+// agentCacheFile 缓存文件名
+const agentCacheFile = "agent.cache"
+
+// agentCacheEnvelope 缓存文件的外层结构，内容为加密后的 agentCacheData
+type agentCacheEnvelope struct {
+	Version    int    `json:"version"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	AuthTag    []byte `json:"auth_tag"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// agentCacheEntry 单个目标文件的缓存
+type agentCacheEntry struct {
+	Content []byte   `json:"content"`
+	Refs    []string `json:"refs"` // 渲染时引用的秘密路径，用于检测变化
+}
+
+// agentCache 代理的本地加密缓存
+// 缓存密钥由安全密码通过Argon2id派生（盐值随缓存文件保存），
+// 服务端暂时不可达时用缓存的渲染结果写出目标文件，保证应用能够启动
+type agentCache struct {
+	path    string
+	salt    []byte
+	key     []byte
+	entries map[string]*agentCacheEntry
+}
+
+// openAgentCache 打开缓存目录，读取并解密已有缓存
+// 缓存不存在时创建空缓存；缓存损坏或安全密码变化导致无法解密时丢弃旧缓存
+func openAgentCache(dir, securityPIN string) (*agentCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("创建缓存目录失败: %w", err)
+	}
+	cache := &agentCache{
+		path:    filepath.Join(dir, agentCacheFile),
+		entries: make(map[string]*agentCacheEntry),
+	}
+
+	data, err := os.ReadFile(cache.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("读取缓存失败: %w", err)
+	}
+
+	var envelope agentCacheEnvelope
+	if err == nil {
+		if err := json.Unmarshal(data, &envelope); err != nil || envelope.Version != agentCacheVersion || len(envelope.Salt) != crypto.SaltSize {
+			envelope = agentCacheEnvelope{}
+		}
+	}
+	if envelope.Salt == nil {
+		if envelope.Salt, err = crypto.GenerateRandomBytes(crypto.SaltSize); err != nil {
+			return nil, err
+		}
+	}
+
+	cache.salt = envelope.Salt
+	if cache.key, err = crypto.DeriveKEK(securityPIN, cache.salt); err != nil {
+		return nil, err
+	}
+	if envelope.Ciphertext == nil {
+		return cache, nil
+	}
+
+	plain, err := crypto.DecryptAESGCM(envelope.Ciphertext, cache.key, envelope.Nonce, envelope.AuthTag)
+	if err != nil {
+		// 安全密码变化后旧缓存无法解密，重新生成盐值
+		if cache.salt, err = crypto.GenerateRandomBytes(crypto.SaltSize); err != nil {
+			return nil, err
+		}
+		crypto.ClearBytes(cache.key)
+		if cache.key, err = crypto.DeriveKEK(securityPIN, cache.salt); err != nil {
+			return nil, err
+		}
+		return cache, nil
+	}
+	defer crypto.ClearBytes(plain)
+	if err := json.Unmarshal(plain, &cache.entries); err != nil {
+		cache.entries = make(map[string]*agentCacheEntry)
+	}
+	return cache, nil
+}
+
+// get 返回目标文件的缓存
+func (c *agentCache) get(target string) *agentCacheEntry {
+	return c.entries[target]
+}
+
+// put 更新目标文件的缓存并写回磁盘
+func (c *agentCache) put(target string, entry *agentCacheEntry) error {
+	c.entries[target] = entry
+
+	plain, err := json.Marshal(c.entries)
+	if err != nil {
+		return fmt.Errorf("序列化缓存失败: %w", err)
+	}
+	defer crypto.ClearBytes(plain)
+
+	ciphertext, nonce, authTag, err := crypto.EncryptAESGCM(plain, c.key)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(&agentCacheEnvelope{
+		Version:    agentCacheVersion,
+		Salt:       c.salt,
+		Nonce:      nonce,
+		AuthTag:    authTag,
+		Ciphertext: ciphertext,
+	})
+	if err != nil {
+		return fmt.Errorf("序列化缓存失败: %w", err)
+	}
+	return writeFileAtomic(c.path, data, 0600)
+}
+
+// close 清除内存中的缓存密钥
+func (c *agentCache) close() {
+	crypto.ClearBytes(c.key)
+}
+
+// writeFileAtomic 先写入同目录下的临时文件再重命名，保证读取方不会看到写了一半的文件
+func writeFileAtomic(path string, data []byte, mode os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		_ = os.Remove(tmpPath)
+	}()
+
+	// 写入内容前先设置权限，避免秘密短暂以默认权限存在
+	if err := tmp.Chmod(mode); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("设置文件权限失败: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("写入临时文件失败: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("写入临时文件失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入临时文件失败: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("替换文件失败: %w", err)
+	}
+	return nil
+}
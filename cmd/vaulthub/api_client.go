@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/cuihe500/vaulthub/pkg/client"
+	"github.com/cuihe500/vaulthub/pkg/version"
+	"github.com/spf13/cobra"
+)
+
+// 客户端子命令共用的连接参数
+var (
+	apiServer string
+	apiToken  string
+)
+
+// 客户端相关环境变量
+const (
+	envServer      = "VAULTHUB_ADDR"
+	envToken       = "VAULTHUB_TOKEN"
+	envSecurityPIN = "VAULTHUB_SECURITY_PIN"
+)
+
+// addClientFlags 为访问API的客户端子命令添加服务器地址和令牌参数
+func addClientFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&apiServer, "server", "", "VaultHub 服务地址（默认读取 "+envServer+"，否则为 http://localhost:8080）")
+	cmd.Flags().StringVar(&apiToken, "token", "", "访问令牌（默认读取 "+envToken+"）")
+}
+
+// apiClient 命令行使用的API客户端，基于 pkg/client
+type apiClient struct {
+	*client.Client
+}
+
+// newAPIClient 根据命令行参数和环境变量创建API客户端
+func newAPIClient() (*apiClient, error) {
+	server := firstNonEmptyString(apiServer, os.Getenv(envServer), "http://localhost:8080")
+	token := firstNonEmptyString(apiToken, os.Getenv(envToken))
+	if token == "" {
+		return nil, fmt.Errorf("缺少访问令牌，请通过 --token 或 %s 提供", envToken)
+	}
+	return newCLIClient(server, 5*time.Minute, client.WithToken(token))
+}
+
+// newCLIClient 创建命令行使用的客户端
+func newCLIClient(server string, timeout time.Duration, opts ...client.Option) (*apiClient, error) {
+	opts = append([]client.Option{
+		client.WithHTTPClient(&http.Client{Timeout: timeout}),
+		client.WithUserAgent("vaulthub-cli/" + version.Version),
+	}, opts...)
+	c, err := client.New(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &apiClient{Client: c}, nil
+}
+
+// do 发送请求并把data字段解析到out中
+func (c *apiClient) do(method, path string, body, out interface{}) error {
+	return c.Do(context.Background(), method, path, nil, body, out)
+}
+
+// firstNonEmptyString 返回第一个非空字符串
+func firstNonEmptyString(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
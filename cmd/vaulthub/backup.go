@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/backup"
+	"github.com/spf13/cobra"
+)
+
+// 备份口令环境变量
+const envBackupPassphrase = "VAULTHUB_BACKUP_PASSPHRASE"
+
+var (
+	backupOutput      string
+	backupInput       string
+	backupPassphrase  string
+	backupSecurityPIN string
+	backupConflict    string
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "导出或恢复加密的秘密备份",
+	Long: `导出当前用户的全部秘密为口令加密的可移植备份文件，或从备份文件恢复。
+
+备份文件使用 Argon2id 从备份口令派生密钥，并以 AES-256-GCM 加密，
+可以恢复到同一实例或其他 VaultHub 实例。格式说明见 docs/backup_format.md。`,
+}
+
+var backupExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "导出加密备份文件",
+	RunE:  runBackupExport,
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "从加密备份文件恢复秘密",
+	RunE:  runBackupRestore,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{backupExportCmd, backupRestoreCmd} {
+		addClientFlags(cmd)
+		cmd.Flags().StringVar(&backupPassphrase, "passphrase", "", "备份口令，至少12个字符（默认读取 "+envBackupPassphrase+"）")
+		cmd.Flags().StringVar(&backupSecurityPIN, "security-pin", "", "安全密码（默认读取 "+envSecurityPIN+"）")
+		backupCmd.AddCommand(cmd)
+	}
+
+	backupExportCmd.Flags().StringVarP(&backupOutput, "output", "o", "", "备份文件保存路径（默认使用服务端建议的文件名）")
+
+	backupRestoreCmd.Flags().StringVarP(&backupInput, "file", "f", "", "备份文件路径")
+	backupRestoreCmd.Flags().StringVar(&backupConflict, "conflict", string(service.ConflictSkip), "同名秘密处理策略：skip/overwrite/rename")
+	_ = backupRestoreCmd.MarkFlagRequired("file")
+
+	rootCmd.AddCommand(backupCmd)
+}
+
+// backupCredentials 读取备份口令和安全密码
+func backupCredentials() (passphrase, securityPIN string, err error) {
+	passphrase = firstNonEmptyString(backupPassphrase, os.Getenv(envBackupPassphrase))
+	if passphrase == "" {
+		return "", "", fmt.Errorf("缺少备份口令，请通过 --passphrase 或 %s 提供", envBackupPassphrase)
+	}
+	securityPIN = firstNonEmptyString(backupSecurityPIN, os.Getenv(envSecurityPIN))
+	if securityPIN == "" {
+		return "", "", fmt.Errorf("缺少安全密码，请通过 --security-pin 或 %s 提供", envSecurityPIN)
+	}
+	return passphrase, securityPIN, nil
+}
+
+// runBackupExport 导出备份
+func runBackupExport(cmd *cobra.Command, args []string) error {
+	passphrase, securityPIN, err := backupCredentials()
+	if err != nil {
+		return err
+	}
+	if len(passphrase) < backup.MinPassphraseLength {
+		return fmt.Errorf("备份口令长度不能少于%d个字符", backup.MinPassphraseLength)
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	var resp service.ExportBackupResponse
+	req := &service.ExportBackupRequest{SecurityPIN: securityPIN, Passphrase: passphrase}
+	if err := client.do("POST", "/api/v1/secrets/backup", req, &resp); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(resp.Backup, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化备份文件失败: %w", err)
+	}
+	output := firstNonEmptyString(backupOutput, resp.FileName)
+	// 备份文件只允许当前用户读写
+	if err := os.WriteFile(output, data, 0600); err != nil {
+		return fmt.Errorf("写入备份文件失败: %w", err)
+	}
+
+	fmt.Printf("已导出 %d 个秘密到 %s\n", resp.SecretCount, output)
+	for _, item := range resp.Skipped {
+		fmt.Printf("未导出 %s: %s\n", item.Name, item.Reason)
+	}
+	return nil
+}
+
+// runBackupRestore 恢复备份
+func runBackupRestore(cmd *cobra.Command, args []string) error {
+	passphrase, securityPIN, err := backupCredentials()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(backupInput)
+	if err != nil {
+		return fmt.Errorf("读取备份文件失败: %w", err)
+	}
+	var file backup.File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("解析备份文件失败: %w", err)
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	req := &service.RestoreBackupRequest{
+		SecurityPIN:      securityPIN,
+		Passphrase:       passphrase,
+		Backup:           &file,
+		ConflictStrategy: service.ConflictStrategy(backupConflict),
+	}
+	var resp service.RestoreBackupResponse
+	if err := client.do("POST", "/api/v1/secrets/restore", req, &resp); err != nil {
+		return err
+	}
+
+	for _, item := range resp.Items {
+		line := fmt.Sprintf("[%-11s] %s", item.Status, item.Name)
+		if item.RestoredName != "" && item.RestoredName != item.Name {
+			line += " -> " + item.RestoredName
+		}
+		if item.Error != "" {
+			line += " 错误: " + item.Error
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("共 %d 条：新建 %d，覆盖 %d，重命名 %d，跳过 %d，失败 %d\n",
+		resp.Total, resp.Created, resp.Overwritten, resp.Renamed, resp.Skipped, resp.Failed)
+	if resp.Failed > 0 {
+		return fmt.Errorf("%d 条记录恢复失败", resp.Failed)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cuihe500/vaulthub/pkg/breach"
+	"github.com/spf13/cobra"
+)
+
+var (
+	breachInput   string
+	breachOutput  string
+	breachFPRate  float64
+	breachEntries uint64
+	breachPath    string
+)
+
+var breachCmd = &cobra.Command{
+	Use:   "breach",
+	Short: "管理离线泄露密码数据",
+	Long: `管理用于离线检查泄露密码的本地数据集（Have I Been Pwned 格式，SHA-1或NTLM）。
+
+服务端通过配置项 security.breached_passwords_path 加载数据，运行时不访问网络。`,
+}
+
+var breachBuildFilterCmd = &cobra.Command{
+	Use:   "build-filter",
+	Short: "由HIBP完整列表生成布隆过滤器",
+	Long: `读取按哈希排序的HIBP完整列表（每行 HASH:COUNT），生成体积更小的布隆过滤器文件。
+
+未指定 --entries 时会先完整读取一遍输入统计条目数。构建期间整个过滤器保存在内存中，
+误判率0.001时每个条目约占1.8字节。`,
+	RunE: runBreachBuildFilter,
+}
+
+var breachCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "检查密码是否出现在本地泄露数据中",
+	Long:  `从标准输入读取密码（每行一个），使用与服务端相同的方式查询本地数据，用于验证数据文件是否可用。`,
+	RunE:  runBreachCheck,
+}
+
+func init() {
+	breachBuildFilterCmd.Flags().StringVarP(&breachInput, "input", "i", "", "HIBP完整列表文件路径")
+	breachBuildFilterCmd.Flags().StringVarP(&breachOutput, "output", "o", "", "布隆过滤器输出路径")
+	breachBuildFilterCmd.Flags().Float64Var(&breachFPRate, "fp-rate", 0.001, "误判率")
+	breachBuildFilterCmd.Flags().Uint64Var(&breachEntries, "entries", 0, "数据集条目数（默认自动统计）")
+	_ = breachBuildFilterCmd.MarkFlagRequired("input")
+	_ = breachBuildFilterCmd.MarkFlagRequired("output")
+
+	breachCheckCmd.Flags().StringVarP(&breachPath, "path", "p", "", "泄露密码数据路径（列表文件、前缀目录或布隆过滤器）")
+	_ = breachCheckCmd.MarkFlagRequired("path")
+
+	breachCmd.AddCommand(breachBuildFilterCmd)
+	breachCmd.AddCommand(breachCheckCmd)
+	rootCmd.AddCommand(breachCmd)
+}
+
+// runBreachBuildFilter 生成布隆过滤器
+func runBreachBuildFilter(cmd *cobra.Command, args []string) error {
+	entries := breachEntries
+	if entries == 0 {
+		input, err := os.Open(breachInput)
+		if err != nil {
+			return fmt.Errorf("打开输入文件失败: %w", err)
+		}
+		entries, err = breach.CountEntries(input)
+		input.Close()
+		if err != nil {
+			return fmt.Errorf("统计条目数失败: %w", err)
+		}
+		fmt.Printf("条目数: %d\n", entries)
+	}
+
+	input, err := os.Open(breachInput)
+	if err != nil {
+		return fmt.Errorf("打开输入文件失败: %w", err)
+	}
+	defer input.Close()
+
+	// 先写入临时文件，完成后再重命名，避免留下不完整的过滤器
+	output, err := os.CreateTemp(filepath.Dir(breachOutput), ".breach-filter-*")
+	if err != nil {
+		return fmt.Errorf("创建输出文件失败: %w", err)
+	}
+	defer os.Remove(output.Name())
+
+	writer := bufio.NewWriterSize(output, 1<<20)
+	params, err := breach.BuildBloomFilter(bufio.NewReaderSize(input, 1<<20), writer, entries, breachFPRate)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := output.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("生成布隆过滤器失败: %w", err)
+	}
+	if err := os.Rename(output.Name(), breachOutput); err != nil {
+		return fmt.Errorf("保存布隆过滤器失败: %w", err)
+	}
+
+	fmt.Printf("布隆过滤器已生成: %s\n", breachOutput)
+	fmt.Printf("  哈希算法: %s\n", params.HashType)
+	fmt.Printf("  条目数: %d\n", params.Entries)
+	fmt.Printf("  哈希函数个数: %d\n", params.Hashes)
+	fmt.Printf("  文件大小: %.1f MB\n", float64(params.SizeBytes())/(1<<20))
+	if params.Entries > entries {
+		fmt.Printf("警告: 实际条目数超过 --entries，误判率将高于 %g\n", breachFPRate)
+	}
+	return nil
+}
+
+// runBreachCheck 从标准输入逐行读取密码并检查
+func runBreachCheck(cmd *cobra.Command, args []string) error {
+	checker, err := breach.Open(breachPath)
+	if err != nil {
+		return err
+	}
+	defer checker.Close()
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		password := strings.TrimRight(scanner.Text(), "\r")
+		if password == "" {
+			continue
+		}
+		breached, err := checker.Contains(password)
+		if err != nil {
+			return err
+		}
+		if breached {
+			fmt.Println("已泄露")
+		} else {
+			fmt.Println("未发现")
+		}
+	}
+	return scanner.Err()
+}
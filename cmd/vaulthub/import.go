@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/importer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFile         string
+	importFormat       string
+	importCSVMapping   string
+	importDefaultType  string
+	importFolder       string
+	importTags         []string
+	importSkipExisting bool
+	importDryRun       bool
+	importSecurityPIN  string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "从 .env、CSV、Bitwarden 或 KeePass 导出文件批量导入秘密",
+	Long: `读取本地文件并调用 /api/v1/secrets/import 批量导入秘密。
+
+支持的格式：
+  dotenv     .env 文件（KEY=VALUE）
+  csv        带表头的CSV，需通过 --csv-mapping 指定列映射，如 name=Title,value=Password,username=User
+  bitwarden  Bitwarden 未加密JSON导出
+  keepass    KeePass 2.x XML导出
+
+建议先使用 --dry-run 预览导入结果，确认后再正式导入。`,
+	RunE: runImport,
+}
+
+func init() {
+	addClientFlags(importCmd)
+	importCmd.Flags().StringVarP(&importFile, "file", "f", "", "要导入的文件路径")
+	importCmd.Flags().StringVar(&importFormat, "format", "", "文件格式：dotenv/csv/bitwarden/keepass（默认按扩展名推断）")
+	importCmd.Flags().StringVar(&importCSVMapping, "csv-mapping", "", "CSV列映射，格式为 字段=列名，多个用逗号分隔")
+	importCmd.Flags().StringVar(&importDefaultType, "default-type", "", "无法推断类型时使用的秘密类型（默认other）")
+	importCmd.Flags().StringVar(&importFolder, "folder", "", "目标文件夹前缀")
+	importCmd.Flags().StringSliceVar(&importTags, "tags", nil, "追加到每条记录的标签")
+	importCmd.Flags().BoolVar(&importSkipExisting, "skip-existing", false, "跳过已存在的同名秘密")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "仅预览，不写入")
+	importCmd.Flags().StringVar(&importSecurityPIN, "security-pin", "", "安全密码（默认读取 "+envSecurityPIN+"）")
+	_ = importCmd.MarkFlagRequired("file")
+
+	rootCmd.AddCommand(importCmd)
+}
+
+// runImport 执行批量导入
+func runImport(cmd *cobra.Command, args []string) error {
+	content, err := os.ReadFile(importFile)
+	if err != nil {
+		return fmt.Errorf("读取文件失败: %w", err)
+	}
+
+	format := importer.Format(importFormat)
+	if format == "" {
+		if format, err = detectImportFormat(importFile); err != nil {
+			return err
+		}
+	}
+
+	req := &service.ImportSecretsRequest{
+		SecurityPIN:  firstNonEmptyString(importSecurityPIN, os.Getenv(envSecurityPIN)),
+		Format:       format,
+		Content:      string(content),
+		Folder:       importFolder,
+		Tags:         importTags,
+		SkipExisting: importSkipExisting,
+		DryRun:       importDryRun,
+		DefaultType:  models.SecretType(importDefaultType),
+	}
+	if format == importer.FormatCSV {
+		if req.CSVMapping, err = parseCSVMapping(importCSVMapping); err != nil {
+			return err
+		}
+	}
+	if !req.DryRun && req.SecurityPIN == "" {
+		return fmt.Errorf("正式导入需要安全密码，请通过 --security-pin 或 %s 提供", envSecurityPIN)
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	var resp service.ImportSecretsResponse
+	if err := client.do("POST", "/api/v1/secrets/import", req, &resp); err != nil {
+		return err
+	}
+
+	printImportReport(&resp)
+	if resp.Failed > 0 && !resp.DryRun {
+		return fmt.Errorf("%d 条记录导入失败", resp.Failed)
+	}
+	return nil
+}
+
+// detectImportFormat 根据文件扩展名推断格式
+func detectImportFormat(path string) (importer.Format, error) {
+	base := strings.ToLower(filepath.Base(path))
+	switch {
+	case base == ".env" || strings.HasPrefix(base, ".env.") || strings.HasSuffix(base, ".env"):
+		return importer.FormatDotenv, nil
+	case strings.HasSuffix(base, ".csv"):
+		return importer.FormatCSV, nil
+	case strings.HasSuffix(base, ".json"):
+		return importer.FormatBitwarden, nil
+	case strings.HasSuffix(base, ".xml"):
+		return importer.FormatKeePass, nil
+	default:
+		return "", fmt.Errorf("无法根据文件名推断格式，请使用 --format 指定")
+	}
+}
+
+// parseCSVMapping 解析 字段=列名 形式的CSV列映射
+func parseCSVMapping(raw string) (*importer.CSVMapping, error) {
+	if raw == "" {
+		return nil, fmt.Errorf("CSV格式需要通过 --csv-mapping 指定列映射，例如 name=Title,value=Password")
+	}
+
+	mapping := &importer.CSVMapping{}
+	fields := map[string]*string{
+		"name":        &mapping.Name,
+		"value":       &mapping.Value,
+		"type":        &mapping.Type,
+		"username":    &mapping.Username,
+		"url":         &mapping.URL,
+		"description": &mapping.Description,
+		"tags":        &mapping.Tags,
+		"folder":      &mapping.Folder,
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		key, col, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("无效的列映射 %q，应为 字段=列名", pair)
+		}
+		target, exists := fields[strings.TrimSpace(key)]
+		if !exists {
+			return nil, fmt.Errorf("未知的映射字段 %q", key)
+		}
+		*target = strings.TrimSpace(col)
+	}
+	if mapping.Name == "" || mapping.Value == "" {
+		return nil, fmt.Errorf("CSV列映射必须包含 name 和 value")
+	}
+	return mapping, nil
+}
+
+// printImportReport 打印导入结果
+func printImportReport(resp *service.ImportSecretsResponse) {
+	if resp.DryRun {
+		fmt.Println("预览模式（未写入任何数据）")
+	}
+	for _, item := range resp.Items {
+		line := fmt.Sprintf("[%-12s] #%-5d %s", item.Status, item.Index, item.Name)
+		if item.SecretType != "" {
+			line += fmt.Sprintf(" (%s)", item.SecretType)
+		}
+		if item.Folder != "" {
+			line += " 文件夹=" + item.Folder
+		}
+		if item.Error != "" {
+			line += " 错误: " + item.Error
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("共 %d 条：导入 %d，跳过 %d，失败 %d\n", resp.Total, resp.Imported, resp.Skipped, resp.Failed)
+}
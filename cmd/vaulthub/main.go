@@ -3,12 +3,14 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/cuihe500/vaulthub/internal/api/grpcserver"
 	"github.com/cuihe500/vaulthub/internal/api/routes"
 	"github.com/cuihe500/vaulthub/internal/app"
 	"github.com/cuihe500/vaulthub/internal/config"
@@ -148,8 +150,10 @@ func runServer(cmd *cobra.Command, args []string) {
 	}
 	defer scheduler.Stop()
 
-	// 8. 初始化路由
-	router := initRouter(cfg, mgr)
+	// 8. 创建服务容器并初始化路由
+	// HTTP和gRPC共用同一组service实例（如密钥轮换进度保存在service内存中）
+	svc := routes.NewServiceContainer(mgr)
+	router := initRouter(cfg, mgr, svc)
 
 	// 9. 创建 HTTP 服务器
 	srv := &http.Server{
@@ -168,8 +172,27 @@ func runServer(cmd *cobra.Command, args []string) {
 		}
 	}()
 
-	// 11. 优雅关闭
-	gracefulShutdown(srv, scheduler)
+	// 11. 启动 gRPC 服务器（可选，独立端口）
+	var grpcSrv *grpcserver.Server
+	if cfg.GRPC.Enabled {
+		lis, err := net.Listen("tcp", cfg.GRPC.Address())
+		if err != nil {
+			logger.Fatal("监听gRPC端口失败", logger.Err(err))
+		}
+		grpcSrv = grpcserver.NewServer(mgr, svc)
+		go func() {
+			logger.Info("启动gRPC服务器",
+				logger.String("host", cfg.GRPC.Host),
+				logger.Int("port", cfg.GRPC.Port),
+			)
+			if err := grpcSrv.Serve(lis); err != nil {
+				logger.Fatal("启动gRPC服务器失败", logger.Err(err))
+			}
+		}()
+	}
+
+	// 12. 优雅关闭
+	gracefulShutdown(srv, grpcSrv, scheduler)
 }
 
 // loadConfig 加载配置
@@ -199,13 +222,26 @@ func initScheduler(mgr *app.Manager) *app.Scheduler {
 	// 创建统计服务
 	statisticsService := initStatisticsService(mgr)
 
+	// 创建回收站服务（彻底清除秘密时一并删除附件）
+	attachmentService := service.NewAttachmentService(mgr.DB, encryptionService, mgr.ConfigManager, mgr.BlobStore)
+	trashService := initTrashService(mgr, encryptionService, attachmentService)
+
+	// 创建检出服务（释放超时检出）
+	checkoutService := initCheckoutService(mgr, encryptionService)
+
+	// 创建紧急访问服务（等待期结束后通知可信联系人）
+	emergencyService := initEmergencyAccessService(mgr, encryptionService)
+
+	// 创建LDAP服务（定期按组同步角色）
+	ldapService := service.NewLDAPService(mgr.DB, mgr.Redis, mgr.LDAP, mgr.LDAPConfig)
+
 	// 创建调度器
-	return app.NewScheduler(keyRotationService, statisticsService)
+	return app.NewScheduler(keyRotationService, statisticsService, trashService, checkoutService, emergencyService, ldapService)
 }
 
 // initEncryptionService 创建加密服务实例
 func initEncryptionService(mgr *app.Manager) *service.EncryptionService {
-	return service.NewEncryptionService(mgr.DB)
+	return service.NewEncryptionService(mgr.DB, mgr.BreachChecker)
 }
 
 // initKeyRotationService 创建密钥轮换服务实例
@@ -218,8 +254,25 @@ func initStatisticsService(mgr *app.Manager) *service.StatisticsService {
 	return service.NewStatisticsService(mgr.DB)
 }
 
+// initTrashService 创建回收站服务实例
+func initTrashService(mgr *app.Manager, encryptionService *service.EncryptionService, attachmentService *service.AttachmentService) *service.TrashService {
+	return service.NewTrashService(mgr.DB, encryptionService, attachmentService, mgr.ConfigManager, mgr.AuditService)
+}
+
+// initCheckoutService 创建秘密检出服务实例
+func initCheckoutService(mgr *app.Manager, encryptionService *service.EncryptionService) *service.CheckoutService {
+	return service.NewCheckoutService(mgr.DB, mgr.Redis, encryptionService, mgr.ConfigManager, mgr.AuditService)
+}
+
+// initEmergencyAccessService 创建紧急访问服务实例
+func initEmergencyAccessService(mgr *app.Manager, encryptionService *service.EncryptionService) *service.EmergencyAccessService {
+	emailService := service.NewEmailService(mgr.DB, mgr.Redis, mgr.ConfigManager)
+	passwordPolicy := service.NewPasswordPolicyService(mgr.DB, mgr.ConfigManager, mgr.BreachChecker)
+	return service.NewEmergencyAccessService(mgr.DB, mgr.Redis, encryptionService, emailService, mgr.ConfigManager, mgr.AuditService, passwordPolicy)
+}
+
 // initRouter 初始化路由
-func initRouter(cfg *config.Config, mgr *app.Manager) *gin.Engine {
+func initRouter(cfg *config.Config, mgr *app.Manager, svc *routes.ServiceContainer) *gin.Engine {
 	// 设置 Gin 运行模式
 	gin.SetMode(cfg.Server.Mode)
 
@@ -238,13 +291,14 @@ func initRouter(cfg *config.Config, mgr *app.Manager) *gin.Engine {
 	router.Use(logger.GinRecovery())
 
 	// 注册业务路由，传入 Manager
-	routes.Setup(router, mgr)
+	routes.Setup(router, mgr, svc)
 
 	return router
 }
 
 // gracefulShutdown 优雅关闭服务器
-func gracefulShutdown(srv *http.Server, scheduler *app.Scheduler) {
+// grpcSrv 为nil表示未启用gRPC服务
+func gracefulShutdown(srv *http.Server, grpcSrv *grpcserver.Server, scheduler *app.Scheduler) {
 	// 创建信号通道
 	quit := make(chan os.Signal, 1)
 	// 监听中断信号
@@ -263,6 +317,9 @@ func gracefulShutdown(srv *http.Server, scheduler *app.Scheduler) {
 
 	// 关闭服务器
 	logger.Info("正在关闭服务器...")
+	if grpcSrv != nil {
+		grpcSrv.Shutdown(ctx)
+	}
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Error("服务器关闭失败", logger.Err(err))
 		return
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var (
+	renderInput       string
+	renderOutput      string
+	renderSecurityPIN string
+)
+
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "渲染引用秘密的配置模板",
+	Long: `使用Go text/template语法渲染配置模板，模板中的秘密引用由服务端解析并解密：
+
+  password = {{ secret "prod/db" }}
+  user     = {{ secret "prod/db-json" "username" }}
+
+路径为"文件夹/名称"，第二个参数可选，用于从JSON格式的秘密中取字段。
+任一引用不存在时渲染失败，不会输出部分结果。`,
+	Example: `  vaulthub render -f app.conf.tmpl -o app.conf
+  cat app.conf.tmpl | vaulthub render > app.conf`,
+	RunE: runRender,
+}
+
+func init() {
+	addClientFlags(renderCmd)
+	renderCmd.Flags().StringVarP(&renderInput, "file", "f", "-", "模板文件路径，- 表示从标准输入读取")
+	renderCmd.Flags().StringVarP(&renderOutput, "output", "o", "", "渲染结果保存路径（默认输出到标准输出）")
+	renderCmd.Flags().StringVar(&renderSecurityPIN, "security-pin", "", "安全密码（默认读取 "+envSecurityPIN+"）")
+
+	rootCmd.AddCommand(renderCmd)
+}
+
+// runRender 渲染模板
+func runRender(cmd *cobra.Command, args []string) error {
+	securityPIN := firstNonEmptyString(renderSecurityPIN, os.Getenv(envSecurityPIN))
+	if securityPIN == "" {
+		return fmt.Errorf("缺少安全密码，请通过 --security-pin 或 %s 提供", envSecurityPIN)
+	}
+
+	var (
+		tmpl []byte
+		err  error
+	)
+	if renderInput == "-" {
+		tmpl, err = io.ReadAll(os.Stdin)
+	} else {
+		tmpl, err = os.ReadFile(renderInput)
+	}
+	if err != nil {
+		return fmt.Errorf("读取模板失败: %w", err)
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	var resp service.RenderTemplateResponse
+	req := &service.RenderTemplateRequest{SecurityPIN: securityPIN, Template: string(tmpl)}
+	if err := client.do("POST", "/api/v1/secrets/render", req, &resp); err != nil {
+		return err
+	}
+
+	if renderOutput == "" {
+		_, err := io.WriteString(os.Stdout, resp.Output)
+		return err
+	}
+	// 渲染结果包含明文秘密，只允许当前用户读写
+	if err := os.WriteFile(renderOutput, []byte(resp.Output), 0600); err != nil {
+		return fmt.Errorf("写入渲染结果失败: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "已渲染 %d 个秘密引用到 %s\n", len(resp.Secrets), renderOutput)
+	return nil
+}
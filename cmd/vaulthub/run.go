@@ -0,0 +1,390 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var (
+	runFolders     []string
+	runMapFile     string
+	runSecurityPIN string
+	runWatch       bool
+	runInterval    time.Duration
+	runKillTimeout time.Duration
+)
+
+// envNamePattern 合法的环境变量名
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// forwardedSignals 转发给子进程的信号
+var forwardedSignals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT}
+
+var runCmd = &cobra.Command{
+	Use:   "run [flags] -- command [args...]",
+	Short: "以环境变量注入秘密并运行命令",
+	Long: `从 VaultHub 获取秘密，以环境变量的形式注入并运行指定命令。
+
+秘密来源（可同时使用）：
+  --folder   注入文件夹下的全部秘密，变量名由秘密名称转换（大写，非字母数字替换为下划线）
+  --map      映射文件，每行 "变量名=文件夹/名称"，可用 "#字段" 取JSON秘密中的字段，
+             以 # 开头的行为注释；与 --folder 得到的变量重名时以映射文件为准
+
+秘密值只保存在内存中，不会写入磁盘；安全密码不会传递给子进程。
+收到的 SIGINT/SIGTERM/SIGHUP/SIGQUIT 会转发给子进程，命令以子进程的退出码退出。
+--watch 模式下定期检查引用的秘密，值发生变化时重新获取并重启子进程。`,
+	Example: `  vaulthub run --folder prod/app -- ./server --port 8080
+  vaulthub run --map app.env --watch -- node index.js`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRun,
+}
+
+func init() {
+	addClientFlags(runCmd)
+	runCmd.Flags().StringArrayVar(&runFolders, "folder", nil, "注入该文件夹下的全部秘密（可重复指定）")
+	runCmd.Flags().StringVar(&runMapFile, "map", "", "环境变量映射文件路径")
+	runCmd.Flags().StringVar(&runSecurityPIN, "security-pin", "", "安全密码（默认读取 "+envSecurityPIN+"）")
+	runCmd.Flags().BoolVar(&runWatch, "watch", false, "秘密变化时重启子进程")
+	runCmd.Flags().DurationVar(&runInterval, "interval", 30*time.Second, "--watch 模式下的检查间隔")
+	runCmd.Flags().DurationVar(&runKillTimeout, "kill-timeout", 10*time.Second, "重启时等待子进程退出的时间，超时后强制结束")
+	// 第一个非flag参数之后的内容都属于子命令，无需再用 -- 分隔
+	runCmd.Flags().SetInterspersed(false)
+
+	rootCmd.AddCommand(runCmd)
+}
+
+// envBinding 环境变量与秘密的对应关系
+type envBinding struct {
+	Name  string
+	Path  string
+	Field string
+}
+
+// secretRunner 负责获取秘密并管理子进程
+type secretRunner struct {
+	client      *apiClient
+	securityPIN string
+	mapping     []envBinding // 映射文件中的绑定，启动时读取一次
+}
+
+// runRun 执行run命令
+func runRun(cmd *cobra.Command, args []string) error {
+	if len(runFolders) == 0 && runMapFile == "" {
+		return errors.New("请通过 --folder 或 --map 指定要注入的秘密")
+	}
+	if runWatch && runInterval < time.Second {
+		return errors.New("--interval 不能小于1秒")
+	}
+	securityPIN := firstNonEmptyString(runSecurityPIN, os.Getenv(envSecurityPIN))
+	if securityPIN == "" {
+		return fmt.Errorf("缺少安全密码，请通过 --security-pin 或 %s 提供", envSecurityPIN)
+	}
+
+	client, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	runner := &secretRunner{client: client, securityPIN: securityPIN}
+	if runMapFile != "" {
+		if runner.mapping, err = parseEnvMapping(runMapFile); err != nil {
+			return err
+		}
+	}
+
+	state, bindings, err := runner.snapshot()
+	if err != nil {
+		return err
+	}
+	values, err := runner.fetch(bindings)
+	if err != nil {
+		return err
+	}
+
+	code, err := runner.supervise(args, state, values)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		os.Exit(code)
+	}
+	return nil
+}
+
+// snapshot 查询秘密列表，解析出需要注入的变量，并返回用于检测变化的状态串
+func (r *secretRunner) snapshot() (string, []envBinding, error) {
+	var list service.ListUserSecretsResponse
+	if err := r.client.do("GET", "/api/v1/secrets", nil, &list); err != nil {
+		return "", nil, err
+	}
+
+	byPath := make(map[string]*models.SafeEncryptedSecret, len(list.Secrets))
+	byName := make(map[string]envBinding)
+	for _, secret := range list.Secrets {
+		path := service.SecretRefPath(secret.Metadata, secret.SecretName)
+		byPath[path] = secret
+
+		folder := ""
+		if secret.Metadata != nil {
+			folder = strings.Trim(secret.Metadata.Folder, "/")
+		}
+		for _, want := range runFolders {
+			if folder != strings.Trim(want, "/") {
+				continue
+			}
+			name := envVarName(secret.SecretName)
+			if existing, ok := byName[name]; ok && existing.Path != path {
+				return "", nil, fmt.Errorf("秘密 %s 和 %s 转换后的变量名都是 %s，请使用 --map 指定", existing.Path, path, name)
+			}
+			byName[name] = envBinding{Name: name, Path: path}
+		}
+	}
+	// 映射文件优先于文件夹
+	for _, b := range r.mapping {
+		byName[b.Name] = b
+	}
+
+	bindings := make([]envBinding, 0, len(byName))
+	for _, b := range byName {
+		bindings = append(bindings, b)
+	}
+	sort.Slice(bindings, func(i, j int) bool { return bindings[i].Name < bindings[j].Name })
+
+	// 状态串包含每个变量引用的秘密UUID和值的变更时间，任一变化都需要重启子进程
+	var state strings.Builder
+	var missing []string
+	for _, b := range bindings {
+		secret, ok := byPath[b.Path]
+		if !ok {
+			missing = append(missing, b.Path)
+			continue
+		}
+		version := secret.UpdatedAt
+		if secret.ValueChangedAt != nil {
+			version = *secret.ValueChangedAt
+		}
+		fmt.Fprintf(&state, "%s=%s#%s@%s:%d\n", b.Name, b.Path, b.Field, secret.SecretUUID, version.UnixNano())
+	}
+	if len(missing) > 0 {
+		return "", nil, fmt.Errorf("引用的秘密不存在: %s", strings.Join(missing, ", "))
+	}
+	return state.String(), bindings, nil
+}
+
+// fetch 通过模板渲染接口一次解锁获取所有变量的值
+// 每个值以Go带引号字符串的形式输出一行，避免值中的换行破坏格式
+func (r *secretRunner) fetch(bindings []envBinding) (map[string]string, error) {
+	values := make(map[string]string, len(bindings))
+	if len(bindings) == 0 {
+		return values, nil
+	}
+
+	var tmpl strings.Builder
+	for _, b := range bindings {
+		args := strconv.Quote(b.Path)
+		if b.Field != "" {
+			args += " " + strconv.Quote(b.Field)
+		}
+		fmt.Fprintf(&tmpl, "%s={{ printf \"%%q\" (secret %s) }}\n", b.Name, args)
+	}
+
+	var resp service.RenderTemplateResponse
+	req := &service.RenderTemplateRequest{SecurityPIN: r.securityPIN, Template: tmpl.String()}
+	if err := r.client.do("POST", "/api/v1/secrets/render", req, &resp); err != nil {
+		return nil, err
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(resp.Output, "\n"), "\n") {
+		name, quoted, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, errors.New("解析秘密值失败: 响应格式不正确")
+		}
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, fmt.Errorf("解析变量 %s 的值失败: %w", name, err)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// supervise 启动子进程并转发信号，--watch 模式下秘密变化时重启子进程
+// 返回子进程的退出码
+func (r *secretRunner) supervise(args []string, state string, values map[string]string) (int, error) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, forwardedSignals...)
+	defer signal.Stop(signals)
+
+	var tick <-chan time.Time
+	if runWatch {
+		ticker := time.NewTicker(runInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		child, done, err := startChild(args, values)
+		if err != nil {
+			return 0, err
+		}
+
+	wait:
+		for {
+			select {
+			case sig := <-signals:
+				_ = child.Process.Signal(sig)
+			case err := <-done:
+				return exitCode(err), nil
+			case <-tick:
+				newState, bindings, err := r.snapshot()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "vaulthub: 检查秘密变化失败: %v\n", err)
+					continue
+				}
+				if newState == state {
+					continue
+				}
+				newValues, err := r.fetch(bindings)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "vaulthub: 获取秘密失败，继续运行当前进程: %v\n", err)
+					continue
+				}
+				fmt.Fprintln(os.Stderr, "vaulthub: 秘密已变化，重启子进程")
+				stopChild(child, done)
+				state, values = newState, newValues
+				break wait
+			}
+		}
+	}
+}
+
+// startChild 以注入的环境变量启动子进程
+func startChild(args []string, values map[string]string) (*exec.Cmd, <-chan error, error) {
+	child := exec.Command(args[0], args[1:]...)
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+	child.Env = childEnv(values)
+	if err := child.Start(); err != nil {
+		return nil, nil, fmt.Errorf("启动命令失败: %w", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- child.Wait()
+	}()
+	return child, done, nil
+}
+
+// stopChild 发送SIGTERM并等待子进程退出，超时后强制结束
+func stopChild(child *exec.Cmd, done <-chan error) {
+	_ = child.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-done:
+	case <-time.After(runKillTimeout):
+		_ = child.Process.Kill()
+		<-done
+	}
+}
+
+// childEnv 在当前环境变量基础上注入秘密，同名变量以秘密为准，并去掉安全密码
+func childEnv(values map[string]string) []string {
+	env := make([]string, 0, len(os.Environ())+len(values))
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		if _, ok := values[name]; ok || name == envSecurityPIN {
+			continue
+		}
+		env = append(env, kv)
+	}
+	for name, value := range values {
+		env = append(env, name+"="+value)
+	}
+	return env
+}
+
+// exitCode 返回子进程的退出码，被信号终止时返回1
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+	return 1
+}
+
+// envVarName 把秘密名称转换为环境变量名：大写，非字母数字替换为下划线，数字开头时加下划线
+func envVarName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	result := b.String()
+	if result == "" || (result[0] >= '0' && result[0] <= '9') {
+		result = "_" + result
+	}
+	return result
+}
+
+// parseEnvMapping 读取映射文件，每行格式为 "变量名=文件夹/名称[#字段]"
+func parseEnvMapping(path string) ([]envBinding, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取映射文件失败: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var bindings []envBinding
+	seen := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, ref, ok := strings.Cut(line, "=")
+		name, ref = strings.TrimSpace(name), strings.TrimSpace(ref)
+		if !ok || ref == "" {
+			return nil, fmt.Errorf("映射文件第%d行格式错误，应为 变量名=文件夹/名称", lineNo)
+		}
+		if !envNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("映射文件第%d行的变量名无效: %s", lineNo, name)
+		}
+		if prev, ok := seen[name]; ok {
+			return nil, fmt.Errorf("映射文件第%d行的变量 %s 与第%d行重复", lineNo, name, prev)
+		}
+		seen[name] = lineNo
+
+		secretPath, field, _ := strings.Cut(ref, "#")
+		bindings = append(bindings, envBinding{
+			Name:  name,
+			Path:  strings.Trim(strings.TrimSpace(secretPath), "/"),
+			Field: strings.TrimSpace(field),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取映射文件失败: %w", err)
+	}
+	return bindings, nil
+}
@@ -23,51 +23,44 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/admin/profiles": {
+        "/api/v1/access-requests": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "获取用户档案列表（需要管理员权限）。不传分页参数时全量导出（最多10000条）",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "分页查询当前用户提交的访问申请",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "用户档案"
+                    "访问审批"
                 ],
-                "summary": "获取用户档案列表",
+                "summary": "查询我的访问申请",
                 "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "denied",
+                            "expired",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "申请状态",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "minimum": 1,
                         "type": "integer",
-                        "description": "页码（可选，不传则全量导出）",
+                        "description": "页码",
                         "name": "page",
                         "in": "query"
                     },
                     {
-                        "maximum": 10000,
+                        "maximum": 100,
                         "minimum": 1,
                         "type": "integer",
-                        "description": "每页数量（可选，不传则全量导出）",
+                        "description": "每页数量",
                         "name": "page_size",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "昵称筛选",
-                        "name": "nickname",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "邮箱筛选",
-                        "name": "email",
-                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -82,58 +75,37 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ListProfilesResponse"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ListAccessRequestsResponse"
                                         }
                                     }
                                 }
                             ]
                         }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
                     }
-                }
-            }
-        },
-        "/api/v1/admin/users/{user_id}/profile": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "根据用户ID获取指定用户的档案信息（需要管理员权限）",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/api/v1/access-requests/email-decision": {
+            "get": {
+                "description": "无需登录，根据邮件中的审批令牌查询申请信息，不消耗令牌",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "用户档案"
+                    "访问审批"
                 ],
-                "summary": "获取指定用户档案",
+                "summary": "查询邮件审批链接对应的申请",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "用户ID",
-                        "name": "user_id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "审批令牌",
+                        "name": "token",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -149,52 +121,17 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserProfile"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.EmailApprovalInfo"
                                         }
                                     }
                                 }
                             ]
                         }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
                     }
                 }
             },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "根据用户ID更新指定用户的档案信息（需要管理员权限）",
+            "post": {
+                "description": "无需登录，使用邮件中的一次性审批令牌批准或拒绝申请，审计日志记录为令牌对应的审批人",
                 "consumes": [
                     "application/json"
                 ],
@@ -202,24 +139,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户档案"
+                    "访问审批"
                 ],
-                "summary": "更新指定用户档案",
+                "summary": "通过邮件链接审批",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "用户ID",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "用户档案信息",
-                        "name": "profile",
+                        "description": "审批结果",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.UpdateProfileRequest"
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.EmailDecisionRequest"
                         }
                     }
                 ],
@@ -235,153 +165,73 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserProfile"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretAccessRequest"
                                         }
                                     }
                                 }
                             ]
                         }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
                     }
                 }
             }
         },
-        "/api/v1/audit/logs": {
+        "/api/v1/access-requests/pending": {
             "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "查询审计日志，支持多条件过滤和分页。普通用户只能查询自己的日志，管理员可以查询所有用户的日志。不传分页参数时全量导出（最多10000条）",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "分页查询当前用户有权审批且尚未处理的访问申请",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "审计"
+                    "访问审批"
                 ],
-                "summary": "查询审计日志",
+                "summary": "查询待我审批的申请",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "用户UUID（管理员可指定，普通用户自动使用当前用户）",
-                        "name": "user_uuid",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "操作类型：CREATE/UPDATE/DELETE/ACCESS/LOGIN/LOGOUT",
-                        "name": "action_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "资源类型：vault/secret/user/config",
-                        "name": "resource_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "操作状态：success/failed",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "开始时间（RFC3339格式，如2024-01-01T00:00:00Z）",
-                        "name": "start_time",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "结束时间（RFC3339格式）",
-                        "name": "end_time",
-                        "in": "query"
-                    },
                     {
                         "minimum": 1,
                         "type": "integer",
-                        "description": "页码（可选，不传则全量导出）",
+                        "description": "页码",
                         "name": "page",
                         "in": "query"
                     },
                     {
-                        "maximum": 10000,
+                        "maximum": 100,
                         "minimum": 1,
                         "type": "integer",
-                        "description": "每页数量（可选，不传则全量导出）",
+                        "description": "每页数量",
                         "name": "page_size",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "查询成功",
-                        "schema": {
-                            "$ref": "#/definitions/internal_api_handlers.QueryAuditLogsResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "参数错误",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "401": {
-                        "description": "未授权",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器错误",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ListAccessRequestsResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/audit/logs/export": {
-            "get": {
+                },
                 "security": [
                     {
-                        "ApiKeyAuth": []
+                        "BearerAuth": []
                     }
-                ],
-                "description": "导出各类型加密数据的统计总量。普通用户只能查询自己的统计，管理员可以查询指定用户或全局统计",
+                ]
+            }
+        },
+        "/api/v1/access-requests/{uuid}/approve": {
+            "post": {
+                "description": "批准后申请人在申请的时间窗口内可以解密秘密；申请人本人不能审批",
                 "consumes": [
                     "application/json"
                 ],
@@ -389,126 +239,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "审计"
-                ],
-                "summary": "导出密钥类型统计",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "用户UUID（管理员可指定，普通用户自动使用当前用户，管理员不指定则查询全局）",
-                        "name": "user_uuid",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "导出成功",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.SecretStatisticsExport"
-                        }
-                    },
-                    "401": {
-                        "description": "未授权",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器错误",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/audit/operations/export": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "导出指定时间范围内的操作统计数据（按操作类型分组）。普通用户只能查询自己的统计，管理员可以查询指定用户或全局统计",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "审计"
+                    "访问审批"
                 ],
-                "summary": "导出操作统计",
+                "summary": "批准访问申请",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "用户UUID（管理员可指定，普通用户自动使用当前用户，管理员不指定则查询全局）",
-                        "name": "user_uuid",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "开始时间（RFC3339格式，如2024-01-01T00:00:00Z）",
-                        "name": "start_time",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "结束时间（RFC3339格式）",
-                        "name": "end_time",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "导出成功",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.OperationStatisticsExport"
-                        }
-                    },
-                    "400": {
-                        "description": "参数错误",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "401": {
-                        "description": "未授权",
-                        "schema": {
-                            "type": "string"
-                        }
+                        "description": "申请UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
                     },
-                    "500": {
-                        "description": "服务器错误",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/v1/auth/login": {
-            "post": {
-                "description": "用户登录获取JWT token",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "认证"
-                ],
-                "summary": "用户登录",
-                "parameters": [
                     {
-                        "description": "登录请求",
+                        "description": "审批意见",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.LoginRequest"
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.DecideAccessRequestRequest"
                         }
                     }
                 ],
@@ -524,38 +271,38 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.LoginResponse"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretAccessRequest"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/api/v1/auth/login-with-email": {
+        "/api/v1/access-requests/{uuid}/cancel": {
             "post": {
-                "description": "使用邮箱和验证码登录，无需密码。验证码通过 /api/v1/email/send-code 接口获取",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "撤回待审批的申请；已批准的申请撤回后立即结束解密窗口",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "访问审批"
                 ],
-                "summary": "邮箱验证码登录",
+                "summary": "撤回访问申请",
                 "parameters": [
                     {
-                        "description": "邮箱验证码登录请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.LoginWithEmailRequest"
-                        }
+                        "type": "string",
+                        "description": "申请UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -570,24 +317,24 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.LoginResponse"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretAccessRequest"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/auth/logout": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "用户登出，使当前token失效",
+                ]
+            }
+        },
+        "/api/v1/access-requests/{uuid}/deny": {
+            "post": {
+                "description": "拒绝后申请人需要重新提交申请",
                 "consumes": [
                     "application/json"
                 ],
@@ -595,37 +342,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "访问审批"
                 ],
-                "summary": "用户登出",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                "summary": "拒绝访问申请",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "申请UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "审批意见",
+                        "name": "request",
+                        "in": "body",
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.DecideAccessRequestRequest"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/auth/me": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "获取当前登录用户的详细信息",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "认证"
                 ],
-                "summary": "获取当前用户信息",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -638,19 +374,24 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUser"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretAccessRequest"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/api/v1/auth/register": {
-            "post": {
-                "description": "注册新用户账号,所有新用户默认为普通用户角色,只有管理员才能提权",
+        "/api/v1/admin/checkouts": {
+            "get": {
+                "description": "管理员查询所有用户的检出记录，可按状态和秘密所属用户过滤",
                 "consumes": [
                     "application/json"
                 ],
@@ -658,18 +399,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "秘密管理"
                 ],
-                "summary": "用户注册",
+                "summary": "查询所有检出记录",
                 "parameters": [
                     {
-                        "description": "注册请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RegisterRequest"
-                        }
+                        "enum": [
+                            "active",
+                            "checked_in",
+                            "expired",
+                            "force_released"
+                        ],
+                        "type": "string",
+                        "description": "检出状态",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "秘密所属用户UUID",
+                        "name": "owner_uuid",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -684,19 +449,24 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RegisterResponse"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ListCheckoutsResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/api/v1/auth/request-password-reset": {
+        "/api/v1/admin/checkouts/{uuid}/release": {
             "post": {
-                "description": "通过邮箱申请密码重置，系统将发送重置链接到邮箱。无需登录即可访问",
+                "description": "管理员强制释放秘密当前的检出，秘密被标记为待轮换",
                 "consumes": [
                     "application/json"
                 ],
@@ -704,17 +474,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "秘密管理"
                 ],
-                "summary": "请求密码重置",
+                "summary": "强制释放检出",
                 "parameters": [
                     {
-                        "description": "请求密码重置",
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "强制释放原因",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RequestPasswordResetRequest"
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ForceReleaseRequest"
                         }
                     }
                 ],
@@ -730,58 +506,24 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RequestPasswordResetResponse"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretCheckout"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/auth/reset-password": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "用户忘记密码时，可使用注册时获得的24个单词恢复助记词重置密码",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "认证"
-                ],
-                "summary": "使用恢复密钥重置密码",
-                "parameters": [
-                    {
-                        "description": "重置密码请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ResetPasswordWithRecoveryRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    }
-                }
+                ]
             }
         },
-        "/api/v1/auth/reset-password-with-token": {
-            "post": {
-                "description": "使用邮件中的重置token设置新密码。无需登录即可访问",
+        "/api/v1/admin/profiles": {
+            "get": {
+                "description": "获取用户档案列表（需要管理员权限）。不传分页参数时全量导出（最多10000条）",
                 "consumes": [
                     "application/json"
                 ],
@@ -789,18 +531,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "用户档案"
                 ],
-                "summary": "使用token重置密码",
+                "summary": "获取用户档案列表",
                 "parameters": [
                     {
-                        "description": "重置密码请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ResetPasswordRequest"
-                        }
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "页码（可选，不传则全量导出）",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 10000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "每页数量（可选，不传则全量导出）",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "昵称筛选",
+                        "name": "nickname",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "邮箱筛选",
+                        "name": "email",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -815,53 +575,42 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ResetPasswordResponse"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ListProfilesResponse"
                                         }
                                     }
                                 }
                             ]
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/auth/security-pin-status": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "检查当前用户是否已设置安全密码，用于前端判断是否需要引导用户设置",
-                "tags": [
-                    "认证"
-                ],
-                "summary": "获取安全密码设置状态",
-                "responses": {
-                    "200": {
-                        "description": "返回 has_security_pin 字段",
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
                         }
                     },
-                    "401": {
-                        "description": "未授权",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
                         }
                     },
                     "500": {
-                        "description": "服务器错误",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/api/v1/auth/verify-reset-token": {
+        "/api/v1/admin/users/{user_id}/profile": {
             "get": {
-                "description": "验证密码重置token是否有效（未过期且未使用）。无需登录即可访问",
+                "description": "根据用户ID获取指定用户的档案信息（需要管理员权限）",
                 "consumes": [
                     "application/json"
                 ],
@@ -869,15 +618,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "认证"
+                    "用户档案"
                 ],
-                "summary": "验证密码重置token",
+                "summary": "获取指定用户档案",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "重置token",
-                        "name": "token",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "user_id",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -893,51 +642,17 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.VerifyResetTokenResponse"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserProfile"
                                         }
                                     }
                                 }
                             ]
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/configs": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "获取所有系统配置项（管理员权限）",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "系统配置"
-                ],
-                "summary": "获取系统配置列表",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ListConfigsResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
                         }
                     },
                     "401": {
@@ -952,23 +667,27 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/configs/batch": {
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "批量更新多个配置项（管理员权限）。所有配置在同一事务中更新，全部成功或全部失败",
+                ]
+            },
+            "put": {
+                "description": "根据用户ID更新指定用户的档案信息（需要管理员权限）",
                 "consumes": [
                     "application/json"
                 ],
@@ -976,17 +695,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "系统配置"
+                    "用户档案"
                 ],
-                "summary": "批量更新系统配置",
+                "summary": "更新指定用户档案",
                 "parameters": [
                     {
-                        "description": "批量更新配置请求",
-                        "name": "request",
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "用户档案信息",
+                        "name": "profile",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.BatchUpdateConfigRequest"
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.UpdateProfileRequest"
                         }
                     }
                 ],
@@ -994,7 +720,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserProfile"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -1027,17 +765,17 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/configs/reload": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "从数据库重新加载所有配置到内存（管理员权限）",
+                ]
+            }
+        },
+        "/api/v1/audit/logs": {
+            "get": {
+                "description": "查询审计日志，支持多条件过滤和分页。普通用户只能查询自己的日志，管理员可以查询所有用户的日志。不传分页参数时全量导出（最多10000条）",
                 "consumes": [
                     "application/json"
                 ],
@@ -1045,45 +783,98 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "系统配置"
+                    "审计"
                 ],
-                "summary": "重新加载配置",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
+                "summary": "查询审计日志",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户UUID（管理员可指定，普通用户自动使用当前用户）",
+                        "name": "user_uuid",
+                        "in": "query"
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
+                    {
+                        "type": "string",
+                        "description": "操作类型：CREATE/UPDATE/DELETE/ACCESS/LOGIN/LOGOUT",
+                        "name": "action_type",
+                        "in": "query"
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    {
+                        "type": "string",
+                        "description": "资源类型：vault/secret/user/config",
+                        "name": "resource_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "操作状态：success/failed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始时间（RFC3339格式，如2024-01-01T00:00:00Z）",
+                        "name": "start_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束时间（RFC3339格式）",
+                        "name": "end_time",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "页码（可选，不传则全量导出）",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 10000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "每页数量（可选，不传则全量导出）",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                            "$ref": "#/definitions/internal_api_handlers.QueryAuditLogsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "服务器错误",
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                            "type": "string"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/configs/{key}": {
-            "get": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "ApiKeyAuth": []
                     }
-                ],
-                "description": "根据配置键获取配置详情（管理员权限）",
+                ]
+            }
+        },
+        "/api/v1/audit/logs/export": {
+            "get": {
+                "description": "导出各类型加密数据的统计总量。普通用户只能查询自己的统计，管理员可以查询指定用户或全局统计",
                 "consumes": [
                     "application/json"
                 ],
@@ -1091,70 +882,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "系统配置"
+                    "审计"
                 ],
-                "summary": "获取单个系统配置",
+                "summary": "导出密钥类型统计",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "配置键",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
+                        "description": "用户UUID（管理员可指定，普通用户自动使用当前用户，管理员不指定则查询全局）",
+                        "name": "user_uuid",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "导出成功",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ConfigItem"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.SecretStatisticsExport"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                        "description": "未授权",
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                            "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "服务器错误",
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                            "type": "string"
                         }
                     }
-                }
-            },
-            "put": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "ApiKeyAuth": []
                     }
-                ],
-                "description": "更新指定配置项的值（管理员权限）。配置更新后会立即生效并触发相关观察者回调",
+                ]
+            }
+        },
+        "/api/v1/audit/operations/export": {
+            "get": {
+                "description": "导出指定时间范围内的操作统计数据（按操作类型分组）。普通用户只能查询自己的统计，管理员可以查询指定用户或全局统计",
                 "consumes": [
                     "application/json"
                 ],
@@ -1162,70 +930,65 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "系统配置"
+                    "审计"
                 ],
-                "summary": "更新系统配置",
+                "summary": "导出操作统计",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "配置键",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
+                        "description": "用户UUID（管理员可指定，普通用户自动使用当前用户，管理员不指定则查询全局）",
+                        "name": "user_uuid",
+                        "in": "query"
                     },
                     {
-                        "description": "更新配置请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.UpdateConfigRequest"
-                        }
+                        "type": "string",
+                        "description": "开始时间（RFC3339格式，如2024-01-01T00:00:00Z）",
+                        "name": "start_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束时间（RFC3339格式）",
+                        "name": "end_time",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "导出成功",
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.OperationStatisticsExport"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "参数错误",
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                            "type": "string"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                        "description": "未授权",
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                            "type": "string"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "服务器错误",
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                            "type": "string"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/api/v1/email/send-code": {
+        "/api/v1/auth/approle/login": {
             "post": {
-                "description": "发送邮箱验证码用于注册、登录、重置密码等操作",
+                "description": "服务账户以 role_id + secret_id 登录（AppRole），返回服务账户令牌。令牌有效期取系统配置 service_account_token_ttl_minutes 和 secret_id 剩余有效期中较短的一个，过期后重新登录。\nsecret_id 过期、登录次数用完、来源IP不在绑定的CIDR内或服务账户所属用户被禁用时登录失败。服务账户令牌只能访问 /api/v1/machine 下的接口。",
                 "consumes": [
                     "application/json"
                 ],
@@ -1233,17 +996,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "邮件"
+                    "服务账户"
                 ],
-                "summary": "发送邮箱验证码",
+                "summary": "服务账户登录",
                 "parameters": [
                     {
-                        "description": "发送验证码请求",
+                        "description": "role_id 和 secret_id",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_api_handlers.SendCodeRequest"
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.AppRoleLoginRequest"
                         }
                     }
                 ],
@@ -1259,7 +1022,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/internal_api_handlers.SendCodeResponse"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.AppRoleLoginResponse"
                                         }
                                     }
                                 }
@@ -1269,9 +1032,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/email/verify-code": {
+        "/api/v1/auth/login": {
             "post": {
-                "description": "验证邮箱验证码是否正确（验证成功后验证码自动失效）",
+                "description": "用户登录获取访问令牌和刷新令牌，每次登录创建独立的会话，不影响其他设备上的会话。\n启用或被要求启用两步验证时返回 mfa_required 和 mfa_token，需要再调用 /api/v1/auth/login/mfa 完成登录。\n密码超过 password_max_age_days 时返回 password_change_required 和 password_change_token，需要先调用 /api/v1/auth/login/password-change 修改密码；需要两步验证时先返回 mfa_token，验证通过后才返回修改密码的令牌。\n同一账户或IP连续登录失败后需要等待递增的时间（返回 40011），账户失败次数达到 login_lockout_threshold 时自动锁定并邮件通知用户",
                 "consumes": [
                     "application/json"
                 ],
@@ -1279,17 +1042,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "邮件"
+                    "认证"
                 ],
-                "summary": "验证邮箱验证码",
+                "summary": "用户登录",
                 "parameters": [
                     {
-                        "description": "验证验证码请求",
+                        "description": "登录请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_api_handlers.VerifyCodeRequest"
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.LoginRequest"
                         }
                     }
                 ],
@@ -1305,7 +1068,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/internal_api_handlers.VerifyCodeResponse"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.LoginResponse"
                                         }
                                     }
                                 }
@@ -1315,14 +1078,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/encryption/keys": {
+        "/api/v1/auth/login-with-email": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "为当前用户创建加密密钥（首次使用加密功能时调用）\n警告：返回的恢复密钥仅显示一次，请务必妥善保管",
+                "description": "使用邮箱和验证码登录，无需密码。验证码通过 /api/v1/email/send-code 接口获取",
                 "consumes": [
                     "application/json"
                 ],
@@ -1330,17 +1088,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "秘密管理"
+                    "认证"
                 ],
-                "summary": "创建用户加密密钥",
+                "summary": "邮箱验证码登录",
                 "parameters": [
                     {
-                        "description": "创建加密密钥请求",
+                        "description": "邮箱验证码登录请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateUserEncryptionKeyRequest"
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.LoginWithEmailRequest"
                         }
                     }
                 ],
@@ -1356,7 +1114,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateUserEncryptionKeyResponse"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.LoginResponse"
                                         }
                                     }
                                 }
@@ -1366,14 +1124,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/keys/create": {
+        "/api/v1/auth/login/mfa": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "在用户注册或首次使用加密功能时创建加密密钥，返回24个单词的恢复助记词（仅显示一次）",
+                "description": "登录第二步：提交登录接口返回的 mfa_token 和认证器验证码（或备用码）换取JWT token。\n角色被要求启用两步验证但尚未绑定时，先调用 /api/v1/auth/login/mfa/enroll 绑定认证器，此时验证码用于确认绑定，响应中附带备用码。\n登录时密码已过期的，验证通过后返回 password_change_required 和 password_change_token，需要再调用 /api/v1/auth/login/password-change 修改密码",
                 "consumes": [
                     "application/json"
                 ],
@@ -1381,17 +1134,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "密钥管理"
+                    "认证"
                 ],
-                "summary": "创建用户加密密钥",
+                "summary": "两步验证登录",
                 "parameters": [
                     {
-                        "description": "创建密钥请求",
+                        "description": "两步验证登录请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateUserEncryptionKeyRequest"
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.LoginWithMFARequest"
                         }
                     }
                 ],
@@ -1407,7 +1160,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateUserEncryptionKeyResponse"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.LoginResponse"
                                         }
                                     }
                                 }
@@ -1417,14 +1170,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/keys/rotate": {
+        "/api/v1/auth/login/mfa/enroll": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "生成新的数据加密密钥(DEK)并在后台渐进式迁移所有加密数据。注意：每30天最多轮换一次",
+                "description": "角色被要求启用两步验证但尚未绑定时，使用登录接口返回的 mfa_token 获取TOTP密钥和otpauth URI",
                 "consumes": [
                     "application/json"
                 ],
@@ -1432,17 +1180,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "密钥管理"
+                    "认证"
                 ],
-                "summary": "手动触发密钥轮换",
+                "summary": "登录时绑定认证器",
                 "parameters": [
                     {
-                        "description": "密钥轮换请求",
+                        "description": "登录挑战令牌",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RotateDEKRequest"
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.BeginLoginMFAEnrollmentRequest"
                         }
                     }
                 ],
@@ -1458,7 +1206,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RotateDEKResponse"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.MFAEnrollmentResponse"
                                         }
                                     }
                                 }
@@ -1468,24 +1216,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/keys/rotation-status": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "获取当前用户的密钥轮换状态和数据迁移进度",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/auth/login/oidc/begin": {
+            "post": {
+                "description": "返回身份提供方的授权地址（授权码+PKCE），前端跳转到该地址登录，10分钟内有效。\n身份提供方回调到配置的 redirect_url 后，把回调中的 code 和 state 提交到 /api/v1/auth/login/oidc/finish",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "密钥管理"
+                    "认证"
                 ],
-                "summary": "查询密钥轮换进度",
+                "summary": "开始单点登录",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1498,7 +1238,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.MigrationTask"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.OIDCLoginBeginResponse"
                                         }
                                     }
                                 }
@@ -1508,14 +1248,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/keys/verify-recovery": {
+        "/api/v1/auth/login/oidc/finish": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "验证用户输入的恢复助记词是否正确，用于在实际重置密码前进行确认",
+                "description": "提交身份提供方回调中的 code 和 state 换取JWT token。首次登录时按已验证的邮箱关联已有账户，\n没有账户时按配置自动创建；每次登录按角色映射同步角色。启用两步验证的用户仍需完成两步验证",
                 "consumes": [
                     "application/json"
                 ],
@@ -1523,17 +1258,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "密钥管理"
+                    "认证"
                 ],
-                "summary": "验证恢复密钥有效性",
+                "summary": "单点登录",
                 "parameters": [
                     {
-                        "description": "验证恢复密钥请求",
+                        "description": "单点登录请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.VerifyRecoveryKeyRequest"
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.OIDCLoginRequest"
                         }
                     }
                 ],
@@ -1549,7 +1284,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.VerifyRecoveryKeyResponse"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.LoginResponse"
                                         }
                                     }
                                 }
@@ -1559,14 +1294,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/profile": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "获取当前登录用户的档案信息",
+        "/api/v1/auth/login/password-change": {
+            "post": {
+                "description": "密码超过系统配置的最长使用期限时，登录接口返回 password_change_required 和 password_change_token。\n需要两步验证的用户在验证通过后才会拿到该令牌。提交令牌和符合密码策略的新密码后直接返回JWT token，修改后该用户其他设备上的登录会话全部失效",
                 "consumes": [
                     "application/json"
                 ],
@@ -1574,9 +1304,20 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户档案"
+                    "认证"
+                ],
+                "summary": "修改过期密码并继续登录",
+                "parameters": [
+                    {
+                        "description": "修改过期密码请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ChangeExpiredPasswordRequest"
+                        }
+                    }
                 ],
-                "summary": "获取用户档案信息",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1589,61 +1330,26 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserProfile"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.LoginResponse"
                                         }
                                     }
                                 }
                             ]
                         }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "更新当前用户的档案信息",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/api/v1/auth/login/webauthn/begin": {
+            "post": {
+                "description": "无需用户名，返回 session_token 和传给 navigator.credentials.get() 的参数，5分钟内有效",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "用户档案"
-                ],
-                "summary": "更新用户档案",
-                "parameters": [
-                    {
-                        "description": "用户档案信息",
-                        "name": "profile",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.UpdateProfileRequest"
-                        }
-                    }
+                    "认证"
                 ],
+                "summary": "开始通行密钥登录",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1656,52 +1362,19 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserProfile"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.WebAuthnAssertionResponse"
                                         }
                                     }
                                 }
                             ]
                         }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/auth/login/webauthn/finish": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "为当前用户创建档案信息",
+                "description": "提交 /api/v1/auth/login/webauthn/begin 返回的 session_token 和 navigator.credentials.get() 返回的凭证换取JWT token。\n通行密钥登录不再要求TOTP两步验证，签发的令牌同时视为已完成二次验证",
                 "consumes": [
                     "application/json"
                 ],
@@ -1709,17 +1382,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户档案"
+                    "认证"
                 ],
-                "summary": "创建用户档案",
+                "summary": "通行密钥登录",
                 "parameters": [
                     {
-                        "description": "用户档案信息",
-                        "name": "profile",
+                        "description": "通行密钥登录请求",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateProfileRequest"
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.WebAuthnLoginRequest"
                         }
                     }
                 ],
@@ -1735,46 +1408,19 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserProfile"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.LoginResponse"
                                         }
                                     }
                                 }
                             ]
                         }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "删除当前用户的档案信息",
+            }
+        },
+        "/api/v1/auth/logout": {
+            "post": {
+                "description": "用户登出，注销当前会话，其他设备上的会话不受影响",
                 "consumes": [
                     "application/json"
                 ],
@@ -1782,43 +1428,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户档案"
+                    "认证"
                 ],
-                "summary": "删除用户档案",
+                "summary": "用户登出",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
                         }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
                     }
-                }
-            },
-            "patch": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "创建或更新当前用户的档案信息（如果不存在则创建，存在则更新）",
+                ]
+            }
+        },
+        "/api/v1/auth/me": {
+            "get": {
+                "description": "获取当前登录用户的详细信息",
                 "consumes": [
                     "application/json"
                 ],
@@ -1826,20 +1456,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户档案"
-                ],
-                "summary": "创建或更新用户档案",
-                "parameters": [
-                    {
-                        "description": "用户档案信息",
-                        "name": "profile",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateProfileRequest"
-                        }
-                    }
+                    "认证"
                 ],
+                "summary": "获取当前用户信息",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1852,48 +1471,61 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserProfile"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUser"
                                         }
                                     }
                                 }
                             ]
                         }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                        }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/api/v1/secrets": {
+        "/api/v1/auth/mfa": {
             "get": {
+                "description": "查询当前用户是否已启用两步验证、角色是否被要求启用以及剩余备用码数量",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "两步验证"
+                ],
+                "summary": "查询两步验证状态",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.MFAStatusResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "获取当前用户的秘密列表（不包含加密数据）。不传分页参数时全量导出（最多10000条）",
+                ]
+            }
+        },
+        "/api/v1/auth/mfa/backup-codes": {
+            "post": {
+                "description": "提交认证器验证码或备用码后重新生成备用码，旧备用码全部作废，新备用码只返回这一次",
                 "consumes": [
                     "application/json"
                 ],
@@ -1901,39 +1533,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "秘密管理"
+                    "两步验证"
                 ],
-                "summary": "获取秘密列表",
+                "summary": "重新生成备用码",
                 "parameters": [
                     {
-                        "enum": [
-                            "api_key",
-                            "db_credential",
-                            "certificate",
-                            "ssh_key",
-                            "token",
-                            "password",
-                            "other"
-                        ],
-                        "type": "string",
-                        "description": "秘密类型",
-                        "name": "secret_type",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "页码（可选，不传则全量导出）",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 10000,
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "每页数量（可选，不传则全量导出）",
-                        "name": "page_size",
-                        "in": "query"
+                        "description": "认证器验证码或备用码",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.MFACodeRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -1948,22 +1559,24 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ListUserSecretsResponse"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.MFABackupCodesResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "加密并存储敏感数据（需要输入密码）",
+                ]
+            }
+        },
+        "/api/v1/auth/mfa/confirm": {
+            "post": {
+                "description": "提交认证器生成的第一个验证码，验证通过后启用两步验证并返回备用码（只返回这一次）",
                 "consumes": [
                     "application/json"
                 ],
@@ -1971,17 +1584,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "秘密管理"
+                    "两步验证"
                 ],
-                "summary": "创建加密秘密",
+                "summary": "确认绑定并启用两步验证",
                 "parameters": [
                     {
-                        "description": "创建秘密请求",
+                        "description": "认证器验证码",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.EncryptAndStoreSecretRequest"
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.MFACodeRequest"
                         }
                     }
                 ],
@@ -1997,24 +1610,24 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeEncryptedSecret"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.MFABackupCodesResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/secrets/{uuid}": {
-            "delete": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "删除指定的秘密（软删除）",
+                ]
+            }
+        },
+        "/api/v1/auth/mfa/disable": {
+            "post": {
+                "description": "提交认证器验证码或备用码停用两步验证；角色被要求启用两步验证时不允许停用",
                 "consumes": [
                     "application/json"
                 ],
@@ -2022,16 +1635,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "秘密管理"
+                    "两步验证"
                 ],
-                "summary": "删除秘密",
+                "summary": "停用两步验证",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "秘密UUID",
-                        "name": "uuid",
-                        "in": "path",
-                        "required": true
+                        "description": "认证器验证码或备用码",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.MFACodeRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -2041,17 +1656,54 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/api/v1/secrets/{uuid}/decrypt": {
+        "/api/v1/auth/mfa/enroll": {
             "post": {
+                "description": "生成TOTP密钥和otpauth URI（前端据此生成二维码），需要再调用确认接口提交第一个验证码才会启用",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "两步验证"
+                ],
+                "summary": "绑定认证器",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.MFAEnrollmentResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "解密并获取秘密的明文数据（需要输入密码）",
+                ]
+            }
+        },
+        "/api/v1/auth/password": {
+            "put": {
+                "description": "已登录用户验证原密码后修改登录密码，新密码需要符合系统的密码策略且不能与最近用过的密码重复。\n修改后注销该用户其他设备上的登录会话，当前会话保持有效",
                 "consumes": [
                     "application/json"
                 ],
@@ -2059,24 +1711,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "秘密管理"
+                    "认证"
                 ],
-                "summary": "解密秘密",
+                "summary": "修改登录密码",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "秘密UUID",
-                        "name": "uuid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "解密请求",
+                        "description": "修改密码请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.DecryptSecretRequest"
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ChangePasswordRequest"
                         }
                     }
                 ],
@@ -2092,24 +1737,24 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.DecryptedSecret"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ChangePasswordResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/statistics/current": {
-            "get": {
+                },
                 "security": [
                     {
-                        "ApiKeyAuth": []
+                        "BearerAuth": []
                     }
-                ],
-                "description": "获取用户的实时统计数据（密钥数量、今日操作数等）。普通用户只能查询自己的统计，管理员可以查询指定用户的统计",
+                ]
+            }
+        },
+        "/api/v1/auth/refresh": {
+            "post": {
+                "description": "使用刷新令牌换取新的访问令牌和刷新令牌，旧的刷新令牌立即失效。\n已失效的刷新令牌再次使用说明令牌可能已泄露，会注销整个会话，需要重新登录",
                 "consumes": [
                     "application/json"
                 ],
@@ -2117,47 +1762,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "统计"
+                    "认证"
                 ],
-                "summary": "获取用户当前统计",
+                "summary": "刷新访问令牌",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "用户UUID（管理员可指定，普通用户自动使用当前用户）",
-                        "name": "user_uuid",
-                        "in": "query"
+                        "description": "刷新令牌",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RefreshTokenRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "查询成功",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CurrentStatistics"
-                        }
-                    },
-                    "401": {
-                        "description": "未授权",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器错误",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.LoginResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/api/v1/statistics/user": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "获取用户的历史统计数据，支持时间范围和统计类型过滤。普通用户只能查询自己的统计，管理员可以查询所有用户的统计",
+        "/api/v1/auth/register": {
+            "post": {
+                "description": "注册新用户账号,所有新用户默认为普通用户角色,只有管理员才能提权",
                 "consumes": [
                     "application/json"
                 ],
@@ -2165,74 +1808,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "统计"
+                    "认证"
                 ],
-                "summary": "获取用户统计数据",
+                "summary": "用户注册",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "用户UUID（管理员可指定，普通用户自动使用当前用户）",
-                        "name": "user_uuid",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "统计类型：daily/weekly/monthly",
-                        "name": "stat_type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "开始日期（格式：2024-01-01）",
-                        "name": "start_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "结束日期（格式：2024-01-31）",
-                        "name": "end_date",
-                        "in": "query"
+                        "description": "注册请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RegisterRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "查询成功",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.UserStatistics"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "参数错误",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "401": {
-                        "description": "未授权",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "服务器错误",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RegisterResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/api/v1/users": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "获取用户列表（需要管理员权限）。不传分页参数时全量导出（最多10000条）",
+        "/api/v1/auth/request-password-reset": {
+            "post": {
+                "description": "通过邮箱申请密码重置，系统将发送重置链接到邮箱。无需登录即可访问",
                 "consumes": [
                     "application/json"
                 ],
@@ -2240,46 +1854,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户管理"
+                    "认证"
                 ],
-                "summary": "获取用户列表",
+                "summary": "请求密码重置",
                 "parameters": [
                     {
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "页码（可选，不传则全量导出）",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 10000,
-                        "minimum": 1,
-                        "type": "integer",
-                        "description": "每页数量（可选，不传则全量导出）",
-                        "name": "page_size",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            1,
-                            2,
-                            3
-                        ],
-                        "type": "integer",
-                        "description": "用户状态",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "admin",
-                            "user",
-                            "readonly"
-                        ],
-                        "type": "string",
-                        "description": "用户角色",
-                        "name": "role",
-                        "in": "query"
+                        "description": "请求密码重置",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RequestPasswordResetRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -2294,7 +1880,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ListUsersResponse"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RequestPasswordResetResponse"
                                         }
                                     }
                                 }
@@ -2304,14 +1890,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/users/{uuid}": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "根据UUID获取用户详细信息（需要管理员权限）",
+        "/api/v1/auth/reset-password": {
+            "post": {
+                "description": "用户忘记密码时，可使用注册时获得的24个单词恢复助记词重置密码",
                 "consumes": [
                     "application/json"
                 ],
@@ -2319,48 +1900,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户管理"
+                    "认证"
                 ],
-                "summary": "获取用户信息",
+                "summary": "使用恢复密钥重置密码",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "用户UUID",
-                        "name": "uuid",
-                        "in": "path",
-                        "required": true
+                        "description": "重置密码请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ResetPasswordWithRecoveryRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUser"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/users/{uuid}/role": {
-            "put": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "更新用户角色（需要管理员权限）",
+                ]
+            }
+        },
+        "/api/v1/auth/reset-password-with-token": {
+            "post": {
+                "description": "使用邮件中的重置token设置新密码。无需登录即可访问。重置后该用户已签发的令牌和登录会话全部失效",
                 "consumes": [
                     "application/json"
                 ],
@@ -2368,24 +1939,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户管理"
+                    "认证"
                 ],
-                "summary": "更新用户角色",
+                "summary": "使用token重置密码",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "用户UUID",
-                        "name": "uuid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新角色请求",
+                        "description": "重置密码请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.UpdateUserRoleRequest"
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ResetPasswordRequest"
                         }
                     }
                 ],
@@ -2401,7 +1965,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUser"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ResetPasswordResponse"
                                         }
                                     }
                                 }
@@ -2411,42 +1975,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/users/{uuid}/status": {
-            "put": {
+        "/api/v1/auth/security-pin-status": {
+            "get": {
+                "description": "检查当前用户是否已设置安全密码，用于前端判断是否需要引导用户设置",
+                "tags": [
+                    "认证"
+                ],
+                "summary": "获取安全密码设置状态",
+                "responses": {
+                    "200": {
+                        "description": "返回 has_security_pin 字段",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器错误",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "更新用户状态（需要管理员权限）",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/api/v1/auth/sessions": {
+            "get": {
+                "description": "查询当前用户未过期的登录会话（设备名称、IP、User-Agent、创建和最后活跃时间），current标记发起请求的会话",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "用户管理"
-                ],
-                "summary": "更新用户状态",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "用户UUID",
-                        "name": "uuid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "更新状态请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.UpdateUserStatusRequest"
-                        }
-                    }
+                    "登录会话"
                 ],
+                "summary": "查询登录会话列表",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -2459,47 +2031,6389 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUser"
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.SessionListResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            }
-        },
-        "/health": {
-            "get": {
-                "description": "检查服务及其依赖（数据库、Redis、Casbin权限系统等）的运行状态。返回整体健康状态、各组件详细状态、系统资源使用情况和服务运行时间。此接口无需认证，用于监控系统健康状态。",
-                "consumes": [
-                    "application/json"
-                ],
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "注销当前用户除当前会话以外的全部会话，返回注销的数量",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "系统"
+                    "登录会话"
                 ],
-                "summary": "健康检查",
+                "summary": "注销其他登录会话",
                 "responses": {
                     "200": {
-                        "description": "健康检查响应。code=0表示服务健康，code=70001表示服务降级，code=70002表示服务不可用",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_handlers.HealthResponse"
-                        }
-                    }
-                }
-            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "additionalProperties": {
+                                                "type": "integer"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/auth/sessions/{uuid}": {
+            "delete": {
+                "description": "注销当前用户的一个会话，该会话的访问令牌和刷新令牌立即失效；注销当前会话等同于登出",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "登录会话"
+                ],
+                "summary": "注销登录会话",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "会话UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/auth/step-up/begin": {
+            "post": {
+                "description": "返回传给 navigator.credentials.get() 的参数，只允许使用当前用户已注册的通行密钥",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "通行密钥"
+                ],
+                "summary": "开始二次验证",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.WebAuthnAssertionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/auth/step-up/finish": {
+            "post": {
+                "description": "提交 navigator.credentials.get() 返回的凭证，校验通过后当前令牌在10分钟内视为已完成二次验证，\n可以访问要求二次验证的秘密接口（系统配置 webauthn_step_up_roles 中的角色）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "通行密钥"
+                ],
+                "summary": "完成二次验证",
+                "parameters": [
+                    {
+                        "description": "认证器验证响应",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.WebAuthnCredentialRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.StepUpResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/auth/tokens": {
+            "get": {
+                "description": "查询当前用户的个人访问令牌（包括已过期的），返回名称、令牌前缀、权限范围、IP白名单、过期时间和最后使用时间及IP，不返回令牌明文",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "个人访问令牌"
+                ],
+                "summary": "查询个人访问令牌列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.PersonalAccessTokenListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "为当前用户创建个人访问令牌，供CI任务和脚本代替用户名密码调用API。令牌明文只在本次响应中返回，请妥善保存。\n权限范围格式为 资源:操作（如 secret:read），只能是当前角色已有权限的子集；allowed_ips 为空时不限制来源IP。\nsecret_uuids 限定令牌只能访问其中列出的秘密（秘密列表和模板渲染只返回或引用这些秘密，备份、导入、创建等接口不可用），为空时可以访问全部秘密。\n令牌只能访问带权限检查的接口，访问的是创建者自己的保险库；创建者修改角色、被禁用或重置密码后令牌失效。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "个人访问令牌"
+                ],
+                "summary": "创建个人访问令牌",
+                "parameters": [
+                    {
+                        "description": "令牌名称、权限范围、有效期和IP白名单",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreatePersonalAccessTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreatePersonalAccessTokenResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/auth/tokens/{uuid}": {
+            "delete": {
+                "description": "撤销当前用户的一个个人访问令牌，令牌立即失效",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "个人访问令牌"
+                ],
+                "summary": "撤销个人访问令牌",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "令牌UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/auth/verify-reset-token": {
+            "get": {
+                "description": "验证密码重置token是否有效（未过期且未使用）。无需登录即可访问",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "认证"
+                ],
+                "summary": "验证密码重置token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "重置token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.VerifyResetTokenResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/webauthn/credentials": {
+            "get": {
+                "description": "查询当前用户已注册的通行密钥，以及角色访问秘密前是否必须二次验证",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "通行密钥"
+                ],
+                "summary": "查询通行密钥列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.WebAuthnCredentialListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/auth/webauthn/credentials/{uuid}": {
+            "put": {
+                "description": "修改通行密钥的名称，同一用户的通行密钥名称不能重复",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "通行密钥"
+                ],
+                "summary": "重命名通行密钥",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "通行密钥UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新名称",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.WebAuthnCredentialNameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.WebAuthnCredential"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "删除当前用户的通行密钥，需要当前会话已完成二次验证",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "通行密钥"
+                ],
+                "summary": "删除通行密钥",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "通行密钥UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/auth/webauthn/register/begin": {
+            "post": {
+                "description": "返回传给 navigator.credentials.create() 的参数，需要在5分钟内调用完成注册接口。\n已有通行密钥时需要先完成二次验证；默认不要求认证器证明（attestation为none）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "通行密钥"
+                ],
+                "summary": "开始注册通行密钥",
+                "parameters": [
+                    {
+                        "description": "通行密钥名称",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.WebAuthnCredentialNameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.WebAuthnRegistrationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/auth/webauthn/register/finish": {
+            "post": {
+                "description": "提交 navigator.credentials.create() 返回的凭证，校验通过后保存通行密钥",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "通行密钥"
+                ],
+                "summary": "完成注册通行密钥",
+                "parameters": [
+                    {
+                        "description": "认证器注册响应",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.WebAuthnCredentialRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.WebAuthnCredential"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/configs": {
+            "get": {
+                "description": "获取所有系统配置项（管理员权限）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统配置"
+                ],
+                "summary": "获取系统配置列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ListConfigsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/configs/batch": {
+            "put": {
+                "description": "批量更新多个配置项（管理员权限）。所有配置在同一事务中更新，全部成功或全部失败",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统配置"
+                ],
+                "summary": "批量更新系统配置",
+                "parameters": [
+                    {
+                        "description": "批量更新配置请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.BatchUpdateConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/configs/casbin/reload": {
+            "post": {
+                "description": "从数据库重新加载Casbin权限策略到内存（管理员权限）。用于在运行时修改casbin_rule表后使策略生效，无需重启服务",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统管理"
+                ],
+                "summary": "重新加载权限策略",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "additionalProperties": true
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/configs/reload": {
+            "post": {
+                "description": "从数据库重新加载所有配置到内存（管理员权限）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统配置"
+                ],
+                "summary": "重新加载配置",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/configs/{key}": {
+            "get": {
+                "description": "根据配置键获取配置详情（管理员权限）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统配置"
+                ],
+                "summary": "获取单个系统配置",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "配置键",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ConfigItem"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "更新指定配置项的值（管理员权限）。配置更新后会立即生效并触发相关观察者回调",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统配置"
+                ],
+                "summary": "更新系统配置",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "配置键",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新配置请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.UpdateConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/email/send-code": {
+            "post": {
+                "description": "发送邮箱验证码用于注册、登录、重置密码等操作",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "邮件"
+                ],
+                "summary": "发送邮箱验证码",
+                "parameters": [
+                    {
+                        "description": "发送验证码请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_handlers.SendCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/internal_api_handlers.SendCodeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/email/verify-code": {
+            "post": {
+                "description": "验证邮箱验证码是否正确（验证成功后验证码自动失效）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "邮件"
+                ],
+                "summary": "验证邮箱验证码",
+                "parameters": [
+                    {
+                        "description": "验证验证码请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_handlers.VerifyCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/internal_api_handlers.VerifyCodeResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/emergency-access/granted": {
+            "get": {
+                "description": "查询把当前用户指定为可信联系人的授权及其状态",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "紧急访问"
+                ],
+                "summary": "查询授予我的紧急访问",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccess"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/emergency-access/granted/{uuid}/request": {
+            "post": {
+                "description": "可信联系人发起申请后进入等待期，授权人会收到邮件通知，等待期内可以拒绝",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "紧急访问"
+                ],
+                "summary": "发起紧急访问申请",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "授权UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccess"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/emergency-access/granted/{uuid}/secrets": {
+            "get": {
+                "description": "紧急访问生效后，可信联系人查询授权人的秘密列表（不包含加密数据）",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "紧急访问"
+                ],
+                "summary": "查询授权人的秘密列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "授权UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "api_key",
+                            "db_credential",
+                            "certificate",
+                            "ssh_key",
+                            "token",
+                            "password",
+                            "other"
+                        ],
+                        "type": "string",
+                        "description": "秘密类型",
+                        "name": "secret_type",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 10000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ListUserSecretsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/emergency-access/granted/{uuid}/secrets/{secret_uuid}/decrypt": {
+            "post": {
+                "description": "紧急访问生效后，可信联系人用自己的安全密码解开授权人的DEK并解密秘密，每次解密都记录审计日志",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "紧急访问"
+                ],
+                "summary": "解密授权人的秘密",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "授权UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "secret_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "联系人自己的安全密码",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.EmergencyDecryptRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.EmergencyDecryptResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/emergency-access/granted/{uuid}/takeover": {
+            "post": {
+                "description": "接管类授权生效后，可信联系人重置授权人的登录密码和安全密码，授权人已登录的会话立即失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "紧急访问"
+                ],
+                "summary": "紧急接管授权人账户",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "授权UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "接管请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.TakeoverRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccess"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/emergency-access/keypair": {
+            "get": {
+                "description": "查询当前用户是否已生成紧急访问密钥对及公钥指纹；生成密钥对后才能被指定为可信联系人",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "紧急访问"
+                ],
+                "summary": "查询紧急访问密钥对",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.KeyPairStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "为已有账户生成紧急访问密钥对（私钥由DEK加密保存），已存在时直接返回",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "紧急访问"
+                ],
+                "summary": "生成紧急访问密钥对",
+                "parameters": [
+                    {
+                        "description": "安全密码",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.InitKeyPairRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.KeyPairStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/emergency-access/trusted": {
+            "get": {
+                "description": "查询当前用户指定的可信联系人及其申请状态",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "紧急访问"
+                ],
+                "summary": "查询我的可信联系人",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.EmergencyAccessInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "指定可信联系人并用其公钥封装自己的DEK（需要安全密码）。联系人发起申请后经过等待期即可查看秘密（view）或接管账户（takeover）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "紧急访问"
+                ],
+                "summary": "指定可信联系人",
+                "parameters": [
+                    {
+                        "description": "可信联系人",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.DesignateContactRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.EmergencyAccessInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/emergency-access/trusted/{uuid}": {
+            "delete": {
+                "description": "撤销后封装给联系人的DEK立即删除",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "紧急访问"
+                ],
+                "summary": "撤销可信联系人",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "授权UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/emergency-access/trusted/{uuid}/approve": {
+            "post": {
+                "description": "授权人提前结束等待期，联系人立即获得访问权限",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "紧急访问"
+                ],
+                "summary": "同意紧急访问申请",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "授权UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccess"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/emergency-access/trusted/{uuid}/reject": {
+            "post": {
+                "description": "等待期内拒绝申请，或收回联系人已获得的访问权限；授权本身保留",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "紧急访问"
+                ],
+                "summary": "拒绝紧急访问申请",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "授权UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccess"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/encryption/keys": {
+            "post": {
+                "description": "为当前用户创建加密密钥（首次使用加密功能时调用）\n警告：返回的恢复密钥仅显示一次，请务必妥善保管",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "创建用户加密密钥",
+                "parameters": [
+                    {
+                        "description": "创建加密密钥请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateUserEncryptionKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateUserEncryptionKeyResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/keys/create": {
+            "post": {
+                "description": "在用户注册或首次使用加密功能时创建加密密钥，返回24个单词的恢复助记词（仅显示一次）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "密钥管理"
+                ],
+                "summary": "创建用户加密密钥",
+                "parameters": [
+                    {
+                        "description": "创建密钥请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateUserEncryptionKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateUserEncryptionKeyResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/keys/rotate": {
+            "post": {
+                "description": "生成新的数据加密密钥(DEK)并在后台渐进式迁移所有加密数据。注意：每30天最多轮换一次",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "密钥管理"
+                ],
+                "summary": "手动触发密钥轮换",
+                "parameters": [
+                    {
+                        "description": "密钥轮换请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RotateDEKRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RotateDEKResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/keys/rotation-status": {
+            "get": {
+                "description": "获取当前用户的密钥轮换状态和数据迁移进度",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "密钥管理"
+                ],
+                "summary": "查询密钥轮换进度",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.MigrationTask"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/keys/verify-recovery": {
+            "post": {
+                "description": "验证用户输入的恢复助记词是否正确，用于在实际重置密码前进行确认",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "密钥管理"
+                ],
+                "summary": "验证恢复密钥有效性",
+                "parameters": [
+                    {
+                        "description": "验证恢复密钥请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.VerifyRecoveryKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.VerifyRecoveryKeyResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/machine/secrets": {
+            "get": {
+                "description": "使用服务账户令牌查询被授权的秘密（不包含加密数据）",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务账户"
+                ],
+                "summary": "服务账户查询可以访问的秘密",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.GrantedSecretListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/machine/secrets/{uuid}": {
+            "get": {
+                "description": "使用服务账户令牌解密被授权的秘密，不需要安全密码。保险库密钥轮换期间返回冲突错误，稍后重试即可。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务账户"
+                ],
+                "summary": "服务账户读取秘密",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.DecryptedSecret"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/profile": {
+            "get": {
+                "description": "获取当前登录用户的档案信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户档案"
+                ],
+                "summary": "获取用户档案信息",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserProfile"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "更新当前用户的档案信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户档案"
+                ],
+                "summary": "更新用户档案",
+                "parameters": [
+                    {
+                        "description": "用户档案信息",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserProfile"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "为当前用户创建档案信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户档案"
+                ],
+                "summary": "创建用户档案",
+                "parameters": [
+                    {
+                        "description": "用户档案信息",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserProfile"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "删除当前用户的档案信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户档案"
+                ],
+                "summary": "删除用户档案",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "创建或更新当前用户的档案信息（如果不存在则创建，存在则更新）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户档案"
+                ],
+                "summary": "创建或更新用户档案",
+                "parameters": [
+                    {
+                        "description": "用户档案信息",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserProfile"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets": {
+            "get": {
+                "description": "获取当前用户的秘密列表（不包含加密数据）。不传分页参数时全量导出（最多10000条）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "获取秘密列表",
+                "parameters": [
+                    {
+                        "enum": [
+                            "api_key",
+                            "db_credential",
+                            "certificate",
+                            "ssh_key",
+                            "token",
+                            "password",
+                            "other"
+                        ],
+                        "type": "string",
+                        "description": "秘密类型",
+                        "name": "secret_type",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "页码（可选，不传则全量导出）",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 10000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "每页数量（可选，不传则全量导出）",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ListUserSecretsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "加密并存储敏感数据（需要输入密码）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "创建加密秘密",
+                "parameters": [
+                    {
+                        "description": "创建秘密请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.EncryptAndStoreSecretRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeEncryptedSecret"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/backup": {
+            "post": {
+                "description": "解密当前用户的全部秘密，并使用备份口令（Argon2id + AES-256-GCM）重新加密为可移植的备份文件\n返回的 backup 字段原样保存为JSON文件即可，格式说明见 docs/backup_format.md\n与解密秘密使用相同的访问控制，未持有检出租约的秘密和未获批准的高敏感秘密不导出，在 skipped 中列出",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "导出加密备份",
+                "parameters": [
+                    {
+                        "description": "导出备份请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ExportBackupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ExportBackupResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/import": {
+            "post": {
+                "description": "从 .env、CSV（需列映射）、Bitwarden未加密JSON或KeePass XML导入秘密\n整批只验证一次安全密码；dry_run=true时仅返回预览结果，不写入数据，可不传安全密码\n单条记录的错误不会中断整批导入，结果中逐条返回处理状态",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "批量导入秘密",
+                "parameters": [
+                    {
+                        "description": "批量导入请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ImportSecretsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ImportSecretsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/render": {
+            "post": {
+                "description": "使用Go text/template语法渲染配置文件，通过 {{ secret \"文件夹/名称\" }} 或 {{ secret \"文件夹/名称\" \"JSON字段\" }} 引用秘密。所有引用在渲染前解析，任一引用不存在则拒绝渲染；每个被引用的秘密都以同一请求ID记录访问审计。需要检出或未获审批的高敏感秘密不能引用",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "渲染配置模板",
+                "parameters": [
+                    {
+                        "description": "模板内容和安全密码",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RenderTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RenderTemplateResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/restore": {
+            "post": {
+                "description": "使用备份口令解密备份文件，并用当前用户的密钥重新加密后写入，可恢复到同一实例或其他实例\n同名秘密按 conflict_strategy 处理：skip（默认，跳过）、overwrite（覆盖）、rename（重命名后导入）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "从加密备份恢复",
+                "parameters": [
+                    {
+                        "description": "恢复备份请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RestoreBackupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RestoreBackupResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/trash": {
+            "get": {
+                "description": "获取当前用户已删除但尚未彻底清除的秘密，按删除时间倒序\n超过保留天数（系统配置 secret_trash_retention_days）的秘密会被定时任务彻底清除",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "获取回收站列表",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ListTrashResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/trash/purge": {
+            "post": {
+                "description": "彻底删除回收站中的秘密，删除后不可恢复（需要输入安全密码）\nsecret_uuids 为空时清空整个回收站",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "立即清除回收站",
+                "parameters": [
+                    {
+                        "description": "清除请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.PurgeTrashRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.PurgeTrashResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/trash/{uuid}/restore": {
+            "post": {
+                "description": "恢复回收站中的指定秘密",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "从回收站恢复秘密",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeEncryptedSecret"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/{uuid}": {
+            "delete": {
+                "description": "删除指定的秘密（软删除），删除后进入回收站，可在保留期内恢复",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "删除秘密",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/{uuid}/access-requests": {
+            "post": {
+                "description": "申请解密高敏感秘密，系统会通过邮件通知审批人；批准后在时间窗口内可以解密",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "访问审批"
+                ],
+                "summary": "提交访问申请",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "访问申请",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateAccessRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateAccessRequestResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/{uuid}/approval-policy": {
+            "put": {
+                "description": "标记为高敏感秘密后，解密前必须提交访问申请并由其他审批人批准\n关闭审批同样需要持有已批准且仍在有效期内的访问申请",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "访问审批"
+                ],
+                "summary": "设置审批策略",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "审批策略",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.SetApprovalPolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeEncryptedSecret"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/{uuid}/attachments": {
+            "get": {
+                "description": "获取秘密的附件列表及当前用户的附件容量使用情况",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "获取附件列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ListAttachmentsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "为秘密上传文件附件（如keystore、kubeconfig），文件分块加密后保存（需要输入安全密码）\n表单字段 security_pin 必须位于 file 之前，服务端边接收边加密，不缓存完整文件\n单个附件大小和每个用户的总容量由系统配置 attachment_max_file_size_mb、attachment_user_quota_mb 限制",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "上传附件",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "安全密码",
+                        "name": "security_pin",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "附件文件",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretAttachment"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/{uuid}/attachments/{attachment_uuid}": {
+            "delete": {
+                "description": "彻底删除秘密的指定附件",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "删除附件",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "附件UUID",
+                        "name": "attachment_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/{uuid}/attachments/{attachment_uuid}/download": {
+            "post": {
+                "description": "验证安全密码后流式解密并下载附件（需要输入安全密码）\n成功时直接返回文件内容；内容在传输过程中逐块校验，发现篡改会中断连接",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "下载附件",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "附件UUID",
+                        "name": "attachment_uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "下载请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.DownloadAttachmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "附件内容",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/{uuid}/checkin": {
+            "post": {
+                "description": "释放检出租约，秘密被标记为待轮换\n秘密关联了密码生成规则且提供了安全密码时，检入后自动轮换为新的随机密码",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "检入秘密",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "检入请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CheckinRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CheckinResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/{uuid}/checkout": {
+            "get": {
+                "description": "查询秘密当前的持有者、待轮换标记和最近的检出记录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "查询检出状态",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CheckoutStatusResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "获得秘密在租约期内的独占访问并返回明文（需要输入安全密码）\n秘密已被检出时返回错误码30004，错误信息中包含当前持有者和到期时间",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "检出秘密",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "检出请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CheckoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CheckoutResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/{uuid}/checkout-policy": {
+            "put": {
+                "description": "启用后秘密不能直接解密，必须检出后独占使用；可同时设置密码生成规则，用于检入后自动轮换",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "设置检出策略",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "检出策略",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.SetCheckoutPolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeEncryptedSecret"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/{uuid}/decrypt": {
+            "post": {
+                "description": "解密并获取秘密的明文数据（需要输入密码）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "解密秘密",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "解密请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.DecryptSecretRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.DecryptedSecret"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/secrets/{uuid}/rotate": {
+            "post": {
+                "description": "更新秘密的明文并清除待轮换标记（需要输入安全密码）。plain_data为空时按密码生成规则生成随机密码\n检出中的秘密不能轮换",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "秘密管理"
+                ],
+                "summary": "轮换秘密",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "秘密UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "轮换请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RotateSecretRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeEncryptedSecret"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/service-accounts": {
+            "get": {
+                "description": "查询当前用户的服务账户及每个服务账户可以访问的秘密",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务账户"
+                ],
+                "summary": "查询服务账户列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ServiceAccountListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "在当前用户的保险库下创建服务账户，供应用程序代替用户访问秘密。服务账户不持有保险库密钥，只能解密授权时为它封装的秘密。\n返回的 role_id 用于登录，还需要再生成 secret_id；服务账户只能读取通过 PUT /api/v1/service-accounts/{uuid}/secrets 授权的秘密。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务账户"
+                ],
+                "summary": "创建服务账户",
+                "parameters": [
+                    {
+                        "description": "名称和描述",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateServiceAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ServiceAccountInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/service-accounts/{uuid}": {
+            "get": {
+                "description": "查询当前用户的一个服务账户及其可以访问的秘密",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务账户"
+                ],
+                "summary": "查询服务账户详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务账户UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ServiceAccountInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "删除当前用户的一个服务账户，同时删除它的 secret_id 和秘密授权，已签发的服务账户令牌立即失效",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务账户"
+                ],
+                "summary": "删除服务账户",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务账户UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/service-accounts/{uuid}/secret-ids": {
+            "get": {
+                "description": "查询服务账户的 secret_id（包括已过期和次数已用完的），返回CIDR绑定、登录次数、过期时间和最后使用时间及IP，不返回明文",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务账户"
+                ],
+                "summary": "查询secret_id列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务账户UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.SecretIDListResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "为服务账户生成新的 secret_id，明文只在本次响应中返回，请妥善保存。\nbound_cidrs 为空时不限制来源IP；max_uses 为 0 时不限制登录次数；有效期不能超过系统配置 service_account_secret_id_max_days。\n轮换时先生成新的 secret_id，应用切换后再撤销旧的。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务账户"
+                ],
+                "summary": "生成secret_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务账户UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "CIDR绑定、登录次数和有效期",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateSecretIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateSecretIDResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/service-accounts/{uuid}/secret-ids/{secret_id_uuid}": {
+            "delete": {
+                "description": "撤销服务账户的一个 secret_id，用它登录获得的服务账户令牌立即失效",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务账户"
+                ],
+                "summary": "撤销secret_id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务账户UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "secret_id的UUID",
+                        "name": "secret_id_uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/service-accounts/{uuid}/secrets": {
+            "put": {
+                "description": "用给定的列表替换服务账户可以访问的秘密，传空列表撤销全部授权。秘密必须属于当前用户，需要检出或审批的秘密不能授权给服务账户。\n新增授权时需要安全密码：服务端解密新增的秘密并用服务账户的公钥封装一份明文，服务账户无法解密未授权的秘密。只撤销授权时不需要安全密码。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "服务账户"
+                ],
+                "summary": "设置服务账户可以访问的秘密",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "服务账户UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "秘密UUID列表和安全密码",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.SetGrantsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ServiceAccountInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/shares": {
+            "post": {
+                "description": "将已保存的秘密（需要安全密码）或一段临时文本生成阅后即焚链接\n内容使用随机密钥加密后暂存，密钥只包含在返回的链接#之后，服务端不保存\n查看次数用完或过期后内容被销毁；每次查看都会记录到创建者的审计日志",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "分享"
+                ],
+                "summary": "创建一次性分享链接",
+                "parameters": [
+                    {
+                        "description": "创建分享请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateShareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CreateShareResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/shares/{uuid}": {
+            "get": {
+                "description": "获取分享是否存在、是否需要口令、剩余次数等信息，不返回内容也不消耗查看次数。无需登录即可访问",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "分享"
+                ],
+                "summary": "获取分享信息",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "分享UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ShareInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "在分享被查看前提前销毁，只能撤销自己创建的分享",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "分享"
+                ],
+                "summary": "撤销分享链接",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "分享UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/shares/{uuid}/open": {
+            "post": {
+                "description": "消耗一次查看次数并返回密文，客户端使用链接#之后的密钥以AES-256-GCM解密。无需登录即可访问\n口令连续错误5次后分享被销毁",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "分享"
+                ],
+                "summary": "查看分享内容",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "分享UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "查看口令",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.OpenShareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.OpenShareResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/statistics/current": {
+            "get": {
+                "description": "获取用户的实时统计数据（密钥数量、今日操作数等）。普通用户只能查询自己的统计，管理员可以查询指定用户的统计",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "统计"
+                ],
+                "summary": "获取用户当前统计",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户UUID（管理员可指定，普通用户自动使用当前用户）",
+                        "name": "user_uuid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.CurrentStatistics"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器错误",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/statistics/user": {
+            "get": {
+                "description": "获取用户的历史统计数据，支持时间范围和统计类型过滤。普通用户只能查询自己的统计，管理员可以查询所有用户的统计",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "统计"
+                ],
+                "summary": "获取用户统计数据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户UUID（管理员可指定，普通用户自动使用当前用户）",
+                        "name": "user_uuid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "统计类型：daily/weekly/monthly",
+                        "name": "stat_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期（格式：2024-01-01）",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期（格式：2024-01-31）",
+                        "name": "end_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "查询成功",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.UserStatistics"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "参数错误",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "未授权",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器错误",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users": {
+            "get": {
+                "description": "获取用户列表（需要管理员权限）。不传分页参数时全量导出（最多10000条）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "获取用户列表",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "页码（可选，不传则全量导出）",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 10000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "每页数量（可选，不传则全量导出）",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            1,
+                            2,
+                            3
+                        ],
+                        "type": "integer",
+                        "description": "用户状态",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "admin",
+                            "user",
+                            "readonly"
+                        ],
+                        "type": "string",
+                        "description": "用户角色",
+                        "name": "role",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ListUsersResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users/{uuid}": {
+            "get": {
+                "description": "根据UUID获取用户详细信息（需要管理员权限）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "获取用户信息",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUser"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users/{uuid}/mfa/reset": {
+            "post": {
+                "description": "用户丢失认证器和备用码时由管理员重置，删除其认证器绑定和备用码（需要user:write权限）",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "重置用户的两步验证",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users/{uuid}/role": {
+            "put": {
+                "description": "更新用户角色（需要管理员权限）。角色变化后该用户已签发的令牌和登录会话全部失效，需要重新登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "更新用户角色",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新角色请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.UpdateUserRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUser"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users/{uuid}/status": {
+            "put": {
+                "description": "更新用户状态（需要管理员权限）。将登录失败自动锁定（status=3）的账户改为活跃（status=1）即解锁。状态变化后该用户已签发的令牌和登录会话全部失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "更新用户状态",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新状态请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.UpdateUserStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUser"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/users/{uuid}/webauthn/reset": {
+            "post": {
+                "description": "用户丢失所有认证器时由管理员删除其全部通行密钥（需要user:write权限）",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户管理"
+                ],
+                "summary": "重置用户的通行密钥",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "用户UUID",
+                        "name": "uuid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/vault/approvers": {
+            "get": {
+                "description": "查询当前用户保险库指定的审批人，未指定时使用默认审批角色",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "访问审批"
+                ],
+                "summary": "查询保险库审批人",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.VaultApproversResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "替换当前用户保险库的审批人，传空列表时恢复使用默认审批角色",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "访问审批"
+                ],
+                "summary": "设置保险库审批人",
+                "parameters": [
+                    {
+                        "description": "审批人",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.SetVaultApproversRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.VaultApproversResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/vault/health": {
+            "get": {
+                "description": "列出重复使用的密码、弱密码、长期未变更和从未访问的秘密。重复检测基于写入时计算的密码指纹，服务端不解密秘密",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "保险库健康"
+                ],
+                "summary": "查询保险库健康报告",
+                "parameters": [
+                    {
+                        "maximum": 3650,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "超过多少天未变更视为过旧，默认取系统配置",
+                        "name": "stale_days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.VaultHealthReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/v1/vault/health/rescan": {
+            "post": {
+                "description": "用安全密码解密DEK，重新计算所有密码类秘密的指纹和强度评分（用于功能上线前创建的秘密）。明文不会返回，也不计入访问统计",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "保险库健康"
+                ],
+                "summary": "重新扫描密码",
+                "parameters": [
+                    {
+                        "description": "安全密码",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RescanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RescanResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "检查服务及其依赖（数据库、Redis、Casbin权限系统等）的运行状态。返回整体健康状态、各组件详细状态、系统资源使用情况和服务运行时间。此接口无需认证，用于监控系统健康状态。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统"
+                ],
+                "summary": "健康检查",
+                "responses": {
+                    "200": {
+                        "description": "健康检查响应。code=0表示服务健康，code=70001表示服务降级，code=70002表示服务不可用",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_handlers.HealthResponse"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
+        "github_com_cuihe500_vaulthub_internal_database_models.AccessRequestStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "denied",
+                "expired",
+                "cancelled"
+            ],
+            "x-enum-comments": {
+                "AccessRequestApproved": "已批准",
+                "AccessRequestCancelled": "申请人撤回",
+                "AccessRequestDenied": "已拒绝",
+                "AccessRequestExpired": "超时未审批",
+                "AccessRequestPending": "待审批"
+            },
+            "x-enum-descriptions": [
+                "待审批",
+                "已批准",
+                "已拒绝",
+                "超时未审批",
+                "申请人撤回"
+            ],
+            "x-enum-varnames": [
+                "AccessRequestPending",
+                "AccessRequestApproved",
+                "AccessRequestDenied",
+                "AccessRequestExpired",
+                "AccessRequestCancelled"
+            ]
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.CheckoutStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "checked_in",
+                "expired",
+                "force_released"
+            ],
+            "x-enum-comments": {
+                "CheckoutActive": "检出中",
+                "CheckoutCheckedIn": "持有者已检入",
+                "CheckoutExpired": "超时自动释放",
+                "CheckoutForceReleased": "管理员强制释放"
+            },
+            "x-enum-descriptions": [
+                "检出中",
+                "持有者已检入",
+                "超时自动释放",
+                "管理员强制释放"
+            ],
+            "x-enum-varnames": [
+                "CheckoutActive",
+                "CheckoutCheckedIn",
+                "CheckoutExpired",
+                "CheckoutForceReleased"
+            ]
+        },
         "github_com_cuihe500_vaulthub_internal_database_models.DecryptedSecret": {
             "type": "object",
             "properties": {
                 "access_count": {
                     "type": "integer"
                 },
+                "access_request_uuid": {
+                    "description": "高敏感秘密解密时依据的访问申请",
+                    "type": "string"
+                },
+                "approval_required": {
+                    "type": "boolean"
+                },
+                "checkout_required": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dek_version": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_accessed_at": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretMetadata"
+                },
+                "plain_data": {
+                    "description": "解密后的明文数据",
+                    "type": "string"
+                },
+                "rotation_required": {
+                    "type": "boolean"
+                },
+                "secret_name": {
+                    "type": "string"
+                },
+                "secret_type": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretType"
+                },
+                "secret_uuid": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_uuid": {
+                    "type": "string"
+                },
+                "value_changed_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccess": {
+            "type": "object",
+            "properties": {
+                "access_type": {
+                    "description": "授权内容",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccessType"
+                        }
+                    ]
+                },
+                "available_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dek_version": {
+                    "type": "integer"
+                },
+                "grant_uuid": {
+                    "type": "string"
+                },
+                "granted_at": {
+                    "type": "string"
+                },
+                "grantee_username": {
+                    "type": "string"
+                },
+                "grantee_uuid": {
+                    "type": "string"
+                },
+                "grantor_username": {
+                    "type": "string"
+                },
+                "grantor_uuid": {
+                    "description": "授权双方",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_rejected_at": {
+                    "type": "string"
+                },
+                "requested_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "申请状态",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccessStatus"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "wait_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccessStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "requested",
+                "granted"
+            ],
+            "x-enum-comments": {
+                "EmergencyAccessActive": "已指定，联系人未发起申请",
+                "EmergencyAccessGranted": "等待期结束或授权人提前同意，联系人已获得访问权限",
+                "EmergencyAccessRequested": "联系人已发起申请，处于等待期"
+            },
+            "x-enum-descriptions": [
+                "已指定，联系人未发起申请",
+                "联系人已发起申请，处于等待期",
+                "等待期结束或授权人提前同意，联系人已获得访问权限"
+            ],
+            "x-enum-varnames": [
+                "EmergencyAccessActive",
+                "EmergencyAccessRequested",
+                "EmergencyAccessGranted"
+            ]
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccessType": {
+            "type": "string",
+            "enum": [
+                "view",
+                "takeover"
+            ],
+            "x-enum-comments": {
+                "EmergencyAccessTakeover": "重置授权人的登录密码和安全密码，接管账户",
+                "EmergencyAccessView": "查看授权人的秘密"
+            },
+            "x-enum-descriptions": [
+                "查看授权人的秘密",
+                "重置授权人的登录密码和安全密码，接管账户"
+            ],
+            "x-enum-varnames": [
+                "EmergencyAccessView",
+                "EmergencyAccessTakeover"
+            ]
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.PasswordGenerator": {
+            "type": "object",
+            "properties": {
+                "length": {
+                    "description": "密码长度，默认24",
+                    "type": "integer",
+                    "maximum": 128,
+                    "minimum": 12
+                },
+                "symbols": {
+                    "description": "是否包含特殊字符",
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.PasswordStrength": {
+            "type": "object",
+            "properties": {
+                "breached": {
+                    "description": "是否出现在泄露密码数据集中",
+                    "type": "boolean"
+                },
+                "entropy": {
+                    "description": "估算熵值（bit）",
+                    "type": "number"
+                },
+                "score": {
+                    "description": "强度等级0-4，越高越强",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.PersonalAccessToken": {
+            "type": "object",
+            "properties": {
+                "allowed_ips": {
+                    "description": "允许使用的IP或CIDR，为空时不限制",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "last_used_ip": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "权限范围，格式为 资源:操作，如 secret:read",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret_uuids": {
+                    "description": "允许访问的秘密，为空时可以访问创建者的全部秘密",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token_prefix": {
+                    "description": "令牌前几位，用于在列表中辨认",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_uuid": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.SafeEncryptedSecret": {
+            "type": "object",
+            "properties": {
+                "access_count": {
+                    "type": "integer"
+                },
+                "approval_required": {
+                    "type": "boolean"
+                },
+                "checkout_required": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dek_version": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_accessed_at": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretMetadata"
+                },
+                "rotation_required": {
+                    "type": "boolean"
+                },
+                "secret_name": {
+                    "type": "string"
+                },
+                "secret_type": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretType"
+                },
+                "secret_uuid": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_uuid": {
+                    "type": "string"
+                },
+                "value_changed_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.SafeUser": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_login_at": {
+                    "type": "string"
+                },
+                "locked_until": {
+                    "type": "string"
+                },
+                "password_changed_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.UserStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.SafeUserEncryptionKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "dek_algorithm": {
+                    "type": "string"
+                },
+                "dek_version": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kek_algorithm": {
+                    "type": "string"
+                },
+                "last_rotation_at": {
+                    "type": "string"
+                },
+                "rotation_started_at": {
+                    "type": "string"
+                },
+                "rotation_status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.SafeUserProfile": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "nickname": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.SecretAccessRequest": {
+            "type": "object",
+            "properties": {
+                "access_until": {
+                    "type": "string"
+                },
+                "approver_username": {
+                    "type": "string"
+                },
+                "approver_uuid": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decision_comment": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "owner_uuid": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "request_uuid": {
+                    "type": "string"
+                },
+                "requester_username": {
+                    "type": "string"
+                },
+                "requester_uuid": {
+                    "description": "申请人信息",
+                    "type": "string"
+                },
+                "secret_uuid": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "审批信息",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.AccessRequestStatus"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "window_minutes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.SecretAttachment": {
+            "type": "object",
+            "properties": {
+                "attachment_uuid": {
+                    "type": "string"
+                },
+                "checksum_sha256": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dek_version": {
+                    "type": "integer"
+                },
+                "file_name": {
+                    "description": "文件信息",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "secret_uuid": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.SecretCheckout": {
+            "type": "object",
+            "properties": {
+                "checked_out_at": {
+                    "type": "string"
+                },
+                "checkout_uuid": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "holder_ip": {
+                    "type": "string"
+                },
+                "holder_user_agent": {
+                    "type": "string"
+                },
+                "holder_username": {
+                    "type": "string"
+                },
+                "holder_uuid": {
+                    "description": "持有者信息",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "owner_uuid": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "released_at": {
+                    "type": "string"
+                },
+                "released_by": {
+                    "description": "释放者用户名（超时释放为system）",
+                    "type": "string"
+                },
+                "secret_uuid": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "租约信息",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.CheckoutStatus"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.SecretMetadata": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "过期时间",
+                    "type": "string"
+                },
+                "extra": {
+                    "description": "额外信息",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "folder": {
+                    "description": "所属文件夹（多级用\"/\"分隔）",
+                    "type": "string"
+                },
+                "generator": {
+                    "description": "密码生成规则（用于检入后自动轮换）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.PasswordGenerator"
+                        }
+                    ]
+                },
+                "strength": {
+                    "description": "密码强度（仅密码类秘密）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.PasswordStrength"
+                        }
+                    ]
+                },
+                "tags": {
+                    "description": "标签",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.SecretType": {
+            "type": "string",
+            "enum": [
+                "api_key",
+                "db_credential",
+                "certificate",
+                "ssh_key",
+                "token",
+                "password",
+                "other"
+            ],
+            "x-enum-comments": {
+                "SecretTypeAPIKey": "API密钥",
+                "SecretTypeCertificate": "证书",
+                "SecretTypeDBCredential": "数据库凭证",
+                "SecretTypeOther": "其他",
+                "SecretTypePassword": "密码",
+                "SecretTypeSSHKey": "SSH密钥",
+                "SecretTypeToken": "令牌"
+            },
+            "x-enum-descriptions": [
+                "API密钥",
+                "数据库凭证",
+                "证书",
+                "SSH密钥",
+                "令牌",
+                "密码",
+                "其他"
+            ],
+            "x-enum-varnames": [
+                "SecretTypeAPIKey",
+                "SecretTypeDBCredential",
+                "SecretTypeCertificate",
+                "SecretTypeSSHKey",
+                "SecretTypeToken",
+                "SecretTypePassword",
+                "SecretTypeOther"
+            ]
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.ServiceAccount": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_login_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_uuid": {
+                    "type": "string"
+                },
+                "role_id": {
+                    "description": "登录标识，不保密",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.ServiceAccountSecretID": {
+            "type": "object",
+            "properties": {
+                "bound_cidrs": {
+                    "description": "允许登录和使用令牌的IP或CIDR，为空时不限制",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "last_used_ip": {
+                    "type": "string"
+                },
+                "max_uses": {
+                    "description": "最多登录次数，0表示不限制",
+                    "type": "integer"
+                },
+                "service_account_uuid": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "use_count": {
+                    "type": "integer"
+                },
+                "uuid": {
+                    "description": "访问标识，用于查询和撤销",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.StatType": {
+            "type": "string",
+            "enum": [
+                "daily",
+                "weekly",
+                "monthly"
+            ],
+            "x-enum-varnames": [
+                "StatDaily",
+                "StatWeekly",
+                "StatMonthly"
+            ]
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.UserStatistics": {
+            "type": "object",
+            "properties": {
+                "access_count": {
+                    "type": "integer"
+                },
+                "api_key_count": {
+                    "type": "integer"
+                },
+                "certificate_count": {
+                    "type": "integer"
+                },
+                "create_count": {
+                    "description": "操作次数统计",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delete_count": {
+                    "type": "integer"
+                },
+                "failed_login_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "login_count": {
+                    "description": "登录统计",
+                    "type": "integer"
+                },
+                "other_count": {
+                    "type": "integer"
+                },
+                "password_count": {
+                    "type": "integer"
+                },
+                "private_key_count": {
+                    "type": "integer"
+                },
+                "ssh_key_count": {
+                    "type": "integer"
+                },
+                "stat_date": {
+                    "type": "string"
+                },
+                "stat_type": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.StatType"
+                },
+                "total_operations": {
+                    "type": "integer"
+                },
+                "total_secrets": {
+                    "description": "密钥数量统计",
+                    "type": "integer"
+                },
+                "update_count": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_uuid": {
+                    "description": "统计维度",
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.UserStatus": {
+            "type": "integer",
+            "enum": [
+                1,
+                2,
+                3
+            ],
+            "x-enum-comments": {
+                "UserStatusActive": "活跃",
+                "UserStatusDisabled": "已禁用",
+                "UserStatusLocked": "已锁定"
+            },
+            "x-enum-descriptions": [
+                "活跃",
+                "已禁用",
+                "已锁定"
+            ],
+            "x-enum-varnames": [
+                "UserStatusActive",
+                "UserStatusDisabled",
+                "UserStatusLocked"
+            ]
+        },
+        "github_com_cuihe500_vaulthub_internal_database_models.WebAuthnCredential": {
+            "type": "object",
+            "properties": {
+                "attestation_type": {
+                    "type": "string"
+                },
+                "backup_eligible": {
+                    "description": "是否为可同步的通行密钥，注册后不会改变",
+                    "type": "boolean"
+                },
+                "backup_state": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sign_count": {
+                    "description": "认证器签名计数，计数回退说明凭证可能被复制",
+                    "type": "integer"
+                },
+                "transports": {
+                    "description": "认证器支持的传输方式（逗号分隔）",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_uuid": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.AppRoleLoginRequest": {
+            "type": "object",
+            "required": [
+                "role_id",
+                "secret_id"
+            ],
+            "properties": {
+                "role_id": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "secret_id": {
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.AppRoleLoginResponse": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "description": "令牌有效期（秒）",
+                    "type": "integer"
+                },
+                "service_account": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.ServiceAccount"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ApproverInfo": {
+            "type": "object",
+            "properties": {
+                "username": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.AuditLogDTO": {
+            "type": "object",
+            "properties": {
+                "action_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "details": {},
+                "error_code": {
+                    "type": "integer"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "resource_name": {
+                    "type": "string"
+                },
+                "resource_type": {
+                    "type": "string"
+                },
+                "resource_uuid": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_uuid": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.BackupSkippedSecret": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "secret_uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.BatchUpdateConfigRequest": {
+            "type": "object",
+            "required": [
+                "configs"
+            ],
+            "properties": {
+                "configs": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "object",
+                        "required": [
+                            "config_key",
+                            "config_value"
+                        ],
+                        "properties": {
+                            "config_key": {
+                                "type": "string"
+                            },
+                            "config_value": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.BeginLoginMFAEnrollmentRequest": {
+            "type": "object",
+            "required": [
+                "mfa_token"
+            ],
+            "properties": {
+                "mfa_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ChangeExpiredPasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "password_change_token"
+            ],
+            "properties": {
+                "device_label": {
+                    "description": "设备名称（可选），显示在会话列表中",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "new_password": {
+                    "type": "string"
+                },
+                "password_change_token": {
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                },
+                "old_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ChangePasswordResponse": {
+            "type": "object",
+            "properties": {
+                "revoked_sessions": {
+                    "description": "被注销的其他登录会话数量",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CheckinRequest": {
+            "type": "object",
+            "required": [
+                "checkout_uuid"
+            ],
+            "properties": {
+                "checkout_uuid": {
+                    "description": "检出时返回的检出UUID",
+                    "type": "string"
+                },
+                "security_pin": {
+                    "description": "可选，秘密关联了密码生成规则时提供则自动轮换",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CheckinResponse": {
+            "type": "object",
+            "properties": {
+                "checkout": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretCheckout"
+                },
+                "rotated": {
+                    "description": "是否已自动轮换",
+                    "type": "boolean"
+                },
+                "rotation_required": {
+                    "description": "是否仍待轮换",
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CheckoutRequest": {
+            "type": "object",
+            "required": [
+                "security_pin"
+            ],
+            "properties": {
+                "duration_minutes": {
+                    "description": "检出时长（分钟），默认取系统配置",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "reason": {
+                    "description": "检出原因",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "security_pin": {
+                    "description": "安全密码",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CheckoutResponse": {
+            "type": "object",
+            "properties": {
+                "checkout": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretCheckout"
+                },
+                "plain_data": {
+                    "description": "解密后的明文",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CheckoutStatusResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "当前检出（未检出时为null）",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretCheckout"
+                        }
+                    ]
+                },
+                "checkout_required": {
+                    "type": "boolean"
+                },
+                "history": {
+                    "description": "最近的检出记录",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretCheckout"
+                    }
+                },
+                "rotation_required": {
+                    "type": "boolean"
+                },
+                "secret_uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ConfigItem": {
+            "type": "object",
+            "properties": {
+                "config_key": {
+                    "type": "string"
+                },
+                "config_value": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ConflictStrategy": {
+            "type": "string",
+            "enum": [
+                "skip",
+                "overwrite",
+                "rename"
+            ],
+            "x-enum-comments": {
+                "ConflictOverwrite": "覆盖最近创建的同名秘密",
+                "ConflictRename": "以新名称导入",
+                "ConflictSkip": "跳过同名秘密"
+            },
+            "x-enum-descriptions": [
+                "跳过同名秘密",
+                "覆盖最近创建的同名秘密",
+                "以新名称导入"
+            ],
+            "x-enum-varnames": [
+                "ConflictSkip",
+                "ConflictOverwrite",
+                "ConflictRename"
+            ]
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CreateAccessRequestRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "申请原因",
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "window_minutes": {
+                    "description": "批准后可解密的时长（分钟），默认取系统配置",
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 1
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CreateAccessRequestResponse": {
+            "type": "object",
+            "properties": {
+                "approvers": {
+                    "description": "收到审批通知的审批人用户名",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "request": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretAccessRequest"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CreatePersonalAccessTokenRequest": {
+            "type": "object",
+            "required": [
+                "allowed_ips",
+                "expires_in_days",
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "allowed_ips": {
+                    "description": "允许使用的IP或CIDR，为空时不限制",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expires_in_days": {
+                    "description": "有效期（天），不能超过系统配置的上限",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "scopes": {
+                    "description": "权限范围，如 [\"secret:read\"]",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret_uuids": {
+                    "description": "只允许访问的秘密，为空时可以访问全部秘密",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CreatePersonalAccessTokenResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.PersonalAccessToken"
+                },
+                "token": {
+                    "description": "令牌明文，只返回这一次",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CreateProfileRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "nickname"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "nickname": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "phone": {
+                    "type": "string",
+                    "maxLength": 20
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CreateSecretIDRequest": {
+            "type": "object",
+            "required": [
+                "bound_cidrs",
+                "expires_in_hours"
+            ],
+            "properties": {
+                "bound_cidrs": {
+                    "description": "允许登录和使用令牌的IP或CIDR，为空时不限制",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expires_in_hours": {
+                    "description": "有效期（小时），不能超过系统配置的上限",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "max_uses": {
+                    "description": "最多登录次数，0表示不限制",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CreateSecretIDResponse": {
+            "type": "object",
+            "properties": {
+                "secret_id": {
+                    "description": "secret_id明文，只返回这一次",
+                    "type": "string"
+                },
+                "secret_id_info": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.ServiceAccountSecretID"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CreateServiceAccountRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 48
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CreateShareRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "临时文本（不分享已保存秘密时使用）",
+                    "type": "string",
+                    "maxLength": 65536
+                },
+                "expires_in": {
+                    "description": "有效期（秒），默认24小时，最长7天",
+                    "type": "integer",
+                    "maximum": 604800,
+                    "minimum": 60
+                },
+                "max_views": {
+                    "description": "最多查看次数，默认1次",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "passphrase": {
+                    "description": "可选的查看口令，需另行告知接收方",
+                    "type": "string",
+                    "maxLength": 128,
+                    "minLength": 6
+                },
+                "secret_uuid": {
+                    "description": "要分享的秘密",
+                    "type": "string"
+                },
+                "security_pin": {
+                    "description": "安全密码（分享秘密时必填）",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CreateShareResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "max_views": {
+                    "type": "integer"
+                },
+                "share_uuid": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "完整链接，#之后为解密密钥，只返回这一次",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CreateUserEncryptionKeyRequest": {
+            "type": "object",
+            "required": [
+                "security_pin"
+            ],
+            "properties": {
+                "security_pin": {
+                    "description": "安全密码，用于保护加密数据（独立于登录密码）",
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CreateUserEncryptionKeyResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_key": {
+                    "description": "恢复密钥（阶段2实现）",
+                    "type": "string"
+                },
+                "user_encryption_key": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserEncryptionKey"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.CurrentStatistics": {
+            "type": "object",
+            "properties": {
+                "api_key_count": {
+                    "type": "integer"
+                },
+                "certificate_count": {
+                    "type": "integer"
+                },
+                "other_count": {
+                    "type": "integer"
+                },
+                "password_count": {
+                    "type": "integer"
+                },
+                "private_key_count": {
+                    "type": "integer"
+                },
+                "ssh_key_count": {
+                    "type": "integer"
+                },
+                "today_operations": {
+                    "type": "integer"
+                },
+                "total_secrets": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.DecideAccessRequestRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "description": "审批意见",
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.DecryptSecretRequest": {
+            "type": "object",
+            "required": [
+                "security_pin"
+            ],
+            "properties": {
+                "security_pin": {
+                    "description": "安全密码，用于解密DEK",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.DesignateContactRequest": {
+            "type": "object",
+            "required": [
+                "access_type",
+                "contact_username",
+                "security_pin"
+            ],
+            "properties": {
+                "access_type": {
+                    "description": "访问类型",
+                    "enum": [
+                        "view",
+                        "takeover"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccessType"
+                        }
+                    ]
+                },
+                "contact_username": {
+                    "description": "联系人用户名",
+                    "type": "string"
+                },
+                "security_pin": {
+                    "description": "安全密码，用于解密DEK后封装给联系人",
+                    "type": "string"
+                },
+                "wait_days": {
+                    "description": "等待期（天），默认取系统配置",
+                    "type": "integer",
+                    "maximum": 90,
+                    "minimum": 1
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.DownloadAttachmentRequest": {
+            "type": "object",
+            "required": [
+                "security_pin"
+            ],
+            "properties": {
+                "security_pin": {
+                    "description": "安全密码",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.EmailApprovalInfo": {
+            "type": "object",
+            "properties": {
+                "approver": {
+                    "description": "令牌对应的审批人用户名",
+                    "type": "string"
+                },
+                "request": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretAccessRequest"
+                },
+                "secret_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.EmailDecisionRequest": {
+            "type": "object",
+            "required": [
+                "decision",
+                "token"
+            ],
+            "properties": {
+                "comment": {
+                    "description": "审批意见",
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "decision": {
+                    "description": "审批结果",
+                    "type": "string",
+                    "enum": [
+                        "approve",
+                        "deny"
+                    ]
+                },
+                "token": {
+                    "description": "邮件链接中的审批令牌",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.EmergencyAccessInfo": {
+            "type": "object",
+            "properties": {
+                "access_type": {
+                    "description": "授权内容",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccessType"
+                        }
+                    ]
+                },
+                "available_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dek_version": {
+                    "type": "integer"
+                },
+                "grant_uuid": {
+                    "type": "string"
+                },
+                "granted_at": {
+                    "type": "string"
+                },
+                "grantee_fingerprint": {
+                    "description": "联系人公钥指纹",
+                    "type": "string"
+                },
+                "grantee_username": {
+                    "type": "string"
+                },
+                "grantee_uuid": {
+                    "type": "string"
+                },
+                "grantor_username": {
+                    "type": "string"
+                },
+                "grantor_uuid": {
+                    "description": "授权双方",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_rejected_at": {
+                    "type": "string"
+                },
+                "requested_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "申请状态",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccessStatus"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "wait_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.EmergencyDecryptRequest": {
+            "type": "object",
+            "required": [
+                "security_pin"
+            ],
+            "properties": {
+                "security_pin": {
+                    "description": "联系人自己的安全密码",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.EmergencyDecryptResponse": {
+            "type": "object",
+            "properties": {
+                "access_count": {
+                    "type": "integer"
+                },
+                "access_request_uuid": {
+                    "description": "高敏感秘密解密时依据的访问申请",
+                    "type": "string"
+                },
+                "approval_required": {
+                    "type": "boolean"
+                },
+                "checkout_required": {
+                    "type": "boolean"
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -2509,6 +8423,9 @@ const docTemplate = `{
                 "description": {
                     "type": "string"
                 },
+                "grantor_username": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -2522,6 +8439,9 @@ const docTemplate = `{
                     "description": "解密后的明文数据",
                     "type": "string"
                 },
+                "rotation_required": {
+                    "type": "boolean"
+                },
                 "secret_name": {
                     "type": "string"
                 },
@@ -2536,832 +8456,1481 @@ const docTemplate = `{
                 },
                 "user_uuid": {
                     "type": "string"
+                },
+                "value_changed_at": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_database_models.SafeEncryptedSecret": {
+        "github_com_cuihe500_vaulthub_internal_service.EncryptAndStoreSecretRequest": {
             "type": "object",
+            "required": [
+                "plain_data",
+                "secret_name",
+                "secret_type",
+                "security_pin"
+            ],
             "properties": {
-                "access_count": {
-                    "type": "integer"
+                "description": {
+                    "type": "string"
                 },
-                "created_at": {
+                "metadata": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretMetadata"
+                },
+                "plain_data": {
                     "type": "string"
                 },
-                "dek_version": {
-                    "type": "integer"
+                "secret_name": {
+                    "type": "string"
                 },
-                "description": {
+                "secret_type": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretType"
+                },
+                "security_pin": {
+                    "description": "安全密码，用于解密DEK",
                     "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ExportBackupRequest": {
+            "type": "object",
+            "required": [
+                "passphrase",
+                "security_pin"
+            ],
+            "properties": {
+                "passphrase": {
+                    "description": "备份口令，用于加密备份文件（独立于安全密码）",
+                    "type": "string",
+                    "minLength": 12
                 },
-                "id": {
+                "security_pin": {
+                    "description": "安全密码，用于解密DEK",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ExportBackupResponse": {
+            "type": "object",
+            "properties": {
+                "backup": {
+                    "description": "备份文件内容，原样保存为JSON文件即可",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_backup.File"
+                        }
+                    ]
+                },
+                "file_name": {
+                    "description": "建议的文件名",
+                    "type": "string"
+                },
+                "secret_count": {
+                    "description": "备份的秘密数量",
                     "type": "integer"
                 },
-                "last_accessed_at": {
+                "skipped": {
+                    "description": "当前无权解密、未导出的秘密",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.BackupSkippedSecret"
+                    }
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ForceReleaseRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "description": "强制释放原因，记录到审计日志",
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.GrantedSecretListResponse": {
+            "type": "object",
+            "properties": {
+                "secrets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeEncryptedSecret"
+                    }
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ImportItemResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "失败原因",
                     "type": "string"
                 },
-                "metadata": {
-                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretMetadata"
+                "folder": {
+                    "description": "映射后的文件夹",
+                    "type": "string"
                 },
-                "secret_name": {
+                "index": {
+                    "description": "在源文件中的序号（dotenv/CSV为行号）",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "秘密名称",
                     "type": "string"
                 },
                 "secret_type": {
-                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretType"
+                    "description": "映射后的秘密类型",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretType"
+                        }
+                    ]
                 },
                 "secret_uuid": {
+                    "description": "导入成功后的秘密UUID",
                     "type": "string"
                 },
-                "updated_at": {
+                "status": {
+                    "description": "处理状态",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ImportItemStatus"
+                        }
+                    ]
+                },
+                "tags": {
+                    "description": "映射后的标签",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ImportItemStatus": {
+            "type": "string",
+            "enum": [
+                "created",
+                "would_create",
+                "skipped",
+                "failed"
+            ],
+            "x-enum-comments": {
+                "ImportStatusCreated": "已导入",
+                "ImportStatusFailed": "校验或加密失败",
+                "ImportStatusSkipped": "已存在同名秘密，跳过",
+                "ImportStatusWouldCreate": "预览模式下可以导入"
+            },
+            "x-enum-descriptions": [
+                "已导入",
+                "预览模式下可以导入",
+                "已存在同名秘密，跳过",
+                "校验或加密失败"
+            ],
+            "x-enum-varnames": [
+                "ImportStatusCreated",
+                "ImportStatusWouldCreate",
+                "ImportStatusSkipped",
+                "ImportStatusFailed"
+            ]
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ImportSecretsRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "format"
+            ],
+            "properties": {
+                "content": {
+                    "description": "文件内容（原始文本）",
                     "type": "string"
                 },
-                "user_uuid": {
+                "csv_mapping": {
+                    "description": "CSV列映射，format=csv时必填",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_importer.CSVMapping"
+                        }
+                    ]
+                },
+                "default_type": {
+                    "description": "无法从文件推断类型时使用的默认类型，默认other",
+                    "enum": [
+                        "api_key",
+                        "db_credential",
+                        "certificate",
+                        "ssh_key",
+                        "token",
+                        "password",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretType"
+                        }
+                    ]
+                },
+                "dry_run": {
+                    "description": "仅预览，不写入",
+                    "type": "boolean"
+                },
+                "folder": {
+                    "description": "目标文件夹前缀，会拼接在源文件夹之前",
+                    "type": "string"
+                },
+                "format": {
+                    "description": "文件格式",
+                    "enum": [
+                        "dotenv",
+                        "csv",
+                        "bitwarden",
+                        "keepass"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_importer.Format"
+                        }
+                    ]
+                },
+                "security_pin": {
+                    "description": "安全密码，预览模式下可不传",
+                    "type": "string"
+                },
+                "skip_existing": {
+                    "description": "跳过已存在的同名秘密",
+                    "type": "boolean"
+                },
+                "tags": {
+                    "description": "追加到每条记录的标签",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ImportSecretsResponse": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "description": "失败的条目数",
+                    "type": "integer"
+                },
+                "format": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_importer.Format"
+                },
+                "imported": {
+                    "description": "导入（或预览可导入）的条目数",
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ImportItemResult"
+                    }
+                },
+                "skipped": {
+                    "description": "跳过的条目数",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "解析出的条目数",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.InitKeyPairRequest": {
+            "type": "object",
+            "required": [
+                "security_pin"
+            ],
+            "properties": {
+                "security_pin": {
+                    "description": "安全密码，用于解密DEK以加密私钥",
                     "type": "string"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_database_models.SafeUser": {
+        "github_com_cuihe500_vaulthub_internal_service.KeyPairStatus": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "fingerprint": {
+                    "description": "公钥指纹，授权人指定联系人前可线下核对",
                     "type": "string"
                 },
-                "id": {
+                "has_key_pair": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ListAccessRequestsResponse": {
+            "type": "object",
+            "properties": {
+                "page": {
                     "type": "integer"
                 },
-                "last_login_at": {
-                    "type": "string"
+                "page_size": {
+                    "type": "integer"
                 },
-                "role": {
-                    "type": "string"
+                "requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretAccessRequest"
+                    }
                 },
-                "status": {
-                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.UserStatus"
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ListAttachmentsResponse": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretAttachment"
+                    }
                 },
-                "updated_at": {
-                    "type": "string"
+                "max_file_size": {
+                    "description": "单个附件大小上限",
+                    "type": "integer"
                 },
-                "username": {
-                    "type": "string"
+                "quota_bytes": {
+                    "description": "当前用户的附件总容量",
+                    "type": "integer"
                 },
-                "uuid": {
-                    "type": "string"
+                "used_bytes": {
+                    "description": "当前用户已使用的附件容量",
+                    "type": "integer"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_database_models.SafeUserEncryptionKey": {
+        "github_com_cuihe500_vaulthub_internal_service.ListCheckoutsResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "dek_algorithm": {
-                    "type": "string"
+                "checkouts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretCheckout"
+                    }
                 },
-                "dek_version": {
+                "page": {
                     "type": "integer"
                 },
-                "id": {
+                "page_size": {
                     "type": "integer"
                 },
-                "kek_algorithm": {
-                    "type": "string"
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ListConfigsResponse": {
+            "type": "object",
+            "properties": {
+                "configs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ConfigItem"
+                    }
                 },
-                "last_rotation_at": {
-                    "type": "string"
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ListProfilesResponse": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer"
                 },
-                "rotation_started_at": {
-                    "type": "string"
+                "page_size": {
+                    "type": "integer"
                 },
-                "rotation_status": {
-                    "type": "string"
+                "profiles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserProfile"
+                    }
                 },
-                "updated_at": {
-                    "type": "string"
+                "total": {
+                    "type": "integer"
                 },
-                "user_uuid": {
-                    "type": "string"
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_database_models.SafeUserProfile": {
+        "github_com_cuihe500_vaulthub_internal_service.ListTrashResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "page": {
+                    "type": "integer"
                 },
-                "email": {
-                    "type": "string"
+                "page_size": {
+                    "type": "integer"
                 },
-                "email_verified": {
-                    "type": "boolean"
+                "retention_days": {
+                    "description": "当前保留天数（0表示不自动清除）",
+                    "type": "integer"
                 },
-                "id": {
+                "secrets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.TrashedSecret"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ListUserSecretsResponse": {
+            "type": "object",
+            "properties": {
+                "page": {
                     "type": "integer"
                 },
-                "nickname": {
-                    "type": "string"
+                "page_size": {
+                    "type": "integer"
                 },
-                "phone": {
-                    "type": "string"
+                "secrets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeEncryptedSecret"
+                    }
                 },
-                "updated_at": {
-                    "type": "string"
+                "total": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "total_pages": {
                     "type": "integer"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_database_models.SecretMetadata": {
+        "github_com_cuihe500_vaulthub_internal_service.ListUsersResponse": {
             "type": "object",
             "properties": {
-                "expires_at": {
-                    "description": "过期时间",
-                    "type": "string"
+                "page": {
+                    "type": "integer"
                 },
-                "extra": {
-                    "description": "额外信息",
-                    "type": "object",
-                    "additionalProperties": true
+                "page_size": {
+                    "type": "integer"
                 },
-                "tags": {
-                    "description": "标签",
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                },
+                "users": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUser"
                     }
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_database_models.SecretType": {
-            "type": "string",
-            "enum": [
-                "api_key",
-                "db_credential",
-                "certificate",
-                "ssh_key",
-                "token",
+        "github_com_cuihe500_vaulthub_internal_service.LoginRequest": {
+            "type": "object",
+            "required": [
                 "password",
-                "other"
-            ],
-            "x-enum-comments": {
-                "SecretTypeAPIKey": "API密钥",
-                "SecretTypeCertificate": "证书",
-                "SecretTypeDBCredential": "数据库凭证",
-                "SecretTypeOther": "其他",
-                "SecretTypePassword": "密码",
-                "SecretTypeSSHKey": "SSH密钥",
-                "SecretTypeToken": "令牌"
-            },
-            "x-enum-descriptions": [
-                "API密钥",
-                "数据库凭证",
-                "证书",
-                "SSH密钥",
-                "令牌",
-                "密码",
-                "其他"
+                "username"
             ],
-            "x-enum-varnames": [
-                "SecretTypeAPIKey",
-                "SecretTypeDBCredential",
-                "SecretTypeCertificate",
-                "SecretTypeSSHKey",
-                "SecretTypeToken",
-                "SecretTypePassword",
-                "SecretTypeOther"
-            ]
+            "properties": {
+                "device_label": {
+                    "description": "设备名称（可选），显示在会话列表中",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
         },
-        "github_com_cuihe500_vaulthub_internal_database_models.StatType": {
-            "type": "string",
-            "enum": [
-                "daily",
-                "weekly",
-                "monthly"
-            ],
-            "x-enum-varnames": [
-                "StatDaily",
-                "StatWeekly",
-                "StatMonthly"
-            ]
+        "github_com_cuihe500_vaulthub_internal_service.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "backup_codes": {
+                    "description": "登录时完成绑定才返回，只返回这一次",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expires_in": {
+                    "description": "访问令牌有效期（秒）",
+                    "type": "integer"
+                },
+                "mfa_enrollment_required": {
+                    "description": "角色要求两步验证但尚未绑定，需要先绑定认证器",
+                    "type": "boolean"
+                },
+                "mfa_required": {
+                    "type": "boolean"
+                },
+                "mfa_token": {
+                    "description": "两步验证挑战令牌，5分钟内有效",
+                    "type": "string"
+                },
+                "password_change_required": {
+                    "description": "密码已超过最长使用期限，需要先修改密码",
+                    "type": "boolean"
+                },
+                "password_change_token": {
+                    "description": "修改过期密码的令牌，10分钟内有效",
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "description": "刷新令牌，通过 /auth/refresh 换取新的令牌，每次使用后轮换",
+                    "type": "string"
+                },
+                "session_uuid": {
+                    "description": "登录会话UUID",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "访问令牌，短期有效",
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUser"
+                }
+            }
         },
-        "github_com_cuihe500_vaulthub_internal_database_models.UserStatistics": {
+        "github_com_cuihe500_vaulthub_internal_service.LoginWithEmailRequest": {
             "type": "object",
+            "required": [
+                "code",
+                "email"
+            ],
             "properties": {
-                "access_count": {
-                    "type": "integer"
+                "code": {
+                    "type": "string"
                 },
-                "api_key_count": {
-                    "type": "integer"
+                "device_label": {
+                    "description": "设备名称（可选），显示在会话列表中",
+                    "type": "string",
+                    "maxLength": 64
                 },
-                "certificate_count": {
-                    "type": "integer"
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.LoginWithMFARequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "mfa_token"
+            ],
+            "properties": {
+                "code": {
+                    "description": "认证器验证码或备用码；首次绑定时只能使用认证器验证码",
+                    "type": "string",
+                    "maxLength": 32
                 },
-                "create_count": {
-                    "description": "操作次数统计",
-                    "type": "integer"
+                "device_label": {
+                    "description": "设备名称（可选），显示在会话列表中",
+                    "type": "string",
+                    "maxLength": 64
                 },
-                "created_at": {
+                "mfa_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.MFABackupCodesResponse": {
+            "type": "object",
+            "properties": {
+                "backup_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.MFACodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 32
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.MFAEnrollmentResponse": {
+            "type": "object",
+            "properties": {
+                "otpauth_uri": {
+                    "description": "生成二维码用的otpauth URI",
                     "type": "string"
                 },
-                "delete_count": {
+                "secret": {
+                    "description": "Base32编码的密钥，供无法扫码时手动输入",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.MFAStatusResponse": {
+            "type": "object",
+            "properties": {
+                "backup_codes_remaining": {
+                    "description": "未使用的备用码数量",
                     "type": "integer"
                 },
-                "failed_login_count": {
-                    "type": "integer"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "id": {
-                    "type": "integer"
+                "enabled_at": {
+                    "type": "string"
                 },
-                "login_count": {
-                    "description": "登录统计",
-                    "type": "integer"
+                "required": {
+                    "description": "当前角色是否被要求启用",
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.MigrationTask": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
                 },
-                "other_count": {
+                "error": {
+                    "type": "string"
+                },
+                "failed_secrets": {
                     "type": "integer"
                 },
-                "password_count": {
+                "migrated_secrets": {
                     "type": "integer"
                 },
-                "private_key_count": {
+                "new_version": {
                     "type": "integer"
                 },
-                "ssh_key_count": {
+                "old_version": {
                     "type": "integer"
                 },
-                "stat_date": {
+                "started_at": {
                     "type": "string"
                 },
-                "stat_type": {
-                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.StatType"
-                },
-                "total_operations": {
-                    "type": "integer"
+                "status": {
+                    "description": "running, completed, failed",
+                    "type": "string"
                 },
                 "total_secrets": {
-                    "description": "密钥数量统计",
-                    "type": "integer"
-                },
-                "update_count": {
                     "type": "integer"
                 },
-                "updated_at": {
-                    "type": "string"
-                },
                 "user_uuid": {
-                    "description": "统计维度",
-                    "type": "string"
-                },
-                "uuid": {
                     "type": "string"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_database_models.UserStatus": {
-            "type": "integer",
-            "enum": [
-                1,
-                2,
-                3
-            ],
-            "x-enum-comments": {
-                "UserStatusActive": "活跃",
-                "UserStatusDisabled": "已禁用",
-                "UserStatusLocked": "已锁定"
-            },
-            "x-enum-descriptions": [
-                "活跃",
-                "已禁用",
-                "已锁定"
-            ],
-            "x-enum-varnames": [
-                "UserStatusActive",
-                "UserStatusDisabled",
-                "UserStatusLocked"
-            ]
-        },
-        "github_com_cuihe500_vaulthub_internal_service.AuditLogDTO": {
+        "github_com_cuihe500_vaulthub_internal_service.OIDCLoginBeginResponse": {
             "type": "object",
             "properties": {
-                "action_type": {
+                "authorization_url": {
+                    "description": "前端跳转到该地址完成身份提供方登录",
                     "type": "string"
                 },
-                "created_at": {
+                "state": {
+                    "description": "回调时身份提供方原样返回，完成登录时提交",
                     "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.OIDCLoginRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "state"
+            ],
+            "properties": {
+                "code": {
+                    "description": "回调中的授权码",
+                    "type": "string",
+                    "maxLength": 2048
                 },
-                "details": {},
-                "error_code": {
-                    "type": "integer"
-                },
-                "error_message": {
-                    "type": "string"
+                "device_label": {
+                    "description": "设备名称（可选），显示在会话列表中",
+                    "type": "string",
+                    "maxLength": 64
                 },
-                "ip_address": {
+                "state": {
+                    "description": "回调中的state",
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.OpenShareRequest": {
+            "type": "object",
+            "properties": {
+                "passphrase": {
+                    "description": "查看口令（创建时设置了口令才需要）",
                     "type": "string"
-                },
-                "request_id": {
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.OpenShareResponse": {
+            "type": "object",
+            "properties": {
+                "ciphertext": {
+                    "description": "base64，[密文][认证标签(16字节)]",
                     "type": "string"
                 },
-                "resource_name": {
+                "nonce": {
+                    "description": "base64，12字节",
                     "type": "string"
                 },
-                "resource_type": {
+                "secret_name": {
                     "type": "string"
                 },
-                "resource_uuid": {
-                    "type": "string"
+                "views_left": {
+                    "description": "剩余查看次数，为0时分享已销毁",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.OperationStatisticsExport": {
+            "type": "object",
+            "properties": {
+                "by_action": {
+                    "description": "按操作类型统计",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
                 },
-                "status": {
-                    "type": "string"
+                "total_operations": {
+                    "description": "操作总数",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.PersonalAccessTokenListResponse": {
+            "type": "object",
+            "properties": {
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.PersonalAccessToken"
+                    }
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.PurgeTrashRequest": {
+            "type": "object",
+            "required": [
+                "security_pin"
+            ],
+            "properties": {
+                "secret_uuids": {
+                    "description": "要清除的秘密，为空时清空整个回收站",
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "user_agent": {
+                "security_pin": {
+                    "description": "安全密码，彻底删除前需要再次确认身份",
                     "type": "string"
-                },
-                "user_uuid": {
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.PurgeTrashResponse": {
+            "type": "object",
+            "properties": {
+                "purged": {
+                    "description": "彻底删除的秘密数量",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "code": {
+                    "description": "验证码（必填）",
                     "type": "string"
                 },
-                "username": {
+                "email": {
+                    "description": "邮箱（必填）",
                     "type": "string"
                 },
-                "uuid": {
-                    "type": "string"
+                "nickname": {
+                    "description": "昵称（可选，默认使用username）",
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 1
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.BatchUpdateConfigRequest": {
+        "github_com_cuihe500_vaulthub_internal_service.RegisterResponse": {
             "type": "object",
-            "required": [
-                "configs"
-            ],
             "properties": {
-                "configs": {
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "object",
-                        "required": [
-                            "config_key",
-                            "config_value"
-                        ],
-                        "properties": {
-                            "config_key": {
-                                "type": "string"
-                            },
-                            "config_value": {
-                                "type": "string"
-                            }
-                        }
-                    }
+                "user": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUser"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.ConfigItem": {
+        "github_com_cuihe500_vaulthub_internal_service.RenderTemplateRequest": {
             "type": "object",
+            "required": [
+                "security_pin",
+                "template"
+            ],
             "properties": {
-                "config_key": {
+                "security_pin": {
+                    "description": "安全密码，用于解密DEK",
                     "type": "string"
                 },
-                "config_value": {
+                "template": {
+                    "description": "模板内容",
                     "type": "string"
-                },
-                "description": {
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.RenderTemplateResponse": {
+            "type": "object",
+            "properties": {
+                "output": {
+                    "description": "渲染结果",
                     "type": "string"
+                },
+                "secrets": {
+                    "description": "引用的秘密（按路径排序）",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.TemplateSecretRef"
+                    }
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.CreateProfileRequest": {
+        "github_com_cuihe500_vaulthub_internal_service.RequestPasswordResetRequest": {
             "type": "object",
             "required": [
-                "email",
-                "nickname"
+                "email"
             ],
             "properties": {
                 "email": {
-                    "type": "string",
-                    "maxLength": 100
-                },
-                "nickname": {
-                    "type": "string",
-                    "maxLength": 50
-                },
-                "phone": {
-                    "type": "string",
-                    "maxLength": 20
+                    "type": "string"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.CreateUserEncryptionKeyRequest": {
+        "github_com_cuihe500_vaulthub_internal_service.RequestPasswordResetResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.RescanRequest": {
             "type": "object",
             "required": [
                 "security_pin"
             ],
             "properties": {
                 "security_pin": {
-                    "description": "安全密码，用于保护加密数据（独立于登录密码）",
-                    "type": "string",
-                    "minLength": 8
+                    "description": "安全密码，用于解密DEK",
+                    "type": "string"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.CreateUserEncryptionKeyResponse": {
+        "github_com_cuihe500_vaulthub_internal_service.RescanResponse": {
             "type": "object",
             "properties": {
-                "recovery_key": {
-                    "description": "恢复密钥（阶段2实现）",
-                    "type": "string"
+                "failed": {
+                    "description": "解密失败或密钥版本不匹配的密码数",
+                    "type": "integer"
                 },
-                "user_encryption_key": {
-                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserEncryptionKey"
+                "scanned": {
+                    "description": "成功计算指纹和强度的密码数",
+                    "type": "integer"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.CurrentStatistics": {
+        "github_com_cuihe500_vaulthub_internal_service.ResetPasswordRequest": {
             "type": "object",
+            "required": [
+                "new_password",
+                "token"
+            ],
             "properties": {
-                "api_key_count": {
-                    "type": "integer"
-                },
-                "certificate_count": {
-                    "type": "integer"
-                },
-                "other_count": {
-                    "type": "integer"
-                },
-                "password_count": {
-                    "type": "integer"
-                },
-                "private_key_count": {
-                    "type": "integer"
-                },
-                "ssh_key_count": {
-                    "type": "integer"
-                },
-                "today_operations": {
-                    "type": "integer"
+                "new_password": {
+                    "type": "string",
+                    "minLength": 8
                 },
-                "total_secrets": {
-                    "type": "integer"
+                "token": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.DecryptSecretRequest": {
+        "github_com_cuihe500_vaulthub_internal_service.ResetPasswordResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ResetPasswordWithRecoveryRequest": {
             "type": "object",
             "required": [
-                "security_pin"
+                "new_security_pin",
+                "recovery_mnemonic"
             ],
             "properties": {
-                "security_pin": {
-                    "description": "安全密码，用于解密DEK",
+                "new_security_pin": {
+                    "description": "新的安全密码（独立于登录密码）",
+                    "type": "string",
+                    "minLength": 8
+                },
+                "recovery_mnemonic": {
+                    "description": "恢复助记词",
                     "type": "string"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.EncryptAndStoreSecretRequest": {
+        "github_com_cuihe500_vaulthub_internal_service.RestoreBackupRequest": {
             "type": "object",
             "required": [
-                "plain_data",
-                "secret_name",
-                "secret_type",
+                "backup",
+                "passphrase",
                 "security_pin"
             ],
             "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "metadata": {
-                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretMetadata"
+                "backup": {
+                    "description": "备份文件内容",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_backup.File"
+                        }
+                    ]
                 },
-                "plain_data": {
-                    "type": "string"
+                "conflict_strategy": {
+                    "description": "冲突处理策略，默认skip",
+                    "enum": [
+                        "skip",
+                        "overwrite",
+                        "rename"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ConflictStrategy"
+                        }
+                    ]
                 },
-                "secret_name": {
+                "passphrase": {
+                    "description": "导出时设置的备份口令",
                     "type": "string"
                 },
-                "secret_type": {
-                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretType"
-                },
                 "security_pin": {
-                    "description": "安全密码，用于解密DEK",
+                    "description": "当前实例的安全密码，用于加密恢复的秘密",
                     "type": "string"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.ListConfigsResponse": {
+        "github_com_cuihe500_vaulthub_internal_service.RestoreBackupResponse": {
             "type": "object",
             "properties": {
-                "configs": {
+                "created": {
+                    "type": "integer"
+                },
+                "exported_at": {
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ConfigItem"
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RestoreItemResult"
                     }
                 },
-                "total": {
+                "overwritten": {
                     "type": "integer"
-                }
-            }
-        },
-        "github_com_cuihe500_vaulthub_internal_service.ListProfilesResponse": {
-            "type": "object",
-            "properties": {
-                "page": {
+                },
+                "renamed": {
                     "type": "integer"
                 },
-                "page_size": {
+                "skipped": {
                     "type": "integer"
                 },
-                "profiles": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserProfile"
-                    }
+                "source_user_uuid": {
+                    "description": "备份来源用户",
+                    "type": "string"
                 },
                 "total": {
                     "type": "integer"
-                },
-                "total_pages": {
-                    "type": "integer"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.ListUserSecretsResponse": {
+        "github_com_cuihe500_vaulthub_internal_service.RestoreItemResult": {
             "type": "object",
             "properties": {
-                "page": {
-                    "type": "integer"
+                "error": {
+                    "type": "string"
                 },
-                "page_size": {
-                    "type": "integer"
+                "name": {
+                    "description": "备份中的名称",
+                    "type": "string"
                 },
-                "secrets": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeEncryptedSecret"
-                    }
+                "restored_name": {
+                    "description": "恢复后的名称（重命名时与原名称不同）",
+                    "type": "string"
                 },
-                "total": {
-                    "type": "integer"
+                "secret_uuid": {
+                    "type": "string"
                 },
-                "total_pages": {
-                    "type": "integer"
+                "status": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.RestoreItemStatus"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.ListUsersResponse": {
+        "github_com_cuihe500_vaulthub_internal_service.RestoreItemStatus": {
+            "type": "string",
+            "enum": [
+                "created",
+                "overwritten",
+                "renamed",
+                "skipped",
+                "failed"
+            ],
+            "x-enum-comments": {
+                "RestoreStatusCreated": "新建",
+                "RestoreStatusFailed": "数据无效或加密失败",
+                "RestoreStatusOverwritten": "覆盖了已有秘密",
+                "RestoreStatusRenamed": "重命名后新建",
+                "RestoreStatusSkipped": "已存在同名秘密，跳过"
+            },
+            "x-enum-descriptions": [
+                "新建",
+                "覆盖了已有秘密",
+                "重命名后新建",
+                "已存在同名秘密，跳过",
+                "数据无效或加密失败"
+            ],
+            "x-enum-varnames": [
+                "RestoreStatusCreated",
+                "RestoreStatusOverwritten",
+                "RestoreStatusRenamed",
+                "RestoreStatusSkipped",
+                "RestoreStatusFailed"
+            ]
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ReusedPasswordGroup": {
             "type": "object",
             "properties": {
-                "page": {
-                    "type": "integer"
-                },
-                "page_size": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
-                },
-                "total_pages": {
+                "count": {
                     "type": "integer"
                 },
-                "users": {
+                "secrets": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUser"
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.VaultHealthSecret"
                     }
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.LoginRequest": {
+        "github_com_cuihe500_vaulthub_internal_service.RotateDEKRequest": {
             "type": "object",
             "required": [
-                "password",
-                "username"
+                "security_pin"
             ],
             "properties": {
-                "password": {
-                    "type": "string"
-                },
-                "username": {
+                "security_pin": {
+                    "description": "安全密码，用于验证和解密DEK",
                     "type": "string"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.LoginResponse": {
+        "github_com_cuihe500_vaulthub_internal_service.RotateDEKResponse": {
             "type": "object",
             "properties": {
-                "token": {
+                "message": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUser"
+                "user_encryption_key": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserEncryptionKey"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.LoginWithEmailRequest": {
+        "github_com_cuihe500_vaulthub_internal_service.RotateSecretRequest": {
             "type": "object",
             "required": [
-                "code",
-                "email"
+                "security_pin"
             ],
             "properties": {
-                "code": {
+                "plain_data": {
+                    "description": "新的明文，为空时按密码生成规则生成",
                     "type": "string"
                 },
-                "email": {
+                "security_pin": {
+                    "description": "安全密码",
                     "type": "string"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.MigrationTask": {
+        "github_com_cuihe500_vaulthub_internal_service.SecretIDListResponse": {
             "type": "object",
             "properties": {
-                "completed_at": {
-                    "type": "string"
+                "secret_ids": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.ServiceAccountSecretID"
+                    }
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.SecretStatisticsExport": {
+            "type": "object",
+            "properties": {
+                "by_type": {
+                    "description": "按类型统计",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
                 },
-                "error": {
+                "total_secrets": {
+                    "description": "密钥总数",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ServiceAccountInfo": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
                 },
-                "failed_secrets": {
-                    "type": "integer"
+                "description": {
+                    "type": "string"
                 },
-                "migrated_secrets": {
+                "id": {
                     "type": "integer"
                 },
-                "new_version": {
-                    "type": "integer"
+                "last_login_at": {
+                    "type": "string"
                 },
-                "old_version": {
-                    "type": "integer"
+                "name": {
+                    "type": "string"
                 },
-                "started_at": {
+                "owner_uuid": {
                     "type": "string"
                 },
-                "status": {
-                    "description": "running, completed, failed",
+                "role_id": {
+                    "description": "登录标识，不保密",
                     "type": "string"
                 },
-                "total_secrets": {
-                    "type": "integer"
+                "secret_uuids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "user_uuid": {
+                "updated_at": {
+                    "type": "string"
+                },
+                "uuid": {
                     "type": "string"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.OperationStatisticsExport": {
+        "github_com_cuihe500_vaulthub_internal_service.ServiceAccountListResponse": {
             "type": "object",
             "properties": {
-                "by_action": {
-                    "description": "按操作类型统计",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer",
-                        "format": "int64"
+                "service_accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ServiceAccountInfo"
                     }
-                },
-                "total_operations": {
-                    "description": "操作总数",
-                    "type": "integer"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.RegisterRequest": {
+        "github_com_cuihe500_vaulthub_internal_service.SessionInfo": {
             "type": "object",
-            "required": [
-                "password",
-                "username"
-            ],
             "properties": {
-                "code": {
-                    "description": "验证码（可选，与email配合使用）",
+                "created_at": {
                     "type": "string"
                 },
-                "email": {
-                    "description": "邮箱（可选，但如果提供则必须验证）",
+                "current": {
+                    "description": "是否为发起请求的会话",
+                    "type": "boolean"
+                },
+                "device_label": {
+                    "description": "登录时客户端提供的设备名称",
                     "type": "string"
                 },
-                "nickname": {
-                    "description": "昵称（可选，默认使用username）",
-                    "type": "string",
-                    "maxLength": 50,
-                    "minLength": 1
+                "expires_at": {
+                    "description": "刷新令牌过期时间，每次刷新后顺延",
+                    "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 8
+                "id": {
+                    "type": "integer"
                 },
-                "username": {
-                    "type": "string",
-                    "maxLength": 32,
-                    "minLength": 3
+                "ip_address": {
+                    "description": "最近一次登录或刷新时的IP",
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_uuid": {
+                    "type": "string"
+                },
+                "uuid": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.RegisterResponse": {
+        "github_com_cuihe500_vaulthub_internal_service.SessionListResponse": {
             "type": "object",
             "properties": {
-                "user": {
-                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUser"
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.SessionInfo"
+                    }
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.RequestPasswordResetRequest": {
+        "github_com_cuihe500_vaulthub_internal_service.SetApprovalPolicyRequest": {
             "type": "object",
-            "required": [
-                "email"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "approval_required": {
+                    "description": "是否为高敏感秘密",
+                    "type": "boolean"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.RequestPasswordResetResponse": {
+        "github_com_cuihe500_vaulthub_internal_service.SetCheckoutPolicyRequest": {
             "type": "object",
             "properties": {
-                "message": {
+                "checkout_required": {
+                    "description": "是否需要检出后才能访问",
+                    "type": "boolean"
+                },
+                "generator": {
+                    "description": "密码生成规则，为空时移除",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.PasswordGenerator"
+                        }
+                    ]
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.SetGrantsRequest": {
+            "type": "object",
+            "required": [
+                "secret_uuids"
+            ],
+            "properties": {
+                "secret_uuids": {
+                    "description": "为空时撤销全部授权",
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "security_pin": {
+                    "description": "新增授权时需要，用于解密秘密并为服务账户封装；只撤销授权时不需要",
                     "type": "string"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.ResetPasswordRequest": {
+        "github_com_cuihe500_vaulthub_internal_service.SetVaultApproversRequest": {
             "type": "object",
             "required": [
-                "new_password",
-                "token"
+                "usernames"
             ],
             "properties": {
-                "new_password": {
-                    "type": "string",
-                    "minLength": 8
+                "usernames": {
+                    "description": "审批人用户名，为空时恢复使用默认审批角色",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.ShareInfo": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
                 },
-                "token": {
+                "requires_passphrase": {
+                    "type": "boolean"
+                },
+                "secret_name": {
+                    "type": "string"
+                },
+                "share_uuid": {
                     "type": "string"
+                },
+                "views_left": {
+                    "type": "integer"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.ResetPasswordResponse": {
+        "github_com_cuihe500_vaulthub_internal_service.StepUpResponse": {
             "type": "object",
             "properties": {
-                "message": {
+                "expires_at": {
+                    "description": "当前会话二次验证的过期时间",
                     "type": "string"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.ResetPasswordWithRecoveryRequest": {
+        "github_com_cuihe500_vaulthub_internal_service.TakeoverRequest": {
             "type": "object",
             "required": [
+                "new_password",
                 "new_security_pin",
-                "recovery_mnemonic"
+                "security_pin"
             ],
             "properties": {
+                "new_password": {
+                    "description": "授权人账户的新登录密码",
+                    "type": "string"
+                },
                 "new_security_pin": {
-                    "description": "新的安全密码（独立于登录密码）",
+                    "description": "授权人账户的新安全密码",
                     "type": "string",
                     "minLength": 8
                 },
-                "recovery_mnemonic": {
-                    "description": "恢复助记词",
+                "security_pin": {
+                    "description": "联系人自己的安全密码",
                     "type": "string"
                 }
             }
         },
-        "github_com_cuihe500_vaulthub_internal_service.RotateDEKRequest": {
+        "github_com_cuihe500_vaulthub_internal_service.TemplateSecretRef": {
             "type": "object",
-            "required": [
-                "security_pin"
-            ],
             "properties": {
-                "security_pin": {
-                    "description": "安全密码，用于验证和解密DEK",
+                "path": {
+                    "type": "string"
+                },
+                "secret_uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.TrashedSecret": {
+            "type": "object",
+            "properties": {
+                "access_count": {
+                    "type": "integer"
+                },
+                "approval_required": {
+                    "type": "boolean"
+                },
+                "checkout_required": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dek_version": {
+                    "type": "integer"
+                },
+                "deleted_at": {
+                    "description": "删除时间",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_accessed_at": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretMetadata"
+                },
+                "purge_at": {
+                    "description": "预计彻底清除时间（未开启自动清除时为空）",
+                    "type": "string"
+                },
+                "rotation_required": {
+                    "type": "boolean"
+                },
+                "secret_name": {
                     "type": "string"
-                }
-            }
-        },
-        "github_com_cuihe500_vaulthub_internal_service.RotateDEKResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
+                },
+                "secret_type": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretType"
+                },
+                "secret_uuid": {
                     "type": "string"
                 },
-                "user_encryption_key": {
-                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SafeUserEncryptionKey"
-                }
-            }
-        },
-        "github_com_cuihe500_vaulthub_internal_service.SecretStatisticsExport": {
-            "type": "object",
-            "properties": {
-                "by_type": {
-                    "description": "按类型统计",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer",
-                        "format": "int64"
-                    }
+                "updated_at": {
+                    "type": "string"
                 },
-                "total_secrets": {
-                    "description": "密钥总数",
-                    "type": "integer"
+                "user_uuid": {
+                    "type": "string"
+                },
+                "value_changed_at": {
+                    "type": "string"
                 }
             }
         },
@@ -3426,6 +9995,145 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_cuihe500_vaulthub_internal_service.VaultApproversResponse": {
+            "type": "object",
+            "properties": {
+                "approvers": {
+                    "description": "指定的审批人",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ApproverInfo"
+                    }
+                },
+                "default_role": {
+                    "description": "默认审批角色",
+                    "type": "string"
+                },
+                "using_default": {
+                    "description": "未指定审批人，使用默认审批角色",
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.VaultHealthReport": {
+            "type": "object",
+            "properties": {
+                "breached": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.VaultHealthSecret"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "never_accessed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.VaultHealthSecret"
+                    }
+                },
+                "reused": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.ReusedPasswordGroup"
+                    }
+                },
+                "stale": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.VaultHealthSecret"
+                    }
+                },
+                "stale_days": {
+                    "type": "integer"
+                },
+                "summary": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.VaultHealthSummary"
+                },
+                "unchecked": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.VaultHealthSecret"
+                    }
+                },
+                "weak": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_service.VaultHealthSecret"
+                    }
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.VaultHealthSecret": {
+            "type": "object",
+            "properties": {
+                "access_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "folder": {
+                    "type": "string"
+                },
+                "last_accessed_at": {
+                    "type": "string"
+                },
+                "secret_name": {
+                    "type": "string"
+                },
+                "secret_type": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.SecretType"
+                },
+                "secret_uuid": {
+                    "type": "string"
+                },
+                "strength": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.PasswordStrength"
+                },
+                "value_changed_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.VaultHealthSummary": {
+            "type": "object",
+            "properties": {
+                "breached": {
+                    "description": "出现在泄露数据集中的密码数",
+                    "type": "integer"
+                },
+                "never_accessed": {
+                    "description": "从未访问的秘密数",
+                    "type": "integer"
+                },
+                "password_secrets": {
+                    "description": "密码类秘密数",
+                    "type": "integer"
+                },
+                "reused": {
+                    "description": "与其他秘密重复的密码数",
+                    "type": "integer"
+                },
+                "stale": {
+                    "description": "过旧的秘密数",
+                    "type": "integer"
+                },
+                "total_secrets": {
+                    "description": "秘密总数",
+                    "type": "integer"
+                },
+                "unchecked": {
+                    "description": "尚未计算指纹的密码数（需要重新扫描）",
+                    "type": "integer"
+                },
+                "weak": {
+                    "description": "弱密码数",
+                    "type": "integer"
+                }
+            }
+        },
         "github_com_cuihe500_vaulthub_internal_service.VerificationPurpose": {
             "type": "string",
             "enum": [
@@ -3483,6 +10191,216 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_cuihe500_vaulthub_internal_service.WebAuthnAssertionResponse": {
+            "type": "object",
+            "properties": {
+                "options": {
+                    "description": "传给 navigator.credentials.get() 的参数",
+                    "type": "object"
+                },
+                "session_token": {
+                    "description": "无密码登录时提交验证结果需要携带，5分钟内有效",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.WebAuthnCredentialListResponse": {
+            "type": "object",
+            "properties": {
+                "credentials": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cuihe500_vaulthub_internal_database_models.WebAuthnCredential"
+                    }
+                },
+                "step_up_required": {
+                    "description": "当前角色访问秘密前是否必须二次验证",
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.WebAuthnCredentialNameRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.WebAuthnCredentialRequest": {
+            "type": "object",
+            "required": [
+                "credential"
+            ],
+            "properties": {
+                "credential": {
+                    "type": "object"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.WebAuthnLoginRequest": {
+            "type": "object",
+            "required": [
+                "credential",
+                "session_token"
+            ],
+            "properties": {
+                "credential": {
+                    "type": "object"
+                },
+                "device_label": {
+                    "description": "设备名称（可选），显示在会话列表中",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "session_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_internal_service.WebAuthnRegistrationResponse": {
+            "type": "object",
+            "properties": {
+                "options": {
+                    "description": "传给 navigator.credentials.create() 的参数",
+                    "type": "object"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_pkg_backup.File": {
+            "type": "object",
+            "properties": {
+                "cipher": {
+                    "type": "string"
+                },
+                "ciphertext": {
+                    "description": "密文+认证标签（base64）",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "kdf": {
+                    "$ref": "#/definitions/github_com_cuihe500_vaulthub_pkg_backup.KDFParams"
+                },
+                "nonce": {
+                    "description": "AES-GCM Nonce（base64）",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_pkg_backup.KDFParams": {
+            "type": "object",
+            "properties": {
+                "memory": {
+                    "description": "内存消耗（KB）",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "固定为argon2id",
+                    "type": "string"
+                },
+                "salt": {
+                    "description": "随机盐值（base64）",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "threads": {
+                    "description": "并行度",
+                    "type": "integer"
+                },
+                "time": {
+                    "description": "迭代次数",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_pkg_importer.CSVMapping": {
+            "type": "object",
+            "required": [
+                "name",
+                "value"
+            ],
+            "properties": {
+                "description": {
+                    "description": "描述列",
+                    "type": "string"
+                },
+                "folder": {
+                    "description": "文件夹列",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "名称列",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "标签列（多个标签用\",\"或\";\"分隔）",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "类型列",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "地址列",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "用户名列",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "明文列",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_cuihe500_vaulthub_pkg_importer.Format": {
+            "type": "string",
+            "enum": [
+                "dotenv",
+                "csv",
+                "bitwarden",
+                "keepass"
+            ],
+            "x-enum-comments": {
+                "FormatBitwarden": "Bitwarden 未加密JSON导出",
+                "FormatCSV": "通用CSV（需要列映射）",
+                "FormatDotenv": ".env 文件（KEY=VALUE）",
+                "FormatKeePass": "KeePass 2.x XML导出"
+            },
+            "x-enum-descriptions": [
+                ".env 文件（KEY=VALUE）",
+                "通用CSV（需要列映射）",
+                "Bitwarden 未加密JSON导出",
+                "KeePass 2.x XML导出"
+            ],
+            "x-enum-varnames": [
+                "FormatDotenv",
+                "FormatCSV",
+                "FormatBitwarden",
+                "FormatKeePass"
+            ]
+        },
         "github_com_cuihe500_vaulthub_pkg_response.Response": {
             "type": "object",
             "properties": {
@@ -0,0 +1,98 @@
+package grpcserver
+
+import (
+	"context"
+
+	vaulthubv1 "github.com/cuihe500/vaulthub/api/proto/vaulthub/v1"
+	"github.com/cuihe500/vaulthub/internal/service"
+)
+
+// authServer 认证服务，与 handlers.AuthHandler 共用 service.AuthService
+type authServer struct {
+	vaulthubv1.UnimplementedAuthServiceServer
+	authService *service.AuthService
+}
+
+// Login 用户名密码登录
+func (s *authServer) Login(ctx context.Context, req *vaulthubv1.LoginRequest) (*vaulthubv1.LoginResponse, error) {
+	loginReq := &service.LoginRequest{
+		Username:      req.GetUsername(),
+		Password:      req.GetPassword(),
+		SessionClient: sessionClient(ctx, req.GetDeviceLabel()),
+	}
+	if err := validate(loginReq); err != nil {
+		return nil, err
+	}
+
+	// 公开方法没有认证用户，审计日志中记录登录的用户名
+	setAuditResource(ctx, "", loginReq.Username)
+
+	resp, err := s.authService.Login(loginReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.User != nil {
+		setAuditResource(ctx, resp.User.UUID, "")
+	}
+	return toProtoLoginResponse(resp), nil
+}
+
+// LoginWithMFA 两步验证登录第二步
+func (s *authServer) LoginWithMFA(ctx context.Context, req *vaulthubv1.LoginWithMFARequest) (*vaulthubv1.LoginResponse, error) {
+	loginReq := &service.LoginWithMFARequest{
+		MFAToken:      req.GetMfaToken(),
+		Code:          req.GetCode(),
+		SessionClient: sessionClient(ctx, req.GetDeviceLabel()),
+	}
+	if err := validate(loginReq); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.authService.LoginWithMFA(loginReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.User != nil {
+		setAuditResource(ctx, resp.User.UUID, resp.User.Username)
+	}
+	return toProtoLoginResponse(resp), nil
+}
+
+// RefreshToken 用刷新令牌换取新的访问令牌和刷新令牌
+func (s *authServer) RefreshToken(ctx context.Context, req *vaulthubv1.RefreshTokenRequest) (*vaulthubv1.LoginResponse, error) {
+	call := getCallInfo(ctx)
+	refreshReq := &service.RefreshTokenRequest{
+		RefreshToken: req.GetRefreshToken(),
+		IPAddress:    call.clientIP,
+		UserAgent:    call.userAgent,
+	}
+	if err := validate(refreshReq); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.authService.RefreshToken(refreshReq)
+	if err != nil {
+		return nil, err
+	}
+	setAuditResource(ctx, resp.User.UUID, resp.User.Username)
+	return toProtoLoginResponse(resp), nil
+}
+
+// Logout 注销当前会话
+func (s *authServer) Logout(ctx context.Context, _ *vaulthubv1.LogoutRequest) (*vaulthubv1.LogoutResponse, error) {
+	if err := s.authService.Logout(getCallInfo(ctx).token); err != nil {
+		return nil, err
+	}
+	return &vaulthubv1.LogoutResponse{}, nil
+}
+
+// sessionClient 登录会话的客户端信息，对应HTTP中的 setSessionClient
+func sessionClient(ctx context.Context, deviceLabel string) service.SessionClient {
+	call := getCallInfo(ctx)
+	return service.SessionClient{DeviceLabel: deviceLabel, IPAddress: call.clientIP, UserAgent: call.userAgent}
+}
+
+// GetMe 获取当前用户信息
+func (s *authServer) GetMe(ctx context.Context, _ *vaulthubv1.GetMeRequest) (*vaulthubv1.User, error) {
+	return toProtoUser(currentUser(ctx).ToSafeUser()), nil
+}
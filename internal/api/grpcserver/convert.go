@@ -0,0 +1,162 @@
+package grpcserver
+
+import (
+	"time"
+
+	vaulthubv1 "github.com/cuihe500/vaulthub/api/proto/vaulthub/v1"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/validator"
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// validate 使用与HTTP接口相同的binding规则校验service层请求
+func validate(req interface{}) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return errors.New(errors.CodeValidationFailed, validator.TranslateError(err))
+	}
+	return nil
+}
+
+// timestampOrNil 转换可为空的时间
+func timestampOrNil(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// toProtoUser 转换用户信息
+func toProtoUser(u *models.SafeUser) *vaulthubv1.User {
+	if u == nil {
+		return nil
+	}
+	return &vaulthubv1.User{
+		Id:          uint64(u.ID),
+		Uuid:        u.UUID,
+		Username:    u.Username,
+		Status:      int32(u.Status),
+		Role:        u.Role,
+		LastLoginAt: timestampOrNil(u.LastLoginAt),
+		CreatedAt:   timestamppb.New(u.CreatedAt),
+		UpdatedAt:   timestamppb.New(u.UpdatedAt),
+	}
+}
+
+// toProtoLoginResponse 转换登录响应
+func toProtoLoginResponse(resp *service.LoginResponse) *vaulthubv1.LoginResponse {
+	return &vaulthubv1.LoginResponse{
+		Token:                  resp.Token,
+		RefreshToken:           resp.RefreshToken,
+		ExpiresIn:              resp.ExpiresIn,
+		SessionUuid:            resp.SessionUUID,
+		User:                   toProtoUser(resp.User),
+		MfaRequired:            resp.MFARequired,
+		MfaToken:               resp.MFAToken,
+		MfaEnrollmentRequired:  resp.MFAEnrollmentRequired,
+		BackupCodes:            resp.BackupCodes,
+		PasswordChangeRequired: resp.PasswordChangeRequired,
+		PasswordChangeToken:    resp.PasswordChangeToken,
+	}
+}
+
+// toProtoMetadata 转换秘密元数据（密码生成规则和强度评分只在REST接口返回）
+func toProtoMetadata(m *models.SecretMetadata) *vaulthubv1.SecretMetadata {
+	if m == nil {
+		return nil
+	}
+	pm := &vaulthubv1.SecretMetadata{
+		ExpiresAt: timestampOrNil(m.ExpiresAt),
+		Tags:      m.Tags,
+		Folder:    m.Folder,
+	}
+	if len(m.Extra) > 0 {
+		// Extra中无法表示为JSON值的字段直接忽略
+		if extra, err := structpb.NewStruct(m.Extra); err == nil {
+			pm.Extra = extra
+		}
+	}
+	return pm
+}
+
+// fromProtoMetadata 转换请求中的秘密元数据
+func fromProtoMetadata(pm *vaulthubv1.SecretMetadata) *models.SecretMetadata {
+	if pm == nil {
+		return nil
+	}
+	m := &models.SecretMetadata{
+		Tags:   pm.GetTags(),
+		Folder: pm.GetFolder(),
+	}
+	if pm.GetExpiresAt() != nil {
+		expiresAt := pm.GetExpiresAt().AsTime()
+		m.ExpiresAt = &expiresAt
+	}
+	if pm.GetExtra() != nil {
+		m.Extra = pm.GetExtra().AsMap()
+	}
+	return m
+}
+
+// toProtoSecret 转换秘密信息
+func toProtoSecret(s *models.SafeEncryptedSecret) *vaulthubv1.Secret {
+	if s == nil {
+		return nil
+	}
+	return &vaulthubv1.Secret{
+		Id:               uint64(s.ID),
+		UserUuid:         s.UserUUID,
+		SecretUuid:       s.SecretUUID,
+		SecretName:       s.SecretName,
+		SecretType:       string(s.SecretType),
+		Description:      s.Description,
+		DekVersion:       int32(s.DEKVersion),
+		Metadata:         toProtoMetadata(s.Metadata),
+		CheckoutRequired: s.CheckoutRequired,
+		RotationRequired: s.RotationRequired,
+		ApprovalRequired: s.ApprovalRequired,
+		ValueChangedAt:   timestampOrNil(s.ValueChangedAt),
+		LastAccessedAt:   timestampOrNil(s.LastAccessedAt),
+		AccessCount:      s.AccessCount,
+		CreatedAt:        timestamppb.New(s.CreatedAt),
+		UpdatedAt:        timestamppb.New(s.UpdatedAt),
+	}
+}
+
+// toProtoKey 转换加密密钥信息
+func toProtoKey(k *models.SafeUserEncryptionKey) *vaulthubv1.EncryptionKey {
+	if k == nil {
+		return nil
+	}
+	return &vaulthubv1.EncryptionKey{
+		Id:                uint64(k.ID),
+		UserUuid:          k.UserUUID,
+		KekAlgorithm:      k.KEKAlgorithm,
+		DekVersion:        int32(k.DEKVersion),
+		DekAlgorithm:      k.DEKAlgorithm,
+		LastRotationAt:    timestampOrNil(k.LastRotationAt),
+		RotationStatus:    k.RotationStatus,
+		RotationStartedAt: timestampOrNil(k.RotationStartedAt),
+		CreatedAt:         timestamppb.New(k.CreatedAt),
+		UpdatedAt:         timestamppb.New(k.UpdatedAt),
+	}
+}
+
+// toProtoRotationStatus 转换密钥轮换进度
+func toProtoRotationStatus(t *service.MigrationTask) *vaulthubv1.RotationStatus {
+	return &vaulthubv1.RotationStatus{
+		UserUuid:        t.UserUUID,
+		OldVersion:      int32(t.OldVersion),
+		NewVersion:      int32(t.NewVersion),
+		TotalSecrets:    t.TotalSecrets,
+		MigratedSecrets: t.MigratedSecrets,
+		FailedSecrets:   t.FailedSecrets,
+		Status:          t.Status,
+		StartedAt:       timestamppb.New(t.StartedAt),
+		CompletedAt:     timestampOrNil(t.CompletedAt),
+		Error:           t.Error,
+	}
+}
@@ -0,0 +1,80 @@
+package grpcserver
+
+import (
+	"strconv"
+
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain 错误详情（google.rpc.ErrorInfo）中的domain
+const ErrorDomain = "vaulthub"
+
+// toStatus 把业务错误转换为gRPC状态
+// 状态码按错误类型映射，业务错误码和请求ID放在 google.rpc.ErrorInfo 详情中：
+// reason为业务错误码（如"40001"），metadata包含 code 和 request_id
+func toStatus(err error, requestID string) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	appErr, ok := err.(*errors.AppError)
+	if !ok {
+		logger.Error("gRPC方法执行失败", logger.String("request_id", requestID), logger.Err(err))
+		appErr = errors.New(errors.CodeInternalError, "")
+	}
+
+	st := status.New(grpcCode(appErr.Code), appErr.Message)
+	code := strconv.Itoa(appErr.Code)
+	detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: code,
+		Domain: ErrorDomain,
+		Metadata: map[string]string{
+			"code":       code,
+			"request_id": requestID,
+		},
+	})
+	if detailErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// grpcCode 业务错误码对应的gRPC状态码
+func grpcCode(code int) codes.Code {
+	switch code {
+	case errors.CodeResourceNotFound, errors.CodeResourceDeleted:
+		return codes.NotFound
+	case errors.CodeResourceAlreadyExists, errors.CodeNicknameExists, errors.CodeEmailExists, errors.CodeUsernameExists:
+		return codes.AlreadyExists
+	case errors.CodeTooManyRequests, errors.CodeQuotaExceeded:
+		return codes.ResourceExhausted
+	case errors.CodeInvalidVerificationCode, errors.CodeVerificationCodeExpired:
+		return codes.InvalidArgument
+	case errors.CodeServiceDegraded, errors.CodeServiceDown:
+		return codes.Unavailable
+	}
+
+	switch errors.GetErrorType(code) {
+	case errors.TypeValidation:
+		return codes.InvalidArgument
+	case errors.TypeAuthentication:
+		return codes.Unauthenticated
+	case errors.TypeAuthorization:
+		return codes.PermissionDenied
+	case errors.TypeResource:
+		return codes.FailedPrecondition
+	case errors.TypeSystem:
+		return codes.Internal
+	case errors.TypeExternal:
+		return codes.Unavailable
+	default:
+		return codes.Unknown
+	}
+}
@@ -0,0 +1,347 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	vaulthubv1 "github.com/cuihe500/vaulthub/api/proto/vaulthub/v1"
+	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/app"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// methodPolicy 单个gRPC方法的访问策略，与REST路由使用的中间件链一一对应
+type methodPolicy struct {
+	public      bool   // 无需认证（对应只挂审计和限流的公开路由）
+	rateLimit   bool   // 是否限流
+	resource    string // Casbin资源，为空时不检查权限
+	action      string // Casbin操作
+	securityPIN bool   // 是否要求已设置安全密码，同时按角色要求通行密钥二次验证（对应 SecureAuth* 链）
+	// secretScoped 方法自行按个人访问令牌的秘密范围检查（按请求中的秘密UUID或过滤结果），
+	// 否则限定了秘密范围的令牌不能调用secret资源的方法
+	secretScoped bool
+
+	auditAction   models.ActionType   // 审计操作类型
+	auditResource models.ResourceType // 审计资源类型
+}
+
+// methodPolicies 所有对外提供的gRPC方法及其访问策略
+// 未在此表中登记的方法一律拒绝，新增方法时必须同时登记策略
+var methodPolicies = map[string]methodPolicy{
+	// 认证：Login、LoginWithMFA、RefreshToken对应 publicChain + RateLimit，其余对应 AuthWithAudit
+	vaulthubv1.AuthService_Login_FullMethodName:        {public: true, rateLimit: true, auditAction: models.ActionLogin, auditResource: models.ResourceUser},
+	vaulthubv1.AuthService_LoginWithMFA_FullMethodName: {public: true, rateLimit: true, auditAction: models.ActionLogin, auditResource: models.ResourceUser},
+	vaulthubv1.AuthService_RefreshToken_FullMethodName: {public: true, rateLimit: true, auditAction: models.ActionTokenRefresh, auditResource: models.ResourceUser},
+	vaulthubv1.AuthService_Logout_FullMethodName:       {auditAction: models.ActionLogout, auditResource: models.ResourceUser},
+	vaulthubv1.AuthService_GetMe_FullMethodName:        {auditAction: models.ActionAccess, auditResource: models.ResourceUser},
+
+	// 秘密管理：对应 SecureAuthWithPermission(secret, ...)
+	vaulthubv1.SecretService_ListSecrets_FullMethodName:   {resource: middleware.ResourceSecret, action: middleware.ActionRead, securityPIN: true, secretScoped: true, auditAction: models.ActionAccess, auditResource: models.ResourceSecret},
+	vaulthubv1.SecretService_CreateSecret_FullMethodName:  {resource: middleware.ResourceSecret, action: middleware.ActionWrite, securityPIN: true, auditAction: models.ActionCreate, auditResource: models.ResourceSecret},
+	vaulthubv1.SecretService_DecryptSecret_FullMethodName: {resource: middleware.ResourceSecret, action: middleware.ActionRead, securityPIN: true, secretScoped: true, auditAction: models.ActionAccess, auditResource: models.ResourceSecret},
+	vaulthubv1.SecretService_DeleteSecret_FullMethodName:  {resource: middleware.ResourceSecret, action: middleware.ActionWrite, securityPIN: true, secretScoped: true, auditAction: models.ActionDelete, auditResource: models.ResourceSecret},
+
+	// 密钥管理：对应 AuthWithPermission(key, ...)
+	vaulthubv1.KeyService_CreateKey_FullMethodName:         {resource: middleware.ResourceKey, action: middleware.ActionWrite, auditAction: models.ActionCreate, auditResource: models.ResourceVault},
+	vaulthubv1.KeyService_VerifyRecoveryKey_FullMethodName: {resource: middleware.ResourceKey, action: middleware.ActionRead, auditAction: models.ActionAccess, auditResource: models.ResourceVault},
+	vaulthubv1.KeyService_RotateKey_FullMethodName:         {resource: middleware.ResourceKey, action: middleware.ActionWrite, auditAction: models.ActionUpdate, auditResource: models.ResourceVault},
+	vaulthubv1.KeyService_GetRotationStatus_FullMethodName: {resource: middleware.ResourceKey, action: middleware.ActionRead, auditAction: models.ActionAccess, auditResource: models.ResourceVault},
+	vaulthubv1.KeyService_WatchRotation_FullMethodName:     {resource: middleware.ResourceKey, action: middleware.ActionRead, auditAction: models.ActionAccess, auditResource: models.ResourceVault},
+}
+
+// callInfo 单次调用的上下文信息，由拦截器写入context
+type callInfo struct {
+	requestID string
+	clientIP  string
+	userAgent string
+	token     string
+	user      *models.User
+	// accessToken 使用个人访问令牌认证时的令牌，使用登录令牌时为nil
+	accessToken *models.PersonalAccessToken
+	audit       auditInfo
+}
+
+// auditInfo 业务方法补充的审计信息，对应HTTP中的 SetAuditResource/SetAuditDetails
+type auditInfo struct {
+	resourceUUID string
+	resourceName string
+	details      interface{}
+}
+
+type callInfoKey struct{}
+
+// getCallInfo 从context获取调用信息
+func getCallInfo(ctx context.Context) *callInfo {
+	if info, ok := ctx.Value(callInfoKey{}).(*callInfo); ok {
+		return info
+	}
+	return &callInfo{}
+}
+
+// currentUser 返回当前认证用户，公开方法中为nil
+func currentUser(ctx context.Context) *models.User {
+	return getCallInfo(ctx).user
+}
+
+// setAuditResource 设置审计资源信息
+func setAuditResource(ctx context.Context, resourceUUID, resourceName string) {
+	info := getCallInfo(ctx)
+	if resourceUUID != "" {
+		info.audit.resourceUUID = resourceUUID
+	}
+	if resourceName != "" {
+		info.audit.resourceName = resourceName
+	}
+}
+
+// setAuditDetails 设置审计详细信息
+func setAuditDetails(ctx context.Context, details interface{}) {
+	getCallInfo(ctx).audit.details = details
+}
+
+// interceptor 按方法策略依次执行 请求ID -> 限流 -> 认证 -> 审计 -> 权限 -> 安全密码检查 -> 二次验证
+// 与HTTP中间件链共用 middleware 包中的检查逻辑
+type interceptor struct {
+	mgr         *app.Manager
+	rateLimiter *middleware.RateLimiter
+	stepUp      *service.WebAuthnService
+}
+
+// newInterceptor 创建拦截器
+// stepUp: 服务容器中的WebAuthn服务，与REST接口共用同一实例
+func newInterceptor(mgr *app.Manager, stepUp *service.WebAuthnService) *interceptor {
+	return &interceptor{
+		mgr:         mgr,
+		rateLimiter: middleware.NewRateLimiter(mgr.Redis, mgr.ConfigManager),
+		stepUp:      stepUp,
+	}
+}
+
+// unary 一元调用拦截器
+func (i *interceptor) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	ctx, call := newCallInfo(ctx)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadataKey, call.requestID))
+	defer recoverPanic(info.FullMethod, &err)
+
+	err = i.call(ctx, info.FullMethod, call, func(ctx context.Context) error {
+		var handlerErr error
+		resp, handlerErr = handler(ctx, req)
+		return handlerErr
+	})
+	return resp, err
+}
+
+// stream 流式调用拦截器
+func (i *interceptor) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	ctx, call := newCallInfo(ss.Context())
+	_ = ss.SetHeader(metadata.Pairs(requestIDMetadataKey, call.requestID))
+	defer recoverPanic(info.FullMethod, &err)
+
+	return i.call(ctx, info.FullMethod, call, func(ctx context.Context) error {
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	})
+}
+
+// call 执行访问检查和审计，并调用业务方法
+func (i *interceptor) call(ctx context.Context, method string, call *callInfo, invoke func(context.Context) error) error {
+	policy, ok := methodPolicies[method]
+	if !ok {
+		logger.Warn("拒绝未登记的gRPC方法", logger.String("method", method))
+		return status.Error(codes.Unimplemented, "方法不存在")
+	}
+
+	// 限流
+	if policy.rateLimit && !i.rateLimiter.Allow(ctx, call.clientIP, method) {
+		return toStatus(errors.New(errors.CodeTooManyRequests, ""), call.requestID)
+	}
+
+	// 认证（与HTTP一致，认证失败的请求不进入审计）
+	if !policy.public {
+		token, appErr := middleware.ExtractBearerToken(firstMetadata(ctx, "authorization"))
+		if appErr != nil {
+			return toStatus(appErr, call.requestID)
+		}
+		if service.IsServiceAccountToken(token) {
+			// 服务账户只能通过HTTP的/api/v1/machine接口读取秘密
+			return toStatus(errors.New(errors.CodeForbidden, "服务账户令牌不能访问该接口"), call.requestID)
+		}
+		if service.IsPersonalAccessToken(token) {
+			// 与HTTP一致：个人访问令牌只能访问带权限检查的方法
+			if policy.resource == "" {
+				return toStatus(errors.New(errors.CodeForbidden, "个人访问令牌不能访问该接口"), call.requestID)
+			}
+			accessToken, user, appErr := middleware.AuthenticateAccessToken(i.mgr.DB, token, call.clientIP)
+			if appErr != nil {
+				return toStatus(appErr, call.requestID)
+			}
+			call.accessToken = accessToken
+			call.user = user
+		} else {
+			user, _, appErr := middleware.AuthenticateToken(i.mgr.JWT, i.mgr.DB, i.mgr.Redis, token)
+			if appErr != nil {
+				return toStatus(appErr, call.requestID)
+			}
+			call.user = user
+		}
+		call.token = token
+	}
+
+	// 审计：权限和安全密码检查失败也会记录
+	startTime := time.Now()
+	err := i.authorize(policy, call)
+	if err == nil {
+		err = invoke(ctx)
+	}
+	i.audit(method, policy, call, startTime, err)
+
+	if err != nil {
+		return toStatus(err, call.requestID)
+	}
+	return nil
+}
+
+// authorize 权限、安全密码和二次验证检查
+func (i *interceptor) authorize(policy methodPolicy, call *callInfo) error {
+	if call.user == nil {
+		return nil
+	}
+	if policy.resource != "" {
+		if appErr := middleware.CheckPermission(i.mgr.Enforcer, call.user.Role, policy.resource, policy.action); appErr != nil {
+			return appErr
+		}
+		if call.accessToken != nil {
+			if appErr := middleware.CheckAccessTokenScope(call.accessToken, policy.resource, policy.action); appErr != nil {
+				return appErr
+			}
+			if policy.resource == middleware.ResourceSecret && !policy.secretScoped {
+				if appErr := middleware.CheckAccessTokenSecret(call.accessToken, ""); appErr != nil {
+					return appErr
+				}
+			}
+		}
+	}
+	if policy.securityPIN {
+		if appErr := middleware.CheckSecurityPIN(i.mgr.DB, call.user.UUID); appErr != nil {
+			return appErr
+		}
+		// 二次验证只能通过REST接口完成，完成后对同一令牌的gRPC调用同样有效
+		if appErr := middleware.CheckStepUp(i.stepUp, call.user, call.token); appErr != nil {
+			return appErr
+		}
+	}
+	return nil
+}
+
+// audit 异步写入审计日志
+func (i *interceptor) audit(method string, policy methodPolicy, call *callInfo, startTime time.Time, err error) {
+	auditLog := &models.AuditLog{
+		ActionType:   policy.auditAction,
+		ResourceType: policy.auditResource,
+		ResourceUUID: stringPtrOrNil(call.audit.resourceUUID),
+		ResourceName: stringPtrOrNil(call.audit.resourceName),
+		Status:       models.AuditSuccess,
+		IPAddress:    stringPtrOrNil(call.clientIP),
+		UserAgent:    stringPtrOrNil(call.userAgent),
+		RequestID:    stringPtrOrNil(call.requestID),
+		CreatedAt:    startTime.UTC(),
+	}
+	if call.user != nil {
+		auditLog.UserUUID = call.user.UUID
+		auditLog.Username = call.user.Username
+	}
+
+	// 审计详情中记录调用的gRPC方法，便于与HTTP请求区分
+	details := map[string]interface{}{"grpc_method": method}
+	if call.audit.details != nil {
+		details["details"] = call.audit.details
+	}
+	auditLog.Details = middleware.FormatAuditDetails(details, string(policy.auditAction))
+
+	if err != nil {
+		auditLog.Status = models.AuditFailed
+		code := errors.CodeInternalError
+		message := err.Error()
+		if appErr, ok := err.(*errors.AppError); ok {
+			code = appErr.Code
+			message = appErr.Message
+		}
+		if len(message) > 500 {
+			message = message[:500] + "..."
+		}
+		auditLog.ErrorCode = &code
+		auditLog.ErrorMessage = &message
+	}
+
+	i.mgr.AuditService.LogAsync(auditLog)
+}
+
+// requestIDMetadataKey 请求ID元数据键（gRPC元数据键为小写）
+var requestIDMetadataKey = strings.ToLower(response.RequestIDKey)
+
+// newCallInfo 提取请求ID、客户端地址和User-Agent，写入context
+func newCallInfo(ctx context.Context) (context.Context, *callInfo) {
+	call := &callInfo{
+		requestID: firstMetadata(ctx, requestIDMetadataKey),
+		userAgent: firstMetadata(ctx, "user-agent"),
+	}
+	if call.requestID == "" {
+		call.requestID = uuid.New().String()
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		call.clientIP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(call.clientIP); err == nil {
+			call.clientIP = host
+		}
+	}
+	return context.WithValue(ctx, callInfoKey{}, call), call
+}
+
+// firstMetadata 读取请求元数据中的第一个值
+func firstMetadata(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// recoverPanic 捕获业务方法中的panic，返回Internal错误
+func recoverPanic(method string, err *error) {
+	if r := recover(); r != nil {
+		logger.Error("gRPC方法发生panic",
+			logger.String("method", method),
+			logger.Any("panic", r),
+			logger.String("stack", string(debug.Stack())))
+		*err = status.Error(codes.Internal, errors.GetMessage(errors.CodeInternalError))
+	}
+}
+
+// wrappedStream 替换流的context，使流式方法能读取调用信息
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
+}
+
+// stringPtrOrNil 如果字符串为空返回nil，否则返回指针
+func stringPtrOrNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
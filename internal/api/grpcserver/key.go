@@ -0,0 +1,117 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	vaulthubv1 "github.com/cuihe500/vaulthub/api/proto/vaulthub/v1"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// rotationWatchInterval 推送轮换进度时查询进度的间隔
+const rotationWatchInterval = time.Second
+
+// keyServer 加密密钥管理服务，与 handlers.KeyManagementHandler 共用同一组service
+type keyServer struct {
+	vaulthubv1.UnimplementedKeyServiceServer
+	encryptionService  *service.EncryptionService
+	recoveryService    *service.RecoveryService
+	keyRotationService *service.KeyRotationService
+}
+
+// CreateKey 创建用户加密密钥
+func (s *keyServer) CreateKey(ctx context.Context, req *vaulthubv1.CreateKeyRequest) (*vaulthubv1.CreateKeyResponse, error) {
+	createReq := &service.CreateUserEncryptionKeyRequest{
+		UserUUID:    currentUser(ctx).UUID,
+		SecurityPIN: req.GetSecurityPin(),
+	}
+	if err := validate(createReq); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.encryptionService.CreateUserEncryptionKey(createReq)
+	if err != nil {
+		return nil, err
+	}
+	return &vaulthubv1.CreateKeyResponse{Key: toProtoKey(resp.UserEncryptionKey), RecoveryKey: resp.RecoveryKey}, nil
+}
+
+// VerifyRecoveryKey 验证恢复助记词
+func (s *keyServer) VerifyRecoveryKey(ctx context.Context, req *vaulthubv1.VerifyRecoveryKeyRequest) (*vaulthubv1.VerifyRecoveryKeyResponse, error) {
+	verifyReq := &service.VerifyRecoveryKeyRequest{
+		UserUUID:         currentUser(ctx).UUID,
+		RecoveryMnemonic: req.GetRecoveryMnemonic(),
+	}
+	if err := validate(verifyReq); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.recoveryService.VerifyRecoveryKey(verifyReq)
+	if err != nil {
+		return nil, err
+	}
+	return &vaulthubv1.VerifyRecoveryKeyResponse{Valid: resp.Valid, Message: resp.Message}, nil
+}
+
+// RotateKey 手动触发DEK轮换
+func (s *keyServer) RotateKey(ctx context.Context, req *vaulthubv1.RotateKeyRequest) (*vaulthubv1.RotateKeyResponse, error) {
+	rotateReq := &service.RotateDEKRequest{
+		UserUUID:    currentUser(ctx).UUID,
+		SecurityPIN: req.GetSecurityPin(),
+	}
+	if err := validate(rotateReq); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.keyRotationService.RotateDEK(rotateReq)
+	if err != nil {
+		return nil, err
+	}
+	return &vaulthubv1.RotateKeyResponse{Key: toProtoKey(resp.UserEncryptionKey), Message: resp.Message}, nil
+}
+
+// GetRotationStatus 查询密钥轮换进度
+func (s *keyServer) GetRotationStatus(ctx context.Context, _ *vaulthubv1.GetRotationStatusRequest) (*vaulthubv1.RotationStatus, error) {
+	task, err := s.keyRotationService.GetRotationStatus(currentUser(ctx).UUID)
+	if err != nil {
+		return nil, err
+	}
+	return toProtoRotationStatus(task), nil
+}
+
+// WatchRotation 持续推送密钥轮换进度
+// 先推送当前进度，之后每次进度变化时推送一次，轮换不在进行中时关闭流
+func (s *keyServer) WatchRotation(_ *vaulthubv1.WatchRotationRequest, stream vaulthubv1.KeyService_WatchRotationServer) error {
+	ctx := stream.Context()
+	userUUID := currentUser(ctx).UUID
+
+	ticker := time.NewTicker(rotationWatchInterval)
+	defer ticker.Stop()
+
+	var last *vaulthubv1.RotationStatus
+	for {
+		task, err := s.keyRotationService.GetRotationStatus(userUUID)
+		if err != nil {
+			return err
+		}
+
+		current := toProtoRotationStatus(task)
+		if last == nil || !proto.Equal(current, last) {
+			if err := stream.Send(current); err != nil {
+				return err
+			}
+			last = current
+		}
+		if current.GetStatus() != "running" {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package grpcserver
+
+import (
+	"context"
+
+	vaulthubv1 "github.com/cuihe500/vaulthub/api/proto/vaulthub/v1"
+	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+)
+
+// secretServer 秘密管理服务，与 handlers.SecretHandler 共用 service.EncryptionService
+type secretServer struct {
+	vaulthubv1.UnimplementedSecretServiceServer
+	encryptionService *service.EncryptionService
+}
+
+// ListSecrets 获取秘密列表
+func (s *secretServer) ListSecrets(ctx context.Context, req *vaulthubv1.ListSecretsRequest) (*vaulthubv1.ListSecretsResponse, error) {
+	listReq := &service.ListUserSecretsRequest{
+		UserUUID:   currentUser(ctx).UUID,
+		SecretType: models.SecretType(req.GetSecretType()),
+		Page:       int(req.GetPage()),
+		PageSize:   int(req.GetPageSize()),
+	}
+	// 限定了秘密范围的个人访问令牌只列出范围内的秘密
+	if accessToken := getCallInfo(ctx).accessToken; accessToken != nil {
+		listReq.SecretUUIDs = accessToken.SecretUUIDs
+	}
+	if err := validate(listReq); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.encryptionService.ListUserSecrets(listReq)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]*vaulthubv1.Secret, 0, len(resp.Secrets))
+	for _, secret := range resp.Secrets {
+		secrets = append(secrets, toProtoSecret(secret))
+	}
+	return &vaulthubv1.ListSecretsResponse{
+		Secrets:    secrets,
+		Total:      resp.Total,
+		Page:       int32(resp.Page),
+		PageSize:   int32(resp.PageSize),
+		TotalPages: int32(resp.TotalPages),
+	}, nil
+}
+
+// CreateSecret 加密并存储秘密
+func (s *secretServer) CreateSecret(ctx context.Context, req *vaulthubv1.CreateSecretRequest) (*vaulthubv1.Secret, error) {
+	createReq := &service.EncryptAndStoreSecretRequest{
+		UserUUID:    currentUser(ctx).UUID,
+		SecurityPIN: req.GetSecurityPin(),
+		SecretName:  req.GetSecretName(),
+		SecretType:  models.SecretType(req.GetSecretType()),
+		PlainData:   req.GetPlainData(),
+		Description: req.GetDescription(),
+		Metadata:    fromProtoMetadata(req.GetMetadata()),
+	}
+	if err := validate(createReq); err != nil {
+		return nil, err
+	}
+
+	secret, err := s.encryptionService.EncryptAndStoreSecret(createReq)
+	if err != nil {
+		return nil, err
+	}
+	setAuditResource(ctx, secret.SecretUUID, secret.SecretName)
+	return toProtoSecret(secret), nil
+}
+
+// DecryptSecret 解密秘密
+func (s *secretServer) DecryptSecret(ctx context.Context, req *vaulthubv1.DecryptSecretRequest) (*vaulthubv1.DecryptedSecret, error) {
+	if req.GetSecretUuid() == "" {
+		return nil, errors.New(errors.CodeMissingParam, "secret_uuid参数必填")
+	}
+	if appErr := middleware.CheckAccessTokenSecret(getCallInfo(ctx).accessToken, req.GetSecretUuid()); appErr != nil {
+		return nil, appErr
+	}
+	decryptReq := &service.DecryptSecretRequest{
+		UserUUID:    currentUser(ctx).UUID,
+		SecretUUID:  req.GetSecretUuid(),
+		SecurityPIN: req.GetSecurityPin(),
+	}
+	if err := validate(decryptReq); err != nil {
+		return nil, err
+	}
+	setAuditResource(ctx, decryptReq.SecretUUID, "")
+
+	resp, err := s.encryptionService.DecryptSecret(decryptReq)
+	if err != nil {
+		return nil, err
+	}
+	setAuditResource(ctx, "", resp.SecretName)
+
+	// 高敏感秘密的解密记录关联到对应的访问申请
+	if resp.AccessRequestUUID != "" {
+		setAuditDetails(ctx, map[string]interface{}{
+			"access_request_uuid": resp.AccessRequestUUID,
+		})
+	}
+
+	return &vaulthubv1.DecryptedSecret{
+		Secret:            toProtoSecret(&resp.SafeEncryptedSecret),
+		PlainData:         resp.PlainData,
+		AccessRequestUuid: resp.AccessRequestUUID,
+	}, nil
+}
+
+// DeleteSecret 删除秘密（进入回收站）
+func (s *secretServer) DeleteSecret(ctx context.Context, req *vaulthubv1.DeleteSecretRequest) (*vaulthubv1.DeleteSecretResponse, error) {
+	if req.GetSecretUuid() == "" {
+		return nil, errors.New(errors.CodeMissingParam, "secret_uuid参数必填")
+	}
+	if appErr := middleware.CheckAccessTokenSecret(getCallInfo(ctx).accessToken, req.GetSecretUuid()); appErr != nil {
+		return nil, appErr
+	}
+	setAuditResource(ctx, req.GetSecretUuid(), "")
+
+	if err := s.encryptionService.DeleteSecret(currentUser(ctx).UUID, req.GetSecretUuid()); err != nil {
+		return nil, err
+	}
+	return &vaulthubv1.DeleteSecretResponse{}, nil
+}
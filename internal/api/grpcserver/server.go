@@ -0,0 +1,61 @@
+// Package grpcserver 提供与 Gin REST API 并行的 gRPC 接口
+//
+// gRPC 接口与 REST 接口共用同一组 service 实例（routes.ServiceContainer），
+// 访问控制由拦截器按方法策略表执行，检查逻辑与HTTP中间件链共用 middleware 包中的实现。
+package grpcserver
+
+import (
+	"context"
+	"net"
+
+	vaulthubv1 "github.com/cuihe500/vaulthub/api/proto/vaulthub/v1"
+	"github.com/cuihe500/vaulthub/internal/api/routes"
+	"github.com/cuihe500/vaulthub/internal/app"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"google.golang.org/grpc"
+)
+
+// Server gRPC服务器
+type Server struct {
+	server *grpc.Server
+}
+
+// NewServer 创建gRPC服务器并注册认证、秘密和密钥管理服务
+func NewServer(mgr *app.Manager, svc *routes.ServiceContainer) *Server {
+	i := newInterceptor(mgr, svc.WebAuthn)
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(i.unary),
+		grpc.ChainStreamInterceptor(i.stream),
+	)
+
+	vaulthubv1.RegisterAuthServiceServer(server, &authServer{authService: svc.Auth})
+	vaulthubv1.RegisterSecretServiceServer(server, &secretServer{encryptionService: svc.Encryption})
+	vaulthubv1.RegisterKeyServiceServer(server, &keyServer{
+		encryptionService:  svc.Encryption,
+		recoveryService:    svc.Recovery,
+		keyRotationService: svc.KeyRotation,
+	})
+
+	return &Server{server: server}
+}
+
+// Serve 在监听器上处理请求，阻塞直到服务器停止
+func (s *Server) Serve(lis net.Listener) error {
+	return s.server.Serve(lis)
+}
+
+// Shutdown 优雅关闭：停止接收新请求并等待进行中的请求完成，ctx超时后强制关闭
+func (s *Server) Shutdown(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		logger.Warn("gRPC服务器优雅关闭超时，强制关闭")
+		s.server.Stop()
+	}
+}
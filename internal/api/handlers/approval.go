@@ -0,0 +1,453 @@
+package handlers
+
+import (
+	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/cuihe500/vaulthub/pkg/validator"
+	"github.com/gin-gonic/gin"
+)
+
+// ApprovalHandler 访问审批处理器
+type ApprovalHandler struct {
+	approvalService *service.ApprovalService
+}
+
+// NewApprovalHandler 创建访问审批处理器实例
+func NewApprovalHandler(approvalService *service.ApprovalService) *ApprovalHandler {
+	return &ApprovalHandler{
+		approvalService: approvalService,
+	}
+}
+
+// respondApprovalError 输出审批相关接口的错误响应
+func respondApprovalError(c *gin.Context, err error, message string) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		response.AppError(c, appErr)
+		return
+	}
+	logger.Error(message, logger.Err(err))
+	response.InternalError(c, message)
+}
+
+// SetApprovalPolicy 设置审批策略
+// @Summary 设置审批策略
+// @Description 标记为高敏感秘密后，解密前必须提交访问申请并由其他审批人批准
+// @Description 关闭审批同样需要持有已批准且仍在有效期内的访问申请
+// @Tags 访问审批
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "秘密UUID"
+// @Param request body service.SetApprovalPolicyRequest true "审批策略"
+// @Success 200 {object} response.Response{data=github_com_cuihe500_vaulthub_internal_database_models.SafeEncryptedSecret}
+// @Router /api/v1/secrets/{uuid}/approval-policy [put]
+func (h *ApprovalHandler) SetApprovalPolicy(c *gin.Context) {
+	// 获取当前用户UUID
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	secretUUID := c.Param("uuid")
+	if secretUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	var req service.SetApprovalPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("设置审批策略请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.UserUUID = userUUID
+	req.SecretUUID = secretUUID
+
+	middleware.SetAuditAction(c, models.ActionUpdate)
+	middleware.SetAuditResource(c, models.ResourceSecret, secretUUID, "")
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation":         "set_approval_policy",
+		"approval_required": req.ApprovalRequired,
+	})
+
+	resp, err := h.approvalService.SetApprovalPolicy(&req)
+	if err != nil {
+		respondApprovalError(c, err, "设置审批策略失败")
+		return
+	}
+
+	middleware.SetAuditResource(c, models.ResourceSecret, resp.SecretUUID, resp.SecretName)
+	response.Success(c, resp)
+}
+
+// CreateAccessRequest 提交访问申请
+// @Summary 提交访问申请
+// @Description 申请解密高敏感秘密，系统会通过邮件通知审批人；批准后在时间窗口内可以解密
+// @Tags 访问审批
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "秘密UUID"
+// @Param request body service.CreateAccessRequestRequest true "访问申请"
+// @Success 200 {object} response.Response{data=service.CreateAccessRequestResponse}
+// @Router /api/v1/secrets/{uuid}/access-requests [post]
+func (h *ApprovalHandler) CreateAccessRequest(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	secretUUID := c.Param("uuid")
+	if secretUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	var req service.CreateAccessRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("提交访问申请请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.UserUUID = user.UUID
+	req.Username = user.Username
+	req.SecretUUID = secretUUID
+
+	middleware.SetAuditAction(c, models.ActionCreate)
+	middleware.SetAuditResource(c, models.ResourceAccessRequest, "", "")
+
+	resp, err := h.approvalService.CreateAccessRequest(&req)
+	if err != nil {
+		respondApprovalError(c, err, "提交访问申请失败")
+		return
+	}
+
+	middleware.SetAuditResource(c, models.ResourceAccessRequest, resp.Request.RequestUUID, "")
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"secret_uuid":    secretUUID,
+		"reason":         req.Reason,
+		"window_minutes": resp.Request.WindowMinutes,
+		"approvers":      resp.Approvers,
+	})
+	response.Success(c, resp)
+}
+
+// ListMyAccessRequests 查询我的访问申请
+// @Summary 查询我的访问申请
+// @Description 分页查询当前用户提交的访问申请
+// @Tags 访问审批
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "申请状态" Enums(pending, approved, denied, expired, cancelled)
+// @Param page query int false "页码" minimum(1)
+// @Param page_size query int false "每页数量" minimum(1) maximum(100)
+// @Success 200 {object} response.Response{data=service.ListAccessRequestsResponse}
+// @Router /api/v1/access-requests [get]
+func (h *ApprovalHandler) ListMyAccessRequests(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.ListAccessRequestsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("查询访问申请请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.UserUUID = userUUID
+
+	resp, err := h.approvalService.ListMyRequests(&req)
+	if err != nil {
+		respondApprovalError(c, err, "查询访问申请失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// ListPendingApprovals 查询待我审批的申请
+// @Summary 查询待我审批的申请
+// @Description 分页查询当前用户有权审批且尚未处理的访问申请
+// @Tags 访问审批
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" minimum(1)
+// @Param page_size query int false "每页数量" minimum(1) maximum(100)
+// @Success 200 {object} response.Response{data=service.ListAccessRequestsResponse}
+// @Router /api/v1/access-requests/pending [get]
+func (h *ApprovalHandler) ListPendingApprovals(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+	role, _ := middleware.GetCurrentUserRole(c)
+
+	var req service.ListAccessRequestsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("查询待审批申请请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.UserUUID = userUUID
+	req.Role = role
+
+	resp, err := h.approvalService.ListPendingApprovals(&req)
+	if err != nil {
+		respondApprovalError(c, err, "查询待审批申请失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// ApproveAccessRequest 批准访问申请
+// @Summary 批准访问申请
+// @Description 批准后申请人在申请的时间窗口内可以解密秘密；申请人本人不能审批
+// @Tags 访问审批
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "申请UUID"
+// @Param request body service.DecideAccessRequestRequest false "审批意见"
+// @Success 200 {object} response.Response{data=github_com_cuihe500_vaulthub_internal_database_models.SecretAccessRequest}
+// @Router /api/v1/access-requests/{uuid}/approve [post]
+func (h *ApprovalHandler) ApproveAccessRequest(c *gin.Context) {
+	h.decide(c, true)
+}
+
+// DenyAccessRequest 拒绝访问申请
+// @Summary 拒绝访问申请
+// @Description 拒绝后申请人需要重新提交申请
+// @Tags 访问审批
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "申请UUID"
+// @Param request body service.DecideAccessRequestRequest false "审批意见"
+// @Success 200 {object} response.Response{data=github_com_cuihe500_vaulthub_internal_database_models.SecretAccessRequest}
+// @Router /api/v1/access-requests/{uuid}/deny [post]
+func (h *ApprovalHandler) DenyAccessRequest(c *gin.Context) {
+	h.decide(c, false)
+}
+
+// decide 批准或拒绝访问申请
+func (h *ApprovalHandler) decide(c *gin.Context, approve bool) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	requestUUID := c.Param("uuid")
+	if requestUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	// 审批意见可选，允许空请求体
+	var req service.DecideAccessRequestRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Warn("审批访问申请请求参数无效", logger.Err(err))
+			response.ValidationError(c, validator.TranslateError(err))
+			return
+		}
+	}
+	req.ApproverUUID = user.UUID
+	req.ApproverUsername = user.Username
+	req.RequestUUID = requestUUID
+	req.Approve = approve
+
+	action := models.ActionDeny
+	if approve {
+		action = models.ActionApprove
+	}
+	middleware.SetAuditAction(c, action)
+	middleware.SetAuditResource(c, models.ResourceAccessRequest, requestUUID, "")
+
+	resp, err := h.approvalService.DecideAccessRequest(&req)
+	if err != nil {
+		respondApprovalError(c, err, "审批访问申请失败")
+		return
+	}
+
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"channel":     "api",
+		"secret_uuid": resp.SecretUUID,
+		"requester":   resp.RequesterUsername,
+		"comment":     req.Comment,
+	})
+	response.Success(c, resp)
+}
+
+// CancelAccessRequest 撤回访问申请
+// @Summary 撤回访问申请
+// @Description 撤回待审批的申请；已批准的申请撤回后立即结束解密窗口
+// @Tags 访问审批
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "申请UUID"
+// @Success 200 {object} response.Response{data=github_com_cuihe500_vaulthub_internal_database_models.SecretAccessRequest}
+// @Router /api/v1/access-requests/{uuid}/cancel [post]
+func (h *ApprovalHandler) CancelAccessRequest(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	requestUUID := c.Param("uuid")
+	if requestUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	middleware.SetAuditAction(c, models.ActionUpdate)
+	middleware.SetAuditResource(c, models.ResourceAccessRequest, requestUUID, "")
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation": "cancel",
+	})
+
+	resp, err := h.approvalService.CancelAccessRequest(userUUID, requestUUID)
+	if err != nil {
+		respondApprovalError(c, err, "撤回访问申请失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// GetEmailApproval 查询邮件审批链接对应的申请
+// @Summary 查询邮件审批链接对应的申请
+// @Description 无需登录，根据邮件中的审批令牌查询申请信息，不消耗令牌
+// @Tags 访问审批
+// @Produce json
+// @Param token query string true "审批令牌"
+// @Success 200 {object} response.Response{data=service.EmailApprovalInfo}
+// @Router /api/v1/access-requests/email-decision [get]
+func (h *ApprovalHandler) GetEmailApproval(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		response.MissingParam(c, "token参数必填")
+		return
+	}
+
+	resp, err := h.approvalService.GetEmailApproval(token)
+	if err != nil {
+		respondApprovalError(c, err, "查询访问申请失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// DecideByEmail 通过邮件链接审批
+// @Summary 通过邮件链接审批
+// @Description 无需登录，使用邮件中的一次性审批令牌批准或拒绝申请，审计日志记录为令牌对应的审批人
+// @Tags 访问审批
+// @Accept json
+// @Produce json
+// @Param request body service.EmailDecisionRequest true "审批结果"
+// @Success 200 {object} response.Response{data=github_com_cuihe500_vaulthub_internal_database_models.SecretAccessRequest}
+// @Router /api/v1/access-requests/email-decision [post]
+func (h *ApprovalHandler) DecideByEmail(c *gin.Context) {
+	var req service.EmailDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("邮件审批请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+	req.RequestID = c.GetString(response.RequestIDKey)
+
+	resp, err := h.approvalService.DecideByEmail(&req)
+	if err != nil {
+		respondApprovalError(c, err, "审批访问申请失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// GetVaultApprovers 查询保险库审批人
+// @Summary 查询保险库审批人
+// @Description 查询当前用户保险库指定的审批人，未指定时使用默认审批角色
+// @Tags 访问审批
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=service.VaultApproversResponse}
+// @Router /api/v1/vault/approvers [get]
+func (h *ApprovalHandler) GetVaultApprovers(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	resp, err := h.approvalService.GetVaultApprovers(userUUID)
+	if err != nil {
+		respondApprovalError(c, err, "查询保险库审批人失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// SetVaultApprovers 设置保险库审批人
+// @Summary 设置保险库审批人
+// @Description 替换当前用户保险库的审批人，传空列表时恢复使用默认审批角色
+// @Tags 访问审批
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.SetVaultApproversRequest true "审批人"
+// @Success 200 {object} response.Response{data=service.VaultApproversResponse}
+// @Router /api/v1/vault/approvers [put]
+func (h *ApprovalHandler) SetVaultApprovers(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.SetVaultApproversRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("设置保险库审批人请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.OwnerUUID = userUUID
+
+	middleware.SetAuditAction(c, models.ActionUpdate)
+	middleware.SetAuditResource(c, models.ResourceVault, userUUID, "")
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation": "set_approvers",
+		"approvers": req.Usernames,
+	})
+
+	resp, err := h.approvalService.SetVaultApprovers(&req)
+	if err != nil {
+		respondApprovalError(c, err, "设置保险库审批人失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
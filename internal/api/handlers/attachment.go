@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/cuihe500/vaulthub/pkg/validator"
+	"github.com/gin-gonic/gin"
+)
+
+// multipart表单中除文件以外内容的大小上限（字段、分隔符和头部）
+const attachmentFormOverhead = 1 << 20
+
+// AttachmentHandler 秘密附件处理器
+type AttachmentHandler struct {
+	attachmentService *service.AttachmentService
+}
+
+// NewAttachmentHandler 创建附件处理器实例
+func NewAttachmentHandler(attachmentService *service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+// UploadAttachment 上传附件
+// @Summary 上传附件
+// @Description 为秘密上传文件附件（如keystore、kubeconfig），文件分块加密后保存（需要输入安全密码）
+// @Description 表单字段 security_pin 必须位于 file 之前，服务端边接收边加密，不缓存完整文件
+// @Description 单个附件大小和每个用户的总容量由系统配置 attachment_max_file_size_mb、attachment_user_quota_mb 限制
+// @Tags 秘密管理
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "秘密UUID"
+// @Param security_pin formData string true "安全密码"
+// @Param file formData file true "附件文件"
+// @Success 200 {object} response.Response{data=models.SecretAttachment}
+// @Router /api/v1/secrets/{uuid}/attachments [post]
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	// 获取当前用户UUID
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	secretUUID := c.Param("uuid")
+	if secretUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	middleware.SetAuditAction(c, models.ActionCreate)
+	middleware.SetAuditResource(c, models.ResourceSecret, secretUUID, "")
+
+	// 限制请求体大小，超出时读取会返回错误（服务层还会按配额精确限制文件大小）
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.attachmentService.MaxFileSize()+attachmentFormOverhead)
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		response.InvalidParam(c, "请求必须是multipart/form-data格式")
+		return
+	}
+
+	req := &service.UploadAttachmentRequest{
+		UserUUID:   userUUID,
+		SecretUUID: secretUUID,
+	}
+	// 依次读取表单字段，处理完第一个file字段后结束（之后的内容忽略）
+	var attachment *models.SecretAttachment
+	for attachment == nil {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			logger.Warn("读取上传表单失败", logger.Err(err))
+			response.InvalidParam(c, "读取上传表单失败")
+			return
+		}
+
+		switch part.FormName() {
+		case "security_pin":
+			pin, err := io.ReadAll(io.LimitReader(part, 256))
+			if err != nil {
+				response.InvalidParam(c, "读取安全密码失败")
+				return
+			}
+			req.SecurityPIN = string(pin)
+		case "file":
+			if req.SecurityPIN == "" {
+				response.ValidationError(c, "security_pin必填，且必须位于file之前")
+				return
+			}
+			req.FileName = sanitizeFileName(part.FileName())
+			req.ContentType = attachmentContentType(part.Header.Get("Content-Type"), req.FileName)
+			req.Content = part
+
+			attachment, err = h.attachmentService.UploadAttachment(c.Request.Context(), req)
+			if err != nil {
+				if appErr, ok := err.(*errors.AppError); ok {
+					response.AppError(c, appErr)
+				} else {
+					logger.Error("上传附件失败", logger.Err(err))
+					response.InternalError(c, "上传附件失败")
+				}
+				return
+			}
+		}
+		_ = part.Close()
+	}
+
+	if attachment == nil {
+		response.ValidationError(c, "file必填")
+		return
+	}
+
+	middleware.SetAuditResource(c, models.ResourceSecret, secretUUID, attachment.FileName)
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation":       "upload_attachment",
+		"attachment_uuid": attachment.AttachmentUUID,
+		"size":            attachment.Size,
+	})
+
+	response.Success(c, attachment)
+}
+
+// ListAttachments 获取附件列表
+// @Summary 获取附件列表
+// @Description 获取秘密的附件列表及当前用户的附件容量使用情况
+// @Tags 秘密管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "秘密UUID"
+// @Success 200 {object} response.Response{data=service.ListAttachmentsResponse}
+// @Router /api/v1/secrets/{uuid}/attachments [get]
+func (h *AttachmentHandler) ListAttachments(c *gin.Context) {
+	// 获取当前用户UUID
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	secretUUID := c.Param("uuid")
+	if secretUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	resp, err := h.attachmentService.ListAttachments(userUUID, secretUUID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("获取附件列表失败", logger.Err(err))
+			response.InternalError(c, "获取附件列表失败")
+		}
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// DownloadAttachment 下载附件
+// @Summary 下载附件
+// @Description 验证安全密码后流式解密并下载附件（需要输入安全密码）
+// @Description 成功时直接返回文件内容；内容在传输过程中逐块校验，发现篡改会中断连接
+// @Tags 秘密管理
+// @Accept json
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param uuid path string true "秘密UUID"
+// @Param attachment_uuid path string true "附件UUID"
+// @Param request body service.DownloadAttachmentRequest true "下载请求"
+// @Success 200 {file} file "附件内容"
+// @Router /api/v1/secrets/{uuid}/attachments/{attachment_uuid}/download [post]
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	// 获取当前用户UUID
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	secretUUID := c.Param("uuid")
+	attachmentUUID := c.Param("attachment_uuid")
+	if secretUUID == "" || attachmentUUID == "" {
+		response.MissingParam(c, "uuid和attachment_uuid参数必填")
+		return
+	}
+
+	var req service.DownloadAttachmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("下载附件请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	// 使用当前用户的UUID（防止用户伪造其他用户的UUID）
+	req.UserUUID = userUUID
+	req.SecretUUID = secretUUID
+	req.AttachmentUUID = attachmentUUID
+
+	middleware.SetAuditAction(c, models.ActionAccess)
+	middleware.SetAuditResource(c, models.ResourceSecret, secretUUID, "")
+
+	attachment, content, err := h.attachmentService.OpenAttachment(c.Request.Context(), &req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("下载附件失败", logger.Err(err))
+			response.InternalError(c, "下载附件失败")
+		}
+		return
+	}
+	defer func() {
+		_ = content.Close()
+	}()
+
+	middleware.SetAuditResource(c, models.ResourceSecret, secretUUID, attachment.FileName)
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation":       "download_attachment",
+		"attachment_uuid": attachment.AttachmentUUID,
+	})
+
+	c.Header("Content-Disposition", contentDisposition(attachment.FileName))
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("X-Checksum-SHA256", attachment.ChecksumSHA256)
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("Content-Length", fmt.Sprintf("%d", attachment.Size))
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, content); err != nil {
+		// 响应头已经发出，只能中断传输；客户端会因内容长度不足而感知失败
+		logger.Error("传输附件失败", logger.Err(err), logger.String("attachment_uuid", attachment.AttachmentUUID))
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
+
+// DeleteAttachment 删除附件
+// @Summary 删除附件
+// @Description 彻底删除秘密的指定附件
+// @Tags 秘密管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "秘密UUID"
+// @Param attachment_uuid path string true "附件UUID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/secrets/{uuid}/attachments/{attachment_uuid} [delete]
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	// 获取当前用户UUID
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	secretUUID := c.Param("uuid")
+	attachmentUUID := c.Param("attachment_uuid")
+	if secretUUID == "" || attachmentUUID == "" {
+		response.MissingParam(c, "uuid和attachment_uuid参数必填")
+		return
+	}
+
+	middleware.SetAuditAction(c, models.ActionDelete)
+	middleware.SetAuditResource(c, models.ResourceSecret, secretUUID, "")
+
+	attachment, err := h.attachmentService.DeleteAttachment(c.Request.Context(), userUUID, secretUUID, attachmentUUID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("删除附件失败", logger.Err(err))
+			response.InternalError(c, "删除附件失败")
+		}
+		return
+	}
+
+	middleware.SetAuditResource(c, models.ResourceSecret, secretUUID, attachment.FileName)
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation":       "delete_attachment",
+		"attachment_uuid": attachment.AttachmentUUID,
+	})
+
+	response.Success(c, nil)
+}
+
+// sanitizeFileName 只保留文件名部分，去掉路径和控制字符
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if len(name) > 255 {
+		name = name[:255]
+	}
+	return name
+}
+
+// attachmentContentType 优先使用客户端声明的类型，否则按扩展名推断
+func attachmentContentType(declared, fileName string) string {
+	if mediaType, _, err := mime.ParseMediaType(declared); err == nil && len(mediaType) <= 128 {
+		return mediaType
+	}
+	if byExt := mime.TypeByExtension(filepath.Ext(fileName)); byExt != "" {
+		if mediaType, _, err := mime.ParseMediaType(byExt); err == nil {
+			return mediaType
+		}
+	}
+	return "application/octet-stream"
+}
+
+// contentDisposition 构造附件下载头，非ASCII文件名使用RFC 5987编码
+func contentDisposition(fileName string) string {
+	ascii := strings.Map(func(r rune) rune {
+		if r > 0x7e || r == '"' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, fileName)
+	return fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`, ascii, url.PathEscape(fileName))
+}
@@ -30,6 +30,12 @@ func NewAuthHandler(authService *service.AuthService, recoveryService *service.R
 	}
 }
 
+// setSessionClient 记录创建登录会话的客户端地址和User-Agent
+func setSessionClient(c *gin.Context, client *service.SessionClient) {
+	client.IPAddress = c.ClientIP()
+	client.UserAgent = c.Request.UserAgent()
+}
+
 // Register 用户注册
 // @Summary 用户注册
 // @Description 注册新用户账号,所有新用户默认为普通用户角色,只有管理员才能提权
@@ -63,7 +69,10 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login 用户登录
 // @Summary 用户登录
-// @Description 用户登录获取JWT token
+// @Description 用户登录获取访问令牌和刷新令牌，每次登录创建独立的会话，不影响其他设备上的会话。
+// @Description 启用或被要求启用两步验证时返回 mfa_required 和 mfa_token，需要再调用 /api/v1/auth/login/mfa 完成登录。
+// @Description 密码超过 password_max_age_days 时返回 password_change_required 和 password_change_token，需要先调用 /api/v1/auth/login/password-change 修改密码；需要两步验证时先返回 mfa_token，验证通过后才返回修改密码的令牌。
+// @Description 同一账户或IP连续登录失败后需要等待递增的时间（返回 40011），账户失败次数达到 login_lockout_threshold 时自动锁定并邮件通知用户
 // @Tags 认证
 // @Accept json
 // @Produce json
@@ -77,6 +86,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		response.ValidationError(c, validator.TranslateError(err))
 		return
 	}
+	setSessionClient(c, &req.SessionClient)
 
 	resp, err := h.authService.Login(&req)
 	if err != nil {
@@ -108,6 +118,7 @@ func (h *AuthHandler) LoginWithEmail(c *gin.Context) {
 		response.ValidationError(c, validator.TranslateError(err))
 		return
 	}
+	setSessionClient(c, &req.SessionClient)
 
 	resp, err := h.authService.LoginWithEmail(&req)
 	if err != nil {
@@ -123,6 +134,212 @@ func (h *AuthHandler) LoginWithEmail(c *gin.Context) {
 	response.Success(c, resp)
 }
 
+// LoginWithMFA 两步验证登录
+// @Summary 两步验证登录
+// @Description 登录第二步：提交登录接口返回的 mfa_token 和认证器验证码（或备用码）换取JWT token。
+// @Description 角色被要求启用两步验证但尚未绑定时，先调用 /api/v1/auth/login/mfa/enroll 绑定认证器，此时验证码用于确认绑定，响应中附带备用码。
+// @Description 登录时密码已过期的，验证通过后返回 password_change_required 和 password_change_token，需要再调用 /api/v1/auth/login/password-change 修改密码
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body service.LoginWithMFARequest true "两步验证登录请求"
+// @Success 200 {object} response.Response{data=service.LoginResponse}
+// @Router /api/v1/auth/login/mfa [post]
+func (h *AuthHandler) LoginWithMFA(c *gin.Context) {
+	var req service.LoginWithMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("两步验证登录请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	setSessionClient(c, &req.SessionClient)
+
+	middleware.SetAuditAction(c, models.ActionLogin)
+	middleware.SetAuditResource(c, models.ResourceUser, "", "")
+
+	resp, err := h.authService.LoginWithMFA(&req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("两步验证登录失败", logger.Err(err))
+			response.InternalError(c, "登录失败")
+		}
+		return
+	}
+	if resp.User != nil {
+		middleware.SetAuditResource(c, models.ResourceUser, resp.User.UUID, resp.User.Username)
+	}
+	if len(resp.BackupCodes) > 0 {
+		middleware.SetAuditDetails(c, map[string]interface{}{
+			"operation": "mfa_enroll_on_login",
+		})
+	}
+
+	response.Success(c, resp)
+}
+
+// ChangeExpiredPassword 修改过期密码并继续登录
+// @Summary 修改过期密码并继续登录
+// @Description 密码超过系统配置的最长使用期限时，登录接口返回 password_change_required 和 password_change_token。
+// @Description 需要两步验证的用户在验证通过后才会拿到该令牌。提交令牌和符合密码策略的新密码后直接返回JWT token，修改后该用户其他设备上的登录会话全部失效
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body service.ChangeExpiredPasswordRequest true "修改过期密码请求"
+// @Success 200 {object} response.Response{data=service.LoginResponse}
+// @Router /api/v1/auth/login/password-change [post]
+func (h *AuthHandler) ChangeExpiredPassword(c *gin.Context) {
+	var req service.ChangeExpiredPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("修改过期密码请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	setSessionClient(c, &req.SessionClient)
+
+	middleware.SetAuditAction(c, models.ActionPasswordChange)
+	middleware.SetAuditResource(c, models.ResourceUser, "", "")
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation": "expired_password_change",
+	})
+
+	resp, err := h.authService.ChangeExpiredPassword(&req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("修改过期密码失败", logger.Err(err))
+			response.InternalError(c, "修改密码失败")
+		}
+		return
+	}
+	if resp.User != nil {
+		middleware.SetAuditResource(c, models.ResourceUser, resp.User.UUID, resp.User.Username)
+	}
+
+	response.Success(c, resp)
+}
+
+// LoginWithPasskey 通行密钥登录
+// @Summary 通行密钥登录
+// @Description 提交 /api/v1/auth/login/webauthn/begin 返回的 session_token 和 navigator.credentials.get() 返回的凭证换取JWT token。
+// @Description 通行密钥登录不再要求TOTP两步验证，签发的令牌同时视为已完成二次验证
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body service.WebAuthnLoginRequest true "通行密钥登录请求"
+// @Success 200 {object} response.Response{data=service.LoginResponse}
+// @Router /api/v1/auth/login/webauthn/finish [post]
+func (h *AuthHandler) LoginWithPasskey(c *gin.Context) {
+	var req service.WebAuthnLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("通行密钥登录请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	setSessionClient(c, &req.SessionClient)
+
+	middleware.SetAuditAction(c, models.ActionLogin)
+	middleware.SetAuditResource(c, models.ResourceUser, "", "")
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"method": "passkey",
+	})
+
+	resp, err := h.authService.LoginWithPasskey(&req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("通行密钥登录失败", logger.Err(err))
+			response.InternalError(c, "登录失败")
+		}
+		return
+	}
+	middleware.SetAuditResource(c, models.ResourceUser, resp.User.UUID, resp.User.Username)
+
+	response.Success(c, resp)
+}
+
+// LoginWithOIDC 单点登录
+// @Summary 单点登录
+// @Description 提交身份提供方回调中的 code 和 state 换取JWT token。首次登录时按已验证的邮箱关联已有账户，
+// @Description 没有账户时按配置自动创建；每次登录按角色映射同步角色。启用两步验证的用户仍需完成两步验证
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body service.OIDCLoginRequest true "单点登录请求"
+// @Success 200 {object} response.Response{data=service.LoginResponse}
+// @Router /api/v1/auth/login/oidc/finish [post]
+func (h *AuthHandler) LoginWithOIDC(c *gin.Context) {
+	var req service.OIDCLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("单点登录请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	setSessionClient(c, &req.SessionClient)
+
+	middleware.SetAuditAction(c, models.ActionLogin)
+	middleware.SetAuditResource(c, models.ResourceUser, "", "")
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"method": "oidc",
+	})
+
+	resp, err := h.authService.LoginWithOIDC(&req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("单点登录失败", logger.Err(err))
+			response.InternalError(c, "登录失败")
+		}
+		return
+	}
+	if resp.User != nil {
+		middleware.SetAuditResource(c, models.ResourceUser, resp.User.UUID, resp.User.Username)
+	}
+
+	response.Success(c, resp)
+}
+
+// BeginLoginMFAEnrollment 登录时绑定认证器
+// @Summary 登录时绑定认证器
+// @Description 角色被要求启用两步验证但尚未绑定时，使用登录接口返回的 mfa_token 获取TOTP密钥和otpauth URI
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body service.BeginLoginMFAEnrollmentRequest true "登录挑战令牌"
+// @Success 200 {object} response.Response{data=service.MFAEnrollmentResponse}
+// @Router /api/v1/auth/login/mfa/enroll [post]
+func (h *AuthHandler) BeginLoginMFAEnrollment(c *gin.Context) {
+	var req service.BeginLoginMFAEnrollmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("登录时绑定认证器请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	middleware.SetAuditAction(c, models.ActionUpdate)
+	middleware.SetAuditResource(c, models.ResourceUser, "", "")
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation": "mfa_enroll",
+	})
+
+	resp, err := h.authService.BeginLoginMFAEnrollment(&req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("登录时绑定认证器失败", logger.Err(err))
+			response.InternalError(c, "绑定认证器失败")
+		}
+		return
+	}
+
+	response.Success(c, resp)
+}
+
 // GetMe 获取当前用户信息
 // @Summary 获取当前用户信息
 // @Description 获取当前登录用户的详细信息
@@ -142,9 +359,50 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 	response.Success(c, user.ToSafeUser())
 }
 
+// RefreshToken 刷新访问令牌
+// @Summary 刷新访问令牌
+// @Description 使用刷新令牌换取新的访问令牌和刷新令牌，旧的刷新令牌立即失效。
+// @Description 已失效的刷新令牌再次使用说明令牌可能已泄露，会注销整个会话，需要重新登录
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body service.RefreshTokenRequest true "刷新令牌"
+// @Success 200 {object} response.Response{data=service.LoginResponse}
+// @Router /api/v1/auth/refresh [post]
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	var req service.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("刷新令牌请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	middleware.SetAuditAction(c, models.ActionTokenRefresh)
+	middleware.SetAuditResource(c, models.ResourceUser, "", "")
+
+	resp, err := h.authService.RefreshToken(&req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("刷新令牌失败", logger.Err(err))
+			response.InternalError(c, "刷新令牌失败")
+		}
+		return
+	}
+	middleware.SetAuditResource(c, models.ResourceUser, resp.User.UUID, resp.User.Username)
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"session_uuid": resp.SessionUUID,
+	})
+
+	response.Success(c, resp)
+}
+
 // Logout 用户登出
 // @Summary 用户登出
-// @Description 用户登出，使当前token失效
+// @Description 用户登出，注销当前会话，其他设备上的会话不受影响
 // @Tags 认证
 // @Accept json
 // @Produce json
@@ -295,7 +553,7 @@ func (h *AuthHandler) VerifyPasswordResetToken(c *gin.Context) {
 
 // ResetPasswordWithToken 使用token重置密码
 // @Summary 使用token重置密码
-// @Description 使用邮件中的重置token设置新密码。无需登录即可访问
+// @Description 使用邮件中的重置token设置新密码。无需登录即可访问。重置后该用户已签发的令牌和登录会话全部失效
 // @Tags 认证
 // @Accept json
 // @Produce json
@@ -324,6 +582,54 @@ func (h *AuthHandler) ResetPasswordWithToken(c *gin.Context) {
 	response.Success(c, resp)
 }
 
+// ChangePassword 修改登录密码
+// @Summary 修改登录密码
+// @Description 已登录用户验证原密码后修改登录密码，新密码需要符合系统的密码策略且不能与最近用过的密码重复。
+// @Description 修改后注销该用户其他设备上的登录会话，当前会话保持有效
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.ChangePasswordRequest true "修改密码请求"
+// @Success 200 {object} response.Response{data=service.ChangePasswordResponse}
+// @Router /api/v1/auth/password [put]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	sessionUUID, sessionExists := middleware.GetCurrentSessionUUID(c)
+	if !exists || !sessionExists {
+		response.Unauthorized(c, "上下文中未找到用户信息")
+		return
+	}
+
+	var req service.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("修改密码请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.UserUUID = user.UUID
+	req.SessionUUID = sessionUUID
+
+	middleware.SetAuditAction(c, models.ActionPasswordChange)
+	middleware.SetAuditResource(c, models.ResourceUser, user.UUID, user.Username)
+
+	resp, err := h.authService.ChangePassword(&req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("修改密码失败", logger.Err(err))
+			response.InternalError(c, "修改密码失败")
+		}
+		return
+	}
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"revoked_sessions": resp.RevokedSessions,
+	})
+
+	response.Success(c, resp)
+}
+
 // GetSecurityPINStatus 获取安全密码设置状态
 // @Summary 获取安全密码设置状态
 // @Description 检查当前用户是否已设置安全密码，用于前端判断是否需要引导用户设置
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/cuihe500/vaulthub/pkg/validator"
+	"github.com/gin-gonic/gin"
+)
+
+// BackupHandler 秘密备份处理器
+type BackupHandler struct {
+	backupService *service.BackupService
+}
+
+// NewBackupHandler 创建秘密备份处理器实例
+func NewBackupHandler(backupService *service.BackupService) *BackupHandler {
+	return &BackupHandler{
+		backupService: backupService,
+	}
+}
+
+// ExportBackup 导出加密备份
+// @Summary 导出加密备份
+// @Description 解密当前用户的全部秘密，并使用备份口令（Argon2id + AES-256-GCM）重新加密为可移植的备份文件
+// @Description 返回的 backup 字段原样保存为JSON文件即可，格式说明见 docs/backup_format.md
+// @Description 与解密秘密使用相同的访问控制，未持有检出租约的秘密和未获批准的高敏感秘密不导出，在 skipped 中列出
+// @Tags 秘密管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.ExportBackupRequest true "导出备份请求"
+// @Success 200 {object} response.Response{data=service.ExportBackupResponse}
+// @Router /api/v1/secrets/backup [post]
+func (h *BackupHandler) ExportBackup(c *gin.Context) {
+	// 获取当前用户UUID
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.ExportBackupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("导出备份请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	// 使用当前用户的UUID（防止用户伪造其他用户的UUID）
+	req.UserUUID = userUUID
+
+	middleware.SetAuditAction(c, models.ActionExport)
+	middleware.SetAuditResource(c, models.ResourceSecret, "", "")
+
+	resp, err := h.backupService.ExportBackup(&req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("导出备份失败", logger.Err(err))
+			response.InternalError(c, "导出备份失败")
+		}
+		return
+	}
+
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"secret_count":  resp.SecretCount,
+		"skipped_count": len(resp.Skipped),
+	})
+
+	response.Success(c, resp)
+}
+
+// RestoreBackup 从加密备份恢复
+// @Summary 从加密备份恢复
+// @Description 使用备份口令解密备份文件，并用当前用户的密钥重新加密后写入，可恢复到同一实例或其他实例
+// @Description 同名秘密按 conflict_strategy 处理：skip（默认，跳过）、overwrite（覆盖）、rename（重命名后导入）
+// @Tags 秘密管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.RestoreBackupRequest true "恢复备份请求"
+// @Success 200 {object} response.Response{data=service.RestoreBackupResponse}
+// @Router /api/v1/secrets/restore [post]
+func (h *BackupHandler) RestoreBackup(c *gin.Context) {
+	// 获取当前用户UUID
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.RestoreBackupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("恢复备份请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	// 使用当前用户的UUID（防止用户伪造其他用户的UUID）
+	req.UserUUID = userUUID
+
+	middleware.SetAuditAction(c, models.ActionImport)
+	middleware.SetAuditResource(c, models.ResourceSecret, "", "")
+
+	resp, err := h.backupService.RestoreBackup(&req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("恢复备份失败", logger.Err(err))
+			response.InternalError(c, "恢复备份失败")
+		}
+		return
+	}
+
+	// 审计详情只记录统计信息，不记录秘密内容
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"source":            "backup",
+		"source_user_uuid":  resp.SourceUserUUID,
+		"conflict_strategy": req.ConflictStrategy,
+		"total":             resp.Total,
+		"created":           resp.Created,
+		"overwritten":       resp.Overwritten,
+		"renamed":           resp.Renamed,
+		"skipped":           resp.Skipped,
+		"failed":            resp.Failed,
+	})
+
+	response.Success(c, resp)
+}
@@ -0,0 +1,366 @@
+package handlers
+
+import (
+	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/cuihe500/vaulthub/pkg/validator"
+	"github.com/gin-gonic/gin"
+)
+
+// CheckoutHandler 秘密检出处理器
+type CheckoutHandler struct {
+	checkoutService *service.CheckoutService
+}
+
+// NewCheckoutHandler 创建秘密检出处理器实例
+func NewCheckoutHandler(checkoutService *service.CheckoutService) *CheckoutHandler {
+	return &CheckoutHandler{
+		checkoutService: checkoutService,
+	}
+}
+
+// respondCheckoutError 输出检出相关接口的错误响应
+func respondCheckoutError(c *gin.Context, err error, message string) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		response.AppError(c, appErr)
+		return
+	}
+	logger.Error(message, logger.Err(err))
+	response.InternalError(c, message)
+}
+
+// SetCheckoutPolicy 设置检出策略
+// @Summary 设置检出策略
+// @Description 启用后秘密不能直接解密，必须检出后独占使用；可同时设置密码生成规则，用于检入后自动轮换
+// @Tags 秘密管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "秘密UUID"
+// @Param request body service.SetCheckoutPolicyRequest true "检出策略"
+// @Success 200 {object} response.Response{data=github_com_cuihe500_vaulthub_internal_database_models.SafeEncryptedSecret}
+// @Router /api/v1/secrets/{uuid}/checkout-policy [put]
+func (h *CheckoutHandler) SetCheckoutPolicy(c *gin.Context) {
+	// 获取当前用户UUID
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	secretUUID := c.Param("uuid")
+	if secretUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	var req service.SetCheckoutPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("设置检出策略请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.UserUUID = userUUID
+	req.SecretUUID = secretUUID
+
+	middleware.SetAuditAction(c, models.ActionUpdate)
+	middleware.SetAuditResource(c, models.ResourceSecret, secretUUID, "")
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation":         "set_checkout_policy",
+		"checkout_required": req.CheckoutRequired,
+		"generator":         req.Generator != nil,
+	})
+
+	resp, err := h.checkoutService.SetCheckoutPolicy(&req)
+	if err != nil {
+		respondCheckoutError(c, err, "设置检出策略失败")
+		return
+	}
+
+	middleware.SetAuditResource(c, models.ResourceSecret, resp.SecretUUID, resp.SecretName)
+	response.Success(c, resp)
+}
+
+// Checkout 检出秘密
+// @Summary 检出秘密
+// @Description 获得秘密在租约期内的独占访问并返回明文（需要输入安全密码）
+// @Description 秘密已被检出时返回错误码30004，错误信息中包含当前持有者和到期时间
+// @Tags 秘密管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "秘密UUID"
+// @Param request body service.CheckoutRequest true "检出请求"
+// @Success 200 {object} response.Response{data=service.CheckoutResponse}
+// @Router /api/v1/secrets/{uuid}/checkout [post]
+func (h *CheckoutHandler) Checkout(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	secretUUID := c.Param("uuid")
+	if secretUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	var req service.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("检出秘密请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.UserUUID = user.UUID
+	req.Username = user.Username
+	req.SecretUUID = secretUUID
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	middleware.SetAuditAction(c, models.ActionCheckout)
+	middleware.SetAuditResource(c, models.ResourceSecret, secretUUID, "")
+
+	resp, err := h.checkoutService.Checkout(&req)
+	if err != nil {
+		respondCheckoutError(c, err, "检出秘密失败")
+		return
+	}
+
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"checkout_uuid": resp.Checkout.CheckoutUUID,
+		"expires_at":    resp.Checkout.ExpiresAt,
+		"reason":        req.Reason,
+	})
+	response.Success(c, resp)
+}
+
+// Checkin 检入秘密
+// @Summary 检入秘密
+// @Description 释放检出租约，秘密被标记为待轮换
+// @Description 秘密关联了密码生成规则且提供了安全密码时，检入后自动轮换为新的随机密码
+// @Tags 秘密管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "秘密UUID"
+// @Param request body service.CheckinRequest true "检入请求"
+// @Success 200 {object} response.Response{data=service.CheckinResponse}
+// @Router /api/v1/secrets/{uuid}/checkin [post]
+func (h *CheckoutHandler) Checkin(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	secretUUID := c.Param("uuid")
+	if secretUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	var req service.CheckinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("检入秘密请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.UserUUID = user.UUID
+	req.Username = user.Username
+	req.SecretUUID = secretUUID
+
+	middleware.SetAuditAction(c, models.ActionCheckin)
+	middleware.SetAuditResource(c, models.ResourceSecret, secretUUID, "")
+
+	resp, err := h.checkoutService.Checkin(&req)
+	if err != nil {
+		respondCheckoutError(c, err, "检入秘密失败")
+		return
+	}
+
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"checkout_uuid": req.CheckoutUUID,
+		"rotated":       resp.Rotated,
+	})
+	response.Success(c, resp)
+}
+
+// GetCheckoutStatus 查询检出状态
+// @Summary 查询检出状态
+// @Description 查询秘密当前的持有者、待轮换标记和最近的检出记录
+// @Tags 秘密管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "秘密UUID"
+// @Success 200 {object} response.Response{data=service.CheckoutStatusResponse}
+// @Router /api/v1/secrets/{uuid}/checkout [get]
+func (h *CheckoutHandler) GetCheckoutStatus(c *gin.Context) {
+	// 获取当前用户UUID
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	secretUUID := c.Param("uuid")
+	if secretUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	resp, err := h.checkoutService.GetCheckoutStatus(userUUID, secretUUID)
+	if err != nil {
+		respondCheckoutError(c, err, "查询检出状态失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// RotateSecret 轮换秘密
+// @Summary 轮换秘密
+// @Description 更新秘密的明文并清除待轮换标记（需要输入安全密码）。plain_data为空时按密码生成规则生成随机密码
+// @Description 检出中的秘密不能轮换
+// @Tags 秘密管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "秘密UUID"
+// @Param request body service.RotateSecretRequest true "轮换请求"
+// @Success 200 {object} response.Response{data=github_com_cuihe500_vaulthub_internal_database_models.SafeEncryptedSecret}
+// @Router /api/v1/secrets/{uuid}/rotate [post]
+func (h *CheckoutHandler) RotateSecret(c *gin.Context) {
+	// 获取当前用户UUID
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	secretUUID := c.Param("uuid")
+	if secretUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	var req service.RotateSecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("轮换秘密请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.UserUUID = userUUID
+	req.SecretUUID = secretUUID
+
+	middleware.SetAuditAction(c, models.ActionUpdate)
+	middleware.SetAuditResource(c, models.ResourceSecret, secretUUID, "")
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation": "rotate",
+		"generated": req.PlainData == "",
+	})
+
+	resp, err := h.checkoutService.RotateSecret(&req)
+	if err != nil {
+		respondCheckoutError(c, err, "轮换秘密失败")
+		return
+	}
+
+	middleware.SetAuditResource(c, models.ResourceSecret, resp.SecretUUID, resp.SecretName)
+	response.Success(c, resp)
+}
+
+// ListCheckouts 查询所有检出记录（管理员）
+// @Summary 查询所有检出记录
+// @Description 管理员查询所有用户的检出记录，可按状态和秘密所属用户过滤
+// @Tags 秘密管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "检出状态" Enums(active, checked_in, expired, force_released)
+// @Param owner_uuid query string false "秘密所属用户UUID"
+// @Param page query int false "页码" minimum(1)
+// @Param page_size query int false "每页数量" minimum(1) maximum(100)
+// @Success 200 {object} response.Response{data=service.ListCheckoutsResponse}
+// @Router /api/v1/admin/checkouts [get]
+func (h *CheckoutHandler) ListCheckouts(c *gin.Context) {
+	var req service.ListCheckoutsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("查询检出记录请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	resp, err := h.checkoutService.ListCheckouts(&req)
+	if err != nil {
+		respondCheckoutError(c, err, "查询检出记录失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// ForceRelease 强制释放检出（管理员）
+// @Summary 强制释放检出
+// @Description 管理员强制释放秘密当前的检出，秘密被标记为待轮换
+// @Tags 秘密管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "秘密UUID"
+// @Param request body service.ForceReleaseRequest false "强制释放原因"
+// @Success 200 {object} response.Response{data=github_com_cuihe500_vaulthub_internal_database_models.SecretCheckout}
+// @Router /api/v1/admin/checkouts/{uuid}/release [post]
+func (h *CheckoutHandler) ForceRelease(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	secretUUID := c.Param("uuid")
+	if secretUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	// 请求体可选
+	var req service.ForceReleaseRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Warn("强制释放检出请求参数无效", logger.Err(err))
+			response.ValidationError(c, validator.TranslateError(err))
+			return
+		}
+	}
+	req.AdminUsername = user.Username
+	req.SecretUUID = secretUUID
+
+	middleware.SetAuditAction(c, models.ActionForceRelease)
+	middleware.SetAuditResource(c, models.ResourceSecret, secretUUID, "")
+
+	resp, err := h.checkoutService.ForceRelease(&req)
+	if err != nil {
+		respondCheckoutError(c, err, "强制释放检出失败")
+		return
+	}
+
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"checkout_uuid": resp.CheckoutUUID,
+		"owner_uuid":    resp.OwnerUUID,
+		"holder":        resp.HolderUsername,
+		"reason":        req.Reason,
+	})
+	response.Success(c, resp)
+}
@@ -0,0 +1,460 @@
+package handlers
+
+import (
+	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/cuihe500/vaulthub/pkg/validator"
+	"github.com/gin-gonic/gin"
+)
+
+// EmergencyAccessHandler 紧急访问处理器
+type EmergencyAccessHandler struct {
+	emergencyService *service.EmergencyAccessService
+}
+
+// NewEmergencyAccessHandler 创建紧急访问处理器实例
+func NewEmergencyAccessHandler(emergencyService *service.EmergencyAccessService) *EmergencyAccessHandler {
+	return &EmergencyAccessHandler{
+		emergencyService: emergencyService,
+	}
+}
+
+// respondEmergencyError 输出紧急访问相关接口的错误响应
+func respondEmergencyError(c *gin.Context, err error, message string) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		response.AppError(c, appErr)
+		return
+	}
+	logger.Error(message, logger.Err(err))
+	response.InternalError(c, message)
+}
+
+// GetKeyPairStatus 查询紧急访问密钥对
+// @Summary 查询紧急访问密钥对
+// @Description 查询当前用户是否已生成紧急访问密钥对及公钥指纹；生成密钥对后才能被指定为可信联系人
+// @Tags 紧急访问
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=service.KeyPairStatus}
+// @Router /api/v1/emergency-access/keypair [get]
+func (h *EmergencyAccessHandler) GetKeyPairStatus(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	resp, err := h.emergencyService.GetKeyPairStatus(userUUID)
+	if err != nil {
+		respondEmergencyError(c, err, "查询紧急访问密钥对失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// InitKeyPair 生成紧急访问密钥对
+// @Summary 生成紧急访问密钥对
+// @Description 为已有账户生成紧急访问密钥对（私钥由DEK加密保存），已存在时直接返回
+// @Tags 紧急访问
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.InitKeyPairRequest true "安全密码"
+// @Success 200 {object} response.Response{data=service.KeyPairStatus}
+// @Router /api/v1/emergency-access/keypair [post]
+func (h *EmergencyAccessHandler) InitKeyPair(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.InitKeyPairRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("生成紧急访问密钥对请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.UserUUID = userUUID
+
+	middleware.SetAuditAction(c, models.ActionCreate)
+	middleware.SetAuditResource(c, models.ResourceVault, userUUID, "")
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation": "init_emergency_keypair",
+	})
+
+	resp, err := h.emergencyService.InitKeyPair(&req)
+	if err != nil {
+		respondEmergencyError(c, err, "生成紧急访问密钥对失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// ListTrustedContacts 查询我的可信联系人
+// @Summary 查询我的可信联系人
+// @Description 查询当前用户指定的可信联系人及其申请状态
+// @Tags 紧急访问
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]service.EmergencyAccessInfo}
+// @Router /api/v1/emergency-access/trusted [get]
+func (h *EmergencyAccessHandler) ListTrustedContacts(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	resp, err := h.emergencyService.ListTrustedContacts(userUUID)
+	if err != nil {
+		respondEmergencyError(c, err, "查询可信联系人失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// DesignateContact 指定可信联系人
+// @Summary 指定可信联系人
+// @Description 指定可信联系人并用其公钥封装自己的DEK（需要安全密码）。联系人发起申请后经过等待期即可查看秘密（view）或接管账户（takeover）
+// @Tags 紧急访问
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.DesignateContactRequest true "可信联系人"
+// @Success 200 {object} response.Response{data=service.EmergencyAccessInfo}
+// @Router /api/v1/emergency-access/trusted [post]
+func (h *EmergencyAccessHandler) DesignateContact(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.DesignateContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("指定可信联系人请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.GrantorUUID = user.UUID
+	req.GrantorUsername = user.Username
+
+	middleware.SetAuditAction(c, models.ActionCreate)
+	middleware.SetAuditResource(c, models.ResourceEmergencyAccess, "", "")
+
+	resp, err := h.emergencyService.DesignateContact(&req)
+	if err != nil {
+		respondEmergencyError(c, err, "指定可信联系人失败")
+		return
+	}
+
+	middleware.SetAuditResource(c, models.ResourceEmergencyAccess, resp.GrantUUID, resp.GranteeUsername)
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"grantee":     resp.GranteeUsername,
+		"access_type": resp.AccessType,
+		"wait_days":   resp.WaitDays,
+	})
+	response.Success(c, resp)
+}
+
+// RevokeContact 撤销可信联系人
+// @Summary 撤销可信联系人
+// @Description 撤销后封装给联系人的DEK立即删除
+// @Tags 紧急访问
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "授权UUID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/emergency-access/trusted/{uuid} [delete]
+func (h *EmergencyAccessHandler) RevokeContact(c *gin.Context) {
+	h.grantorAction(c, models.ActionDelete, "撤销可信联系人失败", h.emergencyService.RevokeContact)
+}
+
+// ApproveAccess 同意紧急访问申请
+// @Summary 同意紧急访问申请
+// @Description 授权人提前结束等待期，联系人立即获得访问权限
+// @Tags 紧急访问
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "授权UUID"
+// @Success 200 {object} response.Response{data=github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccess}
+// @Router /api/v1/emergency-access/trusted/{uuid}/approve [post]
+func (h *EmergencyAccessHandler) ApproveAccess(c *gin.Context) {
+	h.grantorAction(c, models.ActionApprove, "同意紧急访问申请失败", h.emergencyService.ApproveAccess)
+}
+
+// RejectAccess 拒绝紧急访问申请
+// @Summary 拒绝紧急访问申请
+// @Description 等待期内拒绝申请，或收回联系人已获得的访问权限；授权本身保留
+// @Tags 紧急访问
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "授权UUID"
+// @Success 200 {object} response.Response{data=github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccess}
+// @Router /api/v1/emergency-access/trusted/{uuid}/reject [post]
+func (h *EmergencyAccessHandler) RejectAccess(c *gin.Context) {
+	h.grantorAction(c, models.ActionDeny, "拒绝紧急访问申请失败", h.emergencyService.RejectAccess)
+}
+
+// grantorAction 处理授权人对授权的操作（撤销、同意、拒绝）
+func (h *EmergencyAccessHandler) grantorAction(c *gin.Context, action models.ActionType, message string, fn func(grantorUUID, grantUUID string) (*models.EmergencyAccess, error)) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	grantUUID := c.Param("uuid")
+	if grantUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	middleware.SetAuditAction(c, action)
+	middleware.SetAuditResource(c, models.ResourceEmergencyAccess, grantUUID, "")
+
+	resp, err := fn(userUUID, grantUUID)
+	if err != nil {
+		respondEmergencyError(c, err, message)
+		return
+	}
+
+	middleware.SetAuditResource(c, models.ResourceEmergencyAccess, grantUUID, resp.GranteeUsername)
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"grantee":     resp.GranteeUsername,
+		"access_type": resp.AccessType,
+	})
+	if action == models.ActionDelete {
+		response.Success(c, nil)
+		return
+	}
+	response.Success(c, resp)
+}
+
+// ListGrantedToMe 查询授予我的紧急访问
+// @Summary 查询授予我的紧急访问
+// @Description 查询把当前用户指定为可信联系人的授权及其状态
+// @Tags 紧急访问
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccess}
+// @Router /api/v1/emergency-access/granted [get]
+func (h *EmergencyAccessHandler) ListGrantedToMe(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	resp, err := h.emergencyService.ListGrantedToMe(userUUID)
+	if err != nil {
+		respondEmergencyError(c, err, "查询紧急访问授权失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// RequestAccess 发起紧急访问申请
+// @Summary 发起紧急访问申请
+// @Description 可信联系人发起申请后进入等待期，授权人会收到邮件通知，等待期内可以拒绝
+// @Tags 紧急访问
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "授权UUID"
+// @Success 200 {object} response.Response{data=github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccess}
+// @Router /api/v1/emergency-access/granted/{uuid}/request [post]
+func (h *EmergencyAccessHandler) RequestAccess(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	grantUUID := c.Param("uuid")
+	if grantUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	middleware.SetAuditAction(c, models.ActionEmergencyRequest)
+	middleware.SetAuditResource(c, models.ResourceEmergencyAccess, grantUUID, "")
+
+	resp, err := h.emergencyService.RequestAccess(userUUID, grantUUID)
+	if err != nil {
+		respondEmergencyError(c, err, "发起紧急访问申请失败")
+		return
+	}
+
+	middleware.SetAuditResource(c, models.ResourceEmergencyAccess, grantUUID, resp.GrantorUsername)
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"grantor":      resp.GrantorUsername,
+		"access_type":  resp.AccessType,
+		"available_at": resp.AvailableAt,
+	})
+	response.Success(c, resp)
+}
+
+// ListGrantorSecrets 查询授权人的秘密列表
+// @Summary 查询授权人的秘密列表
+// @Description 紧急访问生效后，可信联系人查询授权人的秘密列表（不包含加密数据）
+// @Tags 紧急访问
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "授权UUID"
+// @Param secret_type query string false "秘密类型" Enums(api_key, db_credential, certificate, ssh_key, token, password, other)
+// @Param page query int false "页码" minimum(1)
+// @Param page_size query int false "每页数量" minimum(1) maximum(10000)
+// @Success 200 {object} response.Response{data=service.ListUserSecretsResponse}
+// @Router /api/v1/emergency-access/granted/{uuid}/secrets [get]
+func (h *EmergencyAccessHandler) ListGrantorSecrets(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	grantUUID := c.Param("uuid")
+	if grantUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	var req service.ListUserSecretsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("查询授权人秘密列表请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	resp, err := h.emergencyService.ListGrantorSecrets(userUUID, grantUUID, &req)
+	if err != nil {
+		respondEmergencyError(c, err, "查询授权人秘密列表失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// DecryptGrantorSecret 解密授权人的秘密
+// @Summary 解密授权人的秘密
+// @Description 紧急访问生效后，可信联系人用自己的安全密码解开授权人的DEK并解密秘密，每次解密都记录审计日志
+// @Tags 紧急访问
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "授权UUID"
+// @Param secret_uuid path string true "秘密UUID"
+// @Param request body service.EmergencyDecryptRequest true "联系人自己的安全密码"
+// @Success 200 {object} response.Response{data=service.EmergencyDecryptResponse}
+// @Router /api/v1/emergency-access/granted/{uuid}/secrets/{secret_uuid}/decrypt [post]
+func (h *EmergencyAccessHandler) DecryptGrantorSecret(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	grantUUID := c.Param("uuid")
+	secretUUID := c.Param("secret_uuid")
+	if grantUUID == "" || secretUUID == "" {
+		response.MissingParam(c, "uuid和secret_uuid参数必填")
+		return
+	}
+
+	var req service.EmergencyDecryptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("紧急访问解密请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.GranteeUUID = userUUID
+	req.GrantUUID = grantUUID
+	req.SecretUUID = secretUUID
+
+	middleware.SetAuditAction(c, models.ActionAccess)
+	middleware.SetAuditResource(c, models.ResourceSecret, secretUUID, "")
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"emergency_grant_uuid": grantUUID,
+	})
+
+	resp, err := h.emergencyService.DecryptGrantorSecret(&req)
+	if err != nil {
+		respondEmergencyError(c, err, "紧急访问解密秘密失败")
+		return
+	}
+
+	middleware.SetAuditResource(c, models.ResourceSecret, secretUUID, resp.SecretName)
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"emergency_grant_uuid": grantUUID,
+		"grantor":              resp.GrantorUsername,
+	})
+	response.Success(c, resp)
+}
+
+// Takeover 紧急接管授权人账户
+// @Summary 紧急接管授权人账户
+// @Description 接管类授权生效后，可信联系人重置授权人的登录密码和安全密码，授权人已登录的会话立即失效
+// @Tags 紧急访问
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "授权UUID"
+// @Param request body service.TakeoverRequest true "接管请求"
+// @Success 200 {object} response.Response{data=github_com_cuihe500_vaulthub_internal_database_models.EmergencyAccess}
+// @Router /api/v1/emergency-access/granted/{uuid}/takeover [post]
+func (h *EmergencyAccessHandler) Takeover(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	grantUUID := c.Param("uuid")
+	if grantUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	var req service.TakeoverRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("紧急接管请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.GranteeUUID = userUUID
+	req.GrantUUID = grantUUID
+
+	middleware.SetAuditAction(c, models.ActionEmergencyTakeover)
+	middleware.SetAuditResource(c, models.ResourceEmergencyAccess, grantUUID, "")
+
+	resp, err := h.emergencyService.Takeover(&req)
+	if err != nil {
+		respondEmergencyError(c, err, "紧急接管失败")
+		return
+	}
+
+	middleware.SetAuditResource(c, models.ResourceEmergencyAccess, grantUUID, resp.GrantorUsername)
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"grantor": resp.GrantorUsername,
+	})
+	response.Success(c, resp)
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/cuihe500/vaulthub/pkg/validator"
+	"github.com/gin-gonic/gin"
+)
+
+// MFAHandler 两步验证处理器
+type MFAHandler struct {
+	mfaService *service.MFAService
+}
+
+// NewMFAHandler 创建两步验证处理器实例
+func NewMFAHandler(mfaService *service.MFAService) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+	}
+}
+
+// respondMFAError 输出两步验证相关接口的错误响应
+func respondMFAError(c *gin.Context, err error, message string) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		response.AppError(c, appErr)
+		return
+	}
+	logger.Error(message, logger.Err(err))
+	response.InternalError(c, message)
+}
+
+// GetStatus 查询两步验证状态
+// @Summary 查询两步验证状态
+// @Description 查询当前用户是否已启用两步验证、角色是否被要求启用以及剩余备用码数量
+// @Tags 两步验证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=service.MFAStatusResponse}
+// @Router /api/v1/auth/mfa [get]
+func (h *MFAHandler) GetStatus(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	resp, err := h.mfaService.GetStatus(user.UUID, user.Role)
+	if err != nil {
+		respondMFAError(c, err, "查询两步验证状态失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// BeginEnrollment 绑定认证器
+// @Summary 绑定认证器
+// @Description 生成TOTP密钥和otpauth URI（前端据此生成二维码），需要再调用确认接口提交第一个验证码才会启用
+// @Tags 两步验证
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=service.MFAEnrollmentResponse}
+// @Router /api/v1/auth/mfa/enroll [post]
+func (h *MFAHandler) BeginEnrollment(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	middleware.SetAuditAction(c, models.ActionUpdate)
+	middleware.SetAuditResource(c, models.ResourceUser, user.UUID, user.Username)
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation": "mfa_enroll",
+	})
+
+	resp, err := h.mfaService.BeginEnrollment(user.UUID, user.Username)
+	if err != nil {
+		respondMFAError(c, err, "绑定认证器失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// ConfirmEnrollment 确认绑定并启用两步验证
+// @Summary 确认绑定并启用两步验证
+// @Description 提交认证器生成的第一个验证码，验证通过后启用两步验证并返回备用码（只返回这一次）
+// @Tags 两步验证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.MFACodeRequest true "认证器验证码"
+// @Success 200 {object} response.Response{data=service.MFABackupCodesResponse}
+// @Router /api/v1/auth/mfa/confirm [post]
+func (h *MFAHandler) ConfirmEnrollment(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("确认两步验证请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	middleware.SetAuditAction(c, models.ActionMFAEnable)
+	middleware.SetAuditResource(c, models.ResourceUser, user.UUID, user.Username)
+
+	resp, err := h.mfaService.ConfirmEnrollment(user.UUID, req.Code)
+	if err != nil {
+		respondMFAError(c, err, "启用两步验证失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Disable 停用两步验证
+// @Summary 停用两步验证
+// @Description 提交认证器验证码或备用码停用两步验证；角色被要求启用两步验证时不允许停用
+// @Tags 两步验证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.MFACodeRequest true "认证器验证码或备用码"
+// @Success 200 {object} response.Response
+// @Router /api/v1/auth/mfa/disable [post]
+func (h *MFAHandler) Disable(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("停用两步验证请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	middleware.SetAuditAction(c, models.ActionMFADisable)
+	middleware.SetAuditResource(c, models.ResourceUser, user.UUID, user.Username)
+
+	if err := h.mfaService.Disable(user.UUID, user.Role, req.Code); err != nil {
+		respondMFAError(c, err, "停用两步验证失败")
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// RegenerateBackupCodes 重新生成备用码
+// @Summary 重新生成备用码
+// @Description 提交认证器验证码或备用码后重新生成备用码，旧备用码全部作废，新备用码只返回这一次
+// @Tags 两步验证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.MFACodeRequest true "认证器验证码或备用码"
+// @Success 200 {object} response.Response{data=service.MFABackupCodesResponse}
+// @Router /api/v1/auth/mfa/backup-codes [post]
+func (h *MFAHandler) RegenerateBackupCodes(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("重新生成备用码请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	middleware.SetAuditAction(c, models.ActionUpdate)
+	middleware.SetAuditResource(c, models.ResourceUser, user.UUID, user.Username)
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation": "mfa_regenerate_backup_codes",
+	})
+
+	resp, err := h.mfaService.RegenerateBackupCodes(user.UUID, req.Code)
+	if err != nil {
+		respondMFAError(c, err, "重新生成备用码失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// ResetUserMFA 管理员重置用户的两步验证
+// @Summary 重置用户的两步验证
+// @Description 用户丢失认证器和备用码时由管理员重置，删除其认证器绑定和备用码（需要user:write权限）
+// @Tags 用户管理
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "用户UUID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/users/{uuid}/mfa/reset [post]
+func (h *MFAHandler) ResetUserMFA(c *gin.Context) {
+	userUUID := c.Param("uuid")
+	if userUUID == "" {
+		response.ValidationError(c, "uuid参数必填")
+		return
+	}
+
+	middleware.SetAuditAction(c, models.ActionMFAReset)
+	middleware.SetAuditResource(c, models.ResourceUser, userUUID, "")
+
+	user, err := h.mfaService.Reset(userUUID)
+	if err != nil {
+		respondMFAError(c, err, "重置两步验证失败")
+		return
+	}
+	middleware.SetAuditResource(c, models.ResourceUser, userUUID, user.Username)
+
+	response.Success(c, nil)
+}
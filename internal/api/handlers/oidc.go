@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// OIDCHandler 单点登录处理器
+type OIDCHandler struct {
+	oidcService *service.OIDCService
+}
+
+// NewOIDCHandler 创建单点登录处理器实例
+func NewOIDCHandler(oidcService *service.OIDCService) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+	}
+}
+
+// BeginLogin 开始单点登录
+// @Summary 开始单点登录
+// @Description 返回身份提供方的授权地址（授权码+PKCE），前端跳转到该地址登录，10分钟内有效。
+// @Description 身份提供方回调到配置的 redirect_url 后，把回调中的 code 和 state 提交到 /api/v1/auth/login/oidc/finish
+// @Tags 认证
+// @Produce json
+// @Success 200 {object} response.Response{data=service.OIDCLoginBeginResponse}
+// @Router /api/v1/auth/login/oidc/begin [post]
+func (h *OIDCHandler) BeginLogin(c *gin.Context) {
+	resp, err := h.oidcService.BeginLogin()
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("开始单点登录失败", logger.Err(err))
+			response.InternalError(c, "开始单点登录失败")
+		}
+		return
+	}
+
+	response.Success(c, resp)
+}
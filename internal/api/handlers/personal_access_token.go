@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/cuihe500/vaulthub/pkg/validator"
+	"github.com/gin-gonic/gin"
+)
+
+// PersonalAccessTokenHandler 个人访问令牌处理器
+type PersonalAccessTokenHandler struct {
+	accessTokenService *service.PersonalAccessTokenService
+}
+
+// NewPersonalAccessTokenHandler 创建个人访问令牌处理器实例
+func NewPersonalAccessTokenHandler(accessTokenService *service.PersonalAccessTokenService) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{
+		accessTokenService: accessTokenService,
+	}
+}
+
+// respondAccessTokenError 输出个人访问令牌相关接口的错误响应
+func respondAccessTokenError(c *gin.Context, err error, message string) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		response.AppError(c, appErr)
+		return
+	}
+	logger.Error(message, logger.Err(err))
+	response.InternalError(c, message)
+}
+
+// CreateToken 创建个人访问令牌
+// @Summary 创建个人访问令牌
+// @Description 为当前用户创建个人访问令牌，供CI任务和脚本代替用户名密码调用API。令牌明文只在本次响应中返回，请妥善保存。
+// @Description 权限范围格式为 资源:操作（如 secret:read），只能是当前角色已有权限的子集；allowed_ips 为空时不限制来源IP。
+// @Description secret_uuids 限定令牌只能访问其中列出的秘密（秘密列表和模板渲染只返回或引用这些秘密，备份、导入、创建等接口不可用），为空时可以访问全部秘密。
+// @Description 令牌只能访问带权限检查的接口，访问的是创建者自己的保险库；创建者修改角色、被禁用或重置密码后令牌失效。
+// @Tags 个人访问令牌
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.CreatePersonalAccessTokenRequest true "令牌名称、权限范围、有效期和IP白名单"
+// @Success 200 {object} response.Response{data=service.CreatePersonalAccessTokenResponse}
+// @Router /api/v1/auth/tokens [post]
+func (h *PersonalAccessTokenHandler) CreateToken(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.CreatePersonalAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.UserUUID = user.UUID
+	req.Role = user.Role
+
+	middleware.SetAuditAction(c, models.ActionCreate)
+	middleware.SetAuditResource(c, models.ResourceAccessToken, "", req.Name)
+
+	resp, err := h.accessTokenService.Create(&req)
+	if err != nil {
+		respondAccessTokenError(c, err, "创建个人访问令牌失败")
+		return
+	}
+	middleware.SetAuditResource(c, models.ResourceAccessToken, resp.AccessToken.UUID, resp.AccessToken.Name)
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"scopes":       resp.AccessToken.Scopes,
+		"secret_uuids": resp.AccessToken.SecretUUIDs,
+		"allowed_ips":  resp.AccessToken.AllowedIPs,
+		"expires_at":   resp.AccessToken.ExpiresAt,
+	})
+
+	response.Success(c, resp)
+}
+
+// ListTokens 查询个人访问令牌列表
+// @Summary 查询个人访问令牌列表
+// @Description 查询当前用户的个人访问令牌（包括已过期的），返回名称、令牌前缀、权限范围、IP白名单、过期时间和最后使用时间及IP，不返回令牌明文
+// @Tags 个人访问令牌
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=service.PersonalAccessTokenListResponse}
+// @Router /api/v1/auth/tokens [get]
+func (h *PersonalAccessTokenHandler) ListTokens(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	resp, err := h.accessTokenService.List(userUUID)
+	if err != nil {
+		respondAccessTokenError(c, err, "查询个人访问令牌失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// RevokeToken 撤销个人访问令牌
+// @Summary 撤销个人访问令牌
+// @Description 撤销当前用户的一个个人访问令牌，令牌立即失效
+// @Tags 个人访问令牌
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "令牌UUID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/auth/tokens/{uuid} [delete]
+func (h *PersonalAccessTokenHandler) RevokeToken(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	tokenUUID := c.Param("uuid")
+	middleware.SetAuditAction(c, models.ActionDelete)
+	middleware.SetAuditResource(c, models.ResourceAccessToken, tokenUUID, "")
+
+	token, err := h.accessTokenService.Revoke(userUUID, tokenUUID)
+	if err != nil {
+		respondAccessTokenError(c, err, "撤销个人访问令牌失败")
+		return
+	}
+	middleware.SetAuditResource(c, models.ResourceAccessToken, token.UUID, token.Name)
+
+	response.Success(c, nil)
+}
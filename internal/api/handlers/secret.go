@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/database/models"
 	"github.com/cuihe500/vaulthub/internal/service"
 	"github.com/cuihe500/vaulthub/pkg/errors"
 	"github.com/cuihe500/vaulthub/pkg/logger"
@@ -13,12 +14,14 @@ import (
 // SecretHandler 秘密处理器
 type SecretHandler struct {
 	encryptionService *service.EncryptionService
+	importService     *service.ImportService
 }
 
 // NewSecretHandler 创建秘密处理器实例
-func NewSecretHandler(encryptionService *service.EncryptionService) *SecretHandler {
+func NewSecretHandler(encryptionService *service.EncryptionService, importService *service.ImportService) *SecretHandler {
 	return &SecretHandler{
 		encryptionService: encryptionService,
+		importService:     importService,
 	}
 }
 
@@ -157,6 +160,13 @@ func (h *SecretHandler) GetSecret(c *gin.Context) {
 		return
 	}
 
+	// 高敏感秘密的解密记录关联到对应的访问申请
+	if resp.AccessRequestUUID != "" {
+		middleware.SetAuditDetails(c, map[string]interface{}{
+			"access_request_uuid": resp.AccessRequestUUID,
+		})
+	}
+
 	response.Success(c, resp)
 }
 
@@ -190,6 +200,10 @@ func (h *SecretHandler) ListSecrets(c *gin.Context) {
 
 	// 使用当前用户的UUID
 	req.UserUUID = userUUID
+	// 限定了秘密范围的个人访问令牌只列出范围内的秘密
+	if accessToken, ok := middleware.GetCurrentAccessToken(c); ok {
+		req.SecretUUIDs = accessToken.SecretUUIDs
+	}
 
 	resp, err := h.encryptionService.ListUserSecrets(&req)
 	if err != nil {
@@ -207,7 +221,7 @@ func (h *SecretHandler) ListSecrets(c *gin.Context) {
 
 // DeleteSecret 删除秘密
 // @Summary 删除秘密
-// @Description 删除指定的秘密（软删除）
+// @Description 删除指定的秘密（软删除），删除后进入回收站，可在保留期内恢复
 // @Tags 秘密管理
 // @Accept json
 // @Produce json
@@ -243,3 +257,61 @@ func (h *SecretHandler) DeleteSecret(c *gin.Context) {
 
 	response.Success(c, gin.H{"message": "删除成功"})
 }
+
+// ImportSecrets 批量导入秘密
+// @Summary 批量导入秘密
+// @Description 从 .env、CSV（需列映射）、Bitwarden未加密JSON或KeePass XML导入秘密
+// @Description 整批只验证一次安全密码；dry_run=true时仅返回预览结果，不写入数据，可不传安全密码
+// @Description 单条记录的错误不会中断整批导入，结果中逐条返回处理状态
+// @Tags 秘密管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.ImportSecretsRequest true "批量导入请求"
+// @Success 200 {object} response.Response{data=service.ImportSecretsResponse}
+// @Router /api/v1/secrets/import [post]
+func (h *SecretHandler) ImportSecrets(c *gin.Context) {
+	// 获取当前用户UUID
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.ImportSecretsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("批量导入秘密请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	// 使用当前用户的UUID（防止用户伪造其他用户的UUID）
+	req.UserUUID = userUUID
+
+	middleware.SetAuditAction(c, models.ActionImport)
+	middleware.SetAuditResource(c, models.ResourceSecret, "", "")
+
+	resp, err := h.importService.ImportSecrets(&req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("批量导入秘密失败", logger.Err(err))
+			response.InternalError(c, "批量导入秘密失败")
+		}
+		return
+	}
+
+	// 审计详情只记录统计信息，不记录秘密内容
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"format":   resp.Format,
+		"dry_run":  resp.DryRun,
+		"total":    resp.Total,
+		"imported": resp.Imported,
+		"skipped":  resp.Skipped,
+		"failed":   resp.Failed,
+	})
+
+	response.Success(c, resp)
+}
@@ -0,0 +1,390 @@
+package handlers
+
+import (
+	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/cuihe500/vaulthub/pkg/validator"
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceAccountHandler 服务账户处理器
+type ServiceAccountHandler struct {
+	serviceAccountService *service.ServiceAccountService
+}
+
+// NewServiceAccountHandler 创建服务账户处理器实例
+func NewServiceAccountHandler(serviceAccountService *service.ServiceAccountService) *ServiceAccountHandler {
+	return &ServiceAccountHandler{
+		serviceAccountService: serviceAccountService,
+	}
+}
+
+// respondServiceAccountError 输出服务账户相关接口的错误响应
+func respondServiceAccountError(c *gin.Context, err error, message string) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		response.AppError(c, appErr)
+		return
+	}
+	logger.Error(message, logger.Err(err))
+	response.InternalError(c, message)
+}
+
+// Login 服务账户登录
+// @Summary 服务账户登录
+// @Description 服务账户以 role_id + secret_id 登录（AppRole），返回服务账户令牌。令牌有效期取系统配置 service_account_token_ttl_minutes 和 secret_id 剩余有效期中较短的一个，过期后重新登录。
+// @Description secret_id 过期、登录次数用完、来源IP不在绑定的CIDR内或服务账户所属用户被禁用时登录失败。服务账户令牌只能访问 /api/v1/machine 下的接口。
+// @Tags 服务账户
+// @Accept json
+// @Produce json
+// @Param request body service.AppRoleLoginRequest true "role_id 和 secret_id"
+// @Success 200 {object} response.Response{data=service.AppRoleLoginResponse}
+// @Router /api/v1/auth/approle/login [post]
+func (h *ServiceAccountHandler) Login(c *gin.Context) {
+	var req service.AppRoleLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("服务账户登录请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.ClientIP = c.ClientIP()
+
+	resp, err := h.serviceAccountService.Login(&req)
+	if err != nil {
+		respondServiceAccountError(c, err, "服务账户登录失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// CreateServiceAccount 创建服务账户
+// @Summary 创建服务账户
+// @Description 在当前用户的保险库下创建服务账户，供应用程序代替用户访问秘密。服务账户不持有保险库密钥，只能解密授权时为它封装的秘密。
+// @Description 返回的 role_id 用于登录，还需要再生成 secret_id；服务账户只能读取通过 PUT /api/v1/service-accounts/{uuid}/secrets 授权的秘密。
+// @Tags 服务账户
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.CreateServiceAccountRequest true "名称和描述"
+// @Success 200 {object} response.Response{data=service.ServiceAccountInfo}
+// @Router /api/v1/service-accounts [post]
+func (h *ServiceAccountHandler) CreateServiceAccount(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.CreateServiceAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.OwnerUUID = userUUID
+
+	middleware.SetAuditAction(c, models.ActionCreate)
+	middleware.SetAuditResource(c, models.ResourceServiceAccount, "", req.Name)
+
+	resp, err := h.serviceAccountService.Create(&req)
+	if err != nil {
+		respondServiceAccountError(c, err, "创建服务账户失败")
+		return
+	}
+	middleware.SetAuditResource(c, models.ResourceServiceAccount, resp.UUID, resp.Name)
+
+	response.Success(c, resp)
+}
+
+// ListServiceAccounts 查询服务账户列表
+// @Summary 查询服务账户列表
+// @Description 查询当前用户的服务账户及每个服务账户可以访问的秘密
+// @Tags 服务账户
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=service.ServiceAccountListResponse}
+// @Router /api/v1/service-accounts [get]
+func (h *ServiceAccountHandler) ListServiceAccounts(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	resp, err := h.serviceAccountService.List(userUUID)
+	if err != nil {
+		respondServiceAccountError(c, err, "查询服务账户失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// GetServiceAccount 查询服务账户详情
+// @Summary 查询服务账户详情
+// @Description 查询当前用户的一个服务账户及其可以访问的秘密
+// @Tags 服务账户
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "服务账户UUID"
+// @Success 200 {object} response.Response{data=service.ServiceAccountInfo}
+// @Router /api/v1/service-accounts/{uuid} [get]
+func (h *ServiceAccountHandler) GetServiceAccount(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	resp, err := h.serviceAccountService.Get(userUUID, c.Param("uuid"))
+	if err != nil {
+		respondServiceAccountError(c, err, "查询服务账户失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// DeleteServiceAccount 删除服务账户
+// @Summary 删除服务账户
+// @Description 删除当前用户的一个服务账户，同时删除它的 secret_id 和秘密授权，已签发的服务账户令牌立即失效
+// @Tags 服务账户
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "服务账户UUID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/service-accounts/{uuid} [delete]
+func (h *ServiceAccountHandler) DeleteServiceAccount(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	accountUUID := c.Param("uuid")
+	middleware.SetAuditAction(c, models.ActionDelete)
+	middleware.SetAuditResource(c, models.ResourceServiceAccount, accountUUID, "")
+
+	account, err := h.serviceAccountService.Delete(userUUID, accountUUID)
+	if err != nil {
+		respondServiceAccountError(c, err, "删除服务账户失败")
+		return
+	}
+	middleware.SetAuditResource(c, models.ResourceServiceAccount, account.UUID, account.Name)
+
+	response.Success(c, nil)
+}
+
+// CreateSecretID 生成secret_id
+// @Summary 生成secret_id
+// @Description 为服务账户生成新的 secret_id，明文只在本次响应中返回，请妥善保存。
+// @Description bound_cidrs 为空时不限制来源IP；max_uses 为 0 时不限制登录次数；有效期不能超过系统配置 service_account_secret_id_max_days。
+// @Description 轮换时先生成新的 secret_id，应用切换后再撤销旧的。
+// @Tags 服务账户
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "服务账户UUID"
+// @Param request body service.CreateSecretIDRequest true "CIDR绑定、登录次数和有效期"
+// @Success 200 {object} response.Response{data=service.CreateSecretIDResponse}
+// @Router /api/v1/service-accounts/{uuid}/secret-ids [post]
+func (h *ServiceAccountHandler) CreateSecretID(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.CreateSecretIDRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.OwnerUUID = userUUID
+	req.ServiceAccountUUID = c.Param("uuid")
+
+	middleware.SetAuditAction(c, models.ActionCreate)
+	middleware.SetAuditResource(c, models.ResourceServiceAccount, req.ServiceAccountUUID, "")
+
+	resp, err := h.serviceAccountService.CreateSecretID(&req)
+	if err != nil {
+		respondServiceAccountError(c, err, "生成secret_id失败")
+		return
+	}
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"secret_id_uuid": resp.SecretIDInfo.UUID,
+		"bound_cidrs":    resp.SecretIDInfo.BoundCIDRs,
+		"max_uses":       resp.SecretIDInfo.MaxUses,
+		"expires_at":     resp.SecretIDInfo.ExpiresAt,
+	})
+
+	response.Success(c, resp)
+}
+
+// ListSecretIDs 查询secret_id列表
+// @Summary 查询secret_id列表
+// @Description 查询服务账户的 secret_id（包括已过期和次数已用完的），返回CIDR绑定、登录次数、过期时间和最后使用时间及IP，不返回明文
+// @Tags 服务账户
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "服务账户UUID"
+// @Success 200 {object} response.Response{data=service.SecretIDListResponse}
+// @Router /api/v1/service-accounts/{uuid}/secret-ids [get]
+func (h *ServiceAccountHandler) ListSecretIDs(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	resp, err := h.serviceAccountService.ListSecretIDs(userUUID, c.Param("uuid"))
+	if err != nil {
+		respondServiceAccountError(c, err, "查询secret_id失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// RevokeSecretID 撤销secret_id
+// @Summary 撤销secret_id
+// @Description 撤销服务账户的一个 secret_id，用它登录获得的服务账户令牌立即失效
+// @Tags 服务账户
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "服务账户UUID"
+// @Param secret_id_uuid path string true "secret_id的UUID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/service-accounts/{uuid}/secret-ids/{secret_id_uuid} [delete]
+func (h *ServiceAccountHandler) RevokeSecretID(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	accountUUID := c.Param("uuid")
+	secretIDUUID := c.Param("secret_id_uuid")
+	middleware.SetAuditAction(c, models.ActionDelete)
+	middleware.SetAuditResource(c, models.ResourceServiceAccount, accountUUID, "")
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"secret_id_uuid": secretIDUUID,
+	})
+
+	if err := h.serviceAccountService.RevokeSecretID(userUUID, accountUUID, secretIDUUID); err != nil {
+		respondServiceAccountError(c, err, "撤销secret_id失败")
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// SetGrants 设置服务账户可以访问的秘密
+// @Summary 设置服务账户可以访问的秘密
+// @Description 用给定的列表替换服务账户可以访问的秘密，传空列表撤销全部授权。秘密必须属于当前用户，需要检出或审批的秘密不能授权给服务账户。
+// @Description 新增授权时需要安全密码：服务端解密新增的秘密并用服务账户的公钥封装一份明文，服务账户无法解密未授权的秘密。只撤销授权时不需要安全密码。
+// @Tags 服务账户
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "服务账户UUID"
+// @Param request body service.SetGrantsRequest true "秘密UUID列表和安全密码"
+// @Success 200 {object} response.Response{data=service.ServiceAccountInfo}
+// @Router /api/v1/service-accounts/{uuid}/secrets [put]
+func (h *ServiceAccountHandler) SetGrants(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.SetGrantsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.OwnerUUID = userUUID
+	req.ServiceAccountUUID = c.Param("uuid")
+
+	middleware.SetAuditAction(c, models.ActionGrant)
+	middleware.SetAuditResource(c, models.ResourceServiceAccount, req.ServiceAccountUUID, "")
+
+	resp, err := h.serviceAccountService.SetGrants(&req)
+	if err != nil {
+		respondServiceAccountError(c, err, "设置服务账户授权失败")
+		return
+	}
+	middleware.SetAuditResource(c, models.ResourceServiceAccount, resp.UUID, resp.Name)
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"secret_uuids": resp.SecretUUIDs,
+	})
+
+	response.Success(c, resp)
+}
+
+// ListMachineSecrets 服务账户查询可以访问的秘密
+// @Summary 服务账户查询可以访问的秘密
+// @Description 使用服务账户令牌查询被授权的秘密（不包含加密数据）
+// @Tags 服务账户
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=service.GrantedSecretListResponse}
+// @Router /api/v1/machine/secrets [get]
+func (h *ServiceAccountHandler) ListMachineSecrets(c *gin.Context) {
+	account, exists := middleware.GetCurrentServiceAccount(c)
+	if !exists {
+		logger.Error("无法获取当前服务账户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	resp, err := h.serviceAccountService.ListGrantedSecrets(account)
+	if err != nil {
+		respondServiceAccountError(c, err, "查询秘密列表失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// GetMachineSecret 服务账户读取秘密
+// @Summary 服务账户读取秘密
+// @Description 使用服务账户令牌解密被授权的秘密，不需要安全密码。保险库密钥轮换期间返回冲突错误，稍后重试即可。
+// @Tags 服务账户
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "秘密UUID"
+// @Success 200 {object} response.Response{data=github_com_cuihe500_vaulthub_internal_database_models.DecryptedSecret}
+// @Router /api/v1/machine/secrets/{uuid} [get]
+func (h *ServiceAccountHandler) GetMachineSecret(c *gin.Context) {
+	account, exists := middleware.GetCurrentServiceAccount(c)
+	if !exists {
+		logger.Error("无法获取当前服务账户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	secretUUID := c.Param("uuid")
+	middleware.SetAuditAction(c, models.ActionAccess)
+	middleware.SetAuditResource(c, models.ResourceSecret, secretUUID, "")
+
+	resp, err := h.serviceAccountService.DecryptGrantedSecret(account, secretUUID)
+	if err != nil {
+		respondServiceAccountError(c, err, "读取秘密失败")
+		return
+	}
+	middleware.SetAuditResource(c, models.ResourceSecret, resp.SecretUUID, resp.SecretName)
+
+	response.Success(c, resp)
+}
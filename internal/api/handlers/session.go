@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// SessionHandler 登录会话处理器
+type SessionHandler struct {
+	sessionService *service.SessionService
+}
+
+// NewSessionHandler 创建登录会话处理器实例
+func NewSessionHandler(sessionService *service.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// respondSessionError 输出登录会话相关接口的错误响应
+func respondSessionError(c *gin.Context, err error, message string) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		response.AppError(c, appErr)
+		return
+	}
+	logger.Error(message, logger.Err(err))
+	response.InternalError(c, message)
+}
+
+// currentUserSession 获取当前用户UUID和会话UUID，获取失败时已输出响应
+func currentUserSession(c *gin.Context) (string, string, bool) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	sessionUUID, sessionExists := middleware.GetCurrentSessionUUID(c)
+	if !exists || !sessionExists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return "", "", false
+	}
+	return userUUID, sessionUUID, true
+}
+
+// ListSessions 查询登录会话列表
+// @Summary 查询登录会话列表
+// @Description 查询当前用户未过期的登录会话（设备名称、IP、User-Agent、创建和最后活跃时间），current标记发起请求的会话
+// @Tags 登录会话
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=service.SessionListResponse}
+// @Router /api/v1/auth/sessions [get]
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	userUUID, sessionUUID, ok := currentUserSession(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.sessionService.List(userUUID, sessionUUID)
+	if err != nil {
+		respondSessionError(c, err, "查询登录会话失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// RevokeSession 注销登录会话
+// @Summary 注销登录会话
+// @Description 注销当前用户的一个会话，该会话的访问令牌和刷新令牌立即失效；注销当前会话等同于登出
+// @Tags 登录会话
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "会话UUID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/auth/sessions/{uuid} [delete]
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	sessionUUID := c.Param("uuid")
+	middleware.SetAuditAction(c, models.ActionSessionRevoke)
+	middleware.SetAuditResource(c, models.ResourceUser, user.UUID, user.Username)
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"session_uuid": sessionUUID,
+	})
+
+	session, err := h.sessionService.Revoke(user.UUID, sessionUUID)
+	if err != nil {
+		respondSessionError(c, err, "注销登录会话失败")
+		return
+	}
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"session_uuid": session.UUID,
+		"device_label": session.DeviceLabel,
+		"ip_address":   session.IPAddress,
+	})
+
+	response.Success(c, nil)
+}
+
+// RevokeOtherSessions 注销其他登录会话
+// @Summary 注销其他登录会话
+// @Description 注销当前用户除当前会话以外的全部会话，返回注销的数量
+// @Tags 登录会话
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=map[string]int}
+// @Router /api/v1/auth/sessions [delete]
+func (h *SessionHandler) RevokeOtherSessions(c *gin.Context) {
+	userUUID, sessionUUID, ok := currentUserSession(c)
+	if !ok {
+		return
+	}
+
+	middleware.SetAuditAction(c, models.ActionSessionRevoke)
+	middleware.SetAuditResource(c, models.ResourceUser, userUUID, "")
+
+	count, err := h.sessionService.RevokeOthers(userUUID, sessionUUID)
+	if err != nil {
+		respondSessionError(c, err, "注销其他登录会话失败")
+		return
+	}
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation": "revoke_others",
+		"count":     count,
+	})
+
+	response.Success(c, gin.H{"revoked": count})
+}
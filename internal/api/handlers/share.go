@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/cuihe500/vaulthub/pkg/validator"
+	"github.com/gin-gonic/gin"
+)
+
+// ShareHandler 一次性分享链接处理器
+type ShareHandler struct {
+	shareService *service.ShareService
+}
+
+// NewShareHandler 创建分享链接处理器实例
+func NewShareHandler(shareService *service.ShareService) *ShareHandler {
+	return &ShareHandler{
+		shareService: shareService,
+	}
+}
+
+// CreateShare 创建一次性分享链接
+// @Summary 创建一次性分享链接
+// @Description 将已保存的秘密（需要安全密码）或一段临时文本生成阅后即焚链接
+// @Description 内容使用随机密钥加密后暂存，密钥只包含在返回的链接#之后，服务端不保存
+// @Description 查看次数用完或过期后内容被销毁；每次查看都会记录到创建者的审计日志
+// @Tags 分享
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.CreateShareRequest true "创建分享请求"
+// @Success 200 {object} response.Response{data=service.CreateShareResponse}
+// @Router /api/v1/shares [post]
+func (h *ShareHandler) CreateShare(c *gin.Context) {
+	// 获取当前用户UUID
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.CreateShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("创建分享请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	// 使用当前用户的UUID
+	req.UserUUID = userUUID
+
+	// 获取请求的scheme和host用于构建分享链接
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	baseURL := scheme + "://" + c.Request.Host
+
+	middleware.SetAuditAction(c, models.ActionCreate)
+
+	resp, err := h.shareService.CreateShare(&req, baseURL)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("创建分享失败", logger.Err(err))
+			response.InternalError(c, "创建分享失败")
+		}
+		return
+	}
+
+	middleware.SetAuditResource(c, models.ResourceShare, resp.ShareUUID, "")
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"secret_uuid": req.SecretUUID,
+		"max_views":   resp.MaxViews,
+		"expires_at":  resp.ExpiresAt,
+		"passphrase":  req.Passphrase != "",
+	})
+	response.Success(c, resp)
+}
+
+// GetShareInfo 获取分享信息
+// @Summary 获取分享信息
+// @Description 获取分享是否存在、是否需要口令、剩余次数等信息，不返回内容也不消耗查看次数。无需登录即可访问
+// @Tags 分享
+// @Accept json
+// @Produce json
+// @Param uuid path string true "分享UUID"
+// @Success 200 {object} response.Response{data=service.ShareInfo}
+// @Router /api/v1/shares/{uuid} [get]
+func (h *ShareHandler) GetShareInfo(c *gin.Context) {
+	shareUUID := c.Param("uuid")
+	if shareUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	resp, err := h.shareService.GetShareInfo(shareUUID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("获取分享信息失败", logger.Err(err))
+			response.InternalError(c, "获取分享信息失败")
+		}
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// OpenShare 查看分享内容
+// @Summary 查看分享内容
+// @Description 消耗一次查看次数并返回密文，客户端使用链接#之后的密钥以AES-256-GCM解密。无需登录即可访问
+// @Description 口令连续错误5次后分享被销毁
+// @Tags 分享
+// @Accept json
+// @Produce json
+// @Param uuid path string true "分享UUID"
+// @Param request body service.OpenShareRequest false "查看口令"
+// @Success 200 {object} response.Response{data=service.OpenShareResponse}
+// @Router /api/v1/shares/{uuid}/open [post]
+func (h *ShareHandler) OpenShare(c *gin.Context) {
+	shareUUID := c.Param("uuid")
+	if shareUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	// 请求体可选，未设置口令的分享可以不传
+	var req service.OpenShareRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Warn("查看分享请求参数无效", logger.Err(err))
+			response.ValidationError(c, validator.TranslateError(err))
+			return
+		}
+	}
+	req.ShareUUID = shareUUID
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+	req.RequestID = c.GetString(response.RequestIDKey)
+
+	resp, err := h.shareService.OpenShare(&req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("查看分享失败", logger.Err(err))
+			response.InternalError(c, "查看分享失败")
+		}
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// RevokeShare 撤销分享链接
+// @Summary 撤销分享链接
+// @Description 在分享被查看前提前销毁，只能撤销自己创建的分享
+// @Tags 分享
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "分享UUID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/shares/{uuid} [delete]
+func (h *ShareHandler) RevokeShare(c *gin.Context) {
+	// 获取当前用户UUID
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	shareUUID := c.Param("uuid")
+	if shareUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	middleware.SetAuditAction(c, models.ActionDelete)
+	middleware.SetAuditResource(c, models.ResourceShare, shareUUID, "")
+
+	if err := h.shareService.RevokeShare(userUUID, shareUUID); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("撤销分享失败", logger.Err(err))
+			response.InternalError(c, "撤销分享失败")
+		}
+		return
+	}
+
+	response.Success(c, nil)
+}
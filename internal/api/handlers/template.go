@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/cuihe500/vaulthub/pkg/validator"
+	"github.com/gin-gonic/gin"
+)
+
+// TemplateHandler 配置模板渲染处理器
+type TemplateHandler struct {
+	templateService *service.TemplateService
+}
+
+// NewTemplateHandler 创建配置模板渲染处理器实例
+func NewTemplateHandler(templateService *service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+	}
+}
+
+// Render 渲染配置模板
+// @Summary 渲染配置模板
+// @Description 使用Go text/template语法渲染配置文件，通过 {{ secret "文件夹/名称" }} 或 {{ secret "文件夹/名称" "JSON字段" }} 引用秘密。所有引用在渲染前解析，任一引用不存在则拒绝渲染；每个被引用的秘密都以同一请求ID记录访问审计。需要检出或未获审批的高敏感秘密不能引用
+// @Tags 秘密管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.RenderTemplateRequest true "模板内容和安全密码"
+// @Success 200 {object} response.Response{data=service.RenderTemplateResponse}
+// @Router /api/v1/secrets/render [post]
+func (h *TemplateHandler) Render(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.RenderTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("渲染模板请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.UserUUID = user.UUID
+	req.Username = user.Username
+	req.RequestID = c.GetString(response.RequestIDKey)
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+	// 限定了秘密范围的个人访问令牌只能引用范围内的秘密
+	if accessToken, ok := middleware.GetCurrentAccessToken(c); ok {
+		req.SecretUUIDs = accessToken.SecretUUIDs
+	}
+
+	middleware.SetAuditAction(c, models.ActionAccess)
+	middleware.SetAuditResource(c, models.ResourceVault, user.UUID, "")
+
+	resp, err := h.templateService.Render(&req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+			return
+		}
+		logger.Error("渲染模板失败", logger.Err(err))
+		response.InternalError(c, "渲染模板失败")
+		return
+	}
+
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation":    "template_render",
+		"secret_count": len(resp.Secrets),
+	})
+	response.Success(c, resp)
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/cuihe500/vaulthub/pkg/validator"
+	"github.com/gin-gonic/gin"
+)
+
+// TrashHandler 秘密回收站处理器
+type TrashHandler struct {
+	trashService *service.TrashService
+}
+
+// NewTrashHandler 创建回收站处理器实例
+func NewTrashHandler(trashService *service.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// ListTrash 获取回收站列表
+// @Summary 获取回收站列表
+// @Description 获取当前用户已删除但尚未彻底清除的秘密，按删除时间倒序
+// @Description 超过保留天数（系统配置 secret_trash_retention_days）的秘密会被定时任务彻底清除
+// @Tags 秘密管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" minimum(1)
+// @Param page_size query int false "每页数量" minimum(1) maximum(100)
+// @Success 200 {object} response.Response{data=service.ListTrashResponse}
+// @Router /api/v1/secrets/trash [get]
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	// 获取当前用户UUID
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.ListTrashRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("获取回收站列表请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	// 使用当前用户的UUID
+	req.UserUUID = userUUID
+
+	resp, err := h.trashService.ListTrash(&req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("获取回收站列表失败", logger.Err(err))
+			response.InternalError(c, "获取回收站列表失败")
+		}
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// RestoreSecret 从回收站恢复秘密
+// @Summary 从回收站恢复秘密
+// @Description 恢复回收站中的指定秘密
+// @Tags 秘密管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "秘密UUID"
+// @Success 200 {object} response.Response{data=github_com_cuihe500_vaulthub_internal_database_models.SafeEncryptedSecret}
+// @Router /api/v1/secrets/trash/{uuid}/restore [post]
+func (h *TrashHandler) RestoreSecret(c *gin.Context) {
+	// 获取当前用户UUID
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	secretUUID := c.Param("uuid")
+	if secretUUID == "" {
+		response.MissingParam(c, "uuid参数必填")
+		return
+	}
+
+	middleware.SetAuditAction(c, models.ActionUpdate)
+	middleware.SetAuditResource(c, models.ResourceSecret, secretUUID, "")
+	middleware.SetAuditDetails(c, map[string]interface{}{"operation": "restore_from_trash"})
+
+	resp, err := h.trashService.RestoreSecret(userUUID, secretUUID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("恢复秘密失败", logger.Err(err))
+			response.InternalError(c, "恢复秘密失败")
+		}
+		return
+	}
+
+	middleware.SetAuditResource(c, models.ResourceSecret, resp.SecretUUID, resp.SecretName)
+	response.Success(c, resp)
+}
+
+// PurgeTrash 立即清除回收站
+// @Summary 立即清除回收站
+// @Description 彻底删除回收站中的秘密，删除后不可恢复（需要输入安全密码）
+// @Description secret_uuids 为空时清空整个回收站
+// @Tags 秘密管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.PurgeTrashRequest true "清除请求"
+// @Success 200 {object} response.Response{data=service.PurgeTrashResponse}
+// @Router /api/v1/secrets/trash/purge [post]
+func (h *TrashHandler) PurgeTrash(c *gin.Context) {
+	// 获取当前用户UUID
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.PurgeTrashRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("清除回收站请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	// 使用当前用户的UUID（防止用户伪造其他用户的UUID）
+	req.UserUUID = userUUID
+
+	middleware.SetAuditAction(c, models.ActionDelete)
+	middleware.SetAuditResource(c, models.ResourceSecret, "", "")
+
+	resp, err := h.trashService.PurgeTrash(&req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+		} else {
+			logger.Error("清除回收站失败", logger.Err(err))
+			response.InternalError(c, "清除回收站失败")
+		}
+		return
+	}
+
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"trigger":      "manual",
+		"secret_uuids": req.SecretUUIDs,
+		"purged":       resp.Purged,
+	})
+
+	response.Success(c, resp)
+}
@@ -89,7 +89,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 
 // UpdateUserStatus 更新用户状态
 // @Summary 更新用户状态
-// @Description 更新用户状态（需要管理员权限）
+// @Description 更新用户状态（需要管理员权限）。将登录失败自动锁定（status=3）的账户改为活跃（status=1）即解锁。状态变化后该用户已签发的令牌和登录会话全部失效
 // @Tags 用户管理
 // @Accept json
 // @Produce json
@@ -128,7 +128,7 @@ func (h *UserHandler) UpdateUserStatus(c *gin.Context) {
 
 // UpdateUserRole 更新用户角色
 // @Summary 更新用户角色
-// @Description 更新用户角色（需要管理员权限）
+// @Description 更新用户角色（需要管理员权限）。角色变化后该用户已签发的令牌和登录会话全部失效，需要重新登录
 // @Tags 用户管理
 // @Accept json
 // @Produce json
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/cuihe500/vaulthub/pkg/validator"
+	"github.com/gin-gonic/gin"
+)
+
+// VaultHealthHandler 保险库健康检查处理器
+type VaultHealthHandler struct {
+	healthService *service.VaultHealthService
+}
+
+// NewVaultHealthHandler 创建保险库健康检查处理器实例
+func NewVaultHealthHandler(healthService *service.VaultHealthService) *VaultHealthHandler {
+	return &VaultHealthHandler{
+		healthService: healthService,
+	}
+}
+
+// GetReport 查询保险库健康报告
+// @Summary 查询保险库健康报告
+// @Description 列出重复使用的密码、弱密码、长期未变更和从未访问的秘密。重复检测基于写入时计算的密码指纹，服务端不解密秘密
+// @Tags 保险库健康
+// @Produce json
+// @Security BearerAuth
+// @Param stale_days query int false "超过多少天未变更视为过旧，默认取系统配置" minimum(1) maximum(3650)
+// @Success 200 {object} response.Response{data=service.VaultHealthReport}
+// @Router /api/v1/vault/health [get]
+func (h *VaultHealthHandler) GetReport(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.VaultHealthRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("查询保险库健康报告请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.UserUUID = userUUID
+
+	resp, err := h.healthService.GetReport(&req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+			return
+		}
+		logger.Error("查询保险库健康报告失败", logger.Err(err))
+		response.InternalError(c, "查询保险库健康报告失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// Rescan 重新扫描密码
+// @Summary 重新扫描密码
+// @Description 用安全密码解密DEK，重新计算所有密码类秘密的指纹和强度评分（用于功能上线前创建的秘密）。明文不会返回，也不计入访问统计
+// @Tags 保险库健康
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.RescanRequest true "安全密码"
+// @Success 200 {object} response.Response{data=service.RescanResponse}
+// @Router /api/v1/vault/health/rescan [post]
+func (h *VaultHealthHandler) Rescan(c *gin.Context) {
+	userUUID, exists := middleware.GetCurrentUserUUID(c)
+	if !exists {
+		logger.Error("无法获取当前用户UUID")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.RescanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("重新扫描密码请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+	req.UserUUID = userUUID
+
+	middleware.SetAuditAction(c, models.ActionUpdate)
+	middleware.SetAuditResource(c, models.ResourceVault, userUUID, "")
+
+	resp, err := h.healthService.Rescan(&req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			response.AppError(c, appErr)
+			return
+		}
+		logger.Error("重新扫描密码失败", logger.Err(err))
+		response.InternalError(c, "重新扫描密码失败")
+		return
+	}
+
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation": "vault_health_rescan",
+		"scanned":   resp.Scanned,
+		"failed":    resp.Failed,
+	})
+	response.Success(c, resp)
+}
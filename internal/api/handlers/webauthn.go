@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"github.com/cuihe500/vaulthub/internal/api/middleware"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/cuihe500/vaulthub/pkg/validator"
+	"github.com/gin-gonic/gin"
+)
+
+// WebAuthnHandler 通行密钥处理器
+type WebAuthnHandler struct {
+	webAuthnService *service.WebAuthnService
+}
+
+// NewWebAuthnHandler 创建通行密钥处理器实例
+func NewWebAuthnHandler(webAuthnService *service.WebAuthnService) *WebAuthnHandler {
+	return &WebAuthnHandler{
+		webAuthnService: webAuthnService,
+	}
+}
+
+// respondWebAuthnError 输出通行密钥相关接口的错误响应
+func respondWebAuthnError(c *gin.Context, err error, message string) {
+	if appErr, ok := err.(*errors.AppError); ok {
+		response.AppError(c, appErr)
+		return
+	}
+	logger.Error(message, logger.Err(err))
+	response.InternalError(c, message)
+}
+
+// currentSession 获取当前用户和访问令牌，获取失败时已输出响应
+func currentSession(c *gin.Context) (*models.User, string, bool) {
+	user, exists := middleware.GetCurrentUser(c)
+	token, tokenExists := middleware.GetCurrentToken(c)
+	if !exists || !tokenExists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return nil, "", false
+	}
+	return user, token, true
+}
+
+// ListCredentials 查询通行密钥列表
+// @Summary 查询通行密钥列表
+// @Description 查询当前用户已注册的通行密钥，以及角色访问秘密前是否必须二次验证
+// @Tags 通行密钥
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=service.WebAuthnCredentialListResponse}
+// @Router /api/v1/auth/webauthn/credentials [get]
+func (h *WebAuthnHandler) ListCredentials(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	resp, err := h.webAuthnService.ListCredentials(user)
+	if err != nil {
+		respondWebAuthnError(c, err, "查询通行密钥失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// BeginRegistration 开始注册通行密钥
+// @Summary 开始注册通行密钥
+// @Description 返回传给 navigator.credentials.create() 的参数，需要在5分钟内调用完成注册接口。
+// @Description 已有通行密钥时需要先完成二次验证；默认不要求认证器证明（attestation为none）
+// @Tags 通行密钥
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.WebAuthnCredentialNameRequest true "通行密钥名称"
+// @Success 200 {object} response.Response{data=service.WebAuthnRegistrationResponse}
+// @Router /api/v1/auth/webauthn/register/begin [post]
+func (h *WebAuthnHandler) BeginRegistration(c *gin.Context) {
+	user, token, ok := currentSession(c)
+	if !ok {
+		return
+	}
+
+	var req service.WebAuthnCredentialNameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("注册通行密钥请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	resp, err := h.webAuthnService.BeginRegistration(user, token, req.Name)
+	if err != nil {
+		respondWebAuthnError(c, err, "注册通行密钥失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// FinishRegistration 完成注册通行密钥
+// @Summary 完成注册通行密钥
+// @Description 提交 navigator.credentials.create() 返回的凭证，校验通过后保存通行密钥
+// @Tags 通行密钥
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.WebAuthnCredentialRequest true "认证器注册响应"
+// @Success 200 {object} response.Response{data=models.WebAuthnCredential}
+// @Router /api/v1/auth/webauthn/register/finish [post]
+func (h *WebAuthnHandler) FinishRegistration(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.WebAuthnCredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("完成注册通行密钥请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	middleware.SetAuditAction(c, models.ActionWebAuthnRegister)
+	middleware.SetAuditResource(c, models.ResourceUser, user.UUID, user.Username)
+
+	credential, err := h.webAuthnService.FinishRegistration(user, req.Credential)
+	if err != nil {
+		respondWebAuthnError(c, err, "注册通行密钥失败")
+		return
+	}
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"credential_uuid": credential.UUID,
+		"credential_name": credential.Name,
+	})
+
+	response.Success(c, credential)
+}
+
+// RenameCredential 重命名通行密钥
+// @Summary 重命名通行密钥
+// @Description 修改通行密钥的名称，同一用户的通行密钥名称不能重复
+// @Tags 通行密钥
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "通行密钥UUID"
+// @Param request body service.WebAuthnCredentialNameRequest true "新名称"
+// @Success 200 {object} response.Response{data=models.WebAuthnCredential}
+// @Router /api/v1/auth/webauthn/credentials/{uuid} [put]
+func (h *WebAuthnHandler) RenameCredential(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	var req service.WebAuthnCredentialNameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("重命名通行密钥请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	credentialUUID := c.Param("uuid")
+	middleware.SetAuditAction(c, models.ActionUpdate)
+	middleware.SetAuditResource(c, models.ResourceUser, user.UUID, user.Username)
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"operation":       "webauthn_rename",
+		"credential_uuid": credentialUUID,
+		"credential_name": req.Name,
+	})
+
+	credential, err := h.webAuthnService.RenameCredential(user.UUID, credentialUUID, req.Name)
+	if err != nil {
+		respondWebAuthnError(c, err, "重命名通行密钥失败")
+		return
+	}
+
+	response.Success(c, credential)
+}
+
+// DeleteCredential 删除通行密钥
+// @Summary 删除通行密钥
+// @Description 删除当前用户的通行密钥，需要当前会话已完成二次验证
+// @Tags 通行密钥
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "通行密钥UUID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/auth/webauthn/credentials/{uuid} [delete]
+func (h *WebAuthnHandler) DeleteCredential(c *gin.Context) {
+	user, token, ok := currentSession(c)
+	if !ok {
+		return
+	}
+
+	credentialUUID := c.Param("uuid")
+	middleware.SetAuditAction(c, models.ActionWebAuthnRemove)
+	middleware.SetAuditResource(c, models.ResourceUser, user.UUID, user.Username)
+
+	credential, err := h.webAuthnService.DeleteCredential(user.UUID, token, credentialUUID)
+	if err != nil {
+		respondWebAuthnError(c, err, "删除通行密钥失败")
+		return
+	}
+	middleware.SetAuditDetails(c, map[string]interface{}{
+		"credential_uuid": credential.UUID,
+		"credential_name": credential.Name,
+	})
+
+	response.Success(c, nil)
+}
+
+// BeginStepUp 开始二次验证
+// @Summary 开始二次验证
+// @Description 返回传给 navigator.credentials.get() 的参数，只允许使用当前用户已注册的通行密钥
+// @Tags 通行密钥
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=service.WebAuthnAssertionResponse}
+// @Router /api/v1/auth/step-up/begin [post]
+func (h *WebAuthnHandler) BeginStepUp(c *gin.Context) {
+	user, exists := middleware.GetCurrentUser(c)
+	if !exists {
+		logger.Error("无法获取当前用户")
+		response.Unauthorized(c, "未授权")
+		return
+	}
+
+	resp, err := h.webAuthnService.BeginStepUp(user)
+	if err != nil {
+		respondWebAuthnError(c, err, "开始二次验证失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// FinishStepUp 完成二次验证
+// @Summary 完成二次验证
+// @Description 提交 navigator.credentials.get() 返回的凭证，校验通过后当前令牌在10分钟内视为已完成二次验证，
+// @Description 可以访问要求二次验证的秘密接口（系统配置 webauthn_step_up_roles 中的角色）
+// @Tags 通行密钥
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body service.WebAuthnCredentialRequest true "认证器验证响应"
+// @Success 200 {object} response.Response{data=service.StepUpResponse}
+// @Router /api/v1/auth/step-up/finish [post]
+func (h *WebAuthnHandler) FinishStepUp(c *gin.Context) {
+	user, token, ok := currentSession(c)
+	if !ok {
+		return
+	}
+
+	var req service.WebAuthnCredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("二次验证请求参数无效", logger.Err(err))
+		response.ValidationError(c, validator.TranslateError(err))
+		return
+	}
+
+	middleware.SetAuditAction(c, models.ActionStepUp)
+	middleware.SetAuditResource(c, models.ResourceUser, user.UUID, user.Username)
+
+	resp, err := h.webAuthnService.FinishStepUp(user, token, req.Credential)
+	if err != nil {
+		respondWebAuthnError(c, err, "二次验证失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// BeginLogin 开始通行密钥登录
+// @Summary 开始通行密钥登录
+// @Description 无需用户名，返回 session_token 和传给 navigator.credentials.get() 的参数，5分钟内有效
+// @Tags 认证
+// @Produce json
+// @Success 200 {object} response.Response{data=service.WebAuthnAssertionResponse}
+// @Router /api/v1/auth/login/webauthn/begin [post]
+func (h *WebAuthnHandler) BeginLogin(c *gin.Context) {
+	resp, err := h.webAuthnService.BeginLogin()
+	if err != nil {
+		respondWebAuthnError(c, err, "开始通行密钥登录失败")
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// ResetUserCredentials 管理员重置用户的通行密钥
+// @Summary 重置用户的通行密钥
+// @Description 用户丢失所有认证器时由管理员删除其全部通行密钥（需要user:write权限）
+// @Tags 用户管理
+// @Produce json
+// @Security BearerAuth
+// @Param uuid path string true "用户UUID"
+// @Success 200 {object} response.Response
+// @Router /api/v1/users/{uuid}/webauthn/reset [post]
+func (h *WebAuthnHandler) ResetUserCredentials(c *gin.Context) {
+	userUUID := c.Param("uuid")
+	if userUUID == "" {
+		response.ValidationError(c, "uuid参数必填")
+		return
+	}
+
+	middleware.SetAuditAction(c, models.ActionWebAuthnReset)
+	middleware.SetAuditResource(c, models.ResourceUser, userUUID, "")
+
+	user, err := h.webAuthnService.Reset(userUUID)
+	if err != nil {
+		respondWebAuthnError(c, err, "重置通行密钥失败")
+		return
+	}
+	middleware.SetAuditResource(c, models.ResourceUser, userUUID, user.Username)
+
+	response.Success(c, nil)
+}
@@ -22,6 +22,10 @@ const (
 	AuditDetailsKey      = "audit_details"
 )
 
+// auditBodyCaptureLimit 响应体最多缓存的字节数
+// 只用于记录失败请求的错误信息，避免附件下载等大响应被完整缓存在内存中
+const auditBodyCaptureLimit = 1024
+
 // responseWriter 包装gin.ResponseWriter以捕获响应状态码
 type responseWriter struct {
 	gin.ResponseWriter
@@ -35,7 +39,13 @@ func (w *responseWriter) WriteHeader(code int) {
 }
 
 func (w *responseWriter) Write(b []byte) (int, error) {
-	w.body.Write(b)
+	if remaining := auditBodyCaptureLimit - w.body.Len(); remaining > 0 {
+		if len(b) > remaining {
+			w.body.Write(b[:remaining])
+		} else {
+			w.body.Write(b)
+		}
+	}
 	return w.ResponseWriter.Write(b)
 }
 
@@ -52,6 +62,11 @@ func AuditMiddleware(auditService *service.AuditService) gin.HandlerFunc {
 				usernameStr = user.Username
 			}
 		}
+		// 服务账户的请求记录在服务账户名下
+		if account, ok := GetCurrentServiceAccount(c); ok {
+			userUUID, exists = account.UUID, true
+			usernameStr = "sa:" + account.Name
+		}
 
 		// 调试日志：记录中间件是否执行
 		logger.Debug("审计中间件执行",
@@ -97,27 +112,8 @@ func AuditMiddleware(auditService *service.AuditService) gin.HandlerFunc {
 
 		// 处理Details - 支持任意类型，正确序列化
 		var details string
-		if val, exists := c.Get(AuditDetailsKey); exists && val != nil {
-			switch v := val.(type) {
-			case string:
-				details = v
-			default:
-				// 非string类型，序列化为JSON
-				if jsonBytes, err := json.Marshal(v); err == nil {
-					// 限制大小避免占用过多空间（65535字节 = TEXT字段上限）
-					if len(jsonBytes) > 65535 {
-						details = string(jsonBytes[:65535]) + "...(truncated)"
-						logger.Warn("审计Details过大，已截断",
-							logger.Int("original_size", len(jsonBytes)))
-					} else {
-						details = string(jsonBytes)
-					}
-				} else {
-					logger.Warn("审计Details序列化失败",
-						logger.Err(err),
-						logger.String("type", string(actionType)))
-				}
-			}
+		if val, exists := c.Get(AuditDetailsKey); exists {
+			details = FormatAuditDetails(val, actionType)
 		}
 
 		// 判断操作状态
@@ -199,6 +195,32 @@ func SetAuditDetails(c *gin.Context, details interface{}) {
 	c.Set(AuditDetailsKey, details)
 }
 
+// FormatAuditDetails 把handler设置的审计详细信息转换为字符串，HTTP中间件和gRPC拦截器共用
+// 非string类型序列化为JSON，超过TEXT字段上限时截断
+func FormatAuditDetails(val interface{}, actionType string) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		jsonBytes, err := json.Marshal(v)
+		if err != nil {
+			logger.Warn("审计Details序列化失败",
+				logger.Err(err),
+				logger.String("type", actionType))
+			return ""
+		}
+		// 限制大小避免占用过多空间（65535字节 = TEXT字段上限）
+		if len(jsonBytes) > 65535 {
+			logger.Warn("审计Details过大，已截断",
+				logger.Int("original_size", len(jsonBytes)))
+			return string(jsonBytes[:65535]) + "...(truncated)"
+		}
+		return string(jsonBytes)
+	}
+}
+
 // inferActionType 根据HTTP方法推断操作类型
 func inferActionType(method string) string {
 	switch method {
@@ -2,11 +2,11 @@ package middleware
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"time"
 
 	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
 	"github.com/cuihe500/vaulthub/pkg/errors"
 	"github.com/cuihe500/vaulthub/pkg/jwt"
 	"github.com/cuihe500/vaulthub/pkg/logger"
@@ -24,106 +24,231 @@ const (
 	UserUUIDContextKey = "user_uuid"
 	// RoleContextKey 用户角色在context中的key
 	RoleContextKey = "role"
+	// TokenContextKey 访问令牌在context中的key（二次验证状态与令牌绑定）
+	TokenContextKey = "token"
+	// SessionContextKey 登录会话UUID在context中的key
+	SessionContextKey = "session_uuid"
+	// AccessTokenContextKey 个人访问令牌在context中的key（使用登录令牌认证时不存在）
+	AccessTokenContextKey = "access_token"
 )
 
-// AuthMiddleware JWT认证中间件
+// sessionSeenInterval 会话最后活跃时间的更新间隔，避免每个请求都写数据库
+const sessionSeenInterval = time.Minute
+
+// AuthMiddleware JWT认证中间件，只接受登录令牌
 func AuthMiddleware(jwtManager *jwt.Manager, db *gorm.DB, redis *redisClient.Client) gin.HandlerFunc {
+	return authMiddleware(jwtManager, db, redis, false)
+}
+
+// AccessTokenAuthMiddleware 认证中间件，同时接受登录令牌和个人访问令牌
+// 只用于带权限检查的接口：个人访问令牌的权限范围由PermissionMiddleware检查
+func AccessTokenAuthMiddleware(jwtManager *jwt.Manager, db *gorm.DB, redis *redisClient.Client) gin.HandlerFunc {
+	return authMiddleware(jwtManager, db, redis, true)
+}
+
+func authMiddleware(jwtManager *jwt.Manager, db *gorm.DB, redis *redisClient.Client, allowAccessToken bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 从请求头获取token
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			response.Unauthorized(c, "缺少授权头")
+		tokenString, appErr := ExtractBearerToken(c.GetHeader("Authorization"))
+		if appErr != nil {
+			response.AppError(c, appErr)
 			c.Abort()
 			return
 		}
 
-		// 解析Bearer token
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			response.Unauthorized(c, "授权头格式无效")
-			c.Abort()
-			return
-		}
-
-		tokenString := parts[1]
-
-		// 验证token
-		claims, err := jwtManager.ParseToken(tokenString)
-		if err != nil {
-			logger.Warn("JWT token验证失败", logger.Err(err))
-			response.InvalidToken(c, "token无效或已过期")
+		if service.IsServiceAccountToken(tokenString) {
+			response.AppError(c, errors.New(errors.CodeForbidden, "服务账户令牌不能访问该接口"))
 			c.Abort()
 			return
 		}
 
-		// 优先从Redis验证token
-		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-		defer cancel()
-
-		tokenKey := makeTokenKey(tokenString)
-		userUUID, err := redis.Get(ctx, tokenKey)
-		if err != nil {
-			if err == goredis.Nil {
-				// Redis中不存在token，可能已登出或过期
-				logger.Warn("Token在Redis中不存在，可能已失效",
-					logger.String("uuid", claims.UserUUID))
-				response.InvalidToken(c, "token已失效")
+		if service.IsPersonalAccessToken(tokenString) {
+			if !allowAccessToken {
+				response.AppError(c, errors.New(errors.CodeForbidden, "个人访问令牌不能访问该接口"))
 				c.Abort()
 				return
 			}
-			// Redis查询失败，降级到数据库验证
-			logger.Warn("从Redis查询token失败，降级到数据库验证",
-				logger.String("uuid", claims.UserUUID),
-				logger.Err(err))
-		} else {
-			// Redis中存在token，验证UUID是否匹配
-			if userUUID != claims.UserUUID {
-				logger.Error("Token与用户UUID不匹配",
-					logger.String("expected", claims.UserUUID),
-					logger.String("actual", userUUID))
-				response.InvalidToken(c, "token无效")
+			accessToken, user, appErr := AuthenticateAccessToken(db, tokenString, c.ClientIP())
+			if appErr != nil {
+				response.AppError(c, appErr)
 				c.Abort()
 				return
 			}
-		}
 
-		// 从数据库获取用户信息（确保用户仍然存在且状态正常）
-		var user models.User
-		if err := db.Where("uuid = ?", claims.UserUUID).First(&user).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				response.Unauthorized(c, "用户不存在")
-			} else {
-				logger.Error("查询用户失败", logger.String("uuid", claims.UserUUID), logger.Err(err))
-				response.InternalError(c, "查询用户失败")
-			}
-			c.Abort()
+			// 个人访问令牌不属于任何登录会话，不设置会话UUID
+			c.Set(UserContextKey, user)
+			c.Set(UserUUIDContextKey, user.UUID)
+			c.Set(RoleContextKey, user.Role)
+			c.Set(TokenContextKey, tokenString)
+			c.Set(AccessTokenContextKey, accessToken)
+			c.Next()
 			return
 		}
 
-		// 检查用户状态
-		if !user.CanOperate() {
-			var message string
-			if user.IsDisabled() {
-				message = errors.GetMessage(errors.CodeAccountDisabled)
-			} else if user.IsLocked() {
-				message = errors.GetMessage(errors.CodeAccountLocked)
-			} else {
-				message = errors.GetMessage(errors.CodeAccountNotActivated)
-			}
-			response.Unauthorized(c, message)
+		user, claims, appErr := AuthenticateToken(jwtManager, db, redis, tokenString)
+		if appErr != nil {
+			response.AppError(c, appErr)
 			c.Abort()
 			return
 		}
 
 		// 将用户信息存入context
-		c.Set(UserContextKey, &user)
+		c.Set(UserContextKey, user)
 		c.Set(UserUUIDContextKey, user.UUID)
 		c.Set(RoleContextKey, user.Role)
+		c.Set(TokenContextKey, tokenString)
+		c.Set(SessionContextKey, claims.SessionID)
 
 		c.Next()
 	}
 }
 
+// ExtractBearerToken 从授权头（HTTP的Authorization头或gRPC的authorization元数据）中解析Bearer令牌
+func ExtractBearerToken(authHeader string) (string, *errors.AppError) {
+	if authHeader == "" {
+		return "", errors.New(errors.CodeUnauthorized, "缺少授权头")
+	}
+
+	// 解析Bearer token
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return "", errors.New(errors.CodeUnauthorized, "授权头格式无效")
+	}
+	return parts[1], nil
+}
+
+// AuthenticateToken 验证访问令牌并返回对应的用户和令牌声明
+// HTTP中间件和gRPC拦截器共用：校验JWT签名 -> 校验令牌所属的会话未被注销 -> 确认用户存在且状态正常
+func AuthenticateToken(jwtManager *jwt.Manager, db *gorm.DB, redis *redisClient.Client, tokenString string) (*models.User, *jwt.Claims, *errors.AppError) {
+	// 验证token
+	claims, err := jwtManager.ParseToken(tokenString)
+	if err != nil {
+		logger.Warn("JWT token验证失败", logger.Err(err))
+		return nil, nil, errors.New(errors.CodeInvalidToken, "token无效或已过期")
+	}
+	if claims.SessionID == "" {
+		// 多会话之前签发的令牌不属于任何会话，需要重新登录
+		return nil, nil, errors.New(errors.CodeInvalidToken, "token已失效")
+	}
+
+	if appErr := verifySession(db, redis, claims); appErr != nil {
+		return nil, nil, appErr
+	}
+
+	// 从数据库获取用户信息（确保用户仍然存在且状态正常）
+	var user models.User
+	if err := db.Where("uuid = ?", claims.UserUUID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.New(errors.CodeUnauthorized, "用户不存在")
+		}
+		logger.Error("查询用户失败", logger.String("uuid", claims.UserUUID), logger.Err(err))
+		return nil, nil, errors.New(errors.CodeInternalError, "查询用户失败")
+	}
+
+	// 用户修改密码、角色或状态后令牌版本递增，此前签发的令牌全部失效
+	if claims.TokenEpoch != user.TokenEpoch {
+		return nil, nil, errors.New(errors.CodeInvalidToken, "token已失效")
+	}
+
+	if appErr := checkUserStatus(db, &user); appErr != nil {
+		return nil, nil, appErr
+	}
+
+	return &user, claims, nil
+}
+
+// AuthenticateAccessToken 验证个人访问令牌并返回令牌和所属用户，HTTP中间件和gRPC拦截器共用
+func AuthenticateAccessToken(db *gorm.DB, tokenString, clientIP string) (*models.PersonalAccessToken, *models.User, *errors.AppError) {
+	accessToken, user, appErr := service.AuthenticatePersonalAccessToken(db, tokenString, clientIP)
+	if appErr != nil {
+		return nil, nil, appErr
+	}
+	if appErr := checkUserStatus(db, user); appErr != nil {
+		return nil, nil, appErr
+	}
+	return accessToken, user, nil
+}
+
+// checkUserStatus 检查用户状态（自动锁定到期的账户先解锁）
+func checkUserStatus(db *gorm.DB, user *models.User) *errors.AppError {
+	if err := service.ReleaseExpiredLock(db, user); err != nil {
+		return errors.New(errors.CodeInternalError, "查询用户失败")
+	}
+	if !user.CanOperate() {
+		var message string
+		if user.IsDisabled() {
+			message = errors.GetMessage(errors.CodeAccountDisabled)
+		} else if user.IsLocked() {
+			message = errors.GetMessage(errors.CodeAccountLocked)
+		} else {
+			message = errors.GetMessage(errors.CodeAccountNotActivated)
+		}
+		return errors.New(errors.CodeUnauthorized, message)
+	}
+	return nil
+}
+
+// verifySession 校验访问令牌所属的会话未被注销，并更新会话的最后活跃时间
+// 优先查询Redis中的会话标记；标记不存在或Redis不可用时以数据库中的会话为准
+func verifySession(db *gorm.DB, redis *redisClient.Client, claims *jwt.Claims) *errors.AppError {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	sessionKey := service.MakeSessionKey(claims.SessionID)
+	userUUID, err := redis.Get(ctx, sessionKey)
+	switch {
+	case err == nil:
+		if userUUID != claims.UserUUID {
+			logger.Error("会话与用户UUID不匹配",
+				logger.String("expected", claims.UserUUID),
+				logger.String("actual", userUUID))
+			return errors.New(errors.CodeInvalidToken, "token无效")
+		}
+	case err == goredis.Nil:
+		// 会话已注销，或写入Redis失败，以数据库为准
+		session, appErr := loadSession(db, claims)
+		if appErr != nil {
+			return appErr
+		}
+		if err := redis.Set(ctx, sessionKey, session.UserUUID, time.Until(session.ExpiresAt)); err != nil {
+			logger.Warn("回填会话到Redis失败", logger.String("session_uuid", session.UUID), logger.Err(err))
+		}
+	default:
+		logger.Warn("从Redis查询会话失败，降级到数据库验证",
+			logger.String("uuid", claims.UserUUID),
+			logger.Err(err))
+		if _, appErr := loadSession(db, claims); appErr != nil {
+			return appErr
+		}
+	}
+
+	now := time.Now()
+	if err := db.Model(&models.UserSession{}).
+		Where("uuid = ? AND last_seen_at < ?", claims.SessionID, now.Add(-sessionSeenInterval)).
+		UpdateColumn("last_seen_at", now).Error; err != nil {
+		logger.Warn("更新会话活跃时间失败", logger.String("session_uuid", claims.SessionID), logger.Err(err))
+	}
+	return nil
+}
+
+// loadSession 从数据库查询令牌所属的未过期会话
+func loadSession(db *gorm.DB, claims *jwt.Claims) (*models.UserSession, *errors.AppError) {
+	var session models.UserSession
+	err := db.Where("uuid = ? AND user_uuid = ? AND expires_at > ?", claims.SessionID, claims.UserUUID, time.Now()).
+		First(&session).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Warn("会话不存在，可能已注销或过期",
+				logger.String("uuid", claims.UserUUID),
+				logger.String("session_uuid", claims.SessionID))
+			return nil, errors.New(errors.CodeInvalidToken, "token已失效")
+		}
+		logger.Error("查询会话失败", logger.String("session_uuid", claims.SessionID), logger.Err(err))
+		return nil, errors.New(errors.CodeInternalError, "查询会话失败")
+	}
+	return &session, nil
+}
+
 // GetCurrentUser 从context获取当前用户
 func GetCurrentUser(c *gin.Context) (*models.User, bool) {
 	user, exists := c.Get(UserContextKey)
@@ -151,7 +276,29 @@ func GetCurrentUserRole(c *gin.Context) (string, bool) {
 	return role.(string), true
 }
 
-// makeTokenKey 生成token在Redis中的key
-func makeTokenKey(token string) string {
-	return fmt.Sprintf("token:%s", token)
+// GetCurrentToken 从context获取当前请求的访问令牌
+func GetCurrentToken(c *gin.Context) (string, bool) {
+	token, exists := c.Get(TokenContextKey)
+	if !exists {
+		return "", false
+	}
+	return token.(string), true
+}
+
+// GetCurrentSessionUUID 从context获取当前请求所属的登录会话UUID
+func GetCurrentSessionUUID(c *gin.Context) (string, bool) {
+	sessionUUID, exists := c.Get(SessionContextKey)
+	if !exists {
+		return "", false
+	}
+	return sessionUUID.(string), true
+}
+
+// GetCurrentAccessToken 从context获取当前请求使用的个人访问令牌，使用登录令牌认证时返回false
+func GetCurrentAccessToken(c *gin.Context) (*models.PersonalAccessToken, bool) {
+	accessToken, exists := c.Get(AccessTokenContextKey)
+	if !exists {
+		return nil, false
+	}
+	return accessToken.(*models.PersonalAccessToken), true
 }
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"github.com/cuihe500/vaulthub/internal/app"
+	"github.com/cuihe500/vaulthub/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -12,12 +13,17 @@ import (
 // 2. 集中管理中间件链，易于维护和调整
 // 3. 避免在路由文件中重复书写相同的中间件组合
 type ChainBuilder struct {
-	mgr *app.Manager
+	mgr    *app.Manager
+	stepUp *service.WebAuthnService // 二次验证检查
 }
 
 // NewChainBuilder 创建中间件链构建器
-func NewChainBuilder(mgr *app.Manager) *ChainBuilder {
-	return &ChainBuilder{mgr: mgr}
+// stepUp: 服务容器中的WebAuthn服务，与处理器共用同一实例
+func NewChainBuilder(mgr *app.Manager, stepUp *service.WebAuthnService) *ChainBuilder {
+	return &ChainBuilder{
+		mgr:    mgr,
+		stepUp: stepUp,
+	}
 }
 
 // AuthWithAudit 返回认证+审计中间件链（最常用组合）
@@ -37,41 +43,69 @@ func (b *ChainBuilder) AuthWithAudit() []gin.HandlerFunc {
 //   - action: 操作类型（如"read", "write"）
 //
 // 中间件顺序：Auth -> Audit -> Permission
+// 同时接受个人访问令牌，令牌的权限范围在Permission中检查
 func (b *ChainBuilder) AuthWithPermission(resource, action string) []gin.HandlerFunc {
 	return []gin.HandlerFunc{
-		AuthMiddleware(b.mgr.JWT, b.mgr.DB, b.mgr.Redis),
+		AccessTokenAuthMiddleware(b.mgr.JWT, b.mgr.DB, b.mgr.Redis),
 		AuditMiddleware(b.mgr.AuditService),
 		RequirePermission(b.mgr.Enforcer, resource, action),
 	}
 }
 
-// SecureAuth 返回认证+审计+安全密码检查中间件链
+// SecureAuth 返回认证+审计+安全密码检查+二次验证中间件链
 // 使用场景：涉及敏感操作的接口（如秘密管理）
-// 要求：用户必须设置安全密码（Security PIN）
-// 中间件顺序：Auth -> Audit -> SecurityPINCheck
+// 要求：用户必须设置安全密码（Security PIN）；角色要求二次验证时，当前会话必须已完成通行密钥二次验证
+// 中间件顺序：Auth -> Audit -> SecurityPINCheck -> StepUp
 func (b *ChainBuilder) SecureAuth() []gin.HandlerFunc {
 	return []gin.HandlerFunc{
 		AuthMiddleware(b.mgr.JWT, b.mgr.DB, b.mgr.Redis),
 		AuditMiddleware(b.mgr.AuditService),
 		SecurityPINCheckMiddleware(b.mgr.DB),
+		StepUpMiddleware(b.stepUp),
 	}
 }
 
-// SecureAuthWithPermission 返回认证+审计+权限验证+安全密码检查中间件链
+// SecureAuthWithPermission 返回认证+审计+权限验证+安全密码检查+二次验证中间件链
 // 使用场景：需要特定权限且涉及敏感操作的接口（如秘密管理）
-// 要求：用户必须设置安全密码（Security PIN）并拥有对应权限
+// 要求：用户必须设置安全密码（Security PIN）并拥有对应权限；角色要求二次验证时，当前会话必须已完成通行密钥二次验证
 // 参数：
 //   - resource: 资源名称（如"secret"）
 //   - action: 操作类型（如"read", "write"）
 //
-// 中间件顺序：Auth -> Audit -> Permission -> SecurityPINCheck
+// 中间件顺序：Auth -> Audit -> Permission -> SecurityPINCheck -> StepUp
 // 注意：Permission在SecurityPIN之前，先验证权限再检查PIN，避免无权限用户触发PIN检查
+// 同时接受个人访问令牌，令牌的权限范围在Permission中检查
 func (b *ChainBuilder) SecureAuthWithPermission(resource, action string) []gin.HandlerFunc {
 	return []gin.HandlerFunc{
-		AuthMiddleware(b.mgr.JWT, b.mgr.DB, b.mgr.Redis),
+		AccessTokenAuthMiddleware(b.mgr.JWT, b.mgr.DB, b.mgr.Redis),
 		AuditMiddleware(b.mgr.AuditService),
 		RequirePermission(b.mgr.Enforcer, resource, action),
 		SecurityPINCheckMiddleware(b.mgr.DB),
+		StepUpMiddleware(b.stepUp),
+	}
+}
+
+// SecureAuthWithSecretCollection 返回与 SecureAuthWithPermission(secret, action) 相同的中间件链
+// 使用场景：不针对单个秘密、按个人访问令牌的秘密范围自行过滤结果的接口（如秘密列表、模板渲染）
+// 其他不针对单个秘密的接口使用 SecureAuthWithPermission，限定了秘密范围的个人访问令牌不能访问
+func (b *ChainBuilder) SecureAuthWithSecretCollection(action string) []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		AccessTokenAuthMiddleware(b.mgr.JWT, b.mgr.DB, b.mgr.Redis),
+		AuditMiddleware(b.mgr.AuditService),
+		SecretCollectionPermissionMiddleware(b.mgr.Enforcer, action),
+		SecurityPINCheckMiddleware(b.mgr.DB),
+		StepUpMiddleware(b.stepUp),
+	}
+}
+
+// ServiceAccountWithPermission 返回服务账户认证+审计+权限验证中间件链
+// 使用场景：只允许服务账户访问的接口（如/api/v1/machine）
+// 中间件顺序：ServiceAccountAuth -> Audit -> ServiceAccountPermission
+func (b *ChainBuilder) ServiceAccountWithPermission(resource, action string) []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		ServiceAccountAuthMiddleware(b.mgr.DB, b.mgr.Redis),
+		AuditMiddleware(b.mgr.AuditService),
+		ServiceAccountPermissionMiddleware(b.mgr.Enforcer, resource, action),
 	}
 }
 
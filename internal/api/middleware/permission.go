@@ -1,7 +1,11 @@
 package middleware
 
 import (
+	"fmt"
+
 	"github.com/casbin/casbin/v2"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/errors"
 	"github.com/cuihe500/vaulthub/pkg/logger"
 	"github.com/cuihe500/vaulthub/pkg/response"
 	"github.com/gin-gonic/gin"
@@ -10,6 +14,17 @@ import (
 // PermissionMiddleware Casbin权限检查中间件
 // 需要在AuthMiddleware之后使用
 func PermissionMiddleware(enforcer *casbin.Enforcer, resource, action string) gin.HandlerFunc {
+	return permissionMiddleware(enforcer, resource, action, false)
+}
+
+// SecretCollectionPermissionMiddleware 不针对单个秘密的接口（秘密列表、模板渲染）使用的secret权限检查
+// 限定了秘密范围的个人访问令牌也可以访问，接口必须按令牌的秘密范围自行过滤结果
+func SecretCollectionPermissionMiddleware(enforcer *casbin.Enforcer, action string) gin.HandlerFunc {
+	return permissionMiddleware(enforcer, ResourceSecret, action, true)
+}
+
+// permissionMiddleware 权限检查，secretCollection 表示接口按个人访问令牌的秘密范围自行过滤
+func permissionMiddleware(enforcer *casbin.Enforcer, resource, action string, secretCollection bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 获取用户角色
 		role, exists := GetCurrentUserRole(c)
@@ -20,27 +35,26 @@ func PermissionMiddleware(enforcer *casbin.Enforcer, resource, action string) gi
 			return
 		}
 
-		// 检查权限
-		allowed, err := enforcer.Enforce(role, resource, action)
-		if err != nil {
-			logger.Error("权限检查失败",
-				logger.String("role", role),
-				logger.String("resource", resource),
-				logger.String("action", action),
-				logger.Err(err))
-			response.InternalError(c, "权限检查失败")
+		if appErr := CheckPermission(enforcer, role, resource, action); appErr != nil {
+			response.AppError(c, appErr)
 			c.Abort()
 			return
 		}
-
-		if !allowed {
-			logger.Warn("权限不足",
-				logger.String("role", role),
-				logger.String("resource", resource),
-				logger.String("action", action))
-			response.InsufficientPermission(c, "权限不足")
-			c.Abort()
-			return
+		// 使用个人访问令牌时，权限还不能超出令牌的权限范围
+		if accessToken, ok := GetCurrentAccessToken(c); ok {
+			if appErr := CheckAccessTokenScope(accessToken, resource, action); appErr != nil {
+				response.AppError(c, appErr)
+				c.Abort()
+				return
+			}
+			// 限定了秘密范围的令牌只能访问路径中指定的、范围内的秘密
+			if resource == ResourceSecret && !secretCollection {
+				if appErr := CheckAccessTokenSecret(accessToken, c.Param("uuid")); appErr != nil {
+					response.AppError(c, appErr)
+					c.Abort()
+					return
+				}
+			}
 		}
 
 		c.Next()
@@ -52,3 +66,56 @@ func PermissionMiddleware(enforcer *casbin.Enforcer, resource, action string) gi
 func RequirePermission(enforcer *casbin.Enforcer, resource, action string) gin.HandlerFunc {
 	return PermissionMiddleware(enforcer, resource, action)
 }
+
+// CheckPermission 检查角色是否拥有资源的操作权限，HTTP中间件和gRPC拦截器共用
+func CheckPermission(enforcer *casbin.Enforcer, role, resource, action string) *errors.AppError {
+	allowed, err := enforcer.Enforce(role, resource, action)
+	if err != nil {
+		logger.Error("权限检查失败",
+			logger.String("role", role),
+			logger.String("resource", resource),
+			logger.String("action", action),
+			logger.Err(err))
+		return errors.New(errors.CodeInternalError, "权限检查失败")
+	}
+
+	if !allowed {
+		logger.Warn("权限不足",
+			logger.String("role", role),
+			logger.String("resource", resource),
+			logger.String("action", action))
+		return errors.New(errors.CodeInsufficientPermission, "权限不足")
+	}
+	return nil
+}
+
+// CheckAccessTokenScope 检查个人访问令牌的权限范围是否包含资源的操作，HTTP中间件和gRPC拦截器共用
+func CheckAccessTokenScope(accessToken *models.PersonalAccessToken, resource, action string) *errors.AppError {
+	if !accessToken.HasScope(resource, action) {
+		logger.Warn("个人访问令牌权限范围不足",
+			logger.String("token_uuid", accessToken.UUID),
+			logger.String("resource", resource),
+			logger.String("action", action))
+		return errors.New(errors.CodeInsufficientPermission, fmt.Sprintf("个人访问令牌缺少 %s:%s 权限", resource, action))
+	}
+	return nil
+}
+
+// CheckAccessTokenSecret 检查个人访问令牌是否可以访问指定的秘密，HTTP中间件和gRPC方法共用
+// secretUUID 为空表示接口不针对单个秘密，限定了秘密范围的令牌不能访问
+func CheckAccessTokenSecret(accessToken *models.PersonalAccessToken, secretUUID string) *errors.AppError {
+	if accessToken == nil || !accessToken.RestrictsSecrets() {
+		return nil
+	}
+	if secretUUID == "" {
+		logger.Warn("限定了秘密范围的个人访问令牌访问非单个秘密的接口", logger.String("token_uuid", accessToken.UUID))
+		return errors.New(errors.CodeInsufficientPermission, "限定了秘密范围的个人访问令牌不能访问该接口")
+	}
+	if !accessToken.AllowsSecret(secretUUID) {
+		logger.Warn("个人访问令牌的秘密范围不包含该秘密",
+			logger.String("token_uuid", accessToken.UUID),
+			logger.String("secret_uuid", secretUUID))
+		return errors.New(errors.CodeInsufficientPermission, "个人访问令牌不能访问该秘密")
+	}
+	return nil
+}
@@ -65,44 +65,62 @@ func getEndpointRateLimit(configMgr *config.ConfigManager, path string) RateLimi
 	}
 }
 
-// RateLimitMiddleware 限流中间件（基于redis_rate实现，使用GCRA算法，配置从数据库动态读取）
+// RateLimiter 按客户端IP和接口限流（基于redis_rate实现，使用GCRA算法，配置从数据库动态读取）
+// HTTP中间件和gRPC拦截器共用，gRPC接口使用完整方法名（如 /vaulthub.v1.AuthService/Login）作为接口路径
+type RateLimiter struct {
+	limiter   *redis_rate.Limiter
+	configMgr *config.ConfigManager
+}
+
+// NewRateLimiter 创建限流器
+func NewRateLimiter(redis *redisClient.Client, configMgr *config.ConfigManager) *RateLimiter {
+	return &RateLimiter{
+		limiter:   redis_rate.NewLimiter(redis.GetUniversalClient()),
+		configMgr: configMgr,
+	}
+}
+
+// Allow 判断请求是否允许通过
+// 限流检查本身失败时不阻断请求
+func (l *RateLimiter) Allow(ctx context.Context, clientIP, path string) bool {
+	// 从ConfigManager读取限流配置
+	cfg := getEndpointRateLimit(l.configMgr, path)
+
+	// 生成限流key：ip:path
+	key := fmt.Sprintf("rate_limit:%s:%s", clientIP, path)
+
+	// 执行限流检查
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	res, err := l.limiter.Allow(ctx, key, cfg.ToRedisRate())
+	if err != nil {
+		logger.Error("限流检查失败",
+			logger.String("ip", clientIP),
+			logger.String("path", path),
+			logger.Err(err))
+		return true
+	}
+
+	// 检查是否允许请求
+	if res.Allowed == 0 {
+		logger.Warn("请求被限流",
+			logger.String("ip", clientIP),
+			logger.String("path", path),
+			logger.Int("remaining", res.Remaining),
+			logger.Int("limit", res.Limit.Burst))
+		return false
+	}
+	return true
+}
+
+// RateLimitMiddleware 限流中间件
 func RateLimitMiddleware(redis *redisClient.Client, configMgr *config.ConfigManager) gin.HandlerFunc {
 	// 创建限流器
-	limiter := redis_rate.NewLimiter(redis.GetUniversalClient())
+	limiter := NewRateLimiter(redis, configMgr)
 
 	return func(c *gin.Context) {
-		// 获取客户端IP
-		clientIP := c.ClientIP()
-		path := c.Request.URL.Path
-
-		// 从ConfigManager读取限流配置
-		cfg := getEndpointRateLimit(configMgr, path)
-
-		// 生成限流key：ip:path
-		key := fmt.Sprintf("rate_limit:%s:%s", clientIP, path)
-
-		// 执行限流检查
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
-		defer cancel()
-
-		res, err := limiter.Allow(ctx, key, cfg.ToRedisRate())
-		if err != nil {
-			logger.Error("限流检查失败",
-				logger.String("ip", clientIP),
-				logger.String("path", path),
-				logger.Err(err))
-			// 限流检查失败不阻断请求，继续执行
-			c.Next()
-			return
-		}
-
-		// 检查是否允许请求
-		if res.Allowed == 0 {
-			logger.Warn("请求被限流",
-				logger.String("ip", clientIP),
-				logger.String("path", path),
-				logger.Int("remaining", res.Remaining),
-				logger.Int("limit", res.Limit.Burst))
+		if !limiter.Allow(c.Request.Context(), c.ClientIP(), c.Request.URL.Path) {
 			response.AppError(c, errors.New(errors.CodeTooManyRequests, ""))
 			c.Abort()
 			return
@@ -37,6 +37,14 @@ const (
 	// ResourceCasbin Casbin权限系统资源
 	// 用于权限策略的重新加载等管理操作
 	ResourceCasbin = "casbin"
+
+	// ResourceCheckout 秘密检出管理资源
+	// 用于管理员查看所有检出记录、强制释放检出
+	ResourceCheckout = "checkout"
+
+	// ResourceServiceAccount 服务账户资源
+	// 用于用户管理自己保险库的服务账户、secret_id和秘密授权
+	ResourceServiceAccount = "service_account"
 )
 
 // 操作类型常量
@@ -108,6 +116,12 @@ var (
 
 	// PermCasbinReload Casbin重新加载权限
 	PermCasbinReload = Permission{Resource: ResourceCasbin, Action: ActionReload}
+
+	// PermCheckoutRead 检出管理读权限
+	PermCheckoutRead = Permission{Resource: ResourceCheckout, Action: ActionRead}
+
+	// PermCheckoutWrite 检出管理写权限
+	PermCheckoutWrite = Permission{Resource: ResourceCheckout, Action: ActionWrite}
 )
 
 // Permission 权限结构体
@@ -23,34 +23,8 @@ func SecurityPINCheckMiddleware(db *gorm.DB) gin.HandlerFunc {
 			return
 		}
 
-		// 查询用户的加密密钥配置
-		var userKey models.UserEncryptionKey
-		err := db.Where("user_uuid = ?", userUUID).First(&userKey).Error
-		if err != nil {
-			if err == gorm.ErrRecordNotFound {
-				// 用户尚未创建加密密钥，需要先设置安全密码
-				logger.Info("用户未创建加密密钥",
-					logger.String("user_uuid", userUUID.(string)))
-				response.Error(c, errors.CodeSecurityPINNotSet, "请先设置安全密码")
-				c.Abort()
-				return
-			}
-			// 数据库查询错误
-			logger.Error("查询用户加密密钥失败",
-				logger.String("user_uuid", userUUID.(string)),
-				logger.Err(err))
-			response.Error(c, errors.CodeDatabaseError, "系统错误")
-			c.Abort()
-			return
-		}
-
-		// 检查是否已设置安全密码
-		if !userKey.HasSecurityPIN() {
-			// 理论上不应该出现此情况（创建密钥时必须设置安全密码）
-			// 但作为防御性编程，仍然检查
-			logger.Warn("用户加密密钥存在但安全密码未设置",
-				logger.String("user_uuid", userUUID.(string)))
-			response.Error(c, errors.CodeSecurityPINNotSet, "请先设置安全密码")
+		if appErr := CheckSecurityPIN(db, userUUID.(string)); appErr != nil {
+			response.AppError(c, appErr)
 			c.Abort()
 			return
 		}
@@ -59,3 +33,33 @@ func SecurityPINCheckMiddleware(db *gorm.DB) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// CheckSecurityPIN 检查用户是否已设置安全密码，HTTP中间件和gRPC拦截器共用
+func CheckSecurityPIN(db *gorm.DB, userUUID string) *errors.AppError {
+	// 查询用户的加密密钥配置
+	var userKey models.UserEncryptionKey
+	err := db.Where("user_uuid = ?", userUUID).First(&userKey).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// 用户尚未创建加密密钥，需要先设置安全密码
+			logger.Info("用户未创建加密密钥",
+				logger.String("user_uuid", userUUID))
+			return errors.New(errors.CodeSecurityPINNotSet, "请先设置安全密码")
+		}
+		// 数据库查询错误
+		logger.Error("查询用户加密密钥失败",
+			logger.String("user_uuid", userUUID),
+			logger.Err(err))
+		return errors.New(errors.CodeDatabaseError, "系统错误")
+	}
+
+	// 检查是否已设置安全密码
+	if !userKey.HasSecurityPIN() {
+		// 理论上不应该出现此情况（创建密钥时必须设置安全密码）
+		// 但作为防御性编程，仍然检查
+		logger.Warn("用户加密密钥存在但安全密码未设置",
+			logger.String("user_uuid", userUUID))
+		return errors.New(errors.CodeSecurityPINNotSet, "请先设置安全密码")
+	}
+	return nil
+}
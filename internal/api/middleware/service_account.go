@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"github.com/casbin/casbin/v2"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	redisClient "github.com/cuihe500/vaulthub/pkg/redis"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ServiceAccountContextKey 服务账户在context中的key
+const ServiceAccountContextKey = "service_account"
+
+// ServiceAccountAuthMiddleware 服务账户认证中间件，只接受服务账户令牌
+// 服务账户不是用户，不设置用户相关的context
+func ServiceAccountAuthMiddleware(db *gorm.DB, redis *redisClient.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString, appErr := ExtractBearerToken(c.GetHeader("Authorization"))
+		if appErr != nil {
+			response.AppError(c, appErr)
+			c.Abort()
+			return
+		}
+		if !service.IsServiceAccountToken(tokenString) {
+			response.AppError(c, errors.New(errors.CodeForbidden, "该接口只允许服务账户访问"))
+			c.Abort()
+			return
+		}
+
+		account, appErr := service.AuthenticateServiceAccountToken(db, redis, tokenString, c.ClientIP())
+		if appErr != nil {
+			response.AppError(c, appErr)
+			c.Abort()
+			return
+		}
+
+		c.Set(ServiceAccountContextKey, account)
+		c.Next()
+	}
+}
+
+// ServiceAccountPermissionMiddleware 服务账户的Casbin权限检查中间件
+// 需要在ServiceAccountAuthMiddleware之后使用，以服务账户自身的主体（sa:<uuid>）检查权限
+func ServiceAccountPermissionMiddleware(enforcer *casbin.Enforcer, resource, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, exists := GetCurrentServiceAccount(c)
+		if !exists {
+			logger.Error("权限检查失败：无法获取服务账户")
+			response.Unauthorized(c, "未授权")
+			c.Abort()
+			return
+		}
+
+		if appErr := CheckPermission(enforcer, account.CasbinSubject(), resource, action); appErr != nil {
+			response.AppError(c, appErr)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetCurrentServiceAccount 从context获取当前服务账户
+func GetCurrentServiceAccount(c *gin.Context) (*models.ServiceAccount, bool) {
+	value, exists := c.Get(ServiceAccountContextKey)
+	if !exists {
+		return nil, false
+	}
+	account, ok := value.(*models.ServiceAccount)
+	return account, ok
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// StepUpMiddleware 检查当前会话是否已完成通行密钥二次验证
+// 只对系统配置 webauthn_step_up_roles 中的角色生效，其他角色直接放行
+// 注意：此中间件必须在 AuthMiddleware 之后使用，因为需要从上下文获取用户信息和访问令牌
+func StepUpMiddleware(webAuthnService *service.WebAuthnService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := GetCurrentUser(c)
+		token, tokenExists := GetCurrentToken(c)
+		if !exists || !tokenExists {
+			logger.Error("StepUpMiddleware: 无法从上下文获取用户信息")
+			response.Error(c, errors.CodeUnauthorized, "未授权访问")
+			c.Abort()
+			return
+		}
+
+		if appErr := CheckStepUp(webAuthnService, user, token); appErr != nil {
+			response.AppError(c, appErr)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// CheckStepUp 检查当前会话是否已完成二次验证，HTTP中间件和gRPC拦截器共用
+func CheckStepUp(webAuthnService *service.WebAuthnService, user *models.User, token string) *errors.AppError {
+	if !webAuthnService.IsStepUpRequired(user.Role) {
+		return nil
+	}
+	if webAuthnService.IsSteppedUp(token) {
+		return nil
+	}
+	logger.Info("访问秘密前需要二次验证",
+		logger.String("user_uuid", user.UUID),
+		logger.String("role", user.Role))
+	return errors.New(errors.CodeStepUpRequired, "请先使用通行密钥完成二次验证")
+}
@@ -11,17 +11,32 @@ import (
 // 2. 依赖注入统一管理，避免在路由文件中散落大量构造代码
 // 3. 新增处理器时只需修改此文件，降低维护成本
 type HandlerContainer struct {
-	Health     *handlers.HealthHandler
-	Auth       *handlers.AuthHandler
-	User       *handlers.UserHandler
-	Profile    *handlers.UserProfileHandler
-	Secret     *handlers.SecretHandler
-	KeyManage  *handlers.KeyManagementHandler
-	SysConfig  *handlers.SystemConfigHandler
-	Email      *handlers.EmailHandler
-	Audit      *handlers.AuditHandler
-	Statistics *handlers.StatisticsHandler
-	Casbin     *handlers.CasbinHandler
+	Health         *handlers.HealthHandler
+	Auth           *handlers.AuthHandler
+	MFA            *handlers.MFAHandler
+	WebAuthn       *handlers.WebAuthnHandler
+	OIDC           *handlers.OIDCHandler
+	Session        *handlers.SessionHandler
+	AccessToken    *handlers.PersonalAccessTokenHandler
+	ServiceAccount *handlers.ServiceAccountHandler
+	User           *handlers.UserHandler
+	Profile        *handlers.UserProfileHandler
+	Secret         *handlers.SecretHandler
+	Backup         *handlers.BackupHandler
+	Trash          *handlers.TrashHandler
+	Attachment     *handlers.AttachmentHandler
+	Share          *handlers.ShareHandler
+	Checkout       *handlers.CheckoutHandler
+	Approval       *handlers.ApprovalHandler
+	Emergency      *handlers.EmergencyAccessHandler
+	VaultHealth    *handlers.VaultHealthHandler
+	Template       *handlers.TemplateHandler
+	KeyManage      *handlers.KeyManagementHandler
+	SysConfig      *handlers.SystemConfigHandler
+	Email          *handlers.EmailHandler
+	Audit          *handlers.AuditHandler
+	Statistics     *handlers.StatisticsHandler
+	Casbin         *handlers.CasbinHandler
 }
 
 // NewHandlerContainer 创建处理器容器
@@ -32,16 +47,31 @@ type HandlerContainer struct {
 // 注意：处理器的创建顺序可以任意，因为它们之间没有依赖关系
 func NewHandlerContainer(mgr *app.Manager, svc *ServiceContainer) *HandlerContainer {
 	return &HandlerContainer{
-		Health:     handlers.NewHealthHandler(mgr),
-		Auth:       handlers.NewAuthHandler(svc.Auth, svc.Recovery, mgr.DB),
-		User:       handlers.NewUserHandler(svc.User),
-		Profile:    handlers.NewUserProfileHandler(svc.Profile),
-		Secret:     handlers.NewSecretHandler(svc.Encryption),
-		KeyManage:  handlers.NewKeyManagementHandler(svc.Encryption, svc.Recovery, svc.KeyRotation),
-		SysConfig:  handlers.NewSystemConfigHandler(svc.SystemConfig),
-		Email:      handlers.NewEmailHandler(svc.Email),
-		Audit:      handlers.NewAuditHandler(mgr.AuditService),
-		Statistics: handlers.NewStatisticsHandler(svc.Statistics),
-		Casbin:     handlers.NewCasbinHandler(mgr.Enforcer),
+		Health:         handlers.NewHealthHandler(mgr),
+		Auth:           handlers.NewAuthHandler(svc.Auth, svc.Recovery, mgr.DB),
+		MFA:            handlers.NewMFAHandler(svc.MFA),
+		WebAuthn:       handlers.NewWebAuthnHandler(svc.WebAuthn),
+		OIDC:           handlers.NewOIDCHandler(svc.OIDC),
+		Session:        handlers.NewSessionHandler(svc.Session),
+		AccessToken:    handlers.NewPersonalAccessTokenHandler(svc.AccessToken),
+		ServiceAccount: handlers.NewServiceAccountHandler(svc.ServiceAccount),
+		User:           handlers.NewUserHandler(svc.User),
+		Profile:        handlers.NewUserProfileHandler(svc.Profile),
+		Secret:         handlers.NewSecretHandler(svc.Encryption, svc.Import),
+		Backup:         handlers.NewBackupHandler(svc.Backup),
+		Trash:          handlers.NewTrashHandler(svc.Trash),
+		Attachment:     handlers.NewAttachmentHandler(svc.Attachment),
+		Share:          handlers.NewShareHandler(svc.Share),
+		Checkout:       handlers.NewCheckoutHandler(svc.Checkout),
+		Approval:       handlers.NewApprovalHandler(svc.Approval),
+		Emergency:      handlers.NewEmergencyAccessHandler(svc.Emergency),
+		VaultHealth:    handlers.NewVaultHealthHandler(svc.VaultHealth),
+		Template:       handlers.NewTemplateHandler(svc.Template),
+		KeyManage:      handlers.NewKeyManagementHandler(svc.Encryption, svc.Recovery, svc.KeyRotation),
+		SysConfig:      handlers.NewSystemConfigHandler(svc.SystemConfig),
+		Email:          handlers.NewEmailHandler(svc.Email),
+		Audit:          handlers.NewAuditHandler(mgr.AuditService),
+		Statistics:     handlers.NewStatisticsHandler(svc.Statistics),
+		Casbin:         handlers.NewCasbinHandler(mgr.Enforcer),
 	}
 }
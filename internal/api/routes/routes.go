@@ -16,20 +16,20 @@ import (
 
 // Setup 注册所有路由
 // mgr: 连接管理器，提供数据库等外部连接
-func Setup(r *gin.Engine, mgr *app.Manager) {
+// svc: 服务容器，与gRPC服务共用同一组service实例
+func Setup(r *gin.Engine, mgr *app.Manager, svc *ServiceContainer) {
 	// 全局中间件
 	r.Use(middleware.RequestID())
 	// 注意：审计中间件不能在全局注册，因为它依赖AuthMiddleware设置的用户信息
 	// 审计中间件需要在各个路由组的AuthMiddleware之后注册
 
-	// 创建服务容器和处理器容器
+	// 创建处理器容器
 	// 依赖关系由容器内部管理，避免在此处手动组装
-	svc := NewServiceContainer(mgr)
 	h := NewHandlerContainer(mgr, svc)
 
 	// 创建中间件链构建器
 	// 用于标准化中间件组合，避免重复代码
-	chain := middleware.NewChainBuilder(mgr)
+	chain := middleware.NewChainBuilder(mgr, svc.WebAuthn)
 
 	// 健康检查接口（不需要认证）
 	r.GET("/health", h.Health.HealthCheck)
@@ -63,6 +63,27 @@ func Setup(r *gin.Engine, mgr *app.Manager) {
 			auth.POST("/login", append(append(publicChain, chain.RateLimit()...), h.Auth.Login)...)
 			auth.POST("/login-with-email", append(append(publicChain, chain.RateLimit()...), h.Auth.LoginWithEmail)...)
 
+			// 两步验证登录（凭登录接口返回的mfa_token，不需要token）
+			auth.POST("/login/mfa", append(append(publicChain, chain.RateLimit()...), h.Auth.LoginWithMFA)...)
+			auth.POST("/login/mfa/enroll", append(append(publicChain, chain.RateLimit()...), h.Auth.BeginLoginMFAEnrollment)...)
+
+			// 密码过期时修改密码（凭登录接口返回的password_change_token，不需要token）
+			auth.POST("/login/password-change", append(append(publicChain, chain.RateLimit()...), h.Auth.ChangeExpiredPassword)...)
+
+			// 通行密钥无密码登录（不需要用户名和token）
+			auth.POST("/login/webauthn/begin", append(append(publicChain, chain.RateLimit()...), h.WebAuthn.BeginLogin)...)
+			auth.POST("/login/webauthn/finish", append(append(publicChain, chain.RateLimit()...), h.Auth.LoginWithPasskey)...)
+
+			// 单点登录（授权码+PKCE，不需要用户名和token）
+			auth.POST("/login/oidc/begin", append(append(publicChain, chain.RateLimit()...), h.OIDC.BeginLogin)...)
+			auth.POST("/login/oidc/finish", append(append(publicChain, chain.RateLimit()...), h.Auth.LoginWithOIDC)...)
+
+			// 刷新令牌（访问令牌过期后凭刷新令牌换取，不需要token）
+			auth.POST("/refresh", append(append(publicChain, chain.RateLimit()...), h.Auth.RefreshToken)...)
+
+			// 服务账户登录（凭role_id和secret_id，不需要token）
+			auth.POST("/approle/login", append(append(publicChain, chain.RateLimit()...), h.ServiceAccount.Login)...)
+
 			// 密码找回路由（不需要认证，需要审计和限流）
 			auth.POST("/request-password-reset", append(append(publicChain, chain.RateLimit()...), h.Auth.RequestPasswordReset)...)
 			auth.GET("/verify-reset-token", append(publicChain, h.Auth.VerifyPasswordResetToken)...)
@@ -72,7 +93,36 @@ func Setup(r *gin.Engine, mgr *app.Manager) {
 			auth.GET("/me", append(chain.AuthWithAudit(), h.Auth.GetMe)...)
 			auth.POST("/logout", append(chain.AuthWithAudit(), h.Auth.Logout)...)
 			auth.POST("/reset-password", append(chain.AuthWithAudit(), h.Auth.ResetPassword)...)
+			auth.PUT("/password", append(chain.AuthWithAudit(), h.Auth.ChangePassword)...)
 			auth.GET("/security-pin-status", append(chain.AuthWithAudit(), h.Auth.GetSecurityPINStatus)...)
+
+			// 登录会话管理（用户只能管理自己的会话）
+			auth.GET("/sessions", append(chain.AuthWithAudit(), h.Session.ListSessions)...)
+			auth.DELETE("/sessions", append(chain.AuthWithAudit(), h.Session.RevokeOtherSessions)...)
+			auth.DELETE("/sessions/:uuid", append(chain.AuthWithAudit(), h.Session.RevokeSession)...)
+
+			// 个人访问令牌管理（用户只能管理自己的令牌，个人访问令牌本身不能访问这些接口）
+			auth.GET("/tokens", append(chain.AuthWithAudit(), h.AccessToken.ListTokens)...)
+			auth.POST("/tokens", append(chain.AuthWithAudit(), h.AccessToken.CreateToken)...)
+			auth.DELETE("/tokens/:uuid", append(chain.AuthWithAudit(), h.AccessToken.RevokeToken)...)
+
+			// 两步验证管理（用户只能管理自己的两步验证）
+			auth.GET("/mfa", append(chain.AuthWithAudit(), h.MFA.GetStatus)...)
+			auth.POST("/mfa/enroll", append(chain.AuthWithAudit(), h.MFA.BeginEnrollment)...)
+			auth.POST("/mfa/confirm", append(chain.AuthWithAudit(), h.MFA.ConfirmEnrollment)...)
+			auth.POST("/mfa/disable", append(chain.AuthWithAudit(), h.MFA.Disable)...)
+			auth.POST("/mfa/backup-codes", append(chain.AuthWithAudit(), h.MFA.RegenerateBackupCodes)...)
+
+			// 通行密钥管理（用户只能管理自己的通行密钥）
+			auth.GET("/webauthn/credentials", append(chain.AuthWithAudit(), h.WebAuthn.ListCredentials)...)
+			auth.POST("/webauthn/register/begin", append(chain.AuthWithAudit(), h.WebAuthn.BeginRegistration)...)
+			auth.POST("/webauthn/register/finish", append(chain.AuthWithAudit(), h.WebAuthn.FinishRegistration)...)
+			auth.PUT("/webauthn/credentials/:uuid", append(chain.AuthWithAudit(), h.WebAuthn.RenameCredential)...)
+			auth.DELETE("/webauthn/credentials/:uuid", append(chain.AuthWithAudit(), h.WebAuthn.DeleteCredential)...)
+
+			// 通行密钥二次验证（SecureAuth*链中要求二次验证的角色访问秘密前调用）
+			auth.POST("/step-up/begin", append(chain.AuthWithAudit(), h.WebAuthn.BeginStepUp)...)
+			auth.POST("/step-up/finish", append(chain.AuthWithAudit(), h.WebAuthn.FinishStepUp)...)
 		}
 
 		// 用户管理路由（需要认证和管理员权限）
@@ -90,6 +140,12 @@ func Setup(r *gin.Engine, mgr *app.Manager) {
 
 			// 更新用户角色 - 需要user:write权限
 			users.PUT("/:uuid/role", append(chain.AuthWithPermission(middleware.ResourceUser, middleware.ActionWrite), h.User.UpdateUserRole)...)
+
+			// 重置用户的两步验证 - 需要user:write权限
+			users.POST("/:uuid/mfa/reset", append(chain.AuthWithPermission(middleware.ResourceUser, middleware.ActionWrite), h.MFA.ResetUserMFA)...)
+
+			// 重置用户的通行密钥 - 需要user:write权限
+			users.POST("/:uuid/webauthn/reset", append(chain.AuthWithPermission(middleware.ResourceUser, middleware.ActionWrite), h.WebAuthn.ResetUserCredentials)...)
 		}
 
 		// 用户档案路由（需要认证）
@@ -138,18 +194,188 @@ func Setup(r *gin.Engine, mgr *app.Manager) {
 		secrets := v1.Group("/secrets")
 		{
 			// 获取秘密列表 - 需要secret:read权限
-			secrets.GET("", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionRead), h.Secret.ListSecrets)...)
+			// 限定了秘密范围的个人访问令牌只列出范围内的秘密
+			secrets.GET("", append(chain.SecureAuthWithSecretCollection(middleware.ActionRead), h.Secret.ListSecrets)...)
 
 			// 创建秘密 - 需要secret:write权限
 			// 注意：readonly角色不应该有此权限
 			secrets.POST("", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionWrite), h.Secret.CreateSecret)...)
 
+			// 批量导入秘密 - 需要secret:write权限
+			secrets.POST("/import", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionWrite), h.Secret.ImportSecrets)...)
+
+			// 导出加密备份 - 需要secret:read权限
+			secrets.POST("/backup", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionRead), h.Backup.ExportBackup)...)
+
+			// 从加密备份恢复 - 需要secret:write权限
+			secrets.POST("/restore", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionWrite), h.Backup.RestoreBackup)...)
+
+			// 渲染配置模板 - 需要secret:read权限
+			// 限定了秘密范围的个人访问令牌只能引用范围内的秘密
+			secrets.POST("/render", append(chain.SecureAuthWithSecretCollection(middleware.ActionRead), h.Template.Render)...)
+
+			// 回收站列表 - 需要secret:read权限
+			secrets.GET("/trash", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionRead), h.Trash.ListTrash)...)
+
+			// 从回收站恢复秘密 - 需要secret:write权限
+			secrets.POST("/trash/:uuid/restore", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionWrite), h.Trash.RestoreSecret)...)
+
+			// 立即彻底清除回收站（需要安全密码）- 需要secret:write权限
+			secrets.POST("/trash/purge", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionWrite), h.Trash.PurgeTrash)...)
+
 			// 解密秘密（获取明文）- 需要secret:read权限
 			secrets.POST("/:uuid/decrypt", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionRead), h.Secret.GetSecret)...)
 
 			// 删除秘密 - 需要secret:write权限
 			// 注意：readonly角色不应该有此权限
 			secrets.DELETE("/:uuid", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionWrite), h.Secret.DeleteSecret)...)
+
+			// 附件列表 - 需要secret:read权限
+			secrets.GET("/:uuid/attachments", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionRead), h.Attachment.ListAttachments)...)
+
+			// 上传附件（multipart，需要安全密码）- 需要secret:write权限
+			secrets.POST("/:uuid/attachments", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionWrite), h.Attachment.UploadAttachment)...)
+
+			// 下载附件（流式解密，需要安全密码）- 需要secret:read权限
+			secrets.POST("/:uuid/attachments/:attachment_uuid/download", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionRead), h.Attachment.DownloadAttachment)...)
+
+			// 删除附件 - 需要secret:write权限
+			secrets.DELETE("/:uuid/attachments/:attachment_uuid", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionWrite), h.Attachment.DeleteAttachment)...)
+
+			// 设置检出策略和密码生成规则 - 需要secret:write权限
+			secrets.PUT("/:uuid/checkout-policy", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionWrite), h.Checkout.SetCheckoutPolicy)...)
+
+			// 查询检出状态（当前持有者和检出历史）- 需要secret:read权限
+			secrets.GET("/:uuid/checkout", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionRead), h.Checkout.GetCheckoutStatus)...)
+
+			// 检出秘密（独占租约，需要安全密码）- 需要secret:read权限
+			secrets.POST("/:uuid/checkout", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionRead), h.Checkout.Checkout)...)
+
+			// 检入秘密 - 需要secret:read权限
+			secrets.POST("/:uuid/checkin", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionRead), h.Checkout.Checkin)...)
+
+			// 轮换秘密（需要安全密码）- 需要secret:write权限
+			secrets.POST("/:uuid/rotate", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionWrite), h.Checkout.RotateSecret)...)
+
+			// 设置审批策略（高敏感秘密）- 需要secret:write权限
+			secrets.PUT("/:uuid/approval-policy", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionWrite), h.Approval.SetApprovalPolicy)...)
+
+			// 提交访问申请 - 需要secret:read权限
+			secrets.POST("/:uuid/access-requests", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionRead), h.Approval.CreateAccessRequest)...)
+		}
+
+		// 访问审批路由
+		// 审批人可以是任意角色的用户，审批资格由服务层根据保险库审批人配置校验
+		accessRequests := v1.Group("/access-requests")
+		{
+			// 我的访问申请
+			accessRequests.GET("", append(chain.AuthWithAudit(), h.Approval.ListMyAccessRequests)...)
+
+			// 待我审批的申请
+			accessRequests.GET("/pending", append(chain.AuthWithAudit(), h.Approval.ListPendingApprovals)...)
+
+			// 查询邮件审批链接对应的申请（无需登录，不消耗令牌）
+			accessRequests.GET("/email-decision", append(chain.RateLimit(), h.Approval.GetEmailApproval)...)
+
+			// 通过邮件链接审批（无需登录，一次性令牌）
+			accessRequests.POST("/email-decision", append(chain.RateLimit(), h.Approval.DecideByEmail)...)
+
+			// 批准申请
+			accessRequests.POST("/:uuid/approve", append(chain.AuthWithAudit(), h.Approval.ApproveAccessRequest)...)
+
+			// 拒绝申请
+			accessRequests.POST("/:uuid/deny", append(chain.AuthWithAudit(), h.Approval.DenyAccessRequest)...)
+
+			// 撤回申请
+			accessRequests.POST("/:uuid/cancel", append(chain.AuthWithAudit(), h.Approval.CancelAccessRequest)...)
+		}
+
+		// 紧急访问路由
+		// 授权人和可信联系人都必须设置安全密码；授权关系由服务层校验
+		emergency := v1.Group("/emergency-access")
+		{
+			// 查询/生成紧急访问密钥对（被指定为可信联系人前需要生成）
+			emergency.GET("/keypair", append(chain.SecureAuth(), h.Emergency.GetKeyPairStatus)...)
+			emergency.POST("/keypair", append(chain.SecureAuth(), h.Emergency.InitKeyPair)...)
+
+			// 授权人：管理可信联系人，处理紧急访问申请
+			emergency.GET("/trusted", append(chain.SecureAuth(), h.Emergency.ListTrustedContacts)...)
+			emergency.POST("/trusted", append(chain.SecureAuth(), h.Emergency.DesignateContact)...)
+			emergency.DELETE("/trusted/:uuid", append(chain.SecureAuth(), h.Emergency.RevokeContact)...)
+			emergency.POST("/trusted/:uuid/approve", append(chain.SecureAuth(), h.Emergency.ApproveAccess)...)
+			emergency.POST("/trusted/:uuid/reject", append(chain.SecureAuth(), h.Emergency.RejectAccess)...)
+
+			// 可信联系人：发起申请，生效后查看秘密或接管账户
+			emergency.GET("/granted", append(chain.SecureAuth(), h.Emergency.ListGrantedToMe)...)
+			emergency.POST("/granted/:uuid/request", append(chain.SecureAuth(), h.Emergency.RequestAccess)...)
+			emergency.GET("/granted/:uuid/secrets", append(chain.SecureAuth(), h.Emergency.ListGrantorSecrets)...)
+			emergency.POST("/granted/:uuid/secrets/:secret_uuid/decrypt", append(chain.SecureAuth(), h.Emergency.DecryptGrantorSecret)...)
+			emergency.POST("/granted/:uuid/takeover", append(chain.SecureAuth(), h.Emergency.Takeover)...)
+		}
+
+		// 保险库设置路由
+		vault := v1.Group("/vault")
+		{
+			// 查询保险库审批人 - 需要vault:read权限
+			vault.GET("/approvers", append(chain.AuthWithPermission(middleware.ResourceVault, middleware.ActionRead), h.Approval.GetVaultApprovers)...)
+
+			// 设置保险库审批人 - 需要vault:write权限
+			vault.PUT("/approvers", append(chain.AuthWithPermission(middleware.ResourceVault, middleware.ActionWrite), h.Approval.SetVaultApprovers)...)
+
+			// 查询保险库健康报告（重复密码、弱密码、过旧和从未访问的秘密）- 需要vault:read权限
+			vault.GET("/health", append(chain.AuthWithPermission(middleware.ResourceVault, middleware.ActionRead), h.VaultHealth.GetReport)...)
+
+			// 重新计算密码指纹和强度评分 - 需要vault:write权限+安全密码
+			vault.POST("/health/rescan", append(chain.SecureAuthWithPermission(middleware.ResourceVault, middleware.ActionWrite), h.VaultHealth.Rescan)...)
+		}
+
+		// 服务账户管理路由
+		// 用户只能管理自己保险库下的服务账户
+		// 权限要求：service_account:read用于查询，service_account:write用于创建/删除/授权
+		serviceAccounts := v1.Group("/service-accounts")
+		{
+			// 查询服务账户 - 需要service_account:read权限
+			serviceAccounts.GET("", append(chain.AuthWithPermission(middleware.ResourceServiceAccount, middleware.ActionRead), h.ServiceAccount.ListServiceAccounts)...)
+			serviceAccounts.GET("/:uuid", append(chain.AuthWithPermission(middleware.ResourceServiceAccount, middleware.ActionRead), h.ServiceAccount.GetServiceAccount)...)
+
+			// 创建服务账户 - 需要service_account:write权限+安全密码（为服务账户封装保险库密钥）
+			serviceAccounts.POST("", append(chain.SecureAuthWithPermission(middleware.ResourceServiceAccount, middleware.ActionWrite), h.ServiceAccount.CreateServiceAccount)...)
+
+			// 删除服务账户 - 需要service_account:write权限
+			serviceAccounts.DELETE("/:uuid", append(chain.AuthWithPermission(middleware.ResourceServiceAccount, middleware.ActionWrite), h.ServiceAccount.DeleteServiceAccount)...)
+
+			// secret_id管理 - 生成secret_id相当于签发登录凭证，与授权一样需要安全密码保护的会话
+			serviceAccounts.GET("/:uuid/secret-ids", append(chain.AuthWithPermission(middleware.ResourceServiceAccount, middleware.ActionRead), h.ServiceAccount.ListSecretIDs)...)
+			serviceAccounts.POST("/:uuid/secret-ids", append(chain.SecureAuthWithPermission(middleware.ResourceServiceAccount, middleware.ActionWrite), h.ServiceAccount.CreateSecretID)...)
+			serviceAccounts.DELETE("/:uuid/secret-ids/:secret_id_uuid", append(chain.AuthWithPermission(middleware.ResourceServiceAccount, middleware.ActionWrite), h.ServiceAccount.RevokeSecretID)...)
+
+			// 设置服务账户可以访问的秘密 - 需要service_account:write权限
+			serviceAccounts.PUT("/:uuid/secrets", append(chain.SecureAuthWithPermission(middleware.ResourceServiceAccount, middleware.ActionWrite), h.ServiceAccount.SetGrants)...)
+		}
+
+		// 服务账户访问路由（只接受服务账户令牌）
+		// 服务账户只能读取被授权的秘密，权限由服务账户自身的Casbin主体检查
+		machine := v1.Group("/machine")
+		{
+			machine.GET("/secrets", append(chain.ServiceAccountWithPermission(middleware.ResourceSecret, middleware.ActionRead), h.ServiceAccount.ListMachineSecrets)...)
+			machine.GET("/secrets/:uuid", append(chain.ServiceAccountWithPermission(middleware.ResourceSecret, middleware.ActionRead), h.ServiceAccount.GetMachineSecret)...)
+		}
+
+		// 一次性分享链接路由
+		// 创建和撤销需要登录；查看接口公开（凭链接访问），只做限流，查看审计由服务层以创建者身份记录
+		shares := v1.Group("/shares")
+		{
+			// 创建分享 - 分享内容来自秘密，需要secret:read权限
+			shares.POST("", append(chain.SecureAuthWithPermission(middleware.ResourceSecret, middleware.ActionRead), h.Share.CreateShare)...)
+
+			// 撤销分享 - 只能撤销自己创建的分享
+			shares.DELETE("/:uuid", append(chain.AuthWithAudit(), h.Share.RevokeShare)...)
+
+			// 获取分享信息（不消耗查看次数）
+			shares.GET("/:uuid", append(chain.RateLimit(), h.Share.GetShareInfo)...)
+
+			// 查看分享内容（消耗一次查看次数）
+			shares.POST("/:uuid/open", append(chain.RateLimit(), h.Share.OpenShare)...)
 		}
 
 		// 管理员用户档案路由（需要认证和管理员权限）
@@ -163,6 +389,12 @@ func Setup(r *gin.Engine, mgr *app.Manager) {
 
 			// 更新指定用户档案 - 需要profile:write权限
 			admin.PUT("/users/:user_id/profile", append(chain.AuthWithPermission(middleware.ResourceProfile, middleware.ActionWrite), h.Profile.UpdateUserProfile)...)
+
+			// 查询所有检出记录 - 需要checkout:read权限
+			admin.GET("/checkouts", append(chain.AuthWithPermission(middleware.ResourceCheckout, middleware.ActionRead), h.Checkout.ListCheckouts)...)
+
+			// 强制释放秘密的检出 - 需要checkout:write权限
+			admin.POST("/checkouts/:uuid/release", append(chain.AuthWithPermission(middleware.ResourceCheckout, middleware.ActionWrite), h.Checkout.ForceRelease)...)
 		}
 
 		// 系统配置路由（需要认证和管理员权限）
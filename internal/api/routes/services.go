@@ -11,35 +11,73 @@ import (
 // 2. 依赖关系清晰可见，便于理解服务间的调用链
 // 3. 新增服务时只需修改此文件，符合单一职责原则
 type ServiceContainer struct {
-	Email        *service.EmailService
-	Auth         *service.AuthService
-	User         *service.UserService
-	Profile      *service.UserProfileService
-	Encryption   *service.EncryptionService
-	Recovery     *service.RecoveryService
-	KeyRotation  *service.KeyRotationService
-	SystemConfig *service.SystemConfigService
-	Statistics   *service.StatisticsService
+	Email          *service.EmailService
+	Auth           *service.AuthService
+	MFA            *service.MFAService
+	WebAuthn       *service.WebAuthnService
+	OIDC           *service.OIDCService
+	LDAP           *service.LDAPService
+	Session        *service.SessionService
+	Lockout        *service.LoginLockoutService
+	Password       *service.PasswordPolicyService
+	AccessToken    *service.PersonalAccessTokenService
+	ServiceAccount *service.ServiceAccountService
+	User           *service.UserService
+	Profile        *service.UserProfileService
+	Encryption     *service.EncryptionService
+	Recovery       *service.RecoveryService
+	KeyRotation    *service.KeyRotationService
+	SystemConfig   *service.SystemConfigService
+	Statistics     *service.StatisticsService
+	Import         *service.ImportService
+	Backup         *service.BackupService
+	Trash          *service.TrashService
+	Attachment     *service.AttachmentService
+	Share          *service.ShareService
+	Checkout       *service.CheckoutService
+	Approval       *service.ApprovalService
+	Emergency      *service.EmergencyAccessService
+	VaultHealth    *service.VaultHealthService
+	Template       *service.TemplateService
 }
 
 // NewServiceContainer 创建服务容器
 // 按照依赖顺序构建服务实例：
-// 1. 基础服务（无依赖）：Email, User, Profile, Encryption, Recovery
-// 2. 依赖基础服务的服务：Auth(依赖Email), KeyRotation(依赖Encryption)
+// 1. 基础服务（无依赖）：Email, MFA, WebAuthn, OIDC, LDAP, Session, Password, AccessToken, User, Profile, Encryption, Recovery
+// 2. 依赖基础服务的服务：Lockout(依赖Email), Auth(依赖Email、MFA、WebAuthn、OIDC、LDAP、Session、Lockout、Password), Approval(依赖Email), KeyRotation(依赖Encryption), Import/Backup/Attachment/Trash/Share/Checkout/VaultHealth/Template(依赖Encryption), Emergency(依赖Encryption、Email、Password), ServiceAccount(依赖Encryption)
 // 3. 系统服务：SystemConfig, Statistics
 func NewServiceContainer(mgr *app.Manager) *ServiceContainer {
 	sc := &ServiceContainer{}
 
 	// 第一层：基础服务（无其他服务依赖）
 	sc.Email = service.NewEmailService(mgr.DB, mgr.Redis, mgr.ConfigManager)
-	sc.User = service.NewUserService(mgr.DB)
+	sc.MFA = service.NewMFAService(mgr.DB, mgr.Redis, mgr.ConfigManager, mgr.ServerKey)
+	sc.WebAuthn = service.NewWebAuthnService(mgr.DB, mgr.Redis, mgr.ConfigManager, mgr.WebAuthn)
+	sc.OIDC = service.NewOIDCService(mgr.DB, mgr.Redis, mgr.OIDC, mgr.OIDCConfig)
+	sc.LDAP = service.NewLDAPService(mgr.DB, mgr.Redis, mgr.LDAP, mgr.LDAPConfig)
+	sc.Session = service.NewSessionService(mgr.DB, mgr.Redis, mgr.JWT, mgr.ConfigManager)
+	sc.Password = service.NewPasswordPolicyService(mgr.DB, mgr.ConfigManager, mgr.BreachChecker)
+	sc.AccessToken = service.NewPersonalAccessTokenService(mgr.DB, mgr.Enforcer, mgr.ConfigManager)
+	sc.User = service.NewUserService(mgr.DB, mgr.Redis)
 	sc.Profile = service.NewUserProfileService(mgr.DB)
-	sc.Encryption = service.NewEncryptionService(mgr.DB)
+	sc.Encryption = service.NewEncryptionService(mgr.DB, mgr.BreachChecker)
 	sc.Recovery = service.NewRecoveryService(mgr.DB)
 
 	// 第二层：依赖其他服务的服务
-	sc.Auth = service.NewAuthService(mgr.DB, mgr.JWT, mgr.Redis, sc.Email)
+	sc.Lockout = service.NewLoginLockoutService(mgr.DB, mgr.Redis, mgr.ConfigManager, sc.Email)
+	sc.Auth = service.NewAuthService(mgr.DB, mgr.JWT, mgr.Redis, sc.Email, sc.MFA, sc.WebAuthn, sc.Session, sc.Lockout, sc.Password, sc.OIDC, newAuthProviders(mgr, sc.LDAP))
 	sc.KeyRotation = service.NewKeyRotationService(mgr.DB, sc.Encryption, mgr.ConfigManager)
+	sc.Import = service.NewImportService(mgr.DB, sc.Encryption)
+	sc.Backup = service.NewBackupService(mgr.DB, sc.Encryption)
+	sc.Attachment = service.NewAttachmentService(mgr.DB, sc.Encryption, mgr.ConfigManager, mgr.BlobStore)
+	sc.Trash = service.NewTrashService(mgr.DB, sc.Encryption, sc.Attachment, mgr.ConfigManager, mgr.AuditService)
+	sc.Share = service.NewShareService(mgr.Redis, sc.Encryption, mgr.AuditService)
+	sc.Checkout = service.NewCheckoutService(mgr.DB, mgr.Redis, sc.Encryption, mgr.ConfigManager, mgr.AuditService)
+	sc.Approval = service.NewApprovalService(mgr.DB, mgr.Redis, sc.Email, mgr.ConfigManager, mgr.AuditService, mgr.PublicURL)
+	sc.Emergency = service.NewEmergencyAccessService(mgr.DB, mgr.Redis, sc.Encryption, sc.Email, mgr.ConfigManager, mgr.AuditService, sc.Password)
+	sc.VaultHealth = service.NewVaultHealthService(mgr.DB, sc.Encryption, mgr.ConfigManager)
+	sc.Template = service.NewTemplateService(mgr.DB, sc.Encryption, mgr.AuditService)
+	sc.ServiceAccount = service.NewServiceAccountService(mgr.DB, mgr.Redis, mgr.Enforcer, mgr.ConfigManager, sc.Encryption, mgr.ServerKey)
 
 	// 第三层：系统服务
 	sc.SystemConfig = service.NewSystemConfigService(mgr.DB, mgr.ConfigManager)
@@ -47,3 +85,15 @@ func NewServiceContainer(mgr *app.Manager) *ServiceContainer {
 
 	return sc
 }
+
+// newAuthProviders 按顺序构建用户名密码登录的认证提供方
+// 本地账户优先；启用LDAP时，已关联LDAP身份的用户只能以目录密码登录
+func newAuthProviders(mgr *app.Manager, ldapService *service.LDAPService) []service.AuthProvider {
+	if !ldapService.Enabled() {
+		return []service.AuthProvider{service.NewLocalAuthProvider(mgr.DB)}
+	}
+	return []service.AuthProvider{
+		service.NewLocalAuthProvider(mgr.DB, service.LDAPIdentityIssuer),
+		ldapService,
+	}
+}
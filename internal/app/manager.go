@@ -1,7 +1,14 @@
 package app
 
 import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/casbin/casbin/v2"
@@ -9,9 +16,15 @@ import (
 	"github.com/cuihe500/vaulthub/internal/config"
 	"github.com/cuihe500/vaulthub/internal/database"
 	"github.com/cuihe500/vaulthub/internal/service"
+	"github.com/cuihe500/vaulthub/pkg/blobstore"
+	"github.com/cuihe500/vaulthub/pkg/breach"
 	"github.com/cuihe500/vaulthub/pkg/jwt"
+	"github.com/cuihe500/vaulthub/pkg/ldap"
 	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/oidc"
 	redisClient "github.com/cuihe500/vaulthub/pkg/redis"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
 	"gorm.io/gorm"
 )
 
@@ -24,6 +37,15 @@ type Manager struct {
 	Redis         *redisClient.Client   // Redis客户端
 	ConfigManager *config.ConfigManager // 系统配置管理器
 	AuditService  *service.AuditService // 审计服务
+	BlobStore     blobstore.Store       // 附件存储
+	BreachChecker breach.Checker        // 泄露密码检查器，未配置数据集时为nil
+	ServerKey     []byte                // 由security.encryption_key派生的服务端加密密钥（如加密TOTP密钥）
+	WebAuthn      *webauthn.WebAuthn    // WebAuthn依赖方（通行密钥注册和验证）
+	OIDC          *oidc.Provider        // OIDC身份提供方，未启用单点登录时为nil
+	OIDCConfig    config.OIDCConfig     // OIDC账户关联和角色映射配置
+	LDAP          *ldap.Config          // LDAP连接配置，未启用LDAP认证时为nil
+	LDAPConfig    config.LDAPConfig     // LDAP用户搜索、角色映射和同步配置
+	PublicURL     string                // 服务对外访问地址，用于拼接邮件中的链接，未配置时为空
 	// Cache *cache.Client // 未来添加其他连接
 }
 
@@ -60,6 +82,41 @@ func (m *Manager) Initialize(cfg *config.Config) error {
 		return fmt.Errorf("初始化审计服务失败: %w", err)
 	}
 
+	// 初始化附件存储
+	if err := m.initBlobStore(cfg.Storage); err != nil {
+		return fmt.Errorf("初始化附件存储失败: %w", err)
+	}
+
+	// 加载泄露密码数据
+	if err := m.initBreachChecker(cfg.Security); err != nil {
+		return fmt.Errorf("加载泄露密码数据失败: %w", err)
+	}
+
+	// 派生服务端加密密钥
+	if err := m.initServerKey(cfg.Security); err != nil {
+		return fmt.Errorf("派生服务端加密密钥失败: %w", err)
+	}
+
+	// 初始化WebAuthn依赖方
+	if err := m.initWebAuthn(cfg.WebAuthn); err != nil {
+		return fmt.Errorf("初始化WebAuthn失败: %w", err)
+	}
+
+	// 初始化OIDC身份提供方
+	if err := m.initOIDC(cfg.OIDC); err != nil {
+		return fmt.Errorf("初始化OIDC失败: %w", err)
+	}
+
+	// 初始化LDAP连接配置
+	if err := m.initLDAP(cfg.LDAP); err != nil {
+		return fmt.Errorf("初始化LDAP失败: %w", err)
+	}
+
+	// 校验对外访问地址
+	if err := m.initPublicURL(cfg.Server); err != nil {
+		return fmt.Errorf("校验server.public_url失败: %w", err)
+	}
+
 	// 未来在这里添加其他连接的初始化
 
 	return nil
@@ -96,6 +153,13 @@ func (m *Manager) Close() error {
 		}
 	}
 
+	// 关闭泄露密码数据文件
+	if m.BreachChecker != nil {
+		if err := m.BreachChecker.Close(); err != nil {
+			logger.Error("关闭泄露密码数据失败", logger.Err(err))
+		}
+	}
+
 	// 未来在这里添加其他连接的关闭逻辑
 
 	return nil
@@ -113,9 +177,12 @@ func (m *Manager) initDatabase(cfg config.DatabaseConfig) error {
 
 // initJWT 初始化JWT管理器
 func (m *Manager) initJWT(cfg config.SecurityConfig) error {
-	expiration := time.Duration(cfg.JWTExpiration) * time.Hour
-	m.JWT = jwt.NewManager(cfg.JWTSecret, expiration)
-	logger.Info("JWT管理器初始化成功", logger.Int("expiration_hours", cfg.JWTExpiration))
+	expiration := time.Duration(cfg.AccessTokenExpiration) * time.Minute
+	refreshExpiration := time.Duration(cfg.RefreshTokenExpiration) * time.Hour
+	m.JWT = jwt.NewManager(cfg.JWTSecret, expiration, refreshExpiration)
+	logger.Info("JWT管理器初始化成功",
+		logger.Int("access_token_expiration_minutes", cfg.AccessTokenExpiration),
+		logger.Int("refresh_token_expiration_hours", cfg.RefreshTokenExpiration))
 	return nil
 }
 
@@ -176,4 +243,176 @@ func (m *Manager) initAuditService(auditCfg config.AuditConfig) error {
 	return nil
 }
 
+// initBlobStore 初始化附件存储
+func (m *Manager) initBlobStore(cfg config.StorageConfig) error {
+	store, err := blobstore.New(blobstore.Config{Type: cfg.Type, Path: cfg.Path})
+	if err != nil {
+		return err
+	}
+	m.BlobStore = store
+	logger.Info("附件存储初始化成功", logger.String("type", cfg.Type), logger.String("path", cfg.Path))
+	return nil
+}
+
+// initBreachChecker 加载泄露密码数据，未配置路径时跳过
+func (m *Manager) initBreachChecker(cfg config.SecurityConfig) error {
+	if cfg.BreachedPasswordsPath == "" {
+		logger.Info("未配置泄露密码数据，跳过泄露密码检查")
+		return nil
+	}
+
+	checker, err := breach.Open(cfg.BreachedPasswordsPath)
+	if err != nil {
+		return err
+	}
+	m.BreachChecker = checker
+	logger.Info("泄露密码数据加载成功", logger.String("path", cfg.BreachedPasswordsPath))
+	return nil
+}
+
+// initServerKey 由 security.encryption_key 派生服务端加密密钥
+// 用于加密服务端自身需要读取的数据（用户秘密仍由用户的DEK加密，与此密钥无关）
+func (m *Manager) initServerKey(cfg config.SecurityConfig) error {
+	if cfg.EncryptionKey == "" {
+		return fmt.Errorf("security.encryption_key未配置")
+	}
+
+	key, err := hkdf.Key(sha256.New, []byte(cfg.EncryptionKey), nil, "vaulthub-server-key", 32)
+	if err != nil {
+		return err
+	}
+	m.ServerKey = key
+	return nil
+}
+
+// initWebAuthn 初始化WebAuthn依赖方
+// 默认不要求认证器证明（attestation为none），只校验签名，不校验认证器型号
+func (m *Manager) initWebAuthn(cfg config.WebAuthnConfig) error {
+	var attestation protocol.ConveyancePreference
+	switch cfg.Attestation {
+	case "", "none":
+		attestation = protocol.PreferNoAttestation
+	case "indirect":
+		attestation = protocol.PreferIndirectAttestation
+	case "direct":
+		attestation = protocol.PreferDirectAttestation
+	default:
+		return fmt.Errorf("不支持的webauthn.attestation: %s", cfg.Attestation)
+	}
+
+	wa, err := webauthn.New(&webauthn.Config{
+		RPID:                  cfg.RPID,
+		RPDisplayName:         cfg.RPDisplayName,
+		RPOrigins:             cfg.RPOrigins,
+		AttestationPreference: attestation,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			// 要求可发现凭证，才能在不输入用户名的情况下直接用通行密钥登录
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			UserVerification:   protocol.VerificationRequired,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	m.WebAuthn = wa
+	return nil
+}
+
 // 未来添加其他连接的初始化方法
+
+// initOIDC 创建OIDC身份提供方
+// 只校验配置，不访问身份提供方；发现文档在第一次登录时获取，身份提供方暂时不可用不影响服务启动
+func (m *Manager) initOIDC(cfg config.OIDCConfig) error {
+	m.OIDCConfig = cfg
+	if !cfg.Enabled {
+		return nil
+	}
+	for _, mapping := range cfg.RoleMappings {
+		if value, role, ok := strings.Cut(mapping, "="); !ok || value == "" || role == "" {
+			return fmt.Errorf("无效的oidc.role_mappings: %q，格式应为 声明值=角色", mapping)
+		}
+	}
+
+	provider, err := oidc.NewProvider(oidc.Config{
+		Issuer:       cfg.Issuer,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+		Scopes:       cfg.Scopes,
+	})
+	if err != nil {
+		return err
+	}
+	m.OIDC = provider
+	logger.Info("OIDC单点登录已启用", logger.String("issuer", cfg.Issuer))
+	return nil
+}
+
+// initLDAP 校验LDAP配置并准备TLS配置
+// 不连接目录服务，目录服务暂时不可用只影响LDAP用户登录
+func (m *Manager) initLDAP(cfg config.LDAPConfig) error {
+	m.LDAPConfig = cfg
+	if !cfg.Enabled {
+		return nil
+	}
+	if !strings.HasPrefix(cfg.URL, "ldap://") && !strings.HasPrefix(cfg.URL, "ldaps://") {
+		return fmt.Errorf("无效的ldap.url: %q，应以 ldap:// 或 ldaps:// 开头", cfg.URL)
+	}
+	if cfg.UserBaseDN == "" || !strings.Contains(cfg.UserFilter, "{username}") {
+		return fmt.Errorf("ldap.user_base_dn不能为空，ldap.user_filter必须包含 {username}")
+	}
+	for _, filter := range []string{cfg.UserFilter, cfg.GroupFilter} {
+		if _, err := ldap.ParseFilter(strings.NewReplacer("{username}", "x", "{dn}", "x").Replace(filter)); err != nil {
+			return err
+		}
+	}
+	for _, mapping := range cfg.RoleMappings {
+		if value, role, ok := strings.Cut(mapping, "="); !ok || value == "" || role == "" {
+			return fmt.Errorf("无效的ldap.role_mappings: %q，格式应为 组名=角色", mapping)
+		}
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, // 由配置显式开启，仅用于测试
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return fmt.Errorf("读取ldap.ca_file失败: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("ldap.ca_file中没有有效的证书")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if strings.HasPrefix(cfg.URL, "ldap://") && !cfg.StartTLS {
+		logger.Warn("LDAP连接未加密，用户密码将以明文传输，建议启用start_tls或使用ldaps://")
+	}
+
+	m.LDAP = &ldap.Config{
+		URL:       cfg.URL,
+		StartTLS:  cfg.StartTLS,
+		TLSConfig: tlsConfig,
+		Timeout:   time.Duration(cfg.TimeoutSeconds) * time.Second,
+	}
+	logger.Info("LDAP认证已启用", logger.String("url", cfg.URL), logger.Bool("start_tls", cfg.StartTLS))
+	return nil
+}
+
+// initPublicURL 校验服务对外访问地址
+// 邮件中的链接只使用配置的地址拼接，不使用请求中的Host头，避免请求方把链接指向自己的服务器
+func (m *Manager) initPublicURL(cfg config.ServerConfig) error {
+	if cfg.PublicURL == "" {
+		logger.Warn("未配置server.public_url，邮件中不会包含审批链接")
+		return nil
+	}
+	parsed, err := url.Parse(cfg.PublicURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("无效的server.public_url: %q，应为 http:// 或 https:// 开头的完整地址", cfg.PublicURL)
+	}
+	m.PublicURL = strings.TrimRight(cfg.PublicURL, "/")
+	return nil
+}
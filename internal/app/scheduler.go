@@ -1,6 +1,8 @@
 package app
 
 import (
+	"fmt"
+
 	"github.com/cuihe500/vaulthub/internal/service"
 	"github.com/cuihe500/vaulthub/pkg/logger"
 	"github.com/robfig/cron/v3"
@@ -12,10 +14,14 @@ type Scheduler struct {
 	cron               *cron.Cron
 	keyRotationService *service.KeyRotationService
 	statisticsService  *service.StatisticsService
+	trashService       *service.TrashService
+	checkoutService    *service.CheckoutService
+	emergencyService   *service.EmergencyAccessService
+	ldapService        *service.LDAPService
 }
 
 // NewScheduler 创建定时任务调度器实例
-func NewScheduler(keyRotationService *service.KeyRotationService, statisticsService *service.StatisticsService) *Scheduler {
+func NewScheduler(keyRotationService *service.KeyRotationService, statisticsService *service.StatisticsService, trashService *service.TrashService, checkoutService *service.CheckoutService, emergencyService *service.EmergencyAccessService, ldapService *service.LDAPService) *Scheduler {
 	// 使用带秒级精度的cron
 	c := cron.New(cron.WithSeconds())
 
@@ -23,6 +29,10 @@ func NewScheduler(keyRotationService *service.KeyRotationService, statisticsServ
 		cron:               c,
 		keyRotationService: keyRotationService,
 		statisticsService:  statisticsService,
+		trashService:       trashService,
+		checkoutService:    checkoutService,
+		emergencyService:   emergencyService,
+		ldapService:        ldapService,
 	}
 }
 
@@ -63,6 +73,63 @@ func (s *Scheduler) Start() error {
 		return err
 	}
 
+	// 每天凌晨4点彻底清除超过保留期的回收站秘密
+	// "0 0 4 * * *" = 每天4点0分0秒
+	_, err = s.cron.AddFunc("0 0 4 * * *", func() {
+		logger.Info("开始执行定时任务：清除过期回收站秘密")
+		if _, err := s.trashService.PurgeExpired(); err != nil {
+			logger.Error("清除过期回收站秘密失败", logger.Err(err))
+		} else {
+			logger.Info("完成定时任务：清除过期回收站秘密")
+		}
+	})
+
+	if err != nil {
+		logger.Error("添加回收站清除定时任务失败", logger.Err(err))
+		return err
+	}
+
+	// 每分钟释放租约已到期的秘密检出，并标记秘密待轮换
+	// "0 * * * * *" = 每分钟第0秒
+	_, err = s.cron.AddFunc("0 * * * * *", func() {
+		if _, err := s.checkoutService.ExpireOverdue(); err != nil {
+			logger.Error("释放超时检出失败", logger.Err(err))
+		}
+	})
+
+	if err != nil {
+		logger.Error("添加检出超时释放定时任务失败", logger.Err(err))
+		return err
+	}
+
+	// 每10分钟处理等待期已结束的紧急访问申请，并通知可信联系人
+	// "0 */10 * * * *" = 每10分钟第0秒
+	_, err = s.cron.AddFunc("0 */10 * * * *", func() {
+		if _, err := s.emergencyService.PromoteDueRequests(); err != nil {
+			logger.Error("处理到期紧急访问申请失败", logger.Err(err))
+		}
+	})
+
+	if err != nil {
+		logger.Error("添加紧急访问定时任务失败", logger.Err(err))
+		return err
+	}
+
+	// 按配置的间隔同步LDAP用户的角色（未启用LDAP或间隔为0时不同步）
+	// "@every 60m" = 每60分钟
+	if s.ldapService.SyncEnabled() {
+		_, err = s.cron.AddFunc(fmt.Sprintf("@every %dm", s.ldapService.SyncIntervalMinutes()), func() {
+			if _, err := s.ldapService.SyncRoles(); err != nil {
+				logger.Error("同步LDAP用户角色失败", logger.Err(err))
+			}
+		})
+
+		if err != nil {
+			logger.Error("添加LDAP角色同步定时任务失败", logger.Err(err))
+			return err
+		}
+	}
+
 	// 启动cron调度器
 	s.cron.Start()
 	logger.Info("定时任务调度器已启动")
@@ -15,12 +15,19 @@ type Config struct {
 	Security SecurityConfig `mapstructure:"security"`
 	Logger   LoggerConfig   `mapstructure:"logger"`
 	Audit    AuditConfig    `mapstructure:"audit"`
+	Storage  StorageConfig  `mapstructure:"storage"`
+	GRPC     GRPCConfig     `mapstructure:"grpc"`
+	WebAuthn WebAuthnConfig `mapstructure:"webauthn"`
+	OIDC     OIDCConfig     `mapstructure:"oidc"`
+	LDAP     LDAPConfig     `mapstructure:"ldap"`
 }
 
 type ServerConfig struct {
 	Host string `mapstructure:"host"`
 	Port int    `mapstructure:"port"`
 	Mode string `mapstructure:"mode"` // debug, release, test
+	// PublicURL 服务对外访问地址（如 https://vault.example.com），用于拼接邮件中的链接
+	PublicURL string `mapstructure:"public_url"`
 }
 
 func (s ServerConfig) Address() string {
@@ -69,12 +76,17 @@ func (r RedisConfig) Address() string {
 }
 
 type SecurityConfig struct {
-	JWTSecret       string `mapstructure:"jwt_secret"`
-	JWTExpiration   int    `mapstructure:"jwt_expiration"` // JWT过期时间（小时）
-	EncryptionKey   string `mapstructure:"encryption_key"`
-	CasbinModelPath string `mapstructure:"casbin_model_path"` // Casbin模型文件路径
-	AdminUsername   string `mapstructure:"admin_username"`    // 超级管理员用户名（首次启动时创建）
-	AdminPassword   string `mapstructure:"admin_password"`    // 超级管理员密码（首次启动时创建）
+	JWTSecret              string `mapstructure:"jwt_secret"`
+	AccessTokenExpiration  int    `mapstructure:"access_token_expiration"`  // 访问令牌有效期（分钟），过期后使用刷新令牌换取
+	RefreshTokenExpiration int    `mapstructure:"refresh_token_expiration"` // 刷新令牌有效期（小时），即会话最长空闲时间
+	EncryptionKey          string `mapstructure:"encryption_key"`
+	CasbinModelPath        string `mapstructure:"casbin_model_path"` // Casbin模型文件路径
+	AdminUsername          string `mapstructure:"admin_username"`    // 超级管理员用户名（首次启动时创建）
+	AdminPassword          string `mapstructure:"admin_password"`    // 超级管理员密码（首次启动时创建）
+
+	// 泄露密码数据路径（HIBP格式的排序哈希列表、前缀目录或由 breach build-filter 生成的布隆过滤器）
+	// 为空时不检查；运行时只读取本地文件，不访问网络
+	BreachedPasswordsPath string `mapstructure:"breached_passwords_path"`
 }
 
 type LoggerConfig struct {
@@ -90,6 +102,73 @@ type AuditConfig struct {
 	MaxDetailSize int `mapstructure:"max_detail_size"` // 审计Details字段最大长度
 }
 
+type StorageConfig struct {
+	Type string `mapstructure:"type"` // 附件存储类型: local(本地文件系统)，后续支持s3
+	Path string `mapstructure:"path"` // 本地存储根目录（type=local时使用）
+}
+
+// GRPCConfig gRPC服务配置，与HTTP服务使用独立端口
+type GRPCConfig struct {
+	Enabled bool   `mapstructure:"enabled"` // 是否启用gRPC服务
+	Host    string `mapstructure:"host"`
+	Port    int    `mapstructure:"port"`
+}
+
+func (g GRPCConfig) Address() string {
+	return fmt.Sprintf("%s:%d", g.Host, g.Port)
+}
+
+// WebAuthnConfig WebAuthn（通行密钥）依赖方配置
+// 通行密钥与rp_id绑定，部署后修改rp_id会导致已注册的通行密钥全部无法使用
+type WebAuthnConfig struct {
+	RPID          string   `mapstructure:"rp_id"`           // 依赖方ID，一般为不含协议和端口的域名
+	RPDisplayName string   `mapstructure:"rp_display_name"` // 认证器中显示的服务名称
+	RPOrigins     []string `mapstructure:"rp_origins"`      // 允许发起验证的页面来源，格式: ["https://vault.example.com"]
+	Attestation   string   `mapstructure:"attestation"`     // 证明方式: none, indirect, direct
+}
+
+// OIDCConfig OpenID Connect单点登录配置
+// 角色映射按顺序匹配role_claim中的值，第一个匹配的映射决定用户角色；每次登录都会按映射同步角色
+type OIDCConfig struct {
+	Enabled       bool     `mapstructure:"enabled"`        // 是否启用OIDC登录
+	Issuer        string   `mapstructure:"issuer"`         // 身份提供方的issuer，发现文档为 {issuer}/.well-known/openid-configuration
+	ClientID      string   `mapstructure:"client_id"`      // 在身份提供方注册的客户端ID
+	ClientSecret  string   `mapstructure:"client_secret"`  // 客户端密钥，公开客户端为空
+	RedirectURL   string   `mapstructure:"redirect_url"`   // 在身份提供方注册的回调地址（前端页面，收到code和state后调用完成登录接口）
+	Scopes        []string `mapstructure:"scopes"`         // 申请的scope
+	UsernameClaim string   `mapstructure:"username_claim"` // 自动创建账户时作为用户名的声明
+	RoleClaim     string   `mapstructure:"role_claim"`     // 用于映射角色的声明（如groups），为空时不按声明映射
+	RoleMappings  []string `mapstructure:"role_mappings"`  // 声明值到Casbin角色的映射，格式: ["vaulthub-admins=admin", "engineering=user"]
+	DefaultRole   string   `mapstructure:"default_role"`   // 没有匹配的映射时使用的角色，为空时拒绝登录
+	AutoProvision bool     `mapstructure:"auto_provision"` // 没有关联账户时是否自动创建
+}
+
+// LDAPConfig LDAP/Active Directory认证配置
+// 过滤器中的 {username} 替换为转义后的登录用户名，{dn} 替换为转义后的用户DN
+type LDAPConfig struct {
+	Enabled             bool     `mapstructure:"enabled"`               // 是否启用LDAP认证
+	URL                 string   `mapstructure:"url"`                   // 服务地址，格式: ldap://host:389 或 ldaps://host:636
+	StartTLS            bool     `mapstructure:"start_tls"`             // ldap:// 连接是否升级为TLS
+	CAFile              string   `mapstructure:"ca_file"`               // 校验服务端证书的CA证书（PEM），为空时使用系统证书
+	InsecureSkipVerify  bool     `mapstructure:"insecure_skip_verify"`  // 不校验服务端证书，仅用于测试
+	TimeoutSeconds      int      `mapstructure:"timeout_seconds"`       // 连接和每个操作的超时时间
+	BindDN              string   `mapstructure:"bind_dn"`               // 搜索用户和组使用的服务账户DN
+	BindPassword        string   `mapstructure:"bind_password"`         // 服务账户密码
+	UserBaseDN          string   `mapstructure:"user_base_dn"`          // 搜索用户的起点
+	UserFilter          string   `mapstructure:"user_filter"`           // 按登录用户名查找用户的过滤器，如 (&(objectClass=person)(uid={username}))
+	UsernameAttribute   string   `mapstructure:"username_attribute"`    // 自动创建账户时作为用户名的属性（AD一般为sAMAccountName）
+	EmailAttribute      string   `mapstructure:"email_attribute"`       // 邮箱属性
+	NameAttribute       string   `mapstructure:"name_attribute"`        // 作为昵称的属性
+	GroupBaseDN         string   `mapstructure:"group_base_dn"`         // 搜索组的起点，为空时不查询组
+	GroupFilter         string   `mapstructure:"group_filter"`          // 查找用户所属组的过滤器，如 (&(objectClass=groupOfNames)(member={dn}))
+	GroupNameAttribute  string   `mapstructure:"group_name_attribute"`  // 组名属性，与role_mappings中的值比较
+	RoleMappings        []string `mapstructure:"role_mappings"`         // 组名到Casbin角色的映射，格式: ["vaulthub-admins=admin", "engineering=user"]
+	DefaultRole         string   `mapstructure:"default_role"`          // 没有匹配的映射时使用的角色，为空时拒绝登录
+	AutoProvision       bool     `mapstructure:"auto_provision"`        // 首次登录时没有关联账户是否自动创建
+	LinkByEmail         bool     `mapstructure:"link_by_email"`         // 是否按目录中的邮箱关联已有的非管理员账户（目录邮箱不能证明用户拥有该邮箱，默认关闭）
+	SyncIntervalMinutes int      `mapstructure:"sync_interval_minutes"` // 定期按组同步角色的间隔，0表示只在登录时同步
+}
+
 func Load() *Config {
 	return load("")
 }
@@ -150,7 +229,8 @@ func setDefaults() {
 	viper.SetDefault("redis.port", 6379)
 	viper.SetDefault("redis.password", "")
 	viper.SetDefault("redis.db", 0)
-	viper.SetDefault("security.jwt_expiration", 24) // 默认24小时
+	viper.SetDefault("security.access_token_expiration", 15)   // 默认15分钟
+	viper.SetDefault("security.refresh_token_expiration", 720) // 默认30天
 	viper.SetDefault("security.casbin_model_path", "./configs/rbac_model.conf")
 	viper.SetDefault("logger.level", "info")
 	viper.SetDefault("logger.encoding", "console")
@@ -159,6 +239,32 @@ func setDefaults() {
 	viper.SetDefault("audit.buffer_size", 5000)      // 默认缓冲区5000
 	viper.SetDefault("audit.worker_count", 5)        // 默认5个worker
 	viper.SetDefault("audit.max_detail_size", 65000) // 默认65KB
+	viper.SetDefault("storage.type", "local")
+	viper.SetDefault("storage.path", "./data/attachments")
+	viper.SetDefault("grpc.enabled", false)
+	viper.SetDefault("grpc.host", "0.0.0.0")
+	viper.SetDefault("grpc.port", 9090)
+	viper.SetDefault("webauthn.rp_id", "localhost")
+	viper.SetDefault("webauthn.rp_display_name", "VaultHub")
+	viper.SetDefault("webauthn.rp_origins", []string{"http://localhost:8080"})
+	viper.SetDefault("webauthn.attestation", "none")
+	viper.SetDefault("oidc.enabled", false)
+	viper.SetDefault("oidc.scopes", []string{"openid", "profile", "email"})
+	viper.SetDefault("oidc.username_claim", "preferred_username")
+	viper.SetDefault("oidc.default_role", "user")
+	viper.SetDefault("oidc.auto_provision", true)
+	viper.SetDefault("ldap.enabled", false)
+	viper.SetDefault("ldap.timeout_seconds", 10)
+	viper.SetDefault("ldap.user_filter", "(uid={username})")
+	viper.SetDefault("ldap.username_attribute", "uid")
+	viper.SetDefault("ldap.email_attribute", "mail")
+	viper.SetDefault("ldap.name_attribute", "cn")
+	viper.SetDefault("ldap.group_filter", "(member={dn})")
+	viper.SetDefault("ldap.group_name_attribute", "cn")
+	viper.SetDefault("ldap.default_role", "user")
+	viper.SetDefault("ldap.auto_provision", true)
+	viper.SetDefault("ldap.link_by_email", false)
+	viper.SetDefault("ldap.sync_interval_minutes", 60)
 }
 
 func setupEnvBinding() {
@@ -189,11 +295,13 @@ func setupEnvBinding() {
 		{"redis.sentinel_password", "REDIS_SENTINEL_PASSWORD"},
 		{"redis.addrs", "REDIS_ADDRS"},
 		{"security.jwt_secret", "SECURITY_JWT_SECRET"},
-		{"security.jwt_expiration", "SECURITY_JWT_EXPIRATION"},
+		{"security.access_token_expiration", "SECURITY_ACCESS_TOKEN_EXPIRATION"},
+		{"security.refresh_token_expiration", "SECURITY_REFRESH_TOKEN_EXPIRATION"},
 		{"security.encryption_key", "SECURITY_ENCRYPTION_KEY"},
 		{"security.casbin_model_path", "SECURITY_CASBIN_MODEL_PATH"},
 		{"security.admin_username", "SECURITY_ADMIN_USERNAME"},
 		{"security.admin_password", "SECURITY_ADMIN_PASSWORD"},
+		{"security.breached_passwords_path", "SECURITY_BREACHED_PASSWORDS_PATH"},
 		{"logger.level", "LOGGER_LEVEL"},
 		{"logger.encoding", "LOGGER_ENCODING"},
 		{"logger.output_paths", "LOGGER_OUTPUT_PATHS"},
@@ -201,6 +309,47 @@ func setupEnvBinding() {
 		{"audit.buffer_size", "AUDIT_BUFFER_SIZE"},
 		{"audit.worker_count", "AUDIT_WORKER_COUNT"},
 		{"audit.max_detail_size", "AUDIT_MAX_DETAIL_SIZE"},
+		{"storage.type", "STORAGE_TYPE"},
+		{"storage.path", "STORAGE_PATH"},
+		{"grpc.enabled", "GRPC_ENABLED"},
+		{"grpc.host", "GRPC_HOST"},
+		{"grpc.port", "GRPC_PORT"},
+		{"webauthn.rp_id", "WEBAUTHN_RP_ID"},
+		{"webauthn.rp_display_name", "WEBAUTHN_RP_DISPLAY_NAME"},
+		{"webauthn.rp_origins", "WEBAUTHN_RP_ORIGINS"},
+		{"webauthn.attestation", "WEBAUTHN_ATTESTATION"},
+		{"oidc.enabled", "OIDC_ENABLED"},
+		{"oidc.issuer", "OIDC_ISSUER"},
+		{"oidc.client_id", "OIDC_CLIENT_ID"},
+		{"oidc.client_secret", "OIDC_CLIENT_SECRET"},
+		{"oidc.redirect_url", "OIDC_REDIRECT_URL"},
+		{"oidc.scopes", "OIDC_SCOPES"},
+		{"oidc.username_claim", "OIDC_USERNAME_CLAIM"},
+		{"oidc.role_claim", "OIDC_ROLE_CLAIM"},
+		{"oidc.role_mappings", "OIDC_ROLE_MAPPINGS"},
+		{"oidc.default_role", "OIDC_DEFAULT_ROLE"},
+		{"oidc.auto_provision", "OIDC_AUTO_PROVISION"},
+		{"ldap.enabled", "LDAP_ENABLED"},
+		{"ldap.url", "LDAP_URL"},
+		{"ldap.start_tls", "LDAP_START_TLS"},
+		{"ldap.ca_file", "LDAP_CA_FILE"},
+		{"ldap.insecure_skip_verify", "LDAP_INSECURE_SKIP_VERIFY"},
+		{"ldap.timeout_seconds", "LDAP_TIMEOUT_SECONDS"},
+		{"ldap.bind_dn", "LDAP_BIND_DN"},
+		{"ldap.bind_password", "LDAP_BIND_PASSWORD"},
+		{"ldap.user_base_dn", "LDAP_USER_BASE_DN"},
+		{"ldap.user_filter", "LDAP_USER_FILTER"},
+		{"ldap.username_attribute", "LDAP_USERNAME_ATTRIBUTE"},
+		{"ldap.email_attribute", "LDAP_EMAIL_ATTRIBUTE"},
+		{"ldap.name_attribute", "LDAP_NAME_ATTRIBUTE"},
+		{"ldap.group_base_dn", "LDAP_GROUP_BASE_DN"},
+		{"ldap.group_filter", "LDAP_GROUP_FILTER"},
+		{"ldap.group_name_attribute", "LDAP_GROUP_NAME_ATTRIBUTE"},
+		{"ldap.role_mappings", "LDAP_ROLE_MAPPINGS"},
+		{"ldap.default_role", "LDAP_DEFAULT_ROLE"},
+		{"ldap.auto_provision", "LDAP_AUTO_PROVISION"},
+		{"ldap.link_by_email", "LDAP_LINK_BY_EMAIL"},
+		{"ldap.sync_interval_minutes", "LDAP_SYNC_INTERVAL_MINUTES"},
 	}
 
 	// 注意：此阶段使用标准库fmt而非项目logger，避免循环依赖
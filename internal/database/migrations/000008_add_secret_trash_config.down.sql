@@ -0,0 +1,2 @@
+-- 删除回收站保留期配置
+DELETE FROM system_config WHERE config_key = 'secret_trash_retention_days';
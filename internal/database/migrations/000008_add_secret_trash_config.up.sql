@@ -0,0 +1,3 @@
+-- 回收站保留期配置：已删除的秘密超过保留天数后由定时任务彻底清除（0表示不自动清除）
+INSERT IGNORE INTO system_config (config_key, config_value, description)
+VALUES ('secret_trash_retention_days', '30', '已删除秘密在回收站中的保留天数，超过后彻底清除（0表示不自动清除）');
@@ -0,0 +1,5 @@
+-- 删除附件容量配置
+DELETE FROM system_config WHERE config_key IN ('attachment_max_file_size_mb', 'attachment_user_quota_mb');
+
+-- 删除秘密附件表
+DROP TABLE IF EXISTS secret_attachments;
@@ -0,0 +1,40 @@
+-- 创建秘密附件表
+-- 附件内容分块加密后保存在附件存储（storage配置）中，本表只保存元数据和加密后的文件密钥
+CREATE TABLE IF NOT EXISTS secret_attachments (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+
+    -- 所属用户、秘密和唯一标识
+    user_uuid CHAR(36) NOT NULL COMMENT '所属用户UUID',
+    secret_uuid CHAR(36) NOT NULL COMMENT '所属秘密UUID',
+    attachment_uuid CHAR(36) NOT NULL UNIQUE COMMENT '附件的唯一标识（对外暴露）',
+
+    -- 文件信息
+    file_name VARCHAR(255) NOT NULL COMMENT '原始文件名',
+    content_type VARCHAR(128) NOT NULL COMMENT '文件MIME类型',
+    size BIGINT NOT NULL COMMENT '明文大小（字节），用于容量统计',
+    checksum_sha256 CHAR(64) NOT NULL COMMENT '明文SHA-256校验和',
+
+    -- 存储信息
+    storage_key VARCHAR(255) NOT NULL COMMENT '附件存储中的对象键',
+    stored_size BIGINT NOT NULL COMMENT '密文大小（字节）',
+    chunk_size INT NOT NULL COMMENT '分块加密的块大小（字节）',
+
+    -- 文件密钥（使用DEK加密）
+    encrypted_file_key VARBINARY(32) NOT NULL COMMENT '使用DEK加密的文件密钥',
+    dek_version INT NOT NULL COMMENT '加密文件密钥时使用的DEK版本',
+    key_nonce BINARY(12) NOT NULL COMMENT '加密文件密钥的Nonce（12字节）',
+    key_auth_tag BINARY(16) NOT NULL COMMENT '加密文件密钥的认证标签（16字节）',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME NULL COMMENT '删除时间',
+
+    INDEX idx_secret_attachments_user_uuid (user_uuid),
+    INDEX idx_secret_attachments_secret_uuid (secret_uuid),
+    INDEX idx_secret_attachments_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='秘密附件表';
+
+-- 附件容量配置
+INSERT IGNORE INTO system_config (config_key, config_value, description) VALUES
+('attachment_max_file_size_mb', '25', '单个附件大小上限（MB）'),
+('attachment_user_quota_mb', '100', '每个用户的附件总容量（MB），按明文大小统计');
@@ -0,0 +1,13 @@
+-- 删除检出管理权限
+DELETE FROM casbin_rule WHERE ptype = 'p' AND v0 = 'admin' AND v1 = 'checkout';
+
+-- 删除检出时长配置
+DELETE FROM system_config WHERE config_key IN ('secret_checkout_default_minutes', 'secret_checkout_max_minutes');
+
+-- 删除秘密检出记录表
+DROP TABLE IF EXISTS secret_checkouts;
+
+-- 删除秘密表的检出控制字段
+ALTER TABLE encrypted_secrets
+    DROP COLUMN rotation_required,
+    DROP COLUMN checkout_required;
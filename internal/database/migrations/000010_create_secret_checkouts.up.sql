@@ -0,0 +1,49 @@
+-- 秘密表增加检出控制字段
+ALTER TABLE encrypted_secrets
+    ADD COLUMN checkout_required TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否需要检出后才能访问' AFTER metadata,
+    ADD COLUMN rotation_required TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否待轮换（检入或超时后置位）' AFTER checkout_required;
+
+-- 创建秘密检出记录表
+-- 独占锁由Redis租约保证，本表记录检出历史和当前持有者
+CREATE TABLE IF NOT EXISTS secret_checkouts (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+
+    -- 检出标识和所属秘密
+    checkout_uuid CHAR(36) NOT NULL UNIQUE COMMENT '检出的唯一标识（检入时使用）',
+    secret_uuid CHAR(36) NOT NULL COMMENT '秘密UUID',
+    owner_uuid CHAR(36) NOT NULL COMMENT '秘密所属用户UUID',
+
+    -- 持有者信息
+    holder_uuid CHAR(36) NOT NULL COMMENT '持有者用户UUID',
+    holder_username VARCHAR(64) NOT NULL COMMENT '持有者用户名',
+    holder_ip VARCHAR(45) NULL COMMENT '检出时的客户端IP',
+    holder_user_agent VARCHAR(512) NULL COMMENT '检出时的客户端User-Agent',
+    reason VARCHAR(255) NULL COMMENT '检出原因',
+
+    -- 租约信息
+    status VARCHAR(16) NOT NULL COMMENT '状态：active/checked_in/expired/force_released',
+    checked_out_at DATETIME NOT NULL COMMENT '检出时间',
+    expires_at DATETIME NOT NULL COMMENT '租约到期时间',
+    released_at DATETIME NULL COMMENT '释放时间',
+    released_by VARCHAR(64) NULL COMMENT '释放者用户名（超时释放为system）',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME NULL COMMENT '删除时间',
+
+    INDEX idx_secret_checkouts_secret_uuid (secret_uuid),
+    INDEX idx_secret_checkouts_owner_uuid (owner_uuid),
+    INDEX idx_secret_checkouts_status (status),
+    INDEX idx_secret_checkouts_expires_at (expires_at),
+    INDEX idx_secret_checkouts_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='秘密检出记录表';
+
+-- 检出时长配置
+INSERT IGNORE INTO system_config (config_key, config_value, description) VALUES
+('secret_checkout_default_minutes', '60', '秘密检出默认时长（分钟）'),
+('secret_checkout_max_minutes', '480', '秘密检出最长时长（分钟）');
+
+-- 检出管理权限（管理员查看所有检出和强制释放）
+INSERT IGNORE INTO casbin_rule (ptype, v0, v1, v2) VALUES
+    ('p', 'admin', 'checkout', 'read'),
+    ('p', 'admin', 'checkout', 'write');
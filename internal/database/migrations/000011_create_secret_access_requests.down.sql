@@ -0,0 +1,11 @@
+-- 删除审批相关配置
+DELETE FROM system_config WHERE config_key IN ('secret_approval_approver_role', 'secret_approval_window_minutes', 'secret_approval_request_ttl_hours');
+
+-- 删除保险库审批人表
+DROP TABLE IF EXISTS vault_approvers;
+
+-- 删除秘密访问申请表
+DROP TABLE IF EXISTS secret_access_requests;
+
+-- 删除秘密表的审批控制字段
+ALTER TABLE encrypted_secrets DROP COLUMN approval_required;
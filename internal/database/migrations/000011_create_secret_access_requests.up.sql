@@ -0,0 +1,61 @@
+-- 秘密表增加审批控制字段（高敏感秘密）
+ALTER TABLE encrypted_secrets
+    ADD COLUMN approval_required TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否为高敏感秘密（解密前需要他人审批）' AFTER rotation_required;
+
+-- 创建秘密访问申请表
+-- 高敏感秘密解密前需要提交申请，由审批人批准后在限定时间窗口内可以解密
+CREATE TABLE IF NOT EXISTS secret_access_requests (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+
+    -- 申请标识和所属秘密
+    request_uuid CHAR(36) NOT NULL UNIQUE COMMENT '申请的唯一标识（对外暴露）',
+    secret_uuid CHAR(36) NOT NULL COMMENT '秘密UUID',
+    owner_uuid CHAR(36) NOT NULL COMMENT '秘密所属用户UUID',
+
+    -- 申请人信息
+    requester_uuid CHAR(36) NOT NULL COMMENT '申请人用户UUID',
+    requester_username VARCHAR(64) NOT NULL COMMENT '申请人用户名',
+    reason VARCHAR(500) NOT NULL COMMENT '申请原因',
+    window_minutes INT NOT NULL COMMENT '批准后可解密的时长（分钟）',
+
+    -- 审批信息
+    status VARCHAR(16) NOT NULL COMMENT '状态：pending/approved/denied/expired/cancelled',
+    approver_uuid CHAR(36) NULL COMMENT '审批人用户UUID',
+    approver_username VARCHAR(64) NULL COMMENT '审批人用户名',
+    decision_comment VARCHAR(500) NULL COMMENT '审批意见',
+    decided_at DATETIME NULL COMMENT '审批时间',
+    expires_at DATETIME NOT NULL COMMENT '申请的审批截止时间',
+    access_until DATETIME NULL COMMENT '批准后可解密的截止时间',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME NULL COMMENT '删除时间',
+
+    INDEX idx_secret_access_requests_secret_uuid (secret_uuid),
+    INDEX idx_secret_access_requests_owner_uuid (owner_uuid),
+    INDEX idx_secret_access_requests_requester_uuid (requester_uuid),
+    INDEX idx_secret_access_requests_status (status),
+    INDEX idx_secret_access_requests_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='秘密访问申请表';
+
+-- 创建保险库审批人表
+-- 用户可以为自己的保险库指定审批人；未指定时由系统配置的审批角色审批
+CREATE TABLE IF NOT EXISTS vault_approvers (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    owner_uuid CHAR(36) NOT NULL COMMENT '保险库所属用户UUID',
+    approver_uuid CHAR(36) NOT NULL COMMENT '审批人用户UUID',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME NULL COMMENT '删除时间',
+
+    UNIQUE KEY uk_vault_approvers_owner_approver (owner_uuid, approver_uuid),
+    INDEX idx_vault_approvers_approver_uuid (approver_uuid),
+    INDEX idx_vault_approvers_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='保险库审批人表';
+
+-- 审批相关配置
+INSERT IGNORE INTO system_config (config_key, config_value, description) VALUES
+('secret_approval_approver_role', 'admin', '未指定保险库审批人时，负责审批高敏感秘密访问申请的角色'),
+('secret_approval_window_minutes', '30', '访问申请批准后可解密的默认时长（分钟）'),
+('secret_approval_request_ttl_hours', '24', '访问申请的审批有效期（小时），超时未审批自动失效');
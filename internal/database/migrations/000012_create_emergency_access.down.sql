@@ -0,0 +1,10 @@
+-- 删除紧急访问相关配置
+DELETE FROM system_config WHERE config_key = 'emergency_access_default_wait_days';
+
+-- 删除紧急访问授权表
+DROP TABLE IF EXISTS emergency_access;
+
+-- 删除用户加密密钥表的紧急访问密钥对
+ALTER TABLE user_encryption_keys
+    DROP COLUMN encrypted_private_key,
+    DROP COLUMN public_key;
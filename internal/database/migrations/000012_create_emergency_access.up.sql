@@ -0,0 +1,45 @@
+-- 用户加密密钥表增加紧急访问密钥对
+-- 公钥明文保存，供授权人封装DEK；私钥由用户自己的DEK加密保存
+ALTER TABLE user_encryption_keys
+    ADD COLUMN public_key VARBINARY(32) NULL COMMENT 'X25519公钥（用于接收紧急访问授权）' AFTER encrypted_dek_recovery,
+    ADD COLUMN encrypted_private_key VARBINARY(128) NULL COMMENT 'DEK加密的X25519私钥' AFTER public_key;
+
+-- 创建紧急访问授权表
+-- 授权人指定可信联系人，授权时用联系人的公钥封装一份授权人的DEK
+-- 联系人发起申请后经过等待期（期间授权人可以拒绝）即可查看或接管授权人的保险库
+CREATE TABLE IF NOT EXISTS emergency_access (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    grant_uuid CHAR(36) NOT NULL UNIQUE COMMENT '授权的唯一标识（对外暴露）',
+
+    -- 授权双方
+    grantor_uuid CHAR(36) NOT NULL COMMENT '授权人用户UUID',
+    grantor_username VARCHAR(64) NOT NULL COMMENT '授权人用户名',
+    grantee_uuid CHAR(36) NOT NULL COMMENT '可信联系人用户UUID',
+    grantee_username VARCHAR(64) NOT NULL COMMENT '可信联系人用户名',
+
+    -- 授权内容
+    access_type VARCHAR(16) NOT NULL COMMENT '访问类型：view/takeover',
+    wait_days INT NOT NULL COMMENT '等待期（天）',
+    wrapped_dek VARBINARY(128) NOT NULL COMMENT '联系人公钥封装的授权人DEK',
+    dek_version INT NOT NULL COMMENT '封装的DEK版本',
+
+    -- 申请状态
+    status VARCHAR(16) NOT NULL COMMENT '状态：active/requested/granted',
+    requested_at DATETIME NULL COMMENT '联系人发起申请的时间',
+    available_at DATETIME NULL COMMENT '等待期结束时间',
+    granted_at DATETIME NULL COMMENT '获得访问权限的时间',
+    last_rejected_at DATETIME NULL COMMENT '授权人最近一次拒绝的时间',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME NULL COMMENT '删除时间',
+
+    UNIQUE KEY uk_emergency_access_grantor_grantee (grantor_uuid, grantee_uuid),
+    INDEX idx_emergency_access_grantee_uuid (grantee_uuid),
+    INDEX idx_emergency_access_status (status),
+    INDEX idx_emergency_access_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='紧急访问授权表';
+
+-- 紧急访问相关配置
+INSERT IGNORE INTO system_config (config_key, config_value, description) VALUES
+('emergency_access_default_wait_days', '7', '紧急访问的默认等待期（天），等待期内授权人可以拒绝申请');
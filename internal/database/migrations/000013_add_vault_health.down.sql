@@ -0,0 +1,8 @@
+-- 删除保险库健康检查相关配置
+DELETE FROM system_config WHERE config_key = 'vault_health_stale_days';
+
+-- 删除秘密表的健康检查字段
+ALTER TABLE encrypted_secrets
+    DROP INDEX idx_encrypted_secrets_user_fingerprint,
+    DROP COLUMN value_changed_at,
+    DROP COLUMN password_fingerprint;
@@ -0,0 +1,13 @@
+-- 秘密表增加保险库健康检查字段
+-- password_fingerprint：密码类秘密明文的HMAC指纹（密钥由DEK派生），只用于检测重复密码，服务端无法由指纹还原明文
+-- value_changed_at：秘密值最后一次变更的时间（DEK轮换只更换密文，不算变更），存量数据以创建时间为准
+ALTER TABLE encrypted_secrets
+    ADD COLUMN password_fingerprint CHAR(64) DEFAULT NULL COMMENT '密码指纹（HMAC-SHA256，密钥由DEK派生，仅密码类秘密）' AFTER approval_required,
+    ADD COLUMN value_changed_at DATETIME DEFAULT NULL COMMENT '秘密值最后变更时间' AFTER password_fingerprint,
+    ADD INDEX idx_encrypted_secrets_user_fingerprint (user_uuid, password_fingerprint);
+
+UPDATE encrypted_secrets SET value_changed_at = created_at WHERE value_changed_at IS NULL;
+
+-- 保险库健康检查相关配置
+INSERT IGNORE INTO system_config (config_key, config_value, description) VALUES
+('vault_health_stale_days', '180', '保险库健康检查中超过多少天未变更的秘密视为过旧');
@@ -0,0 +1,8 @@
+-- 删除两步验证相关配置
+DELETE FROM system_config WHERE config_key IN ('mfa_required_roles', 'mfa_issuer');
+
+-- 删除两步验证备用码表
+DROP TABLE IF EXISTS mfa_backup_codes;
+
+-- 删除用户两步验证表
+DROP TABLE IF EXISTS user_mfa;
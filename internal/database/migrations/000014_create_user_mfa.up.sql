@@ -0,0 +1,37 @@
+-- 创建用户两步验证表
+-- TOTP密钥由服务端密钥（security.encryption_key派生）加密保存；绑定后用第一个验证码确认才启用
+CREATE TABLE IF NOT EXISTS user_mfa (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_uuid CHAR(36) NOT NULL UNIQUE COMMENT '用户UUID',
+    encrypted_secret VARBINARY(128) NOT NULL COMMENT '加密的TOTP密钥',
+    enabled TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否已启用',
+    enabled_at DATETIME NULL COMMENT '启用时间',
+    last_used_step BIGINT NOT NULL DEFAULT 0 COMMENT '最后一次通过校验的时间步（防重放）',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME NULL COMMENT '删除时间',
+
+    INDEX idx_user_mfa_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户两步验证表';
+
+-- 创建两步验证备用码表
+-- 备用码只保存bcrypt哈希，每个只能使用一次
+CREATE TABLE IF NOT EXISTS mfa_backup_codes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_uuid CHAR(36) NOT NULL COMMENT '用户UUID',
+    code_hash VARCHAR(255) NOT NULL COMMENT '备用码哈希',
+    used_at DATETIME NULL COMMENT '使用时间（NULL表示未使用）',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME NULL COMMENT '删除时间',
+
+    INDEX idx_mfa_backup_codes_user_uuid (user_uuid),
+    INDEX idx_mfa_backup_codes_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='两步验证备用码表';
+
+-- 两步验证相关配置
+INSERT IGNORE INTO system_config (config_key, config_value, description) VALUES
+('mfa_required_roles', '', '必须启用两步验证的角色（逗号分隔，如admin,user），为空表示不强制'),
+('mfa_issuer', 'VaultHub', '认证器应用中显示的签发方名称');
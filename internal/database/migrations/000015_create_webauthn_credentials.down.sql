@@ -0,0 +1,5 @@
+-- 删除通行密钥二次验证配置
+DELETE FROM system_config WHERE config_key = 'webauthn_step_up_roles';
+
+-- 删除WebAuthn凭证表
+DROP TABLE IF EXISTS webauthn_credentials;
@@ -0,0 +1,29 @@
+-- 创建WebAuthn凭证表（通行密钥）
+-- 只保存凭证公钥；凭证ID由认证器生成，最长1023字节
+CREATE TABLE IF NOT EXISTS webauthn_credentials (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    uuid CHAR(36) NOT NULL UNIQUE COMMENT '凭证UUID',
+    user_uuid CHAR(36) NOT NULL COMMENT '用户UUID',
+    name VARCHAR(64) NOT NULL COMMENT '凭证名称',
+    credential_id VARBINARY(1023) NOT NULL COMMENT '认证器生成的凭证ID',
+    public_key BLOB NOT NULL COMMENT 'COSE格式的凭证公钥',
+    attestation_type VARCHAR(32) NOT NULL DEFAULT '' COMMENT '注册时的证明格式',
+    transports VARCHAR(255) NOT NULL DEFAULT '' COMMENT '认证器支持的传输方式（逗号分隔）',
+    aaguid VARBINARY(16) NULL COMMENT '认证器型号标识',
+    sign_count INT UNSIGNED NOT NULL DEFAULT 0 COMMENT '签名计数',
+    backup_eligible TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否为可同步的通行密钥',
+    backup_state TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否已同步备份',
+    last_used_at DATETIME NULL COMMENT '最后使用时间',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME NULL COMMENT '删除时间',
+
+    UNIQUE INDEX idx_webauthn_credentials_credential_id (credential_id),
+    INDEX idx_webauthn_credentials_user_uuid (user_uuid),
+    INDEX idx_webauthn_credentials_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='WebAuthn凭证表';
+
+-- 通行密钥二次验证配置
+INSERT IGNORE INTO system_config (config_key, config_value, description) VALUES
+('webauthn_step_up_roles', '', '访问秘密前必须用通行密钥二次验证的角色（逗号分隔，如admin），为空表示不要求');
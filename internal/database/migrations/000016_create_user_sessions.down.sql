@@ -0,0 +1,5 @@
+-- 删除并发会话上限配置
+DELETE FROM system_config WHERE config_key = 'max_sessions_per_user';
+
+-- 删除用户会话表
+DROP TABLE IF EXISTS user_sessions;
@@ -0,0 +1,26 @@
+-- 创建用户会话表（多设备登录、刷新令牌）
+-- 只保存刷新令牌的SHA-256，访问令牌的有效性由Redis中的会话标记决定
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    uuid CHAR(36) NOT NULL UNIQUE COMMENT '会话UUID',
+    user_uuid CHAR(36) NOT NULL COMMENT '用户UUID',
+    device_label VARCHAR(64) NOT NULL DEFAULT '' COMMENT '设备名称',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '' COMMENT '最近一次登录或刷新时的IP',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '' COMMENT '客户端User-Agent',
+    refresh_token_hash CHAR(64) NOT NULL COMMENT '当前刷新令牌的SHA-256',
+    last_seen_at DATETIME NOT NULL COMMENT '最后活跃时间',
+    expires_at DATETIME NOT NULL COMMENT '刷新令牌过期时间',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME NULL COMMENT '删除时间',
+
+    UNIQUE INDEX idx_user_sessions_refresh_token_hash (refresh_token_hash),
+    INDEX idx_user_sessions_user_uuid (user_uuid),
+    INDEX idx_user_sessions_expires_at (expires_at),
+    INDEX idx_user_sessions_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户会话表';
+
+-- 并发会话上限配置
+INSERT IGNORE INTO system_config (config_key, config_value, description) VALUES
+('max_sessions_per_user', '0', '每个用户同时保持的登录会话上限，超出时注销最久未活跃的会话，0表示不限制');
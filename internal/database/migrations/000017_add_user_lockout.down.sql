@@ -0,0 +1,10 @@
+-- 删除登录失败锁定配置
+DELETE FROM system_config WHERE config_key IN (
+    'login_lockout_threshold',
+    'login_lockout_minutes',
+    'login_failure_window_minutes',
+    'login_ip_failure_threshold'
+);
+
+-- 删除自动锁定的解锁时间
+ALTER TABLE users DROP COLUMN locked_until;
@@ -0,0 +1,11 @@
+-- 用户表增加自动锁定的解锁时间
+-- 登录失败次数达到阈值时自动锁定；locked_until 为空的锁定（管理员锁定或未配置冷却时间）只能由管理员解锁
+ALTER TABLE users
+    ADD COLUMN locked_until DATETIME NULL COMMENT '自动锁定的解锁时间' AFTER last_login_at;
+
+-- 登录失败锁定配置
+INSERT IGNORE INTO system_config (config_key, config_value, description) VALUES
+('login_lockout_threshold', '10', '同一账户在统计窗口内登录失败达到该次数后自动锁定，0表示不锁定'),
+('login_lockout_minutes', '30', '自动锁定的冷却时间（分钟），到期自动解锁，0表示必须由管理员解锁'),
+('login_failure_window_minutes', '15', '登录失败次数的统计窗口（分钟）'),
+('login_ip_failure_threshold', '50', '同一IP在统计窗口内登录失败超过该次数后，该IP的每次登录尝试都需要等待递增的时间');
@@ -0,0 +1,4 @@
+-- 删除令牌版本
+ALTER TABLE user_sessions DROP COLUMN token_epoch;
+
+ALTER TABLE users DROP COLUMN token_epoch;
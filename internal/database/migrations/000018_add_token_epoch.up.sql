@@ -0,0 +1,7 @@
+-- 用户表和会话表增加令牌版本
+-- 修改密码、角色或状态时递增用户的令牌版本，令牌和会话中的版本与用户不一致时立即失效
+ALTER TABLE users
+    ADD COLUMN token_epoch INT UNSIGNED NOT NULL DEFAULT 0 COMMENT '令牌版本，递增后此前签发的令牌全部失效' AFTER locked_until;
+
+ALTER TABLE user_sessions
+    ADD COLUMN token_epoch INT UNSIGNED NOT NULL DEFAULT 0 COMMENT '创建会话时用户的令牌版本' AFTER refresh_token_hash;
@@ -0,0 +1,15 @@
+-- 删除密码策略配置
+DELETE FROM system_config WHERE config_key IN (
+    'password_min_length',
+    'password_min_char_classes',
+    'password_banned_words',
+    'password_disallow_user_info',
+    'password_history_count',
+    'password_max_age_days'
+);
+
+-- 删除密码历史表
+DROP TABLE IF EXISTS password_history;
+
+-- 删除密码修改时间
+ALTER TABLE users DROP COLUMN password_changed_at;
@@ -0,0 +1,27 @@
+-- 用户表增加密码修改时间，用于密码最长使用期限
+ALTER TABLE users
+    ADD COLUMN password_changed_at DATETIME NULL COMMENT '最近一次修改登录密码的时间，为空时以创建时间为准' AFTER password_hash;
+
+-- 创建密码历史表
+-- 修改登录密码时保存旧密码的哈希，用于禁止重复使用最近用过的密码
+CREATE TABLE IF NOT EXISTS password_history (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_uuid CHAR(36) NOT NULL COMMENT '用户UUID',
+    password_hash VARCHAR(255) NOT NULL COMMENT '旧密码的bcrypt哈希',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME NULL COMMENT '删除时间',
+
+    INDEX idx_password_history_user_uuid (user_uuid),
+    INDEX idx_password_history_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='密码历史表';
+
+-- 密码策略配置
+INSERT IGNORE INTO system_config (config_key, config_value, description) VALUES
+('password_min_length', '8', '登录密码最小长度（字符数）'),
+('password_min_char_classes', '3', '登录密码至少包含大写字母、小写字母、数字、特殊字符中的几种（1-4）'),
+('password_banned_words', '', '登录密码中禁止出现的词（逗号分隔，不区分大小写），如公司名称、产品名称'),
+('password_disallow_user_info', 'true', '登录密码是否禁止包含用户名或邮箱前缀'),
+('password_history_count', '5', '最近几次使用过的登录密码（含当前密码）不能再次使用，0表示不限制'),
+('password_max_age_days', '0', '登录密码最长使用天数，超过后下次登录时必须修改，0表示不限制');
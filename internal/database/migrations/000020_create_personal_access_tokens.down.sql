@@ -0,0 +1,8 @@
+-- 删除个人访问令牌配置
+DELETE FROM system_config WHERE config_key IN (
+    'personal_access_token_max_days',
+    'personal_access_token_max_per_user'
+);
+
+-- 删除个人访问令牌表
+DROP TABLE IF EXISTS personal_access_tokens;
@@ -0,0 +1,29 @@
+-- 创建个人访问令牌表
+-- 供CI任务和脚本使用，令牌只在创建时返回一次，数据库只保存SHA-256
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    uuid CHAR(36) NOT NULL UNIQUE COMMENT '令牌UUID',
+    user_uuid CHAR(36) NOT NULL COMMENT '创建者UUID',
+    name VARCHAR(64) NOT NULL COMMENT '令牌名称',
+    token_hash CHAR(64) NOT NULL COMMENT '令牌的SHA-256',
+    token_prefix VARCHAR(16) NOT NULL COMMENT '令牌前几位，用于在列表中辨认',
+    scopes JSON NOT NULL COMMENT '权限范围，如 ["secret:read"]',
+    allowed_ips JSON NULL COMMENT '允许使用的IP或CIDR，为空时不限制',
+    token_epoch INT UNSIGNED NOT NULL DEFAULT 0 COMMENT '创建时用户的令牌版本，与用户不一致时令牌失效',
+    expires_at DATETIME NOT NULL COMMENT '过期时间',
+    last_used_at DATETIME NULL COMMENT '最后使用时间',
+    last_used_ip VARCHAR(45) NOT NULL DEFAULT '' COMMENT '最后使用的IP',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME NULL COMMENT '删除时间',
+
+    UNIQUE INDEX idx_personal_access_tokens_token_hash (token_hash),
+    INDEX idx_personal_access_tokens_user_uuid (user_uuid),
+    INDEX idx_personal_access_tokens_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='个人访问令牌表';
+
+-- 个人访问令牌配置
+INSERT IGNORE INTO system_config (config_key, config_value, description) VALUES
+('personal_access_token_max_days', '365', '个人访问令牌的最长有效期（天）'),
+('personal_access_token_max_per_user', '20', '每个用户最多可创建的个人访问令牌数量，0表示不限制');
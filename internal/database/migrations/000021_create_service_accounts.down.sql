@@ -0,0 +1,15 @@
+-- 删除服务账户相关配置
+DELETE FROM system_config WHERE config_key IN (
+    'service_account_token_ttl_minutes',
+    'service_account_secret_id_max_days',
+    'service_account_max_per_user'
+);
+
+-- 删除服务账户的Casbin权限（包括每个服务账户的分组和单独策略）
+DELETE FROM casbin_rule WHERE ptype = 'p' AND v1 = 'service_account';
+DELETE FROM casbin_rule WHERE v0 = 'machine' OR v0 LIKE 'sa:%';
+
+-- 删除服务账户相关表
+DROP TABLE IF EXISTS service_account_grants;
+DROP TABLE IF EXISTS service_account_secret_ids;
+DROP TABLE IF EXISTS service_accounts;
@@ -0,0 +1,83 @@
+-- 创建服务账户表
+-- 服务账户属于创建者的保险库，供应用程序以 role_id + secret_id 登录（AppRole），不需要邮箱验证和安全密码
+-- 创建时用服务账户的公钥封装一份保险库DEK；私钥由服务端密钥加密保存，解密被授权的秘密时使用
+CREATE TABLE IF NOT EXISTS service_accounts (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    uuid CHAR(36) NOT NULL UNIQUE COMMENT '服务账户UUID',
+    owner_uuid CHAR(36) NOT NULL COMMENT '所属保险库的用户UUID',
+    name VARCHAR(48) NOT NULL COMMENT '服务账户名称',
+    description VARCHAR(255) NOT NULL DEFAULT '' COMMENT '描述',
+    role_id CHAR(36) NOT NULL COMMENT '登录使用的role_id（不保密，相当于用户名）',
+
+    -- 保险库密钥
+    public_key VARBINARY(32) NOT NULL COMMENT 'X25519公钥',
+    encrypted_private_key VARBINARY(128) NOT NULL COMMENT '服务端密钥加密的X25519私钥',
+    wrapped_dek VARBINARY(128) NOT NULL COMMENT '服务账户公钥封装的保险库DEK',
+    dek_version INT NOT NULL COMMENT '封装的DEK版本',
+
+    last_login_at DATETIME NULL COMMENT '最后登录时间',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME NULL COMMENT '删除时间',
+
+    UNIQUE INDEX idx_service_accounts_role_id (role_id),
+    INDEX idx_service_accounts_owner_uuid (owner_uuid),
+    INDEX idx_service_accounts_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='服务账户表';
+
+-- 创建服务账户secret_id表
+-- secret_id只在生成时返回一次，数据库只保存SHA-256；可限制来源CIDR、使用次数和有效期
+CREATE TABLE IF NOT EXISTS service_account_secret_ids (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    uuid CHAR(36) NOT NULL UNIQUE COMMENT 'secret_id的访问标识（可公开，用于查询和撤销）',
+    service_account_uuid CHAR(36) NOT NULL COMMENT '服务账户UUID',
+    secret_id_hash CHAR(64) NOT NULL COMMENT 'secret_id的SHA-256',
+    bound_cidrs JSON NULL COMMENT '允许登录和使用令牌的IP或CIDR，为空时不限制',
+    max_uses INT NOT NULL DEFAULT 0 COMMENT '最多登录次数，0表示不限制',
+    use_count INT NOT NULL DEFAULT 0 COMMENT '已登录次数',
+    expires_at DATETIME NOT NULL COMMENT '过期时间',
+    last_used_at DATETIME NULL COMMENT '最后登录时间',
+    last_used_ip VARCHAR(45) NOT NULL DEFAULT '' COMMENT '最后登录的IP',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME NULL COMMENT '删除时间',
+
+    UNIQUE INDEX idx_service_account_secret_ids_hash (secret_id_hash),
+    INDEX idx_service_account_secret_ids_account (service_account_uuid),
+    INDEX idx_service_account_secret_ids_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='服务账户secret_id表';
+
+-- 创建服务账户秘密授权表
+-- 服务账户只能访问在此表中明确授权的秘密，撤销授权直接删除记录
+CREATE TABLE IF NOT EXISTS service_account_grants (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    service_account_uuid CHAR(36) NOT NULL COMMENT '服务账户UUID',
+    secret_uuid CHAR(36) NOT NULL COMMENT '被授权的秘密UUID',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME NULL COMMENT '删除时间',
+
+    UNIQUE INDEX uk_service_account_grants (service_account_uuid, secret_uuid),
+    INDEX idx_service_account_grants_secret_uuid (secret_uuid),
+    INDEX idx_service_account_grants_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='服务账户秘密授权表';
+
+-- 服务账户的Casbin权限
+-- 每个服务账户以 sa:<uuid> 作为Casbin主体，创建时归入machine角色；需要更细的控制时可直接为 sa:<uuid> 增删策略
+INSERT IGNORE INTO casbin_rule (ptype, v0, v1, v2) VALUES
+    ('p', 'machine', 'secret', 'read');
+
+-- 管理服务账户的权限（admin通过通配符拥有）
+INSERT IGNORE INTO casbin_rule (ptype, v0, v1, v2) VALUES
+    ('p', 'user', 'service_account', 'read'),
+    ('p', 'user', 'service_account', 'write'),
+    ('p', 'readonly', 'service_account', 'read');
+
+-- 服务账户相关配置
+INSERT IGNORE INTO system_config (config_key, config_value, description) VALUES
+('service_account_token_ttl_minutes', '60', '服务账户登录后令牌的有效期（分钟）'),
+('service_account_secret_id_max_days', '90', '服务账户secret_id的最长有效期（天）'),
+('service_account_max_per_user', '20', '每个用户最多可创建的服务账户数量，0表示不限制');
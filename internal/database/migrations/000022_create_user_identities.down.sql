@@ -0,0 +1,2 @@
+-- 删除外部身份关联表
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,19 @@
+-- 创建外部身份关联表
+-- 记录OIDC身份提供方的用户（issuer + sub）与VaultHub账户的关联，单点登录时据此找到账户
+CREATE TABLE IF NOT EXISTS user_identities (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    uuid CHAR(36) NOT NULL UNIQUE COMMENT '关联UUID',
+    user_uuid CHAR(36) NOT NULL COMMENT '关联的用户UUID',
+    issuer VARCHAR(255) NOT NULL COMMENT '身份提供方的issuer',
+    subject VARCHAR(255) NOT NULL COMMENT '身份提供方中的用户标识（sub声明）',
+    email VARCHAR(100) NOT NULL DEFAULT '' COMMENT '最近一次登录时身份提供方返回的邮箱',
+    last_login_at DATETIME NULL COMMENT '最后一次通过该身份登录的时间',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME NULL COMMENT '删除时间',
+
+    UNIQUE INDEX uk_user_identities_issuer_subject (issuer, subject),
+    INDEX idx_user_identities_user_uuid (user_uuid),
+    INDEX idx_user_identities_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='外部身份关联表';
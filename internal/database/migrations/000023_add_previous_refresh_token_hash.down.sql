@@ -0,0 +1,2 @@
+-- 删除上一个刷新令牌的哈希
+ALTER TABLE user_sessions DROP COLUMN previous_refresh_token_hash;
@@ -0,0 +1,4 @@
+-- 会话表保存上一个刷新令牌的哈希
+-- 只有出示已轮换的上一个刷新令牌才视为令牌泄露并注销会话，其他不匹配的令牌只拒绝请求
+ALTER TABLE user_sessions
+    ADD COLUMN previous_refresh_token_hash CHAR(64) NOT NULL DEFAULT '' COMMENT '上一个刷新令牌的SHA-256' AFTER refresh_token_hash;
@@ -0,0 +1,2 @@
+-- 删除个人访问令牌的秘密范围
+ALTER TABLE personal_access_tokens DROP COLUMN secret_uuids;
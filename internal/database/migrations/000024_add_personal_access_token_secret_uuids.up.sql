@@ -0,0 +1,4 @@
+-- 个人访问令牌增加秘密范围
+-- 设置后令牌只能访问列出的秘密（如CI任务只读取一个秘密），为空时可以访问创建者的全部秘密
+ALTER TABLE personal_access_tokens
+    ADD COLUMN secret_uuids JSON NULL COMMENT '允许访问的秘密UUID，为空时不限制' AFTER scopes;
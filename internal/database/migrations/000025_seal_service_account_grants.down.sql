@@ -0,0 +1,7 @@
+-- 回滚后封装的DEK无法恢复，服务账户需要重新创建
+ALTER TABLE service_accounts
+    ADD COLUMN wrapped_dek VARBINARY(128) NOT NULL DEFAULT '' COMMENT '服务账户公钥封装的保险库DEK' AFTER encrypted_private_key,
+    ADD COLUMN dek_version INT NOT NULL DEFAULT 0 COMMENT '封装的DEK版本' AFTER wrapped_dek;
+
+ALTER TABLE service_account_grants
+    DROP COLUMN sealed_data;
@@ -0,0 +1,11 @@
+-- 服务账户不再持有保险库DEK，改为按授权保存被授权秘密的明文副本（用服务账户公钥封装）
+-- 服务端密钥只能解开服务账户私钥，泄露时最多暴露被授权的秘密，而不是整个保险库
+ALTER TABLE service_account_grants
+    ADD COLUMN sealed_data MEDIUMBLOB NULL COMMENT '服务账户公钥封装的秘密明文' AFTER secret_uuid;
+
+-- 旧授权没有封装的明文，无法再解密，需要创建者重新授权（需要安全密码）
+DELETE FROM service_account_grants WHERE sealed_data IS NULL;
+
+ALTER TABLE service_accounts
+    DROP COLUMN wrapped_dek,
+    DROP COLUMN dek_version;
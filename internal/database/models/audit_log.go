@@ -17,6 +17,42 @@ const (
 	ActionAccess ActionType = "ACCESS"
 	ActionLogin  ActionType = "LOGIN"
 	ActionLogout ActionType = "LOGOUT"
+	ActionImport ActionType = "IMPORT"
+	ActionExport ActionType = "EXPORT"
+
+	// 秘密检出相关操作
+	ActionCheckout     ActionType = "CHECKOUT"
+	ActionCheckin      ActionType = "CHECKIN"
+	ActionForceRelease ActionType = "FORCE_RELEASE"
+
+	// 访问申请审批相关操作
+	ActionApprove ActionType = "APPROVE"
+	ActionDeny    ActionType = "DENY"
+
+	// 紧急访问相关操作
+	ActionEmergencyRequest  ActionType = "EMERGENCY_REQUEST"
+	ActionEmergencyTakeover ActionType = "EMERGENCY_TAKEOVER"
+
+	// 两步验证相关操作
+	ActionMFAEnable  ActionType = "MFA_ENABLE"
+	ActionMFADisable ActionType = "MFA_DISABLE"
+	ActionMFAReset   ActionType = "MFA_RESET"
+
+	// 通行密钥相关操作
+	ActionWebAuthnRegister ActionType = "WEBAUTHN_REGISTER"
+	ActionWebAuthnRemove   ActionType = "WEBAUTHN_REMOVE"
+	ActionWebAuthnReset    ActionType = "WEBAUTHN_RESET"
+	ActionStepUp           ActionType = "STEP_UP"
+
+	// 登录会话相关操作
+	ActionTokenRefresh  ActionType = "TOKEN_REFRESH"
+	ActionSessionRevoke ActionType = "SESSION_REVOKE"
+
+	// 登录密码相关操作
+	ActionPasswordChange ActionType = "PASSWORD_CHANGE"
+
+	// 服务账户相关操作
+	ActionGrant ActionType = "GRANT" // 修改服务账户可访问的秘密
 )
 
 // ResourceType 资源类型
@@ -27,6 +63,12 @@ const (
 	ResourceSecret ResourceType = "secret"
 	ResourceUser   ResourceType = "user"
 	ResourceConfig ResourceType = "config"
+	ResourceShare  ResourceType = "share"
+
+	ResourceAccessRequest   ResourceType = "access_request"
+	ResourceEmergencyAccess ResourceType = "emergency_access"
+	ResourceAccessToken     ResourceType = "access_token"
+	ResourceServiceAccount  ResourceType = "service_account"
 )
 
 // AuditStatus 审计状态
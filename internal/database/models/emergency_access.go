@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// EmergencyAccessType 紧急访问类型
+type EmergencyAccessType string
+
+const (
+	EmergencyAccessView     EmergencyAccessType = "view"     // 查看授权人的秘密
+	EmergencyAccessTakeover EmergencyAccessType = "takeover" // 重置授权人的登录密码和安全密码，接管账户
+)
+
+// EmergencyAccessStatus 紧急访问状态
+type EmergencyAccessStatus string
+
+const (
+	EmergencyAccessActive    EmergencyAccessStatus = "active"    // 已指定，联系人未发起申请
+	EmergencyAccessRequested EmergencyAccessStatus = "requested" // 联系人已发起申请，处于等待期
+	EmergencyAccessGranted   EmergencyAccessStatus = "granted"   // 等待期结束或授权人提前同意，联系人已获得访问权限
+)
+
+// EmergencyAccess 紧急访问授权
+// 授权时用联系人的公钥封装一份授权人的DEK（WrappedDEK），服务端无法解开；
+// 联系人获得访问权限后用自己的安全密码解开私钥，再解开授权人的DEK
+type EmergencyAccess struct {
+	BaseModel
+	GrantUUID string `gorm:"type:char(36);uniqueIndex;not null" json:"grant_uuid"`
+
+	// 授权双方
+	GrantorUUID     string `gorm:"type:char(36);not null;uniqueIndex:uk_emergency_access_grantor_grantee" json:"grantor_uuid"`
+	GrantorUsername string `gorm:"type:varchar(64);not null" json:"grantor_username"`
+	GranteeUUID     string `gorm:"type:char(36);not null;uniqueIndex:uk_emergency_access_grantor_grantee;index" json:"grantee_uuid"`
+	GranteeUsername string `gorm:"type:varchar(64);not null" json:"grantee_username"`
+
+	// 授权内容
+	AccessType EmergencyAccessType `gorm:"type:varchar(16);not null" json:"access_type"`
+	WaitDays   int                 `gorm:"not null" json:"wait_days"`
+	WrappedDEK []byte              `gorm:"column:wrapped_dek;type:varbinary(128);not null" json:"-"` // 封装的DEK不对外暴露
+	DEKVersion int                 `gorm:"column:dek_version;not null" json:"dek_version"`
+
+	// 申请状态
+	Status         EmergencyAccessStatus `gorm:"type:varchar(16);not null;index" json:"status"`
+	RequestedAt    *time.Time            `gorm:"type:datetime" json:"requested_at,omitempty"`
+	AvailableAt    *time.Time            `gorm:"type:datetime" json:"available_at,omitempty"`
+	GrantedAt      *time.Time            `gorm:"type:datetime" json:"granted_at,omitempty"`
+	LastRejectedAt *time.Time            `gorm:"type:datetime" json:"last_rejected_at,omitempty"`
+}
+
+// TableName 指定表名
+func (EmergencyAccess) TableName() string {
+	return "emergency_access"
+}
+
+// WaitingPeriodOver 判断等待期是否已经结束
+func (e *EmergencyAccess) WaitingPeriodOver() bool {
+	return e.Status == EmergencyAccessRequested && e.AvailableAt != nil && !time.Now().Before(*e.AvailableAt)
+}
@@ -19,10 +19,27 @@ const (
 	SecretTypeOther        SecretType = "other"         // 其他
 )
 
+// PasswordGenerator 密码生成规则
+// 关联了生成规则的秘密在检入时可以自动轮换为新生成的随机密码
+type PasswordGenerator struct {
+	Length  int  `json:"length" binding:"omitempty,min=12,max=128"` // 密码长度，默认24
+	Symbols bool `json:"symbols"`                                   // 是否包含特殊字符
+}
+
+// PasswordStrength 密码强度评分（写入时由服务端计算，客户端传入的值会被覆盖）
+type PasswordStrength struct {
+	Score    int     `json:"score"`              // 强度等级0-4，越高越强
+	Entropy  float64 `json:"entropy"`            // 估算熵值（bit）
+	Breached bool    `json:"breached,omitempty"` // 是否出现在泄露密码数据集中
+}
+
 // SecretMetadata 秘密元数据（存储为JSON）
 type SecretMetadata struct {
 	ExpiresAt *time.Time             `json:"expires_at,omitempty"` // 过期时间
 	Tags      []string               `json:"tags,omitempty"`       // 标签
+	Folder    string                 `json:"folder,omitempty"`     // 所属文件夹（多级用"/"分隔）
+	Generator *PasswordGenerator     `json:"generator,omitempty"`  // 密码生成规则（用于检入后自动轮换）
+	Strength  *PasswordStrength      `json:"strength,omitempty"`   // 密码强度（仅密码类秘密）
 	Extra     map[string]interface{} `json:"extra,omitempty"`      // 额外信息
 }
 
@@ -40,7 +57,7 @@ func (m *SecretMetadata) Scan(value interface{}) error {
 
 // Value 实现driver.Valuer接口，用于写入数据库
 func (m SecretMetadata) Value() (driver.Value, error) {
-	if m.ExpiresAt == nil && len(m.Tags) == 0 && len(m.Extra) == 0 {
+	if m.ExpiresAt == nil && len(m.Tags) == 0 && m.Folder == "" && m.Generator == nil && m.Strength == nil && len(m.Extra) == 0 {
 		return nil, nil
 	}
 	return json.Marshal(m)
@@ -67,6 +84,17 @@ type EncryptedSecret struct {
 	// 元数据
 	Metadata *SecretMetadata `gorm:"type:json" json:"metadata,omitempty"`
 
+	// 检出控制：需要检出的秘密只能通过检出接口独占访问，检入或超时后标记为待轮换
+	CheckoutRequired bool `gorm:"not null;default:false" json:"checkout_required"`
+	RotationRequired bool `gorm:"not null;default:false" json:"rotation_required"`
+
+	// 高敏感秘密：解密前需要他人审批访问申请
+	ApprovalRequired bool `gorm:"not null;default:false" json:"approval_required"`
+
+	// 保险库健康检查：密码指纹用于检测重复密码（密钥由DEK派生，不对外暴露）
+	PasswordFingerprint *string    `gorm:"type:char(64)" json:"-"`
+	ValueChangedAt      *time.Time `gorm:"type:datetime" json:"value_changed_at,omitempty"`
+
 	// 审计
 	LastAccessedAt *time.Time `gorm:"type:datetime" json:"last_accessed_at,omitempty"`
 	AccessCount    int64      `gorm:"default:0" json:"access_count"`
@@ -87,40 +115,49 @@ func (s *EncryptedSecret) IsExpired() bool {
 
 // SafeEncryptedSecret 用于返回给前端的安全信息（不包含加密数据）
 type SafeEncryptedSecret struct {
-	ID             uint            `json:"id"`
-	UserUUID       string          `json:"user_uuid"`
-	SecretUUID     string          `json:"secret_uuid"`
-	SecretName     string          `json:"secret_name"`
-	SecretType     SecretType      `json:"secret_type"`
-	Description    string          `json:"description,omitempty"`
-	DEKVersion     int             `json:"dek_version"`
-	Metadata       *SecretMetadata `json:"metadata,omitempty"`
-	LastAccessedAt *time.Time      `json:"last_accessed_at,omitempty"`
-	AccessCount    int64           `json:"access_count"`
-	CreatedAt      time.Time       `json:"created_at"`
-	UpdatedAt      time.Time       `json:"updated_at"`
+	ID               uint            `json:"id"`
+	UserUUID         string          `json:"user_uuid"`
+	SecretUUID       string          `json:"secret_uuid"`
+	SecretName       string          `json:"secret_name"`
+	SecretType       SecretType      `json:"secret_type"`
+	Description      string          `json:"description,omitempty"`
+	DEKVersion       int             `json:"dek_version"`
+	Metadata         *SecretMetadata `json:"metadata,omitempty"`
+	CheckoutRequired bool            `json:"checkout_required"`
+	RotationRequired bool            `json:"rotation_required"`
+	ApprovalRequired bool            `json:"approval_required"`
+	ValueChangedAt   *time.Time      `json:"value_changed_at,omitempty"`
+	LastAccessedAt   *time.Time      `json:"last_accessed_at,omitempty"`
+	AccessCount      int64           `json:"access_count"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
 }
 
 // ToSafe 转换为安全信息
 func (s *EncryptedSecret) ToSafe() *SafeEncryptedSecret {
 	return &SafeEncryptedSecret{
-		ID:             s.ID,
-		UserUUID:       s.UserUUID,
-		SecretUUID:     s.SecretUUID,
-		SecretName:     s.SecretName,
-		SecretType:     s.SecretType,
-		Description:    s.Description,
-		DEKVersion:     s.DEKVersion,
-		Metadata:       s.Metadata,
-		LastAccessedAt: s.LastAccessedAt,
-		AccessCount:    s.AccessCount,
-		CreatedAt:      s.CreatedAt,
-		UpdatedAt:      s.UpdatedAt,
+		ID:               s.ID,
+		UserUUID:         s.UserUUID,
+		SecretUUID:       s.SecretUUID,
+		SecretName:       s.SecretName,
+		SecretType:       s.SecretType,
+		Description:      s.Description,
+		DEKVersion:       s.DEKVersion,
+		Metadata:         s.Metadata,
+		CheckoutRequired: s.CheckoutRequired,
+		RotationRequired: s.RotationRequired,
+		ApprovalRequired: s.ApprovalRequired,
+		ValueChangedAt:   s.ValueChangedAt,
+		LastAccessedAt:   s.LastAccessedAt,
+		AccessCount:      s.AccessCount,
+		CreatedAt:        s.CreatedAt,
+		UpdatedAt:        s.UpdatedAt,
 	}
 }
 
 // DecryptedSecret 解密后的秘密（包含明文数据，仅用于API响应）
 type DecryptedSecret struct {
 	SafeEncryptedSecret
-	PlainData string `json:"plain_data"` // 解密后的明文数据
+	PlainData         string `json:"plain_data"`                    // 解密后的明文数据
+	AccessRequestUUID string `json:"access_request_uuid,omitempty"` // 高敏感秘密解密时依据的访问申请
 }
@@ -0,0 +1,14 @@
+package models
+
+// PasswordHistory 登录密码历史
+// 修改登录密码时保存旧密码的哈希，密码策略据此禁止重复使用最近用过的密码
+type PasswordHistory struct {
+	BaseModel
+	UserUUID     string `gorm:"type:char(36);not null;index" json:"user_uuid"`
+	PasswordHash string `gorm:"type:varchar(255);not null" json:"-"` // 旧密码的bcrypt哈希
+}
+
+// TableName 指定表名
+func (PasswordHistory) TableName() string {
+	return "password_history"
+}
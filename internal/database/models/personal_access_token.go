@@ -0,0 +1,96 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// StringList 字符串列表（存储为JSON数组）
+type StringList []string
+
+// Scan 实现sql.Scanner接口，用于从数据库读取
+func (l *StringList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return fmt.Errorf("无法将 %T 转换为字符串列表", value)
+	}
+}
+
+// Value 实现driver.Valuer接口，用于写入数据库
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// PersonalAccessToken 个人访问令牌
+// 供CI任务和脚本代替用户名密码访问API：权限不超过创建者角色的权限，且只能访问 Scopes 中列出的资源和操作；
+// 设置了 SecretUUIDs 时只能访问其中列出的秘密
+type PersonalAccessToken struct {
+	BaseModel
+	UUID        string     `gorm:"type:char(36);uniqueIndex;not null" json:"uuid"`
+	UserUUID    string     `gorm:"type:char(36);not null;index" json:"user_uuid"`
+	Name        string     `gorm:"type:varchar(64);not null" json:"name"`
+	TokenHash   string     `gorm:"type:char(64);uniqueIndex;not null" json:"-"`   // 令牌的SHA-256，令牌明文只在创建时返回一次
+	TokenPrefix string     `gorm:"type:varchar(16);not null" json:"token_prefix"` // 令牌前几位，用于在列表中辨认
+	Scopes      StringList `gorm:"type:json;not null" json:"scopes"`              // 权限范围，格式为 资源:操作，如 secret:read
+	SecretUUIDs StringList `gorm:"type:json" json:"secret_uuids,omitempty"`       // 允许访问的秘密，为空时可以访问创建者的全部秘密
+	AllowedIPs  StringList `gorm:"type:json" json:"allowed_ips"`                  // 允许使用的IP或CIDR，为空时不限制
+	TokenEpoch  uint       `gorm:"not null;default:0" json:"-"`                   // 创建时用户的令牌版本，与用户不一致时令牌失效
+	ExpiresAt   time.Time  `gorm:"type:datetime;not null" json:"expires_at"`
+	LastUsedAt  *time.Time `gorm:"type:datetime" json:"last_used_at,omitempty"`
+	LastUsedIP  string     `gorm:"type:varchar(45);not null;default:''" json:"last_used_ip,omitempty"`
+}
+
+// TableName 指定表名
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+// IsExpired 令牌是否已过期
+func (t *PersonalAccessToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// HasScope 令牌的权限范围是否包含资源的指定操作
+func (t *PersonalAccessToken) HasScope(resource, action string) bool {
+	scope := resource + ":" + action
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// RestrictsSecrets 令牌是否限定了可以访问的秘密
+func (t *PersonalAccessToken) RestrictsSecrets() bool {
+	return len(t.SecretUUIDs) > 0
+}
+
+// AllowsSecret 令牌是否可以访问指定的秘密，没有限定秘密范围时总是允许
+func (t *PersonalAccessToken) AllowsSecret(secretUUID string) bool {
+	if !t.RestrictsSecrets() {
+		return true
+	}
+	for _, s := range t.SecretUUIDs {
+		if s == secretUUID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// AccessRequestStatus 访问申请状态
+type AccessRequestStatus string
+
+const (
+	AccessRequestPending   AccessRequestStatus = "pending"   // 待审批
+	AccessRequestApproved  AccessRequestStatus = "approved"  // 已批准
+	AccessRequestDenied    AccessRequestStatus = "denied"    // 已拒绝
+	AccessRequestExpired   AccessRequestStatus = "expired"   // 超时未审批
+	AccessRequestCancelled AccessRequestStatus = "cancelled" // 申请人撤回
+)
+
+// SecretAccessRequest 高敏感秘密访问申请
+// 批准后申请人在 AccessUntil 之前可以解密该秘密
+type SecretAccessRequest struct {
+	BaseModel
+	RequestUUID string `gorm:"type:char(36);uniqueIndex;not null" json:"request_uuid"`
+	SecretUUID  string `gorm:"type:char(36);not null;index" json:"secret_uuid"`
+	OwnerUUID   string `gorm:"type:char(36);not null;index" json:"owner_uuid"`
+
+	// 申请人信息
+	RequesterUUID     string `gorm:"type:char(36);not null;index" json:"requester_uuid"`
+	RequesterUsername string `gorm:"type:varchar(64);not null" json:"requester_username"`
+	Reason            string `gorm:"type:varchar(500);not null" json:"reason"`
+	WindowMinutes     int    `gorm:"not null" json:"window_minutes"`
+
+	// 审批信息
+	Status           AccessRequestStatus `gorm:"type:varchar(16);not null;index" json:"status"`
+	ApproverUUID     string              `gorm:"type:char(36)" json:"approver_uuid,omitempty"`
+	ApproverUsername string              `gorm:"type:varchar(64)" json:"approver_username,omitempty"`
+	DecisionComment  string              `gorm:"type:varchar(500)" json:"decision_comment,omitempty"`
+	DecidedAt        *time.Time          `gorm:"type:datetime" json:"decided_at,omitempty"`
+	ExpiresAt        time.Time           `gorm:"type:datetime;not null" json:"expires_at"`
+	AccessUntil      *time.Time          `gorm:"type:datetime" json:"access_until,omitempty"`
+}
+
+// TableName 指定表名
+func (SecretAccessRequest) TableName() string {
+	return "secret_access_requests"
+}
+
+// IsPending 判断申请是否仍可审批
+func (r *SecretAccessRequest) IsPending() bool {
+	return r.Status == AccessRequestPending && time.Now().Before(r.ExpiresAt)
+}
+
+// VaultApprover 保险库审批人
+// 用户为自己的保险库指定的审批人；未指定时由系统配置的审批角色审批
+type VaultApprover struct {
+	BaseModel
+	OwnerUUID    string `gorm:"type:char(36);not null;uniqueIndex:uk_vault_approvers_owner_approver" json:"owner_uuid"`
+	ApproverUUID string `gorm:"type:char(36);not null;uniqueIndex:uk_vault_approvers_owner_approver;index" json:"approver_uuid"`
+}
+
+// TableName 指定表名
+func (VaultApprover) TableName() string {
+	return "vault_approvers"
+}
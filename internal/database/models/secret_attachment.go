@@ -0,0 +1,32 @@
+package models
+
+// SecretAttachment 秘密附件模型
+// 附件内容使用随机文件密钥分块加密后保存在附件存储中，文件密钥再用用户DEK加密后保存在本表
+type SecretAttachment struct {
+	BaseModel
+	UserUUID       string `gorm:"type:char(36);not null;index" json:"-"`
+	SecretUUID     string `gorm:"type:char(36);not null;index" json:"secret_uuid"`
+	AttachmentUUID string `gorm:"type:char(36);uniqueIndex;not null" json:"attachment_uuid"`
+
+	// 文件信息
+	FileName       string `gorm:"type:varchar(255);not null" json:"file_name"`
+	ContentType    string `gorm:"type:varchar(128);not null" json:"content_type"`
+	Size           int64  `gorm:"not null" json:"size"`
+	ChecksumSHA256 string `gorm:"column:checksum_sha256;type:char(64);not null" json:"checksum_sha256"`
+
+	// 存储信息（不对外暴露）
+	StorageKey string `gorm:"type:varchar(255);not null" json:"-"`
+	StoredSize int64  `gorm:"not null" json:"-"`
+	ChunkSize  int    `gorm:"not null" json:"-"`
+
+	// 加密的文件密钥（不对外暴露）
+	EncryptedFileKey []byte `gorm:"type:varbinary(32);not null" json:"-"`
+	DEKVersion       int    `gorm:"type:int;not null" json:"dek_version"`
+	KeyNonce         []byte `gorm:"type:binary(12);not null" json:"-"`
+	KeyAuthTag       []byte `gorm:"type:binary(16);not null" json:"-"`
+}
+
+// TableName 指定表名
+func (SecretAttachment) TableName() string {
+	return "secret_attachments"
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// CheckoutStatus 检出状态
+type CheckoutStatus string
+
+const (
+	CheckoutActive        CheckoutStatus = "active"         // 检出中
+	CheckoutCheckedIn     CheckoutStatus = "checked_in"     // 持有者已检入
+	CheckoutExpired       CheckoutStatus = "expired"        // 超时自动释放
+	CheckoutForceReleased CheckoutStatus = "force_released" // 管理员强制释放
+)
+
+// SecretCheckout 秘密检出记录
+// 独占锁由Redis中带过期时间的租约保证，本表记录检出历史和当前持有者
+type SecretCheckout struct {
+	BaseModel
+	CheckoutUUID string `gorm:"type:char(36);uniqueIndex;not null" json:"checkout_uuid"`
+	SecretUUID   string `gorm:"type:char(36);not null;index" json:"secret_uuid"`
+	OwnerUUID    string `gorm:"type:char(36);not null;index" json:"owner_uuid"`
+
+	// 持有者信息
+	HolderUUID      string `gorm:"type:char(36);not null" json:"holder_uuid"`
+	HolderUsername  string `gorm:"type:varchar(64);not null" json:"holder_username"`
+	HolderIP        string `gorm:"type:varchar(45)" json:"holder_ip,omitempty"`
+	HolderUserAgent string `gorm:"type:varchar(512)" json:"holder_user_agent,omitempty"`
+	Reason          string `gorm:"type:varchar(255)" json:"reason,omitempty"`
+
+	// 租约信息
+	Status       CheckoutStatus `gorm:"type:varchar(16);not null;index" json:"status"`
+	CheckedOutAt time.Time      `gorm:"type:datetime;not null" json:"checked_out_at"`
+	ExpiresAt    time.Time      `gorm:"type:datetime;not null;index" json:"expires_at"`
+	ReleasedAt   *time.Time     `gorm:"type:datetime" json:"released_at,omitempty"`
+	ReleasedBy   string         `gorm:"type:varchar(64)" json:"released_by,omitempty"` // 释放者用户名（超时释放为system）
+}
+
+// TableName 指定表名
+func (SecretCheckout) TableName() string {
+	return "secret_checkouts"
+}
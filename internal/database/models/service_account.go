@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// ServiceAccount 服务账户
+// 属于创建者（Owner）的保险库，应用程序以 RoleID + secret_id 登录，只能访问明确授权给它的秘密。
+// 服务账户不持有保险库DEK：授权时用服务账户的公钥封装被授权秘密的明文（见ServiceAccountGrant），
+// 私钥由服务端密钥加密保存，因此解密时不需要安全密码，服务端密钥泄露时也只会暴露被授权的秘密
+type ServiceAccount struct {
+	BaseModel
+	UUID        string `gorm:"type:char(36);uniqueIndex;not null" json:"uuid"`
+	OwnerUUID   string `gorm:"type:char(36);not null;index" json:"owner_uuid"`
+	Name        string `gorm:"type:varchar(48);not null" json:"name"`
+	Description string `gorm:"type:varchar(255);not null;default:''" json:"description"`
+	RoleID      string `gorm:"type:char(36);uniqueIndex;not null" json:"role_id"` // 登录标识，不保密
+
+	// 服务账户密钥
+	PublicKey           []byte `gorm:"type:varbinary(32);not null" json:"-"`
+	EncryptedPrivateKey []byte `gorm:"type:varbinary(128);not null" json:"-"` // 服务端密钥加密的私钥不对外暴露
+
+	LastLoginAt *time.Time `gorm:"type:datetime" json:"last_login_at,omitempty"`
+}
+
+// TableName 指定表名
+func (ServiceAccount) TableName() string {
+	return "service_accounts"
+}
+
+// CasbinSubject 服务账户在Casbin中的主体名
+func (a *ServiceAccount) CasbinSubject() string {
+	return "sa:" + a.UUID
+}
+
+// ServiceAccountSecretID 服务账户的secret_id
+// secret_id只在生成时返回一次，数据库只保存SHA-256
+type ServiceAccountSecretID struct {
+	BaseModel
+	UUID               string     `gorm:"type:char(36);uniqueIndex;not null" json:"uuid"` // 访问标识，用于查询和撤销
+	ServiceAccountUUID string     `gorm:"type:char(36);not null;index" json:"service_account_uuid"`
+	SecretIDHash       string     `gorm:"type:char(64);uniqueIndex;not null" json:"-"`
+	BoundCIDRs         StringList `gorm:"column:bound_cidrs;type:json" json:"bound_cidrs"` // 允许登录和使用令牌的IP或CIDR，为空时不限制
+	MaxUses            int        `gorm:"not null;default:0" json:"max_uses"`              // 最多登录次数，0表示不限制
+	UseCount           int        `gorm:"not null;default:0" json:"use_count"`
+	ExpiresAt          time.Time  `gorm:"type:datetime;not null" json:"expires_at"`
+	LastUsedAt         *time.Time `gorm:"type:datetime" json:"last_used_at,omitempty"`
+	LastUsedIP         string     `gorm:"type:varchar(45);not null;default:''" json:"last_used_ip,omitempty"`
+}
+
+// TableName 指定表名
+func (ServiceAccountSecretID) TableName() string {
+	return "service_account_secret_ids"
+}
+
+// IsExpired secret_id是否已过期
+func (s *ServiceAccountSecretID) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
+
+// UsesExhausted secret_id的登录次数是否已用完
+func (s *ServiceAccountSecretID) UsesExhausted() bool {
+	return s.MaxUses > 0 && s.UseCount >= s.MaxUses
+}
+
+// ServiceAccountGrant 服务账户可以访问的秘密
+// SealedData 是用服务账户公钥封装的秘密明文，秘密的明文变化时随之重新封装
+type ServiceAccountGrant struct {
+	BaseModel
+	ServiceAccountUUID string `gorm:"type:char(36);not null;uniqueIndex:uk_service_account_grants" json:"service_account_uuid"`
+	SecretUUID         string `gorm:"type:char(36);not null;uniqueIndex:uk_service_account_grants;index" json:"secret_uuid"`
+	SealedData         []byte `gorm:"type:mediumblob" json:"-"` // 封装的明文不对外暴露
+}
+
+// TableName 指定表名
+func (ServiceAccountGrant) TableName() string {
+	return "service_account_grants"
+}
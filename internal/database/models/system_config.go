@@ -38,6 +38,61 @@ const (
 	ConfigKeyEmailSMTPUseTLS   = "email_smtp_use_tls"   // 是否使用TLS加密
 	ConfigKeyEmailCodeExpiry   = "email_code_expiry"    // 验证码有效期（秒）
 	ConfigKeyEmailRateLimit    = "email_rate_limit"     // 邮件发送频率限制（秒）
+
+	// 回收站相关配置
+	ConfigKeySecretTrashRetentionDays = "secret_trash_retention_days" // 已删除秘密在回收站中的保留天数（0表示不自动清除）
+
+	// 附件相关配置
+	ConfigKeyAttachmentMaxFileSizeMB = "attachment_max_file_size_mb" // 单个附件大小上限（MB）
+	ConfigKeyAttachmentUserQuotaMB   = "attachment_user_quota_mb"    // 每个用户的附件总容量（MB）
+
+	// 秘密检出相关配置
+	ConfigKeySecretCheckoutDefaultMinutes = "secret_checkout_default_minutes" // 检出默认时长（分钟）
+	ConfigKeySecretCheckoutMaxMinutes     = "secret_checkout_max_minutes"     // 检出最长时长（分钟）
+
+	// 高敏感秘密审批相关配置
+	ConfigKeySecretApprovalApproverRole    = "secret_approval_approver_role"     // 未指定保险库审批人时的审批角色
+	ConfigKeySecretApprovalWindowMinutes   = "secret_approval_window_minutes"    // 批准后可解密的默认时长（分钟）
+	ConfigKeySecretApprovalRequestTTLHours = "secret_approval_request_ttl_hours" // 访问申请的审批有效期（小时）
+
+	// 紧急访问相关配置
+	ConfigKeyEmergencyAccessDefaultWaitDays = "emergency_access_default_wait_days" // 紧急访问默认等待期（天）
+
+	// 保险库健康检查相关配置
+	ConfigKeyVaultHealthStaleDays = "vault_health_stale_days" // 超过多少天未变更的秘密视为过旧
+
+	// 两步验证相关配置
+	ConfigKeyMFARequiredRoles = "mfa_required_roles" // 必须启用两步验证的角色（逗号分隔）
+	ConfigKeyMFAIssuer        = "mfa_issuer"         // 认证器应用中显示的签发方名称
+
+	// 通行密钥相关配置
+	ConfigKeyWebAuthnStepUpRoles = "webauthn_step_up_roles" // 访问秘密前必须用通行密钥二次验证的角色（逗号分隔）
+
+	// 登录会话相关配置
+	ConfigKeyMaxSessionsPerUser = "max_sessions_per_user" // 每个用户同时保持的登录会话上限（0表示不限制）
+
+	// 登录失败锁定相关配置
+	ConfigKeyLoginLockoutThreshold     = "login_lockout_threshold"      // 同一账户登录失败达到该次数后自动锁定（0表示不锁定）
+	ConfigKeyLoginLockoutMinutes       = "login_lockout_minutes"        // 自动锁定的冷却时间（分钟，0表示必须由管理员解锁）
+	ConfigKeyLoginFailureWindowMinutes = "login_failure_window_minutes" // 登录失败次数的统计窗口（分钟）
+	ConfigKeyLoginIPFailureThreshold   = "login_ip_failure_threshold"   // 同一IP登录失败超过该次数后开始递增等待
+
+	// 密码策略相关配置
+	ConfigKeyPasswordMinLength      = "password_min_length"         // 登录密码最小长度（字符数）
+	ConfigKeyPasswordMinCharClasses = "password_min_char_classes"   // 至少包含大写字母、小写字母、数字、特殊字符中的几种
+	ConfigKeyPasswordBannedWords    = "password_banned_words"       // 禁止出现的词（逗号分隔，不区分大小写）
+	ConfigKeyPasswordDisallowUser   = "password_disallow_user_info" // 是否禁止包含用户名或邮箱前缀
+	ConfigKeyPasswordHistoryCount   = "password_history_count"      // 最近几次使用过的密码（含当前密码）不能再次使用
+	ConfigKeyPasswordMaxAgeDays     = "password_max_age_days"       // 登录密码最长使用天数（0表示不限制）
+
+	// 个人访问令牌相关配置
+	ConfigKeyPersonalAccessTokenMaxDays    = "personal_access_token_max_days"     // 个人访问令牌的最长有效期（天）
+	ConfigKeyPersonalAccessTokenMaxPerUser = "personal_access_token_max_per_user" // 每个用户最多可创建的令牌数量（0表示不限制）
+
+	// 服务账户相关配置
+	ConfigKeyServiceAccountTokenTTLMinutes = "service_account_token_ttl_minutes"  // 服务账户登录后令牌的有效期（分钟）
+	ConfigKeyServiceAccountSecretIDMaxDays = "service_account_secret_id_max_days" // secret_id的最长有效期（天）
+	ConfigKeyServiceAccountMaxPerUser      = "service_account_max_per_user"       // 每个用户最多可创建的服务账户数量（0表示不限制）
 )
 
 // 配置值
@@ -56,4 +111,54 @@ const (
 	ConfigValueEmailCodeExpiryDefault   = "300"             // 默认验证码有效期5分钟
 	ConfigValueEmailRateLimitDefault    = "60"              // 默认发送间隔60秒
 	ConfigValueEmailSMTPFromNameDefault = "VaultHub System" // 默认发件人名称
+
+	// 回收站默认配置值
+	ConfigValueSecretTrashRetentionDaysDefault = "30" // 默认保留30天
+
+	// 附件默认配置值
+	ConfigValueAttachmentMaxFileSizeMBDefault = "25"  // 默认单个附件最大25MB
+	ConfigValueAttachmentUserQuotaMBDefault   = "100" // 默认每个用户100MB
+
+	// 秘密检出默认配置值
+	ConfigValueSecretCheckoutDefaultMinutesDefault = "60"  // 默认检出1小时
+	ConfigValueSecretCheckoutMaxMinutesDefault     = "480" // 最长检出8小时
+
+	// 高敏感秘密审批默认配置值
+	ConfigValueSecretApprovalApproverRoleDefault    = "admin" // 默认由管理员审批
+	ConfigValueSecretApprovalWindowMinutesDefault   = "30"    // 默认批准后30分钟内可解密
+	ConfigValueSecretApprovalRequestTTLHoursDefault = "24"    // 默认24小时内未审批自动失效
+
+	// 紧急访问默认配置值
+	ConfigValueEmergencyAccessDefaultWaitDaysDefault = "7" // 默认等待7天
+
+	// 保险库健康检查默认配置值
+	ConfigValueVaultHealthStaleDaysDefault = "180" // 默认180天未变更视为过旧
+
+	// 两步验证默认配置值
+	ConfigValueMFAIssuerDefault = "VaultHub" // 默认签发方名称
+
+	// 登录会话默认配置值
+	ConfigValueMaxSessionsPerUserDefault = "0" // 默认不限制
+
+	// 登录失败锁定默认配置值
+	ConfigValueLoginLockoutThresholdDefault     = "10" // 默认连续失败10次锁定
+	ConfigValueLoginLockoutMinutesDefault       = "30" // 默认锁定30分钟后自动解锁
+	ConfigValueLoginFailureWindowMinutesDefault = "15" // 默认统计最近15分钟的失败次数
+	ConfigValueLoginIPFailureThresholdDefault   = "50" // 默认同一IP失败50次后开始递增等待
+
+	// 密码策略默认配置值
+	ConfigValuePasswordMinLengthDefault      = "8"    // 默认至少8个字符
+	ConfigValuePasswordMinCharClassesDefault = "3"    // 默认至少包含三类字符
+	ConfigValuePasswordDisallowUserDefault   = "true" // 默认禁止包含用户名或邮箱前缀
+	ConfigValuePasswordHistoryCountDefault   = "5"    // 默认最近5次用过的密码不能再用
+	ConfigValuePasswordMaxAgeDaysDefault     = "0"    // 默认不限制使用期限
+
+	// 个人访问令牌默认配置值
+	ConfigValuePersonalAccessTokenMaxDaysDefault    = "365" // 默认最长有效期1年
+	ConfigValuePersonalAccessTokenMaxPerUserDefault = "20"  // 默认每个用户最多20个
+
+	// 服务账户默认配置值
+	ConfigValueServiceAccountTokenTTLMinutesDefault = "60" // 默认令牌有效期1小时
+	ConfigValueServiceAccountSecretIDMaxDaysDefault = "90" // 默认secret_id最长有效期90天
+	ConfigValueServiceAccountMaxPerUserDefault      = "20" // 默认每个用户最多20个
 )
@@ -16,12 +16,16 @@ const (
 // User 用户模型
 type User struct {
 	BaseModel
-	UUID         string     `gorm:"type:char(36);uniqueIndex;not null" json:"uuid"`
-	Username     string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"username"`
-	PasswordHash string     `gorm:"type:varchar(255);not null" json:"-"` // 密码哈希不返回给前端
-	Status       UserStatus `gorm:"type:tinyint;not null;default:1" json:"status"`
-	Role         string     `gorm:"type:varchar(32);not null;default:'user';index" json:"role"`
-	LastLoginAt  *time.Time `gorm:"type:datetime" json:"last_login_at,omitempty"`
+	UUID         string `gorm:"type:char(36);uniqueIndex;not null" json:"uuid"`
+	Username     string `gorm:"type:varchar(64);uniqueIndex;not null" json:"username"`
+	PasswordHash string `gorm:"type:varchar(255);not null" json:"-"` // 密码哈希不返回给前端
+	// PasswordChangedAt 最近一次修改登录密码的时间，为空时以创建时间为准
+	PasswordChangedAt *time.Time `gorm:"type:datetime" json:"password_changed_at,omitempty"`
+	Status            UserStatus `gorm:"type:tinyint;not null;default:1" json:"status"`
+	Role              string     `gorm:"type:varchar(32);not null;default:'user';index" json:"role"`
+	LastLoginAt       *time.Time `gorm:"type:datetime" json:"last_login_at,omitempty"`
+	LockedUntil       *time.Time `gorm:"type:datetime" json:"locked_until,omitempty"` // 自动锁定的解锁时间，为空时只能由管理员解锁
+	TokenEpoch        uint       `gorm:"not null;default:0" json:"-"`                 // 令牌版本，修改密码、角色或状态时递增，此前签发的令牌全部失效
 }
 
 // TableName 指定表名
@@ -44,6 +48,20 @@ func (u *User) IsLocked() bool {
 	return u.Status == UserStatusLocked
 }
 
+// LockExpired 判断自动锁定是否已到解锁时间
+func (u *User) LockExpired() bool {
+	return u.IsLocked() && u.LockedUntil != nil && time.Now().After(*u.LockedUntil)
+}
+
+// PasswordAge 返回登录密码已使用的时长
+func (u *User) PasswordAge() time.Duration {
+	changedAt := u.CreatedAt
+	if u.PasswordChangedAt != nil {
+		changedAt = *u.PasswordChangedAt
+	}
+	return time.Since(changedAt)
+}
+
 // CanOperate 判断用户是否可以操作（只有活跃用户可以操作）
 func (u *User) CanOperate() bool {
 	return u.IsActive()
@@ -56,26 +74,30 @@ func (u *User) IsAdmin() bool {
 
 // SafeUser 用于返回给前端的安全用户信息（不包含敏感字段）
 type SafeUser struct {
-	ID          uint       `json:"id"`
-	UUID        string     `json:"uuid"`
-	Username    string     `json:"username"`
-	Status      UserStatus `json:"status"`
-	Role        string     `json:"role"`
-	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID                uint       `json:"id"`
+	UUID              string     `json:"uuid"`
+	Username          string     `json:"username"`
+	Status            UserStatus `json:"status"`
+	Role              string     `json:"role"`
+	LastLoginAt       *time.Time `json:"last_login_at,omitempty"`
+	LockedUntil       *time.Time `json:"locked_until,omitempty"`
+	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
 }
 
 // ToSafeUser 转换为安全用户信息
 func (u *User) ToSafeUser() *SafeUser {
 	return &SafeUser{
-		ID:          u.ID,
-		UUID:        u.UUID,
-		Username:    u.Username,
-		Status:      u.Status,
-		Role:        u.Role,
-		LastLoginAt: u.LastLoginAt,
-		CreatedAt:   u.CreatedAt,
-		UpdatedAt:   u.UpdatedAt,
+		ID:                u.ID,
+		UUID:              u.UUID,
+		Username:          u.Username,
+		Status:            u.Status,
+		Role:              u.Role,
+		LastLoginAt:       u.LastLoginAt,
+		LockedUntil:       u.LockedUntil,
+		PasswordChangedAt: u.PasswordChangedAt,
+		CreatedAt:         u.CreatedAt,
+		UpdatedAt:         u.UpdatedAt,
 	}
 }
@@ -27,6 +27,11 @@ type UserEncryptionKey struct {
 	EncryptedDEKRecovery []byte     `gorm:"type:varbinary(512);not null" json:"-"` // 恢复密钥加密的DEK不对外暴露
 	LastRotationAt       *time.Time `gorm:"type:datetime" json:"last_rotation_at"` // 最后一次密钥轮换时间
 
+	// 紧急访问密钥对（X25519）
+	// 公钥供授权人封装DEK，私钥由本用户的DEK加密保存
+	PublicKey           []byte `gorm:"type:varbinary(32)" json:"-"`
+	EncryptedPrivateKey []byte `gorm:"type:varbinary(128)" json:"-"` // 私钥不对外暴露
+
 	// 密钥轮换相关
 	EncryptedDEKOld   []byte     `gorm:"type:varbinary(512)" json:"-"`                                    // 旧DEK（轮换期间暂存）
 	RotationStatus    string     `gorm:"type:varchar(20);not null;default:'none'" json:"rotation_status"` // 轮换状态
@@ -77,6 +82,11 @@ func (k *UserEncryptionKey) ToSafe() *SafeUserEncryptionKey {
 	}
 }
 
+// HasKeyPair 检查用户是否已生成紧急访问密钥对
+func (k *UserEncryptionKey) HasKeyPair() bool {
+	return len(k.PublicKey) > 0 && len(k.EncryptedPrivateKey) > 0
+}
+
 // HasSecurityPIN 检查用户是否已设置安全密码
 // 用于判断用户是否需要先设置安全密码才能使用加密功能
 func (k *UserEncryptionKey) HasSecurityPIN() bool {
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// UserIdentity 外部身份关联
+// 一个OIDC身份（issuer + sub）只能关联一个账户，一个账户可以关联多个身份
+type UserIdentity struct {
+	BaseModel
+	UUID        string     `gorm:"type:char(36);uniqueIndex;not null" json:"uuid"`
+	UserUUID    string     `gorm:"type:char(36);not null;index" json:"user_uuid"`
+	Issuer      string     `gorm:"type:varchar(255);not null;uniqueIndex:uk_user_identities_issuer_subject" json:"issuer"`
+	Subject     string     `gorm:"type:varchar(255);not null;uniqueIndex:uk_user_identities_issuer_subject" json:"subject"` // sub声明
+	Email       string     `gorm:"type:varchar(100);not null;default:''" json:"email"`                                      // 最近一次登录时身份提供方返回的邮箱
+	LastLoginAt *time.Time `gorm:"type:datetime" json:"last_login_at,omitempty"`
+}
+
+// TableName 指定表名
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// UserMFA 用户两步验证（TOTP）配置
+// TOTP密钥需要在每次登录时参与计算，无法只保存哈希，因此使用由服务端密钥派生的密钥加密保存
+type UserMFA struct {
+	BaseModel
+	UserUUID        string     `gorm:"type:char(36);uniqueIndex;not null" json:"user_uuid"`
+	EncryptedSecret []byte     `gorm:"type:varbinary(128);not null" json:"-"` // 加密的TOTP密钥不对外暴露
+	Enabled         bool       `gorm:"not null;default:false" json:"enabled"` // 绑定后需要用第一个验证码确认才启用
+	EnabledAt       *time.Time `gorm:"type:datetime" json:"enabled_at,omitempty"`
+	LastUsedStep    int64      `gorm:"not null;default:0" json:"-"` // 最后一次通过校验的时间步，用于防止验证码重放
+}
+
+// TableName 指定表名
+func (UserMFA) TableName() string {
+	return "user_mfa"
+}
+
+// MFABackupCode 两步验证备用码
+// 备用码只在生成时返回一次，之后只保存bcrypt哈希，每个备用码只能使用一次
+type MFABackupCode struct {
+	BaseModel
+	UserUUID string     `gorm:"type:char(36);not null;index" json:"user_uuid"`
+	CodeHash string     `gorm:"type:varchar(255);not null" json:"-"` // 备用码哈希不对外暴露
+	UsedAt   *time.Time `gorm:"type:datetime" json:"used_at,omitempty"`
+}
+
+// TableName 指定表名
+func (MFABackupCode) TableName() string {
+	return "mfa_backup_codes"
+}
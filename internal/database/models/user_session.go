@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// UserSession 用户登录会话
+// 每次登录创建一个会话，同一用户可以在多个设备上同时登录；
+// 访问令牌短期有效，过期后使用刷新令牌换取新的访问令牌和刷新令牌（刷新令牌每次使用后轮换）
+type UserSession struct {
+	BaseModel
+	UUID                     string    `gorm:"type:char(36);uniqueIndex;not null" json:"uuid"`
+	UserUUID                 string    `gorm:"type:char(36);not null;index" json:"user_uuid"`
+	DeviceLabel              string    `gorm:"type:varchar(64);not null;default:''" json:"device_label"` // 登录时客户端提供的设备名称
+	IPAddress                string    `gorm:"type:varchar(45);not null;default:''" json:"ip_address"`   // 最近一次登录或刷新时的IP
+	UserAgent                string    `gorm:"type:varchar(512);not null;default:''" json:"user_agent"`
+	RefreshTokenHash         string    `gorm:"type:char(64);uniqueIndex;not null" json:"-"` // 当前刷新令牌的SHA-256
+	PreviousRefreshTokenHash string    `gorm:"type:char(64);not null;default:''" json:"-"`  // 上一个（已轮换的）刷新令牌的SHA-256，再次出现说明已泄露
+	TokenEpoch               uint      `gorm:"not null;default:0" json:"-"`                 // 创建会话时用户的令牌版本，与用户不一致时会话失效
+	LastSeenAt               time.Time `gorm:"type:datetime;not null" json:"last_seen_at"`
+	ExpiresAt                time.Time `gorm:"type:datetime;not null;index" json:"expires_at"` // 刷新令牌过期时间，每次刷新后顺延
+}
+
+// TableName 指定表名
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
+
+// IsExpired 会话是否已过期
+func (s *UserSession) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// WebAuthnCredential 用户注册的WebAuthn凭证（通行密钥）
+// 每个用户可以注册多个凭证并分别命名，只保存公钥，私钥始终留在认证器中
+type WebAuthnCredential struct {
+	BaseModel
+	UUID            string     `gorm:"type:char(36);uniqueIndex;not null" json:"uuid"`
+	UserUUID        string     `gorm:"type:char(36);not null;index" json:"user_uuid"`
+	Name            string     `gorm:"type:varchar(64);not null" json:"name"`
+	CredentialID    []byte     `gorm:"type:varbinary(1023);uniqueIndex;not null" json:"-"`
+	PublicKey       []byte     `gorm:"type:blob;not null" json:"-"` // COSE格式的凭证公钥
+	AttestationType string     `gorm:"type:varchar(32);not null;default:''" json:"attestation_type"`
+	Transports      string     `gorm:"type:varchar(255);not null;default:''" json:"transports"` // 认证器支持的传输方式（逗号分隔）
+	AAGUID          []byte     `gorm:"type:varbinary(16)" json:"-"`
+	SignCount       uint32     `gorm:"not null;default:0" json:"sign_count"`          // 认证器签名计数，计数回退说明凭证可能被复制
+	BackupEligible  bool       `gorm:"not null;default:false" json:"backup_eligible"` // 是否为可同步的通行密钥，注册后不会改变
+	BackupState     bool       `gorm:"not null;default:false" json:"backup_state"`
+	LastUsedAt      *time.Time `gorm:"type:datetime" json:"last_used_at,omitempty"`
+}
+
+// TableName 指定表名
+func (WebAuthnCredential) TableName() string {
+	return "webauthn_credentials"
+}
@@ -0,0 +1,760 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/config"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	redisClient "github.com/cuihe500/vaulthub/pkg/redis"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// 访问审批相关常量
+const (
+	approvalTokenPrefix  = "access_approval_token:"
+	approvalTokenSize    = 32 // 邮件审批令牌随机字节数
+	maxVaultApprovers    = 20 // 每个保险库最多指定的审批人数
+	approvalEmailTimeout = 2 * time.Minute
+)
+
+// ApprovalService 高敏感秘密访问审批服务（双人控制）
+// 高敏感秘密解密前，申请人需要提交访问申请并由另一位审批人批准：
+//   - 审批人优先取秘密所属保险库指定的审批人，未指定时取系统配置角色的所有用户，申请人本人不能审批
+//   - 审批人可以通过API审批，也可以通过邮件中的一次性链接审批
+//   - 批准后申请人在时间窗口内可以解密，DecryptSecret 负责校验
+type ApprovalService struct {
+	db            *gorm.DB
+	redis         *redisClient.Client
+	emailService  *EmailService
+	configManager *config.ConfigManager
+	auditService  *AuditService
+	publicURL     string // 服务对外访问地址，用于拼接邮件审批链接
+}
+
+// NewApprovalService 创建访问审批服务实例
+func NewApprovalService(db *gorm.DB, redis *redisClient.Client, emailService *EmailService, configManager *config.ConfigManager, auditService *AuditService, publicURL string) *ApprovalService {
+	return &ApprovalService{
+		db:            db,
+		redis:         redis,
+		emailService:  emailService,
+		configManager: configManager,
+		auditService:  auditService,
+		publicURL:     publicURL,
+	}
+}
+
+// findAccessGrant 查询用户对秘密仍在解密窗口内的已批准访问申请，不存在时返回nil
+func findAccessGrant(db *gorm.DB, userUUID, secretUUID string) (*models.SecretAccessRequest, error) {
+	var grant models.SecretAccessRequest
+	err := db.Where("requester_uuid = ? AND secret_uuid = ? AND status = ? AND access_until > ?",
+		userUUID, secretUUID, models.AccessRequestApproved, time.Now()).
+		Order("access_until DESC").First(&grant).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error("查询访问申请失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return &grant, nil
+}
+
+// requireAccessGrant 高敏感秘密需要有已批准且仍在解密窗口内的访问申请，其他秘密直接通过
+// 附件下载等不经过 DecryptSecret 读取明文的路径使用该函数，与解密秘密保持相同的访问控制
+func requireAccessGrant(db *gorm.DB, userUUID string, secret *models.EncryptedSecret) error {
+	if !secret.ApprovalRequired {
+		return nil
+	}
+	grant, err := findAccessGrant(db, userUUID, secret.SecretUUID)
+	if err != nil {
+		return err
+	}
+	if grant == nil {
+		logger.Warn("高敏感秘密未获批准", logger.String("user_uuid", userUUID), logger.String("secret_uuid", secret.SecretUUID))
+		return errors.New(errors.CodeApprovalRequired, "该秘密需要审批通过后才能解密，请先提交访问申请")
+	}
+	return nil
+}
+
+// makeApprovalTokenKey 生成邮件审批令牌在Redis中的key（只保存令牌的哈希）
+func makeApprovalTokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return approvalTokenPrefix + hex.EncodeToString(sum[:])
+}
+
+// intConfig 读取正整数配置，配置无效时使用默认值
+func (s *ApprovalService) intConfig(key, defaultValue string) int {
+	value := s.configManager.GetWithDefault(key, defaultValue)
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		logger.Warn("审批配置无效，使用默认值", logger.String("key", key), logger.String("value", value))
+		n, _ = strconv.Atoi(defaultValue)
+	}
+	return n
+}
+
+// approverRole 读取默认审批角色
+func (s *ApprovalService) approverRole() string {
+	return s.configManager.GetWithDefault(models.ConfigKeySecretApprovalApproverRole, models.ConfigValueSecretApprovalApproverRoleDefault)
+}
+
+// getSecret 查询用户的秘密
+func (s *ApprovalService) getSecret(userUUID, secretUUID string) (*models.EncryptedSecret, error) {
+	var secret models.EncryptedSecret
+	if err := s.db.Where("user_uuid = ? AND secret_uuid = ?", userUUID, secretUUID).First(&secret).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Warn("秘密不存在或无权访问", logger.String("user_uuid", userUUID), logger.String("secret_uuid", secretUUID))
+			return nil, errors.New(errors.CodeResourceNotFound, "秘密不存在或无权访问")
+		}
+		logger.Error("查询秘密失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return &secret, nil
+}
+
+// eligibleApprovers 查询可以审批某个保险库访问申请的活跃用户（不含申请人）
+func (s *ApprovalService) eligibleApprovers(ownerUUID, requesterUUID string) ([]*models.User, error) {
+	var explicit int64
+	if err := s.db.Model(&models.VaultApprover{}).Where("owner_uuid = ?", ownerUUID).Count(&explicit).Error; err != nil {
+		logger.Error("查询保险库审批人失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	query := s.db.Model(&models.User{}).
+		Where("users.status = ? AND users.uuid <> ?", models.UserStatusActive, requesterUUID)
+	if explicit > 0 {
+		query = query.Where("users.uuid IN (?)",
+			s.db.Model(&models.VaultApprover{}).Select("approver_uuid").Where("owner_uuid = ?", ownerUUID))
+	} else {
+		query = query.Where("users.role = ?", s.approverRole())
+	}
+
+	var approvers []*models.User
+	if err := query.Find(&approvers).Error; err != nil {
+		logger.Error("查询审批人失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return approvers, nil
+}
+
+// SetApprovalPolicyRequest 设置审批策略请求
+type SetApprovalPolicyRequest struct {
+	UserUUID         string `json:"-"`                 // 不从请求体解析，由handler从上下文设置
+	SecretUUID       string `json:"-"`                 // 不从请求体解析，由handler从URL路径设置
+	ApprovalRequired bool   `json:"approval_required"` // 是否为高敏感秘密
+}
+
+// SetApprovalPolicy 设置秘密是否需要审批后才能解密
+// 关闭审批本身也需要他人批准：只有持有有效的已批准访问申请时才能关闭
+func (s *ApprovalService) SetApprovalPolicy(req *SetApprovalPolicyRequest) (*models.SafeEncryptedSecret, error) {
+	secret, err := s.getSecret(req.UserUUID, req.SecretUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if secret.ApprovalRequired && !req.ApprovalRequired {
+		grant, err := findAccessGrant(s.db, req.UserUUID, req.SecretUUID)
+		if err != nil {
+			return nil, err
+		}
+		if grant == nil {
+			return nil, errors.New(errors.CodeApprovalRequired, "关闭审批需要先提交访问申请并获得批准")
+		}
+	}
+
+	if err := s.db.Model(secret).Update("approval_required", req.ApprovalRequired).Error; err != nil {
+		logger.Error("更新审批策略失败", logger.Err(err), logger.String("secret_uuid", req.SecretUUID))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	secret.ApprovalRequired = req.ApprovalRequired
+
+	logger.Info("更新审批策略成功",
+		logger.String("user_uuid", req.UserUUID),
+		logger.String("secret_uuid", req.SecretUUID),
+		logger.Bool("approval_required", req.ApprovalRequired))
+	return secret.ToSafe(), nil
+}
+
+// ApproverInfo 审批人信息
+type ApproverInfo struct {
+	UUID     string `json:"uuid"`
+	Username string `json:"username"`
+}
+
+// VaultApproversResponse 保险库审批人响应
+type VaultApproversResponse struct {
+	Approvers    []*ApproverInfo `json:"approvers"`     // 指定的审批人
+	UsingDefault bool            `json:"using_default"` // 未指定审批人，使用默认审批角色
+	DefaultRole  string          `json:"default_role"`  // 默认审批角色
+}
+
+// GetVaultApprovers 查询保险库指定的审批人
+func (s *ApprovalService) GetVaultApprovers(ownerUUID string) (*VaultApproversResponse, error) {
+	var users []*models.User
+	if err := s.db.Model(&models.User{}).
+		Where("uuid IN (?)", s.db.Model(&models.VaultApprover{}).Select("approver_uuid").Where("owner_uuid = ?", ownerUUID)).
+		Order("username").Find(&users).Error; err != nil {
+		logger.Error("查询保险库审批人失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	approvers := make([]*ApproverInfo, 0, len(users))
+	for _, user := range users {
+		approvers = append(approvers, &ApproverInfo{UUID: user.UUID, Username: user.Username})
+	}
+	return &VaultApproversResponse{
+		Approvers:    approvers,
+		UsingDefault: len(approvers) == 0,
+		DefaultRole:  s.approverRole(),
+	}, nil
+}
+
+// SetVaultApproversRequest 设置保险库审批人请求
+type SetVaultApproversRequest struct {
+	OwnerUUID string   `json:"-"`                                        // 不从请求体解析，由handler从上下文设置
+	Usernames []string `json:"usernames" binding:"max=20,dive,required"` // 审批人用户名，为空时恢复使用默认审批角色
+}
+
+// SetVaultApprovers 替换保险库指定的审批人
+func (s *ApprovalService) SetVaultApprovers(req *SetVaultApproversRequest) (*VaultApproversResponse, error) {
+	if len(req.Usernames) > maxVaultApprovers {
+		return nil, errors.New(errors.CodeParamOutOfRange, "审批人数量超出限制")
+	}
+
+	var users []*models.User
+	if len(req.Usernames) > 0 {
+		if err := s.db.Where("username IN ? AND status = ?", req.Usernames, models.UserStatusActive).Find(&users).Error; err != nil {
+			logger.Error("查询审批人失败", logger.Err(err))
+			return nil, errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		found := make(map[string]bool, len(users))
+		for _, user := range users {
+			if user.UUID == req.OwnerUUID {
+				return nil, errors.New(errors.CodeInvalidParam, "不能指定自己为审批人")
+			}
+			found[user.Username] = true
+		}
+		for _, name := range req.Usernames {
+			if !found[name] {
+				return nil, errors.New(errors.CodeResourceNotFound, "审批人不存在或已停用: "+name)
+			}
+		}
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 物理删除旧记录，避免软删除记录占用唯一索引
+		if err := tx.Unscoped().Where("owner_uuid = ?", req.OwnerUUID).Delete(&models.VaultApprover{}).Error; err != nil {
+			return err
+		}
+		for _, user := range users {
+			if err := tx.Create(&models.VaultApprover{OwnerUUID: req.OwnerUUID, ApproverUUID: user.UUID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("保存保险库审批人失败", logger.Err(err), logger.String("owner_uuid", req.OwnerUUID))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	logger.Info("更新保险库审批人成功", logger.String("owner_uuid", req.OwnerUUID), logger.Int("count", len(users)))
+	return s.GetVaultApprovers(req.OwnerUUID)
+}
+
+// CreateAccessRequestRequest 提交访问申请请求
+type CreateAccessRequestRequest struct {
+	UserUUID      string `json:"-"`                                                 // 不从请求体解析，由handler从上下文设置
+	Username      string `json:"-"`                                                 // 由handler从上下文设置
+	SecretUUID    string `json:"-"`                                                 // 不从请求体解析，由handler从URL路径设置
+	Reason        string `json:"reason" binding:"required,max=500"`                 // 申请原因
+	WindowMinutes int    `json:"window_minutes" binding:"omitempty,min=1,max=1440"` // 批准后可解密的时长（分钟），默认取系统配置
+}
+
+// CreateAccessRequestResponse 提交访问申请响应
+type CreateAccessRequestResponse struct {
+	Request   *models.SecretAccessRequest `json:"request"`
+	Approvers []string                    `json:"approvers"` // 收到审批通知的审批人用户名
+}
+
+// CreateAccessRequest 提交高敏感秘密访问申请并通知审批人
+func (s *ApprovalService) CreateAccessRequest(req *CreateAccessRequestRequest) (*CreateAccessRequestResponse, error) {
+	// 1. 检查秘密
+	secret, err := s.getSecret(req.UserUUID, req.SecretUUID)
+	if err != nil {
+		return nil, err
+	}
+	if !secret.ApprovalRequired {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "该秘密无需审批，可直接解密")
+	}
+
+	// 2. 同一秘密只能有一个待审批的申请
+	var pending int64
+	if err := s.db.Model(&models.SecretAccessRequest{}).
+		Where("requester_uuid = ? AND secret_uuid = ? AND status = ? AND expires_at > ?",
+			req.UserUUID, req.SecretUUID, models.AccessRequestPending, time.Now()).
+		Count(&pending).Error; err != nil {
+		logger.Error("查询访问申请失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if pending > 0 {
+		return nil, errors.New(errors.CodeResourceAlreadyExists, "该秘密已有待审批的访问申请")
+	}
+
+	// 3. 确定审批人
+	approvers, err := s.eligibleApprovers(secret.UserUUID, req.UserUUID)
+	if err != nil {
+		return nil, err
+	}
+	if len(approvers) == 0 {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "没有可用的审批人，请先指定保险库审批人")
+	}
+
+	// 4. 保存申请
+	window := req.WindowMinutes
+	if window == 0 {
+		window = s.intConfig(models.ConfigKeySecretApprovalWindowMinutes, models.ConfigValueSecretApprovalWindowMinutesDefault)
+	}
+	ttlHours := s.intConfig(models.ConfigKeySecretApprovalRequestTTLHours, models.ConfigValueSecretApprovalRequestTTLHoursDefault)
+	request := &models.SecretAccessRequest{
+		RequestUUID:       uuid.New().String(),
+		SecretUUID:        req.SecretUUID,
+		OwnerUUID:         secret.UserUUID,
+		RequesterUUID:     req.UserUUID,
+		RequesterUsername: req.Username,
+		Reason:            req.Reason,
+		WindowMinutes:     window,
+		Status:            models.AccessRequestPending,
+		ExpiresAt:         time.Now().Add(time.Duration(ttlHours) * time.Hour),
+	}
+	if err := s.db.Create(request).Error; err != nil {
+		logger.Error("保存访问申请失败", logger.Err(err), logger.String("secret_uuid", req.SecretUUID))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	// 5. 异步发送审批邮件，邮件失败不影响申请（审批人仍可通过API审批）
+	go s.notifyApprovers(request, secret.SecretName, approvers, ttlHours)
+
+	names := make([]string, 0, len(approvers))
+	for _, approver := range approvers {
+		names = append(names, approver.Username)
+	}
+
+	logger.Info("提交访问申请成功",
+		logger.String("user_uuid", req.UserUUID),
+		logger.String("secret_uuid", req.SecretUUID),
+		logger.String("request_uuid", request.RequestUUID),
+		logger.Int("approvers", len(approvers)))
+
+	return &CreateAccessRequestResponse{
+		Request:   request,
+		Approvers: names,
+	}, nil
+}
+
+// notifyApprovers 为每个审批人生成一次性审批令牌并发送邮件
+// 审批链接只使用配置的 server.public_url 拼接，未配置时不发送邮件，审批人通过API审批
+func (s *ApprovalService) notifyApprovers(request *models.SecretAccessRequest, secretName string, approvers []*models.User, ttlHours int) {
+	if s.publicURL == "" {
+		logger.Warn("未配置server.public_url，跳过审批邮件通知", logger.String("request_uuid", request.RequestUUID))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), approvalEmailTimeout)
+	defer cancel()
+
+	ttl := time.Until(request.ExpiresAt)
+	for _, approver := range approvers {
+		var profile models.UserProfile
+		if err := s.db.Where("user_id = ?", approver.ID).First(&profile).Error; err != nil || profile.Email == "" {
+			logger.Warn("审批人未设置邮箱，跳过邮件通知", logger.String("approver_uuid", approver.UUID))
+			continue
+		}
+
+		tokenBytes, err := crypto.GenerateRandomBytes(approvalTokenSize)
+		if err != nil {
+			logger.Error("生成审批令牌失败", logger.Err(err))
+			return
+		}
+		token := hex.EncodeToString(tokenBytes)
+		if err := s.redis.Set(ctx, makeApprovalTokenKey(token), request.RequestUUID+":"+approver.UUID, ttl); err != nil {
+			logger.Error("保存审批令牌失败", logger.Err(err))
+			continue
+		}
+
+		approvalURL := s.publicURL + "/access-approval?token=" + token
+		if err := s.emailService.SendAccessApprovalRequest(profile.Email, request.RequesterUsername, secretName, request.Reason, approvalURL, ttlHours); err != nil {
+			logger.Error("发送审批邮件失败",
+				logger.String("approver_uuid", approver.UUID),
+				logger.String("request_uuid", request.RequestUUID),
+				logger.Err(err))
+		}
+	}
+}
+
+// ListAccessRequestsRequest 查询访问申请请求
+type ListAccessRequestsRequest struct {
+	UserUUID string                     `form:"-"`
+	Role     string                     `form:"-"`
+	Status   models.AccessRequestStatus `form:"status" binding:"omitempty,oneof=pending approved denied expired cancelled"`
+	Page     int                        `form:"page" binding:"omitempty,min=1"`
+	PageSize int                        `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// ListAccessRequestsResponse 查询访问申请响应
+type ListAccessRequestsResponse struct {
+	Requests []*models.SecretAccessRequest `json:"requests"`
+	Total    int64                         `json:"total"`
+	Page     int                           `json:"page"`
+	PageSize int                           `json:"page_size"`
+}
+
+// ListMyRequests 查询当前用户提交的访问申请
+func (s *ApprovalService) ListMyRequests(req *ListAccessRequestsRequest) (*ListAccessRequestsResponse, error) {
+	query := s.db.Model(&models.SecretAccessRequest{}).Where("requester_uuid = ?", req.UserUUID)
+	return s.listRequests(query, req)
+}
+
+// ListPendingApprovals 查询当前用户可以审批的待审批申请
+func (s *ApprovalService) ListPendingApprovals(req *ListAccessRequestsRequest) (*ListAccessRequestsResponse, error) {
+	explicitOwners := s.db.Model(&models.VaultApprover{}).Select("owner_uuid").Where("approver_uuid = ?", req.UserUUID)
+	condition := s.db.Where("owner_uuid IN (?)", explicitOwners)
+	if req.Role == s.approverRole() {
+		// 未指定审批人的保险库由默认审批角色审批
+		condition = condition.Or("owner_uuid NOT IN (?)", s.db.Model(&models.VaultApprover{}).Select("owner_uuid"))
+	}
+
+	req.Status = models.AccessRequestPending
+	query := s.db.Model(&models.SecretAccessRequest{}).
+		Where("requester_uuid <> ? AND expires_at > ?", req.UserUUID, time.Now()).
+		Where(condition)
+	return s.listRequests(query, req)
+}
+
+// listRequests 按状态过滤并分页查询访问申请
+func (s *ApprovalService) listRequests(query *gorm.DB, req *ListAccessRequestsRequest) (*ListAccessRequestsResponse, error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+
+	// 超时未审批的申请标记为已失效
+	if err := s.db.Model(&models.SecretAccessRequest{}).
+		Where("status = ? AND expires_at <= ?", models.AccessRequestPending, time.Now()).
+		Update("status", models.AccessRequestExpired).Error; err != nil {
+		logger.Error("更新失效访问申请失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error("统计访问申请失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	var requests []*models.SecretAccessRequest
+	if err := query.Order("id DESC").
+		Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).
+		Find(&requests).Error; err != nil {
+		logger.Error("查询访问申请失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	return &ListAccessRequestsResponse{
+		Requests: requests,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, nil
+}
+
+// getRequest 查询访问申请
+func (s *ApprovalService) getRequest(requestUUID string) (*models.SecretAccessRequest, error) {
+	var request models.SecretAccessRequest
+	if err := s.db.Where("request_uuid = ?", requestUUID).First(&request).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeResourceNotFound, "访问申请不存在")
+		}
+		logger.Error("查询访问申请失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return &request, nil
+}
+
+// DecideAccessRequestRequest 审批访问申请请求
+type DecideAccessRequestRequest struct {
+	ApproverUUID     string `json:"-"`                                   // 由handler从上下文设置
+	ApproverUsername string `json:"-"`                                   // 由handler从上下文设置
+	RequestUUID      string `json:"-"`                                   // 不从请求体解析，由handler从URL路径设置
+	Approve          bool   `json:"-"`                                   // 由handler根据接口设置
+	Comment          string `json:"comment" binding:"omitempty,max=500"` // 审批意见
+}
+
+// DecideAccessRequest 批准或拒绝访问申请
+func (s *ApprovalService) DecideAccessRequest(req *DecideAccessRequestRequest) (*models.SecretAccessRequest, error) {
+	request, err := s.getRequest(req.RequestUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 检查审批人资格（申请人本人不能审批）
+	if request.RequesterUUID == req.ApproverUUID {
+		return nil, errors.New(errors.CodeForbidden, "不能审批自己的访问申请")
+	}
+	approvers, err := s.eligibleApprovers(request.OwnerUUID, request.RequesterUUID)
+	if err != nil {
+		return nil, err
+	}
+	eligible := false
+	for _, approver := range approvers {
+		if approver.UUID == req.ApproverUUID {
+			eligible = true
+			break
+		}
+	}
+	if !eligible {
+		return nil, errors.New(errors.CodeForbidden, "您不是该申请的审批人")
+	}
+
+	// 2. 检查申请状态
+	if !request.IsPending() {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "访问申请已处理或已失效")
+	}
+
+	// 3. 带状态条件更新，防止多个审批人同时审批
+	now := time.Now()
+	updates := map[string]interface{}{
+		"approver_uuid":     req.ApproverUUID,
+		"approver_username": req.ApproverUsername,
+		"decision_comment":  req.Comment,
+		"decided_at":        now,
+	}
+	if req.Approve {
+		accessUntil := now.Add(time.Duration(request.WindowMinutes) * time.Minute)
+		updates["status"] = models.AccessRequestApproved
+		updates["access_until"] = accessUntil
+		request.Status = models.AccessRequestApproved
+		request.AccessUntil = &accessUntil
+	} else {
+		updates["status"] = models.AccessRequestDenied
+		request.Status = models.AccessRequestDenied
+	}
+	result := s.db.Model(&models.SecretAccessRequest{}).
+		Where("id = ? AND status = ?", request.ID, models.AccessRequestPending).
+		Updates(updates)
+	if result.Error != nil {
+		logger.Error("更新访问申请失败", logger.Err(result.Error))
+		return nil, errors.Wrap(errors.CodeDatabaseError, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "访问申请已处理或已失效")
+	}
+	request.ApproverUUID = req.ApproverUUID
+	request.ApproverUsername = req.ApproverUsername
+	request.DecisionComment = req.Comment
+	request.DecidedAt = &now
+
+	logger.Info("审批访问申请",
+		logger.String("request_uuid", request.RequestUUID),
+		logger.String("approver_uuid", req.ApproverUUID),
+		logger.String("status", string(request.Status)))
+	return request, nil
+}
+
+// CancelAccessRequest 申请人撤回待审批的访问申请
+func (s *ApprovalService) CancelAccessRequest(userUUID, requestUUID string) (*models.SecretAccessRequest, error) {
+	request, err := s.getRequest(requestUUID)
+	if err != nil {
+		return nil, err
+	}
+	if request.RequesterUUID != userUUID {
+		return nil, errors.New(errors.CodeResourceNotFound, "访问申请不存在")
+	}
+
+	// 已批准的申请也可以撤回，提前结束解密窗口
+	result := s.db.Model(&models.SecretAccessRequest{}).
+		Where("id = ? AND status IN ?", request.ID, []models.AccessRequestStatus{models.AccessRequestPending, models.AccessRequestApproved}).
+		Updates(map[string]interface{}{
+			"status":       models.AccessRequestCancelled,
+			"access_until": nil,
+		})
+	if result.Error != nil {
+		logger.Error("撤回访问申请失败", logger.Err(result.Error))
+		return nil, errors.Wrap(errors.CodeDatabaseError, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "访问申请已处理或已失效")
+	}
+	request.Status = models.AccessRequestCancelled
+	request.AccessUntil = nil
+
+	logger.Info("撤回访问申请", logger.String("request_uuid", requestUUID))
+	return request, nil
+}
+
+// resolveApprovalToken 解析邮件审批令牌，返回申请和审批人
+func (s *ApprovalService) resolveApprovalToken(token string) (*models.SecretAccessRequest, *models.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	value, err := s.redis.Get(ctx, makeApprovalTokenKey(token))
+	if err == redis.Nil {
+		return nil, nil, errors.New(errors.CodeInvalidToken, "审批链接无效或已过期")
+	}
+	if err != nil {
+		logger.Error("读取审批令牌失败", logger.Err(err))
+		return nil, nil, errors.Wrap(errors.CodeCacheError, err)
+	}
+
+	requestUUID, approverUUID, ok := strings.Cut(value, ":")
+	if !ok {
+		return nil, nil, errors.New(errors.CodeInvalidToken, "审批链接无效或已过期")
+	}
+	request, err := s.getRequest(requestUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var approver models.User
+	if err := s.db.Where("uuid = ?", approverUUID).First(&approver).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.New(errors.CodeInvalidToken, "审批链接无效或已过期")
+		}
+		logger.Error("查询审批人失败", logger.Err(err))
+		return nil, nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return request, &approver, nil
+}
+
+// EmailApprovalInfo 邮件审批页面展示的申请信息
+type EmailApprovalInfo struct {
+	Request    *models.SecretAccessRequest `json:"request"`
+	SecretName string                      `json:"secret_name"`
+	Approver   string                      `json:"approver"` // 令牌对应的审批人用户名
+}
+
+// GetEmailApproval 根据邮件审批令牌查询申请信息（不消耗令牌）
+func (s *ApprovalService) GetEmailApproval(token string) (*EmailApprovalInfo, error) {
+	request, approver, err := s.resolveApprovalToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var secret models.EncryptedSecret
+	if err := s.db.Unscoped().Select("secret_name").Where("secret_uuid = ?", request.SecretUUID).First(&secret).Error; err != nil && err != gorm.ErrRecordNotFound {
+		logger.Error("查询秘密失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	return &EmailApprovalInfo{
+		Request:    request,
+		SecretName: secret.SecretName,
+		Approver:   approver.Username,
+	}, nil
+}
+
+// EmailDecisionRequest 通过邮件链接审批请求
+type EmailDecisionRequest struct {
+	Token     string `json:"token" binding:"required"`                       // 邮件链接中的审批令牌
+	Decision  string `json:"decision" binding:"required,oneof=approve deny"` // 审批结果
+	Comment   string `json:"comment" binding:"omitempty,max=500"`            // 审批意见
+	IPAddress string `json:"-"`                                              // 审批人IP，用于审计
+	UserAgent string `json:"-"`                                              // 审批人User-Agent，用于审计
+	RequestID string `json:"-"`                                              // 请求ID，用于审计
+}
+
+// DecideByEmail 通过邮件中的一次性链接审批访问申请
+// 接口无需登录，审批结果以令牌对应的审批人身份写入审计日志
+func (s *ApprovalService) DecideByEmail(req *EmailDecisionRequest) (*models.SecretAccessRequest, error) {
+	request, approver, err := s.resolveApprovalToken(req.Token)
+	if err != nil {
+		return nil, err
+	}
+	if !approver.IsActive() {
+		return nil, errors.New(errors.CodeAccountDisabled, "审批人账户不可用")
+	}
+
+	approve := req.Decision == "approve"
+	decided, err := s.DecideAccessRequest(&DecideAccessRequestRequest{
+		ApproverUUID:     approver.UUID,
+		ApproverUsername: approver.Username,
+		RequestUUID:      request.RequestUUID,
+		Approve:          approve,
+		Comment:          req.Comment,
+	})
+	s.logEmailDecisionAudit(req, request, approver, approve, err)
+	if err != nil {
+		return nil, err
+	}
+
+	// 令牌只能使用一次
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := s.redis.Del(ctx, makeApprovalTokenKey(req.Token)); err != nil {
+		logger.Error("删除审批令牌失败", logger.Err(err))
+	}
+	return decided, nil
+}
+
+// logEmailDecisionAudit 以审批人身份记录邮件审批审计日志
+func (s *ApprovalService) logEmailDecisionAudit(req *EmailDecisionRequest, request *models.SecretAccessRequest, approver *models.User, approve bool, decideErr error) {
+	if s.auditService == nil {
+		return
+	}
+
+	action := models.ActionDeny
+	if approve {
+		action = models.ActionApprove
+	}
+	details, _ := json.Marshal(map[string]interface{}{
+		"channel":     "email",
+		"secret_uuid": request.SecretUUID,
+		"requester":   request.RequesterUsername,
+		"comment":     req.Comment,
+	})
+	requestUUID := request.RequestUUID
+	log := &models.AuditLog{
+		UserUUID:     approver.UUID,
+		Username:     approver.Username,
+		ActionType:   action,
+		ResourceType: models.ResourceAccessRequest,
+		ResourceUUID: &requestUUID,
+		Status:       models.AuditSuccess,
+		Details:      string(details),
+		CreatedAt:    time.Now().UTC(),
+	}
+	if decideErr != nil {
+		log.Status = models.AuditFailed
+		if appErr, ok := decideErr.(*errors.AppError); ok {
+			code := appErr.Code
+			log.ErrorCode = &code
+			log.ErrorMessage = &appErr.Message
+		}
+	}
+	if req.IPAddress != "" {
+		log.IPAddress = &req.IPAddress
+	}
+	if req.UserAgent != "" {
+		log.UserAgent = &req.UserAgent
+	}
+	if req.RequestID != "" {
+		log.RequestID = &req.RequestID
+	}
+	s.auditService.LogAsync(log)
+}
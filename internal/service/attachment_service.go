@@ -0,0 +1,454 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/cuihe500/vaulthub/internal/config"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/blobstore"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const bytesPerMB = 1024 * 1024
+
+// errAttachmentTooLarge 上传内容超过允许的大小
+var errAttachmentTooLarge = stderrors.New("附件超过允许的大小")
+
+// AttachmentService 秘密附件服务
+// 每个附件使用独立的随机文件密钥分块加密（crypto.StreamEncryptor），文件密钥用用户DEK加密后入库，
+// 密文写入附件存储。上传和下载全程流式处理，不在内存中保留完整文件
+type AttachmentService struct {
+	db                *gorm.DB
+	encryptionService *EncryptionService
+	configManager     *config.ConfigManager
+	store             blobstore.Store
+}
+
+// NewAttachmentService 创建附件服务实例
+func NewAttachmentService(db *gorm.DB, encryptionService *EncryptionService, configManager *config.ConfigManager, store blobstore.Store) *AttachmentService {
+	return &AttachmentService{
+		db:                db,
+		encryptionService: encryptionService,
+		configManager:     configManager,
+		store:             store,
+	}
+}
+
+// configMB 读取以MB为单位的配置并转换为字节，配置无效时使用默认值
+func (s *AttachmentService) configMB(key, defaultValue string) int64 {
+	value := s.configManager.GetWithDefault(key, defaultValue)
+	mb, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || mb < 0 {
+		logger.Warn("附件容量配置无效，使用默认值", logger.String("key", key), logger.String("value", value))
+		mb, _ = strconv.ParseInt(defaultValue, 10, 64)
+	}
+	return mb * bytesPerMB
+}
+
+// MaxFileSize 单个附件大小上限（字节）
+func (s *AttachmentService) MaxFileSize() int64 {
+	return s.configMB(models.ConfigKeyAttachmentMaxFileSizeMB, models.ConfigValueAttachmentMaxFileSizeMBDefault)
+}
+
+// userQuota 每个用户的附件总容量（字节）
+func (s *AttachmentService) userQuota() int64 {
+	return s.configMB(models.ConfigKeyAttachmentUserQuotaMB, models.ConfigValueAttachmentUserQuotaMBDefault)
+}
+
+// usedBytes 统计用户已使用的附件容量（按明文大小）
+func (s *AttachmentService) usedBytes(db *gorm.DB, userUUID string) (int64, error) {
+	var used int64
+	if err := db.Model(&models.SecretAttachment{}).
+		Where("user_uuid = ?", userUUID).
+		Select("COALESCE(SUM(size), 0)").
+		Scan(&used).Error; err != nil {
+		logger.Error("统计附件容量失败", logger.Err(err), logger.String("user_uuid", userUUID))
+		return 0, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return used, nil
+}
+
+// checkSecret 校验秘密存在且属于该用户（回收站中的秘密不能操作附件）
+func (s *AttachmentService) checkSecret(userUUID, secretUUID string) error {
+	var count int64
+	if err := s.db.Model(&models.EncryptedSecret{}).
+		Where("user_uuid = ? AND secret_uuid = ?", userUUID, secretUUID).
+		Count(&count).Error; err != nil {
+		logger.Error("查询秘密失败", logger.Err(err))
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if count == 0 {
+		logger.Warn("秘密不存在或无权访问", logger.String("user_uuid", userUUID), logger.String("secret_uuid", secretUUID))
+		return errors.New(errors.CodeResourceNotFound, "秘密不存在或无权访问")
+	}
+	return nil
+}
+
+// checkDownload 校验用户可以读取秘密附件的明文，与解密秘密使用相同的访问控制
+func (s *AttachmentService) checkDownload(userUUID, secretUUID string) error {
+	var secret models.EncryptedSecret
+	if err := s.db.Where("user_uuid = ? AND secret_uuid = ?", userUUID, secretUUID).First(&secret).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Warn("秘密不存在或无权访问", logger.String("user_uuid", userUUID), logger.String("secret_uuid", secretUUID))
+			return errors.New(errors.CodeResourceNotFound, "秘密不存在或无权访问")
+		}
+		logger.Error("查询秘密失败", logger.Err(err))
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if err := requireHeldCheckout(s.db, userUUID, &secret); err != nil {
+		return err
+	}
+	return requireAccessGrant(s.db, userUUID, &secret)
+}
+
+// getAttachment 查询秘密下的附件
+func (s *AttachmentService) getAttachment(userUUID, secretUUID, attachmentUUID string) (*models.SecretAttachment, error) {
+	var attachment models.SecretAttachment
+	err := s.db.Where("user_uuid = ? AND secret_uuid = ? AND attachment_uuid = ?", userUUID, secretUUID, attachmentUUID).
+		First(&attachment).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Warn("附件不存在", logger.String("secret_uuid", secretUUID), logger.String("attachment_uuid", attachmentUUID))
+			return nil, errors.New(errors.CodeResourceNotFound, "附件不存在")
+		}
+		logger.Error("查询附件失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return &attachment, nil
+}
+
+// UploadAttachmentRequest 上传附件请求
+// 由handler从multipart表单中构造，Content为文件内容流
+type UploadAttachmentRequest struct {
+	UserUUID    string
+	SecretUUID  string
+	SecurityPIN string
+	FileName    string
+	ContentType string
+	Content     io.Reader
+}
+
+// UploadAttachment 加密并保存附件
+func (s *AttachmentService) UploadAttachment(ctx context.Context, req *UploadAttachmentRequest) (*models.SecretAttachment, error) {
+	if err := s.checkSecret(req.UserUUID, req.SecretUUID); err != nil {
+		return nil, err
+	}
+
+	// 1. 计算本次允许写入的大小：单文件上限与剩余配额取较小值
+	maxSize := s.MaxFileSize()
+	quota := s.userQuota()
+	used, err := s.usedBytes(s.db, req.UserUUID)
+	if err != nil {
+		return nil, err
+	}
+	if used >= quota {
+		return nil, errors.New(errors.CodeQuotaExceeded, fmt.Sprintf("附件容量已用完（%d MB）", quota/bytesPerMB))
+	}
+	limit := maxSize
+	if remaining := quota - used; remaining < limit {
+		limit = remaining
+	}
+
+	// 2. 解锁DEK，生成文件密钥并用DEK加密
+	userKey, dek, err := s.encryptionService.unlockDEK(req.UserUUID, req.SecurityPIN)
+	if err != nil {
+		return nil, err
+	}
+	fileKey, err := crypto.GenerateRandomBytes(crypto.AESKeySize)
+	if err != nil {
+		crypto.ClearBytes(dek)
+		return nil, err
+	}
+	defer crypto.ClearBytes(fileKey)
+	encryptedFileKey, keyNonce, keyAuthTag, err := crypto.EncryptAESGCM(fileKey, dek)
+	crypto.ClearBytes(dek)
+	if err != nil {
+		logger.Error("加密文件密钥失败", logger.Err(err))
+		return nil, err
+	}
+
+	// 3. 边读边加密写入存储
+	attachmentUUID := uuid.New().String()
+	storageKey := req.UserUUID + "/" + attachmentUUID
+	size, checksum, storedSize, err := s.storeEncrypted(ctx, storageKey, fileKey, req.Content, limit)
+	if err != nil {
+		if stderrors.Is(err, errAttachmentTooLarge) {
+			if limit < maxSize {
+				return nil, errors.New(errors.CodeQuotaExceeded, fmt.Sprintf("附件容量不足，剩余 %d 字节", limit))
+			}
+			return nil, errors.New(errors.CodeParamOutOfRange, fmt.Sprintf("附件大小不能超过 %d MB", maxSize/bytesPerMB))
+		}
+		if _, ok := err.(*errors.AppError); ok {
+			return nil, err
+		}
+		logger.Error("保存附件失败", logger.Err(err), logger.String("storage_key", storageKey))
+		return nil, errors.WithMessage(errors.CodeInternalError, "保存附件失败", err)
+	}
+
+	attachment := &models.SecretAttachment{
+		UserUUID:         req.UserUUID,
+		SecretUUID:       req.SecretUUID,
+		AttachmentUUID:   attachmentUUID,
+		FileName:         req.FileName,
+		ContentType:      req.ContentType,
+		Size:             size,
+		ChecksumSHA256:   checksum,
+		StorageKey:       storageKey,
+		StoredSize:       storedSize,
+		ChunkSize:        crypto.StreamChunkSize,
+		EncryptedFileKey: encryptedFileKey,
+		DEKVersion:       userKey.DEKVersion,
+		KeyNonce:         keyNonce,
+		KeyAuthTag:       keyAuthTag,
+	}
+
+	// 4. 写入记录，写入前按实际大小再次检查配额，缩小并发上传超出限制的窗口
+	if used, err = s.usedBytes(s.db, req.UserUUID); err != nil {
+		s.deleteBlob(storageKey)
+		return nil, err
+	}
+	if used+size > quota {
+		s.deleteBlob(storageKey)
+		return nil, errors.New(errors.CodeQuotaExceeded, fmt.Sprintf("附件容量不足（%d MB）", quota/bytesPerMB))
+	}
+	if err := s.db.Create(attachment).Error; err != nil {
+		s.deleteBlob(storageKey)
+		logger.Error("保存附件记录失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	logger.Info("上传附件成功",
+		logger.String("user_uuid", req.UserUUID),
+		logger.String("secret_uuid", req.SecretUUID),
+		logger.String("attachment_uuid", attachmentUUID),
+		logger.Int64("size", size))
+	return attachment, nil
+}
+
+// storeEncrypted 从content读取最多limit字节，分块加密后写入存储
+// 返回明文大小、明文SHA-256和密文大小；失败时不会留下存储对象
+func (s *AttachmentService) storeEncrypted(ctx context.Context, storageKey string, fileKey []byte, content io.Reader, limit int64) (int64, string, int64, error) {
+	pr, pw := io.Pipe()
+	hash := sha256.New()
+	counter := &limitedCounter{r: content, limit: limit}
+
+	// 加密在独立goroutine中写入管道，存储端从管道读取密文
+	encErr := make(chan error, 1)
+	go func() {
+		enc, err := crypto.NewStreamEncryptor(pw, fileKey, crypto.StreamChunkSize)
+		if err == nil {
+			_, err = io.Copy(enc, io.TeeReader(counter, hash))
+			if closeErr := enc.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		_ = pw.CloseWithError(err)
+		encErr <- err
+	}()
+
+	storedSize, err := s.store.Put(ctx, storageKey, pr)
+	// 存储端提前失败时关闭读端，让加密goroutine退出
+	_ = pr.CloseWithError(err)
+	if e := <-encErr; e != nil && (err == nil || stderrors.Is(e, errAttachmentTooLarge)) {
+		err = e
+	}
+	if err != nil {
+		s.deleteBlob(storageKey)
+		return 0, "", 0, err
+	}
+	return counter.n, hex.EncodeToString(hash.Sum(nil)), storedSize, nil
+}
+
+// limitedCounter 统计读取的字节数，超过limit时返回errAttachmentTooLarge
+type limitedCounter struct {
+	r     io.Reader
+	limit int64
+	n     int64
+}
+
+func (l *limitedCounter) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.n > l.limit {
+		return n, errAttachmentTooLarge
+	}
+	return n, err
+}
+
+// ListAttachmentsResponse 附件列表响应
+type ListAttachmentsResponse struct {
+	Attachments []models.SecretAttachment `json:"attachments"`
+	UsedBytes   int64                     `json:"used_bytes"`    // 当前用户已使用的附件容量
+	QuotaBytes  int64                     `json:"quota_bytes"`   // 当前用户的附件总容量
+	MaxFileSize int64                     `json:"max_file_size"` // 单个附件大小上限
+}
+
+// ListAttachments 列出秘密的附件
+func (s *AttachmentService) ListAttachments(userUUID, secretUUID string) (*ListAttachmentsResponse, error) {
+	if err := s.checkSecret(userUUID, secretUUID); err != nil {
+		return nil, err
+	}
+
+	var attachments []models.SecretAttachment
+	if err := s.db.Where("user_uuid = ? AND secret_uuid = ?", userUUID, secretUUID).
+		Order("created_at DESC").
+		Find(&attachments).Error; err != nil {
+		logger.Error("查询附件列表失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	used, err := s.usedBytes(s.db, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListAttachmentsResponse{
+		Attachments: attachments,
+		UsedBytes:   used,
+		QuotaBytes:  s.userQuota(),
+		MaxFileSize: s.MaxFileSize(),
+	}, nil
+}
+
+// DownloadAttachmentRequest 下载附件请求
+// 注意：UserUUID、SecretUUID、AttachmentUUID 由handler从上下文和路径参数设置
+type DownloadAttachmentRequest struct {
+	UserUUID       string `json:"-"`
+	SecretUUID     string `json:"-"`
+	AttachmentUUID string `json:"-"`
+	SecurityPIN    string `json:"security_pin" binding:"required"` // 安全密码
+}
+
+// OpenAttachment 验证安全密码并返回附件的明文读取流
+// 需要检出的秘密的附件只有租约持有者能下载，高敏感秘密的附件同样需要已批准的访问申请
+// 读取过程中逐块认证，数据被篡改时Read返回CodeDecryptionFailed错误；调用方必须关闭返回的流
+func (s *AttachmentService) OpenAttachment(ctx context.Context, req *DownloadAttachmentRequest) (*models.SecretAttachment, io.ReadCloser, error) {
+	if err := s.checkDownload(req.UserUUID, req.SecretUUID); err != nil {
+		return nil, nil, err
+	}
+	attachment, err := s.getAttachment(req.UserUUID, req.SecretUUID, req.AttachmentUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userKey, dek, err := s.encryptionService.unlockDEK(req.UserUUID, req.SecurityPIN)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment.DEKVersion != userKey.DEKVersion {
+		crypto.ClearBytes(dek)
+		logger.Warn("DEK版本不匹配", logger.Int("attachment_version", attachment.DEKVersion), logger.Int("current_version", userKey.DEKVersion))
+		return nil, nil, errors.New(errors.CodeCryptoError, "密钥版本不匹配，请联系管理员")
+	}
+	fileKey, err := crypto.DecryptAESGCM(attachment.EncryptedFileKey, dek, attachment.KeyNonce, attachment.KeyAuthTag)
+	crypto.ClearBytes(dek)
+	if err != nil {
+		logger.Error("解密文件密钥失败", logger.Err(err), logger.String("attachment_uuid", attachment.AttachmentUUID))
+		return nil, nil, errors.WithMessage(errors.CodeDecryptionFailed, "解密失败或数据被篡改", err)
+	}
+
+	blob, err := s.store.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		crypto.ClearBytes(fileKey)
+		logger.Error("读取附件失败", logger.Err(err), logger.String("storage_key", attachment.StorageKey))
+		if stderrors.Is(err, blobstore.ErrNotFound) {
+			return nil, nil, errors.New(errors.CodeResourceNotFound, "附件内容不存在")
+		}
+		return nil, nil, errors.WithMessage(errors.CodeInternalError, "读取附件失败", err)
+	}
+
+	dec, err := crypto.NewStreamDecryptor(blob, fileKey)
+	crypto.ClearBytes(fileKey)
+	if err != nil {
+		_ = blob.Close()
+		logger.Error("解密附件失败", logger.Err(err), logger.String("attachment_uuid", attachment.AttachmentUUID))
+		return nil, nil, err
+	}
+
+	return attachment, &attachmentReader{dec: dec, blob: blob}, nil
+}
+
+// attachmentReader 关闭时同时释放解密器和存储对象
+type attachmentReader struct {
+	dec  *crypto.StreamDecryptor
+	blob io.Closer
+}
+
+func (r *attachmentReader) Read(p []byte) (int, error) {
+	return r.dec.Read(p)
+}
+
+func (r *attachmentReader) Close() error {
+	_ = r.dec.Close()
+	return r.blob.Close()
+}
+
+// DeleteAttachment 删除附件（记录和存储内容一并彻底删除）
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, userUUID, secretUUID, attachmentUUID string) (*models.SecretAttachment, error) {
+	if err := s.checkSecret(userUUID, secretUUID); err != nil {
+		return nil, err
+	}
+	attachment, err := s.getAttachment(userUUID, secretUUID, attachmentUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Unscoped().Delete(attachment).Error; err != nil {
+		logger.Error("删除附件记录失败", logger.Err(err), logger.String("attachment_uuid", attachmentUUID))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	s.deleteBlob(attachment.StorageKey)
+
+	logger.Info("删除附件成功", logger.String("user_uuid", userUUID), logger.String("attachment_uuid", attachmentUUID))
+	return attachment, nil
+}
+
+// DeleteBySecrets 彻底删除指定秘密的全部附件（回收站清除秘密时调用）
+func (s *AttachmentService) DeleteBySecrets(secretUUIDs []string) (int64, error) {
+	if len(secretUUIDs) == 0 {
+		return 0, nil
+	}
+
+	var attachments []models.SecretAttachment
+	if err := s.db.Select("id", "storage_key").
+		Where("secret_uuid IN ?", secretUUIDs).
+		Find(&attachments).Error; err != nil {
+		logger.Error("查询待删除附件失败", logger.Err(err))
+		return 0, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if len(attachments) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]uint, len(attachments))
+	for i, attachment := range attachments {
+		ids[i] = attachment.ID
+	}
+	result := s.db.Unscoped().Where("id IN ?", ids).Delete(&models.SecretAttachment{})
+	if result.Error != nil {
+		logger.Error("删除附件记录失败", logger.Err(result.Error))
+		return 0, errors.Wrap(errors.CodeDatabaseError, result.Error)
+	}
+
+	for _, attachment := range attachments {
+		s.deleteBlob(attachment.StorageKey)
+	}
+	return result.RowsAffected, nil
+}
+
+// deleteBlob 删除存储中的附件内容，失败只记录日志（记录已删除，残留的密文无法被读取）
+func (s *AttachmentService) deleteBlob(storageKey string) {
+	if err := s.store.Delete(context.Background(), storageKey); err != nil {
+		logger.Error("删除附件存储内容失败", logger.Err(err), logger.String("storage_key", storageKey))
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	stderrors "errors"
+
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// 认证提供方名称
+const (
+	AuthProviderLocal = "local"
+	AuthProviderLDAP  = "ldap"
+)
+
+// ErrAuthProviderSkip 用户不属于该认证提供方，交给下一个提供方
+var ErrAuthProviderSkip = stderrors.New("用户不属于该认证提供方")
+
+// AuthProvider 用户名密码登录的认证提供方
+// AuthService.Login 按顺序询问各提供方，第一个不返回 ErrAuthProviderSkip 的提供方决定登录结果
+type AuthProvider interface {
+	// Name 提供方名称，本地密码提供方为 AuthProviderLocal
+	Name() string
+	// Authenticate 验证用户名和密码，返回对应的本地用户（用户状态由调用方检查）
+	// 密码错误时返回 CodeInvalidCredentials，能确定本地用户时同时返回该用户，用于登录失败锁定
+	Authenticate(username, password string) (*models.User, error)
+}
+
+// LocalAuthProvider 本地密码认证提供方，验证 users.password_hash
+type LocalAuthProvider struct {
+	db              *gorm.DB
+	externalIssuers []string
+}
+
+// NewLocalAuthProvider 创建本地密码认证提供方
+// 关联了externalIssuers中外部身份的用户由对应的提供方认证，本地提供方跳过
+func NewLocalAuthProvider(db *gorm.DB, externalIssuers ...string) *LocalAuthProvider {
+	return &LocalAuthProvider{
+		db:              db,
+		externalIssuers: externalIssuers,
+	}
+}
+
+// Name 提供方名称
+func (p *LocalAuthProvider) Name() string {
+	return AuthProviderLocal
+}
+
+// Authenticate 按用户名查找用户并验证密码
+func (p *LocalAuthProvider) Authenticate(username, password string) (*models.User, error) {
+	var user models.User
+	if err := p.db.Where("username = ?", username).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// 同样执行一次密码哈希比较，避免响应时间暴露用户名是否存在
+			crypto.VerifyPassword(password, dummyPasswordHash())
+			return nil, ErrAuthProviderSkip
+		}
+		logger.Error("查询用户失败", logger.String("username", username), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	if len(p.externalIssuers) > 0 {
+		var count int64
+		if err := p.db.Model(&models.UserIdentity{}).
+			Where("user_uuid = ? AND issuer IN ?", user.UUID, p.externalIssuers).
+			Count(&count).Error; err != nil {
+			logger.Error("查询外部身份失败", logger.String("uuid", user.UUID), logger.Err(err))
+			return nil, errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		if count > 0 {
+			crypto.VerifyPassword(password, dummyPasswordHash())
+			return nil, ErrAuthProviderSkip
+		}
+	}
+
+	if !crypto.VerifyPassword(password, user.PasswordHash) {
+		logger.Warn("密码验证失败", logger.String("username", username))
+		return &user, errors.New(errors.CodeInvalidCredentials, "")
+	}
+	return &user, nil
+}
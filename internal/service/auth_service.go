@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/cuihe500/vaulthub/internal/database/models"
@@ -15,21 +17,43 @@ import (
 	"gorm.io/gorm"
 )
 
+// 密码过期时修改密码的挑战令牌
+const (
+	passwordChangePrefix    = "password_change:"
+	passwordChangeTokenSize = 32
+	passwordChangeTTL       = 10 * time.Minute
+)
+
 // AuthService 认证服务
 type AuthService struct {
-	db           *gorm.DB
-	jwtManager   *jwt.Manager
-	redis        *redisClient.Client
-	emailService *EmailService
+	db             *gorm.DB
+	jwtManager     *jwt.Manager
+	redis          *redisClient.Client
+	emailService   *EmailService
+	mfaService     *MFAService
+	webAuthn       *WebAuthnService
+	sessions       *SessionService
+	lockout        *LoginLockoutService
+	passwordPolicy *PasswordPolicyService
+	oidc           *OIDCService
+	providers      []AuthProvider
 }
 
 // NewAuthService 创建认证服务实例
-func NewAuthService(db *gorm.DB, jwtManager *jwt.Manager, redis *redisClient.Client, emailService *EmailService) *AuthService {
+// providers为用户名密码登录的认证提供方，按顺序尝试
+func NewAuthService(db *gorm.DB, jwtManager *jwt.Manager, redis *redisClient.Client, emailService *EmailService, mfaService *MFAService, webAuthn *WebAuthnService, sessions *SessionService, lockout *LoginLockoutService, passwordPolicy *PasswordPolicyService, oidc *OIDCService, providers []AuthProvider) *AuthService {
 	return &AuthService{
-		db:           db,
-		jwtManager:   jwtManager,
-		redis:        redis,
-		emailService: emailService,
+		db:             db,
+		jwtManager:     jwtManager,
+		redis:          redis,
+		emailService:   emailService,
+		mfaService:     mfaService,
+		webAuthn:       webAuthn,
+		sessions:       sessions,
+		lockout:        lockout,
+		passwordPolicy: passwordPolicy,
+		oidc:           oidc,
+		providers:      providers,
 	}
 }
 
@@ -51,9 +75,9 @@ type RegisterResponse struct {
 func (s *AuthService) Register(req *RegisterRequest) (*RegisterResponse, error) {
 	ctx := context.Background()
 
-	// 验证密码强度
-	if !crypto.ValidatePasswordStrength(req.Password) {
-		return nil, errors.New(errors.CodeWeakPassword, "")
+	// 按密码策略校验密码
+	if err := s.passwordPolicy.Validate(req.Password, req.Username, req.Email); err != nil {
+		return nil, err
 	}
 
 	// 检查用户名是否已存在
@@ -161,103 +185,410 @@ func (s *AuthService) Register(req *RegisterRequest) (*RegisterResponse, error)
 
 // LoginRequest 登录请求
 type LoginRequest struct {
-	Username string `json:"username" binding:"required"`
+	Username string `json:"username" binding:"required,max=64"`
 	Password string `json:"password" binding:"required"`
+	SessionClient
 }
 
 // LoginResponse 登录响应
+// 需要两步验证时只返回 mfa_required 和 mfa_token，通过 /auth/login/mfa 提交验证码后才返回token；
+// 密码过期时（需要两步验证的在验证通过后）只返回 password_change_required 和 password_change_token，
+// 通过 /auth/login/password-change 修改密码后签发token
 type LoginResponse struct {
-	Token string           `json:"token"`
-	User  *models.SafeUser `json:"user"`
+	Token        string           `json:"token"`                   // 访问令牌，短期有效
+	RefreshToken string           `json:"refresh_token,omitempty"` // 刷新令牌，通过 /auth/refresh 换取新的令牌，每次使用后轮换
+	ExpiresIn    int64            `json:"expires_in,omitempty"`    // 访问令牌有效期（秒）
+	SessionUUID  string           `json:"session_uuid,omitempty"`  // 登录会话UUID
+	User         *models.SafeUser `json:"user"`
+
+	MFARequired           bool     `json:"mfa_required,omitempty"`
+	MFAToken              string   `json:"mfa_token,omitempty"`               // 两步验证挑战令牌，5分钟内有效
+	MFAEnrollmentRequired bool     `json:"mfa_enrollment_required,omitempty"` // 角色要求两步验证但尚未绑定，需要先绑定认证器
+	BackupCodes           []string `json:"backup_codes,omitempty"`            // 登录时完成绑定才返回，只返回这一次
+
+	PasswordChangeRequired bool   `json:"password_change_required,omitempty"` // 密码已超过最长使用期限，需要先修改密码
+	PasswordChangeToken    string `json:"password_change_token,omitempty"`    // 修改过期密码的令牌，10分钟内有效
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+// dummyPasswordHash 返回用于不存在用户的占位密码哈希，首次调用时生成
+func dummyPasswordHash() string {
+	dummyHashOnce.Do(func() {
+		hash, err := crypto.HashPassword(uuid.New().String())
+		if err != nil {
+			logger.Error("生成占位密码哈希失败", logger.Err(err))
+			return
+		}
+		dummyHash = hash
+	})
+	return dummyHash
 }
 
 // Login 用户登录
+// 账户或IP登录失败次数过多时需要等待，账户失败次数达到阈值时自动锁定；
+// 用户不存在、密码错误（包括锁定后继续输错）都只返回 CodeInvalidCredentials，不暴露账户是否存在或已锁定
 func (s *AuthService) Login(req *LoginRequest) (*LoginResponse, error) {
-	// 查找用户
+	if err := s.lockout.Check(req.Username, req.IPAddress); err != nil {
+		return nil, err
+	}
+
+	user, provider, err := s.authenticate(req.Username, req.Password)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Code == errors.CodeInvalidCredentials {
+			s.lockout.RecordFailure(req.Username, req.IPAddress, user)
+		}
+		return nil, err
+	}
+
+	// 检查用户状态
+	if err := checkLoginStatus(s.db, user); err != nil {
+		return nil, err
+	}
+
+	// 启用或被要求启用两步验证时，先返回两步验证挑战，验证通过后再签发令牌；
+	// 密码过期的标记随挑战保存，只凭密码不能修改密码
+	passwordExpired := provider == AuthProviderLocal && s.passwordPolicy.Expired(user)
+	if challenge, err := s.mfaChallenge(user, passwordExpired); challenge != nil || err != nil {
+		return challenge, err
+	}
+	// 需要两步验证的账户在第二步通过后才清除失败次数，否则可以用正确的密码反复重置验证码的猜测次数
+	s.lockout.RecordSuccess(req.Username)
+
+	// 本地密码超过最长使用期限时，先要求修改密码；外部提供方的密码由目录服务管理
+	if passwordExpired {
+		return s.passwordChangeChallenge(user)
+	}
+
+	resp, err := s.issueToken(user, req.SessionClient)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("用户登录成功",
+		logger.String("uuid", user.UUID),
+		logger.String("username", user.Username),
+		logger.String("provider", provider))
+	return resp, nil
+}
+
+// authenticate 按顺序询问认证提供方，返回用户和认证通过的提供方名称
+// 所有提供方都不认识该用户时返回 CodeInvalidCredentials
+func (s *AuthService) authenticate(username, password string) (*models.User, string, error) {
+	for _, provider := range s.providers {
+		user, err := provider.Authenticate(username, password)
+		if err == ErrAuthProviderSkip {
+			continue
+		}
+		return user, provider.Name(), err
+	}
+	return nil, "", errors.New(errors.CodeInvalidCredentials, "")
+}
+
+// LoginWithMFARequest 两步登录第二步请求
+type LoginWithMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required,max=32"` // 认证器验证码或备用码；首次绑定时只能使用认证器验证码
+	SessionClient
+}
+
+// LoginWithMFA 两步登录第二步：用挑战令牌和两步验证码换取JWT token
+// 验证码错误与密码错误一样计入账户的失败次数，达到阈值时同样锁定账户；
+// 登录时密码已过期的，验证通过后返回修改密码的挑战
+func (s *AuthService) LoginWithMFA(req *LoginWithMFARequest) (*LoginResponse, error) {
+	userUUID, err := s.mfaService.ChallengeUser(req.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+
 	var user models.User
-	if err := s.db.Where("username = ?", req.Username).First(&user).Error; err != nil {
+	if err := s.db.Where("uuid = ?", userUUID).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.New(errors.CodeInvalidCredentials, "")
 		}
-		logger.Error("查询用户失败", logger.String("username", req.Username), logger.Err(err))
+		logger.Error("查询用户失败", logger.String("uuid", userUUID), logger.Err(err))
 		return nil, errors.Wrap(errors.CodeDatabaseError, err)
 	}
 
-	// 验证密码
-	if !crypto.VerifyPassword(req.Password, user.PasswordHash) {
-		logger.Warn("密码验证失败", logger.String("username", req.Username))
-		return nil, errors.New(errors.CodeInvalidCredentials, "")
+	// 挑战有效期内用户状态可能已被管理员修改，也可能因验证码输错次数过多被锁定
+	if err := checkLoginStatus(s.db, &user); err != nil {
+		return nil, err
+	}
+	if err := s.lockout.Check(user.Username, req.IPAddress); err != nil {
+		return nil, err
 	}
 
-	// 检查用户状态
-	if user.IsDisabled() {
-		return nil, errors.New(errors.CodeAccountDisabled, "")
+	result, err := s.mfaService.CompleteChallenge(req.MFAToken, req.Code)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Code == errors.CodeInvalidMFACode {
+			s.lockout.RecordFailure(user.Username, req.IPAddress, &user)
+		}
+		return nil, err
 	}
-	if user.IsLocked() {
-		return nil, errors.New(errors.CodeAccountLocked, "")
+	s.lockout.RecordSuccess(user.Username)
+
+	// 登录时本地密码已过期（挑战有效期内没有被修改）的，两步验证通过后才返回修改密码的令牌
+	if result.PasswordExpired && s.passwordPolicy.Expired(&user) {
+		resp, err := s.passwordChangeChallenge(&user)
+		if err != nil {
+			return nil, err
+		}
+		resp.BackupCodes = result.BackupCodes
+		return resp, nil
 	}
-	if !user.IsActive() {
-		return nil, errors.New(errors.CodeAccountNotActivated, "")
+
+	resp, err := s.issueToken(&user, req.SessionClient)
+	if err != nil {
+		return nil, err
 	}
+	resp.BackupCodes = result.BackupCodes
 
-	// 更新最后登录时间
-	now := time.Now()
-	user.LastLoginAt = &now
-	if err := s.db.Model(&user).Update("last_login_at", now).Error; err != nil {
-		logger.Error("更新最后登录时间失败", logger.String("uuid", user.UUID), logger.Err(err))
-		// 不返回错误，继续登录流程
+	logger.Info("两步验证登录成功", logger.String("uuid", user.UUID), logger.String("username", user.Username))
+	return resp, nil
+}
+
+// LoginWithPasskey 通行密钥无密码登录
+// 通行密钥登录要求认证器完成用户验证（PIN或生物识别），本身即为多因素认证，不再要求TOTP两步验证；
+// 签发的令牌同时视为已完成二次验证
+func (s *AuthService) LoginWithPasskey(req *WebAuthnLoginRequest) (*LoginResponse, error) {
+	userUUID, err := s.webAuthn.FinishLogin(req.SessionToken, req.Credential)
+	if err != nil {
+		return nil, err
 	}
 
-	// 生成JWT token
-	token, err := s.jwtManager.GenerateToken(user.UUID, user.Username, user.Role)
+	var user models.User
+	if err := s.db.Where("uuid = ?", userUUID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeInvalidCredentials, "")
+		}
+		logger.Error("查询用户失败", logger.String("uuid", userUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	if err := checkLoginStatus(s.db, &user); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.issueToken(&user, req.SessionClient)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.webAuthn.MarkSteppedUp(user.UUID, resp.Token); err != nil {
+		// 不影响登录，访问秘密时会再要求二次验证
+		logger.Warn("标记二次验证状态失败", logger.String("uuid", user.UUID), logger.Err(err))
+	}
+
+	logger.Info("通行密钥登录成功", logger.String("uuid", user.UUID), logger.String("username", user.Username))
+	return resp, nil
+}
+
+// OIDCLoginRequest 单点登录完成请求
+type OIDCLoginRequest struct {
+	Code  string `json:"code" binding:"required,max=2048"` // 回调中的授权码
+	State string `json:"state" binding:"required,max=128"` // 回调中的state
+	SessionClient
+}
+
+// LoginWithOIDC 用身份提供方回调中的授权码完成单点登录
+// 密码由身份提供方管理，不检查本地密码是否过期；用户启用或被要求启用两步验证时仍返回两步验证挑战
+func (s *AuthService) LoginWithOIDC(req *OIDCLoginRequest) (*LoginResponse, error) {
+	user, err := s.oidc.FinishLogin(req.Code, req.State)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkLoginStatus(s.db, user); err != nil {
+		return nil, err
+	}
+
+	if challenge, err := s.mfaChallenge(user, false); challenge != nil || err != nil {
+		return challenge, err
+	}
+
+	resp, err := s.issueToken(user, req.SessionClient)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("单点登录成功", logger.String("uuid", user.UUID), logger.String("username", user.Username))
+	return resp, nil
+}
+
+// BeginLoginMFAEnrollmentRequest 登录过程中绑定认证器请求
+type BeginLoginMFAEnrollmentRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
+
+// BeginLoginMFAEnrollment 角色被要求启用两步验证但尚未绑定时，用登录挑战令牌绑定认证器
+// 绑定后通过 LoginWithMFA 提交第一个验证码，确认绑定并完成登录
+func (s *AuthService) BeginLoginMFAEnrollment(req *BeginLoginMFAEnrollmentRequest) (*MFAEnrollmentResponse, error) {
+	userUUID, err := s.mfaService.ChallengeUser(req.MFAToken)
 	if err != nil {
-		logger.Error("生成JWT token失败", logger.String("uuid", user.UUID), logger.Err(err))
-		return nil, errors.Wrap(errors.CodeInternalError, err)
+		return nil, err
 	}
 
-	// Token互踢机制：一用户一Token
+	var user models.User
+	if err := s.db.Where("uuid = ?", userUUID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeInvalidCredentials, "")
+		}
+		logger.Error("查询用户失败", logger.String("uuid", userUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return s.mfaService.BeginEnrollment(user.UUID, user.Username)
+}
+
+// mfaChallenge 用户启用了两步验证或角色被要求启用时创建登录挑战，否则返回nil
+// passwordExpired 表示本地密码已过期，验证通过后 LoginWithMFA 返回修改密码的挑战
+func (s *AuthService) mfaChallenge(user *models.User, passwordExpired bool) (*LoginResponse, error) {
+	enabled, err := s.mfaService.IsEnabled(user.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled && !s.mfaService.IsRequired(user.Role) {
+		return nil, nil
+	}
+
+	token, err := s.mfaService.CreateChallenge(user.UUID, passwordExpired)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("密码验证通过，等待两步验证",
+		logger.String("uuid", user.UUID),
+		logger.Bool("enrollment_required", !enabled))
+	return &LoginResponse{
+		MFARequired:           true,
+		MFAToken:              token,
+		MFAEnrollmentRequired: !enabled,
+	}, nil
+}
+
+// passwordChangeChallenge 密码过期时创建修改密码的挑战令牌
+// 只能在密码和两步验证（如果需要）都已通过后调用，凭该令牌修改密码后直接签发令牌
+func (s *AuthService) passwordChangeChallenge(user *models.User) (*LoginResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	expiration := s.jwtManager.GetExpiration()
-	userTokenKey := makeUserTokenKey(user.UUID)
+	tokenBytes, err := crypto.GenerateRandomBytes(passwordChangeTokenSize)
+	if err != nil {
+		logger.Error("生成修改密码令牌失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCryptoError, err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+	if err := s.redis.Set(ctx, passwordChangePrefix+token, user.UUID, passwordChangeTTL); err != nil {
+		logger.Error("保存修改密码令牌失败", logger.String("uuid", user.UUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCacheError, err)
+	}
 
-	// 查询并删除旧Token
-	if oldToken, err := s.redis.Get(ctx, userTokenKey); err == nil && oldToken != "" {
-		oldTokenKey := makeTokenKey(oldToken)
-		if err := s.redis.Del(ctx, oldTokenKey); err != nil {
-			logger.Warn("删除旧Token失败",
-				logger.String("uuid", user.UUID),
-				logger.Err(err))
+	logger.Info("密码已过期，等待修改密码", logger.String("uuid", user.UUID))
+	return &LoginResponse{
+		PasswordChangeRequired: true,
+		PasswordChangeToken:    token,
+	}, nil
+}
+
+// ChangeExpiredPasswordRequest 登录时修改过期密码请求
+type ChangeExpiredPasswordRequest struct {
+	PasswordChangeToken string `json:"password_change_token" binding:"required,max=128"`
+	NewPassword         string `json:"new_password" binding:"required"`
+	SessionClient
+}
+
+// ChangeExpiredPassword 密码过期时用登录返回的令牌修改密码，之后签发令牌
+// 令牌在两步验证通过后才会签发，这里不再要求两步验证；修改后使该用户其他设备上的登录会话全部失效
+func (s *AuthService) ChangeExpiredPassword(req *ChangeExpiredPasswordRequest) (*LoginResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	key := passwordChangePrefix + req.PasswordChangeToken
+	userUUID, err := s.redis.Get(ctx, key)
+	if err != nil {
+		return nil, errors.New(errors.CodeInvalidToken, "修改密码令牌无效或已过期，请重新登录")
+	}
+
+	var user models.User
+	if err := s.db.Where("uuid = ?", userUUID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeInvalidCredentials, "")
 		}
+		logger.Error("查询用户失败", logger.String("uuid", userUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
 	}
 
-	// 写入新Token（双向索引）
-	tokenKey := makeTokenKey(token)
-	if err := s.redis.Set(ctx, tokenKey, user.UUID, expiration); err != nil {
-		logger.Error("存储token到Redis失败",
-			logger.String("uuid", user.UUID),
-			logger.Err(err))
-		// Redis存储失败不影响登录流程，仅记录错误
+	if err := checkLoginStatus(s.db, &user); err != nil {
+		return nil, err
 	}
-	if err := s.redis.Set(ctx, userTokenKey, token, expiration); err != nil {
-		logger.Error("存储user_token到Redis失败",
-			logger.String("uuid", user.UUID),
-			logger.Err(err))
+	if err := s.changePassword(&user, req.NewPassword); err != nil {
+		return nil, err
+	}
+	if err := s.redis.Del(ctx, key); err != nil {
+		logger.Warn("删除修改密码令牌失败", logger.String("uuid", user.UUID), logger.Err(err))
+	}
+	if err := s.sessions.RevokeAll(user.UUID); err != nil {
+		logger.Warn("删除用户会话失败", logger.String("uuid", user.UUID), logger.Err(err))
 	}
 
-	logger.Info("用户登录成功", logger.String("uuid", user.UUID), logger.String("username", user.Username))
+	logger.Info("过期密码修改成功", logger.String("uuid", user.UUID), logger.String("username", user.Username))
+	return s.issueToken(&user, req.SessionClient)
+}
 
+// issueToken 更新最后登录时间，创建登录会话并签发令牌
+func (s *AuthService) issueToken(user *models.User, client SessionClient) (*LoginResponse, error) {
+	// 更新最后登录时间
+	now := time.Now()
+	user.LastLoginAt = &now
+	if err := s.db.Model(user).Update("last_login_at", now).Error; err != nil {
+		logger.Error("更新最后登录时间失败", logger.String("uuid", user.UUID), logger.Err(err))
+		// 不返回错误，继续登录流程
+	}
+
+	// 每次登录创建独立的会话，不影响用户在其他设备上的会话
+	tokens, err := s.sessions.Create(user, client)
+	if err != nil {
+		return nil, err
+	}
+	return newLoginResponse(user, tokens), nil
+}
+
+// newLoginResponse 根据会话令牌构造登录响应
+func newLoginResponse(user *models.User, tokens *SessionTokens) *LoginResponse {
 	return &LoginResponse{
-		Token: token,
-		User:  user.ToSafeUser(),
-	}, nil
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		SessionUUID:  tokens.SessionUUID,
+		User:         user.ToSafeUser(),
+	}
+}
+
+// RefreshTokenRequest 刷新令牌请求
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required,max=128"`
+	IPAddress    string `json:"-"` // 不从请求体解析，由handler从请求设置
+	UserAgent    string `json:"-"` // 不从请求体解析，由handler从请求设置
+}
+
+// RefreshToken 使用刷新令牌换取新的访问令牌和刷新令牌
+// 旧的刷新令牌立即失效；已失效的刷新令牌再次使用时注销整个会话
+func (s *AuthService) RefreshToken(req *RefreshTokenRequest) (*LoginResponse, error) {
+	user, tokens, err := s.sessions.Refresh(req.RefreshToken, SessionClient{IPAddress: req.IPAddress, UserAgent: req.UserAgent})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("刷新令牌成功", logger.String("uuid", user.UUID), logger.String("session_uuid", tokens.SessionUUID))
+	return newLoginResponse(user, tokens), nil
 }
 
 // LoginWithEmailRequest 邮箱验证码登录请求
 type LoginWithEmailRequest struct {
 	Email string `json:"email" binding:"required,email"`
 	Code  string `json:"code" binding:"required,len=6,numeric"`
+	SessionClient
 }
 
 // LoginWithEmail 邮箱验证码登录
@@ -301,27 +632,11 @@ func (s *AuthService) LoginWithEmail(req *LoginWithEmailRequest) (*LoginResponse
 	}
 
 	// 4. 检查用户状态
-	if user.IsDisabled() {
-		return nil, errors.New(errors.CodeAccountDisabled, "")
-	}
-	if user.IsLocked() {
-		return nil, errors.New(errors.CodeAccountLocked, "")
-	}
-	if !user.IsActive() {
-		return nil, errors.New(errors.CodeAccountNotActivated, "")
-	}
-
-	// 5. 更新最后登录时间
-	now := time.Now()
-	user.LastLoginAt = &now
-	if err := s.db.Model(&user).Update("last_login_at", now).Error; err != nil {
-		logger.Error("更新最后登录时间失败",
-			logger.String("uuid", user.UUID),
-			logger.Err(err))
-		// 不返回错误，继续登录流程
+	if err := checkLoginStatus(s.db, &user); err != nil {
+		return nil, err
 	}
 
-	// 6. 标记邮箱已验证（如果还未验证）
+	// 5. 标记邮箱已验证（如果还未验证）
 	if !profile.EmailVerified {
 		if err := s.emailService.MarkEmailVerified(ctx, req.Email); err != nil {
 			logger.Warn("标记邮箱验证状态失败",
@@ -331,44 +646,15 @@ func (s *AuthService) LoginWithEmail(req *LoginWithEmailRequest) (*LoginResponse
 		}
 	}
 
-	// 7. 生成JWT token
-	token, err := s.jwtManager.GenerateToken(user.UUID, user.Username, user.Role)
-	if err != nil {
-		logger.Error("生成JWT token失败",
-			logger.String("uuid", user.UUID),
-			logger.Err(err))
-		return nil, errors.Wrap(errors.CodeInternalError, err)
-	}
-
-	// 8. Token互踢机制：一用户一Token
-	ctx2, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	expiration := s.jwtManager.GetExpiration()
-	userTokenKey := makeUserTokenKey(user.UUID)
-
-	// 查询并删除旧Token
-	if oldToken, err := s.redis.Get(ctx2, userTokenKey); err == nil && oldToken != "" {
-		oldTokenKey := makeTokenKey(oldToken)
-		if err := s.redis.Del(ctx2, oldTokenKey); err != nil {
-			logger.Warn("删除旧Token失败",
-				logger.String("uuid", user.UUID),
-				logger.Err(err))
-		}
+	// 6. 启用或被要求启用两步验证时，先返回两步验证挑战
+	if challenge, err := s.mfaChallenge(&user, false); challenge != nil || err != nil {
+		return challenge, err
 	}
 
-	// 写入新Token（双向索引）
-	tokenKey := makeTokenKey(token)
-	if err := s.redis.Set(ctx2, tokenKey, user.UUID, expiration); err != nil {
-		logger.Error("存储token到Redis失败",
-			logger.String("uuid", user.UUID),
-			logger.Err(err))
-		// Redis存储失败不影响登录流程，仅记录错误
-	}
-	if err := s.redis.Set(ctx2, userTokenKey, token, expiration); err != nil {
-		logger.Error("存储user_token到Redis失败",
-			logger.String("uuid", user.UUID),
-			logger.Err(err))
+	// 7. 更新最后登录时间并签发JWT token
+	resp, err := s.issueToken(&user, req.SessionClient)
+	if err != nil {
+		return nil, err
 	}
 
 	logger.Info("邮箱验证码登录成功",
@@ -376,55 +662,32 @@ func (s *AuthService) LoginWithEmail(req *LoginWithEmailRequest) (*LoginResponse
 		logger.String("username", user.Username),
 		logger.String("email", req.Email))
 
-	return &LoginResponse{
-		Token: token,
-		User:  user.ToSafeUser(),
-	}, nil
+	return resp, nil
 }
 
-// Logout 用户登出
+// Logout 用户登出，注销当前会话，不影响其他设备上的会话
 func (s *AuthService) Logout(token string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	// 解析Token获取userUUID
+	// 删除二次验证状态
+	if err := s.redis.Del(ctx, StepUpKey(token)); err != nil {
+		logger.Warn("从Redis删除二次验证状态失败", logger.Err(err))
+	}
+
 	claims, err := s.jwtManager.ParseToken(token)
 	if err != nil {
 		logger.Warn("登出时解析token失败", logger.Err(err))
-		// 即使解析失败，也尝试删除token
-	}
-
-	// 删除token（主索引）
-	tokenKey := makeTokenKey(token)
-	if err := s.redis.Del(ctx, tokenKey); err != nil {
-		logger.Error("从Redis删除token失败", logger.Err(err))
-		return errors.Wrap(errors.CodeCacheError, err)
+		return errors.New(errors.CodeInvalidToken, "")
 	}
-
-	// 删除user_token（反向索引）
-	if claims != nil {
-		userTokenKey := makeUserTokenKey(claims.UserUUID)
-		if err := s.redis.Del(ctx, userTokenKey); err != nil {
-			logger.Warn("从Redis删除user_token失败",
-				logger.String("uuid", claims.UserUUID),
-				logger.Err(err))
-		}
+	if _, err := s.sessions.Revoke(claims.UserUUID, claims.SessionID); err != nil {
+		return err
 	}
 
-	logger.Info("用户登出成功")
+	logger.Info("用户登出成功", logger.String("uuid", claims.UserUUID))
 	return nil
 }
 
-// makeTokenKey 生成token在Redis中的key
-func makeTokenKey(token string) string {
-	return fmt.Sprintf("token:%s", token)
-}
-
-// makeUserTokenKey 生成user_token在Redis中的key（用于反向索引）
-func makeUserTokenKey(userUUID string) string {
-	return fmt.Sprintf("user_token:%s", userUUID)
-}
-
 // RequestPasswordResetRequest 请求密码重置
 type RequestPasswordResetRequest struct {
 	Email string `json:"email" binding:"required,email"`
@@ -603,11 +866,6 @@ type ResetPasswordResponse struct {
 
 // ResetPassword 重置密码（使用token）
 func (s *AuthService) ResetPassword(req *ResetPasswordRequest) (*ResetPasswordResponse, error) {
-	// 验证新密码强度
-	if !crypto.ValidatePasswordStrength(req.NewPassword) {
-		return nil, errors.New(errors.CodeWeakPassword, "")
-	}
-
 	// 查找所有未使用的token记录
 	var tokens []models.PasswordResetToken
 	if err := s.db.Where("used_at IS NULL AND deleted_at IS NULL").Find(&tokens).Error; err != nil {
@@ -657,6 +915,11 @@ func (s *AuthService) ResetPassword(req *ResetPasswordRequest) (*ResetPasswordRe
 		return nil, errors.New(errors.CodeAccountDisabled, "")
 	}
 
+	// 按密码策略校验新密码，不能与最近用过的密码重复
+	if err := s.passwordPolicy.ValidateForUser(&user, req.NewPassword); err != nil {
+		return nil, err
+	}
+
 	// 加密新密码
 	newPasswordHash, err := crypto.HashPassword(req.NewPassword)
 	if err != nil {
@@ -672,8 +935,8 @@ func (s *AuthService) ResetPassword(req *ResetPasswordRequest) (*ResetPasswordRe
 		}
 	}()
 
-	// 更新用户密码
-	if err := tx.Model(&user).Update("password_hash", newPasswordHash).Error; err != nil {
+	// 更新用户密码并记录密码历史
+	if err := s.passwordPolicy.setPassword(tx, &user, newPasswordHash); err != nil {
 		tx.Rollback()
 		logger.Error("更新用户密码失败",
 			logger.String("uuid", user.UUID),
@@ -681,6 +944,15 @@ func (s *AuthService) ResetPassword(req *ResetPasswordRequest) (*ResetPasswordRe
 		return nil, errors.Wrap(errors.CodeDatabaseError, err)
 	}
 
+	// 递增令牌版本，使重置前签发的令牌和登录会话全部失效
+	if err := bumpTokenEpoch(tx, user.UUID); err != nil {
+		tx.Rollback()
+		logger.Error("递增令牌版本失败",
+			logger.String("uuid", user.UUID),
+			logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
 	// 标记token已使用
 	now := time.Now().UTC()
 	if err := tx.Model(matchedToken).Update("used_at", now).Error; err != nil {
@@ -699,6 +971,11 @@ func (s *AuthService) ResetPassword(req *ResetPasswordRequest) (*ResetPasswordRe
 		return nil, errors.Wrap(errors.CodeDatabaseError, err)
 	}
 
+	// 删除全部登录会话，失败不影响结果（令牌版本已递增，会话无法再刷新）
+	if err := revokeAllSessions(s.db, s.redis, user.UUID); err != nil {
+		logger.Warn("删除用户会话失败", logger.String("uuid", user.UUID), logger.Err(err))
+	}
+
 	logger.Info("密码重置成功",
 		logger.String("uuid", user.UUID),
 		logger.String("username", user.Username))
@@ -707,3 +984,70 @@ func (s *AuthService) ResetPassword(req *ResetPasswordRequest) (*ResetPasswordRe
 		Message: "密码重置成功",
 	}, nil
 }
+
+// ChangePasswordRequest 修改登录密码请求
+type ChangePasswordRequest struct {
+	UserUUID    string `json:"-"` // 不从请求体解析，由handler从认证上下文设置
+	SessionUUID string `json:"-"` // 不从请求体解析，由handler从认证上下文设置
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// ChangePasswordResponse 修改登录密码响应
+type ChangePasswordResponse struct {
+	RevokedSessions int `json:"revoked_sessions"` // 被注销的其他登录会话数量
+}
+
+// ChangePassword 已登录用户修改登录密码
+// 需要验证原密码；修改后注销该用户其他设备上的登录会话，当前会话保持有效
+func (s *AuthService) ChangePassword(req *ChangePasswordRequest) (*ChangePasswordResponse, error) {
+	var user models.User
+	if err := s.db.Where("uuid = ?", req.UserUUID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeResourceNotFound, "用户不存在")
+		}
+		logger.Error("查询用户失败", logger.String("uuid", req.UserUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	if !crypto.VerifyPassword(req.OldPassword, user.PasswordHash) {
+		logger.Warn("修改密码时原密码验证失败", logger.String("uuid", user.UUID))
+		return nil, errors.New(errors.CodeInvalidCredentials, "原密码错误")
+	}
+	if err := s.changePassword(&user, req.NewPassword); err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.sessions.RevokeOthers(user.UUID, req.SessionUUID)
+	if err != nil {
+		// 密码已修改，注销其他会话失败不影响结果
+		logger.Warn("注销其他登录会话失败", logger.String("uuid", user.UUID), logger.Err(err))
+	}
+
+	logger.Info("修改密码成功",
+		logger.String("uuid", user.UUID),
+		logger.String("username", user.Username),
+		logger.Int("revoked_sessions", revoked))
+	return &ChangePasswordResponse{RevokedSessions: revoked}, nil
+}
+
+// changePassword 按密码策略校验新密码并保存，同时记录密码历史
+func (s *AuthService) changePassword(user *models.User, newPassword string) error {
+	if err := s.passwordPolicy.ValidateForUser(user, newPassword); err != nil {
+		return err
+	}
+
+	newPasswordHash, err := crypto.HashPassword(newPassword)
+	if err != nil {
+		logger.Error("密码加密失败", logger.Err(err))
+		return errors.Wrap(errors.CodeCryptoError, err)
+	}
+
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		return s.passwordPolicy.setPassword(tx, user, newPasswordHash)
+	}); err != nil {
+		logger.Error("更新用户密码失败", logger.String("uuid", user.UUID), logger.Err(err))
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return nil
+}
@@ -0,0 +1,471 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/backup"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 备份相关限制
+const (
+	maxBackupSecrets = 10000 // 单个备份的最大秘密数
+)
+
+// ConflictStrategy 恢复时同名秘密的冲突处理策略
+type ConflictStrategy string
+
+const (
+	ConflictSkip      ConflictStrategy = "skip"      // 跳过同名秘密
+	ConflictOverwrite ConflictStrategy = "overwrite" // 覆盖最近创建的同名秘密
+	ConflictRename    ConflictStrategy = "rename"    // 以新名称导入
+)
+
+// RestoreItemStatus 单条恢复结果状态
+type RestoreItemStatus string
+
+const (
+	RestoreStatusCreated     RestoreItemStatus = "created"     // 新建
+	RestoreStatusOverwritten RestoreItemStatus = "overwritten" // 覆盖了已有秘密
+	RestoreStatusRenamed     RestoreItemStatus = "renamed"     // 重命名后新建
+	RestoreStatusSkipped     RestoreItemStatus = "skipped"     // 已存在同名秘密，跳过
+	RestoreStatusFailed      RestoreItemStatus = "failed"      // 数据无效或加密失败
+)
+
+// BackupService 秘密备份与恢复服务
+type BackupService struct {
+	db                *gorm.DB
+	encryptionService *EncryptionService
+}
+
+// NewBackupService 创建备份服务实例
+func NewBackupService(db *gorm.DB, encryptionService *EncryptionService) *BackupService {
+	return &BackupService{
+		db:                db,
+		encryptionService: encryptionService,
+	}
+}
+
+// ExportBackupRequest 导出备份请求
+// 注意：UserUUID 由服务端从认证上下文中提取，不需要客户端传入
+type ExportBackupRequest struct {
+	UserUUID    string `json:"-"`                                    // 不从请求体解析，由handler从上下文设置
+	SecurityPIN string `json:"security_pin" binding:"required"`      // 安全密码，用于解密DEK
+	Passphrase  string `json:"passphrase" binding:"required,min=12"` // 备份口令，用于加密备份文件（独立于安全密码）
+}
+
+// ExportBackupResponse 导出备份响应
+type ExportBackupResponse struct {
+	FileName    string                 `json:"file_name"`         // 建议的文件名
+	SecretCount int                    `json:"secret_count"`      // 备份的秘密数量
+	Skipped     []*BackupSkippedSecret `json:"skipped,omitempty"` // 当前无权解密、未导出的秘密
+	Backup      *backup.File           `json:"backup"`            // 备份文件内容，原样保存为JSON文件即可
+}
+
+// BackupSkippedSecret 未导出的秘密
+type BackupSkippedSecret struct {
+	SecretUUID string `json:"secret_uuid"`
+	Name       string `json:"name"`
+	Reason     string `json:"reason"`
+}
+
+// ExportBackup 导出加密备份
+// 用DEK解密用户的全部秘密，再用备份口令派生的密钥整体加密
+// 导出与解密秘密使用相同的访问控制，当前无权解密的秘密（未持有租约的检出秘密、未获批准的高敏感秘密）不导出，在响应中列出
+func (s *BackupService) ExportBackup(req *ExportBackupRequest) (*ExportBackupResponse, error) {
+	var user models.User
+	if err := s.db.Where("uuid = ?", req.UserUUID).First(&user).Error; err != nil {
+		logger.Error("查询用户失败", logger.Err(err), logger.String("user_uuid", req.UserUUID))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	var secrets []models.EncryptedSecret
+	if err := s.db.Where("user_uuid = ?", req.UserUUID).
+		Order("created_at ASC").
+		Limit(maxBackupSecrets + 1).
+		Find(&secrets).Error; err != nil {
+		logger.Error("查询秘密列表失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if len(secrets) > maxBackupSecrets {
+		return nil, errors.New(errors.CodeInvalidParam, "秘密数量超过单个备份上限10000")
+	}
+
+	// 1. 验证安全密码并解密DEK
+	userKey, dek, err := s.encryptionService.unlockDEK(req.UserUUID, req.SecurityPIN)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ClearBytes(dek)
+
+	// 2. 逐个解密秘密
+	payload := &backup.Payload{
+		ExportedAt: time.Now().UTC(),
+		Source:     backup.Source{UserUUID: user.UUID, Username: user.Username},
+		Secrets:    make([]backup.Secret, 0, len(secrets)),
+	}
+	var skipped []*BackupSkippedSecret
+	for i := range secrets {
+		reason, err := s.skipReason(req.UserUUID, &secrets[i])
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			skipped = append(skipped, &BackupSkippedSecret{
+				SecretUUID: secrets[i].SecretUUID,
+				Name:       secrets[i].SecretName,
+				Reason:     reason,
+			})
+			continue
+		}
+		item, err := s.exportSecret(&secrets[i], userKey, dek)
+		if err != nil {
+			return nil, err
+		}
+		payload.Secrets = append(payload.Secrets, *item)
+	}
+
+	// 3. 用备份口令加密
+	file, err := backup.Seal(payload, req.Passphrase)
+	if err != nil {
+		logger.Error("加密备份失败", logger.Err(err), logger.String("user_uuid", req.UserUUID))
+		return nil, errors.WithMessage(errors.CodeEncryptionFailed, "加密备份失败", err)
+	}
+
+	logger.Info("导出秘密备份成功",
+		logger.String("user_uuid", req.UserUUID),
+		logger.Int("secret_count", len(payload.Secrets)),
+		logger.Int("skipped_count", len(skipped)))
+
+	return &ExportBackupResponse{
+		FileName:    fmt.Sprintf("vaulthub-backup-%s-%s.json", user.Username, file.CreatedAt.Format("20060102-150405")),
+		SecretCount: len(payload.Secrets),
+		Skipped:     skipped,
+		Backup:      file,
+	}, nil
+}
+
+// skipReason 返回秘密不能导出的原因，可以导出时返回空字符串
+// 需要检出的秘密只有当前租约持有者能导出，高敏感秘密需要有已批准且仍在解密窗口内的访问申请
+func (s *BackupService) skipReason(userUUID string, secret *models.EncryptedSecret) (string, error) {
+	if secret.CheckoutRequired {
+		checkout, err := findHeldCheckout(s.db, userUUID, secret.SecretUUID)
+		if err != nil {
+			return "", err
+		}
+		if checkout == nil {
+			return "需要检出的秘密只有在持有检出租约时才能导出", nil
+		}
+	}
+	if secret.ApprovalRequired {
+		grant, err := findAccessGrant(s.db, userUUID, secret.SecretUUID)
+		if err != nil {
+			return "", err
+		}
+		if grant == nil {
+			return "高敏感秘密需要审批通过后才能导出", nil
+		}
+	}
+	return "", nil
+}
+
+// exportSecret 解密单个秘密并转换为备份格式
+func (s *BackupService) exportSecret(secret *models.EncryptedSecret, userKey *models.UserEncryptionKey, dek []byte) (*backup.Secret, error) {
+	if secret.DEKVersion != userKey.DEKVersion {
+		logger.Warn("DEK版本不匹配，无法导出", logger.String("secret_uuid", secret.SecretUUID),
+			logger.Int("secret_version", secret.DEKVersion), logger.Int("current_version", userKey.DEKVersion))
+		return nil, errors.New(errors.CodeCryptoError, "存在密钥版本不匹配的秘密，请等待密钥轮换完成后再导出")
+	}
+
+	plainData, err := crypto.DecryptAESGCM(secret.EncryptedData, dek, secret.Nonce, secret.AuthTag)
+	if err != nil {
+		logger.Error("解密秘密数据失败", logger.Err(err), logger.String("secret_uuid", secret.SecretUUID))
+		return nil, errors.WithMessage(errors.CodeDecryptionFailed, "解密秘密失败: "+secret.SecretName, err)
+	}
+
+	var metadata json.RawMessage
+	if secret.Metadata != nil {
+		if metadata, err = json.Marshal(secret.Metadata); err != nil {
+			return nil, errors.Wrap(errors.CodeInternalError, err)
+		}
+	}
+
+	// 当前只保存秘密的最新值，作为唯一的版本导出，版本时间取秘密值最后变更的时间
+	versionAt := secret.UpdatedAt
+	if secret.ValueChangedAt != nil {
+		versionAt = *secret.ValueChangedAt
+	}
+	return &backup.Secret{
+		Name:        secret.SecretName,
+		Type:        string(secret.SecretType),
+		Description: secret.Description,
+		Metadata:    metadata,
+		CreatedAt:   secret.CreatedAt,
+		UpdatedAt:   secret.UpdatedAt,
+		Versions: []backup.Version{
+			{Version: 1, Value: string(plainData), CreatedAt: versionAt},
+		},
+	}, nil
+}
+
+// RestoreBackupRequest 恢复备份请求
+// 注意：UserUUID 由服务端从认证上下文中提取，不需要客户端传入
+type RestoreBackupRequest struct {
+	UserUUID         string           `json:"-"`                                                                 // 不从请求体解析，由handler从上下文设置
+	SecurityPIN      string           `json:"security_pin" binding:"required"`                                   // 当前实例的安全密码，用于加密恢复的秘密
+	Passphrase       string           `json:"passphrase" binding:"required"`                                     // 导出时设置的备份口令
+	Backup           *backup.File     `json:"backup" binding:"required"`                                         // 备份文件内容
+	ConflictStrategy ConflictStrategy `json:"conflict_strategy" binding:"omitempty,oneof=skip overwrite rename"` // 冲突处理策略，默认skip
+}
+
+// RestoreItemResult 单条恢复结果
+type RestoreItemResult struct {
+	Name         string            `json:"name"`                    // 备份中的名称
+	RestoredName string            `json:"restored_name,omitempty"` // 恢复后的名称（重命名时与原名称不同）
+	Status       RestoreItemStatus `json:"status"`
+	SecretUUID   string            `json:"secret_uuid,omitempty"`
+	Error        string            `json:"error,omitempty"`
+}
+
+// RestoreBackupResponse 恢复备份响应
+type RestoreBackupResponse struct {
+	SourceUserUUID string               `json:"source_user_uuid"` // 备份来源用户
+	ExportedAt     time.Time            `json:"exported_at"`
+	Total          int                  `json:"total"`
+	Created        int                  `json:"created"`
+	Overwritten    int                  `json:"overwritten"`
+	Renamed        int                  `json:"renamed"`
+	Skipped        int                  `json:"skipped"`
+	Failed         int                  `json:"failed"`
+	Items          []*RestoreItemResult `json:"items"`
+}
+
+// RestoreBackup 从加密备份恢复秘密
+// 可恢复到同一实例或其他实例，秘密使用当前用户的DEK重新加密，所有写入在同一事务中完成
+func (s *BackupService) RestoreBackup(req *RestoreBackupRequest) (*RestoreBackupResponse, error) {
+	if req.ConflictStrategy == "" {
+		req.ConflictStrategy = ConflictSkip
+	}
+
+	// 1. 用备份口令解密备份文件
+	payload, err := backup.Open(req.Backup, req.Passphrase)
+	if err != nil {
+		logger.Warn("打开备份文件失败", logger.String("user_uuid", req.UserUUID), logger.Err(err))
+		if err == backup.ErrInvalidPassphrase {
+			return nil, errors.New(errors.CodeDecryptionFailed, err.Error())
+		}
+		return nil, errors.WithMessage(errors.CodeInvalidParam, err.Error(), err)
+	}
+	if len(payload.Secrets) > maxBackupSecrets {
+		return nil, errors.New(errors.CodeInvalidParam, "备份中的秘密数量超过上限10000")
+	}
+
+	// 2. 验证安全密码并解密DEK
+	userKey, dek, err := s.encryptionService.unlockDEK(req.UserUUID, req.SecurityPIN)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ClearBytes(dek)
+
+	resp := &RestoreBackupResponse{
+		SourceUserUUID: payload.Source.UserUUID,
+		ExportedAt:     payload.ExportedAt,
+		Total:          len(payload.Secrets),
+		Items:          make([]*RestoreItemResult, 0, len(payload.Secrets)),
+	}
+
+	// 3. 在一个事务中逐条恢复
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		existing, err := s.loadExistingByName(tx, req.UserUUID)
+		if err != nil {
+			return err
+		}
+		for i := range payload.Secrets {
+			result, err := s.restoreSecret(tx, req, userKey, dek, &payload.Secrets[i], existing)
+			if err != nil {
+				return err
+			}
+			resp.Items = append(resp.Items, result)
+		}
+		return nil
+	})
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return nil, appErr
+		}
+		logger.Error("恢复备份失败", logger.Err(err), logger.String("user_uuid", req.UserUUID))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	for _, item := range resp.Items {
+		switch item.Status {
+		case RestoreStatusCreated:
+			resp.Created++
+		case RestoreStatusOverwritten:
+			resp.Overwritten++
+		case RestoreStatusRenamed:
+			resp.Renamed++
+		case RestoreStatusSkipped:
+			resp.Skipped++
+		case RestoreStatusFailed:
+			resp.Failed++
+		}
+	}
+
+	logger.Info("恢复秘密备份完成",
+		logger.String("user_uuid", req.UserUUID),
+		logger.String("source_user_uuid", payload.Source.UserUUID),
+		logger.String("conflict_strategy", string(req.ConflictStrategy)),
+		logger.Int("total", resp.Total),
+		logger.Int("created", resp.Created),
+		logger.Int("overwritten", resp.Overwritten),
+		logger.Int("renamed", resp.Renamed),
+		logger.Int("skipped", resp.Skipped),
+		logger.Int("failed", resp.Failed))
+
+	return resp, nil
+}
+
+// restoreSecret 恢复单个秘密
+// 返回的error表示需要回滚整个事务的数据库错误，单条数据问题记录在结果中
+func (s *BackupService) restoreSecret(tx *gorm.DB, req *RestoreBackupRequest, userKey *models.UserEncryptionKey,
+	dek []byte, item *backup.Secret, existing map[string]*models.EncryptedSecret) (*RestoreItemResult, error) {
+	result := &RestoreItemResult{Name: item.Name}
+
+	record, errMsg := buildRestoreRecord(item)
+	if errMsg != "" {
+		result.Status = RestoreStatusFailed
+		result.Error = errMsg
+		return result, nil
+	}
+
+	current := item.Current()
+	encryptedData, nonce, authTag, err := crypto.EncryptAESGCM([]byte(current.Value), dek)
+	if err != nil {
+		logger.Error("加密恢复秘密失败", logger.Err(err), logger.String("name", item.Name))
+		result.Status = RestoreStatusFailed
+		result.Error = "加密失败"
+		return result, nil
+	}
+	fingerprint, strength, err := s.encryptionService.passwordHealth(record.SecretType, []byte(current.Value), dek)
+	if err != nil {
+		result.Status = RestoreStatusFailed
+		result.Error = "计算密码指纹失败"
+		return result, nil
+	}
+	changedAt := current.CreatedAt
+	if changedAt.IsZero() {
+		changedAt = time.Now()
+	}
+	record.UserUUID = req.UserUUID
+	record.Metadata = withStrength(record.Metadata, strength)
+	record.PasswordFingerprint = fingerprint
+	record.ValueChangedAt = &changedAt
+	record.EncryptedData = encryptedData
+	record.DEKVersion = userKey.DEKVersion
+	record.Nonce = nonce
+	record.AuthTag = authTag
+
+	conflict, exists := existing[item.Name]
+	switch {
+	case !exists:
+		result.Status = RestoreStatusCreated
+	case req.ConflictStrategy == ConflictSkip:
+		result.Status = RestoreStatusSkipped
+		result.Error = "已存在同名秘密"
+		return result, nil
+	case req.ConflictStrategy == ConflictOverwrite:
+		if err := tx.Model(conflict).
+			Select("secret_type", "description", "encrypted_data", "dek_version", "nonce", "auth_tag", "metadata",
+				"password_fingerprint", "value_changed_at").
+			Updates(record).Error; err != nil {
+			return nil, err
+		}
+		if err := resealServiceAccountGrants(tx, conflict.SecretUUID, []byte(current.Value)); err != nil {
+			return nil, err
+		}
+		result.Status = RestoreStatusOverwritten
+		result.RestoredName = conflict.SecretName
+		result.SecretUUID = conflict.SecretUUID
+		return result, nil
+	default:
+		record.SecretName = uniqueRestoreName(item.Name, existing)
+		result.Status = RestoreStatusRenamed
+	}
+
+	record.SecretUUID = uuid.New().String()
+	if err := tx.Create(record).Error; err != nil {
+		return nil, err
+	}
+	existing[record.SecretName] = record
+	result.RestoredName = record.SecretName
+	result.SecretUUID = record.SecretUUID
+	return result, nil
+}
+
+// loadExistingByName 加载用户已有秘密，同名时保留最近创建的一条
+func (s *BackupService) loadExistingByName(tx *gorm.DB, userUUID string) (map[string]*models.EncryptedSecret, error) {
+	var secrets []models.EncryptedSecret
+	if err := tx.Select("id", "secret_uuid", "secret_name", "created_at").
+		Where("user_uuid = ?", userUUID).
+		Order("created_at ASC").
+		Find(&secrets).Error; err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]*models.EncryptedSecret, len(secrets))
+	for i := range secrets {
+		existing[secrets[i].SecretName] = &secrets[i]
+	}
+	return existing, nil
+}
+
+// buildRestoreRecord 校验备份条目并构造待写入的记录（不含加密字段）
+func buildRestoreRecord(item *backup.Secret) (*models.EncryptedSecret, string) {
+	if item.Name == "" {
+		return nil, "名称不能为空"
+	}
+	if len(item.Name) > 255 {
+		return nil, "名称长度不能超过255"
+	}
+	secretType := models.SecretType(item.Type)
+	if !isValidSecretType(secretType) {
+		return nil, "不支持的秘密类型: " + item.Type
+	}
+	current := item.Current()
+	if current == nil || current.Value == "" {
+		return nil, "缺少秘密内容"
+	}
+
+	record := &models.EncryptedSecret{
+		SecretName:  item.Name,
+		SecretType:  secretType,
+		Description: item.Description,
+	}
+	if len(item.Metadata) > 0 && string(item.Metadata) != "null" {
+		var metadata models.SecretMetadata
+		if err := json.Unmarshal(item.Metadata, &metadata); err != nil {
+			return nil, "元数据格式无效"
+		}
+		record.Metadata = &metadata
+	}
+	return record, ""
+}
+
+// uniqueRestoreName 生成不与已有秘密重名的名称
+func uniqueRestoreName(name string, existing map[string]*models.EncryptedSecret) string {
+	candidate := name + " (restored)"
+	for i := 2; ; i++ {
+		if _, ok := existing[candidate]; !ok {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s (restored %d)", name, i)
+	}
+}
@@ -0,0 +1,735 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/config"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	redisClient "github.com/cuihe500/vaulthub/pkg/redis"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 秘密检出相关常量
+const (
+	checkoutLockPrefix      = "checkout:lock:"
+	checkoutExpireBatchSize = 500 // 定时释放每批处理的记录数
+	checkoutHistoryLimit    = 10  // 查询检出状态时返回的历史记录条数
+)
+
+// releaseCheckoutLockScript 只有租约仍属于当前检出时才删除锁，避免误删新的租约
+const releaseCheckoutLockScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`
+
+// CheckoutService 特权凭据检出服务
+// 启用检出的秘密不能直接解密，必须检出后在租约期内独占使用：
+//   - 独占锁是Redis中带过期时间的租约（SET NX），到期自动失效
+//   - secret_checkouts 表记录持有者和检出历史，供他人查看当前持有者
+//   - 检入、超时或管理员强制释放后秘密被标记为待轮换；关联了密码生成规则的秘密在检入时提供安全密码即可自动轮换
+type CheckoutService struct {
+	db                *gorm.DB
+	redis             *redisClient.Client
+	encryptionService *EncryptionService
+	configManager     *config.ConfigManager
+	auditService      *AuditService
+}
+
+// NewCheckoutService 创建检出服务实例
+func NewCheckoutService(db *gorm.DB, redis *redisClient.Client, encryptionService *EncryptionService, configManager *config.ConfigManager, auditService *AuditService) *CheckoutService {
+	return &CheckoutService{
+		db:                db,
+		redis:             redis,
+		encryptionService: encryptionService,
+		configManager:     configManager,
+		auditService:      auditService,
+	}
+}
+
+// makeCheckoutLockKey 生成检出租约在Redis中的key
+func makeCheckoutLockKey(secretUUID string) string {
+	return checkoutLockPrefix + secretUUID
+}
+
+// durationLimits 读取检出默认时长和最长时长，配置无效时使用默认值
+func (s *CheckoutService) durationLimits() (time.Duration, time.Duration) {
+	parse := func(key, defaultValue string) time.Duration {
+		value := s.configManager.GetWithDefault(key, defaultValue)
+		minutes, err := strconv.Atoi(value)
+		if err != nil || minutes <= 0 {
+			logger.Warn("检出时长配置无效，使用默认值", logger.String("key", key), logger.String("value", value))
+			minutes, _ = strconv.Atoi(defaultValue)
+		}
+		return time.Duration(minutes) * time.Minute
+	}
+	return parse(models.ConfigKeySecretCheckoutDefaultMinutes, models.ConfigValueSecretCheckoutDefaultMinutesDefault),
+		parse(models.ConfigKeySecretCheckoutMaxMinutes, models.ConfigValueSecretCheckoutMaxMinutesDefault)
+}
+
+// getSecret 查询用户的秘密
+func (s *CheckoutService) getSecret(userUUID, secretUUID string) (*models.EncryptedSecret, error) {
+	var secret models.EncryptedSecret
+	if err := s.db.Where("user_uuid = ? AND secret_uuid = ?", userUUID, secretUUID).First(&secret).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Warn("秘密不存在或无权访问", logger.String("user_uuid", userUUID), logger.String("secret_uuid", secretUUID))
+			return nil, errors.New(errors.CodeResourceNotFound, "秘密不存在或无权访问")
+		}
+		logger.Error("查询秘密失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return &secret, nil
+}
+
+// getActiveCheckout 查询秘密当前的检出记录，不存在时返回nil
+func (s *CheckoutService) getActiveCheckout(secretUUID string) (*models.SecretCheckout, error) {
+	var checkout models.SecretCheckout
+	err := s.db.Where("secret_uuid = ? AND status = ?", secretUUID, models.CheckoutActive).
+		Order("id DESC").First(&checkout).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error("查询检出记录失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return &checkout, nil
+}
+
+// findHeldCheckout 查询用户持有且尚未到期的检出记录，不存在时返回nil
+func findHeldCheckout(db *gorm.DB, userUUID, secretUUID string) (*models.SecretCheckout, error) {
+	var checkout models.SecretCheckout
+	err := db.Where("secret_uuid = ? AND holder_uuid = ? AND status = ? AND expires_at > ?",
+		secretUUID, userUUID, models.CheckoutActive, time.Now()).
+		Order("id DESC").First(&checkout).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error("查询检出记录失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return &checkout, nil
+}
+
+// requireHeldCheckout 需要检出的秘密只有当前租约持有者能读取明文，其他秘密直接通过
+// 附件下载等不经过检出接口读取明文的路径使用该函数，保证检出的独占性
+func requireHeldCheckout(db *gorm.DB, userUUID string, secret *models.EncryptedSecret) error {
+	if !secret.CheckoutRequired {
+		return nil
+	}
+	checkout, err := findHeldCheckout(db, userUUID, secret.SecretUUID)
+	if err != nil {
+		return err
+	}
+	if checkout == nil {
+		logger.Warn("秘密需要检出后访问", logger.String("user_uuid", userUUID), logger.String("secret_uuid", secret.SecretUUID))
+		return errors.New(errors.CodeOperationNotAllowed, "该秘密需要检出后才能访问")
+	}
+	return nil
+}
+
+// SetCheckoutPolicyRequest 设置检出策略请求
+type SetCheckoutPolicyRequest struct {
+	UserUUID         string                    `json:"-"`                 // 不从请求体解析，由handler从上下文设置
+	SecretUUID       string                    `json:"-"`                 // 不从请求体解析，由handler从URL路径设置
+	CheckoutRequired bool                      `json:"checkout_required"` // 是否需要检出后才能访问
+	Generator        *models.PasswordGenerator `json:"generator"`         // 密码生成规则，为空时移除
+}
+
+// SetCheckoutPolicy 设置秘密的检出策略和密码生成规则
+func (s *CheckoutService) SetCheckoutPolicy(req *SetCheckoutPolicyRequest) (*models.SafeEncryptedSecret, error) {
+	secret, err := s.getSecret(req.UserUUID, req.SecretUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 检出中不允许关闭检出，避免持有者之外的人绕过租约直接解密
+	if !req.CheckoutRequired {
+		active, err := s.getActiveCheckout(req.SecretUUID)
+		if err != nil {
+			return nil, err
+		}
+		if active != nil {
+			return nil, errors.New(errors.CodeResourceLocked, "秘密正在被检出，请检入后再关闭检出")
+		}
+	}
+
+	metadata := secret.Metadata
+	if metadata == nil {
+		metadata = &models.SecretMetadata{}
+	}
+	metadata.Generator = req.Generator
+
+	if err := s.db.Model(secret).Updates(map[string]interface{}{
+		"checkout_required": req.CheckoutRequired,
+		"metadata":          metadata,
+	}).Error; err != nil {
+		logger.Error("更新检出策略失败", logger.Err(err), logger.String("secret_uuid", req.SecretUUID))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	secret.CheckoutRequired = req.CheckoutRequired
+	secret.Metadata = metadata
+
+	logger.Info("更新检出策略成功",
+		logger.String("user_uuid", req.UserUUID),
+		logger.String("secret_uuid", req.SecretUUID),
+		logger.Bool("checkout_required", req.CheckoutRequired))
+	return secret.ToSafe(), nil
+}
+
+// CheckoutRequest 检出秘密请求
+type CheckoutRequest struct {
+	UserUUID        string `json:"-"`                                          // 不从请求体解析，由handler从上下文设置
+	Username        string `json:"-"`                                          // 持有者用户名，由handler从上下文设置
+	SecretUUID      string `json:"-"`                                          // 不从请求体解析，由handler从URL路径设置
+	IPAddress       string `json:"-"`                                          // 客户端IP，记录到检出记录
+	UserAgent       string `json:"-"`                                          // 客户端User-Agent，记录到检出记录
+	SecurityPIN     string `json:"security_pin" binding:"required"`            // 安全密码
+	DurationMinutes int    `json:"duration_minutes" binding:"omitempty,min=1"` // 检出时长（分钟），默认取系统配置
+	Reason          string `json:"reason" binding:"omitempty,max=255"`         // 检出原因
+}
+
+// CheckoutResponse 检出秘密响应
+type CheckoutResponse struct {
+	Checkout  *models.SecretCheckout `json:"checkout"`
+	PlainData string                 `json:"plain_data"` // 解密后的明文
+}
+
+// Checkout 检出秘密，获得租约期内的独占访问
+func (s *CheckoutService) Checkout(req *CheckoutRequest) (*CheckoutResponse, error) {
+	// 1. 检查秘密和检出策略
+	secret, err := s.getSecret(req.UserUUID, req.SecretUUID)
+	if err != nil {
+		return nil, err
+	}
+	if !secret.CheckoutRequired {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "该秘密未启用检出，可直接解密")
+	}
+
+	// 2. 计算租约时长
+	defaultDuration, maxDuration := s.durationLimits()
+	duration := defaultDuration
+	if req.DurationMinutes > 0 {
+		duration = time.Duration(req.DurationMinutes) * time.Minute
+	}
+	if duration > maxDuration {
+		return nil, errors.New(errors.CodeParamOutOfRange, "检出时长不能超过"+strconv.Itoa(int(maxDuration/time.Minute))+"分钟")
+	}
+
+	// 3. 先验证安全密码，避免密码错误也占用租约
+	if _, err := s.encryptionService.verifySecurityPIN(req.UserUUID, req.SecurityPIN); err != nil {
+		return nil, err
+	}
+
+	// 4. 获取独占租约
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	checkoutUUID := uuid.New().String()
+	lockKey := makeCheckoutLockKey(req.SecretUUID)
+	acquired, err := s.redis.SetNX(ctx, lockKey, checkoutUUID, duration)
+	if err != nil {
+		logger.Error("获取检出租约失败", logger.Err(err), logger.String("secret_uuid", req.SecretUUID))
+		return nil, errors.Wrap(errors.CodeCacheError, err)
+	}
+	if !acquired {
+		active, err := s.getActiveCheckout(req.SecretUUID)
+		if err != nil {
+			return nil, err
+		}
+		if active != nil {
+			return nil, errors.New(errors.CodeResourceLocked,
+				"秘密已被 "+active.HolderUsername+" 检出，租约到期时间 "+active.ExpiresAt.Format(time.DateTime))
+		}
+		return nil, errors.New(errors.CodeResourceLocked, "秘密正在被检出，请稍后重试")
+	}
+	release := func() {
+		if _, err := s.redis.Eval(context.Background(), releaseCheckoutLockScript, []string{lockKey}, checkoutUUID); err != nil {
+			logger.Error("释放检出租约失败", logger.Err(err), logger.String("secret_uuid", req.SecretUUID))
+		}
+	}
+
+	// 5. 租约已过期但定时任务尚未处理的旧记录，先按超时释放
+	if _, err := s.expireCheckouts(s.db.Where("secret_uuid = ?", req.SecretUUID)); err != nil {
+		release()
+		return nil, err
+	}
+
+	// 6. 解密秘密
+	decrypted, err := s.encryptionService.DecryptSecret(&DecryptSecretRequest{
+		UserUUID:    req.UserUUID,
+		SecretUUID:  req.SecretUUID,
+		SecurityPIN: req.SecurityPIN,
+		checkedOut:  true,
+	})
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	// 7. 记录检出
+	now := time.Now()
+	checkout := &models.SecretCheckout{
+		CheckoutUUID:    checkoutUUID,
+		SecretUUID:      req.SecretUUID,
+		OwnerUUID:       secret.UserUUID,
+		HolderUUID:      req.UserUUID,
+		HolderUsername:  req.Username,
+		HolderIP:        req.IPAddress,
+		HolderUserAgent: truncateString(req.UserAgent, 512),
+		Reason:          req.Reason,
+		Status:          models.CheckoutActive,
+		CheckedOutAt:    now,
+		ExpiresAt:       now.Add(duration),
+	}
+	if err := s.db.Create(checkout).Error; err != nil {
+		release()
+		logger.Error("保存检出记录失败", logger.Err(err), logger.String("secret_uuid", req.SecretUUID))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	logger.Info("检出秘密成功",
+		logger.String("user_uuid", req.UserUUID),
+		logger.String("secret_uuid", req.SecretUUID),
+		logger.String("checkout_uuid", checkoutUUID),
+		logger.Int("duration_minutes", int(duration/time.Minute)))
+
+	return &CheckoutResponse{
+		Checkout:  checkout,
+		PlainData: decrypted.PlainData,
+	}, nil
+}
+
+// CheckinRequest 检入秘密请求
+type CheckinRequest struct {
+	UserUUID     string `json:"-"`                                     // 不从请求体解析，由handler从上下文设置
+	Username     string `json:"-"`                                     // 由handler从上下文设置
+	SecretUUID   string `json:"-"`                                     // 不从请求体解析，由handler从URL路径设置
+	CheckoutUUID string `json:"checkout_uuid" binding:"required,uuid"` // 检出时返回的检出UUID
+	SecurityPIN  string `json:"security_pin"`                          // 可选，秘密关联了密码生成规则时提供则自动轮换
+}
+
+// CheckinResponse 检入秘密响应
+type CheckinResponse struct {
+	Checkout         *models.SecretCheckout `json:"checkout"`
+	Rotated          bool                   `json:"rotated"`           // 是否已自动轮换
+	RotationRequired bool                   `json:"rotation_required"` // 是否仍待轮换
+}
+
+// Checkin 检入秘密，释放租约并标记待轮换
+func (s *CheckoutService) Checkin(req *CheckinRequest) (*CheckinResponse, error) {
+	secret, err := s.getSecret(req.UserUUID, req.SecretUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var checkout models.SecretCheckout
+	if err := s.db.Where("checkout_uuid = ? AND secret_uuid = ? AND holder_uuid = ? AND status = ?",
+		req.CheckoutUUID, req.SecretUUID, req.UserUUID, models.CheckoutActive).
+		First(&checkout).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeResourceNotFound, "检出记录不存在或已释放")
+		}
+		logger.Error("查询检出记录失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	// 租约已过期的按超时释放处理
+	if !checkout.ExpiresAt.After(time.Now()) {
+		if _, err := s.expireCheckouts(s.db.Where("id = ?", checkout.ID)); err != nil {
+			return nil, err
+		}
+		return nil, errors.New(errors.CodeResourceNotFound, "检出已超时释放")
+	}
+
+	// 需要自动轮换时先验证安全密码，密码错误不释放租约
+	autoRotate := req.SecurityPIN != "" && secret.Metadata != nil && secret.Metadata.Generator != nil
+	if autoRotate {
+		if _, err := s.encryptionService.verifySecurityPIN(req.UserUUID, req.SecurityPIN); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.release(&checkout, models.CheckoutCheckedIn, req.Username); err != nil {
+		return nil, err
+	}
+
+	resp := &CheckinResponse{Checkout: &checkout, RotationRequired: true}
+	if autoRotate {
+		gen := secret.Metadata.Generator
+		if err := s.rotate(req.UserUUID, secret, req.SecurityPIN, "", gen); err != nil {
+			// 检入已完成，轮换失败时保留待轮换标记
+			logger.Error("检入后自动轮换失败", logger.Err(err), logger.String("secret_uuid", req.SecretUUID))
+		} else {
+			resp.Rotated = true
+			resp.RotationRequired = false
+		}
+	}
+
+	logger.Info("检入秘密成功",
+		logger.String("user_uuid", req.UserUUID),
+		logger.String("secret_uuid", req.SecretUUID),
+		logger.String("checkout_uuid", req.CheckoutUUID),
+		logger.Bool("rotated", resp.Rotated))
+	return resp, nil
+}
+
+// ForceReleaseRequest 管理员强制释放检出请求
+type ForceReleaseRequest struct {
+	AdminUsername string `json:"-"`                                  // 由handler从上下文设置
+	SecretUUID    string `json:"-"`                                  // 不从请求体解析，由handler从URL路径设置
+	Reason        string `json:"reason" binding:"omitempty,max=255"` // 强制释放原因，记录到审计日志
+}
+
+// ForceRelease 管理员强制释放秘密的检出，秘密被标记为待轮换
+func (s *CheckoutService) ForceRelease(req *ForceReleaseRequest) (*models.SecretCheckout, error) {
+	checkout, err := s.getActiveCheckout(req.SecretUUID)
+	if err != nil {
+		return nil, err
+	}
+	if checkout == nil {
+		return nil, errors.New(errors.CodeResourceNotFound, "秘密当前未被检出")
+	}
+
+	if err := s.release(checkout, models.CheckoutForceReleased, req.AdminUsername); err != nil {
+		return nil, err
+	}
+
+	logger.Info("强制释放检出成功",
+		logger.String("admin", req.AdminUsername),
+		logger.String("secret_uuid", req.SecretUUID),
+		logger.String("holder", checkout.HolderUsername))
+	return checkout, nil
+}
+
+// CheckoutStatusResponse 检出状态响应
+type CheckoutStatusResponse struct {
+	SecretUUID       string                   `json:"secret_uuid"`
+	CheckoutRequired bool                     `json:"checkout_required"`
+	RotationRequired bool                     `json:"rotation_required"`
+	Active           *models.SecretCheckout   `json:"active"`  // 当前检出（未检出时为null）
+	History          []*models.SecretCheckout `json:"history"` // 最近的检出记录
+}
+
+// GetCheckoutStatus 查询秘密当前的持有者和最近的检出记录
+func (s *CheckoutService) GetCheckoutStatus(userUUID, secretUUID string) (*CheckoutStatusResponse, error) {
+	secret, err := s.getSecret(userUUID, secretUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.getActiveCheckout(secretUUID)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil && !active.ExpiresAt.After(time.Now()) {
+		// 租约已过期，等待定时任务释放
+		active = nil
+	}
+
+	var history []*models.SecretCheckout
+	if err := s.db.Where("secret_uuid = ?", secretUUID).
+		Order("id DESC").Limit(checkoutHistoryLimit).
+		Find(&history).Error; err != nil {
+		logger.Error("查询检出历史失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	return &CheckoutStatusResponse{
+		SecretUUID:       secretUUID,
+		CheckoutRequired: secret.CheckoutRequired,
+		RotationRequired: secret.RotationRequired,
+		Active:           active,
+		History:          history,
+	}, nil
+}
+
+// ListCheckoutsRequest 管理员查询检出记录请求
+type ListCheckoutsRequest struct {
+	Status    models.CheckoutStatus `form:"status" binding:"omitempty,oneof=active checked_in expired force_released"`
+	OwnerUUID string                `form:"owner_uuid" binding:"omitempty,uuid"`
+	Page      int                   `form:"page" binding:"omitempty,min=1"`
+	PageSize  int                   `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// ListCheckoutsResponse 管理员查询检出记录响应
+type ListCheckoutsResponse struct {
+	Checkouts []*models.SecretCheckout `json:"checkouts"`
+	Total     int64                    `json:"total"`
+	Page      int                      `json:"page"`
+	PageSize  int                      `json:"page_size"`
+}
+
+// ListCheckouts 查询所有用户的检出记录
+func (s *CheckoutService) ListCheckouts(req *ListCheckoutsRequest) (*ListCheckoutsResponse, error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+
+	query := s.db.Model(&models.SecretCheckout{})
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.OwnerUUID != "" {
+		query = query.Where("owner_uuid = ?", req.OwnerUUID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error("统计检出记录失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	var checkouts []*models.SecretCheckout
+	if err := query.Order("id DESC").
+		Offset((req.Page - 1) * req.PageSize).Limit(req.PageSize).
+		Find(&checkouts).Error; err != nil {
+		logger.Error("查询检出记录失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	return &ListCheckoutsResponse{
+		Checkouts: checkouts,
+		Total:     total,
+		Page:      req.Page,
+		PageSize:  req.PageSize,
+	}, nil
+}
+
+// RotateSecretRequest 轮换秘密请求
+type RotateSecretRequest struct {
+	UserUUID    string `json:"-"`                               // 不从请求体解析，由handler从上下文设置
+	SecretUUID  string `json:"-"`                               // 不从请求体解析，由handler从URL路径设置
+	SecurityPIN string `json:"security_pin" binding:"required"` // 安全密码
+	PlainData   string `json:"plain_data"`                      // 新的明文，为空时按密码生成规则生成
+}
+
+// RotateSecret 更新秘密的明文并清除待轮换标记
+// 检出中的秘密不能轮换，避免持有者手中的凭据失效
+func (s *CheckoutService) RotateSecret(req *RotateSecretRequest) (*models.SafeEncryptedSecret, error) {
+	secret, err := s.getSecret(req.UserUUID, req.SecretUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var gen *models.PasswordGenerator
+	if req.PlainData == "" {
+		if secret.Metadata == nil || secret.Metadata.Generator == nil {
+			return nil, errors.New(errors.CodeInvalidParam, "秘密未关联密码生成规则，请提供plain_data")
+		}
+		gen = secret.Metadata.Generator
+	}
+
+	active, err := s.getActiveCheckout(req.SecretUUID)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil && active.ExpiresAt.After(time.Now()) {
+		return nil, errors.New(errors.CodeResourceLocked, "秘密已被 "+active.HolderUsername+" 检出，请检入后再轮换")
+	}
+
+	if err := s.rotate(req.UserUUID, secret, req.SecurityPIN, req.PlainData, gen); err != nil {
+		return nil, err
+	}
+
+	logger.Info("轮换秘密成功",
+		logger.String("user_uuid", req.UserUUID),
+		logger.String("secret_uuid", req.SecretUUID),
+		logger.Bool("generated", gen != nil))
+	return secret.ToSafe(), nil
+}
+
+// ExpireOverdue 释放所有租约已过期的检出，供定时任务调用
+func (s *CheckoutService) ExpireOverdue() (int, error) {
+	return s.expireCheckouts(s.db)
+}
+
+// expireCheckouts 按超时释放满足条件且已过期的检出，并以system身份记录审计日志
+func (s *CheckoutService) expireCheckouts(scope *gorm.DB) (int, error) {
+	expired := 0
+	for {
+		var checkouts []*models.SecretCheckout
+		if err := scope.Session(&gorm.Session{}).
+			Where("status = ? AND expires_at <= ?", models.CheckoutActive, time.Now()).
+			Limit(checkoutExpireBatchSize).
+			Find(&checkouts).Error; err != nil {
+			logger.Error("查询过期检出失败", logger.Err(err))
+			return expired, errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		if len(checkouts) == 0 {
+			break
+		}
+
+		for _, checkout := range checkouts {
+			if err := s.release(checkout, models.CheckoutExpired, systemAuditUsername); err != nil {
+				if appErr, ok := err.(*errors.AppError); ok && appErr.Code == errors.CodeResourceNotFound {
+					continue // 已被持有者检入或其他实例释放
+				}
+				return expired, err
+			}
+			expired++
+			s.logExpireAudit(checkout)
+		}
+
+		if len(checkouts) < checkoutExpireBatchSize {
+			break
+		}
+	}
+
+	if expired > 0 {
+		logger.Info("释放超时检出", logger.Int("count", expired))
+	}
+	return expired, nil
+}
+
+// release 释放检出：更新记录状态、标记秘密待轮换并删除Redis租约
+func (s *CheckoutService) release(checkout *models.SecretCheckout, status models.CheckoutStatus, releasedBy string) error {
+	now := time.Now()
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 带状态条件更新，防止并发释放同一条记录
+		result := tx.Model(&models.SecretCheckout{}).
+			Where("id = ? AND status = ?", checkout.ID, models.CheckoutActive).
+			Updates(map[string]interface{}{
+				"status":      status,
+				"released_at": now,
+				"released_by": releasedBy,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New(errors.CodeResourceNotFound, "检出记录不存在或已释放")
+		}
+
+		// 持有者可能已经记下凭据，释放后需要轮换（秘密在回收站中时同样标记）
+		return tx.Unscoped().Model(&models.EncryptedSecret{}).
+			Where("secret_uuid = ?", checkout.SecretUUID).
+			Update("rotation_required", true).Error
+	})
+	if err != nil {
+		if _, ok := err.(*errors.AppError); ok {
+			return err
+		}
+		logger.Error("释放检出失败", logger.Err(err), logger.String("checkout_uuid", checkout.CheckoutUUID))
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if _, err := s.redis.Eval(ctx, releaseCheckoutLockScript, []string{makeCheckoutLockKey(checkout.SecretUUID)}, checkout.CheckoutUUID); err != nil {
+		// 租约自带过期时间，删除失败只会延迟下一次检出
+		logger.Error("释放检出租约失败", logger.Err(err), logger.String("secret_uuid", checkout.SecretUUID))
+	}
+
+	checkout.Status = status
+	checkout.ReleasedAt = &now
+	checkout.ReleasedBy = releasedBy
+	return nil
+}
+
+// rotate 用新的明文重新加密秘密并清除待轮换标记
+// plainData为空时按生成规则生成随机密码
+func (s *CheckoutService) rotate(userUUID string, secret *models.EncryptedSecret, securityPIN, plainData string, gen *models.PasswordGenerator) error {
+	if plainData == "" {
+		generated, err := crypto.GeneratePassword(gen.Length, gen.Symbols)
+		if err != nil {
+			return err
+		}
+		plainData = generated
+	}
+
+	userKey, dek, err := s.encryptionService.unlockDEK(userUUID, securityPIN)
+	if err != nil {
+		return err
+	}
+	defer crypto.ClearBytes(dek)
+
+	plaintext := []byte(plainData)
+	defer crypto.ClearBytes(plaintext)
+	encryptedData, nonce, authTag, err := crypto.EncryptAESGCM(plaintext, dek)
+	if err != nil {
+		logger.Error("加密秘密数据失败", logger.Err(err))
+		return err
+	}
+
+	fingerprint, strength, err := s.encryptionService.passwordHealth(secret.SecretType, plaintext, dek)
+	if err != nil {
+		return err
+	}
+	metadata := withStrength(secret.Metadata, strength)
+
+	now := time.Now()
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(secret).Updates(map[string]interface{}{
+			"encrypted_data":       encryptedData,
+			"nonce":                nonce,
+			"auth_tag":             authTag,
+			"dek_version":          userKey.DEKVersion,
+			"rotation_required":    false,
+			"password_fingerprint": fingerprint,
+			"metadata":             metadata,
+			"value_changed_at":     now,
+		}).Error; err != nil {
+			return errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		// 被授权的服务账户持有的是明文副本，需要随之更新
+		return resealServiceAccountGrants(tx, secret.SecretUUID, plaintext)
+	})
+	if err != nil {
+		logger.Error("保存轮换后的秘密失败", logger.Err(err), logger.String("secret_uuid", secret.SecretUUID))
+		return err
+	}
+	secret.DEKVersion = userKey.DEKVersion
+	secret.RotationRequired = false
+	secret.PasswordFingerprint = fingerprint
+	secret.Metadata = metadata
+	secret.ValueChangedAt = &now
+	return nil
+}
+
+// logExpireAudit 以system身份记录超时释放审计日志
+func (s *CheckoutService) logExpireAudit(checkout *models.SecretCheckout) {
+	if s.auditService == nil {
+		return
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"operation":     "checkout_timeout",
+		"checkout_uuid": checkout.CheckoutUUID,
+		"holder":        checkout.HolderUsername,
+		"expires_at":    checkout.ExpiresAt,
+	})
+	secretUUID := checkout.SecretUUID
+	s.auditService.LogAsync(&models.AuditLog{
+		UserUUID:     checkout.OwnerUUID,
+		Username:     systemAuditUsername,
+		ActionType:   models.ActionCheckin,
+		ResourceType: models.ResourceSecret,
+		ResourceUUID: &secretUUID,
+		Status:       models.AuditSuccess,
+		Details:      string(details),
+		CreatedAt:    time.Now().UTC(),
+	})
+}
+
+// truncateString 按字节截断字符串（保证不截断多字节字符）
+func truncateString(value string, maxBytes int) string {
+	if len(value) <= maxBytes {
+		return value
+	}
+	for maxBytes > 0 && value[maxBytes]&0xC0 == 0x80 {
+		maxBytes--
+	}
+	return value[:maxBytes]
+}
@@ -290,3 +290,42 @@ func (s *EmailService) SendPasswordResetLink(emailAddr string, resetURL string,
 	sender := email.NewSender(emailConfig)
 	return sender.SendPasswordResetLink(emailAddr, resetURL, expiryMinutes)
 }
+
+// SendAccessApprovalRequest 发送高敏感秘密访问审批邮件
+func (s *EmailService) SendAccessApprovalRequest(emailAddr, requester, secretName, reason, approvalURL string, expiryHours int) error {
+	// 获取邮件配置
+	emailConfig, err := s.getEmailConfig()
+	if err != nil {
+		return err
+	}
+
+	// 创建邮件发送器并发送
+	sender := email.NewSender(emailConfig)
+	return sender.SendAccessApprovalRequest(emailAddr, requester, secretName, reason, approvalURL, expiryHours)
+}
+
+// SendEmergencyAccessNotice 发送紧急访问通知邮件
+func (s *EmailService) SendEmergencyAccessNotice(emailAddr, title, message string) error {
+	// 获取邮件配置
+	emailConfig, err := s.getEmailConfig()
+	if err != nil {
+		return err
+	}
+
+	// 创建邮件发送器并发送
+	sender := email.NewSender(emailConfig)
+	return sender.SendEmergencyAccessNotice(emailAddr, title, message)
+}
+
+// SendAccountLockedNotice 发送账户锁定通知邮件
+func (s *EmailService) SendAccountLockedNotice(emailAddr, username, ipAddress, unlockAt string) error {
+	// 获取邮件配置
+	emailConfig, err := s.getEmailConfig()
+	if err != nil {
+		return err
+	}
+
+	// 创建邮件发送器并发送
+	sender := email.NewSender(emailConfig)
+	return sender.SendAccountLockedNotice(emailAddr, username, ipAddress, unlockAt)
+}
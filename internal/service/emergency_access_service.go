@@ -0,0 +1,841 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/config"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	redisClient "github.com/cuihe500/vaulthub/pkg/redis"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// emergencyPromoteBatchSize 定时任务每批处理的等待期到期申请数量
+const emergencyPromoteBatchSize = 100
+
+// EmergencyAccessService 紧急访问服务
+// 用户可以指定可信联系人，当自己无法使用账户时由联系人访问保险库：
+//   - 指定联系人时用联系人的X25519公钥封装一份自己的DEK，服务端保存的只有封装结果，无法自行解开
+//   - 联系人发起申请后进入等待期，授权人收到邮件通知，等待期内可以拒绝
+//   - 等待期结束（或授权人提前同意）后，联系人用自己的安全密码解开私钥和授权人的DEK，
+//     按授权类型查看授权人的秘密（view）或重置授权人的登录密码和安全密码（takeover）
+type EmergencyAccessService struct {
+	db                *gorm.DB
+	redis             *redisClient.Client
+	encryptionService *EncryptionService
+	emailService      *EmailService
+	configManager     *config.ConfigManager
+	auditService      *AuditService
+	passwordPolicy    *PasswordPolicyService
+}
+
+// NewEmergencyAccessService 创建紧急访问服务实例
+func NewEmergencyAccessService(db *gorm.DB, redis *redisClient.Client, encryptionService *EncryptionService, emailService *EmailService, configManager *config.ConfigManager, auditService *AuditService, passwordPolicy *PasswordPolicyService) *EmergencyAccessService {
+	return &EmergencyAccessService{
+		db:                db,
+		redis:             redis,
+		encryptionService: encryptionService,
+		emailService:      emailService,
+		configManager:     configManager,
+		auditService:      auditService,
+		passwordPolicy:    passwordPolicy,
+	}
+}
+
+// generateKeyPair 生成紧急访问密钥对，私钥用DEK加密
+// 返回的加密私钥格式: [密文][nonce(12)][tag(16)]，与加密DEK的格式一致
+func generateKeyPair(dek []byte) (publicKey, encryptedPrivateKey []byte, err error) {
+	publicKey, privateKey, err := crypto.GenerateX25519KeyPair()
+	if err != nil {
+		logger.Error("生成紧急访问密钥对失败", logger.Err(err))
+		return nil, nil, err
+	}
+	defer crypto.ClearBytes(privateKey)
+
+	encryptedPrivateKey, err = encryptKeyBlob(privateKey, dek)
+	if err != nil {
+		logger.Error("加密紧急访问私钥失败", logger.Err(err))
+		return nil, nil, err
+	}
+	return publicKey, encryptedPrivateKey, nil
+}
+
+// encryptKeyBlob 用AES-GCM加密密钥材料，返回 [密文][nonce(12)][tag(16)]
+func encryptKeyBlob(plaintext, key []byte) ([]byte, error) {
+	ciphertext, nonce, authTag, err := crypto.EncryptAESGCM(plaintext, key)
+	if err != nil {
+		return nil, err
+	}
+	blob := make([]byte, 0, len(ciphertext)+len(nonce)+len(authTag))
+	blob = append(blob, ciphertext...)
+	blob = append(blob, nonce...)
+	blob = append(blob, authTag...)
+	return blob, nil
+}
+
+// decryptKeyBlob 解密 encryptKeyBlob 加密的密钥材料
+func decryptKeyBlob(blob, key []byte) ([]byte, error) {
+	if len(blob) < crypto.GCMNonceSize+crypto.GCMTagSize {
+		return nil, errors.New(errors.CodeCryptoError, "无效的加密密钥数据")
+	}
+
+	ciphertext := blob[:len(blob)-crypto.GCMNonceSize-crypto.GCMTagSize]
+	nonce := blob[len(blob)-crypto.GCMNonceSize-crypto.GCMTagSize : len(blob)-crypto.GCMTagSize]
+	authTag := blob[len(blob)-crypto.GCMTagSize:]
+	return crypto.DecryptAESGCM(ciphertext, key, nonce, authTag)
+}
+
+// keyFingerprint 计算公钥指纹，供双方线下核对联系人的公钥
+func keyFingerprint(publicKey []byte) string {
+	if len(publicKey) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(publicKey)
+	encoded := strings.ToUpper(hex.EncodeToString(sum[:8]))
+	groups := make([]string, 0, len(encoded)/4)
+	for i := 0; i < len(encoded); i += 4 {
+		groups = append(groups, encoded[i:i+4])
+	}
+	return strings.Join(groups, "-")
+}
+
+// rewrapEmergencyKeys 在DEK轮换的事务中重新加密紧急访问相关的密钥材料
+//   - 用户自己的紧急访问私钥改用新DEK加密
+//   - 用户作为授权人发出的授权改用联系人公钥重新封装新DEK
+//
+// 只需要联系人的公钥，不需要联系人参与
+func rewrapEmergencyKeys(tx *gorm.DB, userKey *models.UserEncryptionKey, oldDEK, newDEK []byte, newVersion int) error {
+	// 1. 重新加密自己的私钥
+	if userKey.HasKeyPair() {
+		privateKey, err := decryptKeyBlob(userKey.EncryptedPrivateKey, oldDEK)
+		if err != nil {
+			return err
+		}
+		encryptedPrivateKey, err := encryptKeyBlob(privateKey, newDEK)
+		crypto.ClearBytes(privateKey)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.UserEncryptionKey{}).
+			Where("user_uuid = ?", userKey.UserUUID).
+			Update("encrypted_private_key", encryptedPrivateKey).Error; err != nil {
+			return errors.Wrap(errors.CodeDatabaseError, err)
+		}
+	}
+
+	// 2. 重新封装发出的授权
+	var grants []*models.EmergencyAccess
+	if err := tx.Where("grantor_uuid = ?", userKey.UserUUID).Find(&grants).Error; err != nil {
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	for _, grant := range grants {
+		var granteeKey models.UserEncryptionKey
+		if err := tx.Select("public_key").Where("user_uuid = ?", grant.GranteeUUID).First(&granteeKey).Error; err != nil {
+			return errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		wrapped, err := crypto.SealToPublicKey(newDEK, granteeKey.PublicKey)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(grant).Updates(map[string]interface{}{
+			"wrapped_dek": wrapped,
+			"dek_version": newVersion,
+		}).Error; err != nil {
+			return errors.Wrap(errors.CodeDatabaseError, err)
+		}
+	}
+	return nil
+}
+
+// KeyPairStatus 紧急访问密钥对状态
+type KeyPairStatus struct {
+	HasKeyPair  bool   `json:"has_key_pair"`
+	Fingerprint string `json:"fingerprint,omitempty"` // 公钥指纹，授权人指定联系人前可线下核对
+}
+
+// GetKeyPairStatus 查询用户的紧急访问密钥对状态
+func (s *EmergencyAccessService) GetKeyPairStatus(userUUID string) (*KeyPairStatus, error) {
+	var userKey models.UserEncryptionKey
+	if err := s.db.Where("user_uuid = ?", userUUID).First(&userKey).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &KeyPairStatus{}, nil
+		}
+		logger.Error("查询用户加密密钥失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return &KeyPairStatus{
+		HasKeyPair:  userKey.HasKeyPair(),
+		Fingerprint: keyFingerprint(userKey.PublicKey),
+	}, nil
+}
+
+// InitKeyPairRequest 初始化紧急访问密钥对请求
+type InitKeyPairRequest struct {
+	UserUUID    string `json:"-"`                               // 不从请求体解析，由handler从上下文设置
+	SecurityPIN string `json:"security_pin" binding:"required"` // 安全密码，用于解密DEK以加密私钥
+}
+
+// InitKeyPair 为已有账户生成紧急访问密钥对（新创建的加密密钥会自动生成）
+// 用户只有生成密钥对后才能被指定为可信联系人；已存在时直接返回
+func (s *EmergencyAccessService) InitKeyPair(req *InitKeyPairRequest) (*KeyPairStatus, error) {
+	userKey, dek, err := s.encryptionService.unlockDEK(req.UserUUID, req.SecurityPIN)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ClearBytes(dek)
+
+	if userKey.HasKeyPair() {
+		return &KeyPairStatus{HasKeyPair: true, Fingerprint: keyFingerprint(userKey.PublicKey)}, nil
+	}
+
+	publicKey, encryptedPrivateKey, err := generateKeyPair(dek)
+	if err != nil {
+		return nil, err
+	}
+	// 带条件更新，避免并发请求覆盖已生成的密钥对
+	result := s.db.Model(&models.UserEncryptionKey{}).
+		Where("user_uuid = ? AND public_key IS NULL", req.UserUUID).
+		Updates(map[string]interface{}{
+			"public_key":            publicKey,
+			"encrypted_private_key": encryptedPrivateKey,
+		})
+	if result.Error != nil {
+		logger.Error("保存紧急访问密钥对失败", logger.Err(result.Error), logger.String("user_uuid", req.UserUUID))
+		return nil, errors.Wrap(errors.CodeDatabaseError, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return s.GetKeyPairStatus(req.UserUUID)
+	}
+
+	logger.Info("生成紧急访问密钥对成功", logger.String("user_uuid", req.UserUUID))
+	return &KeyPairStatus{HasKeyPair: true, Fingerprint: keyFingerprint(publicKey)}, nil
+}
+
+// DesignateContactRequest 指定可信联系人请求
+type DesignateContactRequest struct {
+	GrantorUUID     string                     `json:"-"`                                                  // 由handler从上下文设置
+	GrantorUsername string                     `json:"-"`                                                  // 由handler从上下文设置
+	SecurityPIN     string                     `json:"security_pin" binding:"required"`                    // 安全密码，用于解密DEK后封装给联系人
+	ContactUsername string                     `json:"contact_username" binding:"required"`                // 联系人用户名
+	AccessType      models.EmergencyAccessType `json:"access_type" binding:"required,oneof=view takeover"` // 访问类型
+	WaitDays        int                        `json:"wait_days" binding:"omitempty,min=1,max=90"`         // 等待期（天），默认取系统配置
+}
+
+// EmergencyAccessInfo 紧急访问授权信息
+type EmergencyAccessInfo struct {
+	*models.EmergencyAccess
+	GranteeFingerprint string `json:"grantee_fingerprint,omitempty"` // 联系人公钥指纹
+}
+
+// DesignateContact 指定可信联系人，并用联系人的公钥封装自己的DEK
+func (s *EmergencyAccessService) DesignateContact(req *DesignateContactRequest) (*EmergencyAccessInfo, error) {
+	// 1. 检查联系人
+	var contact models.User
+	if err := s.db.Where("username = ?", req.ContactUsername).First(&contact).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeResourceNotFound, "联系人不存在")
+		}
+		logger.Error("查询联系人失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if contact.UUID == req.GrantorUUID {
+		return nil, errors.New(errors.CodeInvalidParam, "不能指定自己为可信联系人")
+	}
+	if !contact.IsActive() {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "联系人账户不可用")
+	}
+
+	var contactKey models.UserEncryptionKey
+	if err := s.db.Where("user_uuid = ?", contact.UUID).First(&contactKey).Error; err != nil && err != gorm.ErrRecordNotFound {
+		logger.Error("查询联系人加密密钥失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if !contactKey.HasKeyPair() {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "联系人尚未初始化紧急访问密钥对，请联系人先完成初始化")
+	}
+
+	// 2. 同一联系人只能指定一次
+	var existing int64
+	if err := s.db.Model(&models.EmergencyAccess{}).
+		Where("grantor_uuid = ? AND grantee_uuid = ?", req.GrantorUUID, contact.UUID).
+		Count(&existing).Error; err != nil {
+		logger.Error("查询紧急访问授权失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if existing > 0 {
+		return nil, errors.New(errors.CodeResourceAlreadyExists, "已指定该可信联系人")
+	}
+
+	// 3. 解密自己的DEK并用联系人公钥封装
+	userKey, dek, err := s.encryptionService.unlockDEK(req.GrantorUUID, req.SecurityPIN)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ClearBytes(dek)
+	if userKey.RotationStatus == string(models.RotationStatusInProgress) {
+		return nil, errors.New(errors.CodeResourceConflict, "密钥轮换进行中，请稍后再试")
+	}
+
+	wrapped, err := crypto.SealToPublicKey(dek, contactKey.PublicKey)
+	if err != nil {
+		logger.Error("封装DEK失败", logger.Err(err))
+		return nil, err
+	}
+
+	// 4. 保存授权
+	waitDays := req.WaitDays
+	if waitDays == 0 {
+		waitDays = s.defaultWaitDays()
+	}
+	grant := &models.EmergencyAccess{
+		GrantUUID:       uuid.New().String(),
+		GrantorUUID:     req.GrantorUUID,
+		GrantorUsername: req.GrantorUsername,
+		GranteeUUID:     contact.UUID,
+		GranteeUsername: contact.Username,
+		AccessType:      req.AccessType,
+		WaitDays:        waitDays,
+		WrappedDEK:      wrapped,
+		DEKVersion:      userKey.DEKVersion,
+		Status:          models.EmergencyAccessActive,
+	}
+	if err := s.db.Create(grant).Error; err != nil {
+		logger.Error("保存紧急访问授权失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	go s.notify(contact.UUID, "您被指定为紧急联系人",
+		fmt.Sprintf("用户 %s 将您指定为紧急访问联系人（%s）。当其无法使用账户时，您可以发起紧急访问申请，等待期为 %d 天。",
+			req.GrantorUsername, accessTypeText(req.AccessType), waitDays))
+
+	logger.Info("指定可信联系人成功",
+		logger.String("grantor_uuid", req.GrantorUUID),
+		logger.String("grantee_uuid", contact.UUID),
+		logger.String("grant_uuid", grant.GrantUUID))
+	return &EmergencyAccessInfo{EmergencyAccess: grant, GranteeFingerprint: keyFingerprint(contactKey.PublicKey)}, nil
+}
+
+// ListTrustedContacts 查询我指定的可信联系人
+func (s *EmergencyAccessService) ListTrustedContacts(grantorUUID string) ([]*EmergencyAccessInfo, error) {
+	var grants []*models.EmergencyAccess
+	if err := s.db.Where("grantor_uuid = ?", grantorUUID).Order("id DESC").Find(&grants).Error; err != nil {
+		logger.Error("查询可信联系人失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	result := make([]*EmergencyAccessInfo, 0, len(grants))
+	for _, grant := range grants {
+		s.promoteIfDue(grant)
+		var granteeKey models.UserEncryptionKey
+		s.db.Select("public_key").Where("user_uuid = ?", grant.GranteeUUID).First(&granteeKey)
+		result = append(result, &EmergencyAccessInfo{EmergencyAccess: grant, GranteeFingerprint: keyFingerprint(granteeKey.PublicKey)})
+	}
+	return result, nil
+}
+
+// ListGrantedToMe 查询把我指定为可信联系人的授权
+func (s *EmergencyAccessService) ListGrantedToMe(granteeUUID string) ([]*models.EmergencyAccess, error) {
+	var grants []*models.EmergencyAccess
+	if err := s.db.Where("grantee_uuid = ?", granteeUUID).Order("id DESC").Find(&grants).Error; err != nil {
+		logger.Error("查询紧急访问授权失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	for _, grant := range grants {
+		s.promoteIfDue(grant)
+	}
+	return grants, nil
+}
+
+// RevokeContact 撤销可信联系人，封装的DEK随记录一起物理删除
+func (s *EmergencyAccessService) RevokeContact(grantorUUID, grantUUID string) (*models.EmergencyAccess, error) {
+	grant, err := s.getGrantAsGrantor(grantorUUID, grantUUID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Unscoped().Delete(grant).Error; err != nil {
+		logger.Error("撤销可信联系人失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	logger.Info("撤销可信联系人", logger.String("grant_uuid", grantUUID))
+	return grant, nil
+}
+
+// RequestAccess 联系人发起紧急访问申请，进入等待期并通知授权人
+func (s *EmergencyAccessService) RequestAccess(granteeUUID, grantUUID string) (*models.EmergencyAccess, error) {
+	grant, err := s.getGrantAsGrantee(granteeUUID, grantUUID)
+	if err != nil {
+		return nil, err
+	}
+	if grant.Status != models.EmergencyAccessActive {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "已发起紧急访问申请")
+	}
+
+	now := time.Now()
+	availableAt := now.AddDate(0, 0, grant.WaitDays)
+	result := s.db.Model(&models.EmergencyAccess{}).
+		Where("id = ? AND status = ?", grant.ID, models.EmergencyAccessActive).
+		Updates(map[string]interface{}{
+			"status":       models.EmergencyAccessRequested,
+			"requested_at": now,
+			"available_at": availableAt,
+			"granted_at":   nil,
+		})
+	if result.Error != nil {
+		logger.Error("发起紧急访问申请失败", logger.Err(result.Error))
+		return nil, errors.Wrap(errors.CodeDatabaseError, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "已发起紧急访问申请")
+	}
+	grant.Status = models.EmergencyAccessRequested
+	grant.RequestedAt = &now
+	grant.AvailableAt = &availableAt
+	grant.GrantedAt = nil
+
+	go s.notify(grant.GrantorUUID, "紧急访问申请",
+		fmt.Sprintf("您的紧急联系人 %s 发起了紧急访问申请（%s）。如果您在 %s 之前没有拒绝，对方将获得访问权限。",
+			grant.GranteeUsername, accessTypeText(grant.AccessType), availableAt.Format("2006-01-02 15:04")))
+
+	logger.Info("发起紧急访问申请",
+		logger.String("grant_uuid", grantUUID),
+		logger.String("grantee_uuid", granteeUUID))
+	return grant, nil
+}
+
+// ApproveAccess 授权人提前同意紧急访问申请，联系人立即获得访问权限
+func (s *EmergencyAccessService) ApproveAccess(grantorUUID, grantUUID string) (*models.EmergencyAccess, error) {
+	grant, err := s.getGrantAsGrantor(grantorUUID, grantUUID)
+	if err != nil {
+		return nil, err
+	}
+	if grant.Status != models.EmergencyAccessRequested {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "联系人未发起紧急访问申请")
+	}
+	if !s.promote(grant) {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "紧急访问申请状态已变化，请刷新后重试")
+	}
+	return grant, nil
+}
+
+// RejectAccess 授权人拒绝紧急访问申请（或收回已获得的访问权限），授权恢复为未申请状态
+func (s *EmergencyAccessService) RejectAccess(grantorUUID, grantUUID string) (*models.EmergencyAccess, error) {
+	grant, err := s.getGrantAsGrantor(grantorUUID, grantUUID)
+	if err != nil {
+		return nil, err
+	}
+	if grant.Status == models.EmergencyAccessActive {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "联系人未发起紧急访问申请")
+	}
+
+	now := time.Now()
+	result := s.db.Model(&models.EmergencyAccess{}).
+		Where("id = ? AND status IN ?", grant.ID, []models.EmergencyAccessStatus{models.EmergencyAccessRequested, models.EmergencyAccessGranted}).
+		Updates(map[string]interface{}{
+			"status":           models.EmergencyAccessActive,
+			"requested_at":     nil,
+			"available_at":     nil,
+			"granted_at":       nil,
+			"last_rejected_at": now,
+		})
+	if result.Error != nil {
+		logger.Error("拒绝紧急访问申请失败", logger.Err(result.Error))
+		return nil, errors.Wrap(errors.CodeDatabaseError, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "联系人未发起紧急访问申请")
+	}
+	grant.Status = models.EmergencyAccessActive
+	grant.RequestedAt = nil
+	grant.AvailableAt = nil
+	grant.GrantedAt = nil
+	grant.LastRejectedAt = &now
+
+	go s.notify(grant.GranteeUUID, "紧急访问申请被拒绝",
+		fmt.Sprintf("用户 %s 拒绝了您的紧急访问申请。", grant.GrantorUsername))
+
+	logger.Info("拒绝紧急访问申请", logger.String("grant_uuid", grantUUID))
+	return grant, nil
+}
+
+// PromoteDueRequests 将等待期已结束的申请标记为已获得访问权限（定时任务调用）
+// 以system身份记录审计日志并通知联系人
+func (s *EmergencyAccessService) PromoteDueRequests() (int, error) {
+	promoted := 0
+	lastID := uint(0)
+	for {
+		var grants []*models.EmergencyAccess
+		if err := s.db.Where("id > ? AND status = ? AND available_at <= ?", lastID, models.EmergencyAccessRequested, time.Now()).
+			Order("id").Limit(emergencyPromoteBatchSize).
+			Find(&grants).Error; err != nil {
+			logger.Error("查询到期紧急访问申请失败", logger.Err(err))
+			return promoted, errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		if len(grants) == 0 {
+			break
+		}
+
+		for _, grant := range grants {
+			lastID = grant.ID
+			if s.promote(grant) {
+				promoted++
+			}
+		}
+
+		if len(grants) < emergencyPromoteBatchSize {
+			break
+		}
+	}
+
+	if promoted > 0 {
+		logger.Info("紧急访问等待期结束", logger.Int("count", promoted))
+	}
+	return promoted, nil
+}
+
+// promoteIfDue 查询时顺带处理等待期已结束但定时任务尚未处理的申请
+func (s *EmergencyAccessService) promoteIfDue(grant *models.EmergencyAccess) {
+	if grant.WaitingPeriodOver() {
+		s.promote(grant)
+	}
+}
+
+// promote 把申请标记为已获得访问权限，返回是否由本次调用完成
+// 带状态条件更新，保证定时任务、查询和授权人同意并发时只通知一次
+func (s *EmergencyAccessService) promote(grant *models.EmergencyAccess) bool {
+	now := time.Now()
+	result := s.db.Model(&models.EmergencyAccess{}).
+		Where("id = ? AND status = ?", grant.ID, models.EmergencyAccessRequested).
+		Updates(map[string]interface{}{
+			"status":     models.EmergencyAccessGranted,
+			"granted_at": now,
+		})
+	if result.Error != nil {
+		logger.Error("更新紧急访问状态失败", logger.Err(result.Error), logger.String("grant_uuid", grant.GrantUUID))
+		return false
+	}
+	if result.RowsAffected == 0 {
+		return false
+	}
+	grant.Status = models.EmergencyAccessGranted
+	grant.GrantedAt = &now
+
+	s.logSystemAudit(grant)
+	go s.notify(grant.GranteeUUID, "紧急访问已生效",
+		fmt.Sprintf("您对用户 %s 的紧急访问（%s）已生效，请登录 VaultHub 使用。", grant.GrantorUsername, accessTypeText(grant.AccessType)))
+
+	logger.Info("紧急访问生效", logger.String("grant_uuid", grant.GrantUUID))
+	return true
+}
+
+// ListGrantorSecrets 联系人查询授权人的秘密列表（不包含加密数据）
+func (s *EmergencyAccessService) ListGrantorSecrets(granteeUUID, grantUUID string, req *ListUserSecretsRequest) (*ListUserSecretsResponse, error) {
+	grant, err := s.getGrantedAccess(granteeUUID, grantUUID)
+	if err != nil {
+		return nil, err
+	}
+	req.UserUUID = grant.GrantorUUID
+	return s.encryptionService.ListUserSecrets(req)
+}
+
+// EmergencyDecryptRequest 紧急访问解密秘密请求
+type EmergencyDecryptRequest struct {
+	GranteeUUID string `json:"-"`                               // 由handler从上下文设置
+	GrantUUID   string `json:"-"`                               // 由handler从URL路径设置
+	SecretUUID  string `json:"-"`                               // 由handler从URL路径设置
+	SecurityPIN string `json:"security_pin" binding:"required"` // 联系人自己的安全密码
+}
+
+// EmergencyDecryptResponse 紧急访问解密秘密响应
+type EmergencyDecryptResponse struct {
+	*models.DecryptedSecret
+	GrantorUsername string `json:"grantor_username"`
+}
+
+// DecryptGrantorSecret 联系人解密授权人的秘密
+// 紧急访问不受检出和审批限制，每次解密都会记录审计日志
+func (s *EmergencyAccessService) DecryptGrantorSecret(req *EmergencyDecryptRequest) (*EmergencyDecryptResponse, error) {
+	grant, err := s.getGrantedAccess(req.GranteeUUID, req.GrantUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var secret models.EncryptedSecret
+	if err := s.db.Where("user_uuid = ? AND secret_uuid = ?", grant.GrantorUUID, req.SecretUUID).First(&secret).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeResourceNotFound, "秘密不存在")
+		}
+		logger.Error("查询秘密失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if secret.DEKVersion != grant.DEKVersion {
+		return nil, errors.New(errors.CodeResourceConflict, "授权人的密钥正在轮换，请稍后再试")
+	}
+
+	grantorDEK, err := s.unwrapGrantorDEK(grant, req.SecurityPIN)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ClearBytes(grantorDEK)
+
+	plainData, err := crypto.DecryptAESGCM(secret.EncryptedData, grantorDEK, secret.Nonce, secret.AuthTag)
+	if err != nil {
+		logger.Error("紧急访问解密秘密失败", logger.Err(err), logger.String("secret_uuid", req.SecretUUID))
+		return nil, errors.WithMessage(errors.CodeDecryptionFailed, "解密失败或数据被篡改", err)
+	}
+
+	logger.Info("紧急访问解密秘密",
+		logger.String("grant_uuid", grant.GrantUUID),
+		logger.String("grantee_uuid", req.GranteeUUID),
+		logger.String("secret_uuid", req.SecretUUID))
+
+	return &EmergencyDecryptResponse{
+		DecryptedSecret: &models.DecryptedSecret{
+			SafeEncryptedSecret: *secret.ToSafe(),
+			PlainData:           string(plainData),
+		},
+		GrantorUsername: grant.GrantorUsername,
+	}, nil
+}
+
+// TakeoverRequest 紧急接管请求
+type TakeoverRequest struct {
+	GranteeUUID    string `json:"-"`                                         // 由handler从上下文设置
+	GrantUUID      string `json:"-"`                                         // 由handler从URL路径设置
+	SecurityPIN    string `json:"security_pin" binding:"required"`           // 联系人自己的安全密码
+	NewPassword    string `json:"new_password" binding:"required"`           // 授权人账户的新登录密码
+	NewSecurityPIN string `json:"new_security_pin" binding:"required,min=8"` // 授权人账户的新安全密码
+}
+
+// Takeover 联系人接管授权人账户：重置授权人的登录密码和安全密码，并使授权人已登录的会话失效
+// DEK保持不变，秘密无需重新加密；授权人原有的恢复助记词仍然有效
+func (s *EmergencyAccessService) Takeover(req *TakeoverRequest) (*models.EmergencyAccess, error) {
+	grant, err := s.getGrantedAccess(req.GranteeUUID, req.GrantUUID)
+	if err != nil {
+		return nil, err
+	}
+	if grant.AccessType != models.EmergencyAccessTakeover {
+		return nil, errors.New(errors.CodeForbidden, "该紧急访问授权不包含接管权限")
+	}
+
+	// 按密码策略校验授权人的新登录密码
+	var grantor models.User
+	if err := s.db.Where("uuid = ?", grant.GrantorUUID).First(&grantor).Error; err != nil {
+		logger.Error("查询授权人失败", logger.Err(err), logger.String("grantor_uuid", grant.GrantorUUID))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if err := s.passwordPolicy.ValidateForUser(&grantor, req.NewPassword); err != nil {
+		return nil, err
+	}
+
+	var grantorKey models.UserEncryptionKey
+	if err := s.db.Where("user_uuid = ?", grant.GrantorUUID).First(&grantorKey).Error; err != nil {
+		logger.Error("查询授权人加密密钥失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if grantorKey.DEKVersion != grant.DEKVersion || grantorKey.RotationStatus == string(models.RotationStatusInProgress) {
+		return nil, errors.New(errors.CodeResourceConflict, "授权人的密钥正在轮换，请稍后再试")
+	}
+
+	// 1. 解开授权人的DEK
+	grantorDEK, err := s.unwrapGrantorDEK(grant, req.SecurityPIN)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ClearBytes(grantorDEK)
+
+	// 2. 用新安全密码重新加密DEK
+	newKEKSalt, err := crypto.GenerateRandomBytes(crypto.SaltSize)
+	if err != nil {
+		logger.Error("生成新KEK盐值失败", logger.Err(err))
+		return nil, err
+	}
+	newKEK, err := crypto.DeriveKEK(req.NewSecurityPIN, newKEKSalt)
+	if err != nil {
+		logger.Error("派生新KEK失败", logger.Err(err))
+		return nil, errors.WithMessage(errors.CodeKeyDerivationError, "新密钥派生失败", err)
+	}
+	defer crypto.ClearBytes(newKEK)
+
+	newEncryptedDEK, err := encryptKeyBlob(grantorDEK, newKEK)
+	if err != nil {
+		logger.Error("用新KEK加密DEK失败", logger.Err(err))
+		return nil, err
+	}
+	newSecurityPINHash, err := crypto.HashPassword(req.NewSecurityPIN)
+	if err != nil {
+		logger.Error("生成新安全密码哈希失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCryptoError, err)
+	}
+	newPasswordHash, err := crypto.HashPassword(req.NewPassword)
+	if err != nil {
+		logger.Error("密码加密失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCryptoError, err)
+	}
+
+	// 3. 在事务中更新授权人的密钥和登录密码
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&grantorKey).Updates(map[string]interface{}{
+			"kek_salt":          newKEKSalt,
+			"encrypted_dek":     newEncryptedDEK,
+			"security_pin_hash": newSecurityPINHash,
+		}).Error; err != nil {
+			return err
+		}
+		if err := s.passwordPolicy.setPassword(tx, &grantor, newPasswordHash); err != nil {
+			return err
+		}
+		return bumpTokenEpoch(tx, grant.GrantorUUID)
+	})
+	if err != nil {
+		logger.Error("紧急接管更新授权人账户失败", logger.Err(err), logger.String("grant_uuid", grant.GrantUUID))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	// 4. 使授权人已登录的会话失效
+	s.revokeUserSession(grant.GrantorUUID)
+
+	go s.notify(grant.GrantorUUID, "账户已被紧急接管",
+		fmt.Sprintf("您的紧急联系人 %s 已接管您的账户，登录密码和安全密码均已重置。", grant.GranteeUsername))
+
+	logger.Info("紧急接管成功",
+		logger.String("grant_uuid", grant.GrantUUID),
+		logger.String("grantor_uuid", grant.GrantorUUID),
+		logger.String("grantee_uuid", req.GranteeUUID))
+	return grant, nil
+}
+
+// unwrapGrantorDEK 用联系人的安全密码解开其私钥，再解开授权人的DEK
+// 返回的DEK由调用方负责清零
+func (s *EmergencyAccessService) unwrapGrantorDEK(grant *models.EmergencyAccess, securityPIN string) ([]byte, error) {
+	granteeKey, granteeDEK, err := s.encryptionService.unlockDEK(grant.GranteeUUID, securityPIN)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ClearBytes(granteeDEK)
+
+	if !granteeKey.HasKeyPair() {
+		return nil, errors.New(errors.CodeCryptoError, "紧急访问密钥对不存在")
+	}
+	privateKey, err := decryptKeyBlob(granteeKey.EncryptedPrivateKey, granteeDEK)
+	if err != nil {
+		logger.Error("解密紧急访问私钥失败", logger.Err(err), logger.String("user_uuid", grant.GranteeUUID))
+		return nil, errors.WithMessage(errors.CodeDecryptionFailed, "解密紧急访问私钥失败", err)
+	}
+	defer crypto.ClearBytes(privateKey)
+
+	grantorDEK, err := crypto.OpenSealed(grant.WrappedDEK, privateKey)
+	if err != nil {
+		logger.Error("解开授权人DEK失败", logger.Err(err), logger.String("grant_uuid", grant.GrantUUID))
+		return nil, errors.WithMessage(errors.CodeDecryptionFailed, "解开授权人密钥失败", err)
+	}
+	return grantorDEK, nil
+}
+
+// revokeUserSession 删除用户的全部登录会话（令牌版本已在接管事务中递增，已签发的令牌已经失效）
+func (s *EmergencyAccessService) revokeUserSession(userUUID string) {
+	if err := revokeAllSessions(s.db, s.redis, userUUID); err != nil {
+		logger.Warn("注销用户会话失败", logger.String("uuid", userUUID), logger.Err(err))
+	}
+}
+
+// getGrantAsGrantor 以授权人身份查询授权
+func (s *EmergencyAccessService) getGrantAsGrantor(grantorUUID, grantUUID string) (*models.EmergencyAccess, error) {
+	return s.getGrant("grantor_uuid = ? AND grant_uuid = ?", grantorUUID, grantUUID)
+}
+
+// getGrantAsGrantee 以联系人身份查询授权
+func (s *EmergencyAccessService) getGrantAsGrantee(granteeUUID, grantUUID string) (*models.EmergencyAccess, error) {
+	return s.getGrant("grantee_uuid = ? AND grant_uuid = ?", granteeUUID, grantUUID)
+}
+
+// getGrantedAccess 以联系人身份查询已生效的授权
+func (s *EmergencyAccessService) getGrantedAccess(granteeUUID, grantUUID string) (*models.EmergencyAccess, error) {
+	grant, err := s.getGrantAsGrantee(granteeUUID, grantUUID)
+	if err != nil {
+		return nil, err
+	}
+	s.promoteIfDue(grant)
+	if grant.Status != models.EmergencyAccessGranted {
+		return nil, errors.New(errors.CodeForbidden, "紧急访问尚未生效")
+	}
+	return grant, nil
+}
+
+// getGrant 按条件查询授权
+func (s *EmergencyAccessService) getGrant(query string, args ...interface{}) (*models.EmergencyAccess, error) {
+	var grant models.EmergencyAccess
+	if err := s.db.Where(query, args...).First(&grant).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeResourceNotFound, "紧急访问授权不存在")
+		}
+		logger.Error("查询紧急访问授权失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return &grant, nil
+}
+
+// defaultWaitDays 读取默认等待期（天）
+func (s *EmergencyAccessService) defaultWaitDays() int {
+	value := s.configManager.GetWithDefault(models.ConfigKeyEmergencyAccessDefaultWaitDays, models.ConfigValueEmergencyAccessDefaultWaitDaysDefault)
+	days, err := strconv.Atoi(value)
+	if err != nil || days <= 0 {
+		logger.Warn("紧急访问等待期配置无效，使用默认值", logger.String("value", value))
+		days, _ = strconv.Atoi(models.ConfigValueEmergencyAccessDefaultWaitDaysDefault)
+	}
+	return days
+}
+
+// notify 给用户发送紧急访问通知邮件，用户未设置邮箱时跳过
+func (s *EmergencyAccessService) notify(userUUID, title, message string) {
+	var profile models.UserProfile
+	if err := s.db.Joins("JOIN users ON users.id = user_profiles.user_id").
+		Where("users.uuid = ?", userUUID).First(&profile).Error; err != nil || profile.Email == "" {
+		logger.Warn("用户未设置邮箱，跳过紧急访问通知", logger.String("user_uuid", userUUID))
+		return
+	}
+	if err := s.emailService.SendEmergencyAccessNotice(profile.Email, title, message); err != nil {
+		logger.Error("发送紧急访问通知失败", logger.String("user_uuid", userUUID), logger.Err(err))
+	}
+}
+
+// logSystemAudit 记录等待期结束、访问生效的审计日志（操作者为system）
+func (s *EmergencyAccessService) logSystemAudit(grant *models.EmergencyAccess) {
+	if s.auditService == nil {
+		return
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"operation":   "emergency_access_granted",
+		"grantee":     grant.GranteeUsername,
+		"access_type": grant.AccessType,
+	})
+	grantUUID := grant.GrantUUID
+	s.auditService.LogAsync(&models.AuditLog{
+		UserUUID:     grant.GrantorUUID,
+		Username:     systemAuditUsername,
+		ActionType:   models.ActionApprove,
+		ResourceType: models.ResourceEmergencyAccess,
+		ResourceUUID: &grantUUID,
+		Status:       models.AuditSuccess,
+		Details:      string(details),
+		CreatedAt:    time.Now().UTC(),
+	})
+}
+
+// accessTypeText 访问类型的中文说明
+func accessTypeText(accessType models.EmergencyAccessType) string {
+	if accessType == models.EmergencyAccessTakeover {
+		return "接管账户"
+	}
+	return "查看秘密"
+}
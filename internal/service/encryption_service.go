@@ -1,7 +1,10 @@
 package service
 
 import (
+	"time"
+
 	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/breach"
 	"github.com/cuihe500/vaulthub/pkg/crypto"
 	"github.com/cuihe500/vaulthub/pkg/errors"
 	"github.com/cuihe500/vaulthub/pkg/logger"
@@ -11,13 +14,15 @@ import (
 
 // EncryptionService 加密服务
 type EncryptionService struct {
-	db *gorm.DB
+	db            *gorm.DB
+	breachChecker breach.Checker // 泄露密码检查器，未配置数据集时为nil
 }
 
 // NewEncryptionService 创建加密服务实例
-func NewEncryptionService(db *gorm.DB) *EncryptionService {
+func NewEncryptionService(db *gorm.DB, breachChecker breach.Checker) *EncryptionService {
 	return &EncryptionService{
-		db: db,
+		db:            db,
+		breachChecker: breachChecker,
 	}
 }
 
@@ -125,7 +130,13 @@ func (s *EncryptionService) CreateUserEncryptionKey(req *CreateUserEncryptionKey
 	encryptedDEKRecoveryBlob = append(encryptedDEKRecoveryBlob, nonce2...)
 	encryptedDEKRecoveryBlob = append(encryptedDEKRecoveryBlob, authTag2...)
 
-	// 7. 存储到数据库
+	// 7. 生成紧急访问密钥对（私钥用DEK加密），用于接收其他用户的紧急访问授权
+	publicKey, encryptedPrivateKey, err := generateKeyPair(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	// 8. 存储到数据库
 	userKey := models.UserEncryptionKey{
 		UserUUID:             req.UserUUID,
 		KEKSalt:              kekSalt,
@@ -136,6 +147,8 @@ func (s *EncryptionService) CreateUserEncryptionKey(req *CreateUserEncryptionKey
 		SecurityPINHash:      securityPINHash, // 存储安全密码哈希
 		RecoveryKeyHash:      recoveryKeyHash,
 		EncryptedDEKRecovery: encryptedDEKRecoveryBlob,
+		PublicKey:            publicKey,
+		EncryptedPrivateKey:  encryptedPrivateKey,
 	}
 
 	if err := s.db.Create(&userKey).Error; err != nil {
@@ -145,7 +158,7 @@ func (s *EncryptionService) CreateUserEncryptionKey(req *CreateUserEncryptionKey
 
 	logger.Info("创建用户加密密钥成功", logger.String("user_uuid", req.UserUUID))
 
-	// 8. 返回响应（恢复助记词仅显示一次，用户必须妥善保管）
+	// 9. 返回响应（恢复助记词仅显示一次，用户必须妥善保管）
 	return &CreateUserEncryptionKeyResponse{
 		UserEncryptionKey: userKey.ToSafe(),
 		RecoveryKey:       recoveryMnemonic, // 返回24个单词的助记词
@@ -166,61 +179,44 @@ type EncryptAndStoreSecretRequest struct {
 
 // EncryptAndStoreSecret 加密并存储秘密
 func (s *EncryptionService) EncryptAndStoreSecret(req *EncryptAndStoreSecretRequest) (*models.SafeEncryptedSecret, error) {
-	// 1. 获取用户的加密密钥配置
-	var userKey models.UserEncryptionKey
-	if err := s.db.Where("user_uuid = ?", req.UserUUID).First(&userKey).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			logger.Warn("用户加密密钥不存在", logger.String("user_uuid", req.UserUUID))
-			return nil, errors.New(errors.CodeResourceNotFound, "用户加密密钥不存在，请先创建")
-		}
-		logger.Error("查询用户加密密钥失败", logger.Err(err))
-		return nil, errors.Wrap(errors.CodeDatabaseError, err)
-	}
-
-	// 2. 验证安全密码（快速失败，避免昂贵的Argon2计算）
-	if !crypto.VerifyPassword(req.SecurityPIN, userKey.SecurityPINHash) {
-		logger.Warn("安全密码验证失败", logger.String("user_uuid", req.UserUUID))
-		return nil, errors.New(errors.CodeInvalidCredentials, "安全密码错误")
-	}
-
-	// 3. 从安全密码派生KEK
-	kek, err := crypto.DeriveKEK(req.SecurityPIN, userKey.KEKSalt)
+	// 1. 验证安全密码并解密DEK
+	userKey, dek, err := s.unlockDEK(req.UserUUID, req.SecurityPIN)
 	if err != nil {
-		logger.Error("派生KEK失败", logger.Err(err))
-		return nil, errors.WithMessage(errors.CodeKeyDerivationError, "密钥派生失败", err)
-	}
-	defer crypto.ClearBytes(kek)
-
-	// 4. 解密DEK
-	dek, err := s.decryptDEK(userKey.EncryptedDEK, kek)
-	if err != nil {
-		logger.Warn("解密DEK失败，安全密码可能错误", logger.String("user_uuid", req.UserUUID), logger.Err(err))
-		return nil, errors.New(errors.CodeInvalidCredentials, "安全密码错误")
+		return nil, err
 	}
 	defer crypto.ClearBytes(dek)
 
-	// 4. 用DEK加密实际数据
+	// 2. 用DEK加密实际数据
 	encryptedData, dataNonce, dataAuthTag, err := crypto.EncryptAESGCM([]byte(req.PlainData), dek)
 	if err != nil {
 		logger.Error("加密秘密数据失败", logger.Err(err))
 		return nil, err
 	}
 
-	// 5. 生成秘密UUID
+	// 3. 密码类秘密计算指纹和强度评分（用于保险库健康检查）
+	fingerprint, strength, err := s.passwordHealth(req.SecretType, []byte(req.PlainData), dek)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 生成秘密UUID
 	secretUUID := uuid.New().String()
 
-	// 6. 存储到数据库
+	// 5. 存储到数据库
+	now := time.Now()
 	secret := models.EncryptedSecret{
-		UserUUID:      req.UserUUID,
-		SecretUUID:    secretUUID,
-		SecretName:    req.SecretName,
-		SecretType:    req.SecretType,
-		Description:   req.Description,
-		EncryptedData: encryptedData,
-		DEKVersion:    userKey.DEKVersion,
-		Nonce:         dataNonce,
-		AuthTag:       dataAuthTag,
-		Metadata:      req.Metadata,
+		UserUUID:            req.UserUUID,
+		SecretUUID:          secretUUID,
+		SecretName:          req.SecretName,
+		SecretType:          req.SecretType,
+		Description:         req.Description,
+		EncryptedData:       encryptedData,
+		DEKVersion:          userKey.DEKVersion,
+		Nonce:               dataNonce,
+		AuthTag:             dataAuthTag,
+		Metadata:            withStrength(req.Metadata, strength),
+		PasswordFingerprint: fingerprint,
+		ValueChangedAt:      &now,
 	}
 
 	if err := s.db.Create(&secret).Error; err != nil {
@@ -242,9 +238,12 @@ type DecryptSecretRequest struct {
 	UserUUID    string `json:"-"`                               // 不从请求体解析，由handler从上下文设置
 	SecretUUID  string `json:"-"`                               // 不从请求体解析，由handler从URL路径设置
 	SecurityPIN string `json:"security_pin" binding:"required"` // 安全密码，用于解密DEK
+
+	checkedOut bool // 已持有检出租约，由检出服务设置
 }
 
 // DecryptSecret 解密秘密
+// 需要检出的秘密只能通过检出接口访问
 func (s *EncryptionService) DecryptSecret(req *DecryptSecretRequest) (*models.DecryptedSecret, error) {
 	// 1. 获取加密的秘密
 	var secret models.EncryptedSecret
@@ -263,6 +262,26 @@ func (s *EncryptionService) DecryptSecret(req *DecryptSecretRequest) (*models.De
 		return nil, errors.New(errors.CodeResourceNotFound, "秘密已过期")
 	}
 
+	// 需要检出的秘密只能由检出服务解密，保证同一时间只有租约持有者能拿到明文
+	if secret.CheckoutRequired && !req.checkedOut {
+		logger.Warn("秘密需要检出后访问", logger.String("secret_uuid", req.SecretUUID))
+		return nil, errors.New(errors.CodeOperationNotAllowed, "该秘密需要检出后才能访问")
+	}
+
+	// 高敏感秘密需要有已批准且仍在解密窗口内的访问申请
+	var accessRequestUUID string
+	if secret.ApprovalRequired {
+		grant, err := findAccessGrant(s.db, req.UserUUID, req.SecretUUID)
+		if err != nil {
+			return nil, err
+		}
+		if grant == nil {
+			logger.Warn("高敏感秘密未获批准", logger.String("user_uuid", req.UserUUID), logger.String("secret_uuid", req.SecretUUID))
+			return nil, errors.New(errors.CodeApprovalRequired, "该秘密需要审批通过后才能解密，请先提交访问申请")
+		}
+		accessRequestUUID = grant.RequestUUID
+	}
+
 	// 2. 获取用户密钥
 	var userKey models.UserEncryptionKey
 	if err := s.db.Where("user_uuid = ?", req.UserUUID).First(&userKey).Error; err != nil {
@@ -323,6 +342,7 @@ func (s *EncryptionService) DecryptSecret(req *DecryptSecretRequest) (*models.De
 	return &models.DecryptedSecret{
 		SafeEncryptedSecret: *secret.ToSafe(),
 		PlainData:           string(plainData),
+		AccessRequestUUID:   accessRequestUUID,
 	}, nil
 }
 
@@ -344,10 +364,11 @@ func (s *EncryptionService) DeleteSecret(userUUID, secretUUID string) error {
 
 // ListUserSecretsRequest 列出用户秘密请求
 type ListUserSecretsRequest struct {
-	UserUUID   string            `form:"-"`
-	SecretType models.SecretType `form:"secret_type"`
-	Page       int               `form:"page" binding:"omitempty,min=1"`
-	PageSize   int               `form:"page_size" binding:"omitempty,min=1,max=10000"`
+	UserUUID    string            `form:"-"`
+	SecretUUIDs []string          `form:"-"` // 只列出这些秘密（个人访问令牌的秘密范围），为空时不限制
+	SecretType  models.SecretType `form:"secret_type"`
+	Page        int               `form:"page" binding:"omitempty,min=1"`
+	PageSize    int               `form:"page_size" binding:"omitempty,min=1,max=10000"`
 }
 
 // ListUserSecretsResponse 列出用户秘密响应
@@ -368,6 +389,9 @@ func (s *EncryptionService) ListUserSecrets(req *ListUserSecretsRequest) (*ListU
 	if req.SecretType != "" {
 		query = query.Where("secret_type = ?", req.SecretType)
 	}
+	if len(req.SecretUUIDs) > 0 {
+		query = query.Where("secret_uuid IN ?", req.SecretUUIDs)
+	}
 
 	// 获取总数
 	var total int64
@@ -421,6 +445,53 @@ func (s *EncryptionService) ListUserSecrets(req *ListUserSecretsRequest) (*ListU
 	}, nil
 }
 
+// verifySecurityPIN 获取用户加密密钥配置并验证安全密码
+// 只做bcrypt校验，不派生KEK，适用于不需要解密数据的敏感操作
+func (s *EncryptionService) verifySecurityPIN(userUUID, securityPIN string) (*models.UserEncryptionKey, error) {
+	var userKey models.UserEncryptionKey
+	if err := s.db.Where("user_uuid = ?", userUUID).First(&userKey).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Warn("用户加密密钥不存在", logger.String("user_uuid", userUUID))
+			return nil, errors.New(errors.CodeResourceNotFound, "用户加密密钥不存在，请先创建")
+		}
+		logger.Error("查询用户加密密钥失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	if !crypto.VerifyPassword(securityPIN, userKey.SecurityPINHash) {
+		logger.Warn("安全密码验证失败", logger.String("user_uuid", userUUID))
+		return nil, errors.New(errors.CodeInvalidCredentials, "安全密码错误")
+	}
+	return &userKey, nil
+}
+
+// unlockDEK 验证安全密码并解密用户的DEK
+// 返回的DEK由调用方负责使用 crypto.ClearBytes 清零
+func (s *EncryptionService) unlockDEK(userUUID, securityPIN string) (*models.UserEncryptionKey, []byte, error) {
+	// 1. 获取用户的加密密钥配置并验证安全密码（快速失败，避免昂贵的Argon2计算）
+	userKey, err := s.verifySecurityPIN(userUUID, securityPIN)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 2. 从安全密码派生KEK
+	kek, err := crypto.DeriveKEK(securityPIN, userKey.KEKSalt)
+	if err != nil {
+		logger.Error("派生KEK失败", logger.Err(err))
+		return nil, nil, errors.WithMessage(errors.CodeKeyDerivationError, "密钥派生失败", err)
+	}
+	defer crypto.ClearBytes(kek)
+
+	// 3. 解密DEK
+	dek, err := s.decryptDEK(userKey.EncryptedDEK, kek)
+	if err != nil {
+		logger.Warn("解密DEK失败，安全密码可能错误", logger.String("user_uuid", userUUID), logger.Err(err))
+		return nil, nil, errors.New(errors.CodeInvalidCredentials, "安全密码错误")
+	}
+
+	return userKey, dek, nil
+}
+
 // decryptDEK 解密DEK的辅助函数
 // blob格式: [密文][nonce(12)][tag(16)]
 func (s *EncryptionService) decryptDEK(blob, kek []byte) ([]byte, error) {
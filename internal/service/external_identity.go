@@ -0,0 +1,319 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	redisClient "github.com/cuihe500/vaulthub/pkg/redis"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 自动创建账户的用户名和昵称长度限制
+const (
+	externalUsernameMaxLen = 32
+	externalNicknameMaxLen = 50
+	externalPasswordSize   = 32
+)
+
+// externalAccount 外部身份源（OIDC、LDAP）认证通过的账户信息
+type externalAccount struct {
+	Issuer        string   // 身份源标识，与Subject一起唯一确定外部身份
+	Subject       string   // 身份源内的唯一标识
+	Usernames     []string // 自动创建账户时依次尝试的用户名
+	Email         string
+	EmailVerified bool // 身份源是否验证过该邮箱，决定自动创建的用户档案中邮箱是否已验证
+	LinkByEmail   bool // 是否可以按邮箱关联已有账户（本地邮箱也必须已验证，且不关联管理员账户）
+	Name          string
+}
+
+// resolveExternalUser 查找外部身份关联的用户
+// 没有关联时按account.LinkByEmail决定是否按邮箱关联已有账户，仍然没有时按autoProvision自动创建账户（角色为role）
+func resolveExternalUser(db *gorm.DB, account *externalAccount, role string, autoProvision bool) (*models.User, *models.UserIdentity, error) {
+	user, identity, err := findExternalIdentity(db, account.Issuer, account.Subject)
+	if err != nil || user != nil {
+		return user, identity, err
+	}
+
+	// 按邮箱查找已有账户
+	if account.Email != "" {
+		var profile models.UserProfile
+		err := db.Where("email = ?", account.Email).First(&profile).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			logger.Error("查询用户档案失败", logger.String("email", account.Email), logger.Err(err))
+			return nil, nil, errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		if err == nil {
+			// 只有身份源可信且本地也验证过邮箱时才关联，避免通过未验证的邮箱接管他人账户
+			if !account.LinkByEmail || !profile.EmailVerified {
+				logger.Warn("外部身份的邮箱已被未关联的账户使用",
+					logger.String("issuer", account.Issuer),
+					logger.String("email", account.Email),
+					logger.Bool("link_by_email", account.LinkByEmail),
+					logger.Bool("local_email_verified", profile.EmailVerified))
+				return nil, nil, errors.New(errors.CodeEmailExists, "该邮箱已被其他账户使用，请先用原账户登录或联系管理员")
+			}
+			return linkExternalIdentity(db, account, profile.UserID)
+		}
+	}
+
+	if !autoProvision {
+		logger.Warn("外部身份没有关联账户",
+			logger.String("issuer", account.Issuer),
+			logger.String("subject", account.Subject))
+		return nil, nil, errors.New(errors.CodeForbidden, "没有关联的账户，请联系管理员")
+	}
+	return provisionExternalUser(db, account, role)
+}
+
+// findExternalIdentity 按身份源和唯一标识查找关联的用户，没有关联时返回nil
+func findExternalIdentity(db *gorm.DB, issuer, subject string) (*models.User, *models.UserIdentity, error) {
+	var identity models.UserIdentity
+	if err := db.Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, nil
+		}
+		logger.Error("查询外部身份失败", logger.String("issuer", issuer), logger.String("subject", subject), logger.Err(err))
+		return nil, nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	var user models.User
+	if err := db.Where("uuid = ?", identity.UserUUID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.New(errors.CodeInvalidCredentials, "")
+		}
+		logger.Error("查询用户失败", logger.String("uuid", identity.UserUUID), logger.Err(err))
+		return nil, nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return &user, &identity, nil
+}
+
+// linkExternalIdentity 把外部身份关联到已有账户
+func linkExternalIdentity(db *gorm.DB, account *externalAccount, userID uint) (*models.User, *models.UserIdentity, error) {
+	var user models.User
+	if err := db.Where("id = ?", userID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.New(errors.CodeInvalidCredentials, "")
+		}
+		logger.Error("查询用户失败", logger.Uint("user_id", userID), logger.Err(err))
+		return nil, nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	// 关联后角色由身份源决定，管理员账户不能被外部身份接管
+	if user.IsAdmin() {
+		logger.Warn("拒绝把外部身份关联到管理员账户",
+			logger.String("uuid", user.UUID),
+			logger.String("issuer", account.Issuer),
+			logger.String("subject", account.Subject))
+		return nil, nil, errors.New(errors.CodeEmailExists, "该邮箱已被其他账户使用，请先用原账户登录或联系管理员")
+	}
+
+	identity := newUserIdentity(&user, account)
+	if err := db.Create(identity).Error; err != nil {
+		logger.Error("关联外部身份失败", logger.String("uuid", user.UUID), logger.Err(err))
+		return nil, nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	logger.Info("外部身份已按邮箱关联到已有账户",
+		logger.String("uuid", user.UUID),
+		logger.String("issuer", account.Issuer),
+		logger.String("subject", account.Subject))
+	return &user, identity, nil
+}
+
+// provisionExternalUser 自动创建账户、用户档案和外部身份关联
+// 账户使用随机密码，只能通过外部身份源（或重置密码后）登录
+func provisionExternalUser(db *gorm.DB, account *externalAccount, role string) (*models.User, *models.UserIdentity, error) {
+	if account.Email == "" {
+		return nil, nil, errors.New(errors.CodeEmailRequired, "身份源没有返回邮箱，无法创建账户")
+	}
+
+	username, err := availableUsername(db, account)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	password, err := crypto.GenerateRandomBytes(externalPasswordSize)
+	if err != nil {
+		return nil, nil, errors.Wrap(errors.CodeCryptoError, err)
+	}
+	passwordHash, err := crypto.HashPassword(hex.EncodeToString(password))
+	if err != nil {
+		logger.Error("密码加密失败", logger.Err(err))
+		return nil, nil, errors.Wrap(errors.CodeCryptoError, err)
+	}
+
+	user := &models.User{
+		UUID:         uuid.New().String(),
+		Username:     username,
+		PasswordHash: passwordHash,
+		Status:       models.UserStatusActive,
+		Role:         role,
+	}
+	nickname := strings.TrimSpace(account.Name)
+	if nickname == "" {
+		nickname = username
+	}
+	if runes := []rune(nickname); len(runes) > externalNicknameMaxLen {
+		nickname = string(runes[:externalNicknameMaxLen])
+	}
+	identity := newUserIdentity(user, account)
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		profile := &models.UserProfile{
+			UserID:        user.ID,
+			Nickname:      nickname,
+			Email:         account.Email,
+			EmailVerified: account.EmailVerified,
+		}
+		if err := tx.Create(profile).Error; err != nil {
+			if appErr, ok := err.(*errors.AppError); ok {
+				return appErr
+			}
+			return errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		if err := tx.Create(identity).Error; err != nil {
+			return errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		return nil
+	}); err != nil {
+		logger.Error("自动创建账户失败",
+			logger.String("issuer", account.Issuer),
+			logger.String("username", username),
+			logger.String("email", account.Email),
+			logger.Err(err))
+		return nil, nil, err
+	}
+
+	logger.Info("外部身份账户已自动创建",
+		logger.String("uuid", user.UUID),
+		logger.String("username", user.Username),
+		logger.String("role", role),
+		logger.String("issuer", account.Issuer))
+	return user, identity, nil
+}
+
+// availableUsername 依次尝试候选用户名，都不可用时回退到外部身份的哈希；与已有用户名冲突时追加随机后缀
+func availableUsername(db *gorm.DB, account *externalAccount) (string, error) {
+	sum := sha256.Sum256([]byte(account.Issuer + "\x00" + account.Subject))
+	candidates := append(append([]string(nil), account.Usernames...), "ext_"+hex.EncodeToString(sum[:4]))
+
+	base := ""
+	for _, candidate := range candidates {
+		if base = sanitizeUsername(candidate); len(base) >= 3 {
+			break
+		}
+	}
+
+	username := base
+	for attempt := 0; attempt < 5; attempt++ {
+		var count int64
+		if err := db.Model(&models.User{}).Where("username = ?", username).Count(&count).Error; err != nil {
+			logger.Error("检查用户名失败", logger.String("username", username), logger.Err(err))
+			return "", errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		if count == 0 {
+			return username, nil
+		}
+		random, err := crypto.GenerateRandomBytes(3)
+		if err != nil {
+			return "", errors.Wrap(errors.CodeCryptoError, err)
+		}
+		suffix := hex.EncodeToString(random)
+		if len(base)+1+len(suffix) > externalUsernameMaxLen {
+			base = base[:externalUsernameMaxLen-1-len(suffix)]
+		}
+		username = base + "_" + suffix
+	}
+	return "", errors.New(errors.CodeUsernameExists, "无法生成可用的用户名")
+}
+
+// sanitizeUsername 只保留字母、数字和._-，截断到用户名的最大长度
+func sanitizeUsername(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '_' || r == '-' {
+			b.WriteRune(r)
+		}
+		if b.Len() == externalUsernameMaxLen {
+			break
+		}
+	}
+	return b.String()
+}
+
+// mapExternalRole 按映射（格式 值=角色）计算角色：第一个出现在values中的值决定角色，都不匹配时使用默认角色
+func mapExternalRole(mappings, values []string, defaultRole string) string {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	for _, mapping := range mappings {
+		value, role, _ := strings.Cut(mapping, "=")
+		if set[value] {
+			return role
+		}
+	}
+	return defaultRole
+}
+
+// syncExternalRole 角色与映射结果不一致时更新角色，用户已签发的令牌和登录会话全部失效
+// 更新后重新读取用户，保证之后签发的令牌使用新的令牌版本
+func syncExternalRole(db *gorm.DB, redis *redisClient.Client, user *models.User, role string) (bool, error) {
+	if user.Role == role {
+		return false, nil
+	}
+
+	previous := user.Role
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Update("role", role).Error; err != nil {
+			return err
+		}
+		return bumpTokenEpoch(tx, user.UUID)
+	}); err != nil {
+		logger.Error("同步外部身份用户角色失败", logger.String("uuid", user.UUID), logger.Err(err))
+		return false, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if err := db.Where("uuid = ?", user.UUID).First(user).Error; err != nil {
+		logger.Error("查询用户失败", logger.String("uuid", user.UUID), logger.Err(err))
+		return false, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if err := revokeAllSessions(db, redis, user.UUID); err != nil {
+		logger.Warn("删除用户会话失败", logger.String("uuid", user.UUID), logger.Err(err))
+	}
+
+	logger.Info("外部身份用户角色已同步",
+		logger.String("uuid", user.UUID),
+		logger.String("from", previous),
+		logger.String("to", role))
+	return true, nil
+}
+
+// touchExternalIdentity 更新外部身份最近一次登录的邮箱和时间，失败不影响登录
+func touchExternalIdentity(db *gorm.DB, identity *models.UserIdentity, email string) {
+	if err := db.Model(identity).Updates(map[string]interface{}{
+		"email":         email,
+		"last_login_at": time.Now(),
+	}).Error; err != nil {
+		logger.Warn("更新外部身份失败", logger.String("uuid", identity.UUID), logger.Err(err))
+	}
+}
+
+// newUserIdentity 创建外部身份关联记录
+func newUserIdentity(user *models.User, account *externalAccount) *models.UserIdentity {
+	return &models.UserIdentity{
+		UUID:     uuid.New().String(),
+		UserUUID: user.UUID,
+		Issuer:   account.Issuer,
+		Subject:  account.Subject,
+		Email:    account.Email,
+	}
+}
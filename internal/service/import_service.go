@@ -0,0 +1,370 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/importer"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 导入相关限制
+const (
+	maxImportEntries     = 10000 // 单次导入的最大条目数（与列表全量导出上限保持一致）
+	maxImportContentSize = 16 << 20
+	importBatchSize      = 100
+)
+
+// ImportItemStatus 单条导入结果状态
+type ImportItemStatus string
+
+const (
+	ImportStatusCreated     ImportItemStatus = "created"      // 已导入
+	ImportStatusWouldCreate ImportItemStatus = "would_create" // 预览模式下可以导入
+	ImportStatusSkipped     ImportItemStatus = "skipped"      // 已存在同名秘密，跳过
+	ImportStatusFailed      ImportItemStatus = "failed"       // 校验或加密失败
+)
+
+// ImportService 秘密批量导入服务
+type ImportService struct {
+	db                *gorm.DB
+	encryptionService *EncryptionService
+}
+
+// NewImportService 创建批量导入服务实例
+func NewImportService(db *gorm.DB, encryptionService *EncryptionService) *ImportService {
+	return &ImportService{
+		db:                db,
+		encryptionService: encryptionService,
+	}
+}
+
+// ImportSecretsRequest 批量导入秘密请求
+// 注意：UserUUID 由服务端从认证上下文中提取，不需要客户端传入
+type ImportSecretsRequest struct {
+	UserUUID    string               `json:"-"`                                                            // 不从请求体解析，由handler从上下文设置
+	SecurityPIN string               `json:"security_pin"`                                                 // 安全密码，预览模式下可不传
+	Format      importer.Format      `json:"format" binding:"required,oneof=dotenv csv bitwarden keepass"` // 文件格式
+	Content     string               `json:"content" binding:"required"`                                   // 文件内容（原始文本）
+	CSVMapping  *importer.CSVMapping `json:"csv_mapping"`                                                  // CSV列映射，format=csv时必填
+	// 无法从文件推断类型时使用的默认类型，默认other
+	DefaultType  models.SecretType `json:"default_type" binding:"omitempty,oneof=api_key db_credential certificate ssh_key token password other"`
+	Folder       string            `json:"folder"`        // 目标文件夹前缀，会拼接在源文件夹之前
+	Tags         []string          `json:"tags"`          // 追加到每条记录的标签
+	SkipExisting bool              `json:"skip_existing"` // 跳过已存在的同名秘密
+	DryRun       bool              `json:"dry_run"`       // 仅预览，不写入
+}
+
+// ImportItemResult 单条导入结果
+type ImportItemResult struct {
+	Index      int               `json:"index"`                 // 在源文件中的序号（dotenv/CSV为行号）
+	Name       string            `json:"name"`                  // 秘密名称
+	SecretType models.SecretType `json:"secret_type,omitempty"` // 映射后的秘密类型
+	Folder     string            `json:"folder,omitempty"`      // 映射后的文件夹
+	Tags       []string          `json:"tags,omitempty"`        // 映射后的标签
+	Status     ImportItemStatus  `json:"status"`                // 处理状态
+	SecretUUID string            `json:"secret_uuid,omitempty"` // 导入成功后的秘密UUID
+	Error      string            `json:"error,omitempty"`       // 失败原因
+}
+
+// ImportSecretsResponse 批量导入秘密响应
+type ImportSecretsResponse struct {
+	Format   importer.Format     `json:"format"`
+	DryRun   bool                `json:"dry_run"`
+	Total    int                 `json:"total"`    // 解析出的条目数
+	Imported int                 `json:"imported"` // 导入（或预览可导入）的条目数
+	Skipped  int                 `json:"skipped"`  // 跳过的条目数
+	Failed   int                 `json:"failed"`   // 失败的条目数
+	Items    []*ImportItemResult `json:"items"`
+}
+
+// pendingImport 待写入的条目
+type pendingImport struct {
+	result *ImportItemResult
+	entry  importer.Entry
+	meta   *models.SecretMetadata
+}
+
+// ImportSecrets 批量导入秘密
+// 整批只验证一次安全密码、派生一次DEK；单条记录的问题不会中断整批导入，而是记录在结果中
+func (s *ImportService) ImportSecrets(req *ImportSecretsRequest) (*ImportSecretsResponse, error) {
+	if len(req.Content) > maxImportContentSize {
+		return nil, errors.New(errors.CodeInvalidParam, "导入内容过大，最大支持16MB")
+	}
+	if !req.DryRun && req.SecurityPIN == "" {
+		return nil, errors.New(errors.CodeSecurityPINRequired, "导入秘密需要提供安全密码")
+	}
+	if req.DefaultType == "" {
+		req.DefaultType = models.SecretTypeOther
+	}
+
+	// 1. 解析文件
+	entries, err := importer.Parse(req.Format, strings.NewReader(req.Content), importer.Options{CSVMapping: req.CSVMapping})
+	if err != nil {
+		logger.Warn("解析导入文件失败", logger.String("user_uuid", req.UserUUID), logger.String("format", string(req.Format)), logger.Err(err))
+		return nil, errors.WithMessage(errors.CodeInvalidParam, "解析导入文件失败: "+err.Error(), err)
+	}
+	if len(entries) == 0 {
+		return nil, errors.New(errors.CodeInvalidParam, "导入文件中没有可导入的条目")
+	}
+	if len(entries) > maxImportEntries {
+		return nil, errors.New(errors.CodeInvalidParam, "单次导入条目数超过上限10000")
+	}
+
+	// 2. 查询已存在的秘密名称，用于检测冲突
+	existing, err := s.existingSecretNames(req.UserUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ImportSecretsResponse{
+		Format: req.Format,
+		DryRun: req.DryRun,
+		Total:  len(entries),
+		Items:  make([]*ImportItemResult, 0, len(entries)),
+	}
+
+	// 3. 映射并校验每条记录
+	var pending []pendingImport
+	seen := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		result, meta := s.mapEntry(req, entry)
+		resp.Items = append(resp.Items, result)
+
+		switch {
+		case result.Error != "":
+			result.Status = ImportStatusFailed
+		case seen[result.Name] > 0:
+			result.Status = ImportStatusFailed
+			result.Error = "与文件中的其他条目重名"
+		case existing[result.Name] && req.SkipExisting:
+			result.Status = ImportStatusSkipped
+			result.Error = "已存在同名秘密"
+		default:
+			result.Status = ImportStatusWouldCreate
+			pending = append(pending, pendingImport{result: result, entry: entry, meta: meta})
+		}
+		if result.Name != "" {
+			seen[result.Name]++
+		}
+	}
+
+	// 4. 加密并写入（预览模式跳过）
+	if !req.DryRun && len(pending) > 0 {
+		if err := s.encryptAndStore(req, pending); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, item := range resp.Items {
+		switch item.Status {
+		case ImportStatusCreated, ImportStatusWouldCreate:
+			resp.Imported++
+		case ImportStatusSkipped:
+			resp.Skipped++
+		case ImportStatusFailed:
+			resp.Failed++
+		}
+	}
+
+	logger.Info("批量导入秘密完成",
+		logger.String("user_uuid", req.UserUUID),
+		logger.String("format", string(req.Format)),
+		logger.Bool("dry_run", req.DryRun),
+		logger.Int("total", resp.Total),
+		logger.Int("imported", resp.Imported),
+		logger.Int("skipped", resp.Skipped),
+		logger.Int("failed", resp.Failed))
+
+	return resp, nil
+}
+
+// encryptAndStore 用同一个DEK加密所有待导入条目，并在一个事务中批量写入
+func (s *ImportService) encryptAndStore(req *ImportSecretsRequest, pending []pendingImport) error {
+	userKey, dek, err := s.encryptionService.unlockDEK(req.UserUUID, req.SecurityPIN)
+	if err != nil {
+		return err
+	}
+	defer crypto.ClearBytes(dek)
+
+	now := time.Now()
+	secrets := make([]models.EncryptedSecret, 0, len(pending))
+	stored := make([]*ImportItemResult, 0, len(pending))
+	for _, p := range pending {
+		encryptedData, nonce, authTag, err := crypto.EncryptAESGCM([]byte(p.entry.Value), dek)
+		if err != nil {
+			logger.Error("加密导入秘密失败", logger.Err(err), logger.Int("index", p.entry.Index))
+			p.result.Status = ImportStatusFailed
+			p.result.Error = "加密失败"
+			continue
+		}
+
+		fingerprint, strength, err := s.encryptionService.passwordHealth(p.result.SecretType, []byte(p.entry.Value), dek)
+		if err != nil {
+			p.result.Status = ImportStatusFailed
+			p.result.Error = "计算密码指纹失败"
+			continue
+		}
+
+		secretUUID := uuid.New().String()
+		secrets = append(secrets, models.EncryptedSecret{
+			UserUUID:            req.UserUUID,
+			SecretUUID:          secretUUID,
+			SecretName:          p.result.Name,
+			SecretType:          p.result.SecretType,
+			Description:         p.entry.Description,
+			EncryptedData:       encryptedData,
+			DEKVersion:          userKey.DEKVersion,
+			Nonce:               nonce,
+			AuthTag:             authTag,
+			Metadata:            withStrength(p.meta, strength),
+			PasswordFingerprint: fingerprint,
+			ValueChangedAt:      &now,
+		})
+		p.result.SecretUUID = secretUUID
+		stored = append(stored, p.result)
+	}
+
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(&secrets, importBatchSize).Error
+	})
+	if err != nil {
+		logger.Error("批量写入导入秘密失败", logger.Err(err), logger.String("user_uuid", req.UserUUID))
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	for _, result := range stored {
+		result.Status = ImportStatusCreated
+	}
+	return nil
+}
+
+// mapEntry 把解析结果映射为秘密类型、文件夹、标签和元数据
+func (s *ImportService) mapEntry(req *ImportSecretsRequest, entry importer.Entry) (*ImportItemResult, *models.SecretMetadata) {
+	result := &ImportItemResult{
+		Index: entry.Index,
+		Name:  entry.Name,
+		Error: entry.Error,
+	}
+
+	secretType := models.SecretType(entry.Type)
+	if entry.Type == "" {
+		secretType = req.DefaultType
+	}
+	if !isValidSecretType(secretType) {
+		result.Error = firstNonEmpty(result.Error, "不支持的秘密类型: "+entry.Type)
+	}
+	result.SecretType = secretType
+
+	if result.Name == "" {
+		result.Error = firstNonEmpty(result.Error, "名称不能为空")
+	} else if len(result.Name) > 255 {
+		result.Error = firstNonEmpty(result.Error, "名称长度不能超过255")
+	}
+	if entry.Value == "" {
+		result.Error = firstNonEmpty(result.Error, "秘密内容为空")
+	}
+
+	result.Folder = joinFolder(req.Folder, entry.Folder)
+	result.Tags = mergeTags(entry.Tags, req.Tags)
+
+	meta := &models.SecretMetadata{
+		Tags:   result.Tags,
+		Folder: result.Folder,
+	}
+	extra := make(map[string]interface{}, len(entry.Extra)+3)
+	for k, v := range entry.Extra {
+		extra[k] = v
+	}
+	if entry.Username != "" {
+		extra["username"] = entry.Username
+	}
+	if entry.URL != "" {
+		extra["url"] = entry.URL
+	}
+	extra["import_source"] = string(req.Format)
+	meta.Extra = extra
+
+	return result, meta
+}
+
+// existingSecretNames 查询用户已有的秘密名称集合
+func (s *ImportService) existingSecretNames(userUUID string) (map[string]bool, error) {
+	var names []string
+	if err := s.db.Model(&models.EncryptedSecret{}).
+		Where("user_uuid = ?", userUUID).
+		Pluck("secret_name", &names).Error; err != nil {
+		logger.Error("查询已有秘密名称失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	existing := make(map[string]bool, len(names))
+	for _, name := range names {
+		existing[name] = true
+	}
+	return existing, nil
+}
+
+// isValidSecretType 校验秘密类型
+func isValidSecretType(t models.SecretType) bool {
+	switch t {
+	case models.SecretTypeAPIKey, models.SecretTypeDBCredential, models.SecretTypeCertificate,
+		models.SecretTypeSSHKey, models.SecretTypeToken, models.SecretTypePassword, models.SecretTypeOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// joinFolder 拼接目标文件夹前缀和源文件夹
+func joinFolder(prefix, folder string) string {
+	prefix = strings.Trim(prefix, "/ ")
+	folder = strings.Trim(folder, "/ ")
+	switch {
+	case prefix == "":
+		return folder
+	case folder == "":
+		return prefix
+	default:
+		return prefix + "/" + folder
+	}
+}
+
+// mergeTags 合并标签并去重
+func mergeTags(groups ...[]string) []string {
+	var tags []string
+	seen := make(map[string]struct{})
+	for _, group := range groups {
+		for _, tag := range group {
+			tag = strings.TrimSpace(tag)
+			if tag == "" {
+				continue
+			}
+			if _, ok := seen[tag]; ok {
+				continue
+			}
+			seen[tag] = struct{}{}
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -254,6 +254,12 @@ func (s *KeyRotationService) RotateDEK(req *RotateDEKRequest) (*RotateDEKRespons
 			return errors.Wrap(errors.CodeDatabaseError, err)
 		}
 
+		// 紧急访问的私钥和发出的授权随DEK一起更新，保证可信联系人始终持有当前DEK
+		if err := rewrapEmergencyKeys(tx, &userKey, oldDEK, newDEK, newVersion); err != nil {
+			logger.Error("更新紧急访问密钥失败", logger.Err(err))
+			return err
+		}
+
 		return nil
 	})
 
@@ -315,9 +321,13 @@ func (s *KeyRotationService) migrateSecretsToNewDEK(userUUID string, oldVersion,
 		logger.Int("old_version", oldVersion),
 		logger.Int("new_version", newVersion))
 
+	// 先重新加密附件的文件密钥（每个附件只有32字节密钥，附件内容本身无需重新加密）
+	s.rewrapAttachmentKeys(userUUID, oldVersion, newVersion, oldDEK, newDEK)
+
 	// 获取需要迁移的秘密总数
+	// 回收站中的秘密（软删除）同样需要迁移，否则恢复后无法用新DEK解密
 	var total int64
-	if err := s.db.Model(&models.EncryptedSecret{}).
+	if err := s.db.Unscoped().Model(&models.EncryptedSecret{}).
 		Where("user_uuid = ? AND dek_version = ?", userUUID, oldVersion).
 		Count(&total).Error; err != nil {
 		logger.Error("获取待迁移秘密总数失败", logger.Err(err))
@@ -359,7 +369,7 @@ func (s *KeyRotationService) migrateSecretsToNewDEK(userUUID string, oldVersion,
 		}
 
 		var secrets []models.EncryptedSecret
-		err := s.db.Where("user_uuid = ? AND dek_version = ?", userUUID, oldVersion).
+		err := s.db.Unscoped().Where("user_uuid = ? AND dek_version = ?", userUUID, oldVersion).
 			Limit(batchSize).
 			Offset(offset).
 			Find(&secrets).Error
@@ -402,17 +412,28 @@ func (s *KeyRotationService) migrateSecretsToNewDEK(userUUID string, oldVersion,
 				continue
 			}
 
+			// 密码指纹的密钥由DEK派生，需要用新DEK重新计算（强度评分不变）
+			fingerprint, err := passwordFingerprint(secret.SecretType, plainData, newDEK)
+
 			// 清理明文数据
 			crypto.ClearBytes(plainData)
 
+			if err != nil {
+				task.mu.Lock()
+				task.FailedSecrets++
+				task.mu.Unlock()
+				continue
+			}
+
 			// 更新数据库
-			if err := s.db.Model(&models.EncryptedSecret{}).
+			if err := s.db.Unscoped().Model(&models.EncryptedSecret{}).
 				Where("id = ?", secret.ID).
 				Updates(map[string]interface{}{
-					"encrypted_data": newEncryptedData,
-					"nonce":          newNonce,
-					"auth_tag":       newAuthTag,
-					"dek_version":    newVersion,
+					"encrypted_data":       newEncryptedData,
+					"nonce":                newNonce,
+					"auth_tag":             newAuthTag,
+					"dek_version":          newVersion,
+					"password_fingerprint": fingerprint,
 				}).Error; err != nil {
 				logger.Error("更新秘密失败",
 					logger.Err(err),
@@ -449,6 +470,54 @@ func (s *KeyRotationService) migrateSecretsToNewDEK(userUUID string, oldVersion,
 	s.markMigrationCompleted(task, userUUID)
 }
 
+// rewrapAttachmentKeys 用新DEK重新加密附件的文件密钥
+// 单个附件失败只记录日志，不影响秘密的迁移
+func (s *KeyRotationService) rewrapAttachmentKeys(userUUID string, oldVersion, newVersion int, oldDEK, newDEK []byte) {
+	var attachments []models.SecretAttachment
+	if err := s.db.Select("id", "attachment_uuid", "encrypted_file_key", "key_nonce", "key_auth_tag").
+		Where("user_uuid = ? AND dek_version = ?", userUUID, oldVersion).
+		Find(&attachments).Error; err != nil {
+		logger.Error("查询待迁移附件失败", logger.Err(err), logger.String("user_uuid", userUUID))
+		return
+	}
+
+	failed := 0
+	for _, attachment := range attachments {
+		fileKey, err := crypto.DecryptAESGCM(attachment.EncryptedFileKey, oldDEK, attachment.KeyNonce, attachment.KeyAuthTag)
+		if err != nil {
+			logger.Error("用旧DEK解密文件密钥失败", logger.Err(err), logger.String("attachment_uuid", attachment.AttachmentUUID))
+			failed++
+			continue
+		}
+		encryptedFileKey, keyNonce, keyAuthTag, err := crypto.EncryptAESGCM(fileKey, newDEK)
+		crypto.ClearBytes(fileKey)
+		if err != nil {
+			logger.Error("用新DEK加密文件密钥失败", logger.Err(err), logger.String("attachment_uuid", attachment.AttachmentUUID))
+			failed++
+			continue
+		}
+
+		if err := s.db.Model(&models.SecretAttachment{}).
+			Where("id = ?", attachment.ID).
+			Updates(map[string]interface{}{
+				"encrypted_file_key": encryptedFileKey,
+				"key_nonce":          keyNonce,
+				"key_auth_tag":       keyAuthTag,
+				"dek_version":        newVersion,
+			}).Error; err != nil {
+			logger.Error("更新附件文件密钥失败", logger.Err(err), logger.Uint("attachment_id", attachment.ID))
+			failed++
+		}
+	}
+
+	if len(attachments) > 0 {
+		logger.Info("附件文件密钥迁移完成",
+			logger.String("user_uuid", userUUID),
+			logger.Int("total", len(attachments)),
+			logger.Int("failed", failed))
+	}
+}
+
 // markMigrationCompleted 标记迁移完成
 func (s *KeyRotationService) markMigrationCompleted(task *MigrationTask, userUUID string) {
 	now := time.Now()
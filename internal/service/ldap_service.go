@@ -0,0 +1,272 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/cuihe500/vaulthub/internal/config"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/ldap"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	redisClient "github.com/cuihe500/vaulthub/pkg/redis"
+	"gorm.io/gorm"
+)
+
+// LDAPIdentityIssuer LDAP用户在user_identities中的身份源标识，Subject为小写的用户DN
+const LDAPIdentityIssuer = "ldap"
+
+// LDAPService LDAP/Active Directory认证服务
+// 作为认证提供方验证目录中的用户名密码（先以服务账户搜索用户DN，再以用户DN绑定），
+// 按组映射角色，首次登录时关联或自动创建本地账户，并定期按组同步已关联用户的角色
+type LDAPService struct {
+	db     *gorm.DB
+	redis  *redisClient.Client
+	dialer *ldap.Config
+	cfg    config.LDAPConfig
+}
+
+// NewLDAPService 创建LDAP服务实例，dialer为nil表示未启用LDAP认证
+func NewLDAPService(db *gorm.DB, redis *redisClient.Client, dialer *ldap.Config, cfg config.LDAPConfig) *LDAPService {
+	return &LDAPService{
+		db:     db,
+		redis:  redis,
+		dialer: dialer,
+		cfg:    cfg,
+	}
+}
+
+// Enabled 是否启用了LDAP认证
+func (s *LDAPService) Enabled() bool {
+	return s.dialer != nil
+}
+
+// SyncEnabled 是否需要定期同步角色
+func (s *LDAPService) SyncEnabled() bool {
+	return s.Enabled() && s.cfg.SyncIntervalMinutes > 0
+}
+
+// SyncIntervalMinutes 定期同步角色的间隔
+func (s *LDAPService) SyncIntervalMinutes() int {
+	return s.cfg.SyncIntervalMinutes
+}
+
+// Name 提供方名称
+func (s *LDAPService) Name() string {
+	return AuthProviderLDAP
+}
+
+// Authenticate 在目录中查找用户并以用户DN绑定验证密码
+// 目录中没有该用户时返回 ErrAuthProviderSkip；每次登录都按组同步角色
+func (s *LDAPService) Authenticate(username, password string) (*models.User, error) {
+	// 空密码会被目录服务当作未认证绑定，必须在这里拒绝
+	if password == "" {
+		return nil, errors.New(errors.CodeInvalidCredentials, "")
+	}
+
+	conn, err := s.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	entry, err := s.findUser(conn, username)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	subject := strings.ToLower(entry.DN)
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsResultCode(err, ldap.ResultInvalidCredentials) {
+			logger.Warn("LDAP密码验证失败", logger.String("username", username))
+			// 已关联的本地账户同样计入登录失败锁定
+			user, _, _ := findExternalIdentity(s.db, LDAPIdentityIssuer, subject)
+			return user, errors.New(errors.CodeInvalidCredentials, "")
+		}
+		logger.Error("LDAP用户绑定失败", logger.String("username", username), logger.Err(err))
+		return nil, errors.New(errors.CodeExternalServiceError, "目录服务暂时不可用")
+	}
+
+	// 用户自身可能没有读取组的权限，以服务账户重新绑定后查询组
+	if err := s.bindService(conn); err != nil {
+		return nil, err
+	}
+	role, err := s.roleOf(conn, entry, username)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		logger.Warn("LDAP用户没有匹配的角色", logger.String("username", username), logger.String("dn", entry.DN))
+		return nil, errors.New(errors.CodeForbidden, "没有可用的角色，请联系管理员")
+	}
+
+	email := entry.Value(s.cfg.EmailAttribute)
+	account := &externalAccount{
+		Issuer:        LDAPIdentityIssuer,
+		Subject:       subject,
+		Usernames:     []string{entry.Value(s.cfg.UsernameAttribute), username},
+		Email:         email,
+		EmailVerified: false,             // 目录中的邮箱属性不能证明用户拥有该邮箱
+		LinkByEmail:   s.cfg.LinkByEmail, // 只有显式开启时才按邮箱关联已有账户
+		Name:          entry.Value(s.cfg.NameAttribute),
+	}
+	user, identity, err := resolveExternalUser(s.db, account, role, s.cfg.AutoProvision)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := syncExternalRole(s.db, s.redis, user, role); err != nil {
+		return nil, err
+	}
+	touchExternalIdentity(s.db, identity, email)
+	return user, nil
+}
+
+// SyncRoles 按目录中的组同步所有已关联LDAP用户的角色，返回角色被更新的用户数
+// 目录中已不存在或没有可用角色的用户只记录日志，下次登录时会被拒绝
+func (s *LDAPService) SyncRoles() (int, error) {
+	if !s.Enabled() {
+		return 0, nil
+	}
+
+	var identities []models.UserIdentity
+	if err := s.db.Where("issuer = ?", LDAPIdentityIssuer).Find(&identities).Error; err != nil {
+		logger.Error("查询LDAP用户失败", logger.Err(err))
+		return 0, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if len(identities) == 0 {
+		return 0, nil
+	}
+
+	conn, err := s.connect()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	updated := 0
+	for i := range identities {
+		identity := &identities[i]
+		entries, err := conn.Search(&ldap.SearchRequest{
+			BaseDN:     identity.Subject,
+			Scope:      ldap.ScopeBaseObject,
+			Attributes: []string{s.cfg.UsernameAttribute},
+		})
+		if err != nil && !ldap.IsResultCode(err, ldap.ResultNoSuchObject) {
+			logger.Error("查询LDAP用户失败", logger.String("dn", identity.Subject), logger.Err(err))
+			return updated, errors.New(errors.CodeExternalServiceError, "目录服务暂时不可用")
+		}
+		if len(entries) == 0 {
+			logger.Warn("LDAP用户已不在目录中", logger.String("dn", identity.Subject), logger.String("uuid", identity.UserUUID))
+			continue
+		}
+
+		role, err := s.roleOf(conn, entries[0], entries[0].Value(s.cfg.UsernameAttribute))
+		if err != nil {
+			return updated, err
+		}
+		if role == "" {
+			logger.Warn("LDAP用户没有匹配的角色", logger.String("dn", identity.Subject), logger.String("uuid", identity.UserUUID))
+			continue
+		}
+
+		var user models.User
+		if err := s.db.Where("uuid = ?", identity.UserUUID).First(&user).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				continue
+			}
+			logger.Error("查询用户失败", logger.String("uuid", identity.UserUUID), logger.Err(err))
+			return updated, errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		changed, err := syncExternalRole(s.db, s.redis, &user, role)
+		if err != nil {
+			return updated, err
+		}
+		if changed {
+			updated++
+		}
+	}
+
+	logger.Info("LDAP角色同步完成", logger.Int("users", len(identities)), logger.Int("updated", updated))
+	return updated, nil
+}
+
+// connect 连接目录服务并以服务账户绑定
+func (s *LDAPService) connect() (*ldap.Conn, error) {
+	conn, err := ldap.Dial(*s.dialer)
+	if err != nil {
+		logger.Error("连接LDAP服务失败", logger.String("url", s.dialer.URL), logger.Err(err))
+		return nil, errors.New(errors.CodeExternalServiceUnavailable, "目录服务暂时不可用")
+	}
+	if err := s.bindService(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// bindService 以服务账户绑定，没有配置服务账户时使用匿名绑定
+func (s *LDAPService) bindService(conn *ldap.Conn) error {
+	if s.cfg.BindDN == "" {
+		return nil
+	}
+	if err := conn.Bind(s.cfg.BindDN, s.cfg.BindPassword); err != nil {
+		logger.Error("LDAP服务账户绑定失败", logger.String("bind_dn", s.cfg.BindDN), logger.Err(err))
+		return errors.New(errors.CodeExternalServiceError, "目录服务暂时不可用")
+	}
+	return nil
+}
+
+// findUser 按登录用户名查找用户条目，找不到时返回 ErrAuthProviderSkip
+func (s *LDAPService) findUser(conn *ldap.Conn, username string) (*ldap.Entry, error) {
+	entries, err := conn.Search(&ldap.SearchRequest{
+		BaseDN:     s.cfg.UserBaseDN,
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     strings.ReplaceAll(s.cfg.UserFilter, "{username}", ldap.EscapeFilter(username)),
+		Attributes: []string{s.cfg.UsernameAttribute, s.cfg.EmailAttribute, s.cfg.NameAttribute},
+		SizeLimit:  2,
+	})
+	if err != nil && !ldap.IsResultCode(err, ldap.ResultSizeLimitExceeded) {
+		logger.Error("搜索LDAP用户失败", logger.String("username", username), logger.Err(err))
+		return nil, errors.New(errors.CodeExternalServiceError, "目录服务暂时不可用")
+	}
+	switch len(entries) {
+	case 0:
+		return nil, ErrAuthProviderSkip
+	case 1:
+		return entries[0], nil
+	}
+	// 过滤器匹配到多个用户时无法确定身份
+	logger.Warn("LDAP用户过滤器匹配到多个条目", logger.String("username", username))
+	return nil, errors.New(errors.CodeInvalidCredentials, "")
+}
+
+// roleOf 查询用户所属的组并按映射计算角色
+func (s *LDAPService) roleOf(conn *ldap.Conn, entry *ldap.Entry, username string) (string, error) {
+	if s.cfg.GroupBaseDN == "" || s.cfg.GroupFilter == "" {
+		return s.cfg.DefaultRole, nil
+	}
+
+	filter := strings.NewReplacer(
+		"{dn}", ldap.EscapeFilter(entry.DN),
+		"{username}", ldap.EscapeFilter(username),
+	).Replace(s.cfg.GroupFilter)
+	groups, err := conn.Search(&ldap.SearchRequest{
+		BaseDN:     s.cfg.GroupBaseDN,
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     filter,
+		Attributes: []string{s.cfg.GroupNameAttribute},
+	})
+	if err != nil {
+		logger.Error("搜索LDAP组失败", logger.String("dn", entry.DN), logger.Err(err))
+		return "", errors.New(errors.CodeExternalServiceError, "目录服务暂时不可用")
+	}
+
+	var names []string
+	for _, group := range groups {
+		names = append(names, group.Values(s.cfg.GroupNameAttribute)...)
+	}
+	return mapExternalRole(s.cfg.RoleMappings, names, s.cfg.DefaultRole), nil
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/config"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/ldap"
+	"github.com/cuihe500/vaulthub/pkg/ldaptest"
+)
+
+const (
+	ldapServiceDN = "cn=vaulthub,ou=services,dc=example,dc=com"
+	ldapAliceDN   = "uid=alice,ou=people,dc=example,dc=com"
+	ldapBobDN     = "uid=bob,ou=people,dc=example,dc=com"
+	ldapCarolDN   = "uid=carol (ops),ou=people,dc=example,dc=com"
+)
+
+// newLDAPTestService 启动目录服务并返回不依赖数据库的LDAP服务
+// alice属于vaulthub-admins和engineering，bob只属于engineering，carol的DN含括号且不属于任何组
+func newLDAPTestService(t *testing.T, cfg config.LDAPConfig) (*LDAPService, *ldaptest.Server) {
+	t.Helper()
+	directory := ldaptest.New()
+	t.Cleanup(directory.Close)
+
+	directory.AddEntry(ldapServiceDN, map[string][]string{"objectClass": {"applicationProcess"}, "cn": {"vaulthub"}})
+	directory.SetPassword(ldapServiceDN, "service-secret")
+	for dn, uid := range map[string]string{ldapAliceDN: "alice", ldapBobDN: "bob", ldapCarolDN: "carol"} {
+		directory.AddEntry(dn, map[string][]string{"objectClass": {"person"}, "uid": {uid}, "mail": {uid + "@example.com"}})
+		directory.SetPassword(dn, uid+"-secret")
+	}
+	directory.AddEntry("cn=vaulthub-admins,ou=groups,dc=example,dc=com", map[string][]string{
+		"objectClass": {"groupOfNames"}, "cn": {"vaulthub-admins"}, "member": {ldapAliceDN},
+	})
+	directory.AddEntry("cn=engineering,ou=groups,dc=example,dc=com", map[string][]string{
+		"objectClass": {"groupOfNames"}, "cn": {"engineering"}, "member": {ldapAliceDN, ldapBobDN},
+	})
+
+	cfg.BindDN = ldapServiceDN
+	cfg.BindPassword = "service-secret"
+	cfg.UserBaseDN = "ou=people,dc=example,dc=com"
+	cfg.UserFilter = "(&(objectClass=person)(uid={username}))"
+	cfg.UsernameAttribute = "uid"
+	cfg.EmailAttribute = "mail"
+	cfg.NameAttribute = "cn"
+	dialer := &ldap.Config{URL: directory.URL(), Timeout: 5 * time.Second}
+	return NewLDAPService(nil, nil, dialer, cfg), directory
+}
+
+// withGroups 按 member={dn} 查询组的配置
+func withGroups(mappings []string, defaultRole string) config.LDAPConfig {
+	return config.LDAPConfig{
+		GroupBaseDN:        "ou=groups,dc=example,dc=com",
+		GroupFilter:        "(&(objectClass=groupOfNames)(member={dn}))",
+		GroupNameAttribute: "cn",
+		RoleMappings:       mappings,
+		DefaultRole:        defaultRole,
+	}
+}
+
+func TestLDAPRoleMapping(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.LDAPConfig
+		dn          string
+		username    string
+		want        string
+		wantErrCode int
+	}{
+		{"按映射顺序取第一个匹配", withGroups([]string{"vaulthub-admins=admin", "engineering=user"}, ""), ldapAliceDN, "alice", "admin", 0},
+		{"映射顺序决定优先级", withGroups([]string{"engineering=user", "vaulthub-admins=admin"}, ""), ldapAliceDN, "alice", "user", 0},
+		{"只属于一个组", withGroups([]string{"vaulthub-admins=admin", "engineering=user"}, ""), ldapBobDN, "bob", "user", 0},
+		{"没有匹配时使用默认角色", withGroups([]string{"vaulthub-admins=admin"}, "readonly"), ldapBobDN, "bob", "readonly", 0},
+		{"没有匹配且没有默认角色", withGroups([]string{"vaulthub-admins=admin"}, ""), ldapBobDN, "bob", "", 0},
+		{"DN中的括号被转义", withGroups([]string{"engineering=user"}, "readonly"), ldapCarolDN, "carol", "readonly", 0},
+		{"未配置组时使用默认角色", config.LDAPConfig{DefaultRole: "user"}, ldapAliceDN, "alice", "user", 0},
+		{"组查询失败", func() config.LDAPConfig {
+			cfg := withGroups([]string{"engineering=user"}, "")
+			cfg.GroupBaseDN = "ou=missing,dc=example,dc=com"
+			return cfg
+		}(), ldapAliceDN, "alice", "", errors.CodeExternalServiceError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newLDAPTestService(t, tt.cfg)
+			conn, err := s.connect()
+			if err != nil {
+				t.Fatalf("connect: %v", err)
+			}
+			defer func() { _ = conn.Close() }()
+
+			role, err := s.roleOf(conn, &ldap.Entry{DN: tt.dn}, tt.username)
+			if tt.wantErrCode != 0 {
+				if appErr, ok := err.(*errors.AppError); !ok || appErr.Code != tt.wantErrCode {
+					t.Fatalf("err = %v, want code %d", err, tt.wantErrCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("roleOf: %v", err)
+			}
+			if role != tt.want {
+				t.Fatalf("role = %q, want %q", role, tt.want)
+			}
+		})
+	}
+}
+
+func TestLDAPRoleMappingFollowsDirectory(t *testing.T) {
+	s, directory := newLDAPTestService(t, withGroups([]string{"vaulthub-admins=admin", "engineering=user"}, ""))
+	conn, err := s.connect()
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	alice := &ldap.Entry{DN: ldapAliceDN}
+
+	// 移出管理员组后降为普通用户，再移出全部组后没有可用角色
+	directory.SetAttribute("cn=vaulthub-admins,ou=groups,dc=example,dc=com", "member")
+	if role, err := s.roleOf(conn, alice, "alice"); err != nil || role != "user" {
+		t.Fatalf("移出管理员组后 role = %q, %v", role, err)
+	}
+	directory.SetAttribute("cn=engineering,ou=groups,dc=example,dc=com", "member", ldapBobDN)
+	if role, err := s.roleOf(conn, alice, "alice"); err != nil || role != "" {
+		t.Fatalf("移出全部组后 role = %q, %v", role, err)
+	}
+}
+
+func TestLDAPFindUser(t *testing.T) {
+	s, directory := newLDAPTestService(t, config.LDAPConfig{})
+	conn, err := s.connect()
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	entry, err := s.findUser(conn, "alice")
+	if err != nil || entry == nil || entry.DN != ldapAliceDN {
+		t.Fatalf("findUser(alice) = %+v, %v", entry, err)
+	}
+	if got := entry.Value("mail"); got != "alice@example.com" {
+		t.Fatalf("mail = %q", got)
+	}
+
+	// 用户名中的过滤器字符被转义，不能匹配其他用户
+	for _, username := range []string{"nobody", "*", "a*", "*)(uid=*", "alice)(uid=bob"} {
+		if entry, err := s.findUser(conn, username); err != ErrAuthProviderSkip {
+			t.Fatalf("findUser(%q) = %+v, %v, want ErrAuthProviderSkip", username, entry, err)
+		}
+	}
+
+	// 过滤器匹配到多个条目时无法确定身份
+	directory.AddEntry("uid=alice,ou=contractors,ou=people,dc=example,dc=com", map[string][]string{
+		"objectClass": {"person"}, "uid": {"alice"},
+	})
+	_, err = s.findUser(conn, "alice")
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Code != errors.CodeInvalidCredentials {
+		t.Fatalf("多个条目: err = %v", err)
+	}
+}
+
+func TestLDAPAuthenticateRejectsEmptyPassword(t *testing.T) {
+	s, _ := newLDAPTestService(t, withGroups([]string{"engineering=user"}, ""))
+
+	// 空密码在连接目录之前就被拒绝，不会被当作匿名绑定
+	_, err := s.Authenticate("alice", "")
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Code != errors.CodeInvalidCredentials {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestLDAPServiceBindFailure(t *testing.T) {
+	s, _ := newLDAPTestService(t, config.LDAPConfig{})
+	s.cfg.BindPassword = "wrong"
+
+	_, err := s.connect()
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Code != errors.CodeExternalServiceError {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestMapExternalRole(t *testing.T) {
+	mappings := []string{"vaulthub-admins=admin", "engineering=user"}
+	tests := []struct {
+		values []string
+		want   string
+	}{
+		{[]string{"engineering", "vaulthub-admins"}, "admin"},
+		{[]string{"engineering"}, "user"},
+		{[]string{"Engineering"}, "readonly"}, // 组名区分大小写
+		{nil, "readonly"},
+	}
+	for _, tt := range tests {
+		if got := mapExternalRole(mappings, tt.values, "readonly"); got != tt.want {
+			t.Errorf("mapExternalRole(%v) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
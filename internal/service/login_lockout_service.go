@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/config"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	redisClient "github.com/cuihe500/vaulthub/pkg/redis"
+	"gorm.io/gorm"
+)
+
+// 登录失败锁定相关常量
+const (
+	loginFailurePrefix     = "login_failures:"
+	loginDelayPrefix       = "login_delay:"
+	loginDelayFreeAttempts = 3                // 同一账户前3次失败不等待
+	loginDelayMax          = 60 * time.Second // 单次等待时间上限
+	loginLockoutTimeLayout = "2006-01-02 15:04"
+)
+
+// recordLoginFailureScript 账户和IP的失败次数各加1，第一次失败时设置统计窗口，返回两个累计次数
+const recordLoginFailureScript = `
+local counts = {}
+for i, key in ipairs(KEYS) do
+  counts[i] = redis.call('INCR', key)
+  if counts[i] == 1 then
+    redis.call('PEXPIRE', key, ARGV[1])
+  end
+end
+return counts
+`
+
+// makeLoginFailureKey 生成登录失败计数在Redis中的key
+// 账户按用户名计数（不论用户是否存在），失败时的处理对存在和不存在的用户完全一致，不能用来探测用户名
+func makeLoginFailureKey(kind, value string) string {
+	return loginFailurePrefix + kind + ":" + value
+}
+
+// makeLoginDelayKey 生成登录等待标记在Redis中的key，标记存在期间拒绝登录尝试
+func makeLoginDelayKey(kind, value string) string {
+	return loginDelayPrefix + kind + ":" + value
+}
+
+// normalizeLoginUsername 用户名比较不区分大小写，计数时统一转为小写
+func normalizeLoginUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// loginDelay 计算超出免等待次数后的递增等待时间：1秒、2秒、4秒……最长60秒
+func loginDelay(failures, freeAttempts int64) time.Duration {
+	over := failures - freeAttempts
+	if over <= 0 {
+		return 0
+	}
+	if over > 7 {
+		return loginDelayMax
+	}
+	delay := time.Duration(1<<(over-1)) * time.Second
+	if delay > loginDelayMax {
+		return loginDelayMax
+	}
+	return delay
+}
+
+// LoginLockoutService 登录失败锁定服务
+// 按账户和IP统计密码（以及两步验证码）登录失败次数：超出免等待次数后每次尝试前需要等待递增的时间，
+// 账户失败次数达到阈值时自动锁定并邮件通知用户
+type LoginLockoutService struct {
+	db            *gorm.DB
+	redis         *redisClient.Client
+	configManager *config.ConfigManager
+	emailService  *EmailService
+}
+
+// NewLoginLockoutService 创建登录失败锁定服务实例
+func NewLoginLockoutService(db *gorm.DB, redis *redisClient.Client, configManager *config.ConfigManager, emailService *EmailService) *LoginLockoutService {
+	return &LoginLockoutService{
+		db:            db,
+		redis:         redis,
+		configManager: configManager,
+		emailService:  emailService,
+	}
+}
+
+// intConfig 读取非负整数配置，配置无效时使用默认值
+func (s *LoginLockoutService) intConfig(key, defaultValue string) int {
+	value := s.configManager.GetWithDefault(key, defaultValue)
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		logger.Warn("登录锁定配置无效，使用默认值", logger.String("key", key), logger.String("value", value))
+		n, _ = strconv.Atoi(defaultValue)
+	}
+	return n
+}
+
+// Check 检查账户和IP是否处于等待期，处于等待期时返回 CodeTooManyRequests
+// Redis不可用时不阻止登录
+func (s *LoginLockoutService) Check(username, ipAddress string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var wait time.Duration
+	keys := []string{makeLoginDelayKey("user", normalizeLoginUsername(username))}
+	if ipAddress != "" {
+		keys = append(keys, makeLoginDelayKey("ip", ipAddress))
+	}
+	for _, key := range keys {
+		ttl, err := s.redis.TTL(ctx, key)
+		if err != nil {
+			logger.Warn("查询登录等待状态失败", logger.String("key", key), logger.Err(err))
+			continue
+		}
+		if ttl > wait {
+			wait = ttl
+		}
+	}
+	if wait <= 0 {
+		return nil
+	}
+
+	seconds := int((wait + time.Second - 1) / time.Second)
+	return errors.New(errors.CodeTooManyRequests, fmt.Sprintf("登录失败次数过多，请%d秒后重试", seconds))
+}
+
+// RecordFailure 记录一次密码或两步验证码错误，user为nil表示用户名不存在
+// 账户失败次数达到阈值时锁定账户，调用方仍然只返回 CodeInvalidCredentials
+func (s *LoginLockoutService) RecordFailure(username, ipAddress string, user *models.User) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	name := normalizeLoginUsername(username)
+	window := time.Duration(s.intConfig(models.ConfigKeyLoginFailureWindowMinutes, models.ConfigValueLoginFailureWindowMinutesDefault)) * time.Minute
+	if window <= 0 {
+		window = 15 * time.Minute
+	}
+
+	keys := []string{makeLoginFailureKey("user", name)}
+	if ipAddress != "" {
+		keys = append(keys, makeLoginFailureKey("ip", ipAddress))
+	}
+	result, err := s.redis.Eval(ctx, recordLoginFailureScript, keys, window.Milliseconds())
+	if err != nil {
+		logger.Error("记录登录失败次数失败", logger.String("username", username), logger.Err(err))
+		return
+	}
+	counts, _ := result.([]interface{})
+	var userFailures, ipFailures int64
+	if len(counts) > 0 {
+		userFailures, _ = counts[0].(int64)
+	}
+	if len(counts) > 1 {
+		ipFailures, _ = counts[1].(int64)
+	}
+
+	ipThreshold := int64(s.intConfig(models.ConfigKeyLoginIPFailureThreshold, models.ConfigValueLoginIPFailureThresholdDefault))
+	s.setDelay(ctx, makeLoginDelayKey("user", name), loginDelay(userFailures, loginDelayFreeAttempts))
+	if ipAddress != "" && ipThreshold > 0 {
+		s.setDelay(ctx, makeLoginDelayKey("ip", ipAddress), loginDelay(ipFailures, ipThreshold))
+	}
+
+	logger.Warn("登录失败",
+		logger.String("username", username),
+		logger.String("ip", ipAddress),
+		logger.Int64("account_failures", userFailures),
+		logger.Int64("ip_failures", ipFailures))
+
+	threshold := int64(s.intConfig(models.ConfigKeyLoginLockoutThreshold, models.ConfigValueLoginLockoutThresholdDefault))
+	if user == nil || threshold == 0 || userFailures < threshold {
+		return
+	}
+	s.lock(ctx, user, ipAddress)
+}
+
+// RecordSuccess 登录验证全部通过后清除账户的失败次数和等待状态（IP的计数不清除）
+// 需要两步验证的账户只在第二步通过后调用
+func (s *LoginLockoutService) RecordSuccess(username string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	name := normalizeLoginUsername(username)
+	if err := s.redis.Del(ctx, makeLoginFailureKey("user", name), makeLoginDelayKey("user", name)); err != nil {
+		logger.Warn("清除登录失败次数失败", logger.String("username", username), logger.Err(err))
+	}
+}
+
+// setDelay 设置登录等待标记，delay为0时不设置
+func (s *LoginLockoutService) setDelay(ctx context.Context, key string, delay time.Duration) {
+	if delay <= 0 {
+		return
+	}
+	if err := s.redis.Set(ctx, key, "1", delay); err != nil {
+		logger.Warn("设置登录等待状态失败", logger.String("key", key), logger.Err(err))
+	}
+}
+
+// lock 锁定账户并清零失败次数，解锁后重新计数
+// 只锁定活跃账户，不覆盖已禁用或已被管理员锁定的状态
+func (s *LoginLockoutService) lock(ctx context.Context, user *models.User, ipAddress string) {
+	var lockedUntil *time.Time
+	if minutes := s.intConfig(models.ConfigKeyLoginLockoutMinutes, models.ConfigValueLoginLockoutMinutesDefault); minutes > 0 {
+		until := time.Now().Add(time.Duration(minutes) * time.Minute)
+		lockedUntil = &until
+	}
+
+	result := s.db.Model(&models.User{}).
+		Where("id = ? AND status = ?", user.ID, models.UserStatusActive).
+		Updates(map[string]interface{}{
+			"status":       models.UserStatusLocked,
+			"locked_until": lockedUntil,
+		})
+	if result.Error != nil {
+		logger.Error("锁定账户失败", logger.String("uuid", user.UUID), logger.Err(result.Error))
+		return
+	}
+	if result.RowsAffected == 0 {
+		return
+	}
+
+	if err := s.redis.Del(ctx, makeLoginFailureKey("user", normalizeLoginUsername(user.Username))); err != nil {
+		logger.Warn("清除登录失败次数失败", logger.String("uuid", user.UUID), logger.Err(err))
+	}
+	logger.Warn("登录失败次数过多，账户已锁定",
+		logger.String("uuid", user.UUID),
+		logger.String("username", user.Username),
+		logger.String("ip", ipAddress))
+
+	// 异步发送通知，避免邮件发送耗时让响应时间暴露锁定事件
+	unlockAt := ""
+	if lockedUntil != nil {
+		unlockAt = lockedUntil.Format(loginLockoutTimeLayout)
+	}
+	go s.notifyLocked(user.UUID, user.Username, ipAddress, unlockAt)
+}
+
+// notifyLocked 给用户发送账户锁定通知邮件，用户未设置邮箱时跳过
+func (s *LoginLockoutService) notifyLocked(userUUID, username, ipAddress, unlockAt string) {
+	var profile models.UserProfile
+	if err := s.db.Joins("JOIN users ON users.id = user_profiles.user_id").
+		Where("users.uuid = ?", userUUID).First(&profile).Error; err != nil || profile.Email == "" {
+		logger.Warn("用户未设置邮箱，跳过账户锁定通知", logger.String("user_uuid", userUUID))
+		return
+	}
+	if err := s.emailService.SendAccountLockedNotice(profile.Email, username, ipAddress, unlockAt); err != nil {
+		logger.Error("发送账户锁定通知失败", logger.String("user_uuid", userUUID), logger.Err(err))
+	}
+}
+
+// ReleaseExpiredLock 自动锁定到期时解锁账户，供登录、刷新令牌和认证中间件在检查用户状态前调用
+func ReleaseExpiredLock(db *gorm.DB, user *models.User) error {
+	if !user.LockExpired() {
+		return nil
+	}
+
+	if err := db.Model(&models.User{}).
+		Where("id = ? AND status = ?", user.ID, models.UserStatusLocked).
+		Updates(map[string]interface{}{
+			"status":       models.UserStatusActive,
+			"locked_until": nil,
+		}).Error; err != nil {
+		logger.Error("自动解锁账户失败", logger.String("uuid", user.UUID), logger.Err(err))
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	user.Status = models.UserStatusActive
+	user.LockedUntil = nil
+	logger.Info("账户锁定已到期，自动解锁", logger.String("uuid", user.UUID))
+	return nil
+}
+
+// checkLoginStatus 签发令牌前检查用户状态，自动锁定到期的账户先解锁
+func checkLoginStatus(db *gorm.DB, user *models.User) error {
+	if err := ReleaseExpiredLock(db, user); err != nil {
+		return err
+	}
+	if user.IsDisabled() {
+		return errors.New(errors.CodeAccountDisabled, "")
+	}
+	if user.IsLocked() {
+		return errors.New(errors.CodeAccountLocked, "")
+	}
+	if !user.IsActive() {
+		return errors.New(errors.CodeAccountNotActivated, "")
+	}
+	return nil
+}
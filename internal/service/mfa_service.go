@@ -0,0 +1,598 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/config"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	redisClient "github.com/cuihe500/vaulthub/pkg/redis"
+	"github.com/cuihe500/vaulthub/pkg/totp"
+	"gorm.io/gorm"
+)
+
+// 两步验证相关常量
+const (
+	mfaChallengePrefix       = "mfa_challenge:"
+	mfaChallengeTokenSize    = 32
+	mfaChallengeTTL          = 5 * time.Minute
+	mfaMaxFailedAttempts     = 5 // 同一次登录挑战连续输错达到该次数后作废，需要重新输入密码；每次输错同时计入账户的登录失败次数
+	mfaValidateSkew          = 1 // 允许前后各1个时间步（30秒）的时钟偏差
+	mfaBackupCodeCount       = 10
+	mfaBackupCodeLength      = 10
+	mfaBackupCodeAlphabet    = "abcdefghjkmnpqrstuvwxyz023456789" // 32个字符，去掉了易混淆的i、l、o、1
+	mfaChallengeFieldUser    = "user_uuid"
+	mfaChallengeFieldFailure = "failed_attempts"
+	mfaChallengeFieldExpired = "password_expired"
+)
+
+// recordMFAFailureScript 记录一次验证码错误，达到上限时删除挑战，返回累计错误次数
+const recordMFAFailureScript = `
+if redis.call('EXISTS', KEYS[1]) == 0 then
+  return 0
+end
+local failed = redis.call('HINCRBY', KEYS[1], 'failed_attempts', 1)
+if failed >= tonumber(ARGV[1]) then
+  redis.call('DEL', KEYS[1])
+end
+return failed
+`
+
+// MFAService 两步验证（TOTP）服务
+// 负责绑定/解绑认证器、备用码管理，以及两步登录中的挑战令牌
+type MFAService struct {
+	db            *gorm.DB
+	redis         *redisClient.Client
+	configManager *config.ConfigManager
+	serverKey     []byte // 加密TOTP密钥的服务端密钥
+}
+
+// NewMFAService 创建两步验证服务实例
+func NewMFAService(db *gorm.DB, redis *redisClient.Client, configManager *config.ConfigManager, serverKey []byte) *MFAService {
+	return &MFAService{
+		db:            db,
+		redis:         redis,
+		configManager: configManager,
+		serverKey:     serverKey,
+	}
+}
+
+// makeMFAChallengeKey 生成登录挑战在Redis中的key（只保存令牌的哈希）
+func makeMFAChallengeKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return mfaChallengePrefix + hex.EncodeToString(sum[:])
+}
+
+// IsRequired 判断角色是否被要求启用两步验证
+func (s *MFAService) IsRequired(role string) bool {
+	return roleListed(s.configManager.GetWithDefault(models.ConfigKeyMFARequiredRoles, ""), role)
+}
+
+// roleListed 判断角色是否在逗号分隔的角色列表配置中
+func roleListed(value, role string) bool {
+	for _, r := range strings.Split(value, ",") {
+		if strings.TrimSpace(r) == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEnabled 判断用户是否已启用两步验证
+func (s *MFAService) IsEnabled(userUUID string) (bool, error) {
+	mfa, err := s.getMFA(userUUID)
+	if err != nil {
+		return false, err
+	}
+	return mfa != nil && mfa.Enabled, nil
+}
+
+// getMFA 查询用户的两步验证配置，不存在时返回nil
+func (s *MFAService) getMFA(userUUID string) (*models.UserMFA, error) {
+	var mfa models.UserMFA
+	err := s.db.Where("user_uuid = ?", userUUID).First(&mfa).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Error("查询两步验证配置失败", logger.String("user_uuid", userUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return &mfa, nil
+}
+
+// MFAStatusResponse 两步验证状态
+type MFAStatusResponse struct {
+	Enabled              bool       `json:"enabled"`
+	EnabledAt            *time.Time `json:"enabled_at,omitempty"`
+	Required             bool       `json:"required"`               // 当前角色是否被要求启用
+	BackupCodesRemaining int64      `json:"backup_codes_remaining"` // 未使用的备用码数量
+}
+
+// GetStatus 查询用户的两步验证状态
+func (s *MFAService) GetStatus(userUUID, role string) (*MFAStatusResponse, error) {
+	mfa, err := s.getMFA(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &MFAStatusResponse{Required: s.IsRequired(role)}
+	if mfa == nil || !mfa.Enabled {
+		return resp, nil
+	}
+	resp.Enabled = true
+	resp.EnabledAt = mfa.EnabledAt
+
+	if err := s.db.Model(&models.MFABackupCode{}).
+		Where("user_uuid = ? AND used_at IS NULL", userUUID).
+		Count(&resp.BackupCodesRemaining).Error; err != nil {
+		logger.Error("统计备用码失败", logger.String("user_uuid", userUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return resp, nil
+}
+
+// MFAEnrollmentResponse 绑定认证器响应
+type MFAEnrollmentResponse struct {
+	Secret     string `json:"secret"`      // Base32编码的密钥，供无法扫码时手动输入
+	OTPAuthURI string `json:"otpauth_uri"` // 生成二维码用的otpauth URI
+}
+
+// BeginEnrollment 生成新的TOTP密钥，等待用户用第一个验证码确认
+// 未确认前重复调用会替换密钥；已启用时需要先停用
+func (s *MFAService) BeginEnrollment(userUUID, username string) (*MFAEnrollmentResponse, error) {
+	mfa, err := s.getMFA(userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa != nil && mfa.Enabled {
+		return nil, errors.New(errors.CodeResourceAlreadyExists, "两步验证已启用")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		logger.Error("生成TOTP密钥失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCryptoError, err)
+	}
+	encrypted, err := s.encryptSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if mfa == nil {
+		err = s.db.Create(&models.UserMFA{UserUUID: userUUID, EncryptedSecret: encrypted}).Error
+	} else {
+		err = s.db.Model(mfa).Updates(map[string]interface{}{
+			"encrypted_secret": encrypted,
+			"last_used_step":   0,
+		}).Error
+	}
+	if err != nil {
+		logger.Error("保存TOTP密钥失败", logger.String("user_uuid", userUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	issuer := s.configManager.GetWithDefault(models.ConfigKeyMFAIssuer, models.ConfigValueMFAIssuerDefault)
+	logger.Info("生成TOTP密钥，等待确认", logger.String("user_uuid", userUUID))
+	return &MFAEnrollmentResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(issuer, username, secret),
+	}, nil
+}
+
+// MFACodeRequest 两步验证码请求
+// 确认绑定时只接受认证器验证码；其他场景也可以使用备用码
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+// MFABackupCodesResponse 备用码响应，备用码只返回这一次
+type MFABackupCodesResponse struct {
+	BackupCodes []string `json:"backup_codes"`
+}
+
+// ConfirmEnrollment 用认证器的第一个验证码确认绑定，启用两步验证并生成备用码
+func (s *MFAService) ConfirmEnrollment(userUUID, code string) (*MFABackupCodesResponse, error) {
+	mfa, err := s.getMFA(userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil {
+		return nil, errors.New(errors.CodeResourceNotFound, "请先绑定认证器")
+	}
+	if mfa.Enabled {
+		return nil, errors.New(errors.CodeResourceAlreadyExists, "两步验证已启用")
+	}
+
+	if err := s.verifyTOTP(mfa, code); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(mfa).Updates(map[string]interface{}{
+			"enabled":    true,
+			"enabled_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		var err error
+		codes, err = s.replaceBackupCodes(tx, userUUID)
+		return err
+	})
+	if err != nil {
+		logger.Error("启用两步验证失败", logger.String("user_uuid", userUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	logger.Info("两步验证已启用", logger.String("user_uuid", userUUID))
+	return &MFABackupCodesResponse{BackupCodes: codes}, nil
+}
+
+// Disable 停用两步验证，需要认证器验证码或备用码
+// 角色被要求启用两步验证时不允许停用
+func (s *MFAService) Disable(userUUID, role, code string) error {
+	if s.IsRequired(role) {
+		return errors.New(errors.CodeOperationNotAllowed, "当前角色必须启用两步验证")
+	}
+	if err := s.Verify(userUUID, code); err != nil {
+		return err
+	}
+	if err := s.deleteMFA(userUUID); err != nil {
+		return err
+	}
+
+	logger.Info("两步验证已停用", logger.String("user_uuid", userUUID))
+	return nil
+}
+
+// RegenerateBackupCodes 重新生成备用码，旧备用码全部作废
+func (s *MFAService) RegenerateBackupCodes(userUUID, code string) (*MFABackupCodesResponse, error) {
+	if err := s.Verify(userUUID, code); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		codes, err = s.replaceBackupCodes(tx, userUUID)
+		return err
+	})
+	if err != nil {
+		logger.Error("重新生成备用码失败", logger.String("user_uuid", userUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	logger.Info("备用码已重新生成", logger.String("user_uuid", userUUID))
+	return &MFABackupCodesResponse{BackupCodes: codes}, nil
+}
+
+// Reset 管理员重置用户的两步验证（用户丢失认证器和备用码时使用）
+// 重置后用户下次登录时，如果角色被要求启用两步验证，会被引导重新绑定
+func (s *MFAService) Reset(userUUID string) (*models.SafeUser, error) {
+	var user models.User
+	if err := s.db.Where("uuid = ?", userUUID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeResourceNotFound, "用户不存在")
+		}
+		logger.Error("查询用户失败", logger.String("uuid", userUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	if err := s.deleteMFA(userUUID); err != nil {
+		return nil, err
+	}
+
+	logger.Info("管理员重置两步验证", logger.String("user_uuid", userUUID))
+	return user.ToSafeUser(), nil
+}
+
+// deleteMFA 物理删除两步验证配置和备用码，避免软删除记录占用唯一索引
+func (s *MFAService) deleteMFA(userUUID string) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_uuid = ?", userUUID).Delete(&models.MFABackupCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("user_uuid = ?", userUUID).Delete(&models.UserMFA{}).Error
+	})
+	if err != nil {
+		logger.Error("删除两步验证配置失败", logger.String("user_uuid", userUUID), logger.Err(err))
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return nil
+}
+
+// Verify 校验已启用两步验证的用户提交的认证器验证码或备用码
+func (s *MFAService) Verify(userUUID, code string) error {
+	mfa, err := s.getMFA(userUUID)
+	if err != nil {
+		return err
+	}
+	if mfa == nil || !mfa.Enabled {
+		return errors.New(errors.CodeOperationNotAllowed, "未启用两步验证")
+	}
+
+	// 6位数字为认证器验证码，其他视为备用码
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits && isDigits(code) {
+		return s.verifyTOTP(mfa, code)
+	}
+	return s.useBackupCode(userUUID, code)
+}
+
+// verifyTOTP 校验认证器验证码，每个时间步的验证码只能使用一次
+func (s *MFAService) verifyTOTP(mfa *models.UserMFA, code string) error {
+	secret, err := s.decryptSecret(mfa.EncryptedSecret)
+	if err != nil {
+		return err
+	}
+
+	step, ok, err := totp.Validate(secret, code, time.Now(), mfaValidateSkew)
+	if err != nil {
+		logger.Error("校验TOTP验证码失败", logger.String("user_uuid", mfa.UserUUID), logger.Err(err))
+		return errors.Wrap(errors.CodeCryptoError, err)
+	}
+	if !ok {
+		return errors.New(errors.CodeInvalidMFACode, "")
+	}
+
+	// 条件更新保证并发请求中同一验证码只有一个能通过
+	result := s.db.Model(&models.UserMFA{}).
+		Where("id = ? AND last_used_step < ?", mfa.ID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		logger.Error("更新TOTP时间步失败", logger.String("user_uuid", mfa.UserUUID), logger.Err(result.Error))
+		return errors.Wrap(errors.CodeDatabaseError, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		logger.Warn("TOTP验证码重复使用", logger.String("user_uuid", mfa.UserUUID))
+		return errors.New(errors.CodeInvalidMFACode, "验证码已使用，请等待下一个验证码")
+	}
+	return nil
+}
+
+// useBackupCode 校验并消耗一个备用码
+func (s *MFAService) useBackupCode(userUUID, code string) error {
+	normalized := normalizeBackupCode(code)
+	if len(normalized) != mfaBackupCodeLength {
+		return errors.New(errors.CodeInvalidMFACode, "")
+	}
+
+	var codes []models.MFABackupCode
+	if err := s.db.Where("user_uuid = ? AND used_at IS NULL", userUUID).Find(&codes).Error; err != nil {
+		logger.Error("查询备用码失败", logger.String("user_uuid", userUUID), logger.Err(err))
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	for _, c := range codes {
+		if !crypto.VerifyPassword(normalized, c.CodeHash) {
+			continue
+		}
+		result := s.db.Model(&models.MFABackupCode{}).
+			Where("id = ? AND used_at IS NULL", c.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			logger.Error("标记备用码已使用失败", logger.String("user_uuid", userUUID), logger.Err(result.Error))
+			return errors.Wrap(errors.CodeDatabaseError, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			break // 并发请求已使用了该备用码
+		}
+		logger.Info("使用备用码通过两步验证", logger.String("user_uuid", userUUID), logger.Int("remaining", len(codes)-1))
+		return nil
+	}
+	return errors.New(errors.CodeInvalidMFACode, "")
+}
+
+// replaceBackupCodes 在事务中删除旧备用码并生成新的备用码，返回明文
+func (s *MFAService) replaceBackupCodes(tx *gorm.DB, userUUID string) ([]string, error) {
+	if err := tx.Unscoped().Where("user_uuid = ?", userUUID).Delete(&models.MFABackupCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, mfaBackupCodeCount)
+	for i := 0; i < mfaBackupCodeCount; i++ {
+		code, err := generateBackupCode()
+		if err != nil {
+			return nil, err
+		}
+		hash, err := crypto.HashPassword(code)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Create(&models.MFABackupCode{UserUUID: userUUID, CodeHash: hash}).Error; err != nil {
+			return nil, err
+		}
+		// 返回给用户时分成两段，便于抄写
+		codes = append(codes, code[:mfaBackupCodeLength/2]+"-"+code[mfaBackupCodeLength/2:])
+	}
+	return codes, nil
+}
+
+// generateBackupCode 生成一个随机备用码
+func generateBackupCode() (string, error) {
+	random, err := crypto.GenerateRandomBytes(mfaBackupCodeLength)
+	if err != nil {
+		return "", err
+	}
+	code := make([]byte, mfaBackupCodeLength)
+	for i, b := range random {
+		code[i] = mfaBackupCodeAlphabet[int(b)%len(mfaBackupCodeAlphabet)] // 字母表长度整除256，没有取模偏差
+	}
+	return string(code), nil
+}
+
+// normalizeBackupCode 去掉备用码中的分隔符和空格并转为小写
+func normalizeBackupCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// isDigits 判断字符串是否全部为数字
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// encryptSecret 使用服务端密钥加密TOTP密钥，格式与DEK相同：[密文][nonce][认证标签]
+func (s *MFAService) encryptSecret(secret string) ([]byte, error) {
+	ciphertext, nonce, authTag, err := crypto.EncryptAESGCM([]byte(secret), s.serverKey)
+	if err != nil {
+		logger.Error("加密TOTP密钥失败", logger.Err(err))
+		return nil, err
+	}
+	blob := make([]byte, 0, len(ciphertext)+len(nonce)+len(authTag))
+	blob = append(blob, ciphertext...)
+	blob = append(blob, nonce...)
+	blob = append(blob, authTag...)
+	return blob, nil
+}
+
+// decryptSecret 解密TOTP密钥
+func (s *MFAService) decryptSecret(blob []byte) (string, error) {
+	overhead := crypto.GCMNonceSize + crypto.GCMTagSize
+	if len(blob) < overhead {
+		return "", errors.New(errors.CodeCryptoError, "无效的TOTP密钥数据")
+	}
+	ciphertext := blob[:len(blob)-overhead]
+	nonce := blob[len(blob)-overhead : len(blob)-crypto.GCMTagSize]
+	authTag := blob[len(blob)-crypto.GCMTagSize:]
+
+	secret, err := crypto.DecryptAESGCM(ciphertext, s.serverKey, nonce, authTag)
+	if err != nil {
+		// 通常是 security.encryption_key 被修改，需要管理员重置该用户的两步验证
+		logger.Error("解密TOTP密钥失败", logger.Err(err))
+		return "", err
+	}
+	return string(secret), nil
+}
+
+// CreateChallenge 密码验证通过后创建两步登录挑战，返回挑战令牌
+// passwordExpired 表示本次登录的本地密码已过期，两步验证通过后才允许修改密码
+func (s *MFAService) CreateChallenge(userUUID string, passwordExpired bool) (string, error) {
+	tokenBytes, err := crypto.GenerateRandomBytes(mfaChallengeTokenSize)
+	if err != nil {
+		logger.Error("生成两步验证挑战令牌失败", logger.Err(err))
+		return "", errors.Wrap(errors.CodeCryptoError, err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	expired := 0
+	if passwordExpired {
+		expired = 1
+	}
+	values := map[string]interface{}{
+		mfaChallengeFieldUser:    userUUID,
+		mfaChallengeFieldFailure: 0,
+		mfaChallengeFieldExpired: expired,
+	}
+	if err := s.redis.HSetWithExpiration(ctx, makeMFAChallengeKey(token), values, mfaChallengeTTL); err != nil {
+		logger.Error("保存两步验证挑战失败", logger.String("user_uuid", userUUID), logger.Err(err))
+		return "", errors.Wrap(errors.CodeCacheError, err)
+	}
+	return token, nil
+}
+
+// ChallengeUser 返回挑战令牌对应的用户UUID，令牌不存在或已过期时返回错误
+func (s *MFAService) ChallengeUser(token string) (string, error) {
+	fields, err := s.getChallenge(token)
+	if err != nil {
+		return "", err
+	}
+	return fields[mfaChallengeFieldUser], nil
+}
+
+// getChallenge 读取挑战令牌对应的挑战，令牌不存在或已过期时返回错误
+func (s *MFAService) getChallenge(token string) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	fields, err := s.redis.HGetAll(ctx, makeMFAChallengeKey(token))
+	if err != nil {
+		logger.Error("读取两步验证挑战失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCacheError, err)
+	}
+	if fields[mfaChallengeFieldUser] == "" {
+		return nil, errors.New(errors.CodeTokenExpired, "两步验证已过期，请重新登录")
+	}
+	return fields, nil
+}
+
+// MFAChallengeResult 两步登录挑战验证通过的结果
+type MFAChallengeResult struct {
+	UserUUID        string
+	BackupCodes     []string // 登录时完成绑定才返回
+	PasswordExpired bool     // 本次登录的本地密码已过期，需要先修改密码
+}
+
+// CompleteChallenge 校验两步登录挑战中提交的验证码，通过后作废挑战令牌
+// 已启用两步验证时接受认证器验证码或备用码；
+// 角色被要求启用但尚未启用时，验证码用于确认绑定，此时返回新生成的备用码
+func (s *MFAService) CompleteChallenge(token, code string) (*MFAChallengeResult, error) {
+	fields, err := s.getChallenge(token)
+	if err != nil {
+		return nil, err
+	}
+	userUUID := fields[mfaChallengeFieldUser]
+
+	var backupCodes []string
+	enabled, err := s.IsEnabled(userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		err = s.Verify(userUUID, code)
+	} else {
+		var resp *MFABackupCodesResponse
+		if resp, err = s.ConfirmEnrollment(userUUID, code); err == nil {
+			backupCodes = resp.BackupCodes
+		}
+	}
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok && appErr.Code == errors.CodeInvalidMFACode {
+			s.recordChallengeFailure(token, userUUID)
+		}
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := s.redis.Del(ctx, makeMFAChallengeKey(token)); err != nil {
+		logger.Warn("删除两步验证挑战失败", logger.String("user_uuid", userUUID), logger.Err(err))
+	}
+	return &MFAChallengeResult{
+		UserUUID:        userUUID,
+		BackupCodes:     backupCodes,
+		PasswordExpired: fields[mfaChallengeFieldExpired] == "1",
+	}, nil
+}
+
+// recordChallengeFailure 记录一次验证码错误，错误次数过多时作废挑战
+func (s *MFAService) recordChallengeFailure(token, userUUID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	failed, err := s.redis.Eval(ctx, recordMFAFailureScript, []string{makeMFAChallengeKey(token)}, mfaMaxFailedAttempts)
+	if err != nil {
+		logger.Error("记录两步验证错误次数失败", logger.Err(err))
+		return
+	}
+	attempts, _ := failed.(int64)
+	logger.Warn("两步验证码错误",
+		logger.String("user_uuid", userUUID),
+		logger.Int64("failed_attempts", attempts),
+		logger.Bool("challenge_revoked", attempts >= mfaMaxFailedAttempts))
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
+	"strings"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/config"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/cuihe500/vaulthub/pkg/oidc"
+	redisClient "github.com/cuihe500/vaulthub/pkg/redis"
+	"gorm.io/gorm"
+)
+
+// OIDC登录相关常量
+const (
+	oidcStatePrefix = "oidc_state:"
+	oidcStateTTL    = 10 * time.Minute // 从跳转到身份提供方到回调的最长时间
+	oidcStateSize   = 32
+	oidcTimeout     = 15 * time.Second // 访问身份提供方的超时时间
+)
+
+// OIDCService OpenID Connect单点登录服务
+// 负责授权码+PKCE流程、ID令牌验证、外部身份与本地账户的关联、自动创建账户和按声明映射角色
+type OIDCService struct {
+	db       *gorm.DB
+	redis    *redisClient.Client
+	provider *oidc.Provider
+	cfg      config.OIDCConfig
+}
+
+// NewOIDCService 创建OIDC服务实例，provider为nil表示未启用单点登录
+func NewOIDCService(db *gorm.DB, redis *redisClient.Client, provider *oidc.Provider, cfg config.OIDCConfig) *OIDCService {
+	return &OIDCService{
+		db:       db,
+		redis:    redis,
+		provider: provider,
+		cfg:      cfg,
+	}
+}
+
+// oidcLoginState 一次登录流程保存在Redis中的状态
+type oidcLoginState struct {
+	CodeVerifier string `json:"code_verifier"`
+	Nonce        string `json:"nonce"`
+}
+
+// OIDCLoginBeginResponse 开始单点登录响应
+type OIDCLoginBeginResponse struct {
+	AuthorizationURL string `json:"authorization_url"` // 前端跳转到该地址完成身份提供方登录
+	State            string `json:"state"`             // 回调时身份提供方原样返回，完成登录时提交
+}
+
+// Enabled 是否启用了单点登录
+func (s *OIDCService) Enabled() bool {
+	return s.provider != nil
+}
+
+// BeginLogin 开始单点登录，生成state、nonce和PKCE参数并返回授权地址
+func (s *OIDCService) BeginLogin() (*OIDCLoginBeginResponse, error) {
+	if !s.Enabled() {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "未启用单点登录")
+	}
+
+	state, err := oidc.RandomString(oidcStateSize)
+	if err != nil {
+		return nil, errors.Wrap(errors.CodeCryptoError, err)
+	}
+	nonce, err := oidc.RandomString(oidcStateSize)
+	if err != nil {
+		return nil, errors.Wrap(errors.CodeCryptoError, err)
+	}
+	verifier, challenge, err := oidc.GeneratePKCE()
+	if err != nil {
+		return nil, errors.Wrap(errors.CodeCryptoError, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oidcTimeout)
+	defer cancel()
+
+	authURL, err := s.provider.AuthCodeURL(ctx, state, nonce, challenge)
+	if err != nil {
+		logger.Error("获取身份提供方发现文档失败", logger.Err(err))
+		return nil, errors.New(errors.CodeExternalServiceUnavailable, "身份提供方暂时不可用")
+	}
+
+	data, err := json.Marshal(oidcLoginState{CodeVerifier: verifier, Nonce: nonce})
+	if err != nil {
+		return nil, errors.Wrap(errors.CodeInternalError, err)
+	}
+	if err := s.redis.Set(ctx, makeOIDCStateKey(state), data, oidcStateTTL); err != nil {
+		logger.Error("保存单点登录状态失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCacheError, err)
+	}
+
+	return &OIDCLoginBeginResponse{AuthorizationURL: authURL, State: state}, nil
+}
+
+// FinishLogin 用回调中的授权码完成单点登录，返回关联（或自动创建）的本地用户
+// 每次登录都按角色映射同步用户角色；用户状态和两步验证由调用方检查
+func (s *OIDCService) FinishLogin(code, state string) (*models.User, error) {
+	if !s.Enabled() {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "未启用单点登录")
+	}
+
+	loginState, err := s.takeState(state)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oidcTimeout)
+	defer cancel()
+
+	tokens, err := s.provider.Exchange(ctx, code, loginState.CodeVerifier)
+	if err != nil {
+		logger.Warn("单点登录授权码换取令牌失败", logger.Err(err))
+		return nil, errors.New(errors.CodeInvalidCredentials, "单点登录授权码无效或已过期")
+	}
+	claims, err := s.provider.VerifyIDToken(ctx, tokens.IDToken, loginState.Nonce)
+	if err != nil {
+		if stderrors.Is(err, oidc.ErrInvalidIDToken) {
+			logger.Warn("单点登录ID令牌验证失败", logger.Err(err))
+			return nil, errors.New(errors.CodeInvalidToken, "身份提供方返回的ID令牌无效")
+		}
+		logger.Error("验证单点登录ID令牌失败", logger.Err(err))
+		return nil, errors.New(errors.CodeExternalServiceUnavailable, "身份提供方暂时不可用")
+	}
+
+	role := s.cfg.DefaultRole
+	if s.cfg.RoleClaim != "" {
+		role = mapExternalRole(s.cfg.RoleMappings, claims.StringValues(s.cfg.RoleClaim), s.cfg.DefaultRole)
+	}
+	if role == "" {
+		logger.Warn("单点登录用户没有匹配的角色",
+			logger.String("issuer", claims.Issuer),
+			logger.String("subject", claims.Subject))
+		return nil, errors.New(errors.CodeForbidden, "没有可用的角色，请联系管理员")
+	}
+
+	account := &externalAccount{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Usernames:     claims.StringValues(s.cfg.UsernameClaim),
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		LinkByEmail:   claims.EmailVerified, // 身份提供方验证过的邮箱可以关联已有账户
+		Name:          claims.Name,
+	}
+	// 用户名声明不可用时回退到邮箱前缀
+	if local, _, ok := strings.Cut(claims.Email, "@"); ok {
+		account.Usernames = append(account.Usernames, local)
+	}
+
+	user, identity, err := resolveExternalUser(s.db, account, role, s.cfg.AutoProvision)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := syncExternalRole(s.db, s.redis, user, role); err != nil {
+		return nil, err
+	}
+	touchExternalIdentity(s.db, identity, claims.Email)
+	return user, nil
+}
+
+// takeState 读取并删除登录状态，保证每个state只能使用一次
+func (s *OIDCService) takeState(state string) (*oidcLoginState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	value, err := s.redis.Eval(ctx, takeSessionScript, []string{makeOIDCStateKey(state)})
+	if err != nil {
+		logger.Error("读取单点登录状态失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCacheError, err)
+	}
+	data, _ := value.(string)
+	if data == "" {
+		return nil, errors.New(errors.CodeTokenExpired, "单点登录已过期，请重新开始")
+	}
+	var loginState oidcLoginState
+	if err := json.Unmarshal([]byte(data), &loginState); err != nil {
+		logger.Error("解析单点登录状态失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeInternalError, err)
+	}
+	return &loginState, nil
+}
+
+// makeOIDCStateKey 生成登录状态在Redis中的key（只保存state的哈希）
+func makeOIDCStateKey(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return oidcStatePrefix + hex.EncodeToString(sum[:])
+}
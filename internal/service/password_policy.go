@@ -0,0 +1,221 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cuihe500/vaulthub/internal/config"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/breach"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// minUserInfoLength 用户名或邮箱前缀短于该长度时不检查，避免误伤
+const minUserInfoLength = 3
+
+// PasswordPolicy 登录密码策略，由 system_config 中的密码策略配置组成
+type PasswordPolicy struct {
+	MinLength      int      // 最小长度（字符数）
+	MinCharClasses int      // 至少包含大写字母、小写字母、数字、特殊字符中的几种
+	BannedWords    []string // 禁止出现的词（已转为小写）
+	DisallowUser   bool     // 禁止包含用户名或邮箱前缀
+	HistoryCount   int      // 最近几次使用过的密码（含当前密码）不能再次使用
+	MaxAgeDays     int      // 最长使用天数，0表示不限制
+}
+
+// PasswordPolicyService 登录密码策略服务
+// 注册、修改密码、邮件重置密码和紧急接管设置新密码时统一按策略校验，并维护密码历史
+type PasswordPolicyService struct {
+	db            *gorm.DB
+	configManager *config.ConfigManager
+	breachChecker breach.Checker // 泄露密码检查器，未配置数据集时为nil
+}
+
+// NewPasswordPolicyService 创建登录密码策略服务实例
+func NewPasswordPolicyService(db *gorm.DB, configManager *config.ConfigManager, breachChecker breach.Checker) *PasswordPolicyService {
+	return &PasswordPolicyService{
+		db:            db,
+		configManager: configManager,
+		breachChecker: breachChecker,
+	}
+}
+
+// intConfig 读取非负整数配置，配置无效时使用默认值
+func (s *PasswordPolicyService) intConfig(key, defaultValue string) int {
+	value := s.configManager.GetWithDefault(key, defaultValue)
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		logger.Warn("密码策略配置无效，使用默认值", logger.String("key", key), logger.String("value", value))
+		n, _ = strconv.Atoi(defaultValue)
+	}
+	return n
+}
+
+// Policy 读取当前的密码策略
+func (s *PasswordPolicyService) Policy() *PasswordPolicy {
+	policy := &PasswordPolicy{
+		MinLength:      s.intConfig(models.ConfigKeyPasswordMinLength, models.ConfigValuePasswordMinLengthDefault),
+		MinCharClasses: s.intConfig(models.ConfigKeyPasswordMinCharClasses, models.ConfigValuePasswordMinCharClassesDefault),
+		DisallowUser:   s.configManager.GetWithDefault(models.ConfigKeyPasswordDisallowUser, models.ConfigValuePasswordDisallowUserDefault) == models.ConfigValueTrue,
+		HistoryCount:   s.intConfig(models.ConfigKeyPasswordHistoryCount, models.ConfigValuePasswordHistoryCountDefault),
+		MaxAgeDays:     s.intConfig(models.ConfigKeyPasswordMaxAgeDays, models.ConfigValuePasswordMaxAgeDaysDefault),
+	}
+	// 最小长度不能低于系统下限，字符类别最多4种
+	if policy.MinLength < crypto.MinPasswordLength {
+		policy.MinLength = crypto.MinPasswordLength
+	}
+	if policy.MinCharClasses > 4 {
+		policy.MinCharClasses = 4
+	}
+	for _, word := range strings.Split(s.configManager.GetWithDefault(models.ConfigKeyPasswordBannedWords, ""), ",") {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			policy.BannedWords = append(policy.BannedWords, word)
+		}
+	}
+	return policy
+}
+
+// Validate 按密码策略校验新密码（不含密码历史），username和email为密码所属用户的信息
+func (s *PasswordPolicyService) Validate(password, username, email string) error {
+	policy := s.Policy()
+
+	if len(password) > crypto.MaxPasswordBytes {
+		return errors.New(errors.CodeWeakPassword, fmt.Sprintf("密码不能超过%d个字节", crypto.MaxPasswordBytes))
+	}
+	if utf8.RuneCountInString(password) < policy.MinLength {
+		return errors.New(errors.CodeWeakPassword, fmt.Sprintf("密码长度不能少于%d个字符", policy.MinLength))
+	}
+	if crypto.PasswordCharClasses(password) < policy.MinCharClasses {
+		return errors.New(errors.CodeWeakPassword,
+			fmt.Sprintf("密码需要包含大写字母、小写字母、数字、特殊字符中的至少%d种", policy.MinCharClasses))
+	}
+
+	lower := strings.ToLower(password)
+	for _, word := range policy.BannedWords {
+		if strings.Contains(lower, word) {
+			return errors.New(errors.CodeWeakPassword, "密码包含禁止使用的词")
+		}
+	}
+	if policy.DisallowUser && containsUserInfo(lower, username, email) {
+		return errors.New(errors.CodeWeakPassword, "密码不能包含用户名或邮箱")
+	}
+
+	if isBreachedPassword(s.breachChecker, password) {
+		return errors.New(errors.CodeWeakPassword, "该密码已出现在公开泄露的密码库中，请更换其他密码")
+	}
+	return nil
+}
+
+// ValidateForUser 为已有用户校验新密码：密码策略和密码历史
+func (s *PasswordPolicyService) ValidateForUser(user *models.User, password string) error {
+	var profile models.UserProfile
+	if err := s.db.Where("user_id = ?", user.ID).First(&profile).Error; err != nil && err != gorm.ErrRecordNotFound {
+		logger.Error("查询用户档案失败", logger.String("uuid", user.UUID), logger.Err(err))
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if err := s.Validate(password, user.Username, profile.Email); err != nil {
+		return err
+	}
+	return s.checkHistory(user, password)
+}
+
+// checkHistory 检查新密码是否为当前密码或最近用过的密码
+func (s *PasswordPolicyService) checkHistory(user *models.User, password string) error {
+	count := s.Policy().HistoryCount
+	if count == 0 {
+		return nil
+	}
+	reused := errors.New(errors.CodeWeakPassword, fmt.Sprintf("不能使用最近%d次用过的密码", count))
+	if crypto.VerifyPassword(password, user.PasswordHash) {
+		return reused
+	}
+	if count == 1 {
+		return nil
+	}
+
+	var history []models.PasswordHistory
+	if err := s.db.Where("user_uuid = ?", user.UUID).Order("id DESC").Limit(count - 1).Find(&history).Error; err != nil {
+		logger.Error("查询密码历史失败", logger.String("uuid", user.UUID), logger.Err(err))
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	for i := range history {
+		if crypto.VerifyPassword(password, history[i].PasswordHash) {
+			return reused
+		}
+	}
+	return nil
+}
+
+// Expired 判断用户的登录密码是否超过最长使用期限
+func (s *PasswordPolicyService) Expired(user *models.User) bool {
+	days := s.Policy().MaxAgeDays
+	return days > 0 && user.PasswordAge() > time.Duration(days)*24*time.Hour
+}
+
+// setPassword 在事务中更新登录密码：旧密码哈希写入密码历史并清理超出保留数量的记录
+// 调用方负责事先调用 ValidateForUser 校验新密码
+func (s *PasswordPolicyService) setPassword(tx *gorm.DB, user *models.User, newPasswordHash string) error {
+	keep := s.Policy().HistoryCount - 1 // 当前密码保存在users表，历史表只保留之前的
+	if keep > 0 {
+		if err := tx.Create(&models.PasswordHistory{UserUUID: user.UUID, PasswordHash: user.PasswordHash}).Error; err != nil {
+			return err
+		}
+	}
+
+	// 清理超出保留数量的旧记录（MySQL不支持不带LIMIT的OFFSET，查出全部ID后再截取）
+	var ids []uint
+	if err := tx.Model(&models.PasswordHistory{}).Where("user_uuid = ?", user.UUID).
+		Order("id DESC").Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if keep = max(keep, 0); len(ids) > keep {
+		if err := tx.Unscoped().Where("id IN ?", ids[keep:]).Delete(&models.PasswordHistory{}).Error; err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	if err := tx.Model(user).Updates(map[string]interface{}{
+		"password_hash":       newPasswordHash,
+		"password_changed_at": now,
+	}).Error; err != nil {
+		return err
+	}
+	user.PasswordHash = newPasswordHash
+	user.PasswordChangedAt = &now
+	return nil
+}
+
+// containsUserInfo 判断密码（已转为小写）是否包含用户名或邮箱前缀
+func containsUserInfo(lowerPassword, username, email string) bool {
+	candidates := []string{strings.ToLower(username)}
+	if local, _, ok := strings.Cut(email, "@"); ok {
+		candidates = append(candidates, strings.ToLower(local))
+	}
+	for _, value := range candidates {
+		if utf8.RuneCountInString(value) >= minUserInfoLength && strings.Contains(lowerPassword, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBreachedPassword 判断密码是否出现在泄露密码数据集中
+// 未配置数据集时返回false；读取数据集失败时记录日志并放行，不因数据文件问题阻断业务
+func isBreachedPassword(checker breach.Checker, password string) bool {
+	if checker == nil {
+		return false
+	}
+	breached, err := checker.Contains(password)
+	if err != nil {
+		logger.Error("检查泄露密码失败", logger.Err(err))
+		return false
+	}
+	return breached
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/cuihe500/vaulthub/internal/config"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 个人访问令牌相关常量
+const (
+	PersonalAccessTokenPrefix   = "vhp_" // 令牌前缀，认证时据此区分个人访问令牌和JWT
+	personalAccessTokenSize     = 32
+	personalAccessTokenShownLen = 12          // 列表中显示的令牌前缀长度（含 vhp_）
+	personalAccessTokenSeen     = time.Minute // 最后使用时间的更新间隔，避免每个请求都写数据库
+	personalAccessTokenSecrets  = 100         // 单个令牌最多限定的秘密数量
+)
+
+// personalAccessTokenScopes 个人访问令牌可以申请的权限范围，与路由中 AuthWithPermission/SecureAuthWithPermission 使用的Casbin资源和操作一致
+// 不包含 scope:global 和 casbin:reload 等管理类权限
+var personalAccessTokenScopes = map[string]bool{
+	"secret:read":   true,
+	"secret:write":  true,
+	"key:read":      true,
+	"key:write":     true,
+	"vault:read":    true,
+	"vault:write":   true,
+	"checkout:read": true,
+	"user:read":     true,
+	"profile:read":  true,
+	"config:read":   true,
+}
+
+// IsPersonalAccessToken 判断Bearer令牌是否为个人访问令牌
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
+
+// hashPersonalAccessToken 计算个人访问令牌的SHA-256（令牌本身是高熵随机值，无需加盐）
+func hashPersonalAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// PersonalAccessTokenService 个人访问令牌服务
+type PersonalAccessTokenService struct {
+	db            *gorm.DB
+	enforcer      *casbin.Enforcer
+	configManager *config.ConfigManager
+}
+
+// NewPersonalAccessTokenService 创建个人访问令牌服务实例
+func NewPersonalAccessTokenService(db *gorm.DB, enforcer *casbin.Enforcer, configManager *config.ConfigManager) *PersonalAccessTokenService {
+	return &PersonalAccessTokenService{
+		db:            db,
+		enforcer:      enforcer,
+		configManager: configManager,
+	}
+}
+
+// intConfig 读取非负整数配置，配置无效时使用默认值
+func (s *PersonalAccessTokenService) intConfig(key, defaultValue string) int {
+	value := s.configManager.GetWithDefault(key, defaultValue)
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		logger.Warn("个人访问令牌配置无效，使用默认值", logger.String("key", key), logger.String("value", value))
+		n, _ = strconv.Atoi(defaultValue)
+	}
+	return n
+}
+
+// CreatePersonalAccessTokenRequest 创建个人访问令牌请求
+type CreatePersonalAccessTokenRequest struct {
+	UserUUID      string   `json:"-"` // 不从请求体解析，由handler从认证上下文设置
+	Role          string   `json:"-"` // 不从请求体解析，由handler从认证上下文设置
+	Name          string   `json:"name" binding:"required,max=64"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,required"`               // 权限范围，如 ["secret:read"]
+	SecretUUIDs   []string `json:"secret_uuids" binding:"omitempty,max=100,dive,uuid"`          // 只允许访问的秘密，为空时可以访问全部秘密
+	ExpiresInDays int      `json:"expires_in_days" binding:"required,min=1"`                    // 有效期（天），不能超过系统配置的上限
+	AllowedIPs    []string `json:"allowed_ips" binding:"omitempty,max=20,dive,required,max=64"` // 允许使用的IP或CIDR，为空时不限制
+}
+
+// CreatePersonalAccessTokenResponse 创建个人访问令牌响应
+type CreatePersonalAccessTokenResponse struct {
+	Token       string                      `json:"token"` // 令牌明文，只返回这一次
+	AccessToken *models.PersonalAccessToken `json:"access_token"`
+}
+
+// Create 创建个人访问令牌
+// 权限范围只能是创建者角色当前拥有的权限的子集
+func (s *PersonalAccessTokenService) Create(req *CreatePersonalAccessTokenRequest) (*CreatePersonalAccessTokenResponse, error) {
+	scopes, err := s.normalizeScopes(req.Role, req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	allowedIPs, err := normalizeAllowedIPs(req.AllowedIPs)
+	if err != nil {
+		return nil, err
+	}
+	secretUUIDs, err := s.normalizeSecretUUIDs(req.UserUUID, scopes, req.SecretUUIDs)
+	if err != nil {
+		return nil, err
+	}
+	maxDays := s.intConfig(models.ConfigKeyPersonalAccessTokenMaxDays, models.ConfigValuePersonalAccessTokenMaxDaysDefault)
+	if maxDays > 0 && req.ExpiresInDays > maxDays {
+		return nil, errors.New(errors.CodeInvalidParam, fmt.Sprintf("有效期不能超过%d天", maxDays))
+	}
+
+	var user models.User
+	if err := s.db.Where("uuid = ?", req.UserUUID).First(&user).Error; err != nil {
+		logger.Error("查询用户失败", logger.String("uuid", req.UserUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	if limit := s.intConfig(models.ConfigKeyPersonalAccessTokenMaxPerUser, models.ConfigValuePersonalAccessTokenMaxPerUserDefault); limit > 0 {
+		var count int64
+		if err := s.db.Model(&models.PersonalAccessToken{}).
+			Where("user_uuid = ? AND expires_at > ?", user.UUID, time.Now()).Count(&count).Error; err != nil {
+			logger.Error("统计个人访问令牌失败", logger.String("uuid", user.UUID), logger.Err(err))
+			return nil, errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		if count >= int64(limit) {
+			return nil, errors.New(errors.CodeResourceConflict, fmt.Sprintf("最多只能创建%d个个人访问令牌，请先撤销不再使用的令牌", limit))
+		}
+	}
+
+	tokenBytes, err := crypto.GenerateRandomBytes(personalAccessTokenSize)
+	if err != nil {
+		logger.Error("生成个人访问令牌失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCryptoError, err)
+	}
+	token := PersonalAccessTokenPrefix + hex.EncodeToString(tokenBytes)
+
+	accessToken := &models.PersonalAccessToken{
+		UUID:        uuid.New().String(),
+		UserUUID:    user.UUID,
+		Name:        req.Name,
+		TokenHash:   hashPersonalAccessToken(token),
+		TokenPrefix: token[:personalAccessTokenShownLen],
+		Scopes:      scopes,
+		SecretUUIDs: secretUUIDs,
+		AllowedIPs:  allowedIPs,
+		TokenEpoch:  user.TokenEpoch,
+		ExpiresAt:   time.Now().Add(time.Duration(req.ExpiresInDays) * 24 * time.Hour),
+	}
+	if err := s.db.Create(accessToken).Error; err != nil {
+		logger.Error("保存个人访问令牌失败", logger.String("uuid", user.UUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	logger.Info("个人访问令牌已创建",
+		logger.String("uuid", user.UUID),
+		logger.String("token_uuid", accessToken.UUID),
+		logger.String("scopes", strings.Join(scopes, ",")),
+		logger.Int("secret_count", len(secretUUIDs)))
+	return &CreatePersonalAccessTokenResponse{Token: token, AccessToken: accessToken}, nil
+}
+
+// normalizeScopes 校验权限范围并去重：必须是可申请的范围，且创建者的角色拥有该权限
+func (s *PersonalAccessTokenService) normalizeScopes(role string, requested []string) (models.StringList, error) {
+	seen := make(map[string]bool, len(requested))
+	scopes := make(models.StringList, 0, len(requested))
+	for _, scope := range requested {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if seen[scope] {
+			continue
+		}
+		if !personalAccessTokenScopes[scope] {
+			return nil, errors.New(errors.CodeInvalidParam, fmt.Sprintf("不支持的权限范围: %s", scope))
+		}
+		resource, action, _ := strings.Cut(scope, ":")
+		allowed, err := s.enforcer.Enforce(role, resource, action)
+		if err != nil {
+			logger.Error("权限检查失败", logger.String("role", role), logger.String("scope", scope), logger.Err(err))
+			return nil, errors.New(errors.CodeInternalError, "权限检查失败")
+		}
+		if !allowed {
+			return nil, errors.New(errors.CodeInsufficientPermission, fmt.Sprintf("当前角色没有 %s 权限，不能授予令牌", scope))
+		}
+		seen[scope] = true
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
+// normalizeSecretUUIDs 校验令牌限定的秘密并去重：必须申请了秘密权限，且每个秘密都属于创建者
+func (s *PersonalAccessTokenService) normalizeSecretUUIDs(userUUID string, scopes models.StringList, requested []string) (models.StringList, error) {
+	if len(requested) == 0 {
+		return nil, nil
+	}
+	hasSecretScope := false
+	for _, scope := range scopes {
+		if strings.HasPrefix(scope, "secret:") {
+			hasSecretScope = true
+			break
+		}
+	}
+	if !hasSecretScope {
+		return nil, errors.New(errors.CodeInvalidParam, "限定秘密范围时必须申请 secret:read 或 secret:write 权限")
+	}
+
+	seen := make(map[string]bool, len(requested))
+	secretUUIDs := make(models.StringList, 0, len(requested))
+	for _, secretUUID := range requested {
+		secretUUID = strings.ToLower(strings.TrimSpace(secretUUID))
+		if seen[secretUUID] {
+			continue
+		}
+		seen[secretUUID] = true
+		secretUUIDs = append(secretUUIDs, secretUUID)
+	}
+	if len(secretUUIDs) > personalAccessTokenSecrets {
+		return nil, errors.New(errors.CodeInvalidParam, fmt.Sprintf("最多只能限定%d个秘密", personalAccessTokenSecrets))
+	}
+
+	var count int64
+	if err := s.db.Model(&models.EncryptedSecret{}).
+		Where("user_uuid = ? AND secret_uuid IN ?", userUUID, []string(secretUUIDs)).
+		Count(&count).Error; err != nil {
+		logger.Error("查询秘密失败", logger.String("uuid", userUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if count != int64(len(secretUUIDs)) {
+		return nil, errors.New(errors.CodeResourceNotFound, "限定的秘密不存在或无权访问")
+	}
+	return secretUUIDs, nil
+}
+
+// normalizeAllowedIPs 校验IP白名单，单个IP和CIDR均可
+func normalizeAllowedIPs(values []string) (models.StringList, error) {
+	var allowed models.StringList
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if strings.Contains(value, "/") {
+			_, ipNet, err := net.ParseCIDR(value)
+			if err != nil {
+				return nil, errors.New(errors.CodeInvalidParam, fmt.Sprintf("无效的CIDR: %s", value))
+			}
+			allowed = append(allowed, ipNet.String())
+			continue
+		}
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, errors.New(errors.CodeInvalidParam, fmt.Sprintf("无效的IP地址: %s", value))
+		}
+		allowed = append(allowed, ip.String())
+	}
+	return allowed, nil
+}
+
+// ipAllowed 判断客户端IP是否在令牌的IP白名单中，白名单为空时不限制
+func ipAllowed(allowedIPs []string, clientIP string) bool {
+	if len(allowedIPs) == 0 {
+		return true
+	}
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return false
+	}
+	for _, value := range allowedIPs {
+		if _, ipNet, err := net.ParseCIDR(value); err == nil {
+			if ipNet.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if allowed := net.ParseIP(value); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// PersonalAccessTokenListResponse 个人访问令牌列表
+type PersonalAccessTokenListResponse struct {
+	Tokens []models.PersonalAccessToken `json:"tokens"`
+}
+
+// List 查询用户的个人访问令牌（包括已过期的），按创建时间倒序
+func (s *PersonalAccessTokenService) List(userUUID string) (*PersonalAccessTokenListResponse, error) {
+	var tokens []models.PersonalAccessToken
+	if err := s.db.Where("user_uuid = ?", userUUID).Order("id DESC").Find(&tokens).Error; err != nil {
+		logger.Error("查询个人访问令牌失败", logger.String("uuid", userUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return &PersonalAccessTokenListResponse{Tokens: tokens}, nil
+}
+
+// Revoke 撤销用户的一个个人访问令牌，返回被撤销的令牌
+func (s *PersonalAccessTokenService) Revoke(userUUID, tokenUUID string) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	if err := s.db.Where("uuid = ? AND user_uuid = ?", tokenUUID, userUUID).First(&token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeResourceNotFound, "令牌不存在")
+		}
+		logger.Error("查询个人访问令牌失败", logger.String("token_uuid", tokenUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	if err := s.db.Delete(&token).Error; err != nil {
+		logger.Error("撤销个人访问令牌失败", logger.String("token_uuid", tokenUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	logger.Info("个人访问令牌已撤销", logger.String("uuid", userUUID), logger.String("token_uuid", tokenUUID))
+	return &token, nil
+}
+
+// AuthenticatePersonalAccessToken 验证个人访问令牌并返回令牌和所属用户，供认证中间件和gRPC拦截器使用
+// 检查令牌未撤销、未过期、客户端IP在白名单内、用户的令牌版本未变化，并更新最后使用时间和IP；
+// 用户状态由调用方检查
+func AuthenticatePersonalAccessToken(db *gorm.DB, token, clientIP string) (*models.PersonalAccessToken, *models.User, *errors.AppError) {
+	invalid := errors.New(errors.CodeInvalidToken, "个人访问令牌无效或已过期")
+
+	var accessToken models.PersonalAccessToken
+	if err := db.Where("token_hash = ?", hashPersonalAccessToken(token)).First(&accessToken).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Warn("个人访问令牌不存在或已撤销", logger.String("ip", clientIP))
+			return nil, nil, invalid
+		}
+		logger.Error("查询个人访问令牌失败", logger.Err(err))
+		return nil, nil, errors.New(errors.CodeInternalError, "查询令牌失败")
+	}
+	if accessToken.IsExpired() {
+		return nil, nil, invalid
+	}
+	if !ipAllowed(accessToken.AllowedIPs, clientIP) {
+		logger.Warn("个人访问令牌的使用IP不在白名单中",
+			logger.String("token_uuid", accessToken.UUID),
+			logger.String("ip", clientIP))
+		return nil, nil, errors.New(errors.CodeForbidden, "当前IP不允许使用该令牌")
+	}
+
+	var user models.User
+	if err := db.Where("uuid = ?", accessToken.UserUUID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, invalid
+		}
+		logger.Error("查询用户失败", logger.String("uuid", accessToken.UserUUID), logger.Err(err))
+		return nil, nil, errors.New(errors.CodeInternalError, "查询用户失败")
+	}
+	// 与登录令牌一致：用户修改角色、状态或重置密码后令牌失效
+	if accessToken.TokenEpoch != user.TokenEpoch {
+		return nil, nil, invalid
+	}
+
+	now := time.Now()
+	if err := db.Model(&models.PersonalAccessToken{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ? OR last_used_ip <> ?)", accessToken.ID, now.Add(-personalAccessTokenSeen), clientIP).
+		UpdateColumns(map[string]interface{}{"last_used_at": now, "last_used_ip": clientIP}).Error; err != nil {
+		logger.Warn("更新个人访问令牌使用时间失败", logger.String("token_uuid", accessToken.UUID), logger.Err(err))
+	}
+	return &accessToken, &user, nil
+}
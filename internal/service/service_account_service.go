@@ -0,0 +1,814 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/cuihe500/vaulthub/internal/config"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	redisClient "github.com/cuihe500/vaulthub/pkg/redis"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 服务账户相关常量
+const (
+	ServiceAccountTokenPrefix = "vhs_"    // 服务账户令牌前缀，认证时据此区分服务账户令牌和用户令牌
+	ServiceAccountRole        = "machine" // 服务账户在Casbin中的默认角色
+
+	serviceAccountTokenPrefix = "service_account_token:" // Redis中令牌的key前缀
+	serviceAccountTokenSize   = 32
+	serviceAccountSecretIDLen = 32
+)
+
+// IsServiceAccountToken 判断Bearer令牌是否为服务账户令牌
+func IsServiceAccountToken(token string) bool {
+	return strings.HasPrefix(token, ServiceAccountTokenPrefix)
+}
+
+// makeServiceAccountTokenKey 生成服务账户令牌的Redis key（只保存令牌的SHA-256）
+func makeServiceAccountTokenKey(token string) string {
+	return serviceAccountTokenPrefix + hashPersonalAccessToken(token)
+}
+
+// ServiceAccountService 服务账户服务
+// 服务账户属于创建者的保险库，以 role_id + secret_id 登录（AppRole），只能访问明确授权的秘密
+type ServiceAccountService struct {
+	db                *gorm.DB
+	redis             *redisClient.Client
+	enforcer          *casbin.Enforcer
+	configManager     *config.ConfigManager
+	encryptionService *EncryptionService
+	serverKey         []byte // 加密服务账户私钥的服务端密钥
+}
+
+// NewServiceAccountService 创建服务账户服务实例
+func NewServiceAccountService(db *gorm.DB, redis *redisClient.Client, enforcer *casbin.Enforcer, configManager *config.ConfigManager, encryptionService *EncryptionService, serverKey []byte) *ServiceAccountService {
+	return &ServiceAccountService{
+		db:                db,
+		redis:             redis,
+		enforcer:          enforcer,
+		configManager:     configManager,
+		encryptionService: encryptionService,
+		serverKey:         serverKey,
+	}
+}
+
+// intConfig 读取非负整数配置，配置无效时使用默认值
+func (s *ServiceAccountService) intConfig(key, defaultValue string) int {
+	value := s.configManager.GetWithDefault(key, defaultValue)
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		logger.Warn("服务账户配置无效，使用默认值", logger.String("key", key), logger.String("value", value))
+		n, _ = strconv.Atoi(defaultValue)
+	}
+	return n
+}
+
+// ServiceAccountInfo 服务账户及其可以访问的秘密
+type ServiceAccountInfo struct {
+	*models.ServiceAccount
+	SecretUUIDs []string `json:"secret_uuids"`
+}
+
+// CreateServiceAccountRequest 创建服务账户请求
+type CreateServiceAccountRequest struct {
+	OwnerUUID   string `json:"-"` // 不从请求体解析，由handler从认证上下文设置
+	Name        string `json:"name" binding:"required,max=48"`
+	Description string `json:"description" binding:"omitempty,max=255"`
+}
+
+// Create 创建服务账户
+// 生成服务账户的密钥对，私钥由服务端密钥加密保存；并在Casbin中归入machine角色
+// 服务账户不持有保险库DEK，只能解密授权时为它封装的秘密
+func (s *ServiceAccountService) Create(req *CreateServiceAccountRequest) (*ServiceAccountInfo, error) {
+	if limit := s.intConfig(models.ConfigKeyServiceAccountMaxPerUser, models.ConfigValueServiceAccountMaxPerUserDefault); limit > 0 {
+		var count int64
+		if err := s.db.Model(&models.ServiceAccount{}).Where("owner_uuid = ?", req.OwnerUUID).Count(&count).Error; err != nil {
+			logger.Error("统计服务账户失败", logger.String("uuid", req.OwnerUUID), logger.Err(err))
+			return nil, errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		if count >= int64(limit) {
+			return nil, errors.New(errors.CodeResourceConflict, fmt.Sprintf("最多只能创建%d个服务账户", limit))
+		}
+	}
+
+	publicKey, privateKey, err := crypto.GenerateX25519KeyPair()
+	if err != nil {
+		logger.Error("生成服务账户密钥对失败", logger.Err(err))
+		return nil, err
+	}
+	defer crypto.ClearBytes(privateKey)
+
+	encryptedPrivateKey, err := encryptKeyBlob(privateKey, s.serverKey)
+	if err != nil {
+		logger.Error("加密服务账户私钥失败", logger.Err(err))
+		return nil, err
+	}
+
+	account := &models.ServiceAccount{
+		UUID:                uuid.New().String(),
+		OwnerUUID:           req.OwnerUUID,
+		Name:                req.Name,
+		Description:         req.Description,
+		RoleID:              uuid.New().String(),
+		PublicKey:           publicKey,
+		EncryptedPrivateKey: encryptedPrivateKey,
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(account).Error; err != nil {
+			return errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		// Casbin策略最后写入，失败时回滚服务账户
+		if _, err := s.enforcer.AddGroupingPolicy(account.CasbinSubject(), ServiceAccountRole); err != nil {
+			return errors.WithMessage(errors.CodeInternalError, "保存服务账户权限失败", err)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("创建服务账户失败", logger.String("uuid", req.OwnerUUID), logger.Err(err))
+		return nil, err
+	}
+
+	logger.Info("服务账户已创建",
+		logger.String("uuid", req.OwnerUUID),
+		logger.String("service_account_uuid", account.UUID))
+	return &ServiceAccountInfo{ServiceAccount: account, SecretUUIDs: []string{}}, nil
+}
+
+// ServiceAccountListResponse 服务账户列表
+type ServiceAccountListResponse struct {
+	ServiceAccounts []*ServiceAccountInfo `json:"service_accounts"`
+}
+
+// List 查询用户的服务账户，按创建时间倒序
+func (s *ServiceAccountService) List(ownerUUID string) (*ServiceAccountListResponse, error) {
+	var accounts []*models.ServiceAccount
+	if err := s.db.Where("owner_uuid = ?", ownerUUID).Order("id DESC").Find(&accounts).Error; err != nil {
+		logger.Error("查询服务账户失败", logger.String("uuid", ownerUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	infos := make([]*ServiceAccountInfo, len(accounts))
+	accountUUIDs := make([]string, len(accounts))
+	byUUID := make(map[string]*ServiceAccountInfo, len(accounts))
+	for i, account := range accounts {
+		infos[i] = &ServiceAccountInfo{ServiceAccount: account, SecretUUIDs: []string{}}
+		accountUUIDs[i] = account.UUID
+		byUUID[account.UUID] = infos[i]
+	}
+	if len(accounts) == 0 {
+		return &ServiceAccountListResponse{ServiceAccounts: infos}, nil
+	}
+
+	var grants []models.ServiceAccountGrant
+	if err := s.db.Where("service_account_uuid IN ?", accountUUIDs).Order("id").Find(&grants).Error; err != nil {
+		logger.Error("查询服务账户授权失败", logger.String("uuid", ownerUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	for _, grant := range grants {
+		info := byUUID[grant.ServiceAccountUUID]
+		info.SecretUUIDs = append(info.SecretUUIDs, grant.SecretUUID)
+	}
+	return &ServiceAccountListResponse{ServiceAccounts: infos}, nil
+}
+
+// Get 查询用户的一个服务账户
+func (s *ServiceAccountService) Get(ownerUUID, accountUUID string) (*ServiceAccountInfo, error) {
+	account, err := s.getOwned(ownerUUID, accountUUID)
+	if err != nil {
+		return nil, err
+	}
+	secretUUIDs, err := s.grantedSecretUUIDs(account.UUID)
+	if err != nil {
+		return nil, err
+	}
+	return &ServiceAccountInfo{ServiceAccount: account, SecretUUIDs: secretUUIDs}, nil
+}
+
+// Delete 删除服务账户：同时删除secret_id、秘密授权和Casbin策略，已签发的令牌随之失效
+func (s *ServiceAccountService) Delete(ownerUUID, accountUUID string) (*models.ServiceAccount, error) {
+	account, err := s.getOwned(ownerUUID, accountUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("service_account_uuid = ?", account.UUID).Delete(&models.ServiceAccountSecretID{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("service_account_uuid = ?", account.UUID).Delete(&models.ServiceAccountGrant{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(account).Error
+	})
+	if err != nil {
+		logger.Error("删除服务账户失败", logger.String("service_account_uuid", account.UUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	// 服务账户已删除，认证时查不到即拒绝；Casbin策略删除失败只记录日志
+	subject := account.CasbinSubject()
+	if _, err := s.enforcer.RemoveFilteredGroupingPolicy(0, subject); err != nil {
+		logger.Warn("删除服务账户角色失败", logger.String("subject", subject), logger.Err(err))
+	}
+	if _, err := s.enforcer.RemoveFilteredPolicy(0, subject); err != nil {
+		logger.Warn("删除服务账户策略失败", logger.String("subject", subject), logger.Err(err))
+	}
+
+	logger.Info("服务账户已删除", logger.String("uuid", ownerUUID), logger.String("service_account_uuid", account.UUID))
+	return account, nil
+}
+
+// CreateSecretIDRequest 生成secret_id请求
+type CreateSecretIDRequest struct {
+	OwnerUUID          string   `json:"-"`                                                           // 不从请求体解析，由handler从认证上下文设置
+	ServiceAccountUUID string   `json:"-"`                                                           // 不从请求体解析，由handler从URL路径设置
+	BoundCIDRs         []string `json:"bound_cidrs" binding:"omitempty,max=20,dive,required,max=64"` // 允许登录和使用令牌的IP或CIDR，为空时不限制
+	MaxUses            int      `json:"max_uses" binding:"omitempty,min=0"`                          // 最多登录次数，0表示不限制
+	ExpiresInHours     int      `json:"expires_in_hours" binding:"required,min=1"`                   // 有效期（小时），不能超过系统配置的上限
+}
+
+// CreateSecretIDResponse 生成secret_id响应
+type CreateSecretIDResponse struct {
+	SecretID     string                         `json:"secret_id"` // secret_id明文，只返回这一次
+	SecretIDInfo *models.ServiceAccountSecretID `json:"secret_id_info"`
+}
+
+// CreateSecretID 为服务账户生成新的secret_id
+// 轮换时先生成新的secret_id，应用切换后再撤销旧的
+func (s *ServiceAccountService) CreateSecretID(req *CreateSecretIDRequest) (*CreateSecretIDResponse, error) {
+	account, err := s.getOwned(req.OwnerUUID, req.ServiceAccountUUID)
+	if err != nil {
+		return nil, err
+	}
+	boundCIDRs, err := normalizeAllowedIPs(req.BoundCIDRs)
+	if err != nil {
+		return nil, err
+	}
+	maxDays := s.intConfig(models.ConfigKeyServiceAccountSecretIDMaxDays, models.ConfigValueServiceAccountSecretIDMaxDaysDefault)
+	if maxDays > 0 && req.ExpiresInHours > maxDays*24 {
+		return nil, errors.New(errors.CodeInvalidParam, fmt.Sprintf("有效期不能超过%d天", maxDays))
+	}
+
+	secretBytes, err := crypto.GenerateRandomBytes(serviceAccountSecretIDLen)
+	if err != nil {
+		logger.Error("生成secret_id失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCryptoError, err)
+	}
+	secretID := hex.EncodeToString(secretBytes)
+
+	info := &models.ServiceAccountSecretID{
+		UUID:               uuid.New().String(),
+		ServiceAccountUUID: account.UUID,
+		SecretIDHash:       hashPersonalAccessToken(secretID),
+		BoundCIDRs:         boundCIDRs,
+		MaxUses:            req.MaxUses,
+		ExpiresAt:          time.Now().Add(time.Duration(req.ExpiresInHours) * time.Hour),
+	}
+	if err := s.db.Create(info).Error; err != nil {
+		logger.Error("保存secret_id失败", logger.String("service_account_uuid", account.UUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	logger.Info("服务账户secret_id已生成",
+		logger.String("service_account_uuid", account.UUID),
+		logger.String("secret_id_uuid", info.UUID))
+	return &CreateSecretIDResponse{SecretID: secretID, SecretIDInfo: info}, nil
+}
+
+// SecretIDListResponse secret_id列表
+type SecretIDListResponse struct {
+	SecretIDs []models.ServiceAccountSecretID `json:"secret_ids"`
+}
+
+// ListSecretIDs 查询服务账户的secret_id（包括已过期和次数已用完的），按创建时间倒序
+func (s *ServiceAccountService) ListSecretIDs(ownerUUID, accountUUID string) (*SecretIDListResponse, error) {
+	account, err := s.getOwned(ownerUUID, accountUUID)
+	if err != nil {
+		return nil, err
+	}
+	var secretIDs []models.ServiceAccountSecretID
+	if err := s.db.Where("service_account_uuid = ?", account.UUID).Order("id DESC").Find(&secretIDs).Error; err != nil {
+		logger.Error("查询secret_id失败", logger.String("service_account_uuid", account.UUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return &SecretIDListResponse{SecretIDs: secretIDs}, nil
+}
+
+// RevokeSecretID 撤销服务账户的一个secret_id，用它登录获得的令牌随之失效
+func (s *ServiceAccountService) RevokeSecretID(ownerUUID, accountUUID, secretIDUUID string) error {
+	account, err := s.getOwned(ownerUUID, accountUUID)
+	if err != nil {
+		return err
+	}
+	result := s.db.Where("uuid = ? AND service_account_uuid = ?", secretIDUUID, account.UUID).Delete(&models.ServiceAccountSecretID{})
+	if result.Error != nil {
+		logger.Error("撤销secret_id失败", logger.String("secret_id_uuid", secretIDUUID), logger.Err(result.Error))
+		return errors.Wrap(errors.CodeDatabaseError, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New(errors.CodeResourceNotFound, "secret_id不存在")
+	}
+	logger.Info("服务账户secret_id已撤销",
+		logger.String("service_account_uuid", account.UUID),
+		logger.String("secret_id_uuid", secretIDUUID))
+	return nil
+}
+
+// SetGrantsRequest 设置服务账户可以访问的秘密
+type SetGrantsRequest struct {
+	OwnerUUID          string   `json:"-"`                                                    // 不从请求体解析，由handler从认证上下文设置
+	ServiceAccountUUID string   `json:"-"`                                                    // 不从请求体解析，由handler从URL路径设置
+	SecretUUIDs        []string `json:"secret_uuids" binding:"max=1000,dive,required,max=36"` // 为空时撤销全部授权
+	SecurityPIN        string   `json:"security_pin"`                                         // 新增授权时需要，用于解密秘密并为服务账户封装；只撤销授权时不需要
+}
+
+// SetGrants 用给定的列表替换服务账户可以访问的秘密
+// 秘密必须属于服务账户所在的保险库；需要检出或审批的秘密不能授权给服务账户。
+// 新增的授权用创建者的DEK解密秘密，再用服务账户的公钥封装明文；保留的授权沿用原来的封装
+func (s *ServiceAccountService) SetGrants(req *SetGrantsRequest) (*ServiceAccountInfo, error) {
+	account, err := s.getOwned(req.OwnerUUID, req.ServiceAccountUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	secretUUIDs := make([]string, 0, len(req.SecretUUIDs))
+	seen := make(map[string]bool, len(req.SecretUUIDs))
+	for _, secretUUID := range req.SecretUUIDs {
+		if !seen[secretUUID] {
+			seen[secretUUID] = true
+			secretUUIDs = append(secretUUIDs, secretUUID)
+		}
+	}
+
+	current, err := s.grantedSecretUUIDs(account.UUID)
+	if err != nil {
+		return nil, err
+	}
+	granted := make(map[string]bool, len(current))
+	for _, secretUUID := range current {
+		granted[secretUUID] = true
+	}
+
+	var added []*models.EncryptedSecret
+	if len(secretUUIDs) > 0 {
+		var secrets []*models.EncryptedSecret
+		if err := s.db.Where("user_uuid = ? AND secret_uuid IN ?", account.OwnerUUID, secretUUIDs).Find(&secrets).Error; err != nil {
+			logger.Error("查询秘密失败", logger.Err(err))
+			return nil, errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		found := make(map[string]bool, len(secrets))
+		for _, secret := range secrets {
+			if secret.CheckoutRequired || secret.ApprovalRequired {
+				return nil, errors.New(errors.CodeOperationNotAllowed,
+					fmt.Sprintf("秘密 %s 需要检出或审批，不能授权给服务账户", secret.SecretName))
+			}
+			found[secret.SecretUUID] = true
+			if !granted[secret.SecretUUID] {
+				added = append(added, secret)
+			}
+		}
+		for _, secretUUID := range secretUUIDs {
+			if !found[secretUUID] {
+				return nil, errors.New(errors.CodeResourceNotFound, fmt.Sprintf("秘密不存在: %s", secretUUID))
+			}
+		}
+	}
+
+	sealed := map[string][]byte{}
+	if len(added) > 0 {
+		if req.SecurityPIN == "" {
+			return nil, errors.New(errors.CodeInvalidParam, "新增授权需要安全密码")
+		}
+		if sealed, err = s.sealForAccount(account, req.SecurityPIN, added); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		revoke := tx.Unscoped().Where("service_account_uuid = ?", account.UUID)
+		if len(secretUUIDs) > 0 {
+			revoke = revoke.Where("secret_uuid NOT IN ?", secretUUIDs)
+		}
+		if err := revoke.Delete(&models.ServiceAccountGrant{}).Error; err != nil {
+			return err
+		}
+		if len(added) == 0 {
+			return nil
+		}
+		grants := make([]models.ServiceAccountGrant, len(added))
+		for i, secret := range added {
+			grants[i] = models.ServiceAccountGrant{
+				ServiceAccountUUID: account.UUID,
+				SecretUUID:         secret.SecretUUID,
+				SealedData:         sealed[secret.SecretUUID],
+			}
+		}
+		return tx.Create(&grants).Error
+	})
+	if err != nil {
+		logger.Error("保存服务账户授权失败", logger.String("service_account_uuid", account.UUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	logger.Info("服务账户授权已更新",
+		logger.String("service_account_uuid", account.UUID),
+		logger.Int("count", len(secretUUIDs)),
+		logger.Int("added", len(added)))
+	return &ServiceAccountInfo{ServiceAccount: account, SecretUUIDs: secretUUIDs}, nil
+}
+
+// sealForAccount 用创建者的DEK解密秘密，再用服务账户的公钥封装明文，返回秘密UUID到封装数据的映射
+func (s *ServiceAccountService) sealForAccount(account *models.ServiceAccount, securityPIN string, secrets []*models.EncryptedSecret) (map[string][]byte, error) {
+	userKey, dek, err := s.encryptionService.unlockDEK(account.OwnerUUID, securityPIN)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ClearBytes(dek)
+
+	sealed := make(map[string][]byte, len(secrets))
+	for _, secret := range secrets {
+		if secret.DEKVersion != userKey.DEKVersion {
+			return nil, errors.New(errors.CodeResourceConflict, "保险库的密钥正在轮换，请稍后再试")
+		}
+		plainData, err := crypto.DecryptAESGCM(secret.EncryptedData, dek, secret.Nonce, secret.AuthTag)
+		if err != nil {
+			logger.Error("解密秘密失败", logger.Err(err), logger.String("secret_uuid", secret.SecretUUID))
+			return nil, errors.WithMessage(errors.CodeDecryptionFailed, "解密失败或数据被篡改", err)
+		}
+		data, err := crypto.SealToPublicKey(plainData, account.PublicKey)
+		crypto.ClearBytes(plainData)
+		if err != nil {
+			logger.Error("封装秘密失败", logger.Err(err), logger.String("secret_uuid", secret.SecretUUID))
+			return nil, err
+		}
+		sealed[secret.SecretUUID] = data
+	}
+	return sealed, nil
+}
+
+// AppRoleLoginRequest 服务账户登录请求
+type AppRoleLoginRequest struct {
+	RoleID   string `json:"role_id" binding:"required,max=64"`
+	SecretID string `json:"secret_id" binding:"required,max=128"`
+	ClientIP string `json:"-"` // 不从请求体解析，由handler设置
+}
+
+// AppRoleLoginResponse 服务账户登录响应
+type AppRoleLoginResponse struct {
+	Token          string                 `json:"token"`
+	ExpiresIn      int64                  `json:"expires_in"` // 令牌有效期（秒）
+	ServiceAccount *models.ServiceAccount `json:"service_account"`
+}
+
+// Login 服务账户以 role_id + secret_id 登录，返回服务账户令牌
+// 校验secret_id未过期、来源IP在绑定的CIDR内、登录次数未用完，且保险库所有者状态正常
+func (s *ServiceAccountService) Login(req *AppRoleLoginRequest) (*AppRoleLoginResponse, error) {
+	invalid := errors.New(errors.CodeInvalidCredentials, "role_id或secret_id无效")
+
+	var account models.ServiceAccount
+	if err := s.db.Where("role_id = ?", req.RoleID).First(&account).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, invalid
+		}
+		logger.Error("查询服务账户失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	var secretID models.ServiceAccountSecretID
+	if err := s.db.Where("secret_id_hash = ? AND service_account_uuid = ?", hashPersonalAccessToken(req.SecretID), account.UUID).
+		First(&secretID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Warn("服务账户secret_id错误", logger.String("service_account_uuid", account.UUID), logger.String("ip", req.ClientIP))
+			return nil, invalid
+		}
+		logger.Error("查询secret_id失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if secretID.IsExpired() || secretID.UsesExhausted() {
+		return nil, invalid
+	}
+	if !ipAllowed(secretID.BoundCIDRs, req.ClientIP) {
+		logger.Warn("服务账户登录IP不在绑定范围内",
+			logger.String("secret_id_uuid", secretID.UUID),
+			logger.String("ip", req.ClientIP))
+		return nil, errors.New(errors.CodeForbidden, "当前IP不允许使用该secret_id")
+	}
+	if err := checkServiceAccountOwner(s.db, &account); err != nil {
+		return nil, err
+	}
+
+	// 登录次数在同一条UPDATE中检查并递增，避免并发登录超过次数限制
+	now := time.Now()
+	result := s.db.Model(&models.ServiceAccountSecretID{}).
+		Where("id = ? AND (max_uses = 0 OR use_count < max_uses)", secretID.ID).
+		Updates(map[string]interface{}{
+			"use_count":    gorm.Expr("use_count + 1"),
+			"last_used_at": now,
+			"last_used_ip": req.ClientIP,
+		})
+	if result.Error != nil {
+		logger.Error("更新secret_id使用次数失败", logger.String("secret_id_uuid", secretID.UUID), logger.Err(result.Error))
+		return nil, errors.Wrap(errors.CodeDatabaseError, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, invalid
+	}
+
+	// 令牌不超过secret_id的有效期
+	ttl := time.Duration(s.intConfig(models.ConfigKeyServiceAccountTokenTTLMinutes, models.ConfigValueServiceAccountTokenTTLMinutesDefault)) * time.Minute
+	if remaining := time.Until(secretID.ExpiresAt); remaining < ttl {
+		ttl = remaining
+	}
+	if ttl < time.Second {
+		return nil, invalid
+	}
+
+	tokenBytes, err := crypto.GenerateRandomBytes(serviceAccountTokenSize)
+	if err != nil {
+		logger.Error("生成服务账户令牌失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCryptoError, err)
+	}
+	token := ServiceAccountTokenPrefix + hex.EncodeToString(tokenBytes)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	values := map[string]interface{}{
+		"service_account_uuid": account.UUID,
+		"secret_id_uuid":       secretID.UUID,
+	}
+	if err := s.redis.HSetWithExpiration(ctx, makeServiceAccountTokenKey(token), values, ttl); err != nil {
+		logger.Error("保存服务账户令牌失败", logger.String("service_account_uuid", account.UUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCacheError, err)
+	}
+
+	if err := s.db.Model(&account).UpdateColumn("last_login_at", now).Error; err != nil {
+		logger.Warn("更新服务账户登录时间失败", logger.String("service_account_uuid", account.UUID), logger.Err(err))
+	}
+	account.LastLoginAt = &now
+
+	logger.Info("服务账户登录成功",
+		logger.String("service_account_uuid", account.UUID),
+		logger.String("secret_id_uuid", secretID.UUID),
+		logger.String("ip", req.ClientIP))
+	return &AppRoleLoginResponse{
+		Token:          token,
+		ExpiresIn:      int64(ttl.Seconds()),
+		ServiceAccount: &account,
+	}, nil
+}
+
+// AuthenticateServiceAccountToken 验证服务账户令牌并返回服务账户，供认证中间件使用
+// 检查令牌未过期、服务账户和登录使用的secret_id未被删除、来源IP在secret_id绑定的CIDR内，且保险库所有者状态正常
+func AuthenticateServiceAccountToken(db *gorm.DB, redis *redisClient.Client, token, clientIP string) (*models.ServiceAccount, *errors.AppError) {
+	invalid := errors.New(errors.CodeInvalidToken, "服务账户令牌无效或已过期")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	fields, err := redis.HGetAll(ctx, makeServiceAccountTokenKey(token))
+	if err != nil {
+		logger.Error("查询服务账户令牌失败", logger.Err(err))
+		return nil, errors.New(errors.CodeInternalError, "验证令牌失败")
+	}
+	if len(fields) == 0 {
+		return nil, invalid
+	}
+
+	var account models.ServiceAccount
+	if err := db.Where("uuid = ?", fields["service_account_uuid"]).First(&account).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, invalid
+		}
+		logger.Error("查询服务账户失败", logger.Err(err))
+		return nil, errors.New(errors.CodeInternalError, "查询服务账户失败")
+	}
+
+	var secretID models.ServiceAccountSecretID
+	if err := db.Select("id", "uuid", "bound_cidrs").
+		Where("uuid = ? AND service_account_uuid = ?", fields["secret_id_uuid"], account.UUID).
+		First(&secretID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			// 登录使用的secret_id已撤销
+			return nil, invalid
+		}
+		logger.Error("查询secret_id失败", logger.Err(err))
+		return nil, errors.New(errors.CodeInternalError, "查询服务账户失败")
+	}
+	if !ipAllowed(secretID.BoundCIDRs, clientIP) {
+		logger.Warn("服务账户令牌的使用IP不在绑定范围内",
+			logger.String("service_account_uuid", account.UUID),
+			logger.String("ip", clientIP))
+		return nil, errors.New(errors.CodeForbidden, "当前IP不允许使用该令牌")
+	}
+
+	if err := checkServiceAccountOwner(db, &account); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			return nil, appErr
+		}
+		return nil, errors.New(errors.CodeInternalError, "查询用户失败")
+	}
+	return &account, nil
+}
+
+// checkServiceAccountOwner 检查服务账户所属保险库的所有者存在且状态正常
+func checkServiceAccountOwner(db *gorm.DB, account *models.ServiceAccount) error {
+	var owner models.User
+	if err := db.Where("uuid = ?", account.OwnerUUID).First(&owner).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.New(errors.CodeForbidden, "服务账户所属的用户不存在")
+		}
+		logger.Error("查询用户失败", logger.String("uuid", account.OwnerUUID), logger.Err(err))
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if err := ReleaseExpiredLock(db, &owner); err != nil {
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if !owner.CanOperate() {
+		return errors.New(errors.CodeForbidden, "服务账户所属的用户已被禁用或锁定")
+	}
+	return nil
+}
+
+// GrantedSecretListResponse 服务账户可以访问的秘密列表
+type GrantedSecretListResponse struct {
+	Secrets []*models.SafeEncryptedSecret `json:"secrets"`
+}
+
+// ListGrantedSecrets 查询服务账户可以访问的秘密（不包含加密数据）
+func (s *ServiceAccountService) ListGrantedSecrets(account *models.ServiceAccount) (*GrantedSecretListResponse, error) {
+	secretUUIDs, err := s.grantedSecretUUIDs(account.UUID)
+	if err != nil {
+		return nil, err
+	}
+	safeSecrets := make([]*models.SafeEncryptedSecret, 0, len(secretUUIDs))
+	if len(secretUUIDs) == 0 {
+		return &GrantedSecretListResponse{Secrets: safeSecrets}, nil
+	}
+
+	var secrets []models.EncryptedSecret
+	if err := s.db.Where("user_uuid = ? AND secret_uuid IN ?", account.OwnerUUID, secretUUIDs).
+		Order("created_at DESC").Find(&secrets).Error; err != nil {
+		logger.Error("查询秘密列表失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	for i := range secrets {
+		safeSecrets = append(safeSecrets, secrets[i].ToSafe())
+	}
+	return &GrantedSecretListResponse{Secrets: safeSecrets}, nil
+}
+
+// DecryptGrantedSecret 服务账户解密被授权的秘密
+// 用服务端密钥解开服务账户私钥，再解开授权时封装的明文，不需要安全密码
+func (s *ServiceAccountService) DecryptGrantedSecret(account *models.ServiceAccount, secretUUID string) (*models.DecryptedSecret, error) {
+	notFound := errors.New(errors.CodeResourceNotFound, "秘密不存在或未授权")
+
+	var grant models.ServiceAccountGrant
+	if err := s.db.Where("service_account_uuid = ? AND secret_uuid = ?", account.UUID, secretUUID).First(&grant).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Warn("服务账户访问未授权的秘密",
+				logger.String("service_account_uuid", account.UUID),
+				logger.String("secret_uuid", secretUUID))
+			return nil, notFound
+		}
+		logger.Error("查询服务账户授权失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	var secret models.EncryptedSecret
+	if err := s.db.Where("user_uuid = ? AND secret_uuid = ?", account.OwnerUUID, secretUUID).First(&secret).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, notFound
+		}
+		logger.Error("查询秘密失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if secret.IsExpired() {
+		return nil, errors.New(errors.CodeResourceNotFound, "秘密已过期")
+	}
+	// 授权后秘密被设置为需要检出或审批时，服务账户不能绕过这些限制
+	if secret.CheckoutRequired || secret.ApprovalRequired {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "该秘密需要检出或审批，服务账户不能访问")
+	}
+
+	plainData, err := s.openGrant(account, &grant)
+	if err != nil {
+		return nil, err
+	}
+
+	// 更新访问统计（异步，不影响主流程）
+	go func() {
+		if err := s.db.Model(&models.EncryptedSecret{}).
+			Where("id = ?", secret.ID).
+			Updates(map[string]interface{}{
+				"last_accessed_at": gorm.Expr("NOW()"),
+				"access_count":     gorm.Expr("access_count + ?", 1),
+			}).Error; err != nil {
+			logger.Error("更新秘密访问统计失败", logger.Err(err), logger.Uint("secret_id", secret.ID))
+		}
+	}()
+
+	logger.Info("服务账户解密秘密",
+		logger.String("service_account_uuid", account.UUID),
+		logger.String("secret_uuid", secretUUID))
+	return &models.DecryptedSecret{
+		SafeEncryptedSecret: *secret.ToSafe(),
+		PlainData:           string(plainData),
+	}, nil
+}
+
+// openGrant 用服务端密钥解开服务账户私钥，再解开授权中封装的秘密明文
+func (s *ServiceAccountService) openGrant(account *models.ServiceAccount, grant *models.ServiceAccountGrant) ([]byte, error) {
+	privateKey, err := decryptKeyBlob(account.EncryptedPrivateKey, s.serverKey)
+	if err != nil {
+		logger.Error("解密服务账户私钥失败", logger.Err(err), logger.String("service_account_uuid", account.UUID))
+		return nil, errors.WithMessage(errors.CodeDecryptionFailed, "解密服务账户私钥失败", err)
+	}
+	defer crypto.ClearBytes(privateKey)
+
+	plainData, err := crypto.OpenSealed(grant.SealedData, privateKey)
+	if err != nil {
+		logger.Error("服务账户解密秘密失败", logger.Err(err),
+			logger.String("service_account_uuid", account.UUID),
+			logger.String("secret_uuid", grant.SecretUUID))
+		return nil, errors.WithMessage(errors.CodeDecryptionFailed, "解密失败或数据被篡改", err)
+	}
+	return plainData, nil
+}
+
+// resealServiceAccountGrants 秘密的明文变化后，在同一事务中为被授权的服务账户重新封装
+// 封装只需要服务账户的公钥，不需要服务账户参与；DEK轮换不改变明文，因此不需要调用
+func resealServiceAccountGrants(tx *gorm.DB, secretUUID string, plainData []byte) error {
+	var grants []*models.ServiceAccountGrant
+	if err := tx.Select("id", "service_account_uuid").Where("secret_uuid = ?", secretUUID).Find(&grants).Error; err != nil {
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if len(grants) == 0 {
+		return nil
+	}
+
+	accountUUIDs := make([]string, len(grants))
+	for i, grant := range grants {
+		accountUUIDs[i] = grant.ServiceAccountUUID
+	}
+	var accounts []models.ServiceAccount
+	if err := tx.Select("uuid", "public_key").Where("uuid IN ?", accountUUIDs).Find(&accounts).Error; err != nil {
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	publicKeys := make(map[string][]byte, len(accounts))
+	for _, account := range accounts {
+		publicKeys[account.UUID] = account.PublicKey
+	}
+
+	for _, grant := range grants {
+		publicKey, ok := publicKeys[grant.ServiceAccountUUID]
+		if !ok {
+			continue
+		}
+		sealed, err := crypto.SealToPublicKey(plainData, publicKey)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(grant).Update("sealed_data", sealed).Error; err != nil {
+			return errors.Wrap(errors.CodeDatabaseError, err)
+		}
+	}
+	return nil
+}
+
+// deleteServiceAccountGrants 秘密被彻底删除时删除对应的授权，封装的明文不再保留
+func deleteServiceAccountGrants(db *gorm.DB, secretUUIDs []string) error {
+	return db.Unscoped().Where("secret_uuid IN ?", secretUUIDs).Delete(&models.ServiceAccountGrant{}).Error
+}
+
+// getOwned 查询属于指定用户的服务账户
+func (s *ServiceAccountService) getOwned(ownerUUID, accountUUID string) (*models.ServiceAccount, error) {
+	var account models.ServiceAccount
+	if err := s.db.Where("uuid = ? AND owner_uuid = ?", accountUUID, ownerUUID).First(&account).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeResourceNotFound, "服务账户不存在")
+		}
+		logger.Error("查询服务账户失败", logger.String("service_account_uuid", accountUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return &account, nil
+}
+
+// grantedSecretUUIDs 查询服务账户可以访问的秘密UUID
+func (s *ServiceAccountService) grantedSecretUUIDs(accountUUID string) ([]string, error) {
+	secretUUIDs := []string{}
+	if err := s.db.Model(&models.ServiceAccountGrant{}).Where("service_account_uuid = ?", accountUUID).
+		Order("id").Pluck("secret_uuid", &secretUUIDs).Error; err != nil {
+		logger.Error("查询服务账户授权失败", logger.String("service_account_uuid", accountUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return secretUUIDs, nil
+}
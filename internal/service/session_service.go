@@ -0,0 +1,400 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/config"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/jwt"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	redisClient "github.com/cuihe500/vaulthub/pkg/redis"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 登录会话相关常量
+const (
+	sessionKeyPrefix = "session:"
+	refreshTokenSize = 32
+)
+
+// MakeSessionKey 生成会话有效标记在Redis中的key，值为用户UUID，过期时间与刷新令牌一致
+// 认证中间件据此判断访问令牌所属的会话是否已被注销
+func MakeSessionKey(sessionUUID string) string {
+	return sessionKeyPrefix + sessionUUID
+}
+
+// hashRefreshToken 计算刷新令牌的SHA-256（刷新令牌为高熵随机值，不需要慢哈希）
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// SessionClient 创建会话的客户端信息，嵌入各登录请求
+type SessionClient struct {
+	DeviceLabel string `json:"device_label" binding:"omitempty,max=64"` // 设备名称（可选），显示在会话列表中
+	IPAddress   string `json:"-"`                                       // 不从请求体解析，由handler从请求设置
+	UserAgent   string `json:"-"`                                       // 不从请求体解析，由handler从请求设置
+}
+
+// SessionTokens 会话签发的令牌
+type SessionTokens struct {
+	SessionUUID  string
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64 // 访问令牌有效期（秒）
+}
+
+// SessionService 登录会话服务
+// 每次登录创建一个会话，访问令牌短期有效，刷新令牌每次使用后轮换；
+// 已轮换的刷新令牌再次出现说明令牌已泄露，立即注销整个会话
+type SessionService struct {
+	db            *gorm.DB
+	redis         *redisClient.Client
+	jwtManager    *jwt.Manager
+	configManager *config.ConfigManager
+}
+
+// NewSessionService 创建登录会话服务实例
+func NewSessionService(db *gorm.DB, redis *redisClient.Client, jwtManager *jwt.Manager, configManager *config.ConfigManager) *SessionService {
+	return &SessionService{
+		db:            db,
+		redis:         redis,
+		jwtManager:    jwtManager,
+		configManager: configManager,
+	}
+}
+
+// maxSessions 读取每个用户的会话上限，0表示不限制
+func (s *SessionService) maxSessions() int {
+	value := s.configManager.GetWithDefault(models.ConfigKeyMaxSessionsPerUser, models.ConfigValueMaxSessionsPerUserDefault)
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		logger.Warn("会话上限配置无效，不限制会话数量", logger.String("value", value))
+		return 0
+	}
+	return n
+}
+
+// Create 为用户创建登录会话并签发访问令牌和刷新令牌
+// 配置了会话上限时，先注销最久未活跃的会话
+func (s *SessionService) Create(user *models.User, client SessionClient) (*SessionTokens, error) {
+	s.purgeExpired(user.UUID)
+	if limit := s.maxSessions(); limit > 0 {
+		if err := s.evictOldest(user.UUID, limit-1); err != nil {
+			return nil, err
+		}
+	}
+
+	sessionUUID := uuid.New().String()
+	refreshToken, err := newRefreshToken(sessionUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &models.UserSession{
+		UUID:             sessionUUID,
+		UserUUID:         user.UUID,
+		DeviceLabel:      client.DeviceLabel,
+		IPAddress:        client.IPAddress,
+		UserAgent:        truncateString(client.UserAgent, 512),
+		RefreshTokenHash: hashRefreshToken(refreshToken),
+		TokenEpoch:       user.TokenEpoch,
+		LastSeenAt:       now,
+		ExpiresAt:        now.Add(s.jwtManager.GetRefreshExpiration()),
+	}
+	if err := s.db.Create(session).Error; err != nil {
+		logger.Error("创建登录会话失败", logger.String("uuid", user.UUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	return s.issue(user, session, refreshToken)
+}
+
+// Refresh 使用刷新令牌换取新的访问令牌和刷新令牌，返回会话所属的用户
+func (s *SessionService) Refresh(refreshToken string, client SessionClient) (*models.User, *SessionTokens, error) {
+	sessionUUID, _, ok := strings.Cut(refreshToken, ".")
+	if !ok || sessionUUID == "" {
+		return nil, nil, errors.New(errors.CodeInvalidToken, "刷新令牌无效")
+	}
+
+	var session models.UserSession
+	if err := s.db.Where("uuid = ?", sessionUUID).First(&session).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, errors.New(errors.CodeInvalidToken, "刷新令牌无效")
+		}
+		logger.Error("查询登录会话失败", logger.String("session_uuid", sessionUUID), logger.Err(err))
+		return nil, nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	oldHash := hashRefreshToken(refreshToken)
+	if oldHash != session.RefreshTokenHash {
+		// 会话UUID出现在访问令牌和登录响应中，不是秘密；只有出示已轮换的上一个刷新令牌才说明令牌泄露，
+		// 其他不匹配的令牌只拒绝请求，避免任何人凭会话UUID注销他人的会话
+		if session.PreviousRefreshTokenHash != "" && oldHash == session.PreviousRefreshTokenHash {
+			s.revokeReused(&session)
+			return nil, nil, errors.New(errors.CodeInvalidToken, "刷新令牌已被使用，会话已注销")
+		}
+		return nil, nil, errors.New(errors.CodeInvalidToken, "刷新令牌无效")
+	}
+	if session.IsExpired() {
+		s.revokeQuietly(&session)
+		return nil, nil, errors.New(errors.CodeTokenExpired, "登录会话已过期")
+	}
+
+	var user models.User
+	if err := s.db.Where("uuid = ?", session.UserUUID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			s.revokeQuietly(&session)
+			return nil, nil, errors.New(errors.CodeInvalidToken, "刷新令牌无效")
+		}
+		logger.Error("查询用户失败", logger.String("uuid", session.UserUUID), logger.Err(err))
+		return nil, nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if session.TokenEpoch != user.TokenEpoch {
+		// 会话创建后用户修改了密码、角色或状态
+		s.revokeQuietly(&session)
+		return nil, nil, errors.New(errors.CodeInvalidToken, "登录会话已失效，请重新登录")
+	}
+	if err := checkLoginStatus(s.db, &user); err != nil {
+		return nil, nil, err
+	}
+
+	newToken, err := newRefreshToken(session.UUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	session.PreviousRefreshTokenHash = oldHash
+	session.RefreshTokenHash = hashRefreshToken(newToken)
+	session.LastSeenAt = now
+	session.ExpiresAt = now.Add(s.jwtManager.GetRefreshExpiration())
+	session.IPAddress = client.IPAddress
+	session.UserAgent = truncateString(client.UserAgent, 512)
+
+	// 条件更新：并发请求使用同一个刷新令牌时只有一个能成功，其余视为重复使用
+	result := s.db.Model(&models.UserSession{}).
+		Where("uuid = ? AND refresh_token_hash = ?", session.UUID, oldHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":          session.RefreshTokenHash,
+			"previous_refresh_token_hash": session.PreviousRefreshTokenHash,
+			"last_seen_at":                session.LastSeenAt,
+			"expires_at":                  session.ExpiresAt,
+			"ip_address":                  session.IPAddress,
+			"user_agent":                  session.UserAgent,
+		})
+	if result.Error != nil {
+		logger.Error("轮换刷新令牌失败", logger.String("session_uuid", session.UUID), logger.Err(result.Error))
+		return nil, nil, errors.Wrap(errors.CodeDatabaseError, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		s.revokeReused(&session)
+		return nil, nil, errors.New(errors.CodeInvalidToken, "刷新令牌已被使用，会话已注销")
+	}
+
+	tokens, err := s.issue(&user, &session, newToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &user, tokens, nil
+}
+
+// issue 写入会话有效标记并签发访问令牌
+func (s *SessionService) issue(user *models.User, session *models.UserSession, refreshToken string) (*SessionTokens, error) {
+	accessToken, err := s.jwtManager.GenerateToken(user.UUID, user.Username, user.Role, session.UUID, user.TokenEpoch)
+	if err != nil {
+		logger.Error("生成JWT token失败", logger.String("uuid", user.UUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeInternalError, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err := s.redis.Set(ctx, MakeSessionKey(session.UUID), user.UUID, time.Until(session.ExpiresAt)); err != nil {
+		// Redis中缺少会话标记时认证中间件按数据库中的会话校验并回填，不影响登录
+		logger.Error("存储会话到Redis失败",
+			logger.String("uuid", user.UUID),
+			logger.String("session_uuid", session.UUID),
+			logger.Err(err))
+	}
+
+	return &SessionTokens{
+		SessionUUID:  session.UUID,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.jwtManager.GetExpiration() / time.Second),
+	}, nil
+}
+
+// SessionInfo 会话列表项
+type SessionInfo struct {
+	models.UserSession
+	Current bool `json:"current"` // 是否为发起请求的会话
+}
+
+// SessionListResponse 会话列表
+type SessionListResponse struct {
+	Sessions []SessionInfo `json:"sessions"`
+}
+
+// List 查询用户未过期的会话，按最后活跃时间倒序
+func (s *SessionService) List(userUUID, currentSessionUUID string) (*SessionListResponse, error) {
+	s.purgeExpired(userUUID)
+
+	var sessions []models.UserSession
+	if err := s.db.Where("user_uuid = ?", userUUID).Order("last_seen_at DESC").Find(&sessions).Error; err != nil {
+		logger.Error("查询登录会话失败", logger.String("uuid", userUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	resp := &SessionListResponse{Sessions: make([]SessionInfo, len(sessions))}
+	for i := range sessions {
+		resp.Sessions[i] = SessionInfo{UserSession: sessions[i], Current: sessions[i].UUID == currentSessionUUID}
+	}
+	return resp, nil
+}
+
+// Revoke 注销用户的一个会话，返回被注销的会话
+func (s *SessionService) Revoke(userUUID, sessionUUID string) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := s.db.Where("uuid = ? AND user_uuid = ?", sessionUUID, userUUID).First(&session).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeResourceNotFound, "会话不存在")
+		}
+		logger.Error("查询登录会话失败", logger.String("session_uuid", sessionUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	if err := s.revoke(session); err != nil {
+		return nil, err
+	}
+	logger.Info("登录会话已注销", logger.String("uuid", userUUID), logger.String("session_uuid", sessionUUID))
+	return &session, nil
+}
+
+// RevokeOthers 注销用户除当前会话以外的全部会话，返回注销的数量
+func (s *SessionService) RevokeOthers(userUUID, currentSessionUUID string) (int, error) {
+	var sessions []models.UserSession
+	if err := s.db.Where("user_uuid = ? AND uuid <> ?", userUUID, currentSessionUUID).Find(&sessions).Error; err != nil {
+		logger.Error("查询登录会话失败", logger.String("uuid", userUUID), logger.Err(err))
+		return 0, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	if err := s.revoke(sessions...); err != nil {
+		return 0, err
+	}
+	logger.Info("已注销其他登录会话", logger.String("uuid", userUUID), logger.Int("count", len(sessions)))
+	return len(sessions), nil
+}
+
+// RevokeAll 注销用户的全部会话（如账户被接管、管理员重置时）
+func (s *SessionService) RevokeAll(userUUID string) error {
+	return revokeAllSessions(s.db, s.redis, userUUID)
+}
+
+// revokeAllSessions 注销用户的全部会话，供不依赖SessionService的服务使用
+func revokeAllSessions(db *gorm.DB, redis *redisClient.Client, userUUID string) error {
+	var sessions []models.UserSession
+	if err := db.Where("user_uuid = ?", userUUID).Find(&sessions).Error; err != nil {
+		logger.Error("查询登录会话失败", logger.String("uuid", userUUID), logger.Err(err))
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return deleteSessions(db, redis, sessions...)
+}
+
+// bumpTokenEpoch 递增用户的令牌版本，此前签发的访问令牌和创建的会话立即失效
+// 在修改密码、角色或状态的事务中调用，使令牌失效与修改同时生效；返回数据库原始错误，由调用方包装
+func bumpTokenEpoch(tx *gorm.DB, userUUID string) error {
+	return tx.Model(&models.User{}).Where("uuid = ?", userUUID).
+		UpdateColumn("token_epoch", gorm.Expr("token_epoch + 1")).Error
+}
+
+// evictOldest 会话数量超过keep时注销最久未活跃的会话
+func (s *SessionService) evictOldest(userUUID string, keep int) error {
+	// MySQL不支持不带LIMIT的OFFSET，查出全部会话后再截取
+	var sessions []models.UserSession
+	if err := s.db.Where("user_uuid = ?", userUUID).Order("last_seen_at DESC").Find(&sessions).Error; err != nil {
+		logger.Error("查询登录会话失败", logger.String("uuid", userUUID), logger.Err(err))
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if len(sessions) <= keep {
+		return nil
+	}
+	sessions = sessions[keep:]
+
+	logger.Info("超出会话上限，注销最久未活跃的会话", logger.String("uuid", userUUID), logger.Int("count", len(sessions)))
+	return s.revoke(sessions...)
+}
+
+// purgeExpired 清理用户已过期的会话，失败只记录日志
+func (s *SessionService) purgeExpired(userUUID string) {
+	if err := s.db.Unscoped().Where("user_uuid = ? AND expires_at < ?", userUUID, time.Now()).
+		Delete(&models.UserSession{}).Error; err != nil {
+		logger.Warn("清理过期会话失败", logger.String("uuid", userUUID), logger.Err(err))
+	}
+}
+
+// revokeReused 刷新令牌被重复使用时注销会话
+func (s *SessionService) revokeReused(session *models.UserSession) {
+	logger.Warn("检测到刷新令牌重复使用，注销会话",
+		logger.String("uuid", session.UserUUID),
+		logger.String("session_uuid", session.UUID))
+	s.revokeQuietly(session)
+}
+
+// revokeQuietly 注销会话，失败只记录日志（用于拒绝请求前的清理）
+func (s *SessionService) revokeQuietly(session *models.UserSession) {
+	if err := s.revoke(*session); err != nil {
+		logger.Error("注销会话失败", logger.String("session_uuid", session.UUID), logger.Err(err))
+	}
+}
+
+// revoke 删除会话记录和Redis中的会话标记，会话的访问令牌和刷新令牌立即失效
+func (s *SessionService) revoke(sessions ...models.UserSession) error {
+	return deleteSessions(s.db, s.redis, sessions...)
+}
+
+// deleteSessions 删除会话记录和Redis中的会话标记
+func deleteSessions(db *gorm.DB, redis *redisClient.Client, sessions ...models.UserSession) error {
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	uuids := make([]string, len(sessions))
+	keys := make([]string, len(sessions))
+	for i := range sessions {
+		uuids[i] = sessions[i].UUID
+		keys[i] = MakeSessionKey(sessions[i].UUID)
+	}
+
+	if err := db.Unscoped().Where("uuid IN ?", uuids).Delete(&models.UserSession{}).Error; err != nil {
+		logger.Error("删除登录会话失败", logger.Err(err))
+		return errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := redis.Del(ctx, keys...); err != nil {
+		// 数据库记录已删除，Redis不可用时认证中间件降级到数据库校验，会话同样失效
+		logger.Warn("从Redis删除会话失败", logger.Err(err))
+	}
+	return nil
+}
+
+// newRefreshToken 生成刷新令牌：会话UUID.随机值，会话UUID用于定位会话，随机值保证不可猜测
+func newRefreshToken(sessionUUID string) (string, error) {
+	secret, err := crypto.GenerateRandomBytes(refreshTokenSize)
+	if err != nil {
+		logger.Error("生成刷新令牌失败", logger.Err(err))
+		return "", errors.Wrap(errors.CodeCryptoError, err)
+	}
+	return sessionUUID + "." + hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	redisClient "github.com/cuihe500/vaulthub/pkg/redis"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// 分享链接相关常量
+const (
+	shareKeyPrefix          = "share:"
+	shareDefaultTTL         = 24 * time.Hour
+	shareMaxFailedAttempts  = 5 // 口令连续错误达到该次数后销毁分享
+	shareAnonymousAuditUser = "anonymous"
+)
+
+// consumeShareScript 原子地消耗一次查看次数并返回密文
+// 次数用完时删除整个分享；键不存在时返回nil
+const consumeShareScript = `
+if redis.call('EXISTS', KEYS[1]) == 0 then
+  return nil
+end
+local left = redis.call('HINCRBY', KEYS[1], 'views_left', -1)
+local data = redis.call('HMGET', KEYS[1], 'ciphertext', 'nonce')
+if left <= 0 then
+  redis.call('DEL', KEYS[1])
+end
+return {left, data[1], data[2]}
+`
+
+// recordShareFailureScript 记录一次口令错误，达到上限时删除分享，返回累计错误次数
+const recordShareFailureScript = `
+if redis.call('EXISTS', KEYS[1]) == 0 then
+  return 0
+end
+local failed = redis.call('HINCRBY', KEYS[1], 'failed_attempts', 1)
+if failed >= tonumber(ARGV[1]) then
+  redis.call('DEL', KEYS[1])
+end
+return failed
+`
+
+// ShareService 一次性分享链接服务
+// 分享内容使用随机密钥加密后保存在Redis中，密钥只出现在返回给创建者的链接片段（#之后）中，
+// 浏览器不会把片段发送给服务端，因此服务端保存的密文无法单独解密。
+// 查看次数用完或过期后密文被删除
+type ShareService struct {
+	redis             *redisClient.Client
+	encryptionService *EncryptionService
+	auditService      *AuditService
+}
+
+// NewShareService 创建分享链接服务实例
+func NewShareService(redis *redisClient.Client, encryptionService *EncryptionService, auditService *AuditService) *ShareService {
+	return &ShareService{
+		redis:             redis,
+		encryptionService: encryptionService,
+		auditService:      auditService,
+	}
+}
+
+// makeShareKey 生成分享在Redis中的key
+func makeShareKey(shareUUID string) string {
+	return shareKeyPrefix + shareUUID
+}
+
+// CreateShareRequest 创建分享链接请求
+// secret_uuid 和 content 二选一：分享已保存的秘密时需要安全密码
+type CreateShareRequest struct {
+	UserUUID    string `json:"-"`                                                // 不从请求体解析，由handler从上下文设置
+	SecretUUID  string `json:"secret_uuid" binding:"omitempty,uuid"`             // 要分享的秘密
+	SecurityPIN string `json:"security_pin"`                                     // 安全密码（分享秘密时必填）
+	Content     string `json:"content" binding:"omitempty,max=65536"`            // 临时文本（不分享已保存秘密时使用）
+	Passphrase  string `json:"passphrase" binding:"omitempty,min=6,max=128"`     // 可选的查看口令，需另行告知接收方
+	ExpiresIn   int    `json:"expires_in" binding:"omitempty,min=60,max=604800"` // 有效期（秒），默认24小时，最长7天
+	MaxViews    int    `json:"max_views" binding:"omitempty,min=1,max=100"`      // 最多查看次数，默认1次
+}
+
+// CreateShareResponse 创建分享链接响应
+type CreateShareResponse struct {
+	ShareUUID string    `json:"share_uuid"`
+	URL       string    `json:"url"` // 完整链接，#之后为解密密钥，只返回这一次
+	ExpiresAt time.Time `json:"expires_at"`
+	MaxViews  int       `json:"max_views"`
+}
+
+// CreateShare 创建一次性分享链接
+// baseURL 用于拼接分享链接，由handler根据请求地址构造
+func (s *ShareService) CreateShare(req *CreateShareRequest, baseURL string) (*CreateShareResponse, error) {
+	if (req.SecretUUID == "") == (req.Content == "") {
+		return nil, errors.New(errors.CodeInvalidParam, "secret_uuid和content必须且只能提供一个")
+	}
+	if req.ExpiresIn == 0 {
+		req.ExpiresIn = int(shareDefaultTTL / time.Second)
+	}
+	if req.MaxViews == 0 {
+		req.MaxViews = 1
+	}
+
+	// 1. 准备明文：已保存的秘密需要先用安全密码解密
+	var plaintext []byte
+	secretName := ""
+	if req.SecretUUID != "" {
+		if req.SecurityPIN == "" {
+			return nil, errors.New(errors.CodeSecurityPINRequired, "分享秘密需要输入安全密码")
+		}
+		secret, err := s.encryptionService.DecryptSecret(&DecryptSecretRequest{
+			UserUUID:    req.UserUUID,
+			SecretUUID:  req.SecretUUID,
+			SecurityPIN: req.SecurityPIN,
+		})
+		if err != nil {
+			return nil, err
+		}
+		plaintext = []byte(secret.PlainData)
+		secretName = secret.SecretName
+	} else {
+		plaintext = []byte(req.Content)
+	}
+	defer crypto.ClearBytes(plaintext)
+
+	// 2. 用随机密钥加密，密钥不落盘
+	key, err := crypto.GenerateRandomBytes(crypto.AESKeySize)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ClearBytes(key)
+	ciphertext, nonce, authTag, err := crypto.EncryptAESGCM(plaintext, key)
+	if err != nil {
+		logger.Error("加密分享内容失败", logger.Err(err))
+		return nil, err
+	}
+
+	// 3. 写入Redis，密文格式与WebCrypto的AES-GCM一致：[密文][认证标签]
+	passphraseHash := ""
+	if req.Passphrase != "" {
+		if passphraseHash, err = crypto.HashPassword(req.Passphrase); err != nil {
+			logger.Error("计算分享口令哈希失败", logger.Err(err))
+			return nil, errors.WithMessage(errors.CodeInternalError, "创建分享失败", err)
+		}
+	}
+	shareUUID := uuid.New().String()
+	ttl := time.Duration(req.ExpiresIn) * time.Second
+	expiresAt := time.Now().Add(ttl)
+	values := map[string]interface{}{
+		"ciphertext":      base64.StdEncoding.EncodeToString(append(ciphertext, authTag...)),
+		"nonce":           base64.StdEncoding.EncodeToString(nonce),
+		"views_left":      req.MaxViews,
+		"max_views":       req.MaxViews,
+		"failed_attempts": 0,
+		"passphrase_hash": passphraseHash,
+		"creator_uuid":    req.UserUUID,
+		"secret_uuid":     req.SecretUUID,
+		"secret_name":     secretName,
+		"expires_at":      expiresAt.Unix(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := s.redis.HSetWithExpiration(ctx, makeShareKey(shareUUID), values, ttl); err != nil {
+		logger.Error("保存分享到Redis失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCacheError, err)
+	}
+
+	logger.Info("创建分享链接成功",
+		logger.String("user_uuid", req.UserUUID),
+		logger.String("share_uuid", shareUUID),
+		logger.String("secret_uuid", req.SecretUUID),
+		logger.Int("max_views", req.MaxViews))
+
+	return &CreateShareResponse{
+		ShareUUID: shareUUID,
+		URL:       fmt.Sprintf("%s/share/%s#%s", baseURL, shareUUID, base64.RawURLEncoding.EncodeToString(key)),
+		ExpiresAt: expiresAt,
+		MaxViews:  req.MaxViews,
+	}, nil
+}
+
+// ShareInfo 分享的公开信息（不包含密文，查看不消耗次数）
+type ShareInfo struct {
+	ShareUUID          string    `json:"share_uuid"`
+	SecretName         string    `json:"secret_name,omitempty"`
+	RequiresPassphrase bool      `json:"requires_passphrase"`
+	ViewsLeft          int       `json:"views_left"`
+	ExpiresAt          time.Time `json:"expires_at"`
+}
+
+// GetShareInfo 获取分享的公开信息
+// 链接预览（聊天工具抓取链接）只会触发此接口，不会消耗查看次数
+func (s *ShareService) GetShareInfo(shareUUID string) (*ShareInfo, error) {
+	fields, err := s.loadShare(shareUUID)
+	if err != nil {
+		return nil, err
+	}
+	return &ShareInfo{
+		ShareUUID:          shareUUID,
+		SecretName:         fields["secret_name"],
+		RequiresPassphrase: fields["passphrase_hash"] != "",
+		ViewsLeft:          atoiOrZero(fields["views_left"]),
+		ExpiresAt:          time.Unix(int64(atoiOrZero(fields["expires_at"])), 0),
+	}, nil
+}
+
+// OpenShareRequest 打开分享请求
+type OpenShareRequest struct {
+	ShareUUID  string `json:"-"`          // 由handler从路径参数设置
+	Passphrase string `json:"passphrase"` // 查看口令（创建时设置了口令才需要）
+	IPAddress  string `json:"-"`          // 查看者IP，用于审计
+	UserAgent  string `json:"-"`          // 查看者User-Agent，用于审计
+	RequestID  string `json:"-"`          // 请求ID，用于审计
+}
+
+// OpenShareResponse 打开分享响应
+// 服务端只返回密文，由客户端使用链接片段中的密钥以AES-256-GCM解密
+type OpenShareResponse struct {
+	Ciphertext string `json:"ciphertext"` // base64，[密文][认证标签(16字节)]
+	Nonce      string `json:"nonce"`      // base64，12字节
+	SecretName string `json:"secret_name,omitempty"`
+	ViewsLeft  int    `json:"views_left"` // 剩余查看次数，为0时分享已销毁
+}
+
+// OpenShare 查看分享内容并消耗一次查看次数
+// 每次打开（包括口令错误）都会以创建者身份写入审计日志，创建者可通过审计日志确认分享是否已被查看
+func (s *ShareService) OpenShare(req *OpenShareRequest) (*OpenShareResponse, error) {
+	fields, err := s.loadShare(req.ShareUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	key := makeShareKey(req.ShareUUID)
+
+	// 1. 校验口令，错误次数过多时销毁分享
+	if hash := fields["passphrase_hash"]; hash != "" && !crypto.VerifyPassword(req.Passphrase, hash) {
+		failed, err := s.redis.Eval(ctx, recordShareFailureScript, []string{key}, shareMaxFailedAttempts)
+		if err != nil {
+			logger.Error("记录分享口令错误次数失败", logger.Err(err))
+		}
+		attempts, _ := failed.(int64)
+		s.logOpenAudit(req, fields, models.AuditFailed, map[string]interface{}{
+			"reason":          "invalid_passphrase",
+			"failed_attempts": attempts,
+			"destroyed":       attempts >= shareMaxFailedAttempts,
+		})
+		logger.Warn("分享口令错误", logger.String("share_uuid", req.ShareUUID), logger.Int64("failed_attempts", attempts))
+		return nil, errors.New(errors.CodeInvalidCredentials, "查看口令错误")
+	}
+
+	// 2. 原子地消耗一次查看次数并取出密文
+	result, err := s.redis.Eval(ctx, consumeShareScript, []string{key})
+	if err != nil {
+		if err == redis.Nil {
+			return nil, errors.New(errors.CodeResourceNotFound, "分享不存在、已过期或已被查看")
+		}
+		logger.Error("读取分享失败", logger.Err(err), logger.String("share_uuid", req.ShareUUID))
+		return nil, errors.Wrap(errors.CodeCacheError, err)
+	}
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 3 {
+		logger.Error("分享数据格式异常", logger.String("share_uuid", req.ShareUUID))
+		return nil, errors.New(errors.CodeInternalError, "分享数据格式异常")
+	}
+	viewsLeft, _ := values[0].(int64)
+	ciphertext, _ := values[1].(string)
+	nonce, _ := values[2].(string)
+	if viewsLeft < 0 {
+		viewsLeft = 0
+	}
+
+	s.logOpenAudit(req, fields, models.AuditSuccess, map[string]interface{}{
+		"views_left": viewsLeft,
+		"destroyed":  viewsLeft == 0,
+	})
+	logger.Info("分享链接被查看", logger.String("share_uuid", req.ShareUUID), logger.Int64("views_left", viewsLeft))
+
+	return &OpenShareResponse{
+		Ciphertext: ciphertext,
+		Nonce:      nonce,
+		SecretName: fields["secret_name"],
+		ViewsLeft:  int(viewsLeft),
+	}, nil
+}
+
+// RevokeShare 创建者提前销毁分享
+func (s *ShareService) RevokeShare(userUUID, shareUUID string) error {
+	fields, err := s.loadShare(shareUUID)
+	if err != nil {
+		return err
+	}
+	if fields["creator_uuid"] != userUUID {
+		// 不区分"不存在"和"不属于当前用户"，避免泄露分享是否存在
+		return errors.New(errors.CodeResourceNotFound, "分享不存在、已过期或已被查看")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := s.redis.Del(ctx, makeShareKey(shareUUID)); err != nil {
+		logger.Error("删除分享失败", logger.Err(err), logger.String("share_uuid", shareUUID))
+		return errors.Wrap(errors.CodeCacheError, err)
+	}
+
+	logger.Info("撤销分享链接成功", logger.String("user_uuid", userUUID), logger.String("share_uuid", shareUUID))
+	return nil
+}
+
+// loadShare 读取分享的全部字段，不存在时返回CodeResourceNotFound
+func (s *ShareService) loadShare(shareUUID string) (map[string]string, error) {
+	if _, err := uuid.Parse(shareUUID); err != nil {
+		return nil, errors.New(errors.CodeResourceNotFound, "分享不存在、已过期或已被查看")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	fields, err := s.redis.HGetAll(ctx, makeShareKey(shareUUID))
+	if err != nil {
+		logger.Error("读取分享失败", logger.Err(err), logger.String("share_uuid", shareUUID))
+		return nil, errors.Wrap(errors.CodeCacheError, err)
+	}
+	if len(fields) == 0 {
+		return nil, errors.New(errors.CodeResourceNotFound, "分享不存在、已过期或已被查看")
+	}
+	return fields, nil
+}
+
+// logOpenAudit 以分享创建者的身份记录查看审计日志
+func (s *ShareService) logOpenAudit(req *OpenShareRequest, fields map[string]string, status models.AuditStatus, extra map[string]interface{}) {
+	if s.auditService == nil {
+		return
+	}
+
+	extra["operation"] = "open_share"
+	if secretUUID := fields["secret_uuid"]; secretUUID != "" {
+		extra["secret_uuid"] = secretUUID
+	}
+	details, _ := json.Marshal(extra)
+
+	shareUUID := req.ShareUUID
+	log := &models.AuditLog{
+		UserUUID:     fields["creator_uuid"],
+		Username:     shareAnonymousAuditUser,
+		ActionType:   models.ActionAccess,
+		ResourceType: models.ResourceShare,
+		ResourceUUID: &shareUUID,
+		Status:       status,
+		Details:      string(details),
+		CreatedAt:    time.Now().UTC(),
+	}
+	if name := fields["secret_name"]; name != "" {
+		log.ResourceName = &name
+	}
+	if req.IPAddress != "" {
+		log.IPAddress = &req.IPAddress
+	}
+	if req.UserAgent != "" {
+		log.UserAgent = &req.UserAgent
+	}
+	if req.RequestID != "" {
+		log.RequestID = &req.RequestID
+	}
+	s.auditService.LogAsync(log)
+}
+
+// atoiOrZero 解析整数，失败时返回0
+func atoiOrZero(value string) int {
+	n, _ := strconv.Atoi(value)
+	return n
+}
@@ -0,0 +1,439 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// 模板渲染相关常量
+const (
+	maxTemplateSize     = 256 * 1024  // 模板最大长度
+	maxRenderOutputSize = 1024 * 1024 // 渲染结果最大长度
+	maxTemplateRefs     = 200         // 单个模板最多引用的秘密数
+	templateSecretFunc  = "secret"    // 模板中引用秘密的函数名
+)
+
+// TemplateService 配置模板渲染服务
+// 模板使用Go text/template语法，通过 {{ secret "路径" "字段" }} 引用秘密：
+//   - 路径为 "文件夹/名称"，没有文件夹的秘密直接使用名称
+//   - 字段可选，指定时秘密内容必须是JSON对象，取对应字段的值
+//
+// 渲染前先静态分析模板收集所有引用，任一引用不存在时拒绝渲染；
+// 所有秘密用一次解锁的DEK解密，每个被引用的秘密都以同一请求ID记录访问审计
+type TemplateService struct {
+	db                *gorm.DB
+	encryptionService *EncryptionService
+	auditService      *AuditService
+}
+
+// NewTemplateService 创建模板渲染服务实例
+func NewTemplateService(db *gorm.DB, encryptionService *EncryptionService, auditService *AuditService) *TemplateService {
+	return &TemplateService{
+		db:                db,
+		encryptionService: encryptionService,
+		auditService:      auditService,
+	}
+}
+
+// RenderTemplateRequest 渲染模板请求
+type RenderTemplateRequest struct {
+	UserUUID    string `json:"-"`                               // 由handler从上下文设置
+	Username    string `json:"-"`                               // 由handler从上下文设置
+	SecurityPIN string `json:"security_pin" binding:"required"` // 安全密码，用于解密DEK
+	Template    string `json:"template" binding:"required"`     // 模板内容
+
+	SecretUUIDs []string `json:"-"` // 只允许引用的秘密（个人访问令牌的秘密范围），为空时不限制
+
+	RequestID string `json:"-"` // 请求ID，每个秘密的访问审计都关联到该请求
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+// TemplateSecretRef 模板引用的秘密
+type TemplateSecretRef struct {
+	Path       string `json:"path"`
+	SecretUUID string `json:"secret_uuid"`
+}
+
+// RenderTemplateResponse 渲染模板响应
+type RenderTemplateResponse struct {
+	Output  string               `json:"output"`  // 渲染结果
+	Secrets []*TemplateSecretRef `json:"secrets"` // 引用的秘密（按路径排序）
+}
+
+// Render 解析模板、解密引用的秘密并渲染
+func (s *TemplateService) Render(req *RenderTemplateRequest) (*RenderTemplateResponse, error) {
+	if len(req.Template) > maxTemplateSize {
+		return nil, errors.New(errors.CodeParamOutOfRange, fmt.Sprintf("模板长度不能超过%dKB", maxTemplateSize/1024))
+	}
+
+	// 1. 解析模板并收集引用（secret函数在渲染时替换为实际实现）
+	tmpl, err := template.New("render").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{templateSecretFunc: func(string, ...string) (string, error) { return "", nil }}).
+		Parse(req.Template)
+	if err != nil {
+		return nil, errors.New(errors.CodeInvalidParam, "模板语法错误: "+err.Error())
+	}
+	refs := make(map[string]struct{})
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		if err := collectSecretRefs(t.Tree.Root, refs); err != nil {
+			return nil, err
+		}
+	}
+	if len(refs) > maxTemplateRefs {
+		return nil, errors.New(errors.CodeParamOutOfRange, fmt.Sprintf("模板最多引用%d个秘密", maxTemplateRefs))
+	}
+
+	// 2. 查找引用的秘密，任一引用不存在或不可访问时拒绝渲染
+	secrets, err := s.resolveSecretPaths(req.UserUUID, refs)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSecretScope(req.SecretUUIDs, secrets); err != nil {
+		return nil, err
+	}
+
+	// 3. 一次解锁DEK，解密所有引用的秘密
+	values, err := s.decryptAll(req.UserUUID, req.SecurityPIN, secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 渲染模板
+	output := &limitedBuffer{limit: maxRenderOutputSize}
+	tmpl.Funcs(template.FuncMap{templateSecretFunc: func(path string, field ...string) (string, error) {
+		return secretField(path, values[path], field)
+	}})
+	if err := tmpl.Execute(output, nil); err != nil {
+		return nil, errors.New(errors.CodeInvalidParam, "模板渲染失败: "+err.Error())
+	}
+
+	// 5. 更新访问统计并逐个记录访问审计
+	s.recordAccess(req, secrets)
+
+	resp := &RenderTemplateResponse{Output: output.String(), Secrets: make([]*TemplateSecretRef, 0, len(secrets))}
+	for path, secret := range secrets {
+		resp.Secrets = append(resp.Secrets, &TemplateSecretRef{Path: path, SecretUUID: secret.SecretUUID})
+	}
+	sort.Slice(resp.Secrets, func(i, j int) bool { return resp.Secrets[i].Path < resp.Secrets[j].Path })
+
+	logger.Info("渲染模板成功",
+		logger.String("user_uuid", req.UserUUID),
+		logger.Int("secret_count", len(secrets)),
+		logger.Int("output_size", output.Len()))
+	return resp, nil
+}
+
+// resolveSecretPaths 按路径查找秘密，并检查每个秘密是否允许直接解密
+func (s *TemplateService) resolveSecretPaths(userUUID string, refs map[string]struct{}) (map[string]*models.EncryptedSecret, error) {
+	result := make(map[string]*models.EncryptedSecret, len(refs))
+	if len(refs) == 0 {
+		return result, nil
+	}
+
+	var secrets []models.EncryptedSecret
+	if err := s.db.Where("user_uuid = ?", userUUID).Find(&secrets).Error; err != nil {
+		logger.Error("查询秘密失败", logger.Err(err), logger.String("user_uuid", userUUID))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	var ambiguous []string
+	for i := range secrets {
+		path := secretPath(&secrets[i])
+		if _, ok := refs[path]; !ok {
+			continue
+		}
+		if _, exists := result[path]; exists {
+			ambiguous = append(ambiguous, path)
+			continue
+		}
+		result[path] = &secrets[i]
+	}
+	if len(ambiguous) > 0 {
+		return nil, errors.New(errors.CodeResourceConflict, "存在多个同路径的秘密: "+strings.Join(ambiguous, ", "))
+	}
+
+	var unknown []string
+	for path := range refs {
+		if _, ok := result[path]; !ok {
+			unknown = append(unknown, path)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, errors.New(errors.CodeResourceNotFound, "引用的秘密不存在: "+strings.Join(unknown, ", "))
+	}
+
+	for path, secret := range result {
+		switch {
+		case secret.IsExpired():
+			return nil, errors.New(errors.CodeResourceNotFound, "引用的秘密已过期: "+path)
+		case secret.CheckoutRequired:
+			return nil, errors.New(errors.CodeOperationNotAllowed, "需要检出的秘密不能在模板中引用: "+path)
+		case secret.ApprovalRequired:
+			grant, err := findAccessGrant(s.db, userUUID, secret.SecretUUID)
+			if err != nil {
+				return nil, err
+			}
+			if grant == nil {
+				return nil, errors.New(errors.CodeApprovalRequired, "引用的高敏感秘密需要审批通过后才能解密: "+path)
+			}
+		}
+	}
+	return result, nil
+}
+
+// checkSecretScope 限定了秘密范围时，引用的秘密必须都在范围内
+func checkSecretScope(allowed []string, secrets map[string]*models.EncryptedSecret) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(allowed))
+	for _, secretUUID := range allowed {
+		set[secretUUID] = true
+	}
+	var denied []string
+	for path, secret := range secrets {
+		if !set[secret.SecretUUID] {
+			denied = append(denied, path)
+		}
+	}
+	if len(denied) > 0 {
+		sort.Strings(denied)
+		return errors.New(errors.CodeInsufficientPermission, "个人访问令牌不能访问引用的秘密: "+strings.Join(denied, ", "))
+	}
+	return nil
+}
+
+// decryptAll 解锁一次DEK并解密所有秘密，返回路径到明文的映射
+func (s *TemplateService) decryptAll(userUUID, securityPIN string, secrets map[string]*models.EncryptedSecret) (map[string]string, error) {
+	values := make(map[string]string, len(secrets))
+	if len(secrets) == 0 {
+		return values, nil
+	}
+
+	userKey, dek, err := s.encryptionService.unlockDEK(userUUID, securityPIN)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ClearBytes(dek)
+
+	for path, secret := range secrets {
+		if secret.DEKVersion != userKey.DEKVersion {
+			logger.Warn("DEK版本不匹配", logger.String("secret_uuid", secret.SecretUUID),
+				logger.Int("secret_version", secret.DEKVersion), logger.Int("current_version", userKey.DEKVersion))
+			return nil, errors.New(errors.CodeCryptoError, "密钥版本不匹配，请等待密钥轮换完成后再试")
+		}
+		plainData, err := crypto.DecryptAESGCM(secret.EncryptedData, dek, secret.Nonce, secret.AuthTag)
+		if err != nil {
+			logger.Error("解密秘密数据失败", logger.Err(err), logger.String("secret_uuid", secret.SecretUUID))
+			return nil, errors.WithMessage(errors.CodeDecryptionFailed, "解密失败或数据被篡改: "+path, err)
+		}
+		values[path] = string(plainData)
+		crypto.ClearBytes(plainData)
+	}
+	return values, nil
+}
+
+// recordAccess 更新访问统计，并以同一请求ID为每个秘密记录访问审计
+func (s *TemplateService) recordAccess(req *RenderTemplateRequest, secrets map[string]*models.EncryptedSecret) {
+	if len(secrets) == 0 {
+		return
+	}
+
+	ids := make([]uint, 0, len(secrets))
+	for _, secret := range secrets {
+		ids = append(ids, secret.ID)
+	}
+	if err := s.db.Model(&models.EncryptedSecret{}).
+		Where("id IN ?", ids).
+		UpdateColumns(map[string]interface{}{
+			"last_accessed_at": gorm.Expr("NOW()"),
+			"access_count":     gorm.Expr("access_count + ?", 1),
+		}).Error; err != nil {
+		logger.Error("更新秘密访问统计失败", logger.Err(err), logger.String("user_uuid", req.UserUUID))
+	}
+
+	if s.auditService == nil {
+		return
+	}
+	now := time.Now().UTC()
+	for path, secret := range secrets {
+		details, _ := json.Marshal(map[string]interface{}{
+			"operation": "template_render",
+			"path":      path,
+		})
+		secretUUID := secret.SecretUUID
+		secretName := secret.SecretName
+		log := &models.AuditLog{
+			UserUUID:     req.UserUUID,
+			Username:     req.Username,
+			ActionType:   models.ActionAccess,
+			ResourceType: models.ResourceSecret,
+			ResourceUUID: &secretUUID,
+			ResourceName: &secretName,
+			Status:       models.AuditSuccess,
+			Details:      string(details),
+			CreatedAt:    now,
+		}
+		if req.IPAddress != "" {
+			log.IPAddress = &req.IPAddress
+		}
+		if req.UserAgent != "" {
+			log.UserAgent = &req.UserAgent
+		}
+		if req.RequestID != "" {
+			log.RequestID = &req.RequestID
+		}
+		s.auditService.LogAsync(log)
+	}
+}
+
+// secretPath 返回秘密在模板中的引用路径
+func secretPath(secret *models.EncryptedSecret) string {
+	return SecretRefPath(secret.Metadata, secret.SecretName)
+}
+
+// SecretRefPath 返回秘密在模板中的引用路径（"文件夹/名称"），没有文件夹时为名称
+func SecretRefPath(metadata *models.SecretMetadata, name string) string {
+	if metadata == nil || strings.Trim(metadata.Folder, "/") == "" {
+		return name
+	}
+	return strings.Trim(metadata.Folder, "/") + "/" + name
+}
+
+// secretField 取秘密内容或其中的JSON字段
+func secretField(path, value string, field []string) (string, error) {
+	if len(field) == 0 {
+		return value, nil
+	}
+	if len(field) > 1 {
+		return "", fmt.Errorf("秘密 %s 只能指定一个字段", path)
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(value), &object); err != nil {
+		return "", fmt.Errorf("秘密 %s 的内容不是JSON对象，不能按字段引用", path)
+	}
+	raw, ok := object[field[0]]
+	if !ok {
+		return "", fmt.Errorf("秘密 %s 不包含字段 %s", path, field[0])
+	}
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return str, nil
+	}
+	// 非字符串字段按JSON原样输出
+	return string(raw), nil
+}
+
+// collectSecretRefs 遍历模板语法树，收集secret函数引用的路径
+// 路径和字段必须是字符串常量，保证渲染前就能确定引用了哪些秘密；
+// 模板没有输入数据，不支持range，避免构造死循环
+func collectSecretRefs(node parse.Node, refs map[string]struct{}) error {
+	switch n := node.(type) {
+	case nil:
+		return nil
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := collectSecretRefs(child, refs); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return collectSecretRefs(n.Pipe, refs)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			if err := collectSecretRefs(cmd, refs); err != nil {
+				return err
+			}
+		}
+	case *parse.CommandNode:
+		for i, arg := range n.Args {
+			if ident, ok := arg.(*parse.IdentifierNode); ok && ident.Ident == templateSecretFunc {
+				if i != 0 {
+					return errors.New(errors.CodeInvalidParam, "secret函数只能直接调用")
+				}
+				if err := collectSecretCall(n.Args[1:], refs); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := collectSecretRefs(arg, refs); err != nil {
+				return err
+			}
+		}
+	case *parse.ChainNode:
+		return collectSecretRefs(n.Node, refs)
+	case *parse.IfNode:
+		return collectBranchRefs(&n.BranchNode, refs)
+	case *parse.WithNode:
+		return collectBranchRefs(&n.BranchNode, refs)
+	case *parse.RangeNode:
+		return errors.New(errors.CodeInvalidParam, "模板不支持range")
+	case *parse.TemplateNode:
+		return collectSecretRefs(n.Pipe, refs)
+	}
+	return nil
+}
+
+// collectBranchRefs 收集if/with分支中的引用（所有分支都会检查，与实际执行哪个分支无关）
+func collectBranchRefs(n *parse.BranchNode, refs map[string]struct{}) error {
+	if err := collectSecretRefs(n.Pipe, refs); err != nil {
+		return err
+	}
+	if err := collectSecretRefs(n.List, refs); err != nil {
+		return err
+	}
+	return collectSecretRefs(n.ElseList, refs)
+}
+
+// collectSecretCall 校验secret函数的参数并记录路径
+func collectSecretCall(args []parse.Node, refs map[string]struct{}) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New(errors.CodeInvalidParam, `secret函数的用法为 {{ secret "路径" }} 或 {{ secret "路径" "字段" }}`)
+	}
+	for _, arg := range args {
+		if _, ok := arg.(*parse.StringNode); !ok {
+			return errors.New(errors.CodeInvalidParam, "secret函数的参数必须是字符串常量: "+arg.String())
+		}
+	}
+	refs[args[0].(*parse.StringNode).Text] = struct{}{}
+	return nil
+}
+
+// limitedBuffer 限制写入长度的缓冲区，超出时返回错误终止渲染
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+// Write 写入数据，超出长度限制时返回错误
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, fmt.Errorf("渲染结果超过%dKB", b.limit/1024)
+	}
+	return b.Buffer.Write(p)
+}
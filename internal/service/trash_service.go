@@ -0,0 +1,299 @@
+package service
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/config"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// 回收站清除相关常量
+const (
+	trashPurgeBatchSize = 500      // 定时清除每批删除的记录数
+	systemAuditUsername = "system" // 定时任务写审计日志时使用的操作者名称
+)
+
+// TrashService 秘密回收站服务
+// 秘密删除后进入回收站（软删除），可在保留期内恢复，超过保留期由定时任务彻底清除
+type TrashService struct {
+	db                *gorm.DB
+	encryptionService *EncryptionService
+	attachmentService *AttachmentService
+	configManager     *config.ConfigManager
+	auditService      *AuditService
+}
+
+// NewTrashService 创建回收站服务实例
+func NewTrashService(db *gorm.DB, encryptionService *EncryptionService, attachmentService *AttachmentService, configManager *config.ConfigManager, auditService *AuditService) *TrashService {
+	return &TrashService{
+		db:                db,
+		encryptionService: encryptionService,
+		attachmentService: attachmentService,
+		configManager:     configManager,
+		auditService:      auditService,
+	}
+}
+
+// retentionDays 读取回收站保留天数，配置无效时使用默认值
+func (s *TrashService) retentionDays() int {
+	value := s.configManager.GetWithDefault(models.ConfigKeySecretTrashRetentionDays, models.ConfigValueSecretTrashRetentionDaysDefault)
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		logger.Warn("回收站保留天数配置无效，使用默认值", logger.String("value", value))
+		days, _ = strconv.Atoi(models.ConfigValueSecretTrashRetentionDaysDefault)
+	}
+	return days
+}
+
+// TrashedSecret 回收站中的秘密
+type TrashedSecret struct {
+	models.SafeEncryptedSecret
+	DeletedAt time.Time  `json:"deleted_at"`         // 删除时间
+	PurgeAt   *time.Time `json:"purge_at,omitempty"` // 预计彻底清除时间（未开启自动清除时为空）
+}
+
+// ListTrashRequest 列出回收站请求
+type ListTrashRequest struct {
+	UserUUID string `form:"-"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// ListTrashResponse 列出回收站响应
+type ListTrashResponse struct {
+	Secrets       []*TrashedSecret `json:"secrets"`
+	Total         int64            `json:"total"`
+	Page          int              `json:"page"`
+	PageSize      int              `json:"page_size"`
+	RetentionDays int              `json:"retention_days"` // 当前保留天数（0表示不自动清除）
+}
+
+// ListTrash 列出当前用户回收站中的秘密
+func (s *TrashService) ListTrash(req *ListTrashRequest) (*ListTrashResponse, error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+
+	query := s.db.Unscoped().Model(&models.EncryptedSecret{}).
+		Where("user_uuid = ? AND deleted_at IS NOT NULL", req.UserUUID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error("获取回收站秘密总数失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	var secrets []models.EncryptedSecret
+	if err := query.Order("deleted_at DESC").
+		Offset((req.Page - 1) * req.PageSize).
+		Limit(req.PageSize).
+		Find(&secrets).Error; err != nil {
+		logger.Error("查询回收站秘密失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	retention := s.retentionDays()
+	items := make([]*TrashedSecret, len(secrets))
+	for i := range secrets {
+		item := &TrashedSecret{
+			SafeEncryptedSecret: *secrets[i].ToSafe(),
+			DeletedAt:           secrets[i].DeletedAt.Time,
+		}
+		if retention > 0 {
+			purgeAt := secrets[i].DeletedAt.Time.AddDate(0, 0, retention)
+			item.PurgeAt = &purgeAt
+		}
+		items[i] = item
+	}
+
+	return &ListTrashResponse{
+		Secrets:       items,
+		Total:         total,
+		Page:          req.Page,
+		PageSize:      req.PageSize,
+		RetentionDays: retention,
+	}, nil
+}
+
+// RestoreSecret 从回收站恢复秘密
+func (s *TrashService) RestoreSecret(userUUID, secretUUID string) (*models.SafeEncryptedSecret, error) {
+	var secret models.EncryptedSecret
+	err := s.db.Unscoped().
+		Where("user_uuid = ? AND secret_uuid = ? AND deleted_at IS NOT NULL", userUUID, secretUUID).
+		First(&secret).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Warn("回收站中不存在该秘密", logger.String("user_uuid", userUUID), logger.String("secret_uuid", secretUUID))
+			return nil, errors.New(errors.CodeResourceNotFound, "回收站中不存在该秘密")
+		}
+		logger.Error("查询回收站秘密失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	if err := s.db.Unscoped().Model(&secret).Update("deleted_at", nil).Error; err != nil {
+		logger.Error("恢复秘密失败", logger.Err(err), logger.String("secret_uuid", secretUUID))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	secret.DeletedAt = gorm.DeletedAt{}
+
+	logger.Info("从回收站恢复秘密成功", logger.String("user_uuid", userUUID), logger.String("secret_uuid", secretUUID))
+	return secret.ToSafe(), nil
+}
+
+// PurgeTrashRequest 立即清除回收站请求
+// 注意：UserUUID 由服务端从认证上下文中提取，不需要客户端传入
+type PurgeTrashRequest struct {
+	UserUUID    string   `json:"-"`                                                   // 不从请求体解析，由handler从上下文设置
+	SecurityPIN string   `json:"security_pin" binding:"required"`                     // 安全密码，彻底删除前需要再次确认身份
+	SecretUUIDs []string `json:"secret_uuids" binding:"omitempty,max=1000,dive,uuid"` // 要清除的秘密，为空时清空整个回收站
+}
+
+// PurgeTrashResponse 立即清除回收站响应
+type PurgeTrashResponse struct {
+	Purged int64 `json:"purged"` // 彻底删除的秘密数量
+}
+
+// PurgeTrash 立即彻底删除回收站中的秘密（不可恢复）
+func (s *TrashService) PurgeTrash(req *PurgeTrashRequest) (*PurgeTrashResponse, error) {
+	if _, err := s.encryptionService.verifySecurityPIN(req.UserUUID, req.SecurityPIN); err != nil {
+		return nil, err
+	}
+
+	query := s.db.Unscoped().Model(&models.EncryptedSecret{}).
+		Where("user_uuid = ? AND deleted_at IS NOT NULL", req.UserUUID)
+	if len(req.SecretUUIDs) > 0 {
+		query = query.Where("secret_uuid IN ?", req.SecretUUIDs)
+	}
+
+	var secretUUIDs []string
+	if err := query.Pluck("secret_uuid", &secretUUIDs).Error; err != nil {
+		logger.Error("查询回收站秘密失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if len(secretUUIDs) == 0 {
+		return &PurgeTrashResponse{}, nil
+	}
+
+	result := s.db.Unscoped().
+		Where("user_uuid = ? AND deleted_at IS NOT NULL AND secret_uuid IN ?", req.UserUUID, secretUUIDs).
+		Delete(&models.EncryptedSecret{})
+	if result.Error != nil {
+		logger.Error("彻底删除秘密失败", logger.Err(result.Error), logger.String("user_uuid", req.UserUUID))
+		return nil, errors.Wrap(errors.CodeDatabaseError, result.Error)
+	}
+	s.purgeAttachments(secretUUIDs)
+	s.purgeServiceAccountGrants(secretUUIDs)
+
+	logger.Info("立即清除回收站成功",
+		logger.String("user_uuid", req.UserUUID),
+		logger.Int("requested", len(req.SecretUUIDs)),
+		logger.Int64("purged", result.RowsAffected))
+
+	return &PurgeTrashResponse{Purged: result.RowsAffected}, nil
+}
+
+// PurgeExpired 彻底删除超过保留期的秘密（由定时任务调用）
+// 按批次删除避免长事务，每个受影响的用户写一条系统审计日志
+func (s *TrashService) PurgeExpired() (int64, error) {
+	retention := s.retentionDays()
+	if retention == 0 {
+		logger.Info("回收站自动清除已关闭，跳过")
+		return 0, nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -retention)
+
+	var total int64
+	purgedByUser := make(map[string]int64)
+	for {
+		var batch []models.EncryptedSecret
+		if err := s.db.Unscoped().Select("id", "user_uuid", "secret_uuid").
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Limit(trashPurgeBatchSize).
+			Find(&batch).Error; err != nil {
+			logger.Error("查询过期回收站秘密失败", logger.Err(err))
+			return total, errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		ids := make([]uint, len(batch))
+		secretUUIDs := make([]string, len(batch))
+		for i, secret := range batch {
+			ids[i] = secret.ID
+			secretUUIDs[i] = secret.SecretUUID
+		}
+		result := s.db.Unscoped().Where("id IN ?", ids).Delete(&models.EncryptedSecret{})
+		if result.Error != nil {
+			logger.Error("彻底删除过期秘密失败", logger.Err(result.Error))
+			return total, errors.Wrap(errors.CodeDatabaseError, result.Error)
+		}
+		s.purgeAttachments(secretUUIDs)
+		s.purgeServiceAccountGrants(secretUUIDs)
+
+		total += result.RowsAffected
+		for _, secret := range batch {
+			purgedByUser[secret.UserUUID]++
+		}
+		if len(batch) < trashPurgeBatchSize {
+			break
+		}
+	}
+
+	for userUUID, count := range purgedByUser {
+		s.logPurgeAudit(userUUID, count, retention)
+	}
+
+	logger.Info("回收站自动清除完成",
+		logger.Int("retention_days", retention),
+		logger.Int64("purged", total),
+		logger.Int("users", len(purgedByUser)))
+	return total, nil
+}
+
+// purgeAttachments 删除已彻底清除的秘密的附件，失败只记录日志（秘密已删除，附件无法再被访问）
+func (s *TrashService) purgeAttachments(secretUUIDs []string) {
+	if s.attachmentService == nil {
+		return
+	}
+	if _, err := s.attachmentService.DeleteBySecrets(secretUUIDs); err != nil {
+		logger.Error("删除已清除秘密的附件失败", logger.Err(err), logger.Int("secrets", len(secretUUIDs)))
+	}
+}
+
+// purgeServiceAccountGrants 删除已清除秘密的服务账户授权，失败只记录日志
+func (s *TrashService) purgeServiceAccountGrants(secretUUIDs []string) {
+	if err := deleteServiceAccountGrants(s.db, secretUUIDs); err != nil {
+		logger.Error("删除已清除秘密的服务账户授权失败", logger.Err(err), logger.Int("secrets", len(secretUUIDs)))
+	}
+}
+
+// logPurgeAudit 记录定时清除的审计日志
+// user_uuid记录为秘密所属用户，便于用户在自己的审计日志中看到清除记录；操作者名称为system
+func (s *TrashService) logPurgeAudit(userUUID string, count int64, retention int) {
+	if s.auditService == nil {
+		return
+	}
+	details, _ := json.Marshal(map[string]interface{}{
+		"trigger":        "retention",
+		"purged":         count,
+		"retention_days": retention,
+	})
+	s.auditService.LogAsync(&models.AuditLog{
+		UserUUID:     userUUID,
+		Username:     systemAuditUsername,
+		ActionType:   models.ActionDelete,
+		ResourceType: models.ResourceSecret,
+		Status:       models.AuditSuccess,
+		Details:      string(details),
+		CreatedAt:    time.Now().UTC(),
+	})
+}
@@ -4,18 +4,21 @@ import (
 	"github.com/cuihe500/vaulthub/internal/database/models"
 	"github.com/cuihe500/vaulthub/pkg/errors"
 	"github.com/cuihe500/vaulthub/pkg/logger"
+	redisClient "github.com/cuihe500/vaulthub/pkg/redis"
 	"gorm.io/gorm"
 )
 
 // UserService 用户服务
 type UserService struct {
-	db *gorm.DB
+	db    *gorm.DB
+	redis *redisClient.Client
 }
 
 // NewUserService 创建用户服务实例
-func NewUserService(db *gorm.DB) *UserService {
+func NewUserService(db *gorm.DB, redis *redisClient.Client) *UserService {
 	return &UserService{
-		db: db,
+		db:    db,
+		redis: redis,
 	}
 }
 
@@ -120,7 +123,7 @@ type UpdateUserStatusRequest struct {
 	Status models.UserStatus `json:"status" binding:"required,min=1,max=3"`
 }
 
-// UpdateUserStatus 更新用户状态
+// UpdateUserStatus 更新用户状态，状态变化时用户已签发的令牌和登录会话全部失效
 func (s *UserService) UpdateUserStatus(userUUID string, req *UpdateUserStatusRequest) (*models.SafeUser, error) {
 	var user models.User
 	if err := s.db.Where("uuid = ?", userUUID).First(&user).Error; err != nil {
@@ -131,11 +134,13 @@ func (s *UserService) UpdateUserStatus(userUUID string, req *UpdateUserStatusReq
 		return nil, errors.Wrap(errors.CodeDatabaseError, err)
 	}
 
-	// 更新状态
+	// 更新状态，同时清除自动锁定的解锁时间（管理员设置的锁定只能由管理员解锁）
+	changed := user.Status != req.Status
 	user.Status = req.Status
-	if err := s.db.Save(&user).Error; err != nil {
+	user.LockedUntil = nil
+	if err := s.saveUser(&user, changed); err != nil {
 		logger.Error("更新用户状态失败", logger.String("uuid", userUUID), logger.Err(err))
-		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+		return nil, err
 	}
 
 	logger.Info("用户状态已更新", logger.String("uuid", userUUID), logger.Int("status", int(req.Status)))
@@ -148,7 +153,7 @@ type UpdateUserRoleRequest struct {
 	Role string `json:"role" binding:"required,oneof=admin user readonly"`
 }
 
-// UpdateUserRole 更新用户角色
+// UpdateUserRole 更新用户角色，角色变化时用户已签发的令牌和登录会话全部失效
 func (s *UserService) UpdateUserRole(userUUID string, req *UpdateUserRoleRequest) (*models.SafeUser, error) {
 	var user models.User
 	if err := s.db.Where("uuid = ?", userUUID).First(&user).Error; err != nil {
@@ -160,13 +165,42 @@ func (s *UserService) UpdateUserRole(userUUID string, req *UpdateUserRoleRequest
 	}
 
 	// 更新角色
+	changed := user.Role != req.Role
 	user.Role = req.Role
-	if err := s.db.Save(&user).Error; err != nil {
+	if err := s.saveUser(&user, changed); err != nil {
 		logger.Error("更新用户角色失败", logger.String("uuid", userUUID), logger.Err(err))
-		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+		return nil, err
 	}
 
 	logger.Info("用户角色已更新", logger.String("uuid", userUUID), logger.String("role", req.Role))
 
 	return user.ToSafeUser(), nil
 }
+
+// saveUser 保存用户，revokeTokens为true时在同一事务中递增令牌版本，提交后删除用户的全部会话
+func (s *UserService) saveUser(user *models.User, revokeTokens bool) error {
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(user).Error; err != nil {
+			return errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		if !revokeTokens {
+			return nil
+		}
+		if err := bumpTokenEpoch(tx, user.UUID); err != nil {
+			return errors.Wrap(errors.CodeDatabaseError, err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if !revokeTokens {
+		return nil
+	}
+
+	// 令牌版本已递增，旧令牌和会话都会被拒绝，删除会话失败不影响结果
+	if err := revokeAllSessions(s.db, s.redis, user.UUID); err != nil {
+		logger.Warn("删除用户会话失败", logger.String("uuid", user.UUID), logger.Err(err))
+	}
+	logger.Info("用户令牌已全部失效", logger.String("uuid", user.UUID))
+	return nil
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/config"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// VaultHealthService 保险库健康检查服务
+// 服务端不保存也不解密明文：密码类秘密在写入时计算HMAC指纹（密钥由DEK派生）和强度评分，
+// 健康报告只基于这些字段和访问统计生成
+type VaultHealthService struct {
+	db                *gorm.DB
+	encryptionService *EncryptionService
+	configManager     *config.ConfigManager
+}
+
+// NewVaultHealthService 创建保险库健康检查服务实例
+func NewVaultHealthService(db *gorm.DB, encryptionService *EncryptionService, configManager *config.ConfigManager) *VaultHealthService {
+	return &VaultHealthService{
+		db:                db,
+		encryptionService: encryptionService,
+		configManager:     configManager,
+	}
+}
+
+// passwordFingerprint 计算密码类秘密的指纹，其他类型的秘密返回nil
+func passwordFingerprint(secretType models.SecretType, plaintext, dek []byte) (*string, error) {
+	if secretType != models.SecretTypePassword {
+		return nil, nil
+	}
+
+	fingerprint, err := crypto.PasswordFingerprint(dek, plaintext)
+	if err != nil {
+		logger.Error("计算密码指纹失败", logger.Err(err))
+		return nil, err
+	}
+	return &fingerprint, nil
+}
+
+// passwordHealth 计算密码类秘密的指纹和强度评分，其他类型的秘密返回nil
+// 配置了泄露密码数据时同时检查密码是否已泄露，已泄露的密码评分记为0
+func (s *EncryptionService) passwordHealth(secretType models.SecretType, plaintext, dek []byte) (*string, *models.PasswordStrength, error) {
+	fingerprint, err := passwordFingerprint(secretType, plaintext, dek)
+	if err != nil || fingerprint == nil {
+		return nil, nil, err
+	}
+
+	result := crypto.EstimatePasswordStrength(string(plaintext))
+	strength := &models.PasswordStrength{Score: result.Score, Entropy: result.Entropy}
+	if isBreachedPassword(s.breachChecker, string(plaintext)) {
+		strength.Score = 0
+		strength.Breached = true
+	}
+	return fingerprint, strength, nil
+}
+
+// withStrength 返回设置了强度评分的元数据副本
+// strength为nil时清除客户端传入的评分，强度只能由服务端计算
+func withStrength(metadata *models.SecretMetadata, strength *models.PasswordStrength) *models.SecretMetadata {
+	if metadata == nil {
+		if strength == nil {
+			return nil
+		}
+		return &models.SecretMetadata{Strength: strength}
+	}
+	copied := *metadata
+	copied.Strength = strength
+	return &copied
+}
+
+// VaultHealthRequest 保险库健康报告请求
+type VaultHealthRequest struct {
+	UserUUID  string `form:"-"`
+	StaleDays int    `form:"stale_days" binding:"omitempty,min=1,max=3650"` // 超过多少天未变更视为过旧，默认取系统配置
+}
+
+// VaultHealthSecret 健康报告中的秘密条目（不包含秘密内容）
+type VaultHealthSecret struct {
+	SecretUUID     string                   `json:"secret_uuid"`
+	SecretName     string                   `json:"secret_name"`
+	SecretType     models.SecretType        `json:"secret_type"`
+	Folder         string                   `json:"folder,omitempty"`
+	Strength       *models.PasswordStrength `json:"strength,omitempty"`
+	AccessCount    int64                    `json:"access_count"`
+	LastAccessedAt *time.Time               `json:"last_accessed_at,omitempty"`
+	ValueChangedAt *time.Time               `json:"value_changed_at,omitempty"`
+	CreatedAt      time.Time                `json:"created_at"`
+}
+
+// ReusedPasswordGroup 使用同一密码的一组秘密
+type ReusedPasswordGroup struct {
+	Count   int                  `json:"count"`
+	Secrets []*VaultHealthSecret `json:"secrets"`
+}
+
+// VaultHealthSummary 健康报告汇总
+type VaultHealthSummary struct {
+	TotalSecrets    int `json:"total_secrets"`    // 秘密总数
+	PasswordSecrets int `json:"password_secrets"` // 密码类秘密数
+	Reused          int `json:"reused"`           // 与其他秘密重复的密码数
+	Weak            int `json:"weak"`             // 弱密码数
+	Breached        int `json:"breached"`         // 出现在泄露数据集中的密码数
+	Stale           int `json:"stale"`            // 过旧的秘密数
+	NeverAccessed   int `json:"never_accessed"`   // 从未访问的秘密数
+	Unchecked       int `json:"unchecked"`        // 尚未计算指纹的密码数（需要重新扫描）
+}
+
+// VaultHealthReport 保险库健康报告
+type VaultHealthReport struct {
+	Summary       VaultHealthSummary     `json:"summary"`
+	StaleDays     int                    `json:"stale_days"`
+	Reused        []*ReusedPasswordGroup `json:"reused"`
+	Weak          []*VaultHealthSecret   `json:"weak"`
+	Breached      []*VaultHealthSecret   `json:"breached"`
+	Stale         []*VaultHealthSecret   `json:"stale"`
+	NeverAccessed []*VaultHealthSecret   `json:"never_accessed"`
+	Unchecked     []*VaultHealthSecret   `json:"unchecked"`
+	GeneratedAt   time.Time              `json:"generated_at"`
+}
+
+// GetReport 生成保险库健康报告
+// 列出重复密码、弱密码、已泄露密码、长期未变更和从未访问的秘密，不需要安全密码
+func (s *VaultHealthService) GetReport(req *VaultHealthRequest) (*VaultHealthReport, error) {
+	staleDays := req.StaleDays
+	if staleDays == 0 {
+		staleDays = s.defaultStaleDays()
+	}
+
+	var secrets []models.EncryptedSecret
+	if err := s.db.Select("secret_uuid", "secret_name", "secret_type", "metadata", "password_fingerprint",
+		"value_changed_at", "last_accessed_at", "access_count", "created_at").
+		Where("user_uuid = ?", req.UserUUID).
+		Order("secret_name ASC").
+		Find(&secrets).Error; err != nil {
+		logger.Error("查询秘密失败", logger.Err(err), logger.String("user_uuid", req.UserUUID))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	now := time.Now()
+	staleBefore := now.AddDate(0, 0, -staleDays)
+	report := &VaultHealthReport{
+		StaleDays:     staleDays,
+		Reused:        []*ReusedPasswordGroup{},
+		Weak:          []*VaultHealthSecret{},
+		Breached:      []*VaultHealthSecret{},
+		Stale:         []*VaultHealthSecret{},
+		NeverAccessed: []*VaultHealthSecret{},
+		Unchecked:     []*VaultHealthSecret{},
+		GeneratedAt:   now,
+	}
+	report.Summary.TotalSecrets = len(secrets)
+
+	groups := make(map[string]*ReusedPasswordGroup)
+	for i := range secrets {
+		secret := &secrets[i]
+		item := toHealthSecret(secret)
+
+		if secret.SecretType == models.SecretTypePassword {
+			report.Summary.PasswordSecrets++
+			if secret.PasswordFingerprint == nil {
+				report.Unchecked = append(report.Unchecked, item)
+			} else {
+				group, ok := groups[*secret.PasswordFingerprint]
+				if !ok {
+					group = &ReusedPasswordGroup{}
+					groups[*secret.PasswordFingerprint] = group
+				}
+				group.Secrets = append(group.Secrets, item)
+			}
+			if item.Strength != nil && item.Strength.Score < crypto.WeakStrengthScore {
+				report.Weak = append(report.Weak, item)
+			}
+			if item.Strength != nil && item.Strength.Breached {
+				report.Breached = append(report.Breached, item)
+			}
+		}
+
+		changedAt := secret.CreatedAt
+		if secret.ValueChangedAt != nil {
+			changedAt = *secret.ValueChangedAt
+		}
+		if changedAt.Before(staleBefore) {
+			report.Stale = append(report.Stale, item)
+		}
+
+		if secret.AccessCount == 0 || secret.LastAccessedAt == nil {
+			report.NeverAccessed = append(report.NeverAccessed, item)
+		}
+	}
+
+	for _, group := range groups {
+		if len(group.Secrets) < 2 {
+			continue
+		}
+		group.Count = len(group.Secrets)
+		report.Reused = append(report.Reused, group)
+		report.Summary.Reused += group.Count
+	}
+	// 重复次数多的排在前面，次数相同时按第一个秘密的名称排序，保证结果稳定
+	sort.Slice(report.Reused, func(i, j int) bool {
+		if report.Reused[i].Count != report.Reused[j].Count {
+			return report.Reused[i].Count > report.Reused[j].Count
+		}
+		return report.Reused[i].Secrets[0].SecretName < report.Reused[j].Secrets[0].SecretName
+	})
+
+	report.Summary.Weak = len(report.Weak)
+	report.Summary.Breached = len(report.Breached)
+	report.Summary.Stale = len(report.Stale)
+	report.Summary.NeverAccessed = len(report.NeverAccessed)
+	report.Summary.Unchecked = len(report.Unchecked)
+	return report, nil
+}
+
+// RescanRequest 重新扫描密码请求
+type RescanRequest struct {
+	UserUUID    string `json:"-"`
+	SecurityPIN string `json:"security_pin" binding:"required"` // 安全密码，用于解密DEK
+}
+
+// RescanResponse 重新扫描密码响应
+type RescanResponse struct {
+	Scanned int `json:"scanned"` // 成功计算指纹和强度的密码数
+	Failed  int `json:"failed"`  // 解密失败或密钥版本不匹配的密码数
+}
+
+// Rescan 重新计算所有密码类秘密的指纹和强度评分
+// 用于功能上线前创建的秘密。明文只在内存中用于计算，不返回给调用方，
+// 因此需要检出或审批的秘密同样会被扫描，也不计入访问统计
+func (s *VaultHealthService) Rescan(req *RescanRequest) (*RescanResponse, error) {
+	userKey, dek, err := s.encryptionService.unlockDEK(req.UserUUID, req.SecurityPIN)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ClearBytes(dek)
+
+	var secrets []models.EncryptedSecret
+	if err := s.db.Where("user_uuid = ? AND secret_type = ?", req.UserUUID, models.SecretTypePassword).
+		Find(&secrets).Error; err != nil {
+		logger.Error("查询密码类秘密失败", logger.Err(err), logger.String("user_uuid", req.UserUUID))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	resp := &RescanResponse{}
+	for i := range secrets {
+		secret := &secrets[i]
+		if secret.DEKVersion != userKey.DEKVersion {
+			// 密钥轮换尚未迁移到该秘密，迁移时会重新计算指纹
+			resp.Failed++
+			continue
+		}
+
+		plainData, err := crypto.DecryptAESGCM(secret.EncryptedData, dek, secret.Nonce, secret.AuthTag)
+		if err != nil {
+			logger.Error("解密秘密数据失败", logger.Err(err), logger.String("secret_uuid", secret.SecretUUID))
+			resp.Failed++
+			continue
+		}
+		fingerprint, strength, err := s.encryptionService.passwordHealth(secret.SecretType, plainData, dek)
+		crypto.ClearBytes(plainData)
+		if err != nil {
+			resp.Failed++
+			continue
+		}
+
+		// 使用UpdateColumns，扫描不算秘密变更，不更新updated_at
+		if err := s.db.Model(secret).UpdateColumns(map[string]interface{}{
+			"password_fingerprint": fingerprint,
+			"metadata":             withStrength(secret.Metadata, strength),
+		}).Error; err != nil {
+			logger.Error("保存密码指纹失败", logger.Err(err), logger.String("secret_uuid", secret.SecretUUID))
+			resp.Failed++
+			continue
+		}
+		resp.Scanned++
+	}
+
+	logger.Info("重新扫描密码完成",
+		logger.String("user_uuid", req.UserUUID),
+		logger.Int("scanned", resp.Scanned),
+		logger.Int("failed", resp.Failed))
+	return resp, nil
+}
+
+// toHealthSecret 转换为健康报告条目
+func toHealthSecret(secret *models.EncryptedSecret) *VaultHealthSecret {
+	item := &VaultHealthSecret{
+		SecretUUID:     secret.SecretUUID,
+		SecretName:     secret.SecretName,
+		SecretType:     secret.SecretType,
+		AccessCount:    secret.AccessCount,
+		LastAccessedAt: secret.LastAccessedAt,
+		ValueChangedAt: secret.ValueChangedAt,
+		CreatedAt:      secret.CreatedAt,
+	}
+	if secret.Metadata != nil {
+		item.Folder = secret.Metadata.Folder
+		item.Strength = secret.Metadata.Strength
+	}
+	return item
+}
+
+// defaultStaleDays 读取过旧天数配置，配置无效时使用默认值
+func (s *VaultHealthService) defaultStaleDays() int {
+	value := s.configManager.GetWithDefault(models.ConfigKeyVaultHealthStaleDays, models.ConfigValueVaultHealthStaleDaysDefault)
+	days, err := strconv.Atoi(value)
+	if err != nil || days <= 0 {
+		logger.Warn("过旧天数配置无效，使用默认值", logger.String("value", value))
+		days, _ = strconv.Atoi(models.ConfigValueVaultHealthStaleDaysDefault)
+	}
+	return days
+}
@@ -0,0 +1,615 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/cuihe500/vaulthub/internal/config"
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/logger"
+	redisClient "github.com/cuihe500/vaulthub/pkg/redis"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 通行密钥相关常量
+const (
+	webAuthnRegistrationPrefix = "webauthn_registration:"
+	webAuthnLoginPrefix        = "webauthn_login:"
+	webAuthnStepUpPrefix       = "webauthn_step_up:"
+	webAuthnSessionTTL         = 5 * time.Minute
+	webAuthnLoginTokenSize     = 32
+	webAuthnMaxCredentials     = 10 // 每个用户最多注册的通行密钥数量
+	stepUpPrefix               = "step_up:"
+	stepUpTTL                  = 10 * time.Minute // 一次二次验证的有效期
+)
+
+// takeSessionScript 读取并删除仪式会话，保证每个挑战只能使用一次；不存在时返回空字符串
+const takeSessionScript = `
+local value = redis.call('GET', KEYS[1])
+if not value then
+  return ''
+end
+redis.call('DEL', KEYS[1])
+return value
+`
+
+// WebAuthnService 通行密钥（WebAuthn）服务
+// 负责通行密钥的注册和管理、无密码登录，以及访问秘密前的二次验证（step-up）
+type WebAuthnService struct {
+	db            *gorm.DB
+	redis         *redisClient.Client
+	configManager *config.ConfigManager
+	webAuthn      *webauthn.WebAuthn
+}
+
+// NewWebAuthnService 创建通行密钥服务实例
+func NewWebAuthnService(db *gorm.DB, redis *redisClient.Client, configManager *config.ConfigManager, webAuthn *webauthn.WebAuthn) *WebAuthnService {
+	return &WebAuthnService{
+		db:            db,
+		redis:         redis,
+		configManager: configManager,
+		webAuthn:      webAuthn,
+	}
+}
+
+// StepUpKey 返回二次验证状态在Redis中的key，与访问令牌绑定（只保存令牌的哈希）
+// 认证中间件据此判断当前会话是否已完成二次验证
+func StepUpKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return stepUpPrefix + hex.EncodeToString(sum[:])
+}
+
+// makeWebAuthnLoginKey 生成无密码登录会话在Redis中的key（只保存会话令牌的哈希）
+func makeWebAuthnLoginKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return webAuthnLoginPrefix + hex.EncodeToString(sum[:])
+}
+
+// webAuthnUser 适配 webauthn.User 接口
+// 用户句柄使用用户UUID，不包含用户名等可识别信息
+type webAuthnUser struct {
+	user        *models.User
+	credentials []models.WebAuthnCredential
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte          { return []byte(u.user.UUID) }
+func (u *webAuthnUser) WebAuthnName() string        { return u.user.Username }
+func (u *webAuthnUser) WebAuthnDisplayName() string { return u.user.Username }
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, len(u.credentials))
+	for i := range u.credentials {
+		credentials[i] = toWebAuthnCredential(&u.credentials[i])
+	}
+	return credentials
+}
+
+// findCredential 按凭证ID查找已注册的凭证
+func (u *webAuthnUser) findCredential(id []byte) *models.WebAuthnCredential {
+	for i := range u.credentials {
+		if bytes.Equal(u.credentials[i].CredentialID, id) {
+			return &u.credentials[i]
+		}
+	}
+	return nil
+}
+
+// toWebAuthnCredential 将数据库记录转换为WebAuthn凭证
+func toWebAuthnCredential(c *models.WebAuthnCredential) webauthn.Credential {
+	var transports []protocol.AuthenticatorTransport
+	for _, t := range strings.Split(c.Transports, ",") {
+		if t != "" {
+			transports = append(transports, protocol.AuthenticatorTransport(t))
+		}
+	}
+	return webauthn.Credential{
+		ID:              c.CredentialID,
+		PublicKey:       c.PublicKey,
+		AttestationType: c.AttestationType,
+		Transport:       transports,
+		Flags: webauthn.CredentialFlags{
+			UserPresent:    true,
+			UserVerified:   true,
+			BackupEligible: c.BackupEligible,
+			BackupState:    c.BackupState,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:    c.AAGUID,
+			SignCount: c.SignCount,
+		},
+	}
+}
+
+// IsStepUpRequired 判断角色访问秘密前是否必须完成通行密钥二次验证
+func (s *WebAuthnService) IsStepUpRequired(role string) bool {
+	return roleListed(s.configManager.GetWithDefault(models.ConfigKeyWebAuthnStepUpRoles, ""), role)
+}
+
+// IsSteppedUp 判断访问令牌对应的会话是否已完成二次验证且仍在有效期内
+func (s *WebAuthnService) IsSteppedUp(token string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	n, err := s.redis.Exists(ctx, StepUpKey(token))
+	if err != nil {
+		logger.Error("查询二次验证状态失败", logger.Err(err))
+		return false
+	}
+	return n > 0
+}
+
+// MarkSteppedUp 将访问令牌对应的会话标记为已完成二次验证，返回过期时间
+func (s *WebAuthnService) MarkSteppedUp(userUUID, token string) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err := s.redis.Set(ctx, StepUpKey(token), userUUID, stepUpTTL); err != nil {
+		logger.Error("保存二次验证状态失败", logger.String("user_uuid", userUUID), logger.Err(err))
+		return time.Time{}, errors.Wrap(errors.CodeCacheError, err)
+	}
+	return time.Now().Add(stepUpTTL), nil
+}
+
+// loadUser 查询用户及其已注册的通行密钥
+func (s *WebAuthnService) loadUser(user *models.User) (*webAuthnUser, error) {
+	var credentials []models.WebAuthnCredential
+	if err := s.db.Where("user_uuid = ?", user.UUID).Order("id ASC").Find(&credentials).Error; err != nil {
+		logger.Error("查询通行密钥失败", logger.String("user_uuid", user.UUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return &webAuthnUser{user: user, credentials: credentials}, nil
+}
+
+// WebAuthnCredentialListResponse 通行密钥列表
+type WebAuthnCredentialListResponse struct {
+	Credentials    []models.WebAuthnCredential `json:"credentials"`
+	StepUpRequired bool                        `json:"step_up_required"` // 当前角色访问秘密前是否必须二次验证
+}
+
+// ListCredentials 查询用户已注册的通行密钥
+func (s *WebAuthnService) ListCredentials(user *models.User) (*WebAuthnCredentialListResponse, error) {
+	waUser, err := s.loadUser(user)
+	if err != nil {
+		return nil, err
+	}
+	return &WebAuthnCredentialListResponse{
+		Credentials:    waUser.credentials,
+		StepUpRequired: s.IsStepUpRequired(user.Role),
+	}, nil
+}
+
+// WebAuthnCredentialNameRequest 通行密钥名称请求（注册和重命名）
+type WebAuthnCredentialNameRequest struct {
+	Name string `json:"name" binding:"required,max=64"`
+}
+
+// WebAuthnCredentialRequest 提交认证器响应的请求
+// Credential为浏览器 navigator.credentials.create()/get() 返回的 PublicKeyCredential 的JSON序列化结果
+type WebAuthnCredentialRequest struct {
+	Credential json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+}
+
+// WebAuthnRegistrationResponse 开始注册通行密钥的响应
+type WebAuthnRegistrationResponse struct {
+	Options *protocol.CredentialCreation `json:"options" swaggertype:"object"` // 传给 navigator.credentials.create() 的参数
+}
+
+// webAuthnRegistrationSession 注册仪式会话，与用户绑定
+type webAuthnRegistrationSession struct {
+	Name    string               `json:"name"`
+	Session webauthn.SessionData `json:"session"`
+}
+
+// BeginRegistration 开始注册通行密钥
+// 已有通行密钥时，必须先在当前会话完成二次验证，避免仅凭密码就能添加新的通行密钥
+func (s *WebAuthnService) BeginRegistration(user *models.User, token, name string) (*WebAuthnRegistrationResponse, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New(errors.CodeInvalidParam, "通行密钥名称不能为空")
+	}
+
+	waUser, err := s.loadUser(user)
+	if err != nil {
+		return nil, err
+	}
+	if len(waUser.credentials) >= webAuthnMaxCredentials {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "通行密钥数量已达上限")
+	}
+	if len(waUser.credentials) > 0 && !s.IsSteppedUp(token) {
+		return nil, errors.New(errors.CodeStepUpRequired, "添加新的通行密钥前需要先用已有通行密钥完成二次验证")
+	}
+	for _, c := range waUser.credentials {
+		if c.Name == name {
+			return nil, errors.New(errors.CodeResourceAlreadyExists, "通行密钥名称已存在")
+		}
+	}
+
+	// 排除已注册的凭证，避免同一个认证器重复注册
+	exclusions := webauthn.Credentials(waUser.WebAuthnCredentials()).CredentialDescriptors()
+	creation, session, err := s.webAuthn.BeginRegistration(waUser, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		logger.Error("生成通行密钥注册参数失败", logger.String("user_uuid", user.UUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeInternalError, err)
+	}
+
+	data, err := json.Marshal(&webAuthnRegistrationSession{Name: name, Session: *session})
+	if err != nil {
+		return nil, errors.Wrap(errors.CodeInternalError, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := s.redis.Set(ctx, webAuthnRegistrationPrefix+user.UUID, data, webAuthnSessionTTL); err != nil {
+		logger.Error("保存通行密钥注册会话失败", logger.String("user_uuid", user.UUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCacheError, err)
+	}
+
+	return &WebAuthnRegistrationResponse{Options: creation}, nil
+}
+
+// FinishRegistration 校验认证器的注册响应并保存通行密钥
+func (s *WebAuthnService) FinishRegistration(user *models.User, credential []byte) (*models.WebAuthnCredential, error) {
+	var reg webAuthnRegistrationSession
+	if err := s.takeSession(webAuthnRegistrationPrefix+user.UUID, &reg); err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(credential)
+	if err != nil {
+		logger.Warn("解析通行密钥注册响应失败", logger.String("user_uuid", user.UUID), logger.Err(err))
+		return nil, errors.New(errors.CodeInvalidFormat, "通行密钥注册响应格式无效")
+	}
+
+	waUser, err := s.loadUser(user)
+	if err != nil {
+		return nil, err
+	}
+	created, err := s.webAuthn.CreateCredential(waUser, reg.Session, parsed)
+	if err != nil {
+		logger.Warn("通行密钥注册校验失败", logger.String("user_uuid", user.UUID), logger.Err(err))
+		return nil, errors.New(errors.CodeWebAuthnFailed, "")
+	}
+
+	var count int64
+	if err := s.db.Model(&models.WebAuthnCredential{}).Where("credential_id = ?", created.ID).Count(&count).Error; err != nil {
+		logger.Error("查询通行密钥失败", logger.String("user_uuid", user.UUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if count > 0 {
+		return nil, errors.New(errors.CodeResourceAlreadyExists, "该通行密钥已注册")
+	}
+
+	record := newWebAuthnCredentialRecord(user.UUID, reg.Name, created)
+	if err := s.db.Create(record).Error; err != nil {
+		logger.Error("保存通行密钥失败", logger.String("user_uuid", user.UUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	logger.Info("注册通行密钥成功",
+		logger.String("user_uuid", user.UUID),
+		logger.String("credential_uuid", record.UUID))
+	return record, nil
+}
+
+// newWebAuthnCredentialRecord 将注册通过的凭证转换为数据库记录，与 toWebAuthnCredential 互逆
+func newWebAuthnCredentialRecord(userUUID, name string, created *webauthn.Credential) *models.WebAuthnCredential {
+	transports := make([]string, len(created.Transport))
+	for i, t := range created.Transport {
+		transports[i] = string(t)
+	}
+	return &models.WebAuthnCredential{
+		UUID:            uuid.New().String(),
+		UserUUID:        userUUID,
+		Name:            name,
+		CredentialID:    created.ID,
+		PublicKey:       created.PublicKey,
+		AttestationType: created.AttestationType,
+		Transports:      strings.Join(transports, ","),
+		AAGUID:          created.Authenticator.AAGUID,
+		SignCount:       created.Authenticator.SignCount,
+		BackupEligible:  created.Flags.BackupEligible,
+		BackupState:     created.Flags.BackupState,
+	}
+}
+
+// getCredential 查询用户的单个通行密钥
+func (s *WebAuthnService) getCredential(userUUID, credentialUUID string) (*models.WebAuthnCredential, error) {
+	var credential models.WebAuthnCredential
+	err := s.db.Where("uuid = ? AND user_uuid = ?", credentialUUID, userUUID).First(&credential).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.New(errors.CodeResourceNotFound, "通行密钥不存在")
+	}
+	if err != nil {
+		logger.Error("查询通行密钥失败", logger.String("credential_uuid", credentialUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return &credential, nil
+}
+
+// RenameCredential 重命名通行密钥
+func (s *WebAuthnService) RenameCredential(userUUID, credentialUUID, name string) (*models.WebAuthnCredential, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New(errors.CodeInvalidParam, "通行密钥名称不能为空")
+	}
+
+	credential, err := s.getCredential(userUUID, credentialUUID)
+	if err != nil {
+		return nil, err
+	}
+	if credential.Name == name {
+		return credential, nil
+	}
+
+	var count int64
+	if err := s.db.Model(&models.WebAuthnCredential{}).
+		Where("user_uuid = ? AND name = ?", userUUID, name).
+		Count(&count).Error; err != nil {
+		logger.Error("查询通行密钥失败", logger.String("user_uuid", userUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	if count > 0 {
+		return nil, errors.New(errors.CodeResourceAlreadyExists, "通行密钥名称已存在")
+	}
+
+	if err := s.db.Model(credential).Update("name", name).Error; err != nil {
+		logger.Error("重命名通行密钥失败", logger.String("credential_uuid", credentialUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+	return credential, nil
+}
+
+// DeleteCredential 删除通行密钥，需要当前会话已完成二次验证
+// 物理删除，避免软删除记录占用凭证ID的唯一索引
+func (s *WebAuthnService) DeleteCredential(userUUID, token, credentialUUID string) (*models.WebAuthnCredential, error) {
+	credential, err := s.getCredential(userUUID, credentialUUID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.IsSteppedUp(token) {
+		return nil, errors.New(errors.CodeStepUpRequired, "删除通行密钥前需要先完成二次验证")
+	}
+
+	if err := s.db.Unscoped().Delete(credential).Error; err != nil {
+		logger.Error("删除通行密钥失败", logger.String("credential_uuid", credentialUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	logger.Info("删除通行密钥成功",
+		logger.String("user_uuid", userUUID),
+		logger.String("credential_uuid", credentialUUID))
+	return credential, nil
+}
+
+// Reset 管理员删除用户的全部通行密钥（用户丢失所有认证器时使用）
+func (s *WebAuthnService) Reset(userUUID string) (*models.SafeUser, error) {
+	var user models.User
+	if err := s.db.Where("uuid = ?", userUUID).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New(errors.CodeResourceNotFound, "用户不存在")
+		}
+		logger.Error("查询用户失败", logger.String("uuid", userUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	if err := s.db.Unscoped().Where("user_uuid = ?", userUUID).Delete(&models.WebAuthnCredential{}).Error; err != nil {
+		logger.Error("删除通行密钥失败", logger.String("user_uuid", userUUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeDatabaseError, err)
+	}
+
+	logger.Info("管理员重置通行密钥", logger.String("user_uuid", userUUID))
+	return user.ToSafeUser(), nil
+}
+
+// WebAuthnAssertionResponse 开始通行密钥验证的响应
+type WebAuthnAssertionResponse struct {
+	SessionToken string                        `json:"session_token,omitempty"`      // 无密码登录时提交验证结果需要携带，5分钟内有效
+	Options      *protocol.CredentialAssertion `json:"options" swaggertype:"object"` // 传给 navigator.credentials.get() 的参数
+}
+
+// WebAuthnLoginRequest 通行密钥登录请求
+type WebAuthnLoginRequest struct {
+	SessionToken string          `json:"session_token" binding:"required"`
+	Credential   json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+	SessionClient
+}
+
+// BeginLogin 开始无密码登录，不需要用户名，由认证器选择可发现凭证
+func (s *WebAuthnService) BeginLogin() (*WebAuthnAssertionResponse, error) {
+	assertion, session, err := s.webAuthn.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		logger.Error("生成通行密钥登录参数失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeInternalError, err)
+	}
+
+	tokenBytes, err := crypto.GenerateRandomBytes(webAuthnLoginTokenSize)
+	if err != nil {
+		logger.Error("生成通行密钥登录会话令牌失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCryptoError, err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return nil, errors.Wrap(errors.CodeInternalError, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := s.redis.Set(ctx, makeWebAuthnLoginKey(token), data, webAuthnSessionTTL); err != nil {
+		logger.Error("保存通行密钥登录会话失败", logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCacheError, err)
+	}
+
+	return &WebAuthnAssertionResponse{SessionToken: token, Options: assertion}, nil
+}
+
+// FinishLogin 校验无密码登录的认证器响应，返回通过验证的用户UUID
+// 用户状态由调用方检查
+func (s *WebAuthnService) FinishLogin(sessionToken string, credential []byte) (string, error) {
+	var session webauthn.SessionData
+	if err := s.takeSession(makeWebAuthnLoginKey(sessionToken), &session); err != nil {
+		return "", err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(credential)
+	if err != nil {
+		logger.Warn("解析通行密钥登录响应失败", logger.Err(err))
+		return "", errors.New(errors.CodeInvalidFormat, "通行密钥验证响应格式无效")
+	}
+
+	var waUser *webAuthnUser
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		var user models.User
+		if err := s.db.Where("uuid = ?", string(userHandle)).First(&user).Error; err != nil {
+			return nil, err
+		}
+		u, err := s.loadUser(&user)
+		if err != nil {
+			return nil, err
+		}
+		waUser = u
+		return u, nil
+	}
+
+	_, validated, err := s.webAuthn.ValidatePasskeyLogin(handler, session, parsed)
+	if err != nil {
+		logger.Warn("通行密钥登录校验失败", logger.Err(err))
+		return "", errors.New(errors.CodeWebAuthnFailed, "")
+	}
+	if err := s.recordUse(waUser, validated); err != nil {
+		return "", err
+	}
+	return waUser.user.UUID, nil
+}
+
+// BeginStepUp 开始二次验证，只允许使用当前用户已注册的通行密钥
+func (s *WebAuthnService) BeginStepUp(user *models.User) (*WebAuthnAssertionResponse, error) {
+	waUser, err := s.loadUser(user)
+	if err != nil {
+		return nil, err
+	}
+	if len(waUser.credentials) == 0 {
+		return nil, errors.New(errors.CodeOperationNotAllowed, "请先注册通行密钥")
+	}
+
+	assertion, session, err := s.webAuthn.BeginLogin(waUser, webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		logger.Error("生成二次验证参数失败", logger.String("user_uuid", user.UUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeInternalError, err)
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return nil, errors.Wrap(errors.CodeInternalError, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := s.redis.Set(ctx, webAuthnStepUpPrefix+user.UUID, data, webAuthnSessionTTL); err != nil {
+		logger.Error("保存二次验证会话失败", logger.String("user_uuid", user.UUID), logger.Err(err))
+		return nil, errors.Wrap(errors.CodeCacheError, err)
+	}
+
+	return &WebAuthnAssertionResponse{Options: assertion}, nil
+}
+
+// StepUpResponse 二次验证结果
+type StepUpResponse struct {
+	ExpiresAt time.Time `json:"expires_at"` // 当前会话二次验证的过期时间
+}
+
+// FinishStepUp 校验二次验证的认证器响应，通过后将当前会话标记为已完成二次验证
+func (s *WebAuthnService) FinishStepUp(user *models.User, token string, credential []byte) (*StepUpResponse, error) {
+	var session webauthn.SessionData
+	if err := s.takeSession(webAuthnStepUpPrefix+user.UUID, &session); err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(credential)
+	if err != nil {
+		logger.Warn("解析二次验证响应失败", logger.String("user_uuid", user.UUID), logger.Err(err))
+		return nil, errors.New(errors.CodeInvalidFormat, "通行密钥验证响应格式无效")
+	}
+
+	waUser, err := s.loadUser(user)
+	if err != nil {
+		return nil, err
+	}
+	validated, err := s.webAuthn.ValidateLogin(waUser, session, parsed)
+	if err != nil {
+		logger.Warn("二次验证校验失败", logger.String("user_uuid", user.UUID), logger.Err(err))
+		return nil, errors.New(errors.CodeWebAuthnFailed, "")
+	}
+	if err := s.recordUse(waUser, validated); err != nil {
+		return nil, err
+	}
+
+	expiresAt, err := s.MarkSteppedUp(user.UUID, token)
+	if err != nil {
+		return nil, err
+	}
+	return &StepUpResponse{ExpiresAt: expiresAt}, nil
+}
+
+// recordUse 检查签名计数并更新凭证使用记录
+// 签名计数未递增说明同一凭证的私钥可能存在多个副本，拒绝本次验证
+func (s *WebAuthnService) recordUse(waUser *webAuthnUser, validated *webauthn.Credential) error {
+	record := waUser.findCredential(validated.ID)
+	if record == nil {
+		return errors.New(errors.CodeWebAuthnFailed, "")
+	}
+	if validated.Authenticator.CloneWarning {
+		logger.Warn("通行密钥签名计数未递增，凭证可能已被复制",
+			logger.String("user_uuid", waUser.user.UUID),
+			logger.String("credential_uuid", record.UUID),
+			logger.Int64("stored_sign_count", int64(record.SignCount)))
+		return errors.New(errors.CodeWebAuthnFailed, "通行密钥签名计数异常，可能已被复制，请联系管理员")
+	}
+
+	// 以旧计数作为条件更新，并发使用同一计数值时只有一次能成功
+	result := s.db.Model(&models.WebAuthnCredential{}).
+		Where("id = ? AND sign_count = ?", record.ID, record.SignCount).
+		Updates(map[string]interface{}{
+			"sign_count":   validated.Authenticator.SignCount,
+			"backup_state": validated.Flags.BackupState,
+			"last_used_at": time.Now(),
+		})
+	if result.Error != nil {
+		logger.Error("更新通行密钥使用记录失败", logger.String("credential_uuid", record.UUID), logger.Err(result.Error))
+		return errors.Wrap(errors.CodeDatabaseError, result.Error)
+	}
+	// 不支持签名计数的认证器计数始终为0，此时无法据此判断并发
+	if result.RowsAffected == 0 && validated.Authenticator.SignCount != 0 {
+		return errors.New(errors.CodeWebAuthnFailed, "")
+	}
+	return nil
+}
+
+// takeSession 取出并删除仪式会话，会话不存在或已过期时返回错误
+func (s *WebAuthnService) takeSession(key string, out interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	value, err := s.redis.Eval(ctx, takeSessionScript, []string{key})
+	if err != nil {
+		logger.Error("读取通行密钥会话失败", logger.Err(err))
+		return errors.Wrap(errors.CodeCacheError, err)
+	}
+	data, _ := value.(string)
+	if data == "" {
+		return errors.New(errors.CodeTokenExpired, "通行密钥验证已过期，请重新开始")
+	}
+	if err := json.Unmarshal([]byte(data), out); err != nil {
+		logger.Error("解析通行密钥会话失败", logger.Err(err))
+		return errors.Wrap(errors.CodeInternalError, err)
+	}
+	return nil
+}
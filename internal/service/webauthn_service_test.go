@@ -0,0 +1,311 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cuihe500/vaulthub/internal/database/models"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/cuihe500/vaulthub/pkg/webauthntest"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const webAuthnTestOrigin = "https://vault.example.com"
+
+// newWebAuthnTestService 返回与 app.Manager 配置一致、不依赖数据库和Redis的通行密钥服务
+func newWebAuthnTestService(t *testing.T) *WebAuthnService {
+	t.Helper()
+	wa, err := webauthn.New(&webauthn.Config{
+		RPID:                  "vault.example.com",
+		RPDisplayName:         "VaultHub",
+		RPOrigins:             []string{webAuthnTestOrigin},
+		AttestationPreference: protocol.PreferNoAttestation,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			UserVerification:   protocol.VerificationRequired,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewWebAuthnService(nil, nil, nil, wa)
+}
+
+// newWebAuthnTestUser 创建没有通行密钥的用户
+func newWebAuthnTestUser(username string) *webAuthnUser {
+	return &webAuthnUser{user: &models.User{UUID: uuid.New().String(), Username: username}}
+}
+
+// roundTripSession 模拟会话经过Redis保存后再取出
+func roundTripSession(t *testing.T, session *webauthn.SessionData) webauthn.SessionData {
+	t.Helper()
+	data, err := json.Marshal(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out webauthn.SessionData
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// registerPasskey 按 BeginRegistration/FinishRegistration 的流程注册通行密钥，并把记录加入用户的凭证列表
+func registerPasskey(t *testing.T, s *WebAuthnService, waUser *webAuthnUser, auth *webauthntest.Authenticator, name string) *models.WebAuthnCredential {
+	t.Helper()
+	exclusions := webauthn.Credentials(waUser.WebAuthnCredentials()).CredentialDescriptors()
+	creation, session, err := s.webAuthn.BeginRegistration(waUser, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		t.Fatalf("BeginRegistration: %v", err)
+	}
+	response, err := auth.Register(creation)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		t.Fatalf("ParseCredentialCreationResponseBytes: %v", err)
+	}
+	created, err := s.webAuthn.CreateCredential(waUser, roundTripSession(t, session), parsed)
+	if err != nil {
+		t.Fatalf("CreateCredential: %v", err)
+	}
+	record := newWebAuthnCredentialRecord(waUser.user.UUID, name, created)
+	waUser.credentials = append(waUser.credentials, *record)
+	return record
+}
+
+// stepUp 按 BeginStepUp/FinishStepUp 的流程完成一次二次验证，返回通过验证的凭证
+func stepUp(t *testing.T, s *WebAuthnService, waUser *webAuthnUser, auth *webauthntest.Authenticator) (*webauthn.Credential, error) {
+	t.Helper()
+	assertion, session, err := s.webAuthn.BeginLogin(waUser, webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+	response, err := auth.Assert(assertion)
+	if err != nil {
+		t.Fatalf("Assert: %v", err)
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		t.Fatalf("ParseCredentialRequestResponseBytes: %v", err)
+	}
+	return s.webAuthn.ValidateLogin(waUser, roundTripSession(t, session), parsed)
+}
+
+func TestWebAuthnRegistration(t *testing.T) {
+	s := newWebAuthnTestService(t)
+	alice := newWebAuthnTestUser("alice")
+	auth := webauthntest.New(webAuthnTestOrigin)
+
+	record := registerPasskey(t, s, alice, auth, "笔记本")
+	if record.UserUUID != alice.user.UUID || record.Name != "笔记本" || record.SignCount != 0 {
+		t.Fatalf("record = %+v", record)
+	}
+	if ids := auth.CredentialIDs(); len(ids) != 1 || string(ids[0]) != string(record.CredentialID) {
+		t.Fatal("保存的凭证ID与认证器不一致")
+	}
+	if record.Transports != "internal" {
+		t.Fatalf("Transports = %q", record.Transports)
+	}
+
+	// 保存的记录转换回WebAuthn凭证后内容不变
+	got := toWebAuthnCredential(record)
+	if string(got.ID) != string(record.CredentialID) || string(got.PublicKey) != string(record.PublicKey) ||
+		len(got.Transport) != 1 || got.Transport[0] != protocol.Internal {
+		t.Fatalf("toWebAuthnCredential = %+v", got)
+	}
+
+	// 已注册的认证器被排除，不能重复注册
+	creation, _, err := s.webAuthn.BeginRegistration(alice,
+		webauthn.WithExclusions(webauthn.Credentials(alice.WebAuthnCredentials()).CredentialDescriptors()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Register(creation); err != webauthntest.ErrCredentialExcluded {
+		t.Fatalf("重复注册 err = %v", err)
+	}
+	registerPasskey(t, s, alice, webauthntest.New(webAuthnTestOrigin), "手机")
+	if len(alice.credentials) != 2 {
+		t.Fatalf("credentials = %d", len(alice.credentials))
+	}
+}
+
+func TestWebAuthnRegistrationRejectsForeignOrigin(t *testing.T) {
+	s := newWebAuthnTestService(t)
+	alice := newWebAuthnTestUser("alice")
+
+	creation, session, err := s.webAuthn.BeginRegistration(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := webauthntest.New("https://phishing.example.net").Register(creation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.webAuthn.CreateCredential(alice, *session, parsed); err == nil {
+		t.Fatal("接受了其他来源的注册响应")
+	}
+}
+
+func TestWebAuthnPasskeyLogin(t *testing.T) {
+	s := newWebAuthnTestService(t)
+	alice := newWebAuthnTestUser("alice")
+	bob := newWebAuthnTestUser("bob")
+	aliceAuth := webauthntest.New(webAuthnTestOrigin)
+	bobAuth := webauthntest.New(webAuthnTestOrigin)
+	registerPasskey(t, s, alice, aliceAuth, "笔记本")
+	registerPasskey(t, s, bob, bobAuth, "笔记本")
+
+	// 与 FinishLogin 相同，按用户句柄查找用户
+	users := map[string]*webAuthnUser{alice.user.UUID: alice, bob.user.UUID: bob}
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		if u, ok := users[string(userHandle)]; ok {
+			return u, nil
+		}
+		return nil, gorm.ErrRecordNotFound
+	}
+	login := func(auth *webauthntest.Authenticator) (webauthn.User, *webauthn.Credential, error) {
+		assertion, session, err := s.webAuthn.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(assertion.Response.AllowedCredentials) != 0 {
+			t.Fatal("无密码登录不应限制可用凭证")
+		}
+		response, err := auth.Assert(assertion)
+		if err != nil {
+			t.Fatalf("Assert: %v", err)
+		}
+		parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s.webAuthn.ValidatePasskeyLogin(handler, roundTripSession(t, session), parsed)
+	}
+
+	user, validated, err := login(aliceAuth)
+	if err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+	if user != alice || validated.Authenticator.SignCount != 1 || validated.Authenticator.CloneWarning {
+		t.Fatalf("登录结果 = %v, %+v", user, validated.Authenticator)
+	}
+
+	// 凭证被删除（管理员重置）后不能再登录
+	alice.credentials = nil
+	if _, _, err := login(aliceAuth); err == nil {
+		t.Fatal("已删除的通行密钥仍能登录")
+	}
+
+	// 用户不存在时登录失败
+	delete(users, bob.user.UUID)
+	if _, _, err := login(bobAuth); err == nil {
+		t.Fatal("不存在的用户仍能登录")
+	}
+}
+
+func TestWebAuthnStepUp(t *testing.T) {
+	s := newWebAuthnTestService(t)
+	alice := newWebAuthnTestUser("alice")
+	bob := newWebAuthnTestUser("bob")
+	aliceAuth := webauthntest.New(webAuthnTestOrigin)
+	bobAuth := webauthntest.New(webAuthnTestOrigin)
+	registerPasskey(t, s, alice, aliceAuth, "笔记本")
+	registerPasskey(t, s, bob, bobAuth, "笔记本")
+
+	validated, err := stepUp(t, s, alice, aliceAuth)
+	if err != nil {
+		t.Fatalf("二次验证失败: %v", err)
+	}
+	if string(validated.ID) != string(alice.credentials[0].CredentialID) || validated.Authenticator.SignCount != 1 {
+		t.Fatalf("validated = %+v", validated)
+	}
+
+	// 二次验证只允许当前用户的凭证，其他用户的认证器没有可用凭证
+	assertion, session, err := s.webAuthn.BeginLogin(alice, webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bobAuth.Assert(assertion); err != webauthntest.ErrNoCredential {
+		t.Fatalf("其他用户的认证器 err = %v", err)
+	}
+
+	// 即使认证器忽略允许列表，用其他用户的凭证应答也会被拒绝
+	ignored := *assertion
+	ignored.Response.AllowedCredentials = nil
+	response, err := bobAuth.Assert(&ignored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.webAuthn.ValidateLogin(alice, *session, parsed); err == nil {
+		t.Fatal("接受了其他用户的凭证")
+	}
+}
+
+func TestWebAuthnSignCountRegression(t *testing.T) {
+	s := newWebAuthnTestService(t)
+	alice := newWebAuthnTestUser("alice")
+	auth := webauthntest.New(webAuthnTestOrigin)
+	registerPasskey(t, s, alice, auth, "笔记本")
+	clone := auth.Clone()
+
+	// 原认证器正常使用，签名计数递增，recordUse 保存新计数
+	for want := uint32(1); want <= 2; want++ {
+		validated, err := stepUp(t, s, alice, auth)
+		if err != nil {
+			t.Fatalf("第%d次二次验证失败: %v", want, err)
+		}
+		if validated.Authenticator.SignCount != want || validated.Authenticator.CloneWarning {
+			t.Fatalf("第%d次: Authenticator = %+v", want, validated.Authenticator)
+		}
+		alice.credentials[0].SignCount = validated.Authenticator.SignCount
+	}
+
+	// 复制出的认证器计数落后于已保存的计数，应被识别为凭证被复制并拒绝
+	validated, err := stepUp(t, s, alice, clone)
+	if err != nil {
+		t.Fatalf("ValidateLogin: %v", err)
+	}
+	if !validated.Authenticator.CloneWarning {
+		t.Fatalf("签名计数回退未触发CloneWarning: %+v", validated.Authenticator)
+	}
+	err = s.recordUse(alice, validated)
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Code != errors.CodeWebAuthnFailed {
+		t.Fatalf("recordUse err = %v", err)
+	}
+
+	// 计数相同同样视为回退
+	clone = auth.Clone()
+	alice.credentials[0].SignCount++
+	validated, err = stepUp(t, s, alice, clone)
+	if err != nil {
+		t.Fatalf("ValidateLogin: %v", err)
+	}
+	if !validated.Authenticator.CloneWarning {
+		t.Fatalf("签名计数未递增未触发CloneWarning: %+v", validated.Authenticator)
+	}
+}
+
+func TestWebAuthnRecordUseUnknownCredential(t *testing.T) {
+	s := newWebAuthnTestService(t)
+	alice := newWebAuthnTestUser("alice")
+
+	// 验证通过的凭证不属于该用户时拒绝，且不会写数据库
+	err := s.recordUse(alice, &webauthn.Credential{ID: []byte("unknown")})
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Code != errors.CodeWebAuthnFailed {
+		t.Fatalf("err = %v", err)
+	}
+}
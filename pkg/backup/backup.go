@@ -0,0 +1,231 @@
+// Package backup 实现可移植的加密备份文件格式
+//
+// 备份文件是一个JSON文档，明文头部描述格式版本和密钥派生参数，
+// 秘密数据整体序列化后使用口令派生的密钥（Argon2id）以 AES-256-GCM 加密。
+// 头部字段参与AEAD的附加认证数据，任何篡改都会导致解密失败。
+// 格式说明见 docs/backup_format.md。
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cuihe500/vaulthub/pkg/crypto"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// FormatName 文件格式标识
+	FormatName = "vaulthub-backup"
+	// FormatVersion 当前格式版本
+	FormatVersion = 1
+
+	// KDFArgon2id 密钥派生算法
+	KDFArgon2id = "argon2id"
+	// CipherAES256GCM 加密算法
+	CipherAES256GCM = "AES-256-GCM"
+
+	// MinPassphraseLength 备份口令最小长度
+	MinPassphraseLength = 12
+)
+
+// 解密时允许的KDF参数上限，防止恶意文件通过超大参数耗尽服务器资源
+const (
+	maxKDFTime    = 10
+	maxKDFMemory  = 256 * 1024 // 256MB
+	maxKDFThreads = 16
+)
+
+// ErrInvalidPassphrase 口令错误或文件被篡改
+var ErrInvalidPassphrase = errors.New("备份口令错误或文件已损坏")
+
+// KDFParams 密钥派生参数
+type KDFParams struct {
+	Name    string `json:"name"`    // 固定为argon2id
+	Salt    []byte `json:"salt"`    // 随机盐值（base64）
+	Time    uint32 `json:"time"`    // 迭代次数
+	Memory  uint32 `json:"memory"`  // 内存消耗（KB）
+	Threads uint8  `json:"threads"` // 并行度
+}
+
+// Header 备份文件头部（明文，各字段通过附加认证数据参与认证）
+type Header struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	KDF       KDFParams `json:"kdf"`
+	Cipher    string    `json:"cipher"`
+}
+
+// File 备份文件
+type File struct {
+	Header
+	Nonce      []byte `json:"nonce"`      // AES-GCM Nonce（base64）
+	Ciphertext []byte `json:"ciphertext"` // 密文+认证标签（base64）
+}
+
+// Payload 备份内容（加密前的明文结构）
+type Payload struct {
+	ExportedAt time.Time `json:"exported_at"`
+	Source     Source    `json:"source"`
+	Secrets    []Secret  `json:"secrets"`
+}
+
+// Source 备份来源信息
+type Source struct {
+	UserUUID string `json:"user_uuid"`
+	Username string `json:"username,omitempty"`
+}
+
+// Secret 单个秘密
+type Secret struct {
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Description string          `json:"description,omitempty"`
+	Metadata    json.RawMessage `json:"metadata,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	Versions    []Version       `json:"versions"` // 按版本号升序，最后一个为当前版本
+}
+
+// Version 秘密的一个版本
+type Version struct {
+	Version   int       `json:"version"`
+	Value     string    `json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Current 返回当前（最新）版本，没有版本时返回nil
+func (s *Secret) Current() *Version {
+	if len(s.Versions) == 0 {
+		return nil
+	}
+	return &s.Versions[len(s.Versions)-1]
+}
+
+// Seal 使用口令加密备份内容
+func Seal(payload *Payload, passphrase string) (*File, error) {
+	if len(passphrase) < MinPassphraseLength {
+		return nil, fmt.Errorf("备份口令长度不能少于%d个字符", MinPassphraseLength)
+	}
+
+	plaintext, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("序列化备份内容失败: %w", err)
+	}
+	defer crypto.ClearBytes(plaintext)
+
+	salt, err := crypto.GenerateRandomBytes(crypto.SaltSize)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := crypto.GenerateRandomBytes(crypto.GCMNonceSize)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &File{
+		Header: Header{
+			Format:    FormatName,
+			Version:   FormatVersion,
+			CreatedAt: time.Now().UTC(),
+			KDF: KDFParams{
+				Name:    KDFArgon2id,
+				Salt:    salt,
+				Time:    crypto.Argon2Time,
+				Memory:  crypto.Argon2Memory,
+				Threads: crypto.Argon2Threads,
+			},
+			Cipher: CipherAES256GCM,
+		},
+		Nonce: nonce,
+	}
+
+	aead, err := file.newAEAD(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	file.Ciphertext = aead.Seal(nil, nonce, plaintext, file.additionalData())
+
+	return file, nil
+}
+
+// Open 校验备份文件并使用口令解密
+func Open(file *File, passphrase string) (*Payload, error) {
+	if err := file.validate(); err != nil {
+		return nil, err
+	}
+
+	aead, err := file.newAEAD(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, file.Nonce, file.Ciphertext, file.additionalData())
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+	defer crypto.ClearBytes(plaintext)
+
+	var payload Payload
+	if err := json.Unmarshal(plaintext, &payload); err != nil {
+		return nil, fmt.Errorf("解析备份内容失败: %w", err)
+	}
+	return &payload, nil
+}
+
+// validate 校验头部字段
+func (f *File) validate() error {
+	if f.Format != FormatName {
+		return fmt.Errorf("不是有效的VaultHub备份文件")
+	}
+	if f.Version != FormatVersion {
+		return fmt.Errorf("不支持的备份格式版本: %d", f.Version)
+	}
+	if f.Cipher != CipherAES256GCM {
+		return fmt.Errorf("不支持的加密算法: %s", f.Cipher)
+	}
+	kdf := f.KDF
+	if kdf.Name != KDFArgon2id {
+		return fmt.Errorf("不支持的密钥派生算法: %s", kdf.Name)
+	}
+	if len(kdf.Salt) < 16 || kdf.Time == 0 || kdf.Memory == 0 || kdf.Threads == 0 ||
+		kdf.Time > maxKDFTime || kdf.Memory > maxKDFMemory || kdf.Threads > maxKDFThreads {
+		return fmt.Errorf("备份文件的密钥派生参数无效")
+	}
+	if len(f.Nonce) != crypto.GCMNonceSize || len(f.Ciphertext) < crypto.GCMTagSize {
+		return fmt.Errorf("备份文件数据不完整")
+	}
+	return nil
+}
+
+// additionalData 构造AEAD附加认证数据
+// 使用固定格式的字符串而不是JSON，避免不同实现的序列化差异：
+// format|version|cipher|kdf.name|kdf.time|kdf.memory|kdf.threads|base64(kdf.salt)|created_at(unix秒)
+func (f *File) additionalData() []byte {
+	return []byte(fmt.Sprintf("%s|%d|%s|%s|%d|%d|%d|%s|%d",
+		f.Format, f.Version, f.Cipher,
+		f.KDF.Name, f.KDF.Time, f.KDF.Memory, f.KDF.Threads,
+		base64.StdEncoding.EncodeToString(f.KDF.Salt),
+		f.CreatedAt.Unix()))
+}
+
+// newAEAD 根据头部的KDF参数从口令派生密钥并创建AEAD
+func (f *File) newAEAD(passphrase string) (cipher.AEAD, error) {
+	key := argon2.IDKey([]byte(passphrase), f.KDF.Salt, f.KDF.Time, f.KDF.Memory, f.KDF.Threads, crypto.AESKeySize)
+	defer crypto.ClearBytes(key)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("创建AES cipher失败: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("创建GCM模式失败: %w", err)
+	}
+	return aead, nil
+}
@@ -0,0 +1,69 @@
+// Package blobstore 提供附件等大对象的存储抽象
+//
+// 存储层只保存密文，加解密由调用方负责。目前实现本地文件系统存储，
+// 后续可以按同一接口接入S3兼容的对象存储。
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// 支持的存储类型
+const (
+	TypeLocal = "local" // 本地文件系统
+	TypeS3    = "s3"    // S3兼容对象存储（尚未实现）
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("对象不存在")
+
+// Store 对象存储接口
+type Store interface {
+	// Put 从r读取全部内容写入key，返回写入的字节数
+	// 写入失败时不会留下不完整的对象
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Get 打开key对应的对象，调用方负责关闭
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete 删除key对应的对象，对象不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+}
+
+// Config 存储配置
+type Config struct {
+	Type string // 存储类型：local
+	Path string // 本地存储根目录（type=local时使用）
+}
+
+// New 根据配置创建存储实例
+func New(cfg Config) (Store, error) {
+	switch cfg.Type {
+	case "", TypeLocal:
+		return NewLocalStore(cfg.Path)
+	case TypeS3:
+		return nil, fmt.Errorf("暂不支持的存储类型: %s", cfg.Type)
+	default:
+		return nil, fmt.Errorf("未知的存储类型: %s", cfg.Type)
+	}
+}
+
+// validateKey 校验对象键，只允许 [a-zA-Z0-9-_/.] 且不能包含 ".." 等路径穿越片段
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.HasSuffix(key, "/") {
+		return fmt.Errorf("无效的对象键: %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("无效的对象键: %q", key)
+		}
+	}
+	for _, c := range key {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '/' || c == '.') {
+			return fmt.Errorf("无效的对象键: %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package breach
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// 布隆过滤器文件格式:
+//
+//	[magic "VHBF"(4)][version(1)][hash type(1)][k(1)][reserved(1)][m(8)][n(8)][bitset(m/8)]
+//
+// m为位数，k为哈希函数个数，n为构建时写入的条目数，整数均为小端序
+const (
+	bloomMagic      = "VHBF"
+	bloomVersion    = 1
+	bloomHeaderSize = 24
+	maxBloomHashes  = 30
+)
+
+// bloomFilter 布隆过滤器检查器
+// 查询时按位读取文件，依赖操作系统页缓存，不需要把整个过滤器加载到内存
+type bloomFilter struct {
+	file     *os.File
+	hashType HashType
+	hashes   uint64
+	bits     uint64
+}
+
+// openBloomFilter 读取并校验布隆过滤器文件头
+func openBloomFilter(file *os.File) (*bloomFilter, error) {
+	header := make([]byte, bloomHeaderSize)
+	if _, err := file.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("读取布隆过滤器文件头失败: %w", err)
+	}
+	if header[4] != bloomVersion {
+		return nil, fmt.Errorf("不支持的布隆过滤器版本: %d", header[4])
+	}
+
+	filter := &bloomFilter{
+		file:     file,
+		hashType: HashType(header[5]),
+		hashes:   uint64(header[6]),
+		bits:     binary.LittleEndian.Uint64(header[8:16]),
+	}
+	if filter.hashType.hexLength() == 0 {
+		return nil, fmt.Errorf("不支持的哈希算法: %d", header[5])
+	}
+	if filter.hashes == 0 || filter.hashes > maxBloomHashes || filter.bits == 0 || filter.bits%8 != 0 {
+		return nil, errors.New("布隆过滤器参数无效")
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < bloomHeaderSize+int64(filter.bits/8) {
+		return nil, errors.New("布隆过滤器文件不完整")
+	}
+	return filter, nil
+}
+
+// Contains 判断密码是否可能出现在数据集中
+func (f *bloomFilter) Contains(password string) (bool, error) {
+	h1, h2 := bloomHashes(f.hashType.Hash(password))
+	buf := make([]byte, 1)
+	for i := uint64(0); i < f.hashes; i++ {
+		bit := (h1 + i*h2) % f.bits
+		if _, err := f.file.ReadAt(buf, bloomHeaderSize+int64(bit/8)); err != nil {
+			return false, fmt.Errorf("读取布隆过滤器失败: %w", err)
+		}
+		if buf[0]&(1<<(bit%8)) == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Close 关闭过滤器文件
+func (f *bloomFilter) Close() error {
+	return f.file.Close()
+}
+
+// bloomHashes 由密码摘要得到双重哈希的两个基值
+// 摘要本身已经均匀分布，直接取前16字节；h2取奇数保证与2的幂次位数互素
+func bloomHashes(digest []byte) (uint64, uint64) {
+	return binary.LittleEndian.Uint64(digest[0:8]), binary.LittleEndian.Uint64(digest[8:16]) | 1
+}
+
+// BloomParams 布隆过滤器参数
+type BloomParams struct {
+	HashType HashType // 哈希算法（由数据集识别）
+	Entries  uint64   // 写入的条目数
+	Bits     uint64   // 位数
+	Hashes   uint64   // 哈希函数个数
+}
+
+// SizeBytes 过滤器文件大小（字节）
+func (p BloomParams) SizeBytes() uint64 {
+	return bloomHeaderSize + p.Bits/8
+}
+
+// OptimalBloomParams 按预期条目数和误判率计算位数和哈希函数个数
+func OptimalBloomParams(entries uint64, falsePositiveRate float64) (bits, hashes uint64) {
+	n := float64(entries)
+	m := math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	bits = (uint64(m) + 7) / 8 * 8
+	if bits == 0 {
+		bits = 8
+	}
+	hashes = uint64(math.Round(float64(bits) / n * math.Ln2))
+	if hashes < 1 {
+		hashes = 1
+	}
+	if hashes > maxBloomHashes {
+		hashes = maxBloomHashes
+	}
+	return bits, hashes
+}
+
+// CountEntries 统计数据集的条目数（非空行数），用于构建前确定过滤器大小
+func CountEntries(r io.Reader) (uint64, error) {
+	var count uint64
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if len(parseLine(scanner.Bytes())) > 0 {
+			count++
+		}
+	}
+	return count, scanner.Err()
+}
+
+// BuildBloomFilter 从HIBP格式的完整列表（每行 HASH:COUNT）构建布隆过滤器并写入w
+// expectedEntries为数据集条目数，用于确定过滤器大小；构建时整个位图保存在内存中
+func BuildBloomFilter(r io.Reader, w io.Writer, expectedEntries uint64, falsePositiveRate float64) (*BloomParams, error) {
+	if expectedEntries == 0 {
+		return nil, errors.New("条目数必须大于0")
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		return nil, errors.New("误判率必须在0和1之间")
+	}
+
+	params := &BloomParams{}
+	params.Bits, params.Hashes = OptimalBloomParams(expectedEntries, falsePositiveRate)
+	bitset := make([]byte, params.Bits/8)
+
+	scanner := bufio.NewScanner(r)
+	digest := make([]byte, 0, 20)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		hash := parseLine(scanner.Bytes())
+		if len(hash) == 0 {
+			continue
+		}
+		if params.HashType == 0 {
+			hashType, err := hashTypeByHexLength(len(hash))
+			if err != nil {
+				return nil, fmt.Errorf("第%d行: %w", lineNo, err)
+			}
+			params.HashType = hashType
+		}
+		if len(hash) != params.HashType.hexLength() {
+			return nil, fmt.Errorf("第%d行: 哈希长度与数据集不一致", lineNo)
+		}
+
+		digest = digest[:len(hash)/2]
+		if _, err := hex.Decode(digest, hash); err != nil {
+			return nil, fmt.Errorf("第%d行: 无效的十六进制哈希", lineNo)
+		}
+		h1, h2 := bloomHashes(digest)
+		for i := uint64(0); i < params.Hashes; i++ {
+			bit := (h1 + i*h2) % params.Bits
+			bitset[bit/8] |= 1 << (bit % 8)
+		}
+		params.Entries++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取数据集失败: %w", err)
+	}
+	if params.Entries == 0 {
+		return nil, errors.New("泄露密码数据为空")
+	}
+
+	header := make([]byte, bloomHeaderSize)
+	copy(header, bloomMagic)
+	header[4] = bloomVersion
+	header[5] = byte(params.HashType)
+	header[6] = byte(params.Hashes)
+	binary.LittleEndian.PutUint64(header[8:16], params.Bits)
+	binary.LittleEndian.PutUint64(header[16:24], params.Entries)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(bitset); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
@@ -0,0 +1,160 @@
+// Package breach 提供离线的泄露密码检查
+//
+// 数据来源为 Have I Been Pwned 格式的密码哈希数据集，运行时只读取本地文件，不访问网络。
+// 支持三种数据文件：
+//   - 按哈希排序的完整列表（每行 HASH:COUNT），通过二分查找定位，不需要加载到内存
+//   - 按5位前缀拆分的目录（文件名为前缀，每行 SUFFIX:COUNT），与官方下载工具的输出一致
+//   - 由完整列表构建的布隆过滤器（见 BuildBloomFilter），体积更小但存在少量误判
+//
+// 哈希算法支持SHA-1和NTLM，按数据文件自动识别。
+package breach
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf16"
+
+	// NTLM哈希由MD4定义，由数据集格式决定，不用于新的安全设计
+	"golang.org/x/crypto/md4"
+)
+
+// HashType 数据集使用的哈希算法
+type HashType uint8
+
+const (
+	HashSHA1 HashType = 1 // SHA-1（40位十六进制）
+	HashNTLM HashType = 2 // NTLM，即UTF-16LE编码后的MD4（32位十六进制）
+)
+
+// prefixLength 前缀目录模式下文件名使用的哈希前缀长度（与HIBP range API一致）
+const prefixLength = 5
+
+// Checker 泄露密码检查器
+type Checker interface {
+	// Contains 判断密码是否出现在泄露数据集中
+	// 布隆过滤器可能误判为已泄露，但不会漏判
+	Contains(password string) (bool, error)
+	// Close 释放打开的文件
+	Close() error
+}
+
+// String 返回哈希算法名称
+func (t HashType) String() string {
+	switch t {
+	case HashSHA1:
+		return "sha1"
+	case HashNTLM:
+		return "ntlm"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(t))
+	}
+}
+
+// hexLength 返回哈希的十六进制长度
+func (t HashType) hexLength() int {
+	switch t {
+	case HashSHA1:
+		return sha1.Size * 2
+	case HashNTLM:
+		return md4.Size * 2
+	default:
+		return 0
+	}
+}
+
+// hashTypeByHexLength 根据十六进制哈希长度识别哈希算法
+func hashTypeByHexLength(n int) (HashType, error) {
+	switch n {
+	case sha1.Size * 2:
+		return HashSHA1, nil
+	case md4.Size * 2:
+		return HashNTLM, nil
+	default:
+		return 0, fmt.Errorf("无法识别的哈希长度: %d", n)
+	}
+}
+
+// Hash 按哈希算法计算密码的摘要
+func (t HashType) Hash(password string) []byte {
+	switch t {
+	case HashNTLM:
+		encoded := utf16.Encode([]rune(password))
+		buf := make([]byte, 0, len(encoded)*2)
+		for _, u := range encoded {
+			buf = append(buf, byte(u), byte(u>>8))
+		}
+		h := md4.New()
+		h.Write(buf)
+		return h.Sum(nil)
+	default:
+		sum := sha1.Sum([]byte(password))
+		return sum[:]
+	}
+}
+
+// hexHash 返回密码摘要的大写十六进制表示（与数据集格式一致）
+func (t HashType) hexHash(password string) []byte {
+	return bytes.ToUpper([]byte(hex.EncodeToString(t.Hash(password))))
+}
+
+// Open 打开泄露密码数据文件，按文件内容自动识别格式和哈希算法
+// path为目录时按前缀目录模式打开
+func Open(path string) (Checker, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开泄露密码数据失败: %w", err)
+	}
+	if info.IsDir() {
+		return openPrefixDir(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开泄露密码数据失败: %w", err)
+	}
+
+	magic := make([]byte, len(bloomMagic))
+	if _, err := io.ReadFull(file, magic); err == nil && string(magic) == bloomMagic {
+		checker, err := openBloomFilter(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		return checker, nil
+	}
+
+	checker, err := openHashList(file, info.Size())
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return checker, nil
+}
+
+// parseLine 解析数据集中的一行，返回哈希部分（去掉 :COUNT 后缀）
+func parseLine(line []byte) []byte {
+	line = bytes.TrimRight(line, "\r\n")
+	if i := bytes.IndexByte(line, ':'); i >= 0 {
+		line = line[:i]
+	}
+	return bytes.TrimSpace(line)
+}
+
+// detectHashType 读取数据的第一行识别哈希算法
+func detectHashType(r io.Reader) (HashType, error) {
+	line, err := bufio.NewReader(r).ReadBytes('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, err
+	}
+	hash := parseLine(line)
+	if len(hash) == 0 {
+		return 0, errors.New("泄露密码数据为空")
+	}
+	return hashTypeByHexLength(len(hash))
+}
@@ -0,0 +1,146 @@
+package breach
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hashList 按哈希排序的完整列表（每行 HASH:COUNT）
+// 查询时在文件上二分查找，每次查询只读取少量数据，不需要把数十GB的数据集加载到内存
+type hashList struct {
+	file     *os.File
+	size     int64
+	hashType HashType
+}
+
+// openHashList 打开按哈希排序的完整列表
+func openHashList(file *os.File, size int64) (*hashList, error) {
+	hashType, err := detectHashType(io.NewSectionReader(file, 0, size))
+	if err != nil {
+		return nil, fmt.Errorf("识别泄露密码数据格式失败: %w", err)
+	}
+	return &hashList{file: file, size: size, hashType: hashType}, nil
+}
+
+// Contains 判断密码是否出现在列表中
+func (l *hashList) Contains(password string) (bool, error) {
+	target := l.hashType.hexHash(password)
+
+	lo, hi := int64(0), l.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start, line, err := l.lineFrom(mid)
+		if err != nil {
+			return false, err
+		}
+		if line == nil {
+			// mid之后没有完整的行
+			hi = mid
+			continue
+		}
+
+		switch bytes.Compare(bytes.ToUpper(parseLine(line)), target) {
+		case 0:
+			return true, nil
+		case -1:
+			lo = start + int64(len(line))
+		default:
+			hi = mid
+		}
+	}
+	return false, nil
+}
+
+// lineFrom 返回从offset开始（含）的第一个完整行及其起始位置
+// offset不在行首时跳过当前行的剩余部分；没有更多行时返回nil
+func (l *hashList) lineFrom(offset int64) (int64, []byte, error) {
+	start := offset
+	if offset > 0 {
+		// 从前一个字节开始读，前一个字节为换行符时offset本身就是行首
+		start = offset - 1
+	}
+	reader := bufio.NewReader(io.NewSectionReader(l.file, start, l.size-start))
+	if offset > 0 {
+		skipped, err := reader.ReadBytes('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, nil, nil
+			}
+			return 0, nil, err
+		}
+		start += int64(len(skipped))
+	}
+
+	line, err := reader.ReadBytes('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, nil, err
+	}
+	if len(line) == 0 {
+		return 0, nil, nil
+	}
+	return start, line, nil
+}
+
+// Close 关闭数据文件
+func (l *hashList) Close() error {
+	return l.file.Close()
+}
+
+// prefixDir 按哈希前缀拆分的目录（文件名为5位前缀，每行为去掉前缀的 SUFFIX:COUNT）
+type prefixDir struct {
+	dir      string
+	hashType HashType
+}
+
+// openPrefixDir 打开前缀目录，读取 00000.txt 识别哈希算法
+func openPrefixDir(dir string) (*prefixDir, error) {
+	file, err := os.Open(filepath.Join(dir, strings.Repeat("0", prefixLength)+".txt"))
+	if err != nil {
+		return nil, fmt.Errorf("打开泄露密码前缀目录失败: %w", err)
+	}
+	defer file.Close()
+
+	line, err := bufio.NewReader(file).ReadBytes('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("读取泄露密码前缀文件失败: %w", err)
+	}
+	suffix := parseLine(line)
+	if len(suffix) == 0 {
+		return nil, errors.New("泄露密码前缀文件为空")
+	}
+	hashType, err := hashTypeByHexLength(len(suffix) + prefixLength)
+	if err != nil {
+		return nil, fmt.Errorf("识别泄露密码数据格式失败: %w", err)
+	}
+	return &prefixDir{dir: dir, hashType: hashType}, nil
+}
+
+// Contains 读取密码哈希前缀对应的文件并查找后缀
+func (d *prefixDir) Contains(password string) (bool, error) {
+	target := d.hashType.hexHash(password)
+	file, err := os.Open(filepath.Join(d.dir, string(target[:prefixLength])+".txt"))
+	if err != nil {
+		return false, fmt.Errorf("打开泄露密码前缀文件失败: %w", err)
+	}
+	defer file.Close()
+
+	suffix := target[prefixLength:]
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if bytes.EqualFold(parseLine(scanner.Bytes()), suffix) {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// Close 前缀目录模式不保持打开的文件
+func (d *prefixDir) Close() error {
+	return nil
+}
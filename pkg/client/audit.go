@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// AuditService 审计日志接口
+type AuditService struct {
+	client *Client
+}
+
+// QueryLogs 查询审计日志，opts为nil时返回当前用户的全部日志（最多10000条）
+func (s *AuditService) QueryLogs(ctx context.Context, opts *AuditLogOptions) (*AuditLogList, error) {
+	query := url.Values{}
+	if opts != nil {
+		setIfNotEmpty(query, "user_uuid", opts.UserUUID)
+		setIfNotEmpty(query, "action_type", opts.ActionType)
+		setIfNotEmpty(query, "resource_type", opts.ResourceType)
+		setIfNotEmpty(query, "status", opts.Status)
+		setTime(query, "start_time", opts.StartTime, time.RFC3339)
+		setTime(query, "end_time", opts.EndTime, time.RFC3339)
+		pageQuery(query, opts.Page, opts.PageSize)
+	}
+
+	var list AuditLogList
+	if err := s.client.Do(ctx, http.MethodGet, "/api/v1/audit/logs", query, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// SecretTypeStatistics 导出密钥类型统计，userUUID为空时普通用户统计自己、管理员统计全局
+func (s *AuditService) SecretTypeStatistics(ctx context.Context, userUUID string) (*SecretTypeStatistics, error) {
+	query := url.Values{}
+	setIfNotEmpty(query, "user_uuid", userUUID)
+
+	var stats SecretTypeStatistics
+	if err := s.client.Do(ctx, http.MethodGet, "/api/v1/audit/logs/export", query, nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// OperationStatistics 导出时间范围内的操作统计
+func (s *AuditService) OperationStatistics(ctx context.Context, userUUID string, start, end time.Time) (*OperationStatistics, error) {
+	query := url.Values{}
+	setIfNotEmpty(query, "user_uuid", userUUID)
+	setTime(query, "start_time", start, time.RFC3339)
+	setTime(query, "end_time", end, time.RFC3339)
+
+	var stats OperationStatistics
+	if err := s.client.Do(ctx, http.MethodGet, "/api/v1/audit/operations/export", query, nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// setIfNotEmpty 值非空时设置查询参数
+func setIfNotEmpty(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+// setTime 时间非零时按格式设置查询参数
+func setTime(query url.Values, key string, t time.Time, layout string) {
+	if !t.IsZero() {
+		query.Set(key, t.Format(layout))
+	}
+}
@@ -0,0 +1,275 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// AuthService 认证相关接口
+type AuthService struct {
+	client *Client
+}
+
+// Login 用户名密码登录，成功后客户端使用返回的令牌
+// 需要两步验证时返回的MFARequired为true，客户端令牌不变，需要再调用LoginWithMFA；
+// 密码过期时返回的PasswordChangeRequired为true，需要先调用ChangeExpiredPassword
+func (s *AuthService) Login(ctx context.Context, username, password string) (*LoginResponse, error) {
+	var resp LoginResponse
+	body := map[string]string{"username": username, "password": password}
+	if err := s.client.do(ctx, http.MethodPost, "/api/v1/auth/login", nil, body, &resp, false); err != nil {
+		return nil, err
+	}
+	if !resp.MFARequired && !resp.PasswordChangeRequired {
+		s.client.setTokens(resp.Token, resp.RefreshToken)
+	}
+	return &resp, nil
+}
+
+// LoginWithAppRole 服务账户以role_id和secret_id登录，成功后客户端使用返回的服务账户令牌
+// 服务账户没有刷新令牌，令牌过期后重新登录
+func (s *AuthService) LoginWithAppRole(ctx context.Context, roleID, secretID string) (*AppRoleLoginResponse, error) {
+	var resp AppRoleLoginResponse
+	body := map[string]string{"role_id": roleID, "secret_id": secretID}
+	if err := s.client.do(ctx, http.MethodPost, "/api/v1/auth/approle/login", nil, body, &resp, false); err != nil {
+		return nil, err
+	}
+	s.client.setTokens(resp.Token, "")
+	return &resp, nil
+}
+
+// ChangeExpiredPassword 提交Login或LoginWithMFA返回的PasswordChangeToken和新密码，修改过期密码后客户端使用返回的令牌
+func (s *AuthService) ChangeExpiredPassword(ctx context.Context, passwordChangeToken, newPassword string) (*LoginResponse, error) {
+	var resp LoginResponse
+	body := map[string]string{"password_change_token": passwordChangeToken, "new_password": newPassword}
+	if err := s.client.do(ctx, http.MethodPost, "/api/v1/auth/login/password-change", nil, body, &resp, false); err != nil {
+		return nil, err
+	}
+	s.client.setTokens(resp.Token, resp.RefreshToken)
+	return &resp, nil
+}
+
+// LoginWithMFA 两步验证登录第二步，提交Login返回的MFAToken和认证器验证码（或备用码），成功后客户端使用返回的令牌
+// 登录时密码已过期的，返回的PasswordChangeRequired为true，需要再调用ChangeExpiredPassword
+func (s *AuthService) LoginWithMFA(ctx context.Context, mfaToken, code string) (*LoginResponse, error) {
+	var resp LoginResponse
+	body := map[string]string{"mfa_token": mfaToken, "code": code}
+	if err := s.client.do(ctx, http.MethodPost, "/api/v1/auth/login/mfa", nil, body, &resp, false); err != nil {
+		return nil, err
+	}
+	if !resp.PasswordChangeRequired {
+		s.client.setTokens(resp.Token, resp.RefreshToken)
+	}
+	return &resp, nil
+}
+
+// BeginPasskeyLogin 开始通行密钥无密码登录，返回会话令牌和传给认证器的参数
+func (s *AuthService) BeginPasskeyLogin(ctx context.Context) (*WebAuthnAssertion, error) {
+	var resp WebAuthnAssertion
+	if err := s.client.do(ctx, http.MethodPost, "/api/v1/auth/login/webauthn/begin", nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// LoginWithPasskey 提交认证器的验证结果完成通行密钥登录，成功后客户端使用返回的令牌
+func (s *AuthService) LoginWithPasskey(ctx context.Context, sessionToken string, credential json.RawMessage) (*LoginResponse, error) {
+	var resp LoginResponse
+	body := map[string]interface{}{"session_token": sessionToken, "credential": credential}
+	if err := s.client.do(ctx, http.MethodPost, "/api/v1/auth/login/webauthn/finish", nil, body, &resp, false); err != nil {
+		return nil, err
+	}
+	s.client.setTokens(resp.Token, resp.RefreshToken)
+	return &resp, nil
+}
+
+// BeginOIDCLogin 开始单点登录，返回身份提供方的授权地址和state
+func (s *AuthService) BeginOIDCLogin(ctx context.Context) (*OIDCLoginBegin, error) {
+	var resp OIDCLoginBegin
+	if err := s.client.do(ctx, http.MethodPost, "/api/v1/auth/login/oidc/begin", nil, nil, &resp, false); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// LoginWithOIDC 提交身份提供方回调中的授权码和state完成单点登录
+// 需要两步验证时返回的MFARequired为true，客户端令牌不变，需要再调用LoginWithMFA
+func (s *AuthService) LoginWithOIDC(ctx context.Context, code, state string) (*LoginResponse, error) {
+	var resp LoginResponse
+	body := map[string]string{"code": code, "state": state}
+	if err := s.client.do(ctx, http.MethodPost, "/api/v1/auth/login/oidc/finish", nil, body, &resp, false); err != nil {
+		return nil, err
+	}
+	if !resp.MFARequired {
+		s.client.setTokens(resp.Token, resp.RefreshToken)
+	}
+	return &resp, nil
+}
+
+// BeginStepUp 开始通行密钥二次验证，返回传给认证器的参数
+func (s *AuthService) BeginStepUp(ctx context.Context) (*WebAuthnAssertion, error) {
+	var resp WebAuthnAssertion
+	if err := s.client.Do(ctx, http.MethodPost, "/api/v1/auth/step-up/begin", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// FinishStepUp 提交认证器的验证结果完成二次验证，返回当前令牌二次验证的过期时间
+// 令牌变化（如自动刷新或重新登录）后需要重新进行二次验证
+func (s *AuthService) FinishStepUp(ctx context.Context, credential json.RawMessage) (time.Time, error) {
+	var resp struct {
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	body := map[string]interface{}{"credential": credential}
+	if err := s.client.Do(ctx, http.MethodPost, "/api/v1/auth/step-up/finish", nil, body, &resp); err != nil {
+		return time.Time{}, err
+	}
+	return resp.ExpiresAt, nil
+}
+
+// LoginWithEmail 邮箱验证码登录，成功后客户端使用返回的令牌
+func (s *AuthService) LoginWithEmail(ctx context.Context, email, code string) (*LoginResponse, error) {
+	var resp LoginResponse
+	body := map[string]string{"email": email, "code": code}
+	if err := s.client.do(ctx, http.MethodPost, "/api/v1/auth/login-with-email", nil, body, &resp, false); err != nil {
+		return nil, err
+	}
+	s.client.setTokens(resp.Token, resp.RefreshToken)
+	return &resp, nil
+}
+
+// Register 注册用户
+func (s *AuthService) Register(ctx context.Context, req *RegisterRequest) (*User, error) {
+	var resp struct {
+		User *User `json:"user"`
+	}
+	if err := s.client.do(ctx, http.MethodPost, "/api/v1/auth/register", nil, req, &resp, false); err != nil {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+// Me 查询当前用户
+func (s *AuthService) Me(ctx context.Context) (*User, error) {
+	var user User
+	if err := s.client.Do(ctx, http.MethodGet, "/api/v1/auth/me", nil, nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Refresh 使用刷新令牌换取新的访问令牌和刷新令牌，成功后客户端使用新的令牌
+// 刷新令牌失效时清除客户端的刷新令牌
+func (s *AuthService) Refresh(ctx context.Context) (*LoginResponse, error) {
+	var resp LoginResponse
+	body := map[string]string{"refresh_token": s.client.RefreshToken()}
+	if err := s.client.do(ctx, http.MethodPost, "/api/v1/auth/refresh", nil, body, &resp, false); err != nil {
+		if IsUnauthorized(err) {
+			s.client.setTokens(s.client.Token(), "")
+		}
+		return nil, err
+	}
+	s.client.setTokens(resp.Token, resp.RefreshToken)
+	return &resp, nil
+}
+
+// Logout 登出，当前会话在服务端注销，客户端清除令牌
+func (s *AuthService) Logout(ctx context.Context) error {
+	if err := s.client.Do(ctx, http.MethodPost, "/api/v1/auth/logout", nil, nil, nil); err != nil {
+		return err
+	}
+	s.client.setTokens("", "")
+	return nil
+}
+
+// ListSessions 查询当前用户的登录会话
+func (s *AuthService) ListSessions(ctx context.Context) ([]Session, error) {
+	var resp struct {
+		Sessions []Session `json:"sessions"`
+	}
+	if err := s.client.Do(ctx, http.MethodGet, "/api/v1/auth/sessions", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Sessions, nil
+}
+
+// RevokeSession 注销当前用户的一个登录会话
+func (s *AuthService) RevokeSession(ctx context.Context, sessionUUID string) error {
+	return s.client.Do(ctx, http.MethodDelete, "/api/v1/auth/sessions/"+url.PathEscape(sessionUUID), nil, nil, nil)
+}
+
+// RevokeOtherSessions 注销当前用户除当前会话以外的全部会话，返回注销的数量
+func (s *AuthService) RevokeOtherSessions(ctx context.Context) (int, error) {
+	var resp struct {
+		Revoked int `json:"revoked"`
+	}
+	if err := s.client.Do(ctx, http.MethodDelete, "/api/v1/auth/sessions", nil, nil, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Revoked, nil
+}
+
+// CreateAccessToken 创建个人访问令牌，令牌明文只在返回值中出现一次
+// 令牌可以通过 WithToken 使用，只能访问带权限检查的接口，且不能用来管理个人访问令牌
+func (s *AuthService) CreateAccessToken(ctx context.Context, req *CreateAccessTokenRequest) (*CreateAccessTokenResponse, error) {
+	var resp CreateAccessTokenResponse
+	if err := s.client.Do(ctx, http.MethodPost, "/api/v1/auth/tokens", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListAccessTokens 查询当前用户的个人访问令牌
+func (s *AuthService) ListAccessTokens(ctx context.Context) ([]AccessToken, error) {
+	var resp struct {
+		Tokens []AccessToken `json:"tokens"`
+	}
+	if err := s.client.Do(ctx, http.MethodGet, "/api/v1/auth/tokens", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tokens, nil
+}
+
+// RevokeAccessToken 撤销当前用户的一个个人访问令牌
+func (s *AuthService) RevokeAccessToken(ctx context.Context, tokenUUID string) error {
+	return s.client.Do(ctx, http.MethodDelete, "/api/v1/auth/tokens/"+url.PathEscape(tokenUUID), nil, nil, nil)
+}
+
+// ChangePassword 修改当前用户的登录密码，返回被注销的其他登录会话数量，当前会话不受影响
+// 新密码需要符合服务端的密码策略，不能与最近用过的密码重复
+func (s *AuthService) ChangePassword(ctx context.Context, oldPassword, newPassword string) (int, error) {
+	var resp struct {
+		RevokedSessions int `json:"revoked_sessions"`
+	}
+	body := map[string]string{"old_password": oldPassword, "new_password": newPassword}
+	if err := s.client.Do(ctx, http.MethodPut, "/api/v1/auth/password", nil, body, &resp); err != nil {
+		return 0, err
+	}
+	// 使用WithCredentials自动登录时，后续重新登录使用新密码
+	s.client.loginMu.Lock()
+	if s.client.canLogin() {
+		s.client.password = newPassword
+	}
+	s.client.loginMu.Unlock()
+	return resp.RevokedSessions, nil
+}
+
+// HasSecurityPIN 查询当前用户是否已设置安全密码
+func (s *AuthService) HasSecurityPIN(ctx context.Context) (bool, error) {
+	var resp struct {
+		HasSecurityPIN bool `json:"has_security_pin"`
+	}
+	if err := s.client.Do(ctx, http.MethodGet, "/api/v1/auth/security-pin-status", nil, nil, &resp); err != nil {
+		return false, err
+	}
+	return resp.HasSecurityPIN, nil
+}
+
+// ResetSecurityPIN 使用恢复助记词重置安全密码
+func (s *AuthService) ResetSecurityPIN(ctx context.Context, recoveryMnemonic, newSecurityPIN string) error {
+	body := map[string]string{"recovery_mnemonic": recoveryMnemonic, "new_security_pin": newSecurityPIN}
+	return s.client.Do(ctx, http.MethodPost, "/api/v1/auth/reset-password", nil, body, nil)
+}
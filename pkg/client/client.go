@@ -0,0 +1,417 @@
+// Package client 是 VaultHub API 的官方 Go 客户端
+//
+// 客户端负责处理统一响应格式（response.Response）、错误码映射、请求ID传递、
+// 令牌失效后自动重新登录，以及限流（CodeTooManyRequests）时按退避策略重试。
+//
+// 基本用法：
+//
+//	c, err := client.New("https://vaulthub.example.com", client.WithCredentials("alice", "password"))
+//	if err != nil {
+//		return err
+//	}
+//	secrets, err := c.Secrets.List(ctx, nil)
+//
+// 单元测试可以使用 clienttest 子包启动进程内的测试服务。
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader 请求ID头，与服务端 response.RequestIDKey 一致
+const RequestIDHeader = "X-Request-ID"
+
+// 默认参数
+const (
+	defaultTimeout      = time.Minute
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = 500 * time.Millisecond
+	maxRetryBackoff     = 10 * time.Second
+)
+
+// Client VaultHub API客户端，可安全地被多个goroutine共用
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+
+	maxRetries   int
+	retryBackoff time.Duration
+
+	mu           sync.RWMutex
+	token        string
+	refreshToken string
+	username     string
+	password     string
+	roleID       string
+	secretID     string
+	loginMu      sync.Mutex // 保证并发请求遇到令牌失效时只刷新或重新登录一次
+
+	// 按资源分组的接口
+	Auth       *AuthService
+	Secrets    *SecretsService
+	Keys       *KeysService
+	Audit      *AuditService
+	Statistics *StatisticsService
+	Configs    *ConfigsService
+	Machine    *MachineService
+}
+
+// Option 客户端配置项
+type Option func(*Client)
+
+// WithHTTPClient 使用自定义的http.Client（如配置代理、TLS或超时）
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken 使用已有的访问令牌（登录令牌或个人访问令牌）
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithRefreshToken 使用已有的刷新令牌，访问令牌过期或失效时自动换取新的令牌
+// 刷新令牌每次使用后轮换，需要保存时通过 Client.RefreshToken 读取最新的值
+func WithRefreshToken(refreshToken string) Option {
+	return func(c *Client) {
+		c.refreshToken = refreshToken
+	}
+}
+
+// WithCredentials 使用用户名和密码认证
+// 首次请求前自动登录，令牌过期或失效时优先使用刷新令牌，刷新失败再重新登录，然后重试请求
+func WithCredentials(username, password string) Option {
+	return func(c *Client) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// WithAppRole 使用服务账户的role_id和secret_id认证
+// 首次请求前自动登录，服务账户令牌过期或失效时重新登录，然后重试请求；服务账户只能访问 Client.Machine 下的接口
+func WithAppRole(roleID, secretID string) Option {
+	return func(c *Client) {
+		c.roleID = roleID
+		c.secretID = secretID
+	}
+}
+
+// WithRetry 设置限流时的最大重试次数和初始退避时间，maxRetries为0表示不重试
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryBackoff = backoff
+	}
+}
+
+// WithUserAgent 设置User-Agent，便于在审计日志中区分调用方
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// New 创建客户端，baseURL为服务地址（如 https://vaulthub.example.com）
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("无效的服务地址: %q", baseURL)
+	}
+
+	c := &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		httpClient:   &http.Client{Timeout: defaultTimeout},
+		userAgent:    "vaulthub-go-client",
+		maxRetries:   defaultMaxRetries,
+		retryBackoff: defaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.Auth = &AuthService{client: c}
+	c.Secrets = &SecretsService{client: c}
+	c.Keys = &KeysService{client: c}
+	c.Audit = &AuditService{client: c}
+	c.Statistics = &StatisticsService{client: c}
+	c.Configs = &ConfigsService{client: c}
+	c.Machine = &MachineService{client: c}
+	return c, nil
+}
+
+// Token 返回当前使用的访问令牌
+func (c *Client) Token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token
+}
+
+// SetToken 替换访问令牌
+func (c *Client) SetToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+}
+
+// RefreshToken 返回当前的刷新令牌，没有时返回空字符串
+func (c *Client) RefreshToken() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.refreshToken
+}
+
+// setTokens 同时替换访问令牌和刷新令牌
+func (c *Client) setTokens(token, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	c.refreshToken = refreshToken
+}
+
+// canLogin 是否配置了用户名密码或服务账户凭证
+func (c *Client) canLogin() bool {
+	return (c.username != "" && c.password != "") || c.useAppRole()
+}
+
+// useAppRole 是否以服务账户身份登录
+func (c *Client) useAppRole() bool {
+	return c.roleID != "" && c.secretID != ""
+}
+
+// canRenew 令牌失效时是否可以自动刷新或重新登录
+func (c *Client) canRenew() bool {
+	return c.canLogin() || c.RefreshToken() != ""
+}
+
+// envelope 服务端统一响应格式
+type envelope struct {
+	Code      int             `json:"code"`
+	Message   string          `json:"message"`
+	Data      json.RawMessage `json:"data"`
+	RequestID string          `json:"requestId"`
+}
+
+// Do 发送请求并把响应的data字段解析到out中
+// path为以 /api 开头的路径；query可为nil；body非nil时以JSON发送；out为nil时忽略data
+// 业务错误以 *Error 返回
+func (c *Client) Do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	return c.do(ctx, method, path, query, body, out, true)
+}
+
+// do 发送请求，auth为false时不携带令牌也不自动登录（用于登录接口本身）
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}, auth bool) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("序列化请求失败: %w", err)
+		}
+	}
+
+	// 同一次调用的重试和重新登录后的重发使用同一个请求ID，便于在服务端审计日志中关联
+	requestID := RequestIDFromContext(ctx)
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+
+	if auth && c.Token() == "" && c.canRenew() {
+		if err := c.renew(ctx, ""); err != nil {
+			return err
+		}
+	}
+
+	renewed := false
+	for attempt := 0; ; attempt++ {
+		token := ""
+		if auth {
+			token = c.Token()
+		}
+		err := c.send(ctx, method, path, query, payload, out, requestID, token)
+		if err == nil {
+			return nil
+		}
+
+		apiErr, ok := err.(*Error)
+		if !ok {
+			return err
+		}
+		switch {
+		case auth && !renewed && c.canRenew() && apiErr.isTokenError():
+			renewed = true
+			if err := c.renew(ctx, token); err != nil {
+				return err
+			}
+		case apiErr.Code == errors.CodeTooManyRequests && attempt < c.maxRetries:
+			if err := sleepContext(ctx, c.backoff(attempt, apiErr.retryAfter)); err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+	}
+}
+
+// send 发送一次HTTP请求
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, payload []byte, out interface{}, requestID, token string) error {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set(RequestIDHeader, requestID)
+	req.Header.Set("Accept", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求 %s 失败: %w", path, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return &Error{Code: errors.CodeTooManyRequests, Message: errors.GetMessage(errors.CodeTooManyRequests),
+				RequestID: requestID, HTTPStatus: resp.StatusCode, retryAfter: parseRetryAfter(resp.Header)}
+		}
+		return fmt.Errorf("解析响应失败（HTTP %d）: %w", resp.StatusCode, err)
+	}
+	if env.RequestID == "" {
+		env.RequestID = resp.Header.Get(RequestIDHeader)
+	}
+	if env.Code != errors.CodeSuccess {
+		return &Error{Code: env.Code, Message: env.Message, RequestID: env.RequestID,
+			HTTPStatus: resp.StatusCode, retryAfter: parseRetryAfter(resp.Header)}
+	}
+	if out != nil && len(env.Data) > 0 && string(env.Data) != "null" {
+		if err := json.Unmarshal(env.Data, out); err != nil {
+			return fmt.Errorf("解析响应数据失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// renew 换取新的访问令牌：服务账户重新登录；用户优先使用刷新令牌，没有或刷新失败时使用用户名密码重新登录
+// staleToken为发现失效的令牌，其他goroutine已经换了新令牌时直接返回
+func (c *Client) renew(ctx context.Context, staleToken string) error {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+	if current := c.Token(); current != "" && current != staleToken {
+		return nil
+	}
+	if c.useAppRole() {
+		_, err := c.Auth.LoginWithAppRole(ctx, c.roleID, c.secretID)
+		return err
+	}
+	if c.RefreshToken() != "" {
+		_, err := c.Auth.Refresh(ctx)
+		if err == nil || !c.canLogin() {
+			return err
+		}
+	}
+	resp, err := c.Auth.Login(ctx, c.username, c.password)
+	if err != nil {
+		return err
+	}
+	if resp.PasswordChangeRequired {
+		return ErrPasswordChangeRequired
+	}
+	if resp.MFARequired {
+		return ErrMFARequired
+	}
+	return nil
+}
+
+// backoff 计算第attempt次重试前的等待时间：优先使用服务端的Retry-After，否则指数退避加随机抖动
+func (c *Client) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, maxRetryBackoff)
+	}
+	wait := c.retryBackoff << attempt
+	if wait <= 0 || wait > maxRetryBackoff {
+		wait = maxRetryBackoff
+	}
+	return wait/2 + rand.N(wait/2+1)
+}
+
+// parseRetryAfter 解析Retry-After头（秒数）
+func parseRetryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// sleepContext 等待指定时间，context取消时提前返回
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// requestIDKey context中保存请求ID的键
+type requestIDKey struct{}
+
+// WithRequestID 返回携带请求ID的context，使用该context的请求会把ID通过 X-Request-ID 头传给服务端，
+// 用于把调用方的链路ID与服务端的审计日志关联；未设置时每次调用自动生成
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext 返回context中的请求ID
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// pageQuery 添加分页参数
+func pageQuery(query url.Values, page, pageSize int) {
+	if page > 0 {
+		query.Set("page", strconv.Itoa(page))
+	}
+	if pageSize > 0 {
+		query.Set("page_size", strconv.Itoa(pageSize))
+	}
+}
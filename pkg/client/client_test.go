@@ -0,0 +1,306 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cuihe500/vaulthub/pkg/client"
+	"github.com/cuihe500/vaulthub/pkg/client/clienttest"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+)
+
+// handleRefresh 注册刷新接口：提交的刷新令牌为valid时签发fresh令牌，否则返回令牌无效
+// 同时注册只接受fresh令牌的 /auth/me，返回刷新接口被调用的次数
+func handleRefresh(srv *clienttest.Server, valid, fresh string) *atomic.Int32 {
+	var calls atomic.Int32
+	srv.Handle(http.MethodPost, "/api/v1/auth/refresh", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var req struct {
+			RefreshToken string `json:"refresh_token"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.RefreshToken != valid {
+			clienttest.WriteError(w, r, errors.CodeInvalidToken, "")
+			return
+		}
+		// 放慢刷新，让并发请求在刷新期间都遇到令牌失效
+		time.Sleep(20 * time.Millisecond)
+		clienttest.WriteSuccess(w, r, client.LoginResponse{Token: fresh, RefreshToken: valid + "-rotated"})
+	})
+	srv.Handle(http.MethodGet, "/api/v1/auth/me", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+fresh {
+			clienttest.WriteError(w, r, errors.CodeTokenExpired, "")
+			return
+		}
+		clienttest.WriteSuccess(w, r, client.User{Username: "alice"})
+	})
+	return &calls
+}
+
+func TestAutoLogin(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	srv.AddUser("alice", "password", "")
+	c := srv.Client(client.WithCredentials("alice", "password"))
+	ctx := context.Background()
+
+	user, err := c.Auth.Me(ctx)
+	if err != nil {
+		t.Fatalf("首次请求前应自动登录: %v", err)
+	}
+	if user.Username != "alice" {
+		t.Fatalf("用户名 = %q，期望 alice", user.Username)
+	}
+	first := c.Token()
+
+	srv.ExpireTokens()
+	if _, err := c.Auth.Me(ctx); err != nil {
+		t.Fatalf("令牌失效后应重新登录并重试: %v", err)
+	}
+	if c.Token() == first {
+		t.Fatal("重新登录后令牌应更新")
+	}
+}
+
+func TestRefreshAfterTokenError(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	calls := handleRefresh(srv, "refresh-1", "fresh")
+	c := srv.Client(client.WithToken("stale"), client.WithRefreshToken("refresh-1"))
+
+	if _, err := c.Auth.Me(context.Background()); err != nil {
+		t.Fatalf("令牌过期后应使用刷新令牌并重试: %v", err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("刷新次数 = %d，期望 1", n)
+	}
+	if c.Token() != "fresh" || c.RefreshToken() != "refresh-1-rotated" {
+		t.Fatalf("刷新后令牌 = %q/%q，期望使用新签发的令牌", c.Token(), c.RefreshToken())
+	}
+}
+
+func TestRefreshFailureFallsBackToLogin(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	srv.AddUser("alice", "password", "")
+	var calls atomic.Int32
+	srv.Handle(http.MethodPost, "/api/v1/auth/refresh", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		clienttest.WriteError(w, r, errors.CodeInvalidToken, "")
+	})
+	c := srv.Client(client.WithToken("stale"), client.WithRefreshToken("revoked"), client.WithCredentials("alice", "password"))
+
+	if _, err := c.Auth.Me(context.Background()); err != nil {
+		t.Fatalf("刷新失败后应使用用户名密码重新登录: %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("刷新次数 = %d，期望 1", calls.Load())
+	}
+	if c.RefreshToken() != "" {
+		t.Fatalf("失效的刷新令牌应被清除，实际为 %q", c.RefreshToken())
+	}
+}
+
+func TestConcurrentRenewOnce(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	calls := handleRefresh(srv, "refresh-1", "fresh")
+	c := srv.Client(client.WithToken("stale"), client.WithRefreshToken("refresh-1"))
+
+	const workers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.Auth.Me(context.Background())
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("并发请求失败: %v", err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("并发遇到令牌失效时刷新了 %d 次，期望只刷新 1 次", n)
+	}
+}
+
+func TestRetryOnTooManyRequests(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	srv.AddUser("alice", "password", "")
+	c := srv.Client(client.WithCredentials("alice", "password"))
+	ctx := context.Background()
+	if _, err := c.Auth.Me(ctx); err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+
+	before := len(srv.RequestIDs())
+	srv.FailNext(errors.CodeTooManyRequests, errors.CodeTooManyRequests)
+	if _, err := c.Auth.Me(ctx); err != nil {
+		t.Fatalf("限流后应重试成功: %v", err)
+	}
+	ids := srv.RequestIDs()[before:]
+	if len(ids) != 3 {
+		t.Fatalf("请求次数 = %d，期望 3（两次限流加一次成功）", len(ids))
+	}
+	if ids[0] == "" || ids[0] != ids[1] || ids[1] != ids[2] {
+		t.Fatalf("同一次调用的重试应使用同一个请求ID: %v", ids)
+	}
+
+	// 默认最多重试3次，第4次限流后返回错误
+	srv.FailNext(errors.CodeTooManyRequests, errors.CodeTooManyRequests, errors.CodeTooManyRequests, errors.CodeTooManyRequests)
+	_, err := c.Auth.Me(ctx)
+	if !client.IsCode(err, errors.CodeTooManyRequests) {
+		t.Fatalf("超过重试次数应返回限流错误，实际: %v", err)
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	var calls atomic.Int32
+	srv.Handle(http.MethodGet, "/api/v1/auth/me", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// 网关层限流，响应不是统一格式
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			return
+		}
+		clienttest.WriteSuccess(w, r, client.User{Username: "alice"})
+	})
+	c := srv.Client(client.WithToken("token"))
+
+	start := time.Now()
+	if _, err := c.Auth.Me(context.Background()); err != nil {
+		t.Fatalf("HTTP 429 后应重试成功: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("应按 Retry-After 等待1秒后重试，实际等待 %v", elapsed)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("请求次数 = %d，期望 2", calls.Load())
+	}
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	srv.Handle(http.MethodGet, "/api/v1/auth/me", func(w http.ResponseWriter, r *http.Request) {
+		clienttest.WriteError(w, r, errors.CodeForbidden, "")
+	})
+	c := srv.Client(client.WithToken("token"))
+
+	ctx := client.WithRequestID(context.Background(), "trace-123")
+	_, err := c.Auth.Me(ctx)
+	var apiErr *client.Error
+	if !stderrors.As(err, &apiErr) {
+		t.Fatalf("期望 *client.Error，实际: %v", err)
+	}
+	if apiErr.RequestID != "trace-123" {
+		t.Fatalf("错误中的请求ID = %q，期望 trace-123", apiErr.RequestID)
+	}
+	if !strings.Contains(err.Error(), "request_id=trace-123") {
+		t.Fatalf("错误信息应包含请求ID: %v", err)
+	}
+
+	// 未设置时每次调用生成新的请求ID
+	_, _ = c.Auth.Me(context.Background())
+	_, _ = c.Auth.Me(context.Background())
+	ids := srv.RequestIDs()
+	if ids[0] != "trace-123" {
+		t.Fatalf("服务端收到的请求ID = %q，期望 trace-123", ids[0])
+	}
+	if ids[1] == "" || ids[1] == ids[2] {
+		t.Fatalf("未设置请求ID时每次调用应自动生成不同的ID: %v", ids[1:])
+	}
+}
+
+func TestEnvelopeDecoding(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	srv.AddUser("alice", "password", "123456")
+	c := srv.Client(client.WithCredentials("alice", "password"))
+	ctx := context.Background()
+
+	created, err := c.Secrets.Create(ctx, &client.CreateSecretRequest{
+		SecurityPIN: "123456", SecretName: "db", SecretType: client.SecretTypePassword, PlainData: "s3cret",
+	})
+	if err != nil {
+		t.Fatalf("创建秘密失败: %v", err)
+	}
+	decrypted, err := c.Secrets.Decrypt(ctx, created.SecretUUID, "123456")
+	if err != nil {
+		t.Fatalf("解密秘密失败: %v", err)
+	}
+	if decrypted.PlainData != "s3cret" || decrypted.SecretName != "db" {
+		t.Fatalf("解密结果 = %q/%q，期望 db/s3cret", decrypted.SecretName, decrypted.PlainData)
+	}
+	// data为null的响应不解析
+	if err := c.Secrets.Delete(ctx, created.SecretUUID); err != nil {
+		t.Fatalf("删除秘密失败: %v", err)
+	}
+
+	// 不是统一格式的响应返回普通错误，而不是业务错误
+	srv.Handle(http.MethodGet, "/api/v1/secrets", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<html>bad gateway</html>", http.StatusBadGateway)
+	})
+	_, err = c.Secrets.List(ctx, nil)
+	if err == nil || client.Code(err) != 0 || !strings.Contains(err.Error(), "HTTP 502") {
+		t.Fatalf("非统一格式响应应返回解析错误，实际: %v", err)
+	}
+}
+
+func TestErrorMapping(t *testing.T) {
+	srv := clienttest.NewServer()
+	defer srv.Close()
+	srv.AddUser("alice", "password", "123456")
+	authed := srv.Client(client.WithCredentials("alice", "password"))
+	ctx := context.Background()
+	if _, err := authed.Auth.Me(ctx); err != nil {
+		t.Fatalf("登录失败: %v", err)
+	}
+
+	_, err := authed.Secrets.Decrypt(ctx, "missing", "123456")
+	if !client.IsNotFound(err) {
+		t.Fatalf("不存在的秘密应返回资源不存在，实际: %v", err)
+	}
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.Code != errors.CodeResourceNotFound {
+		t.Fatalf("应能转换为 *errors.AppError，实际: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		code  int
+		check func(error) bool
+	}{
+		{"权限不足", errors.CodeForbidden, client.IsForbidden},
+		{"需要二次验证", errors.CodeStepUpRequired, client.IsStepUpRequired},
+		{"令牌无效", errors.CodeInvalidToken, client.IsUnauthorized},
+	}
+	// 只有令牌、无法刷新或重新登录的客户端直接返回令牌错误
+	c := srv.Client(client.WithToken("token"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv.FailNext(tt.code)
+			_, err := c.Auth.Me(ctx)
+			if !tt.check(err) || client.Code(err) != tt.code {
+				t.Fatalf("错误码 %d 映射错误: %v", tt.code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,471 @@
+// Package clienttest 提供进程内的 VaultHub 测试服务，用于在单元测试中使用 client 包而无需真实的服务端
+//
+// 测试服务在内存中模拟用户、令牌、秘密和系统配置，覆盖登录、秘密增删查、解密、模板渲染、
+// 创建密钥和系统配置等常用接口，响应格式和错误码与真实服务端一致。
+// 其他接口可以通过 Handle 注册自定义处理函数，FailNext 和 ExpireTokens 用于模拟限流和令牌过期。
+//
+//	srv := clienttest.NewServer()
+//	defer srv.Close()
+//	srv.AddUser("alice", "password", "123456")
+//	c := srv.Client(client.WithCredentials("alice", "password"))
+package clienttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/cuihe500/vaulthub/pkg/client"
+	"github.com/cuihe500/vaulthub/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// Server 进程内测试服务
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	users      map[string]*fakeUser   // 用户名 -> 用户
+	tokens     map[string]string      // 令牌 -> 用户名
+	secrets    map[string]*fakeSecret // 秘密UUID -> 秘密
+	configs    map[string]*client.ConfigItem
+	failures   []int // 待返回的错误码队列
+	requestIDs []string
+	handlers   map[string]http.HandlerFunc
+}
+
+// fakeUser 测试用户
+type fakeUser struct {
+	user        client.User
+	password    string
+	securityPIN string
+}
+
+// fakeSecret 测试秘密
+type fakeSecret struct {
+	secret    client.Secret
+	plainData string
+}
+
+// NewServer 启动测试服务
+func NewServer() *Server {
+	s := &Server{
+		users:    make(map[string]*fakeUser),
+		tokens:   make(map[string]string),
+		secrets:  make(map[string]*fakeSecret),
+		configs:  make(map[string]*client.ConfigItem),
+		handlers: make(map[string]http.HandlerFunc),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client 创建连接到测试服务的客户端，默认关闭限流重试的等待时间
+func (s *Server) Client(opts ...client.Option) *client.Client {
+	opts = append([]client.Option{client.WithHTTPClient(s.Server.Client()), client.WithRetry(3, time.Millisecond)}, opts...)
+	c, err := client.New(s.URL, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// AddUser 添加用户，securityPIN为空表示未设置安全密码
+func (s *Server) AddUser(username, password, securityPIN string) *client.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	u := &fakeUser{
+		user:        client.User{ID: uint(len(s.users) + 1), UUID: uuid.NewString(), Username: username, Status: 1, Role: "user", CreatedAt: now, UpdatedAt: now},
+		password:    password,
+		securityPIN: securityPIN,
+	}
+	s.users[username] = u
+	user := u.user
+	return &user
+}
+
+// AddSecret 为用户添加秘密
+func (s *Server) AddSecret(username string, req *client.CreateSecretRequest) *client.Secret {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[username]
+	if !ok {
+		panic("clienttest: 用户不存在: " + username)
+	}
+	secret := s.addSecretLocked(u, req)
+	return &secret
+}
+
+// SetConfig 设置系统配置
+func (s *Server) SetConfig(key, value, description string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configs[key] = &client.ConfigItem{ConfigKey: key, ConfigValue: value, Description: description}
+}
+
+// FailNext 让接下来的请求依次返回指定的错误码（如 errors.CodeTooManyRequests）
+func (s *Server) FailNext(codes ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, codes...)
+}
+
+// ExpireTokens 使所有已签发的令牌失效，用于测试自动重新登录
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = make(map[string]string)
+}
+
+// RequestIDs 返回收到的所有请求ID（按请求顺序）
+func (s *Server) RequestIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requestIDs...)
+}
+
+// Handle 为指定方法和路径注册处理函数，优先于内置实现
+// 处理函数可以使用 WriteSuccess 和 WriteError 写出统一格式的响应
+func (s *Server) Handle(method, path string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method+" "+path] = handler
+}
+
+// WriteSuccess 写出成功响应
+func WriteSuccess(w http.ResponseWriter, r *http.Request, data interface{}) {
+	writeEnvelope(w, r, errors.CodeSuccess, "成功", data)
+}
+
+// WriteError 写出业务错误响应，message为空时使用错误码的默认信息
+func WriteError(w http.ResponseWriter, r *http.Request, code int, message string) {
+	if message == "" {
+		message = errors.GetMessage(code)
+	}
+	writeEnvelope(w, r, code, message, nil)
+}
+
+// writeEnvelope 按服务端统一格式写出响应
+func writeEnvelope(w http.ResponseWriter, r *http.Request, code int, message string, data interface{}) {
+	requestID := r.Header.Get(client.RequestIDHeader)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(client.RequestIDHeader, requestID)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":      code,
+		"message":   message,
+		"data":      data,
+		"requestId": requestID,
+		"timestamp": time.Now().UnixMilli(),
+	})
+}
+
+// serveHTTP 分发请求
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requestIDs = append(s.requestIDs, r.Header.Get(client.RequestIDHeader))
+	if len(s.failures) > 0 {
+		code := s.failures[0]
+		s.failures = s.failures[1:]
+		s.mu.Unlock()
+		WriteError(w, r, code, "")
+		return
+	}
+	handler := s.handlers[r.Method+" "+r.URL.Path]
+	s.mu.Unlock()
+	if handler != nil {
+		handler(w, r)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1")
+	switch {
+	case r.Method == http.MethodPost && path == "/auth/login":
+		s.login(w, r)
+		return
+	case !strings.HasPrefix(r.URL.Path, "/api/v1/"):
+		WriteError(w, r, errors.CodeResourceNotFound, "接口不存在")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user := s.authenticate(r)
+	if user == nil {
+		WriteError(w, r, errors.CodeInvalidToken, "")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && path == "/auth/me":
+		WriteSuccess(w, r, user.user)
+	case r.Method == http.MethodPost && path == "/auth/logout":
+		delete(s.tokens, bearerToken(r))
+		WriteSuccess(w, r, nil)
+	case r.Method == http.MethodGet && path == "/auth/security-pin-status":
+		WriteSuccess(w, r, map[string]bool{"has_security_pin": user.securityPIN != ""})
+	case r.Method == http.MethodPost && path == "/keys/create":
+		s.createKey(w, r, user)
+	case r.Method == http.MethodGet && path == "/secrets":
+		s.listSecrets(w, r, user)
+	case r.Method == http.MethodPost && path == "/secrets":
+		s.createSecret(w, r, user)
+	case r.Method == http.MethodPost && path == "/secrets/render":
+		s.render(w, r, user)
+	case r.Method == http.MethodPost && strings.HasPrefix(path, "/secrets/") && strings.HasSuffix(path, "/decrypt"):
+		s.decryptSecret(w, r, user, strings.TrimSuffix(strings.TrimPrefix(path, "/secrets/"), "/decrypt"))
+	case r.Method == http.MethodDelete && strings.HasPrefix(path, "/secrets/"):
+		s.deleteSecret(w, r, user, strings.TrimPrefix(path, "/secrets/"))
+	case r.Method == http.MethodGet && path == "/configs":
+		s.listConfigs(w, r)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/configs/"):
+		s.getConfig(w, r, strings.TrimPrefix(path, "/configs/"))
+	case r.Method == http.MethodPut && strings.HasPrefix(path, "/configs/") && path != "/configs/batch":
+		s.updateConfig(w, r, strings.TrimPrefix(path, "/configs/"))
+	default:
+		WriteError(w, r, errors.CodeResourceNotFound, "接口不存在")
+	}
+}
+
+// login 用户名密码登录
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[req.Username]
+	if !ok || user.password != req.Password {
+		WriteError(w, r, errors.CodeInvalidCredentials, "用户名或密码错误")
+		return
+	}
+	token := uuid.NewString()
+	s.tokens[token] = req.Username
+	WriteSuccess(w, r, client.LoginResponse{Token: token, User: &user.user})
+}
+
+// authenticate 根据Bearer令牌查找用户，调用方需持有锁
+func (s *Server) authenticate(r *http.Request) *fakeUser {
+	username, ok := s.tokens[bearerToken(r)]
+	if !ok {
+		return nil
+	}
+	return s.users[username]
+}
+
+// createKey 创建加密密钥（设置安全密码）
+func (s *Server) createKey(w http.ResponseWriter, r *http.Request, user *fakeUser) {
+	var req struct {
+		SecurityPIN string `json:"security_pin"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if user.securityPIN != "" {
+		WriteError(w, r, errors.CodeResourceAlreadyExists, "加密密钥已存在")
+		return
+	}
+	user.securityPIN = req.SecurityPIN
+	now := time.Now().UTC()
+	WriteSuccess(w, r, client.CreateKeyResponse{
+		UserEncryptionKey: &client.EncryptionKey{UserUUID: user.user.UUID, KEKAlgorithm: "argon2id", DEKVersion: 1, DEKAlgorithm: "AES-256-GCM", RotationStatus: "none", CreatedAt: now, UpdatedAt: now},
+		RecoveryKey:       "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+	})
+}
+
+// listSecrets 列出当前用户的秘密
+func (s *Server) listSecrets(w http.ResponseWriter, r *http.Request, user *fakeUser) {
+	secretType := r.URL.Query().Get("secret_type")
+	list := client.SecretList{Secrets: []*client.Secret{}}
+	for _, fs := range s.secrets {
+		if fs.secret.UserUUID != user.user.UUID || (secretType != "" && string(fs.secret.SecretType) != secretType) {
+			continue
+		}
+		secret := fs.secret
+		list.Secrets = append(list.Secrets, &secret)
+	}
+	sort.Slice(list.Secrets, func(i, j int) bool { return list.Secrets[i].ID < list.Secrets[j].ID })
+	list.Total = int64(len(list.Secrets))
+	WriteSuccess(w, r, list)
+}
+
+// createSecret 创建秘密
+func (s *Server) createSecret(w http.ResponseWriter, r *http.Request, user *fakeUser) {
+	var req client.CreateSecretRequest
+	if !decodeBody(w, r, &req) || !s.checkPIN(w, r, user, req.SecurityPIN) {
+		return
+	}
+	if req.SecretName == "" || req.SecretType == "" || req.PlainData == "" {
+		WriteError(w, r, errors.CodeValidationFailed, "secret_name、secret_type、plain_data为必填字段")
+		return
+	}
+	WriteSuccess(w, r, s.addSecretLocked(user, &req))
+}
+
+// addSecretLocked 保存秘密，调用方需持有锁
+func (s *Server) addSecretLocked(user *fakeUser, req *client.CreateSecretRequest) client.Secret {
+	now := time.Now().UTC()
+	secret := client.Secret{
+		ID: uint(len(s.secrets) + 1), UserUUID: user.user.UUID, SecretUUID: uuid.NewString(),
+		SecretName: req.SecretName, SecretType: req.SecretType, Description: req.Description, DEKVersion: 1,
+		Metadata: req.Metadata, ValueChangedAt: &now, CreatedAt: now, UpdatedAt: now,
+	}
+	s.secrets[secret.SecretUUID] = &fakeSecret{secret: secret, plainData: req.PlainData}
+	return secret
+}
+
+// decryptSecret 解密秘密
+func (s *Server) decryptSecret(w http.ResponseWriter, r *http.Request, user *fakeUser, secretUUID string) {
+	var req struct {
+		SecurityPIN string `json:"security_pin"`
+	}
+	if !decodeBody(w, r, &req) || !s.checkPIN(w, r, user, req.SecurityPIN) {
+		return
+	}
+	fs, ok := s.secrets[secretUUID]
+	if !ok || fs.secret.UserUUID != user.user.UUID {
+		WriteError(w, r, errors.CodeResourceNotFound, "秘密不存在")
+		return
+	}
+	now := time.Now().UTC()
+	fs.secret.AccessCount++
+	fs.secret.LastAccessedAt = &now
+	WriteSuccess(w, r, client.DecryptedSecret{Secret: fs.secret, PlainData: fs.plainData})
+}
+
+// deleteSecret 删除秘密
+func (s *Server) deleteSecret(w http.ResponseWriter, r *http.Request, user *fakeUser, secretUUID string) {
+	fs, ok := s.secrets[secretUUID]
+	if !ok || fs.secret.UserUUID != user.user.UUID {
+		WriteError(w, r, errors.CodeResourceNotFound, "秘密不存在")
+		return
+	}
+	delete(s.secrets, secretUUID)
+	WriteSuccess(w, r, nil)
+}
+
+// render 渲染模板，secret函数按"文件夹/名称"查找秘密（不做服务端的静态引用检查）
+func (s *Server) render(w http.ResponseWriter, r *http.Request, user *fakeUser) {
+	var req struct {
+		SecurityPIN string `json:"security_pin"`
+		Template    string `json:"template"`
+	}
+	if !decodeBody(w, r, &req) || !s.checkPIN(w, r, user, req.SecurityPIN) {
+		return
+	}
+
+	refs := make(map[string]string)
+	funcs := template.FuncMap{"secret": func(path string, field ...string) (string, error) {
+		for _, fs := range s.secrets {
+			if fs.secret.UserUUID == user.user.UUID && fs.secret.Path() == path {
+				refs[path] = fs.secret.SecretUUID
+				if len(field) == 0 {
+					return fs.plainData, nil
+				}
+				var object map[string]interface{}
+				if err := json.Unmarshal([]byte(fs.plainData), &object); err != nil {
+					return "", err
+				}
+				value, _ := object[field[0]].(string)
+				return value, nil
+			}
+		}
+		return "", errors.New(errors.CodeResourceNotFound, "引用的秘密不存在: "+path)
+	}}
+	tmpl, err := template.New("render").Funcs(funcs).Parse(req.Template)
+	if err != nil {
+		WriteError(w, r, errors.CodeInvalidParam, "模板语法错误: "+err.Error())
+		return
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, nil); err != nil {
+		WriteError(w, r, errors.CodeInvalidParam, "模板渲染失败: "+err.Error())
+		return
+	}
+
+	result := client.RenderResult{Output: out.String(), Secrets: []*client.RenderedSecretRef{}}
+	for path, secretUUID := range refs {
+		result.Secrets = append(result.Secrets, &client.RenderedSecretRef{Path: path, SecretUUID: secretUUID})
+	}
+	sort.Slice(result.Secrets, func(i, j int) bool { return result.Secrets[i].Path < result.Secrets[j].Path })
+	WriteSuccess(w, r, result)
+}
+
+// listConfigs 列出系统配置
+func (s *Server) listConfigs(w http.ResponseWriter, r *http.Request) {
+	list := client.ConfigList{Configs: []client.ConfigItem{}}
+	for _, item := range s.configs {
+		list.Configs = append(list.Configs, *item)
+	}
+	sort.Slice(list.Configs, func(i, j int) bool { return list.Configs[i].ConfigKey < list.Configs[j].ConfigKey })
+	list.Total = int64(len(list.Configs))
+	WriteSuccess(w, r, list)
+}
+
+// getConfig 查询单个系统配置
+func (s *Server) getConfig(w http.ResponseWriter, r *http.Request, key string) {
+	item, ok := s.configs[key]
+	if !ok {
+		WriteError(w, r, errors.CodeResourceNotFound, "配置不存在")
+		return
+	}
+	WriteSuccess(w, r, item)
+}
+
+// updateConfig 更新单个系统配置
+func (s *Server) updateConfig(w http.ResponseWriter, r *http.Request, key string) {
+	var req struct {
+		ConfigValue string `json:"config_value"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	item, ok := s.configs[key]
+	if !ok {
+		WriteError(w, r, errors.CodeResourceNotFound, "配置不存在")
+		return
+	}
+	item.ConfigValue = req.ConfigValue
+	WriteSuccess(w, r, nil)
+}
+
+// checkPIN 校验安全密码
+func (s *Server) checkPIN(w http.ResponseWriter, r *http.Request, user *fakeUser, pin string) bool {
+	switch {
+	case user.securityPIN == "":
+		WriteError(w, r, errors.CodeSecurityPINNotSet, "")
+		return false
+	case pin == "":
+		WriteError(w, r, errors.CodeSecurityPINRequired, "")
+		return false
+	case pin != user.securityPIN:
+		WriteError(w, r, errors.CodeInvalidCredentials, "安全密码错误")
+		return false
+	}
+	return true
+}
+
+// decodeBody 解析JSON请求体，失败时写出错误响应
+func decodeBody(w http.ResponseWriter, r *http.Request, out interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(out); err != nil {
+		WriteError(w, r, errors.CodeInvalidJSON, "")
+		return false
+	}
+	return true
+}
+
+// bearerToken 从Authorization头取出令牌
+func bearerToken(r *http.Request) string {
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
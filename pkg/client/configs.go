@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ConfigsService 系统配置接口（需要管理员权限）
+type ConfigsService struct {
+	client *Client
+}
+
+// List 查询全部系统配置
+func (s *ConfigsService) List(ctx context.Context) (*ConfigList, error) {
+	var list ConfigList
+	if err := s.client.Do(ctx, http.MethodGet, "/api/v1/configs", nil, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// Get 查询单个系统配置
+func (s *ConfigsService) Get(ctx context.Context, key string) (*ConfigItem, error) {
+	var item ConfigItem
+	if err := s.client.Do(ctx, http.MethodGet, "/api/v1/configs/"+url.PathEscape(key), nil, nil, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Update 更新单个系统配置
+func (s *ConfigsService) Update(ctx context.Context, key, value string) error {
+	body := map[string]string{"config_value": value}
+	return s.client.Do(ctx, http.MethodPut, "/api/v1/configs/"+url.PathEscape(key), nil, body, nil)
+}
+
+// BatchUpdate 批量更新系统配置
+func (s *ConfigsService) BatchUpdate(ctx context.Context, values map[string]string) error {
+	type item struct {
+		ConfigKey   string `json:"config_key"`
+		ConfigValue string `json:"config_value"`
+	}
+	body := struct {
+		Configs []item `json:"configs"`
+	}{}
+	for key, value := range values {
+		body.Configs = append(body.Configs, item{ConfigKey: key, ConfigValue: value})
+	}
+	return s.client.Do(ctx, http.MethodPut, "/api/v1/configs/batch", nil, body, nil)
+}
+
+// Reload 从数据库重新加载系统配置
+func (s *ConfigsService) Reload(ctx context.Context) error {
+	return s.client.Do(ctx, http.MethodPost, "/api/v1/configs/reload", nil, nil, nil)
+}
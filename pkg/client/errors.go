@@ -0,0 +1,93 @@
+package client
+
+import (
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/cuihe500/vaulthub/pkg/errors"
+)
+
+// ErrMFARequired 账户启用了两步验证，无法使用用户名密码自动登录
+// 需要调用 Auth.Login 和 Auth.LoginWithMFA 完成登录，或直接使用 WithToken 传入令牌
+var ErrMFARequired = stderrors.New("账户需要两步验证，无法自动登录")
+
+// ErrPasswordChangeRequired 账户密码已超过最长使用期限，无法使用用户名密码自动登录
+// 需要调用 Auth.Login 和 Auth.ChangeExpiredPassword 修改密码后再登录
+var ErrPasswordChangeRequired = stderrors.New("账户密码已过期，需要修改密码后才能登录")
+
+// Error 服务端返回的业务错误
+// Code与 pkg/errors 中的错误码一致，可通过 errors.As 转换为 *errors.AppError
+type Error struct {
+	Code       int    // 业务错误码
+	Message    string // 错误信息
+	RequestID  string // 服务端请求ID，用于排查和审计日志关联
+	HTTPStatus int    // HTTP状态码
+
+	retryAfter time.Duration
+}
+
+// Error 实现error接口
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (code=%d, request_id=%s)", e.Message, e.Code, e.RequestID)
+}
+
+// Type 返回错误码对应的错误类型
+func (e *Error) Type() errors.ErrorType {
+	return errors.GetErrorType(e.Code)
+}
+
+// AppError 转换为服务端使用的 *errors.AppError
+func (e *Error) AppError() *errors.AppError {
+	return errors.New(e.Code, e.Message)
+}
+
+// Unwrap 使 errors.As(err, &appErr) 可以取得 *errors.AppError
+func (e *Error) Unwrap() error {
+	return e.AppError()
+}
+
+// isTokenError 是否为令牌缺失、无效或过期
+func (e *Error) isTokenError() bool {
+	switch e.Code {
+	case errors.CodeUnauthorized, errors.CodeInvalidToken, errors.CodeTokenExpired:
+		return true
+	}
+	return false
+}
+
+// Code 返回错误中的业务错误码，不是服务端业务错误时返回0
+func Code(err error) int {
+	var apiErr *Error
+	if stderrors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return 0
+}
+
+// IsCode 判断错误是否为指定的业务错误码
+func IsCode(err error, code int) bool {
+	return err != nil && Code(err) == code
+}
+
+// IsNotFound 判断是否为资源不存在
+func IsNotFound(err error) bool {
+	return IsCode(err, errors.CodeResourceNotFound)
+}
+
+// IsUnauthorized 判断是否为未认证或令牌无效
+func IsUnauthorized(err error) bool {
+	var apiErr *Error
+	return stderrors.As(err, &apiErr) && apiErr.isTokenError()
+}
+
+// IsStepUpRequired 判断是否需要先完成通行密钥二次验证（见 Auth.BeginStepUp）
+func IsStepUpRequired(err error) bool {
+	return IsCode(err, errors.CodeStepUpRequired)
+}
+
+// IsForbidden 判断是否为权限不足
+func IsForbidden(err error) bool {
+	var apiErr *Error
+	return stderrors.As(err, &apiErr) && apiErr.Type() == errors.TypeAuthorization
+}
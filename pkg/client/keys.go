@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// KeysService 加密密钥管理接口
+type KeysService struct {
+	client *Client
+}
+
+// Create 创建加密密钥并设置安全密码，返回的恢复助记词只出现一次，需要妥善保存
+func (s *KeysService) Create(ctx context.Context, securityPIN string) (*CreateKeyResponse, error) {
+	var resp CreateKeyResponse
+	body := map[string]string{"security_pin": securityPIN}
+	if err := s.client.Do(ctx, http.MethodPost, "/api/v1/keys/create", nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// VerifyRecovery 验证恢复助记词是否有效
+func (s *KeysService) VerifyRecovery(ctx context.Context, recoveryMnemonic string) (*VerifyRecoveryResponse, error) {
+	var resp VerifyRecoveryResponse
+	body := map[string]string{"recovery_mnemonic": recoveryMnemonic}
+	if err := s.client.Do(ctx, http.MethodPost, "/api/v1/keys/verify-recovery", nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Rotate 触发DEK轮换，秘密在后台重新加密，进度通过 RotationStatus 查询
+func (s *KeysService) Rotate(ctx context.Context, securityPIN string) (*RotateKeyResponse, error) {
+	var resp RotateKeyResponse
+	body := map[string]string{"security_pin": securityPIN}
+	if err := s.client.Do(ctx, http.MethodPost, "/api/v1/keys/rotate", nil, body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RotationStatus 查询密钥轮换进度
+func (s *KeysService) RotationStatus(ctx context.Context) (*RotationStatus, error) {
+	var status RotationStatus
+	if err := s.client.Do(ctx, http.MethodGet, "/api/v1/keys/rotation-status", nil, nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
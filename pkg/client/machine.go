@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// MachineService 服务账户访问秘密的接口，需要使用 WithAppRole 或服务账户令牌
+type MachineService struct {
+	client *Client
+}
+
+// ListSecrets 查询服务账户被授权的秘密（不含明文）
+func (s *MachineService) ListSecrets(ctx context.Context) ([]Secret, error) {
+	var resp struct {
+		Secrets []Secret `json:"secrets"`
+	}
+	if err := s.client.Do(ctx, http.MethodGet, "/api/v1/machine/secrets", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Secrets, nil
+}
+
+// GetSecret 解密服务账户被授权的秘密，返回明文，不需要安全密码
+func (s *MachineService) GetSecret(ctx context.Context, secretUUID string) (*DecryptedSecret, error) {
+	var secret DecryptedSecret
+	if err := s.client.Do(ctx, http.MethodGet, "/api/v1/machine/secrets/"+url.PathEscape(secretUUID), nil, nil, &secret); err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SecretsService 秘密管理接口
+type SecretsService struct {
+	client *Client
+}
+
+// List 查询秘密列表（不含明文），opts为nil时返回全部
+func (s *SecretsService) List(ctx context.Context, opts *ListSecretsOptions) (*SecretList, error) {
+	query := url.Values{}
+	if opts != nil {
+		if opts.SecretType != "" {
+			query.Set("secret_type", string(opts.SecretType))
+		}
+		pageQuery(query, opts.Page, opts.PageSize)
+	}
+
+	var list SecretList
+	if err := s.client.Do(ctx, http.MethodGet, "/api/v1/secrets", query, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// Create 创建秘密
+func (s *SecretsService) Create(ctx context.Context, req *CreateSecretRequest) (*Secret, error) {
+	var secret Secret
+	if err := s.client.Do(ctx, http.MethodPost, "/api/v1/secrets", nil, req, &secret); err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// Decrypt 解密秘密，返回明文
+func (s *SecretsService) Decrypt(ctx context.Context, secretUUID, securityPIN string) (*DecryptedSecret, error) {
+	var secret DecryptedSecret
+	body := map[string]string{"security_pin": securityPIN}
+	if err := s.client.Do(ctx, http.MethodPost, "/api/v1/secrets/"+url.PathEscape(secretUUID)+"/decrypt", nil, body, &secret); err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// Delete 删除秘密（进入回收站）
+func (s *SecretsService) Delete(ctx context.Context, secretUUID string) error {
+	return s.client.Do(ctx, http.MethodDelete, "/api/v1/secrets/"+url.PathEscape(secretUUID), nil, nil, nil)
+}
+
+// Render 渲染引用秘密的配置模板，所有引用在一次解锁中解密
+func (s *SecretsService) Render(ctx context.Context, template, securityPIN string) (*RenderResult, error) {
+	var result RenderResult
+	body := map[string]string{"template": template, "security_pin": securityPIN}
+	if err := s.client.Do(ctx, http.MethodPost, "/api/v1/secrets/render", nil, body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// trimSlashes 去掉首尾的"/"
+func trimSlashes(s string) string {
+	return strings.Trim(s, "/")
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// statDateLayout 统计接口的日期格式
+const statDateLayout = "2006-01-02"
+
+// StatisticsService 统计数据接口
+type StatisticsService struct {
+	client *Client
+}
+
+// User 查询历史统计
+func (s *StatisticsService) User(ctx context.Context, opts *StatisticsOptions) ([]*UserStatistics, error) {
+	query := url.Values{}
+	if opts != nil {
+		setIfNotEmpty(query, "user_uuid", opts.UserUUID)
+		setIfNotEmpty(query, "stat_type", opts.StatType)
+		setTime(query, "start_date", opts.StartDate, statDateLayout)
+		setTime(query, "end_date", opts.EndDate, statDateLayout)
+	}
+
+	var stats []*UserStatistics
+	if err := s.client.Do(ctx, http.MethodGet, "/api/v1/statistics/user", query, nil, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// Current 查询实时统计，userUUID为空时查询当前用户
+func (s *StatisticsService) Current(ctx context.Context, userUUID string) (*CurrentStatistics, error) {
+	query := url.Values{}
+	setIfNotEmpty(query, "user_uuid", userUUID)
+
+	var stats CurrentStatistics
+	if err := s.client.Do(ctx, http.MethodGet, "/api/v1/statistics/current", query, nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -0,0 +1,387 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 以下类型与服务端接口的JSON结构一致，独立定义以避免调用方依赖服务端内部包
+
+// User 用户信息
+type User struct {
+	ID          uint       `json:"id"`
+	UUID        string     `json:"uuid"`
+	Username    string     `json:"username"`
+	Status      int        `json:"status"` // 1活跃 2禁用 3锁定
+	Role        string     `json:"role"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// LoginResponse 登录响应
+// MFARequired为true时只返回MFAToken，需要调用 Auth.LoginWithMFA 提交验证码后才返回令牌
+type LoginResponse struct {
+	Token                 string   `json:"token"`
+	RefreshToken          string   `json:"refresh_token,omitempty"`
+	ExpiresIn             int64    `json:"expires_in,omitempty"` // 访问令牌有效期（秒）
+	SessionUUID           string   `json:"session_uuid,omitempty"`
+	User                  *User    `json:"user"`
+	MFARequired           bool     `json:"mfa_required,omitempty"`
+	MFAToken              string   `json:"mfa_token,omitempty"`
+	MFAEnrollmentRequired bool     `json:"mfa_enrollment_required,omitempty"`
+	BackupCodes           []string `json:"backup_codes,omitempty"`
+
+	PasswordChangeRequired bool   `json:"password_change_required,omitempty"` // 密码已过期，需要调用ChangeExpiredPassword修改
+	PasswordChangeToken    string `json:"password_change_token,omitempty"`
+}
+
+// Session 登录会话
+type Session struct {
+	UUID        string    `json:"uuid"`
+	DeviceLabel string    `json:"device_label"`
+	IPAddress   string    `json:"ip_address"`
+	UserAgent   string    `json:"user_agent"`
+	Current     bool      `json:"current"` // 是否为当前客户端使用的会话
+	CreatedAt   time.Time `json:"created_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// AccessToken 个人访问令牌（不含令牌明文）
+type AccessToken struct {
+	UUID        string     `json:"uuid"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"token_prefix"`
+	Scopes      []string   `json:"scopes"`
+	SecretUUIDs []string   `json:"secret_uuids,omitempty"`
+	AllowedIPs  []string   `json:"allowed_ips"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP  string     `json:"last_used_ip,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CreateAccessTokenRequest 创建个人访问令牌的参数
+type CreateAccessTokenRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`                 // 权限范围，如 secret:read
+	SecretUUIDs   []string `json:"secret_uuids,omitempty"` // 只允许访问的秘密，为空时可以访问全部秘密
+	ExpiresInDays int      `json:"expires_in_days"`
+	AllowedIPs    []string `json:"allowed_ips,omitempty"` // 允许使用的IP或CIDR，为空时不限制
+}
+
+// CreateAccessTokenResponse 创建个人访问令牌的结果
+type CreateAccessTokenResponse struct {
+	Token       string       `json:"token"` // 令牌明文，只返回这一次
+	AccessToken *AccessToken `json:"access_token"`
+}
+
+// ServiceAccount 服务账户
+type ServiceAccount struct {
+	UUID        string     `json:"uuid"`
+	OwnerUUID   string     `json:"owner_uuid"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	RoleID      string     `json:"role_id"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// AppRoleLoginResponse 服务账户登录结果
+type AppRoleLoginResponse struct {
+	Token          string          `json:"token"`
+	ExpiresIn      int64           `json:"expires_in"` // 令牌有效期（秒）
+	ServiceAccount *ServiceAccount `json:"service_account"`
+}
+
+// WebAuthnAssertion 通行密钥验证参数
+// Options原样传给浏览器的 navigator.credentials.get()，或测试中的 webauthntest.Authenticator.Assert
+type WebAuthnAssertion struct {
+	SessionToken string          `json:"session_token,omitempty"` // 只有无密码登录时返回
+	Options      json.RawMessage `json:"options"`
+}
+
+// OIDCLoginBegin 单点登录参数
+// AuthorizationURL在浏览器中打开，或测试中传给 oidctest.Issuer.Authorize
+type OIDCLoginBegin struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+}
+
+// RegisterRequest 注册请求
+type RegisterRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Email    string `json:"email"`
+	Code     string `json:"code"`               // 邮箱验证码
+	Nickname string `json:"nickname,omitempty"` // 昵称，默认使用用户名
+}
+
+// SecretType 秘密类型
+type SecretType string
+
+// 秘密类型
+const (
+	SecretTypeAPIKey       SecretType = "api_key"
+	SecretTypeDBCredential SecretType = "db_credential"
+	SecretTypeCertificate  SecretType = "certificate"
+	SecretTypeSSHKey       SecretType = "ssh_key"
+	SecretTypeToken        SecretType = "token"
+	SecretTypePassword     SecretType = "password"
+	SecretTypeOther        SecretType = "other"
+)
+
+// PasswordGenerator 密码生成规则
+type PasswordGenerator struct {
+	Length  int  `json:"length"`
+	Symbols bool `json:"symbols"`
+}
+
+// PasswordStrength 密码强度评分（由服务端计算）
+type PasswordStrength struct {
+	Score    int     `json:"score"`
+	Entropy  float64 `json:"entropy"`
+	Breached bool    `json:"breached,omitempty"`
+}
+
+// SecretMetadata 秘密元数据
+type SecretMetadata struct {
+	ExpiresAt *time.Time             `json:"expires_at,omitempty"`
+	Tags      []string               `json:"tags,omitempty"`
+	Folder    string                 `json:"folder,omitempty"` // 所属文件夹（多级用"/"分隔）
+	Generator *PasswordGenerator     `json:"generator,omitempty"`
+	Strength  *PasswordStrength      `json:"strength,omitempty"`
+	Extra     map[string]interface{} `json:"extra,omitempty"`
+}
+
+// Secret 秘密信息（不含明文）
+type Secret struct {
+	ID               uint            `json:"id"`
+	UserUUID         string          `json:"user_uuid"`
+	SecretUUID       string          `json:"secret_uuid"`
+	SecretName       string          `json:"secret_name"`
+	SecretType       SecretType      `json:"secret_type"`
+	Description      string          `json:"description,omitempty"`
+	DEKVersion       int             `json:"dek_version"`
+	Metadata         *SecretMetadata `json:"metadata,omitempty"`
+	CheckoutRequired bool            `json:"checkout_required"`
+	RotationRequired bool            `json:"rotation_required"`
+	ApprovalRequired bool            `json:"approval_required"`
+	ValueChangedAt   *time.Time      `json:"value_changed_at,omitempty"`
+	LastAccessedAt   *time.Time      `json:"last_accessed_at,omitempty"`
+	AccessCount      int64           `json:"access_count"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// Path 返回秘密在模板中的引用路径（"文件夹/名称"）
+func (s *Secret) Path() string {
+	if s.Metadata == nil {
+		return s.SecretName
+	}
+	folder := trimSlashes(s.Metadata.Folder)
+	if folder == "" {
+		return s.SecretName
+	}
+	return folder + "/" + s.SecretName
+}
+
+// DecryptedSecret 解密后的秘密
+type DecryptedSecret struct {
+	Secret
+	PlainData         string `json:"plain_data"`
+	AccessRequestUUID string `json:"access_request_uuid,omitempty"`
+}
+
+// CreateSecretRequest 创建秘密请求
+type CreateSecretRequest struct {
+	SecurityPIN string          `json:"security_pin"`
+	SecretName  string          `json:"secret_name"`
+	SecretType  SecretType      `json:"secret_type"`
+	PlainData   string          `json:"plain_data"`
+	Description string          `json:"description,omitempty"`
+	Metadata    *SecretMetadata `json:"metadata,omitempty"`
+}
+
+// ListSecretsOptions 秘密列表查询条件，不设置分页时返回全部（最多10000条）
+type ListSecretsOptions struct {
+	SecretType SecretType
+	Page       int
+	PageSize   int
+}
+
+// SecretList 秘密列表
+type SecretList struct {
+	Secrets    []*Secret `json:"secrets"`
+	Total      int64     `json:"total"`
+	Page       int       `json:"page"`
+	PageSize   int       `json:"page_size"`
+	TotalPages int       `json:"total_pages"`
+}
+
+// RenderedSecretRef 模板引用的秘密
+type RenderedSecretRef struct {
+	Path       string `json:"path"`
+	SecretUUID string `json:"secret_uuid"`
+}
+
+// RenderResult 模板渲染结果
+type RenderResult struct {
+	Output  string               `json:"output"`
+	Secrets []*RenderedSecretRef `json:"secrets"`
+}
+
+// EncryptionKey 用户加密密钥信息（不含密钥材料）
+type EncryptionKey struct {
+	ID                uint       `json:"id"`
+	UserUUID          string     `json:"user_uuid"`
+	KEKAlgorithm      string     `json:"kek_algorithm"`
+	DEKVersion        int        `json:"dek_version"`
+	DEKAlgorithm      string     `json:"dek_algorithm"`
+	LastRotationAt    *time.Time `json:"last_rotation_at,omitempty"`
+	RotationStatus    string     `json:"rotation_status"`
+	RotationStartedAt *time.Time `json:"rotation_started_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// CreateKeyResponse 创建加密密钥响应
+type CreateKeyResponse struct {
+	UserEncryptionKey *EncryptionKey `json:"user_encryption_key"`
+	RecoveryKey       string         `json:"recovery_key,omitempty"` // 恢复助记词，只返回一次
+}
+
+// VerifyRecoveryResponse 验证恢复密钥响应
+type VerifyRecoveryResponse struct {
+	Valid   bool   `json:"valid"`
+	Message string `json:"message"`
+}
+
+// RotateKeyResponse 密钥轮换响应
+type RotateKeyResponse struct {
+	UserEncryptionKey *EncryptionKey `json:"user_encryption_key"`
+	Message           string         `json:"message"`
+}
+
+// RotationStatus 密钥轮换进度
+type RotationStatus struct {
+	UserUUID        string     `json:"user_uuid"`
+	OldVersion      int        `json:"old_version"`
+	NewVersion      int        `json:"new_version"`
+	TotalSecrets    int64      `json:"total_secrets"`
+	MigratedSecrets int64      `json:"migrated_secrets"`
+	FailedSecrets   int64      `json:"failed_secrets"`
+	Status          string     `json:"status"` // running, completed, failed
+	StartedAt       time.Time  `json:"started_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	Error           string     `json:"error,omitempty"`
+}
+
+// AuditLog 审计日志
+type AuditLog struct {
+	UUID         string      `json:"uuid"`
+	UserUUID     string      `json:"user_uuid"`
+	Username     string      `json:"username"`
+	ActionType   string      `json:"action_type"`
+	ResourceType string      `json:"resource_type"`
+	ResourceUUID string      `json:"resource_uuid,omitempty"`
+	ResourceName string      `json:"resource_name,omitempty"`
+	Status       string      `json:"status"`
+	ErrorCode    int         `json:"error_code,omitempty"`
+	ErrorMessage string      `json:"error_message,omitempty"`
+	IPAddress    string      `json:"ip_address,omitempty"`
+	UserAgent    string      `json:"user_agent,omitempty"`
+	RequestID    string      `json:"request_id,omitempty"`
+	Details      interface{} `json:"details,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+// AuditLogOptions 审计日志查询条件，普通用户的UserUUID由服务端强制为自己
+type AuditLogOptions struct {
+	UserUUID     string
+	ActionType   string
+	ResourceType string
+	Status       string
+	StartTime    time.Time
+	EndTime      time.Time
+	Page         int
+	PageSize     int
+}
+
+// AuditLogList 审计日志列表
+type AuditLogList struct {
+	Total    int64       `json:"total"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"page_size"`
+	Logs     []*AuditLog `json:"logs"`
+}
+
+// SecretTypeStatistics 密钥类型统计
+type SecretTypeStatistics struct {
+	TotalSecrets int64            `json:"total_secrets"`
+	ByType       map[string]int64 `json:"by_type"`
+}
+
+// OperationStatistics 操作统计
+type OperationStatistics struct {
+	TotalOperations int64            `json:"total_operations"`
+	ByAction        map[string]int64 `json:"by_action"`
+}
+
+// UserStatistics 用户历史统计
+type UserStatistics struct {
+	UUID             string    `json:"uuid"`
+	UserUUID         string    `json:"user_uuid"`
+	StatDate         time.Time `json:"stat_date"`
+	StatType         string    `json:"stat_type"` // daily, weekly, monthly
+	TotalSecrets     int       `json:"total_secrets"`
+	APIKeyCount      int       `json:"api_key_count"`
+	PasswordCount    int       `json:"password_count"`
+	CertificateCount int       `json:"certificate_count"`
+	SSHKeyCount      int       `json:"ssh_key_count"`
+	PrivateKeyCount  int       `json:"private_key_count"`
+	OtherCount       int       `json:"other_count"`
+	CreateCount      int       `json:"create_count"`
+	UpdateCount      int       `json:"update_count"`
+	DeleteCount      int       `json:"delete_count"`
+	AccessCount      int       `json:"access_count"`
+	TotalOperations  int       `json:"total_operations"`
+	LoginCount       int       `json:"login_count"`
+	FailedLoginCount int       `json:"failed_login_count"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// StatisticsOptions 历史统计查询条件
+type StatisticsOptions struct {
+	UserUUID  string
+	StatType  string
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// CurrentStatistics 实时统计
+type CurrentStatistics struct {
+	TotalSecrets     int64 `json:"total_secrets"`
+	APIKeyCount      int64 `json:"api_key_count"`
+	PasswordCount    int64 `json:"password_count"`
+	CertificateCount int64 `json:"certificate_count"`
+	SSHKeyCount      int64 `json:"ssh_key_count"`
+	PrivateKeyCount  int64 `json:"private_key_count"`
+	OtherCount       int64 `json:"other_count"`
+	TodayOperations  int64 `json:"today_operations"`
+}
+
+// ConfigItem 系统配置项
+type ConfigItem struct {
+	ConfigKey   string `json:"config_key"`
+	ConfigValue string `json:"config_value"`
+	Description string `json:"description"`
+}
+
+// ConfigList 系统配置列表
+type ConfigList struct {
+	Configs []ConfigItem `json:"configs"`
+	Total   int64        `json:"total"`
+}
@@ -1,8 +1,12 @@
 package crypto
 
 import (
+	"crypto/rand"
+	"math/big"
 	"unicode"
 
+	"github.com/cuihe500/vaulthub/pkg/errors"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -11,6 +15,14 @@ const (
 	BcryptCost = 12
 	// MinPasswordLength 最小密码长度
 	MinPasswordLength = 8
+	// MaxPasswordBytes bcrypt只处理密码的前72个字节，更长的密码无法完整校验
+	MaxPasswordBytes = 72
+	// DefaultGeneratedPasswordLength 生成随机密码的默认长度
+	DefaultGeneratedPasswordLength = 24
+
+	passwordLetters = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	passwordDigits  = "23456789"
+	passwordSymbols = "!@#$%^&*()-_=+[]{}:,.?"
 )
 
 // HashPassword 使用bcrypt加密密码
@@ -29,12 +41,16 @@ func VerifyPassword(password, hash string) bool {
 }
 
 // ValidatePasswordStrength 验证密码强度
-// 要求：至少8位，包含大小写字母、数字
+// 要求：至少8位，包含大写字母、小写字母、数字、特殊字符中的至少三种
 func ValidatePasswordStrength(password string) bool {
 	if len(password) < MinPasswordLength {
 		return false
 	}
+	return PasswordCharClasses(password) >= 3
+}
 
+// PasswordCharClasses 统计密码包含的字符类别数（大写字母、小写字母、数字、特殊字符）
+func PasswordCharClasses(password string) int {
 	var (
 		hasUpper   bool
 		hasLower   bool
@@ -55,20 +71,55 @@ func ValidatePasswordStrength(password string) bool {
 		}
 	}
 
-	// 至少包含大小写字母和数字中的三种
 	count := 0
-	if hasUpper {
-		count++
+	for _, has := range []bool{hasUpper, hasLower, hasNumber, hasSpecial} {
+		if has {
+			count++
+		}
+	}
+	return count
+}
+
+// GeneratePassword 使用crypto/rand生成随机密码
+// 字符集去掉了容易混淆的 0/O、1/l/I；length为0时使用默认长度
+func GeneratePassword(length int, symbols bool) (string, error) {
+	if length == 0 {
+		length = DefaultGeneratedPasswordLength
 	}
-	if hasLower {
-		count++
+	if length < MinPasswordLength {
+		return "", errors.New(errors.CodeInvalidParam, "密码长度过短")
 	}
-	if hasNumber {
-		count++
+
+	charset := passwordLetters + passwordDigits
+	if symbols {
+		charset += passwordSymbols
 	}
-	if hasSpecial {
-		count++
+	max := big.NewInt(int64(len(charset)))
+
+	// 保证至少包含大小写字母和数字（需要时包含特殊字符），不满足时重新生成
+	for {
+		password := make([]byte, length)
+		for i := range password {
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return "", errors.WithMessage(errors.CodeCryptoError, "生成随机密码失败", err)
+			}
+			password[i] = charset[n.Int64()]
+		}
+		if ValidatePasswordStrength(string(password)) && (!symbols || containsAny(password, passwordSymbols)) {
+			return string(password), nil
+		}
 	}
+}
 
-	return count >= 3
+// containsAny 判断是否包含字符集中的任一字符
+func containsAny(data []byte, chars string) bool {
+	for _, b := range data {
+		for i := 0; i < len(chars); i++ {
+			if b == chars[i] {
+				return true
+			}
+		}
+	}
+	return false
 }
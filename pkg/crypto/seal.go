@@ -0,0 +1,111 @@
+package crypto
+
+import (
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+
+	"github.com/cuihe500/vaulthub/pkg/errors"
+)
+
+const (
+	// X25519KeySize X25519公钥和私钥长度（32字节）
+	X25519KeySize = 32
+
+	// sealInfo HKDF派生封装密钥时使用的上下文信息，修改会导致已封装的数据无法解开
+	sealInfo = "vaulthub-sealed-box-v1"
+)
+
+// GenerateX25519KeyPair 生成X25519密钥对
+// 用于把数据加密给指定用户（如紧急访问时封装授权人的DEK），私钥由调用方负责加密保存
+func GenerateX25519KeyPair() (publicKey, privateKey []byte, err error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, errors.WithMessage(errors.CodeCryptoError, "生成X25519密钥对失败", err)
+	}
+	return key.PublicKey().Bytes(), key.Bytes(), nil
+}
+
+// SealToPublicKey 使用接收方公钥封装数据（ECIES：X25519 + HKDF-SHA256 + AES-256-GCM）
+// 每次封装生成新的临时密钥对，只有持有对应私钥的一方能解开
+// 返回格式: [临时公钥(32)][nonce(12)][密文][tag(16)]
+func SealToPublicKey(plaintext, recipientPublicKey []byte) ([]byte, error) {
+	recipient, err := ecdh.X25519().NewPublicKey(recipientPublicKey)
+	if err != nil {
+		return nil, errors.WithMessage(errors.CodeInvalidParam, "无效的X25519公钥", err)
+	}
+
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, errors.WithMessage(errors.CodeEncryptionFailed, "生成临时密钥失败", err)
+	}
+	ephemeralPublic := ephemeral.PublicKey().Bytes()
+
+	key, err := deriveSealKey(ephemeral, recipient, ephemeralPublic, recipientPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	defer ClearBytes(key)
+
+	ciphertext, nonce, authTag, err := EncryptAESGCM(plaintext, key)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := make([]byte, 0, X25519KeySize+len(nonce)+len(ciphertext)+len(authTag))
+	sealed = append(sealed, ephemeralPublic...)
+	sealed = append(sealed, nonce...)
+	sealed = append(sealed, ciphertext...)
+	sealed = append(sealed, authTag...)
+	return sealed, nil
+}
+
+// OpenSealed 使用接收方私钥解开 SealToPublicKey 封装的数据
+func OpenSealed(sealed, recipientPrivateKey []byte) ([]byte, error) {
+	if len(sealed) < X25519KeySize+GCMNonceSize+GCMTagSize {
+		return nil, errors.New(errors.CodeDecryptionFailed, "封装数据长度无效")
+	}
+
+	private, err := ecdh.X25519().NewPrivateKey(recipientPrivateKey)
+	if err != nil {
+		return nil, errors.WithMessage(errors.CodeInvalidParam, "无效的X25519私钥", err)
+	}
+	ephemeralPublic := sealed[:X25519KeySize]
+	ephemeral, err := ecdh.X25519().NewPublicKey(ephemeralPublic)
+	if err != nil {
+		return nil, errors.WithMessage(errors.CodeDecryptionFailed, "无效的临时公钥", err)
+	}
+
+	key, err := deriveSealKey(private, ephemeral, ephemeralPublic, private.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	defer ClearBytes(key)
+
+	body := sealed[X25519KeySize:]
+	nonce := body[:GCMNonceSize]
+	ciphertext := body[GCMNonceSize : len(body)-GCMTagSize]
+	authTag := body[len(body)-GCMTagSize:]
+	return DecryptAESGCM(ciphertext, key, nonce, authTag)
+}
+
+// deriveSealKey 通过ECDH协商共享密钥并用HKDF派生AES密钥
+// 盐值绑定双方公钥，防止密文被挪用到其他接收方
+func deriveSealKey(private *ecdh.PrivateKey, public *ecdh.PublicKey, ephemeralPublic, recipientPublic []byte) ([]byte, error) {
+	shared, err := private.ECDH(public)
+	if err != nil {
+		return nil, errors.WithMessage(errors.CodeCryptoError, "密钥协商失败", err)
+	}
+	defer ClearBytes(shared)
+
+	salt := make([]byte, 0, len(ephemeralPublic)+len(recipientPublic))
+	salt = append(salt, ephemeralPublic...)
+	salt = append(salt, recipientPublic...)
+
+	key, err := hkdf.Key(sha256.New, shared, salt, sealInfo, AESKeySize)
+	if err != nil {
+		return nil, errors.WithMessage(errors.CodeKeyDerivationError, "封装密钥派生失败", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,285 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/cuihe500/vaulthub/pkg/errors"
+)
+
+// 分块流式加密（STREAM构造，Hoang et al. 2015）
+//
+// 密文格式: [头部][块0][块1]...[最后一块]
+//   - 头部（16字节）: 魔数"VHS1"(4) | 块大小uint32(4) | 随机Nonce前缀(7) | 保留(1)
+//   - 每块: AES-256-GCM(明文块) + 认证标签(16字节)
+//
+// 第i块的Nonce = Nonce前缀(7) | i(uint32大端) | 末块标志(1)，头部作为每块的附加认证数据。
+// 末块标志防止截断，块序号防止重排，因此整个流的完整性由逐块认证保证，
+// 解密时无需缓存全部密文即可边读边验证。
+const (
+	// StreamChunkSize 默认明文块大小（64KB）
+	StreamChunkSize = 64 * 1024
+	// StreamHeaderSize 流头部长度
+	StreamHeaderSize = 16
+
+	streamMagic        = "VHS1"
+	streamPrefixSize   = 7
+	streamMaxChunkSize = 16 * 1024 * 1024 // 解密时允许的最大块大小，防止恶意头部导致超大内存分配
+)
+
+// StreamCiphertextSize 计算明文长度对应的密文总长度
+func StreamCiphertextSize(plaintextSize int64, chunkSize int) int64 {
+	chunks := (plaintextSize + int64(chunkSize) - 1) / int64(chunkSize)
+	if chunks == 0 {
+		chunks = 1 // 空明文也会输出一个空的末块
+	}
+	return StreamHeaderSize + plaintextSize + chunks*GCMTagSize
+}
+
+// streamCipher 加解密共用的状态
+type streamCipher struct {
+	aead      cipher.AEAD
+	header    []byte
+	chunkSize int
+	counter   uint32
+	nonce     []byte
+}
+
+func newStreamCipher(key, header []byte, chunkSize int) (*streamCipher, error) {
+	if len(key) != AESKeySize {
+		return nil, errors.New(errors.CodeInvalidParam, "密钥长度必须是32字节")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.WithMessage(errors.CodeCryptoError, "创建AES cipher失败", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.WithMessage(errors.CodeCryptoError, "创建GCM模式失败", err)
+	}
+
+	nonce := make([]byte, GCMNonceSize)
+	copy(nonce, header[8:8+streamPrefixSize])
+	return &streamCipher{
+		aead:      aead,
+		header:    header,
+		chunkSize: chunkSize,
+		nonce:     nonce,
+	}, nil
+}
+
+// nextNonce 生成下一块的Nonce
+func (s *streamCipher) nextNonce(last bool) ([]byte, error) {
+	if s.counter == math.MaxUint32 {
+		return nil, errors.New(errors.CodeCryptoError, "数据流过长，块序号溢出")
+	}
+	binary.BigEndian.PutUint32(s.nonce[streamPrefixSize:], s.counter)
+	if last {
+		s.nonce[GCMNonceSize-1] = 1
+	} else {
+		s.nonce[GCMNonceSize-1] = 0
+	}
+	s.counter++
+	return s.nonce, nil
+}
+
+// StreamEncryptor 分块加密写入器
+// 写入的明文按块加密后写到底层Writer，必须调用Close写出末块，否则密文无法通过校验
+type StreamEncryptor struct {
+	*streamCipher
+	w      io.Writer
+	buf    []byte
+	closed bool
+}
+
+// NewStreamEncryptor 创建分块加密写入器，写入头部后返回
+// chunkSize为0时使用默认块大小
+func NewStreamEncryptor(w io.Writer, key []byte, chunkSize int) (*StreamEncryptor, error) {
+	if chunkSize == 0 {
+		chunkSize = StreamChunkSize
+	}
+	if chunkSize < 0 || chunkSize > streamMaxChunkSize {
+		return nil, errors.New(errors.CodeInvalidParam, "块大小无效")
+	}
+
+	header := make([]byte, StreamHeaderSize)
+	copy(header, streamMagic)
+	binary.BigEndian.PutUint32(header[4:8], uint32(chunkSize))
+	prefix, err := GenerateRandomBytes(streamPrefixSize)
+	if err != nil {
+		return nil, err
+	}
+	copy(header[8:], prefix)
+
+	sc, err := newStreamCipher(key, header, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, errors.WithMessage(errors.CodeEncryptionFailed, "写入流头部失败", err)
+	}
+
+	return &StreamEncryptor{
+		streamCipher: sc,
+		w:            w,
+		buf:          make([]byte, 0, chunkSize+GCMTagSize),
+	}, nil
+}
+
+// Write 写入明文
+func (e *StreamEncryptor) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New(errors.CodeEncryptionFailed, "加密流已关闭")
+	}
+
+	written := 0
+	for len(p) > 0 {
+		// 缓冲区满一块且还有后续数据时才输出，保证最后一块一定在Close中以末块标志加密
+		if len(e.buf) == e.chunkSize {
+			if err := e.flush(false); err != nil {
+				return written, err
+			}
+		}
+		n := copy(e.buf[len(e.buf):e.chunkSize], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// Close 加密并写出末块，不关闭底层Writer
+func (e *StreamEncryptor) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	err := e.flush(true)
+	ClearBytes(e.buf[:cap(e.buf)])
+	return err
+}
+
+func (e *StreamEncryptor) flush(last bool) error {
+	nonce, err := e.nextNonce(last)
+	if err != nil {
+		return err
+	}
+	sealed := e.aead.Seal(e.buf[:0], nonce, e.buf, e.header)
+	if _, err := e.w.Write(sealed); err != nil {
+		return errors.WithMessage(errors.CodeEncryptionFailed, "写入密文失败", err)
+	}
+	e.buf = e.buf[:0]
+	return nil
+}
+
+// StreamDecryptor 分块解密读取器
+// 每块在返回给调用方之前完成认证；遇到截断、重排或篡改时返回CodeDecryptionFailed错误
+type StreamDecryptor struct {
+	*streamCipher
+	r       io.Reader
+	buf     []byte // 密文缓冲区，多读1字节用于判断是否为末块
+	out     []byte // 明文缓冲区
+	pending []byte // 已解密待读取的明文
+	carry   bool   // buf[0]是否为上次预读的字节
+	done    bool
+	err     error // 认证失败后不再继续读取，之后每次都返回同一错误
+}
+
+// NewStreamDecryptor 读取并校验头部，返回分块解密读取器
+func NewStreamDecryptor(r io.Reader, key []byte) (*StreamDecryptor, error) {
+	header := make([]byte, StreamHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, errors.WithMessage(errors.CodeDecryptionFailed, "读取流头部失败", err)
+	}
+	if string(header[:4]) != streamMagic {
+		return nil, errors.New(errors.CodeDecryptionFailed, "不支持的加密流格式")
+	}
+	chunkSize := int(binary.BigEndian.Uint32(header[4:8]))
+	if chunkSize <= 0 || chunkSize > streamMaxChunkSize {
+		return nil, errors.New(errors.CodeDecryptionFailed, "加密流块大小无效")
+	}
+
+	sc, err := newStreamCipher(key, header, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	return &StreamDecryptor{
+		streamCipher: sc,
+		r:            r,
+		buf:          make([]byte, chunkSize+GCMTagSize+1),
+		out:          make([]byte, 0, chunkSize),
+	}, nil
+}
+
+// Read 读取明文
+func (d *StreamDecryptor) Read(p []byte) (int, error) {
+	for len(d.pending) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.readChunk(); err != nil {
+			d.err = err
+			return 0, err
+		}
+	}
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+	return n, nil
+}
+
+// Close 清除明文缓冲区，不关闭底层Reader
+func (d *StreamDecryptor) Close() error {
+	ClearBytes(d.out[:cap(d.out)])
+	d.pending = nil
+	d.done = true
+	return nil
+}
+
+// readChunk 读取并认证下一块
+func (d *StreamDecryptor) readChunk() error {
+	start := 0
+	if d.carry {
+		start = 1
+	}
+	n, err := io.ReadFull(d.r, d.buf[start:])
+	n += start
+	last := false
+	switch err {
+	case nil:
+	case io.EOF, io.ErrUnexpectedEOF:
+		last = true
+	default:
+		return errors.WithMessage(errors.CodeDecryptionFailed, "读取密文失败", err)
+	}
+
+	chunkLen := n
+	if !last {
+		chunkLen = n - 1 // 最后1字节属于下一块
+	}
+	if chunkLen < GCMTagSize {
+		return errors.New(errors.CodeDecryptionFailed, "密文被截断")
+	}
+
+	nonce, err := d.nextNonce(last)
+	if err != nil {
+		return err
+	}
+	plain, err := d.aead.Open(d.out[:0], nonce, d.buf[:chunkLen], d.header)
+	if err != nil {
+		return errors.New(errors.CodeDecryptionFailed, "解密失败或数据被篡改")
+	}
+
+	d.pending = plain
+	d.done = last
+	d.carry = !last
+	if !last {
+		d.buf[0] = d.buf[chunkLen]
+	}
+	return nil
+}
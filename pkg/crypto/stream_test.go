@@ -0,0 +1,330 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/cuihe500/vaulthub/pkg/errors"
+)
+
+const testChunkSize = 16
+
+// randomPlaintext 生成随机明文，允许长度为0
+func randomPlaintext(t *testing.T, size int) []byte {
+	t.Helper()
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func newStreamTestKey(t *testing.T) []byte {
+	t.Helper()
+	key, err := GenerateRandomBytes(AESKeySize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// encryptStream 加密明文，返回完整密文
+func encryptStream(t *testing.T, key, plaintext []byte, chunkSize int) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	enc, err := NewStreamEncryptor(&out, key, chunkSize)
+	if err != nil {
+		t.Fatalf("NewStreamEncryptor: %v", err)
+	}
+	if _, err := enc.Write(plaintext); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return out.Bytes()
+}
+
+// decryptStream 解密完整密文，返回解密错误之前已经读出的明文
+func decryptStream(key, ciphertext []byte) ([]byte, error) {
+	dec, err := NewStreamDecryptor(bytes.NewReader(ciphertext), key)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = dec.Close() }()
+	return io.ReadAll(dec)
+}
+
+// splitChunks 将密文拆分为头部和各个密文块
+func splitChunks(ciphertext []byte, chunkSize int) (header []byte, chunks [][]byte) {
+	header, rest := ciphertext[:StreamHeaderSize], ciphertext[StreamHeaderSize:]
+	for len(rest) > chunkSize+GCMTagSize {
+		chunks = append(chunks, rest[:chunkSize+GCMTagSize])
+		rest = rest[chunkSize+GCMTagSize:]
+	}
+	return header, append(chunks, rest)
+}
+
+func joinChunks(header []byte, chunks ...[]byte) []byte {
+	return bytes.Join(append([][]byte{header}, chunks...), nil)
+}
+
+func assertDecryptionFailed(t *testing.T, err error) {
+	t.Helper()
+	if appErr, ok := err.(*errors.AppError); !ok || appErr.Code != errors.CodeDecryptionFailed {
+		t.Fatalf("err = %v, want CodeDecryptionFailed", err)
+	}
+}
+
+func TestStreamRoundTrip(t *testing.T) {
+	key := newStreamTestKey(t)
+	for _, size := range []int{
+		0, 1,
+		testChunkSize - 1, testChunkSize, testChunkSize + 1,
+		2 * testChunkSize, 2*testChunkSize + 1,
+		10*testChunkSize - 1, 10 * testChunkSize,
+	} {
+		plaintext := randomPlaintext(t, size)
+		ciphertext := encryptStream(t, key, plaintext, testChunkSize)
+		if want := StreamCiphertextSize(int64(size), testChunkSize); int64(len(ciphertext)) != want {
+			t.Errorf("size %d: 密文长度 = %d, StreamCiphertextSize = %d", size, len(ciphertext), want)
+		}
+
+		got, err := decryptStream(key, ciphertext)
+		if err != nil {
+			t.Fatalf("size %d: 解密失败: %v", size, err)
+		}
+		if !bytes.Equal(got, plaintext) {
+			t.Fatalf("size %d: 解密结果不一致", size)
+		}
+	}
+}
+
+func TestStreamRoundTripDefaultChunkSize(t *testing.T) {
+	key := newStreamTestKey(t)
+	for _, size := range []int{0, StreamChunkSize, StreamChunkSize + 1} {
+		plaintext := bytes.Repeat([]byte{0xa5}, size)
+		ciphertext := encryptStream(t, key, plaintext, 0)
+		if chunkSize := binary.BigEndian.Uint32(ciphertext[4:8]); chunkSize != StreamChunkSize {
+			t.Fatalf("头部块大小 = %d", chunkSize)
+		}
+		got, err := decryptStream(key, ciphertext)
+		if err != nil || !bytes.Equal(got, plaintext) {
+			t.Fatalf("size %d: 解密失败: %v", size, err)
+		}
+	}
+}
+
+func TestStreamWriteSplitting(t *testing.T) {
+	key := newStreamTestKey(t)
+	plaintext := randomPlaintext(t, 5*testChunkSize+3)
+
+	// 任意的写入分段都得到相同的块划分
+	for _, step := range []int{1, 7, testChunkSize, testChunkSize + 5} {
+		var out bytes.Buffer
+		enc, err := NewStreamEncryptor(&out, key, testChunkSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for p := plaintext; len(p) > 0; {
+			n := min(step, len(p))
+			if written, err := enc.Write(p[:n]); err != nil || written != n {
+				t.Fatalf("Write = %d, %v", written, err)
+			}
+			p = p[n:]
+		}
+		if err := enc.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if int64(out.Len()) != StreamCiphertextSize(int64(len(plaintext)), testChunkSize) {
+			t.Fatalf("step %d: 密文长度 = %d", step, out.Len())
+		}
+		got, err := decryptStream(key, out.Bytes())
+		if err != nil || !bytes.Equal(got, plaintext) {
+			t.Fatalf("step %d: 解密失败: %v", step, err)
+		}
+
+		// 关闭后不能继续写入，重复关闭不会再写出末块
+		if _, err := enc.Write([]byte{1}); err == nil {
+			t.Fatal("关闭后仍能写入")
+		}
+		length := out.Len()
+		if err := enc.Close(); err != nil || out.Len() != length {
+			t.Fatalf("重复关闭: %v, 长度 %d -> %d", err, length, out.Len())
+		}
+	}
+}
+
+func TestStreamRejectsTruncation(t *testing.T) {
+	key := newStreamTestKey(t)
+	plaintext := randomPlaintext(t, 3*testChunkSize)
+	ciphertext := encryptStream(t, key, plaintext, testChunkSize)
+	header, chunks := splitChunks(ciphertext, testChunkSize)
+	if len(chunks) != 3 {
+		t.Fatalf("块数 = %d", len(chunks))
+	}
+
+	// 在块边界截断：剩下的最后一块不带末块标志，不能当作完整的流
+	for n := 0; n < len(chunks); n++ {
+		got, err := decryptStream(key, joinChunks(header, chunks[:n]...))
+		assertDecryptionFailed(t, err)
+		if n > 0 && !bytes.Equal(got, plaintext[:(n-1)*testChunkSize]) {
+			t.Fatalf("截断为%d块: 返回了未认证的明文", n)
+		}
+	}
+
+	// 在块内任意位置截断
+	for _, cut := range []int{len(ciphertext) - 1, len(ciphertext) - GCMTagSize, StreamHeaderSize + 1, StreamHeaderSize + testChunkSize + GCMTagSize + 3} {
+		_, err := decryptStream(key, ciphertext[:cut])
+		assertDecryptionFailed(t, err)
+	}
+
+	// 头部不完整
+	for _, cut := range []int{0, 4, StreamHeaderSize - 1} {
+		_, err := NewStreamDecryptor(bytes.NewReader(ciphertext[:cut]), key)
+		assertDecryptionFailed(t, err)
+	}
+}
+
+func TestStreamRejectsReorderedChunks(t *testing.T) {
+	key := newStreamTestKey(t)
+	plaintext := randomPlaintext(t, 3*testChunkSize+5)
+	header, chunks := splitChunks(encryptStream(t, key, plaintext, testChunkSize), testChunkSize)
+	c0, c1, c2, last := chunks[0], chunks[1], chunks[2], chunks[3]
+
+	tests := []struct {
+		name   string
+		chunks [][]byte
+		good   int // 出错前应读出的明文块数
+	}{
+		{"交换前两块", [][]byte{c1, c0, c2, last}, 0},
+		{"交换中间两块", [][]byte{c0, c2, c1, last}, 1},
+		{"重复一块", [][]byte{c0, c0, c1, c2, last}, 1},
+		{"删除中间一块", [][]byte{c0, c2, last}, 1},
+		{"末块提前", [][]byte{c0, last, c1, c2}, 1},
+		{"重复末块", [][]byte{c0, c1, c2, last, last}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decryptStream(key, joinChunks(header, tt.chunks...))
+			assertDecryptionFailed(t, err)
+			if !bytes.Equal(got, plaintext[:tt.good*testChunkSize]) {
+				t.Fatalf("出错前读出 %d 字节, want %d", len(got), tt.good*testChunkSize)
+			}
+		})
+	}
+
+	// 同一密钥加密的另一个流的块不能拼接进来（Nonce前缀和头部不同）
+	_, other := splitChunks(encryptStream(t, key, plaintext, testChunkSize), testChunkSize)
+	_, err := decryptStream(key, joinChunks(header, c0, other[1], c2, last))
+	assertDecryptionFailed(t, err)
+}
+
+func TestStreamRejectsTamperedHeader(t *testing.T) {
+	key := newStreamTestKey(t)
+	plaintext := randomPlaintext(t, 2*testChunkSize)
+	ciphertext := encryptStream(t, key, plaintext, testChunkSize)
+
+	// 头部是每块的附加认证数据，任何一个字节（包括保留字节）被修改都无法解密
+	for i := 0; i < StreamHeaderSize; i++ {
+		tampered := bytes.Clone(ciphertext)
+		tampered[i] ^= 0x01
+		got, err := decryptStream(key, tampered)
+		if err == nil || len(got) != 0 {
+			t.Fatalf("修改头部第%d字节: 读出 %d 字节, err = %v", i, len(got), err)
+		}
+		assertDecryptionFailed(t, err)
+	}
+
+	// 块大小超过上限时拒绝，不会按头部分配内存
+	tampered := bytes.Clone(ciphertext)
+	binary.BigEndian.PutUint32(tampered[4:8], streamMaxChunkSize+1)
+	_, err := NewStreamDecryptor(bytes.NewReader(tampered), key)
+	assertDecryptionFailed(t, err)
+}
+
+func TestStreamRejectsTamperedChunk(t *testing.T) {
+	key := newStreamTestKey(t)
+	plaintext := randomPlaintext(t, 2*testChunkSize+1)
+	ciphertext := encryptStream(t, key, plaintext, testChunkSize)
+
+	for _, pos := range []int{StreamHeaderSize, StreamHeaderSize + testChunkSize, len(ciphertext) - 1} {
+		tampered := bytes.Clone(ciphertext)
+		tampered[pos] ^= 0x80
+		got, err := decryptStream(key, tampered)
+		assertDecryptionFailed(t, err)
+		// 被篡改的块及其后的明文不会返回
+		if want := (pos - StreamHeaderSize) / (testChunkSize + GCMTagSize) * testChunkSize; len(got) != want {
+			t.Fatalf("篡改第%d字节: 读出 %d 字节, want %d", pos, len(got), want)
+		}
+	}
+
+	// 使用其他密钥无法解密
+	_, err := decryptStream(newStreamTestKey(t), ciphertext)
+	assertDecryptionFailed(t, err)
+}
+
+func TestStreamRejectsTrailingData(t *testing.T) {
+	key := newStreamTestKey(t)
+	for _, size := range []int{0, testChunkSize - 1, testChunkSize, 2 * testChunkSize} {
+		plaintext := randomPlaintext(t, size)
+		ciphertext := encryptStream(t, key, plaintext, testChunkSize)
+
+		// 末块之后追加任意长度的数据（包括另一个完整的块）都应被拒绝
+		_, chunks := splitChunks(ciphertext, testChunkSize)
+		for _, trailing := range [][]byte{{0}, make([]byte, GCMTagSize), make([]byte, testChunkSize+GCMTagSize+1), chunks[len(chunks)-1]} {
+			_, err := decryptStream(key, append(bytes.Clone(ciphertext), trailing...))
+			assertDecryptionFailed(t, err)
+		}
+	}
+}
+
+func TestStreamDecryptorStopsAfterError(t *testing.T) {
+	key := newStreamTestKey(t)
+	plaintext := randomPlaintext(t, 4*testChunkSize)
+	ciphertext := encryptStream(t, key, plaintext, testChunkSize)
+	tampered := bytes.Clone(ciphertext)
+	tampered[StreamHeaderSize+testChunkSize+GCMTagSize] ^= 0x01
+
+	dec, err := NewStreamDecryptor(bytes.NewReader(tampered), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = dec.Close() }()
+	if _, err := io.ReadFull(dec, make([]byte, testChunkSize)); err != nil {
+		t.Fatalf("第一块: %v", err)
+	}
+
+	// 出错后继续读取仍然返回错误，不会跳过被篡改的块返回后续明文
+	for i := 0; i < 4; i++ {
+		n, err := dec.Read(make([]byte, testChunkSize))
+		if n != 0 {
+			t.Fatalf("第%d次读取: 出错后仍返回了 %d 字节", i, n)
+		}
+		assertDecryptionFailed(t, err)
+	}
+}
+
+func TestStreamInvalidParams(t *testing.T) {
+	var out bytes.Buffer
+	for _, chunkSize := range []int{-1, streamMaxChunkSize + 1} {
+		if _, err := NewStreamEncryptor(&out, newStreamTestKey(t), chunkSize); err == nil {
+			t.Fatalf("块大小 %d 未被拒绝", chunkSize)
+		}
+	}
+	if _, err := NewStreamEncryptor(&out, make([]byte, 16), testChunkSize); err == nil {
+		t.Fatal("16字节密钥未被拒绝")
+	}
+
+	ciphertext := encryptStream(t, newStreamTestKey(t), []byte("data"), testChunkSize)
+	if _, err := NewStreamDecryptor(bytes.NewReader(ciphertext), make([]byte, 16)); err == nil {
+		t.Fatal("16字节密钥未被拒绝")
+	}
+	zero := bytes.Clone(ciphertext)
+	binary.BigEndian.PutUint32(zero[4:8], 0)
+	_, err := NewStreamDecryptor(bytes.NewReader(zero), newStreamTestKey(t))
+	assertDecryptionFailed(t, err)
+}
@@ -0,0 +1,236 @@
+package crypto
+
+import (
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/cuihe500/vaulthub/pkg/errors"
+)
+
+const (
+	// MaxStrengthScore 密码强度最高等级
+	MaxStrengthScore = 4
+	// WeakStrengthScore 强度等级低于该值视为弱密码
+	WeakStrengthScore = 2
+
+	// fingerprintInfo HKDF派生指纹密钥时使用的上下文信息，修改会导致已有指纹全部失效
+	fingerprintInfo = "vaulthub-password-fingerprint-v1"
+)
+
+// strengthThresholds 各强度等级对应的最低熵值（bit），索引i表示达到等级i+1
+var strengthThresholds = [MaxStrengthScore]float64{28, 36, 60, 80}
+
+// commonPasswords 常见弱密码和高频单词（小写，去掉了leet替换）
+// 整个密码或其中的片段命中时按字典攻击的代价计算熵值
+var commonPasswords = []string{
+	"password", "passwd", "qwerty", "qwertyuiop", "asdfgh", "zxcvbnm", "qazwsx", "1qaz2wsx",
+	"admin", "administrator", "root", "test", "guest", "login", "welcome", "letmein", "changeme",
+	"default", "secret", "master", "hello", "iloveyou", "monkey", "dragon", "shadow", "sunshine",
+	"princess", "football", "baseball", "soccer", "superman", "batman", "starwars", "trustno1",
+	"freedom", "whatever", "computer", "internet", "michael", "jennifer", "charlie", "access",
+	"flower", "loveme", "abc123", "123456", "12345678", "123123", "111111", "666666", "888888",
+	"000000", "5201314", "woaini", "aa123456", "vaulthub", "summer", "winter", "spring", "autumn",
+}
+
+// keyboardRows 键盘横排，相邻按键连续输入视为键盘模式
+var keyboardRows = []string{"1234567890", "qwertyuiop", "asdfghjkl", "zxcvbnm"}
+
+// leetReplacer 把常见的leet替换还原为字母，用于字典匹配
+var leetReplacer = strings.NewReplacer(
+	"@", "a", "4", "a", "0", "o", "1", "l", "!", "i", "3", "e", "$", "s", "5", "s", "7", "t", "+", "t",
+)
+
+// StrengthResult 密码强度评估结果
+type StrengthResult struct {
+	Score   int     // 强度等级0-4
+	Entropy float64 // 估算熵值（bit）
+}
+
+// EstimatePasswordStrength 估算密码强度
+// 参考zxcvbn的思路：按字符集大小计算每个字符的熵值，再对重复、连续序列、键盘模式、
+// 常见密码（含leet替换）和年份等可预测片段降低熵值，最后按熵值划分0-4级
+func EstimatePasswordStrength(password string) StrengthResult {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return StrengthResult{}
+	}
+
+	perChar := math.Log2(float64(charsetSize(runes)))
+	costs := make([]float64, len(runes))
+	for i := range costs {
+		costs[i] = perChar
+	}
+
+	lower := []rune(strings.ToLower(password))
+	markRuns(lower, costs)
+	markKeyboard(lower, costs)
+	markYears(lower, costs)
+	markDictionary(password, costs)
+
+	entropy := 0.0
+	for _, c := range costs {
+		entropy += c
+	}
+	entropy = math.Round(entropy*10) / 10
+
+	score := 0
+	for score < MaxStrengthScore && entropy >= strengthThresholds[score] {
+		score++
+	}
+	return StrengthResult{Score: score, Entropy: entropy}
+}
+
+// charsetSize 根据出现的字符类别估算字符集大小
+func charsetSize(runes []rune) int {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range runes {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+
+	size := 0
+	if lower {
+		size += 26
+	}
+	if upper {
+		size += 26
+	}
+	if digit {
+		size += 10
+	}
+	if symbol {
+		size += 33
+	}
+	if other {
+		size += 100
+	}
+	return size
+}
+
+// markRuns 重复字符（aaa）和连续序列（abc、321）除第一个字符外每个字符只计1bit
+func markRuns(runes []rune, costs []float64) {
+	for i := 2; i < len(runes); i++ {
+		delta := runes[i] - runes[i-1]
+		if delta == runes[i-1]-runes[i-2] && delta >= -1 && delta <= 1 {
+			discount(costs, i-1, i+1, 1)
+		}
+	}
+}
+
+// markKeyboard 键盘横排上连续相邻的按键（qwer、asdf）除第一个字符外每个字符只计1bit
+func markKeyboard(runes []rune, costs []float64) {
+	adjacent := func(a, b rune) bool {
+		for _, row := range keyboardRows {
+			ia, ib := strings.IndexRune(row, a), strings.IndexRune(row, b)
+			if ia >= 0 && ib >= 0 && (ia-ib == 1 || ib-ia == 1) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := 3; i < len(runes); i++ {
+		if adjacent(runes[i-3], runes[i-2]) && adjacent(runes[i-2], runes[i-1]) && adjacent(runes[i-1], runes[i]) {
+			discount(costs, i-2, i+1, 1)
+		}
+	}
+}
+
+// markYears 1900-2099之间的四位年份整体只计约8bit
+func markYears(runes []rune, costs []float64) {
+	for i := 0; i+4 <= len(runes); i++ {
+		s := string(runes[i : i+4])
+		if (strings.HasPrefix(s, "19") || strings.HasPrefix(s, "20")) && isDigits(s) &&
+			(i == 0 || !unicode.IsDigit(runes[i-1])) && (i+4 == len(runes) || !unicode.IsDigit(runes[i+4])) {
+			discount(costs, i, i+1, 8)
+			discount(costs, i+1, i+4, 0)
+			i += 3
+		}
+	}
+}
+
+// markDictionary 命中常见密码或单词的片段整体只计字典大小对应的熵值
+// 大小写变化和leet替换各额外计1bit
+func markDictionary(password string, costs []float64) {
+	for i := 0; i < len(password); i++ {
+		if password[i] >= unicode.MaxASCII {
+			// 字典只包含ASCII单词，含非ASCII字符时字节位置和字符位置无法对应
+			return
+		}
+	}
+	lower := strings.ToLower(password)
+	leet := leetReplacer.Replace(lower)
+
+	wordCost := math.Log2(float64(len(commonPasswords)))
+	for _, text := range []string{lower, leet} {
+		for _, word := range commonPasswords {
+			for offset := 0; ; {
+				idx := strings.Index(text[offset:], word)
+				if idx < 0 {
+					break
+				}
+				start := offset + idx
+				end := start + len(word)
+				cost := wordCost
+				if password[start:end] != lower[start:end] {
+					cost++
+				}
+				if lower[start:end] != word {
+					cost++
+				}
+				discount(costs, start, start+1, cost)
+				discount(costs, start+1, end, 0)
+				offset = end
+			}
+		}
+	}
+}
+
+// discount 把[start,end)范围内每个字符的熵值降低到不超过limit
+func discount(costs []float64, start, end int, limit float64) {
+	for i := start; i < end; i++ {
+		if costs[i] > limit {
+			costs[i] = limit
+		}
+	}
+}
+
+// isDigits 判断字符串是否全部为数字
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// PasswordFingerprint 计算密码指纹（HMAC-SHA256，十六进制）
+// 指纹密钥由用户DEK通过HKDF派生，同一用户相同的密码得到相同的指纹，可用于检测重复密码；
+// 不同用户的指纹互不相关，服务端也无法在没有DEK的情况下由指纹反推密码
+func PasswordFingerprint(dek, password []byte) (string, error) {
+	key, err := hkdf.Key(sha256.New, dek, nil, fingerprintInfo, AESKeySize)
+	if err != nil {
+		return "", errors.WithMessage(errors.CodeKeyDerivationError, "指纹密钥派生失败", err)
+	}
+	defer ClearBytes(key)
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(password)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
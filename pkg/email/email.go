@@ -3,6 +3,7 @@ package email
 import (
 	"crypto/tls"
 	"fmt"
+	"html"
 	"net/smtp"
 	"strings"
 
@@ -250,3 +251,161 @@ func (s *Sender) SendPasswordResetLink(to, resetURL string, expiryMinutes int) e
 
 	return s.SendMail([]string{to}, subject, body)
 }
+
+// SendAccessApprovalRequest 发送高敏感秘密访问审批邮件
+// to: 审批人邮箱
+// requester: 申请人用户名
+// secretName: 秘密名称
+// reason: 申请原因
+// approvalURL: 审批页面链接
+// expiryHours: 审批有效期（小时）
+func (s *Sender) SendAccessApprovalRequest(to, requester, secretName, reason, approvalURL string, expiryHours int) error {
+	subject := "VaultHub - 秘密访问审批"
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #4CAF50; color: white; padding: 10px; text-align: center; }
+        .content { background-color: #f9f9f9; padding: 20px; border-radius: 5px; margin-top: 20px; }
+        .button { display: inline-block; padding: 12px 24px; background-color: #4CAF50; color: white; text-decoration: none; border-radius: 4px; margin: 20px 0; }
+        .button:hover { background-color: #45a049; }
+        .reason { background-color: #ffffff; border-left: 4px solid #4CAF50; padding: 10px; margin: 15px 0; }
+        .warning { background-color: #fff3cd; border-left: 4px solid #ffc107; padding: 10px; margin: 15px 0; }
+        .footer { text-align: center; margin-top: 20px; font-size: 12px; color: #666; }
+        .link { word-break: break-all; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h2>VaultHub 密钥管理系统</h2>
+        </div>
+        <div class="content">
+            <p>您好，</p>
+            <p>用户 <strong>%s</strong> 申请解密高敏感秘密 <strong>%s</strong>，申请原因：</p>
+            <div class="reason">%s</div>
+            <p>请点击下面的按钮查看并审批：</p>
+            <div style="text-align: center;">
+                <a href="%s" class="button">查看申请</a>
+            </div>
+            <p>或者复制以下链接到浏览器打开：</p>
+            <p class="link">%s</p>
+            <div class="warning">
+                <p style="margin: 0;"><strong>注意：</strong></p>
+                <ul style="margin: 5px 0;">
+                    <li>申请在 <strong>%d小时</strong> 内未审批将自动失效</li>
+                    <li>链接仅对您本人有效，请勿转发</li>
+                    <li>如果您不了解该申请的背景，请拒绝或联系申请人确认</li>
+                </ul>
+            </div>
+        </div>
+        <div class="footer">
+            <p>此邮件由系统自动发送，请勿回复。</p>
+            <p>&copy; 2024 VaultHub. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`, html.EscapeString(requester), html.EscapeString(secretName), html.EscapeString(reason), approvalURL, approvalURL, expiryHours)
+
+	return s.SendMail([]string{to}, subject, body)
+}
+
+// SendEmergencyAccessNotice 发送紧急访问通知邮件
+// to: 收件人邮箱
+// title: 通知标题
+// message: 通知内容（纯文本，会进行HTML转义）
+func (s *Sender) SendEmergencyAccessNotice(to, title, message string) error {
+	subject := "VaultHub - " + title
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #4CAF50; color: white; padding: 10px; text-align: center; }
+        .content { background-color: #f9f9f9; padding: 20px; border-radius: 5px; margin-top: 20px; }
+        .warning { background-color: #fff3cd; border-left: 4px solid #ffc107; padding: 10px; margin: 15px 0; }
+        .footer { text-align: center; margin-top: 20px; font-size: 12px; color: #666; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h2>VaultHub 密钥管理系统</h2>
+        </div>
+        <div class="content">
+            <p>您好，</p>
+            <p><strong>%s</strong></p>
+            <p>%s</p>
+            <div class="warning">
+                <p style="margin: 0;">如果您对此不知情，请立即登录 VaultHub 在"紧急访问"中拒绝申请或撤销授权。</p>
+            </div>
+        </div>
+        <div class="footer">
+            <p>此邮件由系统自动发送，请勿回复。</p>
+            <p>&copy; 2024 VaultHub. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`, html.EscapeString(title), html.EscapeString(message))
+
+	return s.SendMail([]string{to}, subject, body)
+}
+
+// SendAccountLockedNotice 发送账户锁定通知邮件
+// to: 收件人邮箱
+// username: 被锁定的用户名
+// ipAddress: 最后一次登录失败的来源IP
+// unlockAt: 自动解锁时间，为空表示需要联系管理员解锁
+func (s *Sender) SendAccountLockedNotice(to, username, ipAddress, unlockAt string) error {
+	subject := "VaultHub - 账户已锁定"
+	unlockTip := "请联系管理员解锁账户。"
+	if unlockAt != "" {
+		unlockTip = fmt.Sprintf("账户将在 %s 自动解锁，也可以联系管理员提前解锁。", unlockAt)
+	}
+	body := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #4CAF50; color: white; padding: 10px; text-align: center; }
+        .content { background-color: #f9f9f9; padding: 20px; border-radius: 5px; margin-top: 20px; }
+        .warning { background-color: #fff3cd; border-left: 4px solid #ffc107; padding: 10px; margin: 15px 0; }
+        .footer { text-align: center; margin-top: 20px; font-size: 12px; color: #666; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h2>VaultHub 密钥管理系统</h2>
+        </div>
+        <div class="content">
+            <p>您好，</p>
+            <p>您的账户 <strong>%s</strong> 连续多次登录失败（最近一次来自 %s），已被自动锁定。</p>
+            <p>%s</p>
+            <div class="warning">
+                <p style="margin: 0;">如果这些登录尝试不是您本人发起的，说明有人正在尝试猜测您的密码，建议解锁后立即修改密码并启用两步验证。</p>
+            </div>
+        </div>
+        <div class="footer">
+            <p>此邮件由系统自动发送，请勿回复。</p>
+            <p>&copy; 2024 VaultHub. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+`, html.EscapeString(username), html.EscapeString(ipAddress), html.EscapeString(unlockTip))
+
+	return s.SendMail([]string{to}, subject, body)
+}
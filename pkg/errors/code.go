@@ -25,6 +25,9 @@ const (
 	CodeUsernameExists      = 20009
 	CodeSecurityPINNotSet   = 20010 // 安全密码未设置
 	CodeSecurityPINRequired = 20011 // 需要安全密码
+	CodeInvalidMFACode      = 20012 // 两步验证码错误
+	CodeWebAuthnFailed      = 20013 // 通行密钥验证失败
+	CodeStepUpRequired      = 20014 // 需要通行密钥二次验证
 )
 
 const (
@@ -32,6 +35,7 @@ const (
 	CodeInsufficientPermission = 30002
 	CodeOperationNotAllowed    = 30003
 	CodeResourceLocked         = 30004
+	CodeApprovalRequired       = 30005 // 高敏感秘密需要审批
 )
 
 const (
@@ -46,6 +50,7 @@ const (
 	CodeNicknameExists        = 40009
 	CodeEmailExists           = 40010
 	CodeTooManyRequests       = 40011
+	CodeQuotaExceeded         = 40012 // 存储容量超出限制
 )
 
 const (
@@ -95,11 +100,15 @@ var codeMessages = map[int]string{
 	CodeUsernameExists:      "用户名已存在",
 	CodeSecurityPINNotSet:   "安全密码未设置",
 	CodeSecurityPINRequired: "需要安全密码",
+	CodeInvalidMFACode:      "两步验证码错误",
+	CodeWebAuthnFailed:      "通行密钥验证失败",
+	CodeStepUpRequired:      "需要通行密钥二次验证",
 
 	CodeForbidden:              "禁止访问",
 	CodeInsufficientPermission: "权限不足",
 	CodeOperationNotAllowed:    "操作不被允许",
 	CodeResourceLocked:         "资源已锁定",
+	CodeApprovalRequired:       "需要审批",
 
 	CodeResourceNotFound:      "资源未找到",
 	CodeResourceAlreadyExists: "资源已存在",
@@ -112,6 +121,7 @@ var codeMessages = map[int]string{
 	CodeNicknameExists:        "昵称已存在",
 	CodeEmailExists:           "邮箱已存在",
 	CodeTooManyRequests:       "请求过于频繁",
+	CodeQuotaExceeded:         "存储容量超出限制",
 
 	CodeInternalError:      "内部错误",
 	CodeDatabaseError:      "数据库错误",
@@ -0,0 +1,144 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Bitwarden条目类型
+const (
+	bitwardenTypeLogin      = 1
+	bitwardenTypeSecureNote = 2
+	bitwardenTypeCard       = 3
+	bitwardenTypeIdentity   = 4
+)
+
+// bitwardenExport Bitwarden未加密JSON导出结构（只保留需要的字段）
+type bitwardenExport struct {
+	Encrypted bool `json:"encrypted"`
+	Folders   []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"folders"`
+	Items []bitwardenItem `json:"items"`
+}
+
+type bitwardenItem struct {
+	FolderID string `json:"folderId"`
+	Type     int    `json:"type"`
+	Name     string `json:"name"`
+	Notes    string `json:"notes"`
+	Login    *struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		TOTP     string `json:"totp"`
+		URIs     []struct {
+			URI string `json:"uri"`
+		} `json:"uris"`
+	} `json:"login"`
+	Card     map[string]interface{} `json:"card"`
+	Identity map[string]interface{} `json:"identity"`
+	Fields   []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"fields"`
+}
+
+// ParseBitwarden 解析Bitwarden未加密JSON导出
+// 登录项映射为password类型（明文为密码），安全笔记、卡片和身份信息映射为other类型
+func ParseBitwarden(r io.Reader) ([]Entry, error) {
+	var export bitwardenExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("解析Bitwarden JSON失败: %w", err)
+	}
+	if export.Encrypted {
+		return nil, fmt.Errorf("不支持加密的Bitwarden导出，请使用未加密JSON格式导出")
+	}
+
+	folders := make(map[string]string, len(export.Folders))
+	for _, f := range export.Folders {
+		folders[f.ID] = strings.Trim(f.Name, "/")
+	}
+
+	entries := make([]Entry, 0, len(export.Items))
+	for i, item := range export.Items {
+		entry := Entry{
+			Index:       i + 1,
+			Name:        strings.TrimSpace(item.Name),
+			Description: item.Notes,
+			Folder:      folders[item.FolderID],
+		}
+
+		for _, f := range item.Fields {
+			if f.Name == "" {
+				continue
+			}
+			if entry.Extra == nil {
+				entry.Extra = make(map[string]string)
+			}
+			entry.Extra[f.Name] = f.Value
+		}
+
+		switch item.Type {
+		case bitwardenTypeLogin:
+			entry.Type = TypePassword
+			if item.Login != nil {
+				entry.Value = item.Login.Password
+				entry.Username = item.Login.Username
+				if len(item.Login.URIs) > 0 {
+					entry.URL = item.Login.URIs[0].URI
+				}
+				if item.Login.TOTP != "" {
+					if entry.Extra == nil {
+						entry.Extra = make(map[string]string)
+					}
+					entry.Extra["totp"] = item.Login.TOTP
+				}
+			}
+			if entry.Value == "" {
+				entry.Error = "登录项缺少密码"
+			}
+		case bitwardenTypeSecureNote:
+			// 安全笔记的内容本身就是秘密，不放在描述中
+			entry.Type = TypeOther
+			entry.Value = item.Notes
+			entry.Description = ""
+			if entry.Value == "" {
+				entry.Error = "安全笔记内容为空"
+			}
+		case bitwardenTypeCard:
+			entry.Type = TypeOther
+			entry.Value, entry.Error = marshalFields(item.Card)
+		case bitwardenTypeIdentity:
+			entry.Type = TypeOther
+			entry.Value, entry.Error = marshalFields(item.Identity)
+		default:
+			entry.Error = fmt.Sprintf("不支持的Bitwarden条目类型: %d", item.Type)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// marshalFields 把卡片/身份信息中的非空字段序列化为JSON作为秘密明文
+func marshalFields(fields map[string]interface{}) (string, string) {
+	compact := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if v == nil || v == "" {
+			continue
+		}
+		compact[k] = v
+	}
+	if len(compact) == 0 {
+		return "", "条目内容为空"
+	}
+	data, err := json.Marshal(compact)
+	if err != nil {
+		return "", fmt.Sprintf("序列化条目内容失败: %v", err)
+	}
+	return string(data), ""
+}
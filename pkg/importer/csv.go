@@ -0,0 +1,125 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseCSV 解析带表头的通用CSV文件
+// 第一行必须是表头，通过mapping把列名映射到Entry字段，未映射的列写入Extra
+func ParseCSV(r io.Reader, mapping CSVMapping) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // 允许行之间列数不一致，缺失列按空值处理
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("CSV内容为空")
+		}
+		return nil, fmt.Errorf("读取CSV表头失败: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		// 去掉Excel导出时可能带有的BOM
+		name := strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+		columns[name] = i
+	}
+
+	// 校验映射的列都存在
+	mapped := map[string]string{
+		"name":        mapping.Name,
+		"value":       mapping.Value,
+		"type":        mapping.Type,
+		"username":    mapping.Username,
+		"url":         mapping.URL,
+		"description": mapping.Description,
+		"tags":        mapping.Tags,
+		"folder":      mapping.Folder,
+	}
+	used := make(map[int]struct{}, len(mapped))
+	for field, col := range mapped {
+		if col == "" {
+			continue
+		}
+		idx, ok := columns[col]
+		if !ok {
+			return nil, fmt.Errorf("CSV表头中不存在列 %q（映射字段 %s）", col, field)
+		}
+		used[idx] = struct{}{}
+	}
+
+	get := func(record []string, col string) string {
+		if col == "" {
+			return ""
+		}
+		idx := columns[col]
+		if idx >= len(record) {
+			return ""
+		}
+		return record[idx]
+	}
+
+	var entries []Entry
+	rowNo := 1 // 表头是第1行
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		rowNo++
+		if err != nil {
+			entries = append(entries, Entry{
+				Index: rowNo,
+				Error: fmt.Sprintf("第%d行解析失败: %v", rowNo, err),
+			})
+			continue
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+
+		entry := Entry{
+			Index:       rowNo,
+			Name:        strings.TrimSpace(get(record, mapping.Name)),
+			Type:        strings.TrimSpace(get(record, mapping.Type)),
+			Value:       get(record, mapping.Value),
+			Username:    strings.TrimSpace(get(record, mapping.Username)),
+			URL:         strings.TrimSpace(get(record, mapping.URL)),
+			Description: get(record, mapping.Description),
+			Tags:        splitTags(get(record, mapping.Tags)),
+			Folder:      strings.Trim(strings.TrimSpace(get(record, mapping.Folder)), "/"),
+		}
+
+		// 未映射的非空列保留到Extra，避免信息丢失
+		for name, idx := range columns {
+			if _, ok := used[idx]; ok || idx >= len(record) || record[idx] == "" {
+				continue
+			}
+			if entry.Extra == nil {
+				entry.Extra = make(map[string]string)
+			}
+			entry.Extra[name] = record[idx]
+		}
+
+		if entry.Type == "" {
+			entry.Type = InferTypeFromName(entry.Name)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// isBlankRecord 判断CSV行是否全部为空
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
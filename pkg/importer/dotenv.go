@@ -0,0 +1,144 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseDotenv 解析 .env 文件
+// 支持的语法：
+//   - KEY=VALUE，可选的 export 前缀
+//   - 单引号值按原样保留，双引号值支持 \n \t \" \\ 转义并可跨行
+//   - # 开头的整行注释，以及未加引号值后以空白+# 开始的行尾注释
+func ParseDotenv(r io.Reader) ([]Entry, error) {
+	scanner := bufio.NewScanner(r)
+	// 证书、私钥等值可能很长，放宽单行长度限制
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	var entries []Entry
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		startLine := lineNo
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		idx := strings.Index(line, "=")
+		if idx <= 0 {
+			entries = append(entries, Entry{
+				Index: startLine,
+				Name:  line,
+				Error: fmt.Sprintf("第%d行格式无效，应为KEY=VALUE", startLine),
+			})
+			continue
+		}
+
+		key := strings.TrimSpace(line[:idx])
+		raw := strings.TrimSpace(line[idx+1:])
+
+		var value string
+		switch {
+		case strings.HasPrefix(raw, `"`):
+			// 双引号值可能跨行，持续读取直到遇到未转义的结束引号
+			body := raw[1:]
+			for !hasClosingQuote(body) {
+				if !scanner.Scan() {
+					return nil, fmt.Errorf("第%d行的双引号值未闭合", startLine)
+				}
+				lineNo++
+				body += "\n" + scanner.Text()
+			}
+			end := closingQuoteIndex(body)
+			value = unescapeDoubleQuoted(body[:end])
+		case strings.HasPrefix(raw, "'"):
+			end := strings.Index(raw[1:], "'")
+			if end < 0 {
+				entries = append(entries, Entry{
+					Index: startLine,
+					Name:  key,
+					Error: fmt.Sprintf("第%d行的单引号值未闭合", startLine),
+				})
+				continue
+			}
+			value = raw[1 : end+1]
+		default:
+			value = stripInlineComment(raw)
+		}
+
+		entries = append(entries, Entry{
+			Index: startLine,
+			Name:  key,
+			Type:  InferTypeFromName(key),
+			Value: value,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取dotenv内容失败: %w", err)
+	}
+	return entries, nil
+}
+
+// hasClosingQuote 判断双引号值中是否出现了未转义的结束引号
+func hasClosingQuote(s string) bool {
+	return closingQuoteIndex(s) >= 0
+}
+
+// closingQuoteIndex 返回第一个未转义双引号的位置，不存在返回-1
+func closingQuoteIndex(s string) int {
+	escaped := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case s[i] == '\\':
+			escaped = true
+		case s[i] == '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeDoubleQuoted 处理双引号值中的转义序列
+func unescapeDoubleQuoted(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '"', '\\':
+			b.WriteByte(s[i])
+		default:
+			// 未知转义保持原样
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// stripInlineComment 去掉未加引号值的行尾注释
+func stripInlineComment(s string) string {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '#' && (s[i-1] == ' ' || s[i-1] == '\t') {
+			return strings.TrimSpace(s[:i])
+		}
+	}
+	return s
+}
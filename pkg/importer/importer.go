@@ -0,0 +1,132 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format 导入文件格式
+type Format string
+
+const (
+	FormatDotenv    Format = "dotenv"    // .env 文件（KEY=VALUE）
+	FormatCSV       Format = "csv"       // 通用CSV（需要列映射）
+	FormatBitwarden Format = "bitwarden" // Bitwarden 未加密JSON导出
+	FormatKeePass   Format = "keepass"   // KeePass 2.x XML导出
+)
+
+// 秘密类型提示
+// 与 models.SecretType 取值保持一致，由调用方负责最终校验和映射
+const (
+	TypeAPIKey       = "api_key"
+	TypeDBCredential = "db_credential"
+	TypeCertificate  = "certificate"
+	TypeSSHKey       = "ssh_key"
+	TypeToken        = "token"
+	TypePassword     = "password"
+	TypeOther        = "other"
+)
+
+// Entry 解析后的单条导入记录
+// 解析器只负责把不同格式统一为Entry，不做加密和持久化
+type Entry struct {
+	Index       int               // 在源文件中的序号（从1开始），用于错误报告
+	Name        string            // 秘密名称
+	Type        string            // 建议的秘密类型（为空时由调用方使用默认类型）
+	Value       string            // 秘密明文
+	Username    string            // 关联用户名（可选）
+	URL         string            // 关联地址（可选）
+	Description string            // 描述/备注
+	Tags        []string          // 标签
+	Folder      string            // 文件夹路径（多级用"/"分隔）
+	Extra       map[string]string // 其他字段（自定义字段、卡片信息等）
+	Error       string            // 单条记录的解析错误（非空时该条记录不可导入）
+}
+
+// CSVMapping CSV列映射
+// 值为CSV表头中的列名，Name和Value必填
+type CSVMapping struct {
+	Name        string `json:"name" binding:"required"`  // 名称列
+	Value       string `json:"value" binding:"required"` // 明文列
+	Type        string `json:"type"`                     // 类型列
+	Username    string `json:"username"`                 // 用户名列
+	URL         string `json:"url"`                      // 地址列
+	Description string `json:"description"`              // 描述列
+	Tags        string `json:"tags"`                     // 标签列（多个标签用","或";"分隔）
+	Folder      string `json:"folder"`                   // 文件夹列
+}
+
+// Options 解析选项
+type Options struct {
+	CSVMapping *CSVMapping // 仅CSV格式需要
+}
+
+// Parse 按指定格式解析导入内容
+// 返回的error表示整个文件无法解析；单条记录的问题记录在Entry.Error中
+func Parse(format Format, r io.Reader, opts Options) ([]Entry, error) {
+	switch format {
+	case FormatDotenv:
+		return ParseDotenv(r)
+	case FormatCSV:
+		if opts.CSVMapping == nil {
+			return nil, fmt.Errorf("CSV格式需要提供列映射")
+		}
+		return ParseCSV(r, *opts.CSVMapping)
+	case FormatBitwarden:
+		return ParseBitwarden(r)
+	case FormatKeePass:
+		return ParseKeePassXML(r)
+	default:
+		return nil, fmt.Errorf("不支持的导入格式: %s", format)
+	}
+}
+
+// InferTypeFromName 根据名称推断秘密类型
+// 用于dotenv等没有类型信息的格式，无法推断时返回空字符串
+func InferTypeFromName(name string) string {
+	upper := strings.ToUpper(name)
+	switch {
+	case strings.Contains(upper, "PRIVATE_KEY") || strings.Contains(upper, "SSH"):
+		return TypeSSHKey
+	case strings.Contains(upper, "CERT") || strings.HasSuffix(upper, "_PEM"):
+		return TypeCertificate
+	case strings.Contains(upper, "PASSWORD") || strings.Contains(upper, "PASSWD") ||
+		strings.HasSuffix(upper, "_PASS") || strings.HasSuffix(upper, "_PWD"):
+		return TypePassword
+	case strings.Contains(upper, "DATABASE_URL") || strings.Contains(upper, "DSN") ||
+		strings.HasPrefix(upper, "DB_") || strings.Contains(upper, "_DB_"):
+		return TypeDBCredential
+	case strings.Contains(upper, "API_KEY") || strings.Contains(upper, "APIKEY") ||
+		strings.HasSuffix(upper, "_KEY") || strings.Contains(upper, "ACCESS_KEY"):
+		return TypeAPIKey
+	case strings.Contains(upper, "TOKEN") || strings.Contains(upper, "SECRET"):
+		return TypeToken
+	default:
+		return ""
+	}
+}
+
+// splitTags 拆分标签字符串，支持","和";"分隔，去除空白和重复项
+func splitTags(raw string) []string {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ';'
+	})
+	seen := make(map[string]struct{}, len(fields))
+	tags := make([]string, 0, len(fields))
+	for _, f := range fields {
+		tag := strings.TrimSpace(f)
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		tags = append(tags, tag)
+	}
+	return tags
+}
@@ -0,0 +1,115 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// KeePass 2.x XML导出结构（只保留需要的字段）
+type keepassFile struct {
+	XMLName xml.Name `xml:"KeePassFile"`
+	Meta    struct {
+		RecycleBinUUID string `xml:"RecycleBinUUID"`
+	} `xml:"Meta"`
+	Root struct {
+		Groups []keepassGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type keepassGroup struct {
+	UUID    string         `xml:"UUID"`
+	Name    string         `xml:"Name"`
+	Entries []keepassEntry `xml:"Entry"`
+	Groups  []keepassGroup `xml:"Group"`
+}
+
+// keepassEntry 条目
+// 注意：Entry下的History子元素包含历史版本，这里不解析，只导入当前版本
+type keepassEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	} `xml:"String"`
+	Tags string `xml:"Tags"`
+}
+
+// KeePass标准字段名
+const (
+	keepassFieldTitle    = "Title"
+	keepassFieldUserName = "UserName"
+	keepassFieldPassword = "Password"
+	keepassFieldURL      = "URL"
+	keepassFieldNotes    = "Notes"
+)
+
+// ParseKeePassXML 解析KeePass 2.x未加密XML导出
+// 组路径映射为文件夹（根组名称不计入路径），回收站中的条目会被跳过
+func ParseKeePassXML(r io.Reader) ([]Entry, error) {
+	var file keepassFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("解析KeePass XML失败: %w", err)
+	}
+
+	var entries []Entry
+	index := 0
+	var walk func(g keepassGroup, path []string)
+	walk = func(g keepassGroup, path []string) {
+		if file.Meta.RecycleBinUUID != "" && g.UUID == file.Meta.RecycleBinUUID {
+			return
+		}
+		for _, e := range g.Entries {
+			index++
+			entries = append(entries, convertKeePassEntry(index, e, strings.Join(path, "/")))
+		}
+		for _, child := range g.Groups {
+			walk(child, append(append([]string(nil), path...), strings.Trim(child.Name, "/")))
+		}
+	}
+
+	// 根组通常是数据库名称，不作为文件夹路径的一部分
+	for _, root := range file.Root.Groups {
+		walk(root, nil)
+	}
+
+	return entries, nil
+}
+
+// convertKeePassEntry 把KeePass条目转换为Entry
+func convertKeePassEntry(index int, e keepassEntry, folder string) Entry {
+	entry := Entry{
+		Index:  index,
+		Type:   TypePassword,
+		Folder: folder,
+		Tags:   splitTags(e.Tags),
+	}
+
+	for _, s := range e.Strings {
+		switch s.Key {
+		case keepassFieldTitle:
+			entry.Name = strings.TrimSpace(s.Value)
+		case keepassFieldUserName:
+			entry.Username = s.Value
+		case keepassFieldPassword:
+			entry.Value = s.Value
+		case keepassFieldURL:
+			entry.URL = s.Value
+		case keepassFieldNotes:
+			entry.Description = s.Value
+		default:
+			if s.Value == "" {
+				continue
+			}
+			if entry.Extra == nil {
+				entry.Extra = make(map[string]string)
+			}
+			entry.Extra[s.Key] = s.Value
+		}
+	}
+
+	if entry.Value == "" {
+		entry.Error = "条目缺少密码"
+	}
+	return entry
+}
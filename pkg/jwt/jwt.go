@@ -7,8 +7,10 @@ import (
 )
 
 const (
-	// DefaultExpiration 默认过期时间（24小时）
-	DefaultExpiration = 24 * time.Hour
+	// DefaultExpiration 访问令牌默认过期时间（15分钟）
+	DefaultExpiration = 15 * time.Minute
+	// DefaultRefreshExpiration 刷新令牌默认过期时间（30天）
+	DefaultRefreshExpiration = 30 * 24 * time.Hour
 	// Issuer JWT签发者
 	Issuer = "vaulthub"
 )
@@ -18,33 +20,45 @@ type Claims struct {
 	UserUUID string `json:"user_uuid"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	// SessionID 令牌所属的登录会话，会话被注销后令牌立即失效
+	SessionID string `json:"sid"`
+	// TokenEpoch 签发时用户的令牌版本，用户修改密码、角色或状态后版本递增，旧令牌立即失效
+	TokenEpoch uint `json:"epoch"`
 	jwt.RegisteredClaims
 }
 
 // Manager JWT管理器
 type Manager struct {
-	secret     []byte
-	expiration time.Duration
+	secret            []byte
+	expiration        time.Duration
+	refreshExpiration time.Duration
 }
 
 // NewManager 创建JWT管理器
-func NewManager(secret string, expiration time.Duration) *Manager {
+// expiration为访问令牌有效期，refreshExpiration为刷新令牌（登录会话）有效期
+func NewManager(secret string, expiration, refreshExpiration time.Duration) *Manager {
 	if expiration == 0 {
 		expiration = DefaultExpiration
 	}
+	if refreshExpiration == 0 {
+		refreshExpiration = DefaultRefreshExpiration
+	}
 	return &Manager{
-		secret:     []byte(secret),
-		expiration: expiration,
+		secret:            []byte(secret),
+		expiration:        expiration,
+		refreshExpiration: refreshExpiration,
 	}
 }
 
 // GenerateToken 生成JWT token
-func (m *Manager) GenerateToken(userUUID, username, role string) (string, error) {
+func (m *Manager) GenerateToken(userUUID, username, role, sessionID string, tokenEpoch uint) (string, error) {
 	now := time.Now()
 	claims := Claims{
-		UserUUID: userUUID,
-		Username: username,
-		Role:     role,
+		UserUUID:   userUUID,
+		Username:   username,
+		Role:       role,
+		SessionID:  sessionID,
+		TokenEpoch: tokenEpoch,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    Issuer,
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -83,3 +97,8 @@ func (m *Manager) ValidateToken(tokenString string) bool {
 func (m *Manager) GetExpiration() time.Duration {
 	return m.expiration
 }
+
+// GetRefreshExpiration 获取刷新令牌过期时间
+func (m *Manager) GetRefreshExpiration() time.Duration {
+	return m.refreshExpiration
+}
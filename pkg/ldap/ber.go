@@ -0,0 +1,251 @@
+package ldap
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxPacketSize 单个LDAP消息的最大长度，防止异常的长度字段耗尽内存
+const maxPacketSize = 4 << 20
+
+// Class BER标签类别
+type Class byte
+
+// BER标签类别
+const (
+	ClassUniversal   Class = 0x00
+	ClassApplication Class = 0x40
+	ClassContext     Class = 0x80
+)
+
+// UNIVERSAL类别的标签
+const (
+	TagBoolean     = 0x01
+	TagInteger     = 0x02
+	TagOctetString = 0x04
+	TagNull        = 0x05
+	TagEnumerated  = 0x0a
+	TagSequence    = 0x10
+	TagSet         = 0x11
+)
+
+// Packet BER编码的一个TLV，LDAP只用到低标签号（小于31）和确定长度
+type Packet struct {
+	Class       Class
+	Constructed bool
+	Tag         int
+	Value       []byte    // 基本类型的内容
+	Children    []*Packet // 构造类型的子元素
+}
+
+// NewSequence 创建SEQUENCE（或指定类别和标签的构造类型）
+func NewSequence(class Class, tag int, children ...*Packet) *Packet {
+	return &Packet{Class: class, Constructed: true, Tag: tag, Children: children}
+}
+
+// NewString 创建字符串类型的基本元素
+func NewString(class Class, tag int, value string) *Packet {
+	return &Packet{Class: class, Tag: tag, Value: []byte(value)}
+}
+
+// NewInteger 创建整数类型的基本元素（INTEGER或ENUMERATED）
+func NewInteger(class Class, tag int, value int64) *Packet {
+	return &Packet{Class: class, Tag: tag, Value: encodeInteger(value)}
+}
+
+// NewBoolean 创建BOOLEAN
+func NewBoolean(value bool) *Packet {
+	b := byte(0x00)
+	if value {
+		b = 0xff
+	}
+	return &Packet{Class: ClassUniversal, Tag: TagBoolean, Value: []byte{b}}
+}
+
+// Append 追加子元素
+func (p *Packet) Append(children ...*Packet) *Packet {
+	p.Children = append(p.Children, children...)
+	return p
+}
+
+// Is 判断类别、构造方式和标签是否一致
+func (p *Packet) Is(class Class, constructed bool, tag int) bool {
+	return p.Class == class && p.Constructed == constructed && p.Tag == tag
+}
+
+// String 以字符串读取基本元素的内容
+func (p *Packet) String() string {
+	return string(p.Value)
+}
+
+// Int 以整数读取基本元素的内容
+func (p *Packet) Int() (int64, error) {
+	if p.Constructed || len(p.Value) == 0 || len(p.Value) > 8 {
+		return 0, errors.New("ldap: 无效的整数")
+	}
+	value := int64(int8(p.Value[0]))
+	for _, b := range p.Value[1:] {
+		value = value<<8 | int64(b)
+	}
+	return value, nil
+}
+
+// Bool 以布尔值读取基本元素的内容
+func (p *Packet) Bool() bool {
+	return len(p.Value) == 1 && p.Value[0] != 0
+}
+
+// Child 返回第i个子元素，不存在时返回nil
+func (p *Packet) Child(i int) *Packet {
+	if i < 0 || i >= len(p.Children) {
+		return nil
+	}
+	return p.Children[i]
+}
+
+// Bytes 编码为BER
+func (p *Packet) Bytes() []byte {
+	content := p.Value
+	if p.Constructed {
+		content = nil
+		for _, child := range p.Children {
+			content = append(content, child.Bytes()...)
+		}
+	}
+
+	identifier := byte(p.Class) | byte(p.Tag&0x1f)
+	if p.Constructed {
+		identifier |= 0x20
+	}
+	out := append([]byte{identifier}, encodeLength(len(content))...)
+	return append(out, content...)
+}
+
+// ReadPacket 从流中读取一个完整的TLV并解析
+func ReadPacket(r *bufio.Reader) (*Packet, error) {
+	header := make([]byte, 0, 6)
+	identifier, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	header = append(header, identifier)
+
+	first, err := r.ReadByte()
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	header = append(header, first)
+	length := int(first)
+	if first&0x80 != 0 {
+		n := int(first & 0x7f)
+		if n == 0 || n > 4 {
+			return nil, errors.New("ldap: 不支持的BER长度")
+		}
+		length = 0
+		for i := 0; i < n; i++ {
+			b, err := r.ReadByte()
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			header = append(header, b)
+			length = length<<8 | int(b)
+		}
+	}
+	if length > maxPacketSize {
+		return nil, fmt.Errorf("ldap: 消息长度 %d 超过限制", length)
+	}
+
+	data := make([]byte, len(header)+length)
+	copy(data, header)
+	if _, err := io.ReadFull(r, data[len(header):]); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	packet, rest, err := parsePacket(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("ldap: BER数据有多余字节")
+	}
+	return packet, nil
+}
+
+// parsePacket 解析一个TLV，返回剩余的字节
+func parsePacket(data []byte) (*Packet, []byte, error) {
+	if len(data) < 2 {
+		return nil, nil, errors.New("ldap: BER数据过短")
+	}
+	identifier := data[0]
+	if identifier&0x1f == 0x1f {
+		return nil, nil, errors.New("ldap: 不支持的BER标签")
+	}
+	p := &Packet{
+		Class:       Class(identifier & 0xc0),
+		Constructed: identifier&0x20 != 0,
+		Tag:         int(identifier & 0x1f),
+	}
+
+	offset := 2
+	length := int(data[1])
+	if data[1]&0x80 != 0 {
+		n := int(data[1] & 0x7f)
+		if n == 0 || n > 4 || len(data) < 2+n {
+			return nil, nil, errors.New("ldap: 不支持的BER长度")
+		}
+		length = 0
+		for _, b := range data[2 : 2+n] {
+			length = length<<8 | int(b)
+		}
+		offset += n
+	}
+	if length < 0 || len(data)-offset < length {
+		return nil, nil, errors.New("ldap: BER长度超出数据范围")
+	}
+
+	content := data[offset : offset+length]
+	if p.Constructed {
+		for len(content) > 0 {
+			child, rest, err := parsePacket(content)
+			if err != nil {
+				return nil, nil, err
+			}
+			p.Children = append(p.Children, child)
+			content = rest
+		}
+	} else {
+		p.Value = content
+	}
+	return p, data[offset+length:], nil
+}
+
+// encodeLength 编码确定长度
+func encodeLength(length int) []byte {
+	if length < 0x80 {
+		return []byte{byte(length)}
+	}
+	var b []byte
+	for l := length; l > 0; l >>= 8 {
+		b = append([]byte{byte(l)}, b...)
+	}
+	return append([]byte{0x80 | byte(len(b))}, b...)
+}
+
+// encodeInteger 以最少字节的补码编码整数
+func encodeInteger(value int64) []byte {
+	b := []byte{byte(value)}
+	for value > 127 || value < -128 {
+		value >>= 8
+		b = append([]byte{byte(value)}, b...)
+	}
+	return b
+}
+
+// unexpectedEOF 读取到一半时连接关闭
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
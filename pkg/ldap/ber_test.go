@@ -0,0 +1,134 @@
+package ldap
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func readPacket(data []byte) (*Packet, error) {
+	return ReadPacket(bufio.NewReader(bytes.NewReader(data)))
+}
+
+func TestPacketRoundTrip(t *testing.T) {
+	// 长度编码的边界：短格式的上限127，长格式1到3个字节
+	for _, size := range []int{0, 1, 127, 128, 255, 256, 65535, 65536} {
+		value := strings.Repeat("a", size)
+		p := NewSequence(ClassUniversal, TagSequence,
+			NewInteger(ClassUniversal, TagInteger, 7),
+			NewString(ClassContext, 0, value))
+
+		decoded, err := readPacket(p.Bytes())
+		if err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+		if !decoded.Is(ClassUniversal, true, TagSequence) || len(decoded.Children) != 2 {
+			t.Fatalf("size %d: 结构不一致: %+v", size, decoded)
+		}
+		if id, err := decoded.Child(0).Int(); err != nil || id != 7 {
+			t.Fatalf("size %d: 消息ID = %d, %v", size, id, err)
+		}
+		if !decoded.Child(1).Is(ClassContext, false, 0) || decoded.Child(1).String() != value {
+			t.Fatalf("size %d: 内容不一致", size)
+		}
+	}
+}
+
+func TestEncodeLength(t *testing.T) {
+	tests := []struct {
+		length int
+		want   []byte
+	}{
+		{0, []byte{0x00}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x81, 0x80}},
+		{255, []byte{0x81, 0xff}},
+		{256, []byte{0x82, 0x01, 0x00}},
+		{65536, []byte{0x83, 0x01, 0x00, 0x00}},
+	}
+	for _, tt := range tests {
+		if got := encodeLength(tt.length); !bytes.Equal(got, tt.want) {
+			t.Errorf("encodeLength(%d) = %x, want %x", tt.length, got, tt.want)
+		}
+	}
+}
+
+func TestIntegerRoundTrip(t *testing.T) {
+	for _, value := range []int64{0, 1, 127, 128, 255, 256, -1, -128, -129, 1<<31 - 1, -1 << 31} {
+		got, err := NewInteger(ClassUniversal, TagInteger, value).Int()
+		if err != nil || got != value {
+			t.Errorf("Int() = %d, %v, want %d", got, err, value)
+		}
+	}
+
+	invalid := []*Packet{
+		{Class: ClassUniversal, Tag: TagInteger},
+		{Class: ClassUniversal, Tag: TagInteger, Value: make([]byte, 9)},
+		NewSequence(ClassUniversal, TagInteger),
+	}
+	for _, p := range invalid {
+		if _, err := p.Int(); err == nil {
+			t.Errorf("Int() 接受了无效的整数 %+v", p)
+		}
+	}
+}
+
+func TestReadPacketMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"只有标签", []byte{0x30}},
+		{"内容被截断", []byte{0x04, 0x05, 'a', 'b'}},
+		{"长格式长度被截断", []byte{0x04, 0x82, 0x01}},
+		{"不确定长度", []byte{0x30, 0x80, 0x00, 0x00}},
+		{"长度字节数超过4", []byte{0x04, 0x85, 0x00, 0x00, 0x00, 0x00, 0x01, 'a'}},
+		{"长度超过上限", append([]byte{0x04, 0x84}, encodeUint32(maxPacketSize+1)...)},
+		{"高标签号", []byte{0x1f, 0x01, 0x00}},
+		{"子元素长度超出父元素", []byte{0x30, 0x03, 0x04, 0x05, 'a'}},
+		{"子元素不完整", []byte{0x30, 0x01, 0x04}},
+		{"子元素长度字段被截断", []byte{0x30, 0x02, 0x04, 0x82}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p, err := readPacket(tt.data); err == nil {
+				t.Fatalf("ReadPacket() 接受了无效数据: %+v", p)
+			}
+		})
+	}
+}
+
+func TestReadPacketTruncatedIsUnexpectedEOF(t *testing.T) {
+	// 连接在消息中途关闭时不能当作正常结束
+	full := NewSequence(ClassUniversal, TagSequence, NewString(ClassUniversal, TagOctetString, "value")).Bytes()
+	for i := 1; i < len(full); i++ {
+		if _, err := readPacket(full[:i]); err != io.ErrUnexpectedEOF {
+			t.Errorf("截断到%d字节: err = %v, want %v", i, err, io.ErrUnexpectedEOF)
+		}
+	}
+	if _, err := readPacket(nil); err != io.EOF {
+		t.Errorf("空输入: err = %v, want %v", err, io.EOF)
+	}
+}
+
+func TestReadPacketConsecutive(t *testing.T) {
+	// 一次读取只消费一条消息，后续消息留在缓冲中
+	first := NewString(ClassUniversal, TagOctetString, "first").Bytes()
+	second := NewString(ClassUniversal, TagOctetString, "second").Bytes()
+	r := bufio.NewReader(bytes.NewReader(append(first, second...)))
+	for _, want := range []string{"first", "second"} {
+		p, err := ReadPacket(r)
+		if err != nil || p.String() != want {
+			t.Fatalf("ReadPacket() = %v, %v, want %q", p, err, want)
+		}
+	}
+	if _, err := ReadPacket(r); err != io.EOF {
+		t.Fatalf("err = %v, want %v", err, io.EOF)
+	}
+}
+
+func encodeUint32(v int) []byte {
+	return []byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+}
@@ -0,0 +1,298 @@
+package ldap
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FilterType 过滤器类型，取值即RFC 4511中Filter的上下文标签
+type FilterType int
+
+// 过滤器类型
+const (
+	FilterAnd            FilterType = 0
+	FilterOr             FilterType = 1
+	FilterNot            FilterType = 2
+	FilterEqualityMatch  FilterType = 3
+	FilterSubstrings     FilterType = 4
+	FilterGreaterOrEqual FilterType = 5
+	FilterLessOrEqual    FilterType = 6
+	FilterPresent        FilterType = 7
+	FilterApproxMatch    FilterType = 8
+)
+
+// 子串过滤器各部分的上下文标签
+const (
+	substringInitial = 0
+	substringAny     = 1
+	substringFinal   = 2
+)
+
+// Filter 解析后的搜索过滤器（RFC 4515字符串形式）
+type Filter struct {
+	Type      FilterType
+	Children  []*Filter // and、or、not的子过滤器
+	Attribute string
+	Value     string   // 比较类过滤器的值
+	Initial   string   // 子串过滤器：开头
+	Any       []string // 子串过滤器：中间
+	Final     string   // 子串过滤器：结尾
+}
+
+// EscapeFilter 转义过滤器中的值，拼接用户输入时必须使用
+func EscapeFilter(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch c {
+		case '*', '(', ')', '\\', 0:
+			fmt.Fprintf(&b, "\\%02x", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// ParseFilter 解析RFC 4515字符串形式的过滤器，不支持扩展匹配（:=）
+func ParseFilter(s string) (*Filter, error) {
+	p := &filterParser{input: s}
+	f, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.input) {
+		return nil, fmt.Errorf("ldap: 过滤器 %q 末尾有多余字符", s)
+	}
+	return f, nil
+}
+
+// filterParser 递归下降解析器
+type filterParser struct {
+	input string
+	pos   int
+}
+
+func (p *filterParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("ldap: 无效的过滤器 %q: %s", p.input, fmt.Sprintf(format, args...))
+}
+
+func (p *filterParser) parse() (*Filter, error) {
+	if p.pos >= len(p.input) || p.input[p.pos] != '(' {
+		return nil, p.errorf("缺少 (")
+	}
+	p.pos++
+	if p.pos >= len(p.input) {
+		return nil, p.errorf("过滤器不完整")
+	}
+
+	var f *Filter
+	var err error
+	switch p.input[p.pos] {
+	case '&', '|':
+		f = &Filter{Type: FilterAnd}
+		if p.input[p.pos] == '|' {
+			f.Type = FilterOr
+		}
+		p.pos++
+		for p.pos < len(p.input) && p.input[p.pos] == '(' {
+			child, err := p.parse()
+			if err != nil {
+				return nil, err
+			}
+			f.Children = append(f.Children, child)
+		}
+		if len(f.Children) == 0 {
+			return nil, p.errorf("& 和 | 至少需要一个子过滤器")
+		}
+	case '!':
+		p.pos++
+		child, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		f = &Filter{Type: FilterNot, Children: []*Filter{child}}
+	default:
+		f, err = p.parseItem()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.pos >= len(p.input) || p.input[p.pos] != ')' {
+		return nil, p.errorf("缺少 )")
+	}
+	p.pos++
+	return f, nil
+}
+
+// parseItem 解析 attr op value，停在右括号前
+func (p *filterParser) parseItem() (*Filter, error) {
+	end := strings.IndexByte(p.input[p.pos:], ')')
+	if end < 0 {
+		return nil, p.errorf("缺少 )")
+	}
+	item := p.input[p.pos : p.pos+end]
+	p.pos += end
+
+	eq := strings.IndexByte(item, '=')
+	if eq <= 0 {
+		return nil, p.errorf("缺少属性或 =")
+	}
+	attr, raw := item[:eq], item[eq+1:]
+	f := &Filter{Type: FilterEqualityMatch}
+	switch attr[len(attr)-1] {
+	case '>':
+		f.Type, attr = FilterGreaterOrEqual, attr[:len(attr)-1]
+	case '<':
+		f.Type, attr = FilterLessOrEqual, attr[:len(attr)-1]
+	case '~':
+		f.Type, attr = FilterApproxMatch, attr[:len(attr)-1]
+	case ':':
+		return nil, p.errorf("不支持扩展匹配")
+	}
+	if attr == "" || strings.ContainsAny(attr, "()*\\ ") {
+		return nil, p.errorf("无效的属性名 %q", attr)
+	}
+	f.Attribute = attr
+
+	if f.Type != FilterEqualityMatch || !strings.Contains(raw, "*") {
+		value, err := unescapeFilterValue(raw)
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		f.Value = value
+		return f, nil
+	}
+	if raw == "*" {
+		f.Type = FilterPresent
+		return f, nil
+	}
+
+	f.Type = FilterSubstrings
+	parts := strings.Split(raw, "*")
+	for i, part := range parts {
+		value, err := unescapeFilterValue(part)
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		switch {
+		case i == 0:
+			f.Initial = value
+		case i == len(parts)-1:
+			f.Final = value
+		case value != "":
+			f.Any = append(f.Any, value)
+		}
+	}
+	return f, nil
+}
+
+// unescapeFilterValue 还原 \XX 形式的转义
+func unescapeFilterValue(s string) (string, error) {
+	if !strings.Contains(s, "\\") {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+2 >= len(s) {
+			return "", errors.New("转义不完整")
+		}
+		decoded, err := hex.DecodeString(s[i+1 : i+3])
+		if err != nil {
+			return "", fmt.Errorf("无效的转义 %q", s[i:i+3])
+		}
+		b.Write(decoded)
+		i += 2
+	}
+	return b.String(), nil
+}
+
+// Encode 编码为SearchRequest中的Filter
+func (f *Filter) Encode() *Packet {
+	tag := int(f.Type)
+	switch f.Type {
+	case FilterAnd, FilterOr:
+		p := NewSequence(ClassContext, tag)
+		for _, child := range f.Children {
+			p.Append(child.Encode())
+		}
+		return p
+	case FilterNot:
+		return NewSequence(ClassContext, tag, f.Children[0].Encode())
+	case FilterPresent:
+		return NewString(ClassContext, tag, f.Attribute)
+	case FilterSubstrings:
+		parts := NewSequence(ClassUniversal, TagSequence)
+		if f.Initial != "" {
+			parts.Append(NewString(ClassContext, substringInitial, f.Initial))
+		}
+		for _, value := range f.Any {
+			parts.Append(NewString(ClassContext, substringAny, value))
+		}
+		if f.Final != "" {
+			parts.Append(NewString(ClassContext, substringFinal, f.Final))
+		}
+		return NewSequence(ClassContext, tag, NewString(ClassUniversal, TagOctetString, f.Attribute), parts)
+	default:
+		return NewSequence(ClassContext, tag,
+			NewString(ClassUniversal, TagOctetString, f.Attribute),
+			NewString(ClassUniversal, TagOctetString, f.Value))
+	}
+}
+
+// DecodeFilter 从SearchRequest中的Filter解析
+func DecodeFilter(p *Packet) (*Filter, error) {
+	if p.Class != ClassContext {
+		return nil, errors.New("ldap: 无效的过滤器")
+	}
+	f := &Filter{Type: FilterType(p.Tag)}
+	switch f.Type {
+	case FilterAnd, FilterOr, FilterNot:
+		if !p.Constructed || len(p.Children) == 0 || (f.Type == FilterNot && len(p.Children) != 1) {
+			return nil, errors.New("ldap: 无效的过滤器")
+		}
+		for _, child := range p.Children {
+			decoded, err := DecodeFilter(child)
+			if err != nil {
+				return nil, err
+			}
+			f.Children = append(f.Children, decoded)
+		}
+	case FilterPresent:
+		if p.Constructed {
+			return nil, errors.New("ldap: 无效的过滤器")
+		}
+		f.Attribute = p.String()
+	case FilterSubstrings:
+		if !p.Constructed || len(p.Children) != 2 {
+			return nil, errors.New("ldap: 无效的过滤器")
+		}
+		f.Attribute = p.Children[0].String()
+		for _, part := range p.Children[1].Children {
+			switch part.Tag {
+			case substringInitial:
+				f.Initial = part.String()
+			case substringAny:
+				f.Any = append(f.Any, part.String())
+			case substringFinal:
+				f.Final = part.String()
+			}
+		}
+	case FilterEqualityMatch, FilterGreaterOrEqual, FilterLessOrEqual, FilterApproxMatch:
+		if !p.Constructed || len(p.Children) != 2 {
+			return nil, errors.New("ldap: 无效的过滤器")
+		}
+		f.Attribute = p.Children[0].String()
+		f.Value = p.Children[1].String()
+	default:
+		return nil, fmt.Errorf("ldap: 不支持的过滤器类型 %d", p.Tag)
+	}
+	return f, nil
+}
@@ -0,0 +1,127 @@
+package ldap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEscapeFilter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"alice", "alice"},
+		{"a*", `a\2a`},
+		{"*)(uid=*", `\2a\29\28uid=\2a`},
+		{`back\slash`, `back\5cslash`},
+		{"nul\x00byte", `nul\00byte`},
+		{"张三", "张三"},
+	}
+	for _, tt := range tests {
+		if got := EscapeFilter(tt.value); got != tt.want {
+			t.Errorf("EscapeFilter(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestEscapedValueStaysEquality(t *testing.T) {
+	// 拼接用户输入后仍然是单个等值过滤器，值与输入完全一致
+	for _, input := range []string{"*", "a*", "*)(uid=*", "x)(|(objectClass=*)", `\`, "(", "nul\x00"} {
+		f, err := ParseFilter("(uid=" + EscapeFilter(input) + ")")
+		if err != nil {
+			t.Fatalf("ParseFilter(%q): %v", input, err)
+		}
+		if f.Type != FilterEqualityMatch || f.Attribute != "uid" || f.Value != input {
+			t.Fatalf("输入 %q 解析为 %+v", input, f)
+		}
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   *Filter
+	}{
+		{"(uid=alice)", &Filter{Type: FilterEqualityMatch, Attribute: "uid", Value: "alice"}},
+		{"(objectClass=*)", &Filter{Type: FilterPresent, Attribute: "objectClass"}},
+		{"(cn=al*ce*)", &Filter{Type: FilterSubstrings, Attribute: "cn", Initial: "al", Any: []string{"ce"}}},
+		{"(cn=*a\\2ab*c)", &Filter{Type: FilterSubstrings, Attribute: "cn", Any: []string{"a*b"}, Final: "c"}},
+		{"(age>=18)", &Filter{Type: FilterGreaterOrEqual, Attribute: "age", Value: "18"}},
+		{"(age<=65)", &Filter{Type: FilterLessOrEqual, Attribute: "age", Value: "65"}},
+		{"(cn~=alice)", &Filter{Type: FilterApproxMatch, Attribute: "cn", Value: "alice"}},
+		{"(!(uid=bob))", &Filter{Type: FilterNot, Children: []*Filter{
+			{Type: FilterEqualityMatch, Attribute: "uid", Value: "bob"},
+		}}},
+		{"(&(objectClass=person)(|(uid=a)(mail=a@example.com)))", &Filter{Type: FilterAnd, Children: []*Filter{
+			{Type: FilterEqualityMatch, Attribute: "objectClass", Value: "person"},
+			{Type: FilterOr, Children: []*Filter{
+				{Type: FilterEqualityMatch, Attribute: "uid", Value: "a"},
+				{Type: FilterEqualityMatch, Attribute: "mail", Value: "a@example.com"},
+			}},
+		}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseFilter(tt.filter)
+		if err != nil {
+			t.Errorf("ParseFilter(%q): %v", tt.filter, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFilter(%q) = %+v, want %+v", tt.filter, got, tt.want)
+		}
+
+		// 编码后经过BER再解码，结果不变
+		packet, err := readPacket(got.Encode().Bytes())
+		if err != nil {
+			t.Errorf("%q: 读取编码结果: %v", tt.filter, err)
+			continue
+		}
+		decoded, err := DecodeFilter(packet)
+		if err != nil {
+			t.Errorf("%q: DecodeFilter: %v", tt.filter, err)
+			continue
+		}
+		if !reflect.DeepEqual(decoded, tt.want) {
+			t.Errorf("%q: DecodeFilter = %+v, want %+v", tt.filter, decoded, tt.want)
+		}
+	}
+}
+
+func TestParseFilterInvalid(t *testing.T) {
+	for _, filter := range []string{
+		"",
+		"uid=alice",
+		"(uid=alice",
+		"(uid=alice))",
+		"(=alice)",
+		"(uid)",
+		"(&)",
+		"(!)",
+		"(u id=alice)",
+		"(uid:dn:=alice)",
+		`(uid=\2)`,
+		`(uid=\zz)`,
+	} {
+		if f, err := ParseFilter(filter); err == nil {
+			t.Errorf("ParseFilter(%q) 接受了无效的过滤器: %+v", filter, f)
+		}
+	}
+}
+
+func TestDecodeFilterInvalid(t *testing.T) {
+	for _, p := range []*Packet{
+		NewString(ClassUniversal, TagOctetString, "uid"),
+		NewSequence(ClassContext, int(FilterAnd)),
+		NewSequence(ClassContext, int(FilterNot),
+			NewString(ClassContext, int(FilterPresent), "a"),
+			NewString(ClassContext, int(FilterPresent), "b")),
+		NewSequence(ClassContext, int(FilterPresent)),
+		NewSequence(ClassContext, int(FilterEqualityMatch), NewString(ClassUniversal, TagOctetString, "uid")),
+		NewString(ClassContext, int(FilterEqualityMatch), "uid"),
+		NewSequence(ClassContext, 9),
+	} {
+		if f, err := DecodeFilter(p); err == nil {
+			t.Errorf("DecodeFilter 接受了无效的过滤器 %+v: %+v", p, f)
+		}
+	}
+}
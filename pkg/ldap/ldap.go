@@ -0,0 +1,408 @@
+// Package ldap 实现VaultHub认证需要的LDAPv3客户端子集
+//
+// 支持 ldap:// 和 ldaps:// 连接、StartTLS、简单绑定和搜索，不依赖第三方库：
+//
+//	conn, err := ldap.Dial(ldap.Config{URL: "ldap://ldap.example.com", StartTLS: true})
+//	defer conn.Close()
+//	err = conn.Bind("cn=vaulthub,ou=services,dc=example,dc=com", "password")
+//	entries, err := conn.Search(&ldap.SearchRequest{
+//		BaseDN: "ou=people,dc=example,dc=com",
+//		Scope:  ldap.ScopeWholeSubtree,
+//		Filter: "(uid=" + ldap.EscapeFilter(username) + ")",
+//	})
+//
+// 连接不能并发使用，每个操作同步等待响应。
+package ldap
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 默认参数
+const (
+	defaultTimeout = 10 * time.Second
+	protocolV3     = 3
+)
+
+// LDAPMessage中protocolOp的APPLICATION标签
+const (
+	ApplicationBindRequest           = 0
+	ApplicationBindResponse          = 1
+	ApplicationUnbindRequest         = 2
+	ApplicationSearchRequest         = 3
+	ApplicationSearchResultEntry     = 4
+	ApplicationSearchResultDone      = 5
+	ApplicationSearchResultReference = 19
+	ApplicationExtendedRequest       = 23
+	ApplicationExtendedResponse      = 24
+)
+
+// OIDStartTLS StartTLS扩展操作的OID（RFC 4511 4.14）
+const OIDStartTLS = "1.3.6.1.4.1.1466.20037"
+
+// 常用结果码（RFC 4511 附录A）
+const (
+	ResultSuccess                  = 0
+	ResultOperationsError          = 1
+	ResultProtocolError            = 2
+	ResultSizeLimitExceeded        = 4
+	ResultAuthMethodNotSupported   = 7
+	ResultNoSuchObject             = 32
+	ResultInvalidCredentials       = 49
+	ResultInsufficientAccessRights = 50
+	ResultUnwillingToPerform       = 53
+)
+
+// Scope 搜索范围
+type Scope int
+
+// 搜索范围
+const (
+	ScopeBaseObject   Scope = 0 // 只搜索BaseDN本身
+	ScopeSingleLevel  Scope = 1 // 只搜索BaseDN的直接子条目
+	ScopeWholeSubtree Scope = 2 // 搜索BaseDN及其全部子孙条目
+)
+
+// Error 服务端返回的非成功结果
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("ldap: 结果码 %d", e.Code)
+	}
+	return fmt.Sprintf("ldap: 结果码 %d: %s", e.Code, e.Message)
+}
+
+// IsResultCode 判断错误是否为指定结果码的服务端错误
+func IsResultCode(err error, code int) bool {
+	var ldapErr *Error
+	return errors.As(err, &ldapErr) && ldapErr.Code == code
+}
+
+// Config 连接配置
+type Config struct {
+	URL       string        // ldap://host[:389] 或 ldaps://host[:636]
+	StartTLS  bool          // ldap:// 连接建立后是否升级为TLS
+	TLSConfig *tls.Config   // ldaps和StartTLS使用，为空时按主机名校验系统信任的证书
+	Timeout   time.Duration // 连接和每个操作的超时时间，为空时为10秒
+}
+
+// Conn LDAP连接
+type Conn struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	host      string
+	timeout   time.Duration
+	messageID int64
+	tls       bool
+}
+
+// SearchRequest 搜索请求
+type SearchRequest struct {
+	BaseDN     string
+	Scope      Scope
+	Filter     string   // RFC 4515字符串，为空时为 (objectClass=*)
+	Attributes []string // 为空时返回全部用户属性
+	SizeLimit  int      // 最多返回的条目数，0表示不限制（以服务端限制为准）
+}
+
+// Entry 搜索结果中的条目
+type Entry struct {
+	DN         string
+	Attributes map[string][]string // 属性名保持服务端返回的大小写
+}
+
+// Values 读取属性的全部值，属性名不区分大小写
+func (e *Entry) Values(name string) []string {
+	for attr, values := range e.Attributes {
+		if strings.EqualFold(attr, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+// Value 读取属性的第一个值
+func (e *Entry) Value(name string) string {
+	if values := e.Values(name); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Dial 建立连接，cfg.StartTLS为true时同时完成StartTLS
+func Dial(cfg Config) (*Conn, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("ldap: 无效的URL: %w", err)
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	host := u.Hostname()
+	var conn net.Conn
+	dialer := &net.Dialer{Timeout: timeout}
+	switch u.Scheme {
+	case "ldap":
+		conn, err = dialer.Dial("tcp", net.JoinHostPort(host, portOrDefault(u, "389")))
+	case "ldaps":
+		conn, err = tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(host, portOrDefault(u, "636")), tlsConfigFor(cfg.TLSConfig, host))
+	default:
+		return nil, fmt.Errorf("ldap: 不支持的协议 %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ldap: 连接失败: %w", err)
+	}
+
+	c := &Conn{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		host:    host,
+		timeout: timeout,
+		tls:     u.Scheme == "ldaps",
+	}
+	if cfg.StartTLS && !c.tls {
+		if err := c.StartTLS(cfg.TLSConfig); err != nil {
+			_ = c.conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// StartTLS 把当前连接升级为TLS，tlsConfig为空时按主机名校验系统信任的证书
+func (c *Conn) StartTLS(tlsConfig *tls.Config) error {
+	if c.tls {
+		return errors.New("ldap: 连接已经是TLS")
+	}
+	request := NewSequence(ClassApplication, ApplicationExtendedRequest,
+		NewString(ClassContext, 0, OIDStartTLS))
+	response, err := c.roundTrip(request, ApplicationExtendedResponse)
+	if err != nil {
+		return err
+	}
+	if err := resultError(response); err != nil {
+		return err
+	}
+
+	tlsConn := tls.Client(c.conn, tlsConfigFor(tlsConfig, c.host))
+	if err := tlsConn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return err
+	}
+	if err := tlsConn.Handshake(); err != nil {
+		return fmt.Errorf("ldap: TLS握手失败: %w", err)
+	}
+	c.conn = tlsConn
+	c.reader = bufio.NewReader(tlsConn)
+	c.tls = true
+	return nil
+}
+
+// TLS 当前连接是否已加密
+func (c *Conn) TLS() bool {
+	return c.tls
+}
+
+// Bind 简单绑定。password为空会被服务端视为匿名（未认证）绑定，因此直接拒绝
+func (c *Conn) Bind(dn, password string) error {
+	if password == "" {
+		return &Error{Code: ResultInvalidCredentials, Message: "密码不能为空"}
+	}
+	request := NewSequence(ClassApplication, ApplicationBindRequest,
+		NewInteger(ClassUniversal, TagInteger, protocolV3),
+		NewString(ClassUniversal, TagOctetString, dn),
+		NewString(ClassContext, 0, password))
+	response, err := c.roundTrip(request, ApplicationBindResponse)
+	if err != nil {
+		return err
+	}
+	return resultError(response)
+}
+
+// Search 搜索条目，忽略搜索结果引用（referral）
+func (c *Conn) Search(req *SearchRequest) ([]*Entry, error) {
+	filterString := req.Filter
+	if filterString == "" {
+		filterString = "(objectClass=*)"
+	}
+	filter, err := ParseFilter(filterString)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := NewSequence(ClassUniversal, TagSequence)
+	for _, attr := range req.Attributes {
+		attributes.Append(NewString(ClassUniversal, TagOctetString, attr))
+	}
+	request := NewSequence(ClassApplication, ApplicationSearchRequest,
+		NewString(ClassUniversal, TagOctetString, req.BaseDN),
+		NewInteger(ClassUniversal, TagEnumerated, int64(req.Scope)),
+		NewInteger(ClassUniversal, TagEnumerated, 0), // neverDerefAliases
+		NewInteger(ClassUniversal, TagInteger, int64(req.SizeLimit)),
+		NewInteger(ClassUniversal, TagInteger, int64(c.timeout/time.Second)),
+		NewBoolean(false),
+		filter.Encode(),
+		attributes)
+
+	messageID, err := c.send(request)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*Entry
+	for {
+		op, err := c.receive(messageID)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case op.Is(ClassApplication, true, ApplicationSearchResultEntry):
+			entry, err := decodeEntry(op)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		case op.Is(ClassApplication, true, ApplicationSearchResultReference):
+			continue
+		case op.Is(ClassApplication, true, ApplicationSearchResultDone):
+			if err := resultError(op); err != nil {
+				// 超过条目数限制时仍返回已收到的条目
+				if IsResultCode(err, ResultSizeLimitExceeded) {
+					return entries, err
+				}
+				return nil, err
+			}
+			return entries, nil
+		default:
+			return nil, fmt.Errorf("ldap: 意外的响应类型 %d", op.Tag)
+		}
+	}
+}
+
+// Close 发送UnbindRequest并关闭连接
+func (c *Conn) Close() error {
+	_, _ = c.send(&Packet{Class: ClassApplication, Tag: ApplicationUnbindRequest})
+	return c.conn.Close()
+}
+
+// roundTrip 发送请求并读取指定类型的响应
+func (c *Conn) roundTrip(request *Packet, responseTag int) (*Packet, error) {
+	messageID, err := c.send(request)
+	if err != nil {
+		return nil, err
+	}
+	op, err := c.receive(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if !op.Is(ClassApplication, true, responseTag) {
+		return nil, fmt.Errorf("ldap: 意外的响应类型 %d", op.Tag)
+	}
+	return op, nil
+}
+
+// send 以新的消息ID发送请求
+func (c *Conn) send(op *Packet) (int64, error) {
+	c.messageID++
+	message := NewSequence(ClassUniversal, TagSequence,
+		NewInteger(ClassUniversal, TagInteger, c.messageID), op)
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+	if _, err := c.conn.Write(message.Bytes()); err != nil {
+		return 0, fmt.Errorf("ldap: 发送请求失败: %w", err)
+	}
+	return c.messageID, nil
+}
+
+// receive 读取指定消息ID的响应，返回其中的protocolOp
+func (c *Conn) receive(messageID int64) (*Packet, error) {
+	for {
+		message, err := ReadPacket(c.reader)
+		if err != nil {
+			return nil, fmt.Errorf("ldap: 读取响应失败: %w", err)
+		}
+		if !message.Is(ClassUniversal, true, TagSequence) || len(message.Children) < 2 {
+			return nil, errors.New("ldap: 无效的响应消息")
+		}
+		id, err := message.Children[0].Int()
+		if err != nil {
+			return nil, err
+		}
+		op := message.Children[1]
+		// 消息ID为0的是服务端主动发送的通知（如断开连接）
+		if id == 0 && op.Is(ClassApplication, true, ApplicationExtendedResponse) {
+			if err := resultError(op); err != nil {
+				return nil, err
+			}
+			return nil, errors.New("ldap: 服务端断开了连接")
+		}
+		if id == messageID {
+			return op, nil
+		}
+	}
+}
+
+// resultError 解析LDAPResult，非成功时返回*Error
+func resultError(op *Packet) error {
+	if len(op.Children) < 3 {
+		return errors.New("ldap: 无效的LDAPResult")
+	}
+	code, err := op.Children[0].Int()
+	if err != nil {
+		return err
+	}
+	if code == ResultSuccess {
+		return nil
+	}
+	return &Error{Code: int(code), Message: op.Children[2].String()}
+}
+
+// decodeEntry 解析SearchResultEntry
+func decodeEntry(op *Packet) (*Entry, error) {
+	if len(op.Children) != 2 {
+		return nil, errors.New("ldap: 无效的搜索结果")
+	}
+	entry := &Entry{DN: op.Children[0].String(), Attributes: make(map[string][]string)}
+	for _, attr := range op.Children[1].Children {
+		if len(attr.Children) != 2 {
+			return nil, errors.New("ldap: 无效的搜索结果属性")
+		}
+		name := attr.Children[0].String()
+		for _, value := range attr.Children[1].Children {
+			entry.Attributes[name] = append(entry.Attributes[name], value.String())
+		}
+	}
+	return entry, nil
+}
+
+// tlsConfigFor 复制TLS配置并补充ServerName
+func tlsConfigFor(cfg *tls.Config, host string) *tls.Config {
+	if cfg == nil {
+		return &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+	}
+	cfg = cfg.Clone()
+	if cfg.ServerName == "" {
+		cfg.ServerName = host
+	}
+	return cfg
+}
+
+// portOrDefault URL中没有端口时使用默认端口
+func portOrDefault(u *url.URL, port string) string {
+	if p := u.Port(); p != "" {
+		return p
+	}
+	return port
+}
@@ -0,0 +1,291 @@
+package ldap_test
+
+import (
+	"bufio"
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cuihe500/vaulthub/pkg/ldap"
+	"github.com/cuihe500/vaulthub/pkg/ldaptest"
+)
+
+const (
+	serviceDN = "cn=vaulthub,ou=services,dc=example,dc=com"
+	aliceDN   = "uid=alice,ou=people,dc=example,dc=com"
+	starDN    = "uid=a*,ou=people,dc=example,dc=com"
+)
+
+func newDirectory(t *testing.T) *ldaptest.Server {
+	t.Helper()
+	directory := ldaptest.New()
+	t.Cleanup(directory.Close)
+
+	directory.AddEntry(serviceDN, map[string][]string{"objectClass": {"applicationProcess"}, "cn": {"vaulthub"}})
+	directory.SetPassword(serviceDN, "service-secret")
+	directory.AddEntry(aliceDN, map[string][]string{
+		"objectClass": {"person"}, "uid": {"alice"}, "mail": {"alice@example.com"}, "cn": {"Alice"},
+	})
+	directory.SetPassword(aliceDN, "alice-secret")
+	directory.AddEntry(starDN, map[string][]string{"objectClass": {"person"}, "uid": {"a*"}, "cn": {"Star"}})
+	return directory
+}
+
+func dial(t *testing.T, cfg ldap.Config) *ldap.Conn {
+	t.Helper()
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	conn, err := ldap.Dial(cfg)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestBind(t *testing.T) {
+	directory := newDirectory(t)
+	conn := dial(t, ldap.Config{URL: directory.URL()})
+
+	if err := conn.Bind(aliceDN, "alice-secret"); err != nil {
+		t.Fatalf("正确的密码: %v", err)
+	}
+	if err := conn.Bind(aliceDN, "wrong"); !ldap.IsResultCode(err, ldap.ResultInvalidCredentials) {
+		t.Fatalf("错误的密码: err = %v", err)
+	}
+	if err := conn.Bind("uid=nobody,ou=people,dc=example,dc=com", "alice-secret"); !ldap.IsResultCode(err, ldap.ResultInvalidCredentials) {
+		t.Fatalf("不存在的DN: err = %v", err)
+	}
+}
+
+func TestBindRejectsEmptyPassword(t *testing.T) {
+	directory := newDirectory(t)
+	conn := dial(t, ldap.Config{URL: directory.URL()})
+
+	// 目录服务把空密码当作匿名绑定并返回成功，客户端必须在发送前拒绝
+	for _, dn := range []string{aliceDN, ""} {
+		if err := conn.Bind(dn, ""); !ldap.IsResultCode(err, ldap.ResultInvalidCredentials) {
+			t.Fatalf("Bind(%q, \"\"): err = %v", dn, err)
+		}
+	}
+
+	// 被拒绝的绑定没有把连接变成已认证状态
+	_, err := conn.Search(&ldap.SearchRequest{BaseDN: "dc=example,dc=com", Scope: ldap.ScopeWholeSubtree})
+	if !ldap.IsResultCode(err, ldap.ResultInsufficientAccessRights) {
+		t.Fatalf("未认证的搜索: err = %v", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	directory := newDirectory(t)
+	conn := dial(t, ldap.Config{URL: directory.URL()})
+	if err := conn.Bind(serviceDN, "service-secret"); err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+
+	entries, err := conn.Search(&ldap.SearchRequest{
+		BaseDN:     "ou=people,dc=example,dc=com",
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     "(&(objectClass=person)(uid=" + ldap.EscapeFilter("alice") + "))",
+		Attributes: []string{"mail", "cn"},
+	})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(entries) != 1 || entries[0].DN != aliceDN {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if got := entries[0].Value("MAIL"); got != "alice@example.com" {
+		t.Fatalf("mail = %q", got)
+	}
+	if got := entries[0].Value("uid"); got != "" {
+		t.Fatalf("返回了未请求的属性 uid = %q", got)
+	}
+
+	// 空过滤器等同于 (objectClass=*)
+	entries, err = conn.Search(&ldap.SearchRequest{BaseDN: "ou=people,dc=example,dc=com", Scope: ldap.ScopeWholeSubtree})
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("空过滤器: %d个条目, err = %v", len(entries), err)
+	}
+
+	// 条目数超过限制时返回已收到的条目和错误
+	entries, err = conn.Search(&ldap.SearchRequest{BaseDN: "dc=example,dc=com", Scope: ldap.ScopeWholeSubtree, SizeLimit: 1})
+	if !ldap.IsResultCode(err, ldap.ResultSizeLimitExceeded) || len(entries) != 1 {
+		t.Fatalf("SizeLimit: %d个条目, err = %v", len(entries), err)
+	}
+
+	_, err = conn.Search(&ldap.SearchRequest{BaseDN: "ou=missing,dc=example,dc=com", Scope: ldap.ScopeWholeSubtree})
+	if !ldap.IsResultCode(err, ldap.ResultNoSuchObject) {
+		t.Fatalf("不存在的BaseDN: err = %v", err)
+	}
+
+	if _, err := conn.Search(&ldap.SearchRequest{BaseDN: "dc=example,dc=com", Filter: "(uid=alice"}); err == nil {
+		t.Fatal("接受了无效的过滤器")
+	}
+}
+
+func TestSearchEscapesUserInput(t *testing.T) {
+	directory := newDirectory(t)
+	conn := dial(t, ldap.Config{URL: directory.URL()})
+	if err := conn.Bind(serviceDN, "service-secret"); err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+
+	search := func(username string) []*ldap.Entry {
+		t.Helper()
+		entries, err := conn.Search(&ldap.SearchRequest{
+			BaseDN: "ou=people,dc=example,dc=com",
+			Scope:  ldap.ScopeWholeSubtree,
+			Filter: "(&(objectClass=person)(uid=" + ldap.EscapeFilter(username) + "))",
+		})
+		if err != nil {
+			t.Fatalf("Search(%q): %v", username, err)
+		}
+		return entries
+	}
+
+	// 转义后的通配符只匹配字面值
+	if entries := search("a*"); len(entries) != 1 || entries[0].DN != starDN {
+		t.Fatalf("a*: entries = %+v", entries)
+	}
+	for _, username := range []string{"*", "*)(uid=*", "alice)(|(objectClass=*"} {
+		if entries := search(username); len(entries) != 0 {
+			t.Fatalf("%q 匹配到了 %+v", username, entries)
+		}
+	}
+}
+
+func TestStartTLS(t *testing.T) {
+	directory := newDirectory(t)
+	trusted := &tls.Config{RootCAs: directory.CertPool(), MinVersion: tls.VersionTLS12}
+
+	conn := dial(t, ldap.Config{URL: directory.URL(), StartTLS: true, TLSConfig: trusted})
+	if !conn.TLS() {
+		t.Fatal("StartTLS后连接未加密")
+	}
+	if err := conn.Bind(aliceDN, "alice-secret"); err != nil {
+		t.Fatalf("TLS连接上绑定: %v", err)
+	}
+	if err := conn.StartTLS(trusted); err == nil {
+		t.Fatal("已加密的连接再次StartTLS没有报错")
+	}
+
+	// 不信任目录服务的自签名证书时握手失败
+	if conn, err := ldap.Dial(ldap.Config{URL: directory.URL(), StartTLS: true, Timeout: 5 * time.Second}); err == nil {
+		_ = conn.Close()
+		t.Fatal("不受信任的证书通过了校验")
+	}
+
+	// 不要求StartTLS时保持明文
+	plain := dial(t, ldap.Config{URL: directory.URL()})
+	if plain.TLS() {
+		t.Fatal("未StartTLS的连接被标记为加密")
+	}
+}
+
+func TestDialInvalidURL(t *testing.T) {
+	for _, url := range []string{"http://127.0.0.1:389", "://bad"} {
+		if conn, err := ldap.Dial(ldap.Config{URL: url}); err == nil {
+			_ = conn.Close()
+			t.Fatalf("Dial(%q) 没有报错", url)
+		}
+	}
+}
+
+// fakeServer 读取一个请求后原样写出reply并关闭连接，用于构造异常响应
+func fakeServer(t *testing.T, reply []byte) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		if _, err := ldap.ReadPacket(bufio.NewReader(conn)); err != nil {
+			return
+		}
+		_, _ = conn.Write(reply)
+	}()
+	return "ldap://" + listener.Addr().String()
+}
+
+// message 按请求的消息ID 1 构造响应
+func message(op *ldap.Packet) []byte {
+	return ldap.NewSequence(ldap.ClassUniversal, ldap.TagSequence,
+		ldap.NewInteger(ldap.ClassUniversal, ldap.TagInteger, 1), op).Bytes()
+}
+
+func bindResponse(code int64) *ldap.Packet {
+	return ldap.NewSequence(ldap.ClassApplication, ldap.ApplicationBindResponse,
+		ldap.NewInteger(ldap.ClassUniversal, ldap.TagEnumerated, code),
+		ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, ""),
+		ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, ""))
+}
+
+func TestMalformedResponses(t *testing.T) {
+	valid := message(bindResponse(ldap.ResultSuccess))
+	tests := []struct {
+		name  string
+		reply []byte
+	}{
+		{"空响应", nil},
+		{"响应被截断", valid[:len(valid)-3]},
+		{"只有长度字段", valid[:2]},
+		{"长度超过上限", []byte{0x30, 0x84, 0x7f, 0xff, 0xff, 0xff}},
+		{"不是SEQUENCE", ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, "x").Bytes()},
+		{"缺少protocolOp", ldap.NewSequence(ldap.ClassUniversal, ldap.TagSequence,
+			ldap.NewInteger(ldap.ClassUniversal, ldap.TagInteger, 1)).Bytes()},
+		{"消息ID不是整数", ldap.NewSequence(ldap.ClassUniversal, ldap.TagSequence,
+			ldap.NewSequence(ldap.ClassUniversal, ldap.TagSequence), bindResponse(ldap.ResultSuccess)).Bytes()},
+		{"响应类型不符", message(ldap.NewSequence(ldap.ClassApplication, ldap.ApplicationSearchResultDone,
+			ldap.NewInteger(ldap.ClassUniversal, ldap.TagEnumerated, ldap.ResultSuccess),
+			ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, ""),
+			ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, "")))},
+		{"LDAPResult不完整", message(ldap.NewSequence(ldap.ClassApplication, ldap.ApplicationBindResponse,
+			ldap.NewInteger(ldap.ClassUniversal, ldap.TagEnumerated, ldap.ResultSuccess)))},
+		{"结果码为空", message(ldap.NewSequence(ldap.ClassApplication, ldap.ApplicationBindResponse,
+			ldap.NewString(ldap.ClassUniversal, ldap.TagEnumerated, ""),
+			ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, ""),
+			ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, "")))},
+		{"服务端断开通知", ldap.NewSequence(ldap.ClassUniversal, ldap.TagSequence,
+			ldap.NewInteger(ldap.ClassUniversal, ldap.TagInteger, 0),
+			ldap.NewSequence(ldap.ClassApplication, ldap.ApplicationExtendedResponse,
+				ldap.NewInteger(ldap.ClassUniversal, ldap.TagEnumerated, ldap.ResultSuccess),
+				ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, ""),
+				ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, ""))).Bytes()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := dial(t, ldap.Config{URL: fakeServer(t, tt.reply), Timeout: 2 * time.Second})
+			if err := conn.Bind(aliceDN, "alice-secret"); err == nil {
+				t.Fatal("Bind 接受了异常响应")
+			}
+		})
+	}
+
+	// 对照：格式正确的响应可以通过
+	conn := dial(t, ldap.Config{URL: fakeServer(t, valid), Timeout: 2 * time.Second})
+	if err := conn.Bind(aliceDN, "alice-secret"); err != nil {
+		t.Fatalf("正常响应: %v", err)
+	}
+}
+
+func TestMalformedSearchEntry(t *testing.T) {
+	// 属性缺少值集合的搜索结果
+	entry := ldap.NewSequence(ldap.ClassApplication, ldap.ApplicationSearchResultEntry,
+		ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, aliceDN),
+		ldap.NewSequence(ldap.ClassUniversal, ldap.TagSequence,
+			ldap.NewSequence(ldap.ClassUniversal, ldap.TagSequence,
+				ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, "mail"))))
+	conn := dial(t, ldap.Config{URL: fakeServer(t, message(entry)), Timeout: 2 * time.Second})
+	if _, err := conn.Search(&ldap.SearchRequest{BaseDN: "dc=example,dc=com"}); err == nil {
+		t.Fatal("Search 接受了异常的搜索结果")
+	}
+}
@@ -0,0 +1,534 @@
+// Package ldaptest 提供进程内的LDAP目录服务，用于在Go测试和本地开发中代替OpenLDAP或Active Directory
+//
+// 目录服务支持简单绑定、搜索（base/one/sub范围，常用过滤器）和StartTLS（使用自签名证书），
+// 条目保存在内存中，可以在运行中修改以模拟组成员变化：
+//
+//	directory := ldaptest.New()
+//	defer directory.Close()
+//	directory.AddEntry("uid=alice,ou=people,dc=example,dc=com", map[string][]string{
+//		"objectClass": {"person"}, "uid": {"alice"}, "mail": {"alice@example.com"}, "cn": {"Alice"},
+//	})
+//	directory.SetPassword("uid=alice,ou=people,dc=example,dc=com", "secret")
+//	directory.AddEntry("cn=vaulthub-admins,ou=groups,dc=example,dc=com", map[string][]string{
+//		"objectClass": {"groupOfNames"}, "cn": {"vaulthub-admins"}, "member": {"uid=alice,ou=people,dc=example,dc=com"},
+//	})
+//	// 将 directory.URL() 配置为 ldap.url，StartTLS时信任 directory.CertPool()
+package ldaptest
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cuihe500/vaulthub/pkg/ldap"
+)
+
+// entry 目录中的条目
+type entry struct {
+	dn         string
+	attributes map[string][]string
+}
+
+// Server 进程内的LDAP目录服务，可并发使用
+type Server struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+	certPool  *x509.CertPool
+
+	mu        sync.Mutex
+	entries   map[string]*entry // 规范化DN -> 条目
+	passwords map[string]string // 规范化DN -> 密码
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// New 在127.0.0.1的随机端口启动目录服务
+func New() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("ldaptest: 监听失败: %v", err))
+	}
+	cert, pool, err := selfSignedCertificate()
+	if err != nil {
+		panic(fmt.Sprintf("ldaptest: 生成证书失败: %v", err))
+	}
+
+	s := &Server{
+		listener:  listener,
+		tlsConfig: &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12},
+		certPool:  pool,
+		entries:   make(map[string]*entry),
+		passwords: make(map[string]string),
+		conns:     make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// URL 返回 ldap:// 地址
+func (s *Server) URL() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+// CertPool 返回信任StartTLS证书的证书池（证书对127.0.0.1和localhost有效）
+func (s *Server) CertPool() *x509.CertPool {
+	return s.certPool
+}
+
+// Close 关闭目录服务和全部连接
+func (s *Server) Close() {
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	_ = s.listener.Close()
+	s.wg.Wait()
+}
+
+// AddEntry 添加条目，DN已存在时替换其属性
+func (s *Server) AddEntry(dn string, attributes map[string][]string) {
+	copied := make(map[string][]string, len(attributes))
+	for name, values := range attributes {
+		copied[name] = append([]string(nil), values...)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[normalizeDN(dn)] = &entry{dn: dn, attributes: copied}
+}
+
+// DeleteEntry 删除条目和它的密码
+func (s *Server) DeleteEntry(dn string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, normalizeDN(dn))
+	delete(s.passwords, normalizeDN(dn))
+}
+
+// SetAttribute 替换条目的一个属性，values为空时删除该属性
+func (s *Server) SetAttribute(dn, name string, values ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[normalizeDN(dn)]
+	if !ok {
+		return
+	}
+	for attr := range e.attributes {
+		if strings.EqualFold(attr, name) {
+			delete(e.attributes, attr)
+		}
+	}
+	if len(values) > 0 {
+		e.attributes[name] = append([]string(nil), values...)
+	}
+}
+
+// SetPassword 设置条目的绑定密码
+func (s *Server) SetPassword(dn, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.passwords[normalizeDN(dn)] = password
+}
+
+// serve 接受连接
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+// session 一个连接的状态
+type session struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	bound  bool // 是否已通过认证绑定
+	tls    bool
+}
+
+// handle 处理一个连接上的请求，直到客户端解绑或断开
+func (s *Server) handle(conn net.Conn) {
+	defer s.wg.Done()
+	sess := &session{conn: conn, reader: bufio.NewReader(conn)}
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, sess.conn)
+		s.mu.Unlock()
+		_ = sess.conn.Close()
+	}()
+
+	for {
+		message, err := ldap.ReadPacket(sess.reader)
+		if err != nil {
+			return
+		}
+		if !message.Is(ldap.ClassUniversal, true, ldap.TagSequence) || len(message.Children) < 2 {
+			return
+		}
+		id, err := message.Children[0].Int()
+		if err != nil {
+			return
+		}
+		op := message.Children[1]
+		if op.Class != ldap.ClassApplication {
+			return
+		}
+
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			sess.write(id, s.bind(sess, op))
+		case ldap.ApplicationSearchRequest:
+			if !s.search(sess, id, op) {
+				return
+			}
+		case ldap.ApplicationExtendedRequest:
+			if !s.extended(sess, id, op) {
+				return
+			}
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			sess.write(id, result(ldap.ApplicationExtendedResponse, ldap.ResultProtocolError, "不支持的操作"))
+		}
+	}
+}
+
+// bind 处理简单绑定；匿名绑定成功但不获得搜索权限，未认证绑定（有DN无密码）被拒绝
+func (s *Server) bind(sess *session, op *ldap.Packet) *ldap.Packet {
+	sess.bound = false
+	if len(op.Children) != 3 {
+		return result(ldap.ApplicationBindResponse, ldap.ResultProtocolError, "无效的绑定请求")
+	}
+	dn := op.Children[1].String()
+	auth := op.Children[2]
+	if !auth.Is(ldap.ClassContext, false, 0) {
+		return result(ldap.ApplicationBindResponse, ldap.ResultAuthMethodNotSupported, "只支持简单绑定")
+	}
+	password := auth.String()
+	switch {
+	case dn == "" && password == "":
+		return result(ldap.ApplicationBindResponse, ldap.ResultSuccess, "")
+	case password == "":
+		return result(ldap.ApplicationBindResponse, ldap.ResultUnwillingToPerform, "不允许未认证绑定")
+	}
+
+	s.mu.Lock()
+	expected, ok := s.passwords[normalizeDN(dn)]
+	s.mu.Unlock()
+	if !ok || expected != password {
+		return result(ldap.ApplicationBindResponse, ldap.ResultInvalidCredentials, "")
+	}
+	sess.bound = true
+	return result(ldap.ApplicationBindResponse, ldap.ResultSuccess, "")
+}
+
+// search 处理搜索请求，只允许已认证的连接搜索；返回false时关闭连接
+func (s *Server) search(sess *session, id int64, op *ldap.Packet) bool {
+	if !sess.bound {
+		return sess.write(id, result(ldap.ApplicationSearchResultDone, ldap.ResultInsufficientAccessRights, "需要先绑定"))
+	}
+	if len(op.Children) != 8 {
+		return sess.write(id, result(ldap.ApplicationSearchResultDone, ldap.ResultProtocolError, "无效的搜索请求"))
+	}
+	baseDN := normalizeDN(op.Children[0].String())
+	scope, _ := op.Children[1].Int()
+	sizeLimit, _ := op.Children[3].Int()
+	filter, err := ldap.DecodeFilter(op.Children[6])
+	if err != nil {
+		return sess.write(id, result(ldap.ApplicationSearchResultDone, ldap.ResultProtocolError, err.Error()))
+	}
+	var requested []string
+	for _, attr := range op.Children[7].Children {
+		requested = append(requested, attr.String())
+	}
+
+	// 不要求显式创建ou等容器条目：BaseDN本身或其下有条目即视为存在
+	s.mu.Lock()
+	exists := baseDN == ""
+	var matched []*ldap.Packet
+	for key, e := range s.entries {
+		if inScope(key, baseDN, ldap.ScopeWholeSubtree) {
+			exists = true
+		}
+		if !inScope(key, baseDN, ldap.Scope(scope)) || !match(filter, e.attributes) {
+			continue
+		}
+		matched = append(matched, encodeEntry(e, requested))
+	}
+	s.mu.Unlock()
+	if !exists {
+		return sess.write(id, result(ldap.ApplicationSearchResultDone, ldap.ResultNoSuchObject, ""))
+	}
+
+	// 按DN排序，保证结果稳定
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].Children[0].String() < matched[j].Children[0].String()
+	})
+	for i, e := range matched {
+		if sizeLimit > 0 && int64(i) >= sizeLimit {
+			return sess.write(id, result(ldap.ApplicationSearchResultDone, ldap.ResultSizeLimitExceeded, ""))
+		}
+		if !sess.write(id, e) {
+			return false
+		}
+	}
+	return sess.write(id, result(ldap.ApplicationSearchResultDone, ldap.ResultSuccess, ""))
+}
+
+// extended 处理扩展操作，只支持StartTLS；返回false时关闭连接
+func (s *Server) extended(sess *session, id int64, op *ldap.Packet) bool {
+	name := op.Child(0)
+	if name == nil || name.String() != ldap.OIDStartTLS {
+		return sess.write(id, result(ldap.ApplicationExtendedResponse, ldap.ResultProtocolError, "不支持的扩展操作"))
+	}
+	if sess.tls {
+		return sess.write(id, result(ldap.ApplicationExtendedResponse, ldap.ResultOperationsError, "连接已经是TLS"))
+	}
+	if !sess.write(id, result(ldap.ApplicationExtendedResponse, ldap.ResultSuccess, "")) {
+		return false
+	}
+
+	tlsConn := tls.Server(sess.conn, s.tlsConfig)
+	if err := tlsConn.SetDeadline(time.Now().Add(10 * time.Second)); err != nil {
+		return false
+	}
+	if err := tlsConn.Handshake(); err != nil {
+		return false
+	}
+	_ = tlsConn.SetDeadline(time.Time{})
+
+	s.mu.Lock()
+	delete(s.conns, sess.conn)
+	s.conns[tlsConn] = struct{}{}
+	s.mu.Unlock()
+	sess.conn = tlsConn
+	sess.reader = bufio.NewReader(tlsConn)
+	sess.tls = true
+	return true
+}
+
+// write 发送一个响应消息
+func (sess *session) write(id int64, op *ldap.Packet) bool {
+	message := ldap.NewSequence(ldap.ClassUniversal, ldap.TagSequence,
+		ldap.NewInteger(ldap.ClassUniversal, ldap.TagInteger, id), op)
+	_, err := sess.conn.Write(message.Bytes())
+	return err == nil
+}
+
+// result 构造LDAPResult响应
+func result(tag, code int, message string) *ldap.Packet {
+	return ldap.NewSequence(ldap.ClassApplication, tag,
+		ldap.NewInteger(ldap.ClassUniversal, ldap.TagEnumerated, int64(code)),
+		ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, ""),
+		ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, message))
+}
+
+// encodeEntry 构造SearchResultEntry，requested为空或包含*时返回全部属性
+func encodeEntry(e *entry, requested []string) *ldap.Packet {
+	all := len(requested) == 0
+	for _, name := range requested {
+		if name == "*" {
+			all = true
+		}
+	}
+
+	names := make([]string, 0, len(e.attributes))
+	for name := range e.attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attributes := ldap.NewSequence(ldap.ClassUniversal, ldap.TagSequence)
+	for _, name := range names {
+		if !all && !containsFold(requested, name) {
+			continue
+		}
+		values := ldap.NewSequence(ldap.ClassUniversal, ldap.TagSet)
+		for _, value := range e.attributes[name] {
+			values.Append(ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, value))
+		}
+		attributes.Append(ldap.NewSequence(ldap.ClassUniversal, ldap.TagSequence,
+			ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, name), values))
+	}
+	return ldap.NewSequence(ldap.ClassApplication, ldap.ApplicationSearchResultEntry,
+		ldap.NewString(ldap.ClassUniversal, ldap.TagOctetString, e.dn), attributes)
+}
+
+// match 判断条目是否满足过滤器，值的比较不区分大小写，DN值按规范化形式比较
+func match(f *ldap.Filter, attributes map[string][]string) bool {
+	switch f.Type {
+	case ldap.FilterAnd:
+		for _, child := range f.Children {
+			if !match(child, attributes) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range f.Children {
+			if match(child, attributes) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return !match(f.Children[0], attributes)
+	}
+
+	values := attributeValues(attributes, f.Attribute)
+	if f.Type == ldap.FilterPresent {
+		// 真实目录中每个条目都有objectClass，测试数据可以省略
+		return len(values) > 0 || strings.EqualFold(f.Attribute, "objectClass")
+	}
+	for _, value := range values {
+		v, target := normalizeValue(value), normalizeValue(f.Value)
+		switch f.Type {
+		case ldap.FilterEqualityMatch, ldap.FilterApproxMatch:
+			if v == target {
+				return true
+			}
+		case ldap.FilterGreaterOrEqual:
+			if v >= target {
+				return true
+			}
+		case ldap.FilterLessOrEqual:
+			if v <= target {
+				return true
+			}
+		case ldap.FilterSubstrings:
+			if matchSubstrings(v, f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchSubstrings 子串匹配
+func matchSubstrings(value string, f *ldap.Filter) bool {
+	initial, final := strings.ToLower(f.Initial), strings.ToLower(f.Final)
+	if !strings.HasPrefix(value, initial) {
+		return false
+	}
+	value = value[len(initial):]
+	for _, part := range f.Any {
+		i := strings.Index(value, strings.ToLower(part))
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+	return strings.HasSuffix(value, final)
+}
+
+// attributeValues 读取属性值，属性名不区分大小写
+func attributeValues(attributes map[string][]string, name string) []string {
+	for attr, values := range attributes {
+		if strings.EqualFold(attr, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+// inScope 判断条目是否在搜索范围内
+func inScope(dn, baseDN string, scope ldap.Scope) bool {
+	switch scope {
+	case ldap.ScopeBaseObject:
+		return dn == baseDN
+	case ldap.ScopeSingleLevel:
+		parent := ""
+		if i := strings.IndexByte(dn, ','); i >= 0 {
+			parent = dn[i+1:]
+		}
+		return dn != baseDN && parent == baseDN
+	default:
+		return baseDN == "" || dn == baseDN || strings.HasSuffix(dn, ","+baseDN)
+	}
+}
+
+// normalizeDN 规范化DN用于比较：去掉RDN之间的空格并转为小写（不处理转义的逗号）
+func normalizeDN(dn string) string {
+	parts := strings.Split(dn, ",")
+	for i, part := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(part))
+	}
+	return strings.Join(parts, ",")
+}
+
+// normalizeValue 规范化属性值用于比较，DN形式的值（如member）按DN规范化
+func normalizeValue(value string) string {
+	if strings.Contains(value, "=") {
+		return normalizeDN(value)
+	}
+	return strings.ToLower(value)
+}
+
+// containsFold 不区分大小写地判断是否包含
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// selfSignedCertificate 生成对127.0.0.1和localhost有效的自签名证书
+func selfSignedCertificate() (tls.Certificate, *x509.CertPool, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ldaptest"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:              []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, pool, nil
+}
@@ -0,0 +1,114 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"testing"
+)
+
+func TestJSONWebKeyRoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []interface{}{&rsaKey.PublicKey}
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		ecKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, &ecKey.PublicKey)
+	}
+
+	for _, key := range keys {
+		jwk, err := NewJSONWebKey("kid", key)
+		if err != nil {
+			t.Fatalf("NewJSONWebKey(%T): %v", key, err)
+		}
+		parsed, err := jwk.publicKey()
+		if err != nil {
+			t.Fatalf("publicKey(%+v): %v", jwk, err)
+		}
+		switch want := key.(type) {
+		case *rsa.PublicKey:
+			if !want.Equal(parsed) {
+				t.Fatalf("RSA公钥不一致")
+			}
+		case *ecdsa.PublicKey:
+			if !want.Equal(parsed) {
+				t.Fatalf("%s公钥不一致", want.Curve.Params().Name)
+			}
+		}
+	}
+
+	if _, err := NewJSONWebKey("kid", "not a key"); err == nil {
+		t.Fatal("NewJSONWebKey 接受了不支持的公钥类型")
+	}
+}
+
+func TestJSONWebKeyInvalid(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid, err := NewJSONWebKey("kid", &ecKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64 := base64.RawURLEncoding.EncodeToString
+
+	offCurve := valid
+	offCurve.Y = b64([]byte{1})
+	wrongCurve := valid
+	wrongCurve.Crv = "P-224"
+	badX := valid
+	badX.X = "!!"
+
+	rsaKey := JSONWebKey{Kty: "RSA", N: b64([]byte{0xc5, 0x01}), E: "AQAB"}
+	smallExponent := rsaKey
+	smallExponent.E = b64([]byte{1})
+	largeExponent := rsaKey
+	largeExponent.E = b64([]byte{0x01, 0x00, 0x00, 0x00, 0x00})
+	badModulus := rsaKey
+	badModulus.N = "@@"
+
+	for name, jwk := range map[string]JSONWebKey{
+		"点不在曲线上":  offCurve,
+		"不支持的曲线":  wrongCurve,
+		"坐标编码无效":  badX,
+		"RSA指数过小": smallExponent,
+		"RSA指数过大": largeExponent,
+		"模数编码无效":  badModulus,
+		"对称密钥":    {Kty: "oct", Kid: "kid"},
+		"缺少kty":   {Kid: "kid"},
+	} {
+		if key, err := jwk.publicKey(); err == nil {
+			t.Errorf("%s: publicKey() = %v, 应当报错", name, key)
+		}
+	}
+}
+
+func TestS256Challenge(t *testing.T) {
+	// RFC 7636 附录B的示例
+	if got := S256Challenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"); got != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Fatalf("S256Challenge = %q", got)
+	}
+
+	verifier, challenge, err := GeneratePKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// RFC 7636 要求code_verifier为43到128个字符
+	if len(verifier) < 43 || len(verifier) > 128 {
+		t.Fatalf("code_verifier长度 = %d", len(verifier))
+	}
+	if challenge != S256Challenge(verifier) {
+		t.Fatal("code_challenge与code_verifier不对应")
+	}
+	if other, _, _ := GeneratePKCE(); other == verifier {
+		t.Fatal("两次生成的code_verifier相同")
+	}
+}
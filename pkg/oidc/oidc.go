@@ -0,0 +1,380 @@
+// Package oidc 实现 OpenID Connect 授权码流程（PKCE）的依赖方
+//
+// 只实现VaultHub登录需要的部分：发现文档、构造授权地址、用授权码换取令牌、
+// 按JWKS验证ID令牌（RS256/RS384/RS512/ES256/ES384/ES512）。
+//
+//	provider, err := oidc.NewProvider(oidc.Config{Issuer: "https://idp.example.com", ClientID: "vaulthub", RedirectURL: "https://vault.example.com/sso/callback"})
+//	verifier, challenge, err := oidc.GeneratePKCE()
+//	authURL, err := provider.AuthCodeURL(ctx, state, nonce, challenge)
+//	// 浏览器跳转到authURL，身份提供方回调时带回code和state
+//	tokens, err := provider.Exchange(ctx, code, verifier)
+//	claims, err := provider.VerifyIDToken(ctx, tokens.IDToken, nonce)
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 默认参数
+const (
+	defaultTimeout   = 10 * time.Second
+	maxResponseSize  = 1 << 20
+	jwksMinRefresh   = time.Minute // 遇到未知kid时重新获取JWKS的最小间隔，避免被伪造的令牌放大请求
+	idTokenClockSkew = time.Minute
+)
+
+// DefaultScopes 默认申请的scope
+var DefaultScopes = []string{"openid", "profile", "email"}
+
+// ErrInvalidIDToken ID令牌验证失败
+var ErrInvalidIDToken = errors.New("oidc: ID令牌无效")
+
+// Config 依赖方配置
+type Config struct {
+	Issuer       string       // 身份提供方的issuer，发现文档为 {Issuer}/.well-known/openid-configuration
+	ClientID     string       // 在身份提供方注册的客户端ID
+	ClientSecret string       // 客户端密钥，公开客户端为空（只使用PKCE）
+	RedirectURL  string       // 在身份提供方注册的回调地址
+	Scopes       []string     // 为空时使用DefaultScopes
+	HTTPClient   *http.Client // 为空时使用10秒超时的默认客户端
+}
+
+// Metadata 发现文档中使用的字段
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint,omitempty"`
+}
+
+// Tokens 令牌端点的响应
+type Tokens struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Claims 验证通过的ID令牌声明
+type Claims struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+	Raw               map[string]interface{} // 全部声明，用于读取自定义声明（如groups）
+}
+
+// StringValues 读取字符串或字符串数组类型的声明，不存在或类型不符时返回nil
+func (c *Claims) StringValues(name string) []string {
+	switch v := c.Raw[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// Provider 身份提供方，首次使用时获取发现文档，可被多个goroutine共用
+type Provider struct {
+	cfg        Config
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	metadata *Metadata
+	keys     map[string]interface{} // kid -> 公钥
+	keysAt   time.Time
+}
+
+// NewProvider 创建身份提供方，不访问网络
+func NewProvider(cfg Config) (*Provider, error) {
+	if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return nil, errors.New("oidc: issuer、client_id和redirect_url不能为空")
+	}
+	if _, err := url.ParseRequestURI(cfg.RedirectURL); err != nil {
+		return nil, fmt.Errorf("oidc: 无效的redirect_url: %w", err)
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = DefaultScopes
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Provider{cfg: cfg, httpClient: httpClient}, nil
+}
+
+// GeneratePKCE 生成PKCE的code_verifier和S256方式的code_challenge
+func GeneratePKCE() (verifier, challenge string, err error) {
+	verifier, err = RandomString(32)
+	if err != nil {
+		return "", "", err
+	}
+	return verifier, S256Challenge(verifier), nil
+}
+
+// S256Challenge 计算code_verifier对应的code_challenge
+func S256Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// RandomString 生成n字节随机数的base64url编码，用于state、nonce和code_verifier
+func RandomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("oidc: 生成随机数失败: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Discover 返回发现文档，成功后缓存，失败时下次调用重试
+func (p *Provider) Discover(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	var metadata Metadata
+	discoveryURL := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, discoveryURL, &metadata); err != nil {
+		return nil, fmt.Errorf("oidc: 获取发现文档失败: %w", err)
+	}
+	// 发现文档中的issuer必须与配置一致，防止被引导到其他身份提供方
+	if metadata.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("oidc: 发现文档的issuer %q 与配置的 %q 不一致", metadata.Issuer, p.cfg.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, errors.New("oidc: 发现文档缺少authorization_endpoint、token_endpoint或jwks_uri")
+	}
+	p.metadata = &metadata
+	return p.metadata, nil
+}
+
+// AuthCodeURL 构造授权地址
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	metadata, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	authURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("oidc: 无效的authorization_endpoint: %w", err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+	return authURL.String(), nil
+}
+
+// Exchange 用授权码和code_verifier换取令牌
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*Tokens, error) {
+	metadata, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	if p.cfg.ClientSecret == "" {
+		form.Set("client_id", p.cfg.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("oidc: 创建令牌请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		// client_secret_basic：按RFC 6749 2.3.1先做表单编码
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: 请求令牌端点失败: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("oidc: 读取令牌响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		_ = json.Unmarshal(body, &oauthErr)
+		return nil, fmt.Errorf("oidc: 令牌端点返回 %d: %s %s", resp.StatusCode, oauthErr.Error, oauthErr.Description)
+	}
+
+	var tokens Tokens
+	if err := json.Unmarshal(body, &tokens); err != nil {
+		return nil, fmt.Errorf("oidc: 解析令牌响应失败: %w", err)
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("oidc: 令牌响应中没有id_token")
+	}
+	return &tokens, nil
+}
+
+// VerifyIDToken 验证ID令牌的签名、issuer、audience、有效期和nonce，返回声明
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*Claims, error) {
+	if _, err := p.Discover(ctx); err != nil {
+		return nil, err
+	}
+
+	mapClaims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, mapClaims,
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return p.publicKey(ctx, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(p.cfg.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(idTokenClockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	// 存在多个audience时必须有azp；azp存在时必须是当前客户端
+	rawAzp, hasAzp := mapClaims["azp"]
+	if aud, _ := mapClaims.GetAudience(); len(aud) > 1 && !hasAzp {
+		return nil, fmt.Errorf("%w: 缺少azp", ErrInvalidIDToken)
+	}
+	if azp, _ := rawAzp.(string); hasAzp && azp != p.cfg.ClientID {
+		return nil, fmt.Errorf("%w: azp不是当前客户端", ErrInvalidIDToken)
+	}
+	if tokenNonce, _ := mapClaims["nonce"].(string); tokenNonce != nonce {
+		return nil, fmt.Errorf("%w: nonce不匹配", ErrInvalidIDToken)
+	}
+
+	claims := &Claims{Raw: map[string]interface{}(mapClaims)}
+	claims.Issuer, _ = mapClaims["iss"].(string)
+	claims.Subject, _ = mapClaims["sub"].(string)
+	claims.Email, _ = mapClaims["email"].(string)
+	claims.Name, _ = mapClaims["name"].(string)
+	claims.PreferredUsername, _ = mapClaims["preferred_username"].(string)
+	switch v := mapClaims["email_verified"].(type) {
+	case bool:
+		claims.EmailVerified = v
+	case string:
+		// 部分身份提供方以字符串返回
+		claims.EmailVerified = v == "true"
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: 缺少sub", ErrInvalidIDToken)
+	}
+	return claims, nil
+}
+
+// publicKey 按kid查找签名公钥，找不到时重新获取JWKS（身份提供方轮换了签名密钥）
+func (p *Provider) publicKey(ctx context.Context, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	if p.keys != nil && time.Since(p.keysAt) < jwksMinRefresh {
+		return nil, fmt.Errorf("未知的签名密钥: %q", kid)
+	}
+
+	var set jsonWebKeySet
+	if err := p.getJSON(ctx, p.metadata.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("获取JWKS失败: %w", err)
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// 跳过不支持的密钥类型，不影响其他密钥
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	p.keys = keys
+	p.keysAt = time.Now()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("未知的签名密钥: %q", kid)
+}
+
+// lookupKey 在缓存的JWKS中查找公钥；令牌没有kid且JWKS只有一个密钥时使用该密钥
+func (p *Provider) lookupKey(kid string) (interface{}, bool) {
+	if key, ok := p.keys[kid]; ok {
+		return key, true
+	}
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// getJSON 发送GET请求并解析JSON响应
+func (p *Provider) getJSON(ctx context.Context, target string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s 返回 %d", target, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(out)
+}
@@ -0,0 +1,389 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cuihe500/vaulthub/pkg/oidc"
+	"github.com/cuihe500/vaulthub/pkg/oidctest"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	clientID    = "vaulthub"
+	redirectURL = "http://localhost:8080/sso/callback"
+	nonce       = "expected-nonce"
+)
+
+// countingTransport 统计对JWKS的请求次数
+type countingTransport struct {
+	jwks atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.URL.Path, "/jwks") {
+		t.jwks.Add(1)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newProvider(t *testing.T, clientSecret string) (*oidc.Provider, *oidctest.Issuer, *countingTransport) {
+	t.Helper()
+	issuer := oidctest.New()
+	t.Cleanup(issuer.Close)
+	issuer.RegisterClient(clientID, clientSecret, redirectURL)
+
+	transport := &countingTransport{}
+	provider, err := oidc.NewProvider(oidc.Config{
+		Issuer:       issuer.Issuer(),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		HTTPClient:   &http.Client{Transport: transport, Timeout: 5 * time.Second},
+	})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	return provider, issuer, transport
+}
+
+// validClaims 返回能通过验证的声明，测试在此基础上修改
+func validClaims(issuer *oidctest.Issuer) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":   issuer.Issuer(),
+		"sub":   "alice",
+		"aud":   clientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": nonce,
+	}
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	provider, issuer, _ := newProvider(t, "secret")
+	idToken, err := issuer.SignIDToken(clientID, nonce, oidctest.User{
+		Subject:           "alice",
+		Email:             "alice@example.com",
+		EmailVerified:     true,
+		Name:              "Alice",
+		PreferredUsername: "alice",
+		Groups:            []string{"admins", "engineering"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := provider.VerifyIDToken(context.Background(), idToken, nonce)
+	if err != nil {
+		t.Fatalf("VerifyIDToken: %v", err)
+	}
+	if claims.Issuer != issuer.Issuer() || claims.Subject != "alice" || claims.Email != "alice@example.com" ||
+		!claims.EmailVerified || claims.Name != "Alice" || claims.PreferredUsername != "alice" {
+		t.Fatalf("claims = %+v", claims)
+	}
+	if groups := claims.StringValues("groups"); len(groups) != 2 || groups[0] != "admins" || groups[1] != "engineering" {
+		t.Fatalf("groups = %v", groups)
+	}
+	if values := claims.StringValues("preferred_username"); len(values) != 1 || values[0] != "alice" {
+		t.Fatalf("preferred_username = %v", values)
+	}
+	if values := claims.StringValues("missing"); values != nil {
+		t.Fatalf("missing = %v", values)
+	}
+}
+
+func TestVerifyIDTokenClaims(t *testing.T) {
+	provider, issuer, _ := newProvider(t, "secret")
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		modify func(jwt.MapClaims)
+		valid  bool
+	}{
+		{"有效", func(jwt.MapClaims) {}, true},
+		{"iss不一致", func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }, false},
+		{"缺少iss", func(c jwt.MapClaims) { delete(c, "iss") }, false},
+		{"aud不一致", func(c jwt.MapClaims) { c["aud"] = "other-client" }, false},
+		{"aud中没有当前客户端", func(c jwt.MapClaims) { c["aud"] = []string{"a", "b"} }, false},
+		{"缺少aud", func(c jwt.MapClaims) { delete(c, "aud") }, false},
+		{"多个aud缺少azp", func(c jwt.MapClaims) { c["aud"] = []string{clientID, "other-client"} }, false},
+		{"多个aud且azp不一致", func(c jwt.MapClaims) {
+			c["aud"] = []string{clientID, "other-client"}
+			c["azp"] = "other-client"
+		}, false},
+		{"多个aud且azp为当前客户端", func(c jwt.MapClaims) {
+			c["aud"] = []string{clientID, "other-client"}
+			c["azp"] = clientID
+		}, true},
+		{"单个aud且azp不一致", func(c jwt.MapClaims) { c["azp"] = "other-client" }, false},
+		{"azp不是字符串", func(c jwt.MapClaims) { c["azp"] = 1 }, false},
+		{"单个aud且azp为当前客户端", func(c jwt.MapClaims) { c["azp"] = clientID }, true},
+		{"缺少exp", func(c jwt.MapClaims) { delete(c, "exp") }, false},
+		{"exp不是数字", func(c jwt.MapClaims) { c["exp"] = "tomorrow" }, false},
+		{"已过期", func(c jwt.MapClaims) { c["exp"] = now.Add(-2 * time.Minute).Unix() }, false},
+		{"过期时间在时钟偏差内", func(c jwt.MapClaims) { c["exp"] = now.Add(-30 * time.Second).Unix() }, true},
+		{"iat在未来", func(c jwt.MapClaims) { c["iat"] = now.Add(10 * time.Minute).Unix() }, false},
+		{"缺少sub", func(c jwt.MapClaims) { delete(c, "sub") }, false},
+		{"nonce不匹配", func(c jwt.MapClaims) { c["nonce"] = "other-nonce" }, false},
+		{"缺少nonce", func(c jwt.MapClaims) { delete(c, "nonce") }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims(issuer)
+			tt.modify(claims)
+			idToken, err := issuer.SignClaims(claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = provider.VerifyIDToken(context.Background(), idToken, nonce)
+			if tt.valid && err != nil {
+				t.Fatalf("VerifyIDToken: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, oidc.ErrInvalidIDToken) {
+				t.Fatalf("err = %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenAlgorithms(t *testing.T) {
+	provider, issuer, _ := newProvider(t, "secret")
+	claims := validClaims(issuer)
+
+	// alg为none的令牌
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 以客户端密钥作为HMAC密钥签名，模拟算法混淆
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	hmacToken.Header["kid"] = "oidctest"
+	hmacSigned, err := hmacToken.SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 不在允许列表中的RSA算法
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pssToken := jwt.NewWithClaims(jwt.SigningMethodPS256, claims)
+	pssToken.Header["kid"] = "oidctest"
+	pssSigned, err := pssToken.SignedString(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed := mustSign(t, issuer, claims)
+	tampered := signed[:len(signed)-4] + "AAAA"
+
+	for name, idToken := range map[string]string{
+		"none":  unsigned,
+		"HS256": hmacSigned,
+		"PS256": pssSigned,
+		"格式错误":  "not.a.jwt",
+		"空令牌":   "",
+		"签名被篡改": tampered,
+	} {
+		if _, err := provider.VerifyIDToken(context.Background(), idToken, nonce); !errors.Is(err, oidc.ErrInvalidIDToken) {
+			t.Errorf("%s: err = %v, want ErrInvalidIDToken", name, err)
+		}
+	}
+}
+
+func TestVerifyIDTokenUnknownKey(t *testing.T) {
+	provider, issuer, transport := newProvider(t, "secret")
+	ctx := context.Background()
+	claims := validClaims(issuer)
+
+	if _, err := provider.VerifyIDToken(ctx, mustSign(t, issuer, claims), nonce); err != nil {
+		t.Fatalf("VerifyIDToken: %v", err)
+	}
+	if n := transport.jwks.Load(); n != 1 {
+		t.Fatalf("JWKS请求次数 = %d, want 1", n)
+	}
+
+	// 身份提供方不认识的签名密钥
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreign := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	foreign.Header["kid"] = "rotated"
+	foreignSigned, err := foreign.SignedString(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 冒用已知kid但用其他密钥签名
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	impostor := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	impostor.Header["kid"] = "oidctest"
+	impostorSigned, err := impostor.SignedString(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, idToken := range map[string]string{"未知kid": foreignSigned, "密钥不匹配": impostorSigned} {
+		for i := 0; i < 3; i++ {
+			if _, err := provider.VerifyIDToken(ctx, idToken, nonce); !errors.Is(err, oidc.ErrInvalidIDToken) {
+				t.Fatalf("%s: err = %v, want ErrInvalidIDToken", name, err)
+			}
+		}
+	}
+	// 刚获取过JWKS时未知kid不会触发重新获取，伪造的令牌不能放大对身份提供方的请求
+	if n := transport.jwks.Load(); n != 1 {
+		t.Fatalf("JWKS请求次数 = %d, want 1", n)
+	}
+}
+
+func TestDiscoverIssuerMismatch(t *testing.T) {
+	issuer := oidctest.New()
+	defer issuer.Close()
+
+	provider, err := oidc.NewProvider(oidc.Config{Issuer: issuer.Issuer() + "/", ClientID: clientID, RedirectURL: redirectURL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.Discover(context.Background()); err == nil {
+		t.Fatal("发现文档的issuer与配置不一致时没有报错")
+	}
+}
+
+func TestAuthorizationCodeFlowWithPKCE(t *testing.T) {
+	for name, clientSecret := range map[string]string{"机密客户端": "secret", "公开客户端": ""} {
+		t.Run(name, func(t *testing.T) {
+			provider, issuer, _ := newProvider(t, clientSecret)
+			issuer.SetUser(oidctest.User{Subject: "alice", Email: "alice@example.com", EmailVerified: true})
+			ctx := context.Background()
+
+			verifier, challenge, err := oidc.GeneratePKCE()
+			if err != nil {
+				t.Fatal(err)
+			}
+			authURL, err := provider.AuthCodeURL(ctx, "state-1", nonce, challenge)
+			if err != nil {
+				t.Fatalf("AuthCodeURL: %v", err)
+			}
+			query := mustParseQuery(t, authURL)
+			if query.Get("code_challenge") != challenge || query.Get("code_challenge_method") != "S256" ||
+				query.Get("nonce") != nonce || query.Get("redirect_uri") != redirectURL || query.Get("client_id") != clientID {
+				t.Fatalf("授权地址参数不完整: %v", query)
+			}
+
+			// 回调带回授权码和原样的state
+			code, state, err := issuer.Authorize(authURL)
+			if err != nil {
+				t.Fatalf("Authorize: %v", err)
+			}
+			if state != "state-1" || code == "" {
+				t.Fatalf("code = %q, state = %q", code, state)
+			}
+
+			tokens, err := provider.Exchange(ctx, code, verifier)
+			if err != nil {
+				t.Fatalf("Exchange: %v", err)
+			}
+			claims, err := provider.VerifyIDToken(ctx, tokens.IDToken, nonce)
+			if err != nil {
+				t.Fatalf("VerifyIDToken: %v", err)
+			}
+			if claims.Subject != "alice" || !claims.EmailVerified {
+				t.Fatalf("claims = %+v", claims)
+			}
+
+			// 授权码只能使用一次
+			if _, err := provider.Exchange(ctx, code, verifier); err == nil {
+				t.Fatal("授权码被重复使用")
+			}
+			// 使用其他登录的nonce验证失败
+			if _, err := provider.VerifyIDToken(ctx, tokens.IDToken, "other-nonce"); !errors.Is(err, oidc.ErrInvalidIDToken) {
+				t.Fatalf("nonce不匹配: err = %v", err)
+			}
+		})
+	}
+}
+
+func TestAuthorizationCodeFlowRejectsWrongVerifier(t *testing.T) {
+	provider, issuer, _ := newProvider(t, "secret")
+	issuer.SetUser(oidctest.User{Subject: "alice"})
+	ctx := context.Background()
+
+	_, challenge, err := oidc.GeneratePKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", nonce, challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _, err := issuer.Authorize(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 截获授权码的一方没有code_verifier，无法换取令牌
+	otherVerifier, _, err := oidc.GeneratePKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.Exchange(ctx, code, otherVerifier); err == nil {
+		t.Fatal("错误的code_verifier换取到了令牌")
+	}
+}
+
+func TestExchangeRejectsWrongClientSecret(t *testing.T) {
+	provider, issuer, _ := newProvider(t, "secret")
+	issuer.SetUser(oidctest.User{Subject: "alice"})
+	issuer.RegisterClient(clientID, "rotated-secret", redirectURL)
+	ctx := context.Background()
+
+	verifier, challenge, err := oidc.GeneratePKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", nonce, challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _, err := issuer.Authorize(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.Exchange(ctx, code, verifier); err == nil {
+		t.Fatal("错误的客户端密钥换取到了令牌")
+	}
+}
+
+func mustSign(t *testing.T, issuer *oidctest.Issuer, claims jwt.MapClaims) string {
+	t.Helper()
+	idToken, err := issuer.SignClaims(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return idToken
+}
+
+func mustParseQuery(t *testing.T, rawURL string) url.Values {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Query()
+}
@@ -0,0 +1,338 @@
+// Package oidctest 提供进程内的OpenID Connect身份提供方，用于在Go测试和本地开发中完成单点登录流程
+//
+// 身份提供方支持发现文档、JWKS、授权端点（自动同意，直接以当前用户签发授权码）和令牌端点（校验客户端和PKCE），
+// ID令牌使用RS256签名：
+//
+//	issuer := oidctest.New()
+//	defer issuer.Close()
+//	issuer.RegisterClient("vaulthub", "secret", "http://localhost:8080/sso/callback")
+//	issuer.SetUser(oidctest.User{Subject: "alice", Email: "alice@example.com", EmailVerified: true, Groups: []string{"admins"}})
+//	// 将 issuer.Issuer() 配置为 oidc.issuer，调用开始登录接口得到 authorization_url
+//	code, state, err := issuer.Authorize(authorizationURL)
+//	// 提交 code 和 state 到完成登录接口
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/cuihe500/vaulthub/pkg/oidc"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 授权码和ID令牌的有效期
+const (
+	codeTTL    = time.Minute
+	idTokenTTL = 5 * time.Minute
+	keyID      = "oidctest"
+)
+
+// User 授权时登录的用户，字段对应ID令牌中的标准声明
+type User struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+	Groups            []string               // 写入groups声明，为空时不写
+	Extra             map[string]interface{} // 其他自定义声明
+}
+
+// client 注册的客户端
+type client struct {
+	secret      string
+	redirectURL string
+}
+
+// authorization 已签发的授权码
+type authorization struct {
+	clientID      string
+	redirectURL   string
+	codeChallenge string
+	nonce         string
+	user          User
+	expiresAt     time.Time
+}
+
+// Issuer 进程内的身份提供方，可并发使用
+type Issuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu      sync.Mutex
+	clients map[string]client
+	user    *User
+	codes   map[string]*authorization
+}
+
+// New 启动身份提供方
+func New() *Issuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(fmt.Sprintf("oidctest: 生成签名密钥失败: %v", err))
+	}
+	i := &Issuer{
+		key:     key,
+		clients: make(map[string]client),
+		codes:   make(map[string]*authorization),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", i.handleDiscovery)
+	mux.HandleFunc("/jwks", i.handleJWKS)
+	mux.HandleFunc("/authorize", i.handleAuthorize)
+	mux.HandleFunc("/token", i.handleToken)
+	i.server = httptest.NewServer(mux)
+	return i
+}
+
+// Issuer 返回issuer（即服务地址），用于配置依赖方
+func (i *Issuer) Issuer() string {
+	return i.server.URL
+}
+
+// Close 关闭身份提供方
+func (i *Issuer) Close() {
+	i.server.Close()
+}
+
+// RegisterClient 注册客户端，clientSecret为空时作为公开客户端（只校验PKCE）
+func (i *Issuer) RegisterClient(clientID, clientSecret, redirectURL string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.clients[clientID] = client{secret: clientSecret, redirectURL: redirectURL}
+}
+
+// SetUser 设置之后授权时登录的用户
+func (i *Issuer) SetUser(user User) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.user = &user
+}
+
+// Authorize 以当前用户访问授权地址（代替浏览器），返回回调中的授权码和state
+func (i *Issuer) Authorize(authorizationURL string) (code, state string, err error) {
+	httpClient := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := httpClient.Get(authorizationURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusFound {
+		return "", "", fmt.Errorf("oidctest: 授权失败: %d", resp.StatusCode)
+	}
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", "", err
+	}
+	query := location.Query()
+	if query.Get("error") != "" {
+		return "", "", fmt.Errorf("oidctest: 授权失败: %s", query.Get("error"))
+	}
+	return query.Get("code"), query.Get("state"), nil
+}
+
+// handleDiscovery 发现文档
+func (i *Issuer) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                i.server.URL,
+		"authorization_endpoint":                i.server.URL + "/authorize",
+		"token_endpoint":                        i.server.URL + "/token",
+		"jwks_uri":                              i.server.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+// handleJWKS 签名公钥
+func (i *Issuer) handleJWKS(w http.ResponseWriter, _ *http.Request) {
+	jwk, err := oidc.NewJSONWebKey(keyID, &i.key.PublicKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"keys": []oidc.JSONWebKey{jwk}})
+}
+
+// handleAuthorize 授权端点：校验客户端和回调地址，以当前用户签发授权码并重定向回客户端
+func (i *Issuer) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	clientID := query.Get("client_id")
+	redirectURL := query.Get("redirect_uri")
+
+	i.mu.Lock()
+	registered, ok := i.clients[clientID]
+	user := i.user
+	i.mu.Unlock()
+	// 客户端或回调地址无效时不能重定向
+	if !ok || registered.redirectURL != redirectURL {
+		http.Error(w, "invalid client_id or redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	callback, err := url.Parse(redirectURL)
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	params := callback.Query()
+	params.Set("state", query.Get("state"))
+	switch {
+	case query.Get("response_type") != "code":
+		params.Set("error", "unsupported_response_type")
+	case query.Get("code_challenge") == "" || query.Get("code_challenge_method") != "S256":
+		params.Set("error", "invalid_request")
+	case user == nil:
+		params.Set("error", "access_denied")
+	default:
+		code, err := oidc.RandomString(24)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		i.mu.Lock()
+		i.codes[code] = &authorization{
+			clientID:      clientID,
+			redirectURL:   redirectURL,
+			codeChallenge: query.Get("code_challenge"),
+			nonce:         query.Get("nonce"),
+			user:          *user,
+			expiresAt:     time.Now().Add(codeTTL),
+		}
+		i.mu.Unlock()
+		params.Set("code", code)
+	}
+	callback.RawQuery = params.Encode()
+	http.Redirect(w, r, callback.String(), http.StatusFound)
+}
+
+// handleToken 令牌端点：校验授权码、客户端认证、回调地址和PKCE，签发ID令牌
+func (i *Issuer) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	clientID, clientSecret, basic := r.BasicAuth()
+	if basic {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+
+	// 授权码只能使用一次
+	code := r.PostForm.Get("code")
+	i.mu.Lock()
+	auth := i.codes[code]
+	delete(i.codes, code)
+	registered, ok := i.clients[clientID]
+	i.mu.Unlock()
+
+	if !ok || registered.secret != clientSecret {
+		tokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+	if auth == nil || time.Now().After(auth.expiresAt) || auth.clientID != clientID ||
+		auth.redirectURL != r.PostForm.Get("redirect_uri") ||
+		oidc.S256Challenge(r.PostForm.Get("code_verifier")) != auth.codeChallenge {
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	idToken, err := i.SignIDToken(clientID, auth.nonce, auth.user)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	accessToken, err := oidc.RandomString(24)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int64(idTokenTTL.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+// SignIDToken 为用户签发ID令牌，可用于构造授权流程以外的测试场景（如nonce不匹配）
+func (i *Issuer) SignIDToken(audience, nonce string, user User) (string, error) {
+	if user.Subject == "" {
+		return "", errors.New("oidctest: 用户的Subject不能为空")
+	}
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   i.server.URL,
+		"sub":   user.Subject,
+		"aud":   audience,
+		"iat":   now.Unix(),
+		"exp":   now.Add(idTokenTTL).Unix(),
+		"nonce": nonce,
+	}
+	if user.Email != "" {
+		claims["email"] = user.Email
+		claims["email_verified"] = user.EmailVerified
+	}
+	if user.Name != "" {
+		claims["name"] = user.Name
+	}
+	if user.PreferredUsername != "" {
+		claims["preferred_username"] = user.PreferredUsername
+	}
+	if len(user.Groups) > 0 {
+		claims["groups"] = user.Groups
+	}
+	for name, value := range user.Extra {
+		claims[name] = value
+	}
+
+	return i.SignClaims(claims)
+}
+
+// SignClaims 用身份提供方的签名密钥签发任意声明，用于构造缺少或篡改标准声明的ID令牌
+func (i *Issuer) SignClaims(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+	return token.SignedString(i.key)
+}
+
+// tokenError 令牌端点的错误响应（RFC 6749 5.2）
+func tokenError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code})
+}
+
+// writeJSON 输出JSON响应
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -146,6 +146,11 @@ func (c *Client) Get(ctx context.Context, key string) (string, error) {
 	return c.client.Get(ctx, key).Result()
 }
 
+// SetNX 键不存在时设置键值对，返回是否设置成功（用于分布式锁）
+func (c *Client) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, key, value, expiration).Result()
+}
+
 // Del 删除键
 func (c *Client) Del(ctx context.Context, keys ...string) error {
 	return c.client.Del(ctx, keys...).Err()
@@ -166,6 +171,26 @@ func (c *Client) TTL(ctx context.Context, key string) (time.Duration, error) {
 	return c.client.TTL(ctx, key).Result()
 }
 
+// HSetWithExpiration 写入哈希字段并设置过期时间（在同一事务中执行）
+func (c *Client) HSetWithExpiration(ctx context.Context, key string, values map[string]interface{}, expiration time.Duration) error {
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, values)
+		pipe.Expire(ctx, key, expiration)
+		return nil
+	})
+	return err
+}
+
+// HGetAll 获取哈希的全部字段，键不存在时返回空map
+func (c *Client) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return c.client.HGetAll(ctx, key).Result()
+}
+
+// Eval 执行Lua脚本，用于需要原子性的读-改-写操作
+func (c *Client) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return c.client.Eval(ctx, script, keys, args...).Result()
+}
+
 // GetUniversalClient 获取底层的UniversalClient（用于第三方库集成，如redis_rate）
 func (c *Client) GetUniversalClient() redis.UniversalClient {
 	return c.client
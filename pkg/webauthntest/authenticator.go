@@ -0,0 +1,356 @@
+// Package webauthntest 提供软件实现的WebAuthn认证器，用于在Go测试中完成通行密钥的注册、登录和二次验证
+//
+// 认证器直接处理服务端返回的 options（即 navigator.credentials.create()/get() 的参数），
+// 返回的JSON与浏览器 PublicKeyCredential 序列化结果一致，可以原样提交给完成注册和验证的接口：
+//
+//	auth := webauthntest.New("http://localhost:8080")
+//	credential, err := auth.Register(beginResp.Options)
+//	// 提交 {"credential": credential} 到 /api/v1/auth/webauthn/register/finish
+//
+// 认证器只生成ES256密钥，使用"none"证明格式，并且总是设置用户在场（UP）和用户验证（UV）标志
+package webauthntest
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// 认证器数据中的标志位
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttestedData = 0x40
+)
+
+// ErrNoCredential 认证器中没有可用于本次验证的凭证
+var ErrNoCredential = errors.New("webauthntest: 没有可用的凭证")
+
+// ErrCredentialExcluded 认证器中已有服务端排除的凭证（同一认证器重复注册）
+var ErrCredentialExcluded = errors.New("webauthntest: 凭证已注册")
+
+// credential 认证器中保存的凭证
+type credential struct {
+	id         []byte
+	rpID       string
+	userHandle []byte
+	key        *ecdsa.PrivateKey
+	signCount  uint32
+}
+
+// Authenticator 软件认证器，可并发使用
+type Authenticator struct {
+	origin string
+
+	mu          sync.Mutex
+	credentials []*credential
+}
+
+// New 创建软件认证器，origin为发起验证的页面来源，需要在服务端的 webauthn.rp_origins 中
+func New(origin string) *Authenticator {
+	return &Authenticator{origin: origin}
+}
+
+// Clone 复制认证器及其全部凭证（包括私钥和签名计数）
+// 用于模拟凭证私钥被复制的情况：两个副本交替使用时，服务端应检测到签名计数未递增
+func (a *Authenticator) Clone() *Authenticator {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	clone := &Authenticator{origin: a.origin}
+	for _, c := range a.credentials {
+		copied := *c
+		clone.credentials = append(clone.credentials, &copied)
+	}
+	return clone
+}
+
+// CredentialIDs 返回认证器中全部凭证的ID
+func (a *Authenticator) CredentialIDs() [][]byte {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ids := make([][]byte, len(a.credentials))
+	for i, c := range a.credentials {
+		ids[i] = c.id
+	}
+	return ids
+}
+
+// creationOptions navigator.credentials.create() 参数中认证器需要的字段
+type creationOptions struct {
+	PublicKey struct {
+		Challenge string `json:"challenge"`
+		RP        struct {
+			ID string `json:"id"`
+		} `json:"rp"`
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+		ExcludeCredentials []struct {
+			ID string `json:"id"`
+		} `json:"excludeCredentials"`
+	} `json:"publicKey"`
+}
+
+// Register 执行注册仪式，options为服务端返回的注册参数（可以是结构体或JSON）
+// 返回可直接提交给服务端的凭证JSON
+func (a *Authenticator) Register(options interface{}) (json.RawMessage, error) {
+	var opts creationOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return nil, err
+	}
+	rpID, err := a.rpID(opts.PublicKey.RP.ID)
+	if err != nil {
+		return nil, err
+	}
+	userHandle, err := decodeBase64URL(opts.PublicKey.User.ID)
+	if err != nil {
+		return nil, fmt.Errorf("webauthntest: 解析用户ID失败: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, excluded := range opts.PublicKey.ExcludeCredentials {
+		id, err := decodeBase64URL(excluded.ID)
+		if err != nil {
+			return nil, fmt.Errorf("webauthntest: 解析排除的凭证ID失败: %w", err)
+		}
+		if a.find(rpID, id) != nil {
+			return nil, ErrCredentialExcluded
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	cred := &credential{id: id, rpID: rpID, userHandle: userHandle, key: key}
+
+	publicKey, err := cbor.Marshal(coseKey{
+		Kty: 2,  // EC2
+		Alg: -7, // ES256
+		Crv: 1,  // P-256
+		X:   key.PublicKey.X.FillBytes(make([]byte, 32)),
+		Y:   key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 已证明的凭证数据：AAGUID(16) | 凭证ID长度(2) | 凭证ID | COSE公钥
+	authData := authenticatorData(rpID, flagUserPresent|flagUserVerified|flagAttestedData, cred.signCount)
+	authData = append(authData, make([]byte, 16)...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(id)))
+	authData = append(authData, id...)
+	authData = append(authData, publicKey...)
+
+	attestation, err := cbor.Marshal(attestationObject{
+		Fmt:      "none",
+		AttStmt:  map[string]interface{}{},
+		AuthData: authData,
+	})
+	if err != nil {
+		return nil, err
+	}
+	clientData, err := a.clientData("webauthn.create", opts.PublicKey.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	a.credentials = append(a.credentials, cred)
+	return json.Marshal(map[string]interface{}{
+		"id":                      encodeBase64URL(id),
+		"rawId":                   encodeBase64URL(id),
+		"type":                    "public-key",
+		"authenticatorAttachment": "platform",
+		"clientExtensionResults":  map[string]interface{}{},
+		"response": map[string]interface{}{
+			"clientDataJSON":    encodeBase64URL(clientData),
+			"attestationObject": encodeBase64URL(attestation),
+			"transports":        []string{"internal"},
+		},
+	})
+}
+
+// requestOptions navigator.credentials.get() 参数中认证器需要的字段
+type requestOptions struct {
+	PublicKey struct {
+		Challenge        string `json:"challenge"`
+		RPID             string `json:"rpId"`
+		AllowCredentials []struct {
+			ID string `json:"id"`
+		} `json:"allowCredentials"`
+	} `json:"publicKey"`
+}
+
+// Assert 执行验证仪式，options为服务端返回的验证参数（可以是结构体或JSON）
+// 参数中指定了允许的凭证时使用其中第一个已保存的凭证，否则（无密码登录）使用该依赖方的第一个凭证；
+// 每次验证签名计数加1。返回可直接提交给服务端的凭证JSON
+func (a *Authenticator) Assert(options interface{}) (json.RawMessage, error) {
+	var opts requestOptions
+	if err := decodeOptions(options, &opts); err != nil {
+		return nil, err
+	}
+	rpID, err := a.rpID(opts.PublicKey.RPID)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var cred *credential
+	if len(opts.PublicKey.AllowCredentials) == 0 {
+		cred = a.find(rpID, nil)
+	}
+	for _, allowed := range opts.PublicKey.AllowCredentials {
+		id, err := decodeBase64URL(allowed.ID)
+		if err != nil {
+			return nil, fmt.Errorf("webauthntest: 解析允许的凭证ID失败: %w", err)
+		}
+		if cred = a.find(rpID, id); cred != nil {
+			break
+		}
+	}
+	if cred == nil {
+		return nil, ErrNoCredential
+	}
+
+	clientData, err := a.clientData("webauthn.get", opts.PublicKey.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	cred.signCount++
+	authData := authenticatorData(rpID, flagUserPresent|flagUserVerified, cred.signCount)
+
+	// 签名内容：认证器数据 | SHA-256(clientDataJSON)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, cred.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"id":                      encodeBase64URL(cred.id),
+		"rawId":                   encodeBase64URL(cred.id),
+		"type":                    "public-key",
+		"authenticatorAttachment": "platform",
+		"clientExtensionResults":  map[string]interface{}{},
+		"response": map[string]interface{}{
+			"clientDataJSON":    encodeBase64URL(clientData),
+			"authenticatorData": encodeBase64URL(authData),
+			"signature":         encodeBase64URL(signature),
+			"userHandle":        encodeBase64URL(cred.userHandle),
+		},
+	})
+}
+
+// find 查找依赖方的凭证，id为空时返回该依赖方的第一个凭证
+func (a *Authenticator) find(rpID string, id []byte) *credential {
+	for _, c := range a.credentials {
+		if c.rpID == rpID && (id == nil || bytes.Equal(c.id, id)) {
+			return c
+		}
+	}
+	return nil
+}
+
+// rpID 返回依赖方ID，参数中未指定时使用来源的主机名
+func (a *Authenticator) rpID(rpID string) (string, error) {
+	if rpID != "" {
+		return rpID, nil
+	}
+	u, err := url.Parse(a.origin)
+	if err != nil {
+		return "", fmt.Errorf("webauthntest: 解析来源失败: %w", err)
+	}
+	return u.Hostname(), nil
+}
+
+// clientData 生成客户端数据（clientDataJSON），challenge保持服务端下发的编码
+func (a *Authenticator) clientData(ceremony, challenge string) ([]byte, error) {
+	if challenge == "" {
+		return nil, errors.New("webauthntest: 参数中缺少challenge")
+	}
+	return json.Marshal(map[string]interface{}{
+		"type":        ceremony,
+		"challenge":   strings.TrimRight(challenge, "="),
+		"origin":      a.origin,
+		"crossOrigin": false,
+	})
+}
+
+// authenticatorData 生成认证器数据：SHA-256(rpID)(32) | 标志(1) | 签名计数(4)
+func authenticatorData(rpID string, flags byte, signCount uint32) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	return binary.BigEndian.AppendUint32(data, signCount)
+}
+
+// coseKey COSE格式的EC2公钥
+type coseKey struct {
+	Kty int    `cbor:"1,keyasint"`
+	Alg int    `cbor:"3,keyasint"`
+	Crv int    `cbor:"-1,keyasint"`
+	X   []byte `cbor:"-2,keyasint"`
+	Y   []byte `cbor:"-3,keyasint"`
+}
+
+// attestationObject 注册响应中的证明对象
+type attestationObject struct {
+	Fmt      string                 `cbor:"fmt"`
+	AttStmt  map[string]interface{} `cbor:"attStmt"`
+	AuthData []byte                 `cbor:"authData"`
+}
+
+// decodeOptions 将服务端参数（结构体、json.RawMessage或[]byte）解析到out
+func decodeOptions(options interface{}, out interface{}) error {
+	var data []byte
+	switch v := options.(type) {
+	case json.RawMessage:
+		data = v
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return fmt.Errorf("webauthntest: 序列化参数失败: %w", err)
+		}
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("webauthntest: 解析参数失败: %w", err)
+	}
+	return nil
+}
+
+// encodeBase64URL 无填充的base64url编码
+func encodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeBase64URL 解码base64url，兼容带填充的格式
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
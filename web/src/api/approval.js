@@ -0,0 +1,78 @@
+import request from './request'
+
+/**
+ * 设置秘密审批策略（是否为高敏感秘密）
+ */
+export const setApprovalPolicy = (uuid, data) => {
+  return request.put(`/v1/secrets/${uuid}/approval-policy`, data)
+}
+
+/**
+ * 提交访问申请
+ */
+export const createAccessRequest = (uuid, data) => {
+  return request.post(`/v1/secrets/${uuid}/access-requests`, data)
+}
+
+/**
+ * 查询我的访问申请
+ */
+export const listMyAccessRequests = (params) => {
+  return request.get('/v1/access-requests', { params })
+}
+
+/**
+ * 查询待我审批的申请
+ */
+export const listPendingApprovals = (params) => {
+  return request.get('/v1/access-requests/pending', { params })
+}
+
+/**
+ * 批准访问申请
+ */
+export const approveAccessRequest = (uuid, data = {}) => {
+  return request.post(`/v1/access-requests/${uuid}/approve`, data)
+}
+
+/**
+ * 拒绝访问申请
+ */
+export const denyAccessRequest = (uuid, data = {}) => {
+  return request.post(`/v1/access-requests/${uuid}/deny`, data)
+}
+
+/**
+ * 撤回访问申请
+ */
+export const cancelAccessRequest = (uuid) => {
+  return request.post(`/v1/access-requests/${uuid}/cancel`)
+}
+
+/**
+ * 查询邮件审批链接对应的申请（无需登录，不消耗令牌）
+ */
+export const getEmailApproval = (token) => {
+  return request.get('/v1/access-requests/email-decision', { params: { token } })
+}
+
+/**
+ * 通过邮件链接审批（无需登录）
+ */
+export const decideByEmail = (data) => {
+  return request.post('/v1/access-requests/email-decision', data)
+}
+
+/**
+ * 查询保险库审批人
+ */
+export const getVaultApprovers = () => {
+  return request.get('/v1/vault/approvers')
+}
+
+/**
+ * 设置保险库审批人，传空列表时恢复使用默认审批角色
+ */
+export const setVaultApprovers = (usernames) => {
+  return request.put('/v1/vault/approvers', { usernames })
+}
@@ -0,0 +1,85 @@
+import request from './request'
+
+/**
+ * 查询紧急访问密钥对
+ */
+export const getEmergencyKeyPair = () => {
+  return request.get('/v1/emergency-access/keypair')
+}
+
+/**
+ * 生成紧急访问密钥对
+ */
+export const initEmergencyKeyPair = (data) => {
+  return request.post('/v1/emergency-access/keypair', data)
+}
+
+/**
+ * 查询我的可信联系人
+ */
+export const listTrustedContacts = () => {
+  return request.get('/v1/emergency-access/trusted')
+}
+
+/**
+ * 指定可信联系人
+ */
+export const designateTrustedContact = (data) => {
+  return request.post('/v1/emergency-access/trusted', data)
+}
+
+/**
+ * 撤销可信联系人
+ */
+export const revokeTrustedContact = (uuid) => {
+  return request.delete(`/v1/emergency-access/trusted/${uuid}`)
+}
+
+/**
+ * 同意紧急访问申请
+ */
+export const approveEmergencyAccess = (uuid) => {
+  return request.post(`/v1/emergency-access/trusted/${uuid}/approve`)
+}
+
+/**
+ * 拒绝紧急访问申请
+ */
+export const rejectEmergencyAccess = (uuid) => {
+  return request.post(`/v1/emergency-access/trusted/${uuid}/reject`)
+}
+
+/**
+ * 查询授予我的紧急访问
+ */
+export const listGrantedToMe = () => {
+  return request.get('/v1/emergency-access/granted')
+}
+
+/**
+ * 发起紧急访问申请
+ */
+export const requestEmergencyAccess = (uuid) => {
+  return request.post(`/v1/emergency-access/granted/${uuid}/request`)
+}
+
+/**
+ * 查询授权人的秘密列表
+ */
+export const listGrantorSecrets = (uuid, params) => {
+  return request.get(`/v1/emergency-access/granted/${uuid}/secrets`, { params })
+}
+
+/**
+ * 解密授权人的秘密
+ */
+export const decryptGrantorSecret = (uuid, secretUuid, data) => {
+  return request.post(`/v1/emergency-access/granted/${uuid}/secrets/${secretUuid}/decrypt`, data)
+}
+
+/**
+ * 接管授权人账户
+ */
+export const takeoverAccount = (uuid, data) => {
+  return request.post(`/v1/emergency-access/granted/${uuid}/takeover`, data)
+}
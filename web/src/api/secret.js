@@ -27,3 +27,11 @@ export const decryptSecret = (secretUuid, data) => {
 export const deleteSecret = (secretUuid) => {
   return request.delete(`/v1/secrets/${secretUuid}`)
 }
+
+/**
+ * 渲染引用秘密的配置模板
+ * @param {Object} data - { security_pin, template }
+ */
+export const renderTemplate = (data) => {
+  return request.post('/v1/secrets/render', data)
+}
@@ -0,0 +1,30 @@
+import request from './request'
+
+/**
+ * 创建一次性分享链接
+ * 分享已保存的秘密时传 secret_uuid + security_pin，分享临时文本时传 content
+ */
+export const createShare = (data) => {
+  return request.post('/v1/shares', data)
+}
+
+/**
+ * 获取分享信息（不消耗查看次数）
+ */
+export const getShareInfo = (uuid) => {
+  return request.get(`/v1/shares/${uuid}`)
+}
+
+/**
+ * 查看分享内容（消耗一次查看次数，返回密文）
+ */
+export const openShare = (uuid, data = {}) => {
+  return request.post(`/v1/shares/${uuid}/open`, data)
+}
+
+/**
+ * 撤销分享链接
+ */
+export const revokeShare = (uuid) => {
+  return request.delete(`/v1/shares/${uuid}`)
+}
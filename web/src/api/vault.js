@@ -27,3 +27,17 @@ export const deleteSecret = (uuid) => {
 export const decryptSecret = (uuid, data) => {
   return request.post(`/v1/secrets/${uuid}/decrypt`, data)
 }
+
+/**
+ * 获取保险库健康报告
+ */
+export const getVaultHealth = (params) => {
+  return request.get('/v1/vault/health', { params })
+}
+
+/**
+ * 重新扫描密码指纹和强度评分
+ */
+export const rescanVaultHealth = (data) => {
+  return request.post('/v1/vault/health/rescan', data)
+}
@@ -15,6 +15,20 @@ const routes = [
     component: () => import('@/views/login/Register.vue'),
     meta: { requiresAuth: false }
   },
+  {
+    // 一次性分享查看页，接收方无需登录，解密密钥在链接#之后
+    path: '/share/:id',
+    name: 'Share',
+    component: () => import('@/views/share/ShareView.vue'),
+    meta: { requiresAuth: false }
+  },
+  {
+    // 邮件审批页，审批人通过邮件中的一次性令牌审批，无需登录
+    path: '/access-approval',
+    name: 'AccessApproval',
+    component: () => import('@/views/approval/ApprovalView.vue'),
+    meta: { requiresAuth: false }
+  },
   {
     path: '/setup-security-pin',
     name: 'SetupSecurityPin',